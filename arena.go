@@ -0,0 +1,130 @@
+package pixel
+
+// arenaMinBlock is the number of vertices of the first block of an Arena.
+const arenaMinBlock = 4096
+
+// Arena allocates TrianglesData for geometry that lives for a single frame, such as the shapes of
+// an IMDraw, the particles of an Emitter or the transformed glyphs of a Text. Reset frees all the
+// allocated TrianglesData at once and the following allocations reuse the memory, so once the
+// Arena grows to the size of a frame, drawing doesn't allocate anymore.
+//
+//   arena := pixel.NewArena()
+//   imd := imdraw.New(nil)
+//   imd.SetArena(arena)
+//   for !win.Closed() {
+//       arena.Reset() // clears imd too
+//       ... draw the shapes of this frame onto imd
+//       imd.Draw(win)
+//       win.Update()
+//   }
+//
+// The TrianglesData allocated before a Reset must not be used after it. An Arena is not safe for
+// concurrent use.
+type Arena struct {
+	blocks []TrianglesData
+	used   int
+	total  int
+
+	// the headers of the allocated TrianglesData are reused too
+	headers []*TrianglesData
+	nheader int
+
+	// the functions registered by OnReset and the spare slice swapped with them by Reset
+	onReset, spare []func()
+}
+
+// NewArena creates a new empty Arena.
+func NewArena() *Arena {
+	return &Arena{}
+}
+
+// alloc returns n uninitialized vertices with the capacity of exactly n, so that appending to them
+// never overwrites the other allocations
+func (a *Arena) alloc(n int) TrianglesData {
+	if len(a.blocks) == 0 || a.used+n > len(a.blocks[len(a.blocks)-1]) {
+		size := arenaMinBlock
+		if len(a.blocks) > 0 {
+			size = 2 * len(a.blocks[len(a.blocks)-1])
+		}
+		if size < n {
+			size = n
+		}
+		a.blocks = append(a.blocks, make(TrianglesData, size))
+		a.used = 0
+	}
+	block := a.blocks[len(a.blocks)-1]
+	td := block[a.used : a.used+n : a.used+n]
+	a.used += n
+	a.total += n
+	return td
+}
+
+// Triangles allocates TrianglesData of the length, with the default values like
+// MakeTrianglesData.
+func (a *Arena) Triangles(length int) *TrianglesData {
+	if a.nheader == len(a.headers) {
+		a.headers = append(a.headers, new(TrianglesData))
+	}
+	td := a.headers[a.nheader]
+	a.nheader++
+
+	*td = a.alloc(length)
+	resetVertices(*td)
+	return td
+}
+
+// SetLen resizes the TrianglesData like its SetLen method, but allocates the vertices from the
+// Arena if its capacity isn't enough. The TrianglesData doesn't have to be allocated from the Arena,
+// but it becomes an Arena allocation if it grows.
+func (a *Arena) SetLen(td *TrianglesData, length int) {
+	if length <= cap(*td) {
+		td.SetLen(length)
+		return
+	}
+	// grow twice as much, so growing often takes amortized O(1) time
+	grown := a.alloc(2 * length)[:length]
+	n := copy(grown, *td)
+	resetVertices(grown[n:])
+	*td = grown
+}
+
+// Reset frees all TrianglesData allocated from the Arena. The memory is reused by the following
+// allocations.
+func (a *Arena) Reset() {
+	if len(a.blocks) > 1 {
+		// the next frame probably needs as much memory as this one, fit it into one block
+		a.blocks = append(a.blocks[:0], make(TrianglesData, a.total))
+	}
+	a.used = 0
+	a.total = 0
+	a.nheader = 0
+
+	onReset := a.onReset
+	a.onReset = a.spare[:0]
+	for i, f := range onReset {
+		onReset[i] = nil
+		f()
+	}
+	a.spare = onReset[:0]
+}
+
+// OnReset registers the function to be called by the next Reset, once. Users of the Arena keeping
+// its TrianglesData use it to drop them, e.g. IMDraw clears itself.
+func (a *Arena) OnReset(f func()) {
+	a.onReset = append(a.onReset, f)
+}
+
+// Len returns the number of vertices allocated since the last Reset.
+func (a *Arena) Len() int {
+	return a.total
+}
+
+// resetVertices sets the vertices to the default values of TrianglesData.SetLen
+func resetVertices(td TrianglesData) {
+	for i := range td {
+		td[i].Position = ZV
+		td[i].Color = RGBA{1, 1, 1, 1}
+		td[i].Picture = ZV
+		td[i].Intensity = 0
+	}
+}
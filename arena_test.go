@@ -0,0 +1,56 @@
+package pixel_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArena(t *testing.T) {
+	a := pixel.NewArena()
+
+	td := a.Triangles(3)
+	assert.Equal(t, pixel.MakeTrianglesData(3), td)
+	(*td)[0].Position = pixel.V(1, 2)
+
+	// appending to the vertices never overwrites the following allocations
+	next := a.Triangles(2)
+	(*next)[0].Position = pixel.V(3, 4)
+	td.SetLen(4)
+	assert.Equal(t, pixel.V(3, 4), next.Position(0))
+	assert.Equal(t, pixel.RGBA{R: 1, G: 1, B: 1, A: 1}, td.Color(3))
+
+	// SetLen grows in the Arena and keeps the vertices
+	a.SetLen(next, 100)
+	assert.Equal(t, 100, next.Len())
+	assert.Equal(t, pixel.V(3, 4), next.Position(0))
+	assert.Equal(t, pixel.RGBA{R: 1, G: 1, B: 1, A: 1}, next.Color(99))
+	assert.Equal(t, 3+2+200, a.Len())
+
+	var resets int
+	a.OnReset(func() { resets++ })
+	a.Reset()
+	a.Reset()
+	assert.Equal(t, 1, resets)
+	assert.Equal(t, 0, a.Len())
+
+	// the memory is reused and reset to the default values
+	td = a.Triangles(3)
+	assert.Equal(t, pixel.MakeTrianglesData(3), td)
+}
+
+func TestArena_allocs(t *testing.T) {
+	a := pixel.NewArena()
+	frame := func() {
+		for i := 0; i < 100; i++ {
+			td := a.Triangles(6)
+			a.SetLen(td, 60)
+		}
+		a.Reset()
+	}
+	// the first frames grow the Arena into a single block
+	frame()
+	frame()
+	assert.Equal(t, 0.0, testing.AllocsPerRun(10, frame))
+}
@@ -0,0 +1,276 @@
+// Package assets loads the assets of a game, such as the pictures, the fonts and the maps, and
+// reloads them when their files change, so the edits of the artists show up in the running game
+// without restarting it.
+//
+// A Manager loads each file once, by the Loader registered for its extension. With Watch enabled,
+// Update checks the files every Interval and reloads the changed ones:
+//
+//   assets := assets.NewManager()
+//   assets.Watch = true
+//
+//   hero, err := assets.Picture("images/hero.png")
+//   if err != nil {
+//       panic(err)
+//   }
+//   sprite := pixel.NewSprite(hero, hero.Bounds())
+//
+//   for !win.Closed() {
+//       assets.Update(dt)
+//       ...
+//       sprite.Draw(win, pixel.IM)
+//   }
+//
+// A Picture reloads its texture transparently and a Shader recompiles itself, showing the compile
+// errors on the screen. The other assets notify the objects using them by the functions passed to
+// Asset.OnChange.
+package assets
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Loader loads an asset from the file. The open function opens the file and the other files the
+// asset is made of, such as the images of a font or the tilesets of a map, so that the Manager
+// reloads the asset when any of them changes.
+type Loader func(file string, open func(file string) (io.ReadCloser, error)) (interface{}, error)
+
+// Manager loads the assets and reloads them when their files change.
+//
+// A Manager isn't safe for concurrent use. The assets are reloaded by Update on the goroutine
+// calling it, usually the game loop, so the objects using them are notified between the frames.
+type Manager struct {
+	// Watch enables the reloading of the changed files by Update.
+	Watch bool
+
+	// Interval is the number of seconds between the checks of the files, 0.5 by default.
+	Interval float64
+
+	// Atlas enables packing the small pictures returned by Picture into shared textures, the
+	// atlases, so that the sprites of different pictures are drawn without switching the
+	// textures, often by a single draw. See Picture.Frame.
+	Atlas bool
+
+	// AtlasSize is the width and the height of the atlases in pixels, 2048 by default. The
+	// pictures larger than a quarter of it keep their own textures.
+	AtlasSize int
+
+	loaders map[string]Loader
+	assets  map[string]*Asset
+	timer   float64
+	atlases []*atlas
+}
+
+// NewManager creates a new Manager with the Loaders of the PNG, JPEG and GIF pictures (".png",
+// ".jpg", ".jpeg" and ".gif"), the TMX maps (".tmx"), the BMFont atlases (".fnt") and the fragment
+// shaders (".frag", ".fs" and ".glsl").
+func NewManager() *Manager {
+	m := &Manager{
+		Interval: 0.5,
+		loaders:  make(map[string]Loader),
+		assets:   make(map[string]*Asset),
+	}
+	for _, ext := range []string{".png", ".jpg", ".jpeg", ".gif"} {
+		m.Register(ext, LoadPicture)
+	}
+	m.Register(".tmx", LoadMap)
+	m.Register(".fnt", LoadBMFont)
+	for _, ext := range []string{".frag", ".fs", ".glsl"} {
+		m.Register(ext, LoadShader)
+	}
+	return m
+}
+
+// Register sets the Loader of the files with the extension, including the dot, e.g. ".ttf". The
+// extensions are case insensitive. A nil Loader removes the Loader of the extension.
+func (m *Manager) Register(ext string, loader Loader) {
+	ext = strings.ToLower(ext)
+	if loader == nil {
+		delete(m.loaders, ext)
+		return
+	}
+	m.loaders[ext] = loader
+}
+
+// Load returns the Asset of the file, loading it by the Loader of its extension if it isn't loaded
+// yet.
+func (m *Manager) Load(file string) (*Asset, error) {
+	file = filepath.Clean(file)
+	if a := m.assets[file]; a != nil {
+		return a, nil
+	}
+	loader := m.loaders[strings.ToLower(filepath.Ext(file))]
+	if loader == nil {
+		return nil, fmt.Errorf("assets: no loader of %s", file)
+	}
+	a := &Asset{file: file, loader: loader}
+	if err := a.load(); err != nil {
+		return nil, err
+	}
+	m.assets[file] = a
+	return a, nil
+}
+
+// Unload removes the Asset of the file from the Manager, which stops reloading it. The next Load
+// of the file loads it again.
+func (m *Manager) Unload(file string) {
+	delete(m.assets, filepath.Clean(file))
+}
+
+// Files returns the sorted files of the loaded assets.
+func (m *Manager) Files() []string {
+	files := make([]string, 0, len(m.assets))
+	for file := range m.assets {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Update advances the time by dt and, if Watch is enabled and the Interval has passed, reloads the
+// changed assets like Check.
+func (m *Manager) Update(dt float64) []*Asset {
+	if !m.Watch {
+		return nil
+	}
+	m.timer += dt
+	if m.timer < m.Interval {
+		return nil
+	}
+	m.timer = 0
+	return m.Check()
+}
+
+// Check reloads the assets whose files changed since they were loaded, and returns them sorted by
+// their files. If an asset fails to reload, it keeps its previous value and its Err returns the
+// error.
+func (m *Manager) Check() []*Asset {
+	var changed []*Asset
+	for _, file := range m.Files() {
+		a := m.assets[file]
+		if !a.changed() {
+			continue
+		}
+		if err := a.load(); err == nil {
+			a.notify()
+		}
+		changed = append(changed, a)
+	}
+	return changed
+}
+
+// Asset is an asset loaded by a Manager.
+type Asset struct {
+	file      string
+	loader    Loader
+	value     interface{}
+	version   int
+	err       error
+	stamps    map[string]stamp
+	listeners []func(a *Asset)
+	picture   *Picture
+}
+
+// stamp identifies the content of a file, changed by writing it
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// File returns the file of the Asset.
+func (a *Asset) File() string {
+	return a.file
+}
+
+// Value returns the value loaded by the Loader, e.g. a *pixel.PictureData. It's replaced by a new
+// value when the Asset is reloaded.
+func (a *Asset) Value() interface{} {
+	return a.value
+}
+
+// Version returns the number of the times the Asset was reloaded.
+func (a *Asset) Version() int {
+	return a.version
+}
+
+// Err returns the error of the last reload of the Asset, or nil if it succeeded.
+func (a *Asset) Err() error {
+	return a.err
+}
+
+// Files returns the sorted files the Asset is made of, which the Manager watches.
+func (a *Asset) Files() []string {
+	files := make([]string, 0, len(a.stamps))
+	for file := range a.stamps {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// OnChange adds the function called after each reload of the Asset, with the new value already
+// loaded.
+func (a *Asset) OnChange(fn func(a *Asset)) {
+	a.listeners = append(a.listeners, fn)
+}
+
+// load loads the Asset by its Loader, recording the stamps of the opened files
+func (a *Asset) load() error {
+	stamps := make(map[string]stamp)
+	open := func(file string) (io.ReadCloser, error) {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := f.Stat(); err == nil {
+			stamps[filepath.Clean(file)] = stamp{info.ModTime(), info.Size()}
+		}
+		return f, nil
+	}
+
+	value, err := a.loader(a.file, open)
+	if err != nil {
+		err = fmt.Errorf("assets: failed to load %s: %v", a.file, err)
+		if a.stamps == nil {
+			return err
+		}
+		// watch the files read by the failed load too, e.g. an image added to a font
+		for file, s := range stamps {
+			a.stamps[file] = s
+		}
+		a.err = err
+		return err
+	}
+
+	if a.stamps != nil {
+		a.version++
+	}
+	a.value, a.err, a.stamps = value, nil, stamps
+	return nil
+}
+
+// changed reports whether any of the files of the Asset changed. The missing files don't count,
+// because editors often replace them while saving.
+func (a *Asset) changed() bool {
+	for file, s := range a.stamps {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(s.modTime) || info.Size() != s.size {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Asset) notify() {
+	for _, fn := range a.listeners {
+		fn(a)
+	}
+}
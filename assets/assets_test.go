@@ -0,0 +1,360 @@
+package assets_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/assets"
+	"github.com/faiface/pixel/raster"
+	"github.com/faiface/pixel/text"
+	"github.com/faiface/pixel/tmx"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// writeFile writes the file with the modification time of the step seconds after the start, so
+// the changes are detected regardless of the resolution of the file system's timestamps
+func writeFile(t *testing.T, file string, data []byte, step int) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	mod := time.Unix(1600000000+int64(step), 0)
+	if err := os.Chtimes(file, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writePNG(t *testing.T, file string, w, h int, c color.Color, step int) {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	mod := time.Unix(1600000000+int64(step), 0)
+	if err := os.Chtimes(file, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestManager_Picture(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "hero.png")
+	writePNG(t, file, 4, 2, color.RGBA{R: 255, A: 255}, 0)
+
+	m := assets.NewManager()
+	m.Watch = true
+	pic, err := m.Picture(file)
+	assert.NoError(t, err)
+	assert.Equal(t, pixel.R(0, 0, 4, 2), pic.Bounds())
+	assert.Equal(t, pixel.RGB(1, 0, 0), pic.Color(pixel.V(1, 1)))
+
+	same, err := m.Picture(file)
+	assert.NoError(t, err)
+	assert.True(t, pic == same)
+
+	var notified []int
+	pic.Asset().OnChange(func(a *assets.Asset) {
+		notified = append(notified, a.Version())
+	})
+
+	assert.Empty(t, m.Update(1), "nothing changed")
+	writePNG(t, file, 8, 8, color.RGBA{B: 255, A: 255}, 1)
+	assert.Empty(t, m.Update(0.2), "before the interval")
+	changed := m.Update(0.3)
+	if assert.Len(t, changed, 1) {
+		assert.True(t, changed[0] == pic.Asset())
+	}
+	assert.Equal(t, []int{1}, notified)
+	assert.Equal(t, pixel.R(0, 0, 8, 8), pic.Bounds())
+	assert.Equal(t, pixel.RGB(0, 0, 1), pic.Color(pixel.V(1, 1)))
+
+	// a broken save keeps the previous picture, until it's fixed
+	writeFile(t, file, []byte("not a picture"), 2)
+	changed = m.Check()
+	if assert.Len(t, changed, 1) {
+		assert.Error(t, changed[0].Err())
+	}
+	assert.Equal(t, []int{1}, notified)
+	assert.Equal(t, pixel.RGB(0, 0, 1), pic.Color(pixel.V(1, 1)))
+	assert.Empty(t, m.Check(), "the broken file isn't reloaded again")
+
+	writePNG(t, file, 8, 8, color.RGBA{G: 255, A: 255}, 3)
+	assert.Len(t, m.Check(), 1)
+	assert.NoError(t, pic.Asset().Err())
+	assert.Equal(t, []int{1, 2}, notified)
+	assert.Equal(t, pixel.RGB(0, 1, 0), pic.Color(pixel.V(1, 1)))
+
+	// a missing file doesn't count as a change
+	assert.NoError(t, os.Remove(file))
+	assert.Empty(t, m.Check())
+
+	m.Watch = false
+	writePNG(t, file, 8, 8, color.RGBA{R: 255, A: 255}, 4)
+	assert.Empty(t, m.Update(1), "not watching")
+}
+
+func TestManager_Atlas(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	red, green, big := filepath.Join(dir, "red.png"), filepath.Join(dir, "green.png"), filepath.Join(dir, "big.png")
+	writePNG(t, red, 4, 2, color.RGBA{R: 255, A: 255}, 0)
+	writePNG(t, green, 3, 3, color.RGBA{G: 255, A: 255}, 0)
+	writePNG(t, big, 20, 4, color.RGBA{B: 255, A: 255}, 0)
+
+	m := assets.NewManager()
+	m.Atlas = true
+	m.AtlasSize = 64
+	r, err := m.Picture(red)
+	assert.NoError(t, err)
+	g, err := m.Picture(green)
+	assert.NoError(t, err)
+	b, err := m.Picture(big)
+	assert.NoError(t, err)
+
+	// the small pictures share an atlas, the big one keeps its own bounds
+	atlas := pixel.R(0, 0, 64, 64)
+	assert.Equal(t, atlas, r.Bounds())
+	assert.Equal(t, atlas, g.Bounds())
+	assert.Equal(t, pixel.R(0, 0, 20, 4), b.Bounds())
+	assert.Equal(t, b.Bounds(), b.Region())
+	assert.Equal(t, pixel.R(0, 0, 3, 3), g.PictureData().Bounds())
+	assert.Equal(t, 4.0, r.Region().W())
+	assert.Equal(t, 2.0, r.Region().H())
+	assert.Equal(t, pixel.Rect{}, r.Region().Intersect(g.Region()))
+
+	// the sprites drawn through the converted frames show the original content
+	c := raster.NewCanvas(pixel.R(0, 0, 8, 4))
+	pixel.NewSprite(r, r.Frame(pixel.R(0, 0, 2, 2))).Draw(c, pixel.IM.Moved(pixel.V(1, 1)))
+	pixel.NewSprite(g, g.Region()).Draw(c, pixel.IM.Moved(pixel.V(5.5, 1.5)))
+	assert.Equal(t, pixel.RGB(1, 0, 0), c.Color(pixel.V(1, 1)))
+	assert.Equal(t, pixel.RGB(0, 1, 0), c.Color(pixel.V(5, 1)))
+	assert.Equal(t, pixel.Alpha(0), c.Color(pixel.V(3, 1)))
+
+	// a reload of the same size keeps the region, a larger one moves it
+	region := g.Region()
+	writePNG(t, green, 3, 3, color.RGBA{R: 255, G: 255, A: 255}, 1)
+	assert.Len(t, m.Check(), 1)
+	assert.Equal(t, region, g.Region())
+	assert.Equal(t, pixel.RGB(1, 1, 0), g.Color(region.Center()))
+
+	writePNG(t, green, 5, 5, color.RGBA{G: 255, A: 255}, 2)
+	assert.Len(t, m.Check(), 1)
+	assert.Equal(t, 5.0, g.Region().W())
+	assert.Equal(t, pixel.Rect{}, g.Region().Intersect(r.Region()))
+	assert.Equal(t, pixel.RGB(0, 1, 0), g.Color(g.Region().Center()))
+
+	// a picture grown too large leaves the atlas
+	writePNG(t, green, 32, 4, color.RGBA{G: 255, A: 255}, 3)
+	assert.Len(t, m.Check(), 1)
+	assert.Equal(t, pixel.R(0, 0, 32, 4), g.Bounds())
+	assert.Equal(t, g.Bounds(), g.Frame(g.Bounds()))
+}
+
+func TestManager_Load(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	m := assets.NewManager()
+	_, err := m.Load(filepath.Join(dir, "level.txt"))
+	assert.Error(t, err, "no loader")
+	_, err = m.Load(filepath.Join(dir, "missing.png"))
+	assert.Error(t, err)
+	assert.Empty(t, m.Files())
+
+	file := filepath.Join(dir, "level.txt")
+	writeFile(t, file, []byte("one"), 0)
+	var loads int
+	m.Register(".TXT", func(file string, open func(file string) (io.ReadCloser, error)) (interface{}, error) {
+		loads++
+		f, err := open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		data, err := ioutil.ReadAll(f)
+		return string(data), err
+	})
+	a, err := m.Load(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "one", a.Value())
+	assert.Equal(t, 0, a.Version())
+	assert.Equal(t, []string{file}, a.Files())
+
+	b, err := m.Load(filepath.Join(dir, ".", "level.txt"))
+	assert.NoError(t, err)
+	assert.True(t, a == b)
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, []string{file}, m.Files())
+
+	_, err = m.Picture(file)
+	assert.Error(t, err, "not a picture")
+
+	writeFile(t, file, []byte("two"), 1)
+	assert.Len(t, m.Check(), 1)
+	assert.Equal(t, "two", a.Value())
+	assert.Equal(t, 1, a.Version())
+
+	m.Unload(file)
+	assert.Empty(t, m.Files())
+	writeFile(t, file, []byte("three"), 2)
+	assert.Empty(t, m.Check(), "unloaded")
+
+	m.Register(".txt", nil)
+	_, err = m.Load(file)
+	assert.Error(t, err, "no loader")
+}
+
+const testMap = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="2" tileheight="2">
+ <tileset firstgid="1" source="tilesets/test.tsx"/>
+ <layer id="1" name="ground" width="1" height="1">
+  <data encoding="csv">1</data>
+ </layer>
+</map>
+`
+
+const testTileset = `<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="test" tilewidth="2" tileheight="2" tilecount="1" columns="1">
+ <image source="../images/tiles.png" width="2" height="2"/>
+</tileset>
+`
+
+func TestLoadMap(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "maps", "level.tmx")
+	writeFile(t, file, []byte(testMap), 0)
+	writeFile(t, filepath.Join(dir, "maps", "tilesets", "test.tsx"), []byte(testTileset), 0)
+	if err := os.MkdirAll(filepath.Join(dir, "maps", "images"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	tiles := filepath.Join(dir, "maps", "images", "tiles.png")
+	writePNG(t, tiles, 2, 2, color.RGBA{R: 255, A: 255}, 0)
+
+	m := assets.NewManager()
+	a, err := m.Load(file)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, a.Files(), 3)
+	tileset := a.Value().(*tmx.Map).Tilesets[0]
+	assert.Equal(t, pixel.RGB(1, 0, 0), tileset.Picture.(pixel.PictureColor).Color(pixel.V(1, 1)))
+
+	// changing the image of the tileset reloads the map
+	writePNG(t, tiles, 2, 2, color.RGBA{G: 255, A: 255}, 1)
+	assert.Len(t, m.Check(), 1)
+	tileset = a.Value().(*tmx.Map).Tilesets[0]
+	assert.Equal(t, pixel.RGB(0, 1, 0), tileset.Picture.(pixel.PictureColor).Color(pixel.V(1, 1)))
+}
+
+func TestFontLoader(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "regular.ttf")
+	writeFile(t, file, goregular.TTF, 0)
+
+	m := assets.NewManager()
+	m.Register(".ttf", assets.FontLoader(16))
+	a, err := m.Load(file)
+	if !assert.NoError(t, err) {
+		return
+	}
+	atlas := a.Value().(*text.Atlas)
+	assert.True(t, atlas.Contains('A'))
+	assert.False(t, atlas.Contains('é'))
+
+	writeFile(t, file, []byte("not a font"), 1)
+	assert.Len(t, m.Check(), 1)
+	assert.Error(t, a.Err())
+	assert.True(t, atlas == a.Value())
+}
+
+// shaderTarget compiles the shaders without "error" in them
+type shaderTarget struct {
+	src string
+}
+
+func (st *shaderTarget) TrySetFragmentShader(src string) error {
+	if strings.Contains(src, "error") {
+		return fmt.Errorf("0:1(1): error: syntax error\n0:2(1): error: another one")
+	}
+	st.src = src
+	return nil
+}
+
+func TestManager_Shader(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "water.frag")
+	writeFile(t, file, []byte("error"), 0)
+
+	m := assets.NewManager()
+	var a, b shaderTarget
+	_, err := m.Shader(file, &a, &b)
+	assert.Error(t, err, "doesn't compile")
+
+	writeFile(t, file, []byte("void main() {}"), 1)
+	m.Unload(file)
+	s, err := m.Shader(file, &a, &b)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "void main() {}", a.src)
+	assert.Equal(t, "void main() {}", b.src)
+
+	canvas := raster.NewCanvas(pixel.R(0, 0, 200, 100))
+	s.DrawOverlay(canvas, canvas.Bounds())
+	assert.Equal(t, pixel.Alpha(0), canvas.Color(pixel.V(100, 95)), "no errors")
+
+	writeFile(t, file, []byte("void main() { error }"), 2)
+	assert.Len(t, m.Check(), 1)
+	if assert.Error(t, s.Err()) {
+		assert.Contains(t, s.Err().Error(), "water.frag")
+		assert.Contains(t, s.Err().Error(), "syntax error")
+	}
+	assert.Equal(t, "void main() {}", a.src, "the last working shader")
+
+	s.DrawOverlay(canvas, canvas.Bounds())
+	assert.NotEqual(t, pixel.Alpha(0), canvas.Color(pixel.V(199, 95)), "the overlay")
+	assert.Equal(t, pixel.Alpha(0), canvas.Color(pixel.V(199, 5)), "only at the top")
+
+	writeFile(t, file, []byte("void main() { gl_FragColor = vec4(1); }"), 3)
+	assert.Len(t, m.Check(), 1)
+	assert.NoError(t, s.Err())
+	assert.Equal(t, "void main() { gl_FragColor = vec4(1); }", a.src)
+	assert.Equal(t, "void main() { gl_FragColor = vec4(1); }", b.src)
+
+	_, err = m.Picture(file)
+	assert.Error(t, err, "not a picture")
+}
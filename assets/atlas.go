@@ -0,0 +1,123 @@
+package assets
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// atlasPadding is the number of pixels around a packed picture, filled with its edge pixels, so
+// that the smooth sampling of its edges doesn't blend in the neighbouring pictures.
+const atlasPadding = 1
+
+// atlas is a shared texture the small pictures are packed into, on shelves: the rows of pictures
+// filled from the left, each as high as its highest picture.
+type atlas struct {
+	pic   *pixel.PictureData
+	gl    pixelgl.GLPicture
+	dirty bool
+
+	x, y, shelf int
+}
+
+func newAtlas(size int) *atlas {
+	return &atlas{pic: pixel.MakePictureData(pixel.R(0, 0, float64(size), float64(size)))}
+}
+
+// insert finds a place for a picture of the size, and returns the position of its bottom-left
+// pixel, or false if the atlas is full
+func (a *atlas) insert(w, h int) (x, y int, ok bool) {
+	size := a.pic.Stride
+	w, h = w+2*atlasPadding, h+2*atlasPadding
+	if a.x+w > size {
+		// start a new shelf
+		a.x, a.y, a.shelf = 0, a.y+a.shelf, 0
+	}
+	if a.x+w > size || a.y+h > size {
+		return 0, 0, false
+	}
+	x, y = a.x+atlasPadding, a.y+atlasPadding
+	a.x += w
+	if h > a.shelf {
+		a.shelf = h
+	}
+	return x, y, true
+}
+
+// copy copies the picture into the atlas with its bottom-left pixel at the position, and extrudes
+// its edges into the padding
+func (a *atlas) copy(pd *pixel.PictureData, x, y int) {
+	_, _, w, h := intRect(pd.Bounds())
+	src := pd.Rect.Min
+	for row := -atlasPadding; row < h+atlasPadding; row++ {
+		sy := clamp(row, 0, h-1)
+		dst := a.pic.Index(pixel.V(float64(x), float64(y+row)))
+		from := pd.Index(src.Add(pixel.V(0, float64(sy))))
+		copy(a.pic.Pix[dst:dst+w], pd.Pix[from:from+w])
+		for p := 1; p <= atlasPadding; p++ {
+			a.pic.Pix[dst-p] = pd.Pix[from]
+			a.pic.Pix[dst+w-1+p] = pd.Pix[from+w-1]
+		}
+	}
+	a.dirty = true
+}
+
+// texture returns the texture of the atlas, uploading it if any picture was copied since
+func (a *atlas) texture() pixelgl.Texture {
+	if a.gl == nil || a.dirty {
+		a.gl = pixelgl.NewGLPicture(a.pic)
+		a.dirty = false
+	}
+	return a.gl.Texture()
+}
+
+// pack packs the picture into one of the atlases of the Manager, adding a new one if they're all
+// full, and returns the atlas and the picture's region in it
+func (m *Manager) pack(pd *pixel.PictureData) (*atlas, pixel.Rect) {
+	_, _, w, h := intRect(pd.Bounds())
+	for _, a := range m.atlases {
+		if x, y, ok := a.insert(w, h); ok {
+			a.copy(pd, x, y)
+			return a, pixel.R(float64(x), float64(y), float64(x+w), float64(y+h))
+		}
+	}
+	a := newAtlas(m.atlasSize())
+	m.atlases = append(m.atlases, a)
+	x, y, _ := a.insert(w, h)
+	a.copy(pd, x, y)
+	return a, pixel.R(float64(x), float64(y), float64(x+w), float64(y+h))
+}
+
+// atlasSize returns the AtlasSize or its default
+func (m *Manager) atlasSize() int {
+	if m.AtlasSize <= 0 {
+		return 2048
+	}
+	return m.AtlasSize
+}
+
+// packable returns whether the picture is small enough to be packed into an atlas, at most a
+// quarter of its size in both directions
+func (m *Manager) packable(pd *pixel.PictureData) bool {
+	_, _, w, h := intRect(pd.Bounds())
+	limit := m.atlasSize() / 4
+	return w > 0 && h > 0 && w <= limit && h <= limit
+}
+
+// intRect returns the integer position and size of the pixels of the rectangle
+func intRect(r pixel.Rect) (x, y, w, h int) {
+	x0, y0 := int(math.Floor(r.Min.X)), int(math.Floor(r.Min.Y))
+	x1, y1 := int(math.Ceil(r.Max.X)), int(math.Ceil(r.Max.Y))
+	return x0, y0, x1 - x0, y1 - y0
+}
+
+func clamp(x, low, high int) int {
+	if x < low {
+		return low
+	}
+	if x > high {
+		return high
+	}
+	return x
+}
@@ -0,0 +1,238 @@
+package assets
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // the GIF pictures
+	_ "image/jpeg" // the JPEG pictures
+	_ "image/png"  // the PNG pictures
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"github.com/faiface/pixel/tmx"
+	"github.com/golang/freetype/truetype"
+)
+
+// LoadPicture is the Loader of the pictures decoded by image.Decode, the value is a
+// *pixel.PictureData. The PNG, JPEG and GIF formats are registered by the package.
+func LoadPicture(file string, open func(file string) (io.ReadCloser, error)) (interface{}, error) {
+	f, err := open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return pixel.PictureDataFromImage(img), nil
+}
+
+// LoadMap is the Loader of the TMX maps, the value is a *tmx.Map. The tilesets and their images
+// are watched along with the map.
+func LoadMap(file string, open func(file string) (io.ReadCloser, error)) (interface{}, error) {
+	f, err := open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return tmx.Load(f, relative(file, open))
+}
+
+// LoadBMFont is the Loader of the BMFonts, the value is a *text.Atlas of all the runes of the
+// font. The page images are watched along with the font descriptor.
+func LoadBMFont(file string, open func(file string) (io.ReadCloser, error)) (interface{}, error) {
+	f, err := open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	openRel := relative(file, open)
+	face, err := text.LoadBMFont(f, func(page string) (image.Image, error) {
+		pf, err := openRel(page)
+		if err != nil {
+			return nil, err
+		}
+		defer pf.Close()
+		img, _, err := image.Decode(pf)
+		return img, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return text.NewAtlas(face, face.Runes()), nil
+}
+
+// FontLoader returns the Loader of the TrueType fonts, the value is a *text.Atlas of the font of
+// the size with the runes, or text.ASCII if there are none. Register it for the extension of the
+// fonts:
+//
+//   assets.Register(".ttf", assets.FontLoader(16, text.ASCII, text.RangeTable(unicode.Latin)))
+func FontLoader(size float64, runeSets ...[]rune) Loader {
+	return func(file string, open func(file string) (io.ReadCloser, error)) (interface{}, error) {
+		f, err := open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		data, err := ioutil.ReadAll(f)
+		if err != nil {
+			return nil, err
+		}
+		ttf, err := truetype.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(runeSets) == 0 {
+			runeSets = [][]rune{text.ASCII}
+		}
+		face := truetype.NewFace(ttf, &truetype.Options{Size: size})
+		return text.NewAtlas(face, runeSets...), nil
+	}
+}
+
+// relative returns the open function of the slash-separated paths relative to the file
+func relative(file string, open func(file string) (io.ReadCloser, error)) func(name string) (io.ReadCloser, error) {
+	dir := filepath.Dir(file)
+	return func(name string) (io.ReadCloser, error) {
+		return open(filepath.Join(dir, filepath.FromSlash(name)))
+	}
+}
+
+// Picture is a picture Asset, which draws its current content after a reload without creating the
+// sprites again. It's a pixelgl.GLPicture, so the Canvases and Windows draw the texture of the
+// last reload. The other Targets, which copy the Pictures drawn onto them, and the Sprites, whose
+// frames don't follow a change of the size, should be updated by Asset.OnChange.
+//
+// With Manager.Atlas enabled, a small Picture is packed into an atlas shared with other Pictures.
+// Its Bounds are then the bounds of the atlas, and the frames of its Sprites must be converted
+// into them by Frame:
+//
+//   hero, err := assets.Picture("images/hero.png")
+//   ...
+//   sprite := pixel.NewSprite(hero, hero.Frame(hero.Region()))
+//   walk := pixel.NewSprite(hero, hero.Frame(pixel.R(16, 0, 32, 16)))
+//
+// Frame and Region work the same way with a Picture that isn't packed, so code using them doesn't
+// depend on the Atlas option.
+type Picture struct {
+	asset   *Asset
+	gl      pixelgl.GLPicture
+	version int
+
+	// the atlas the Picture is packed into, its region in it and the version packed
+	manager      *Manager
+	atlas        *atlas
+	region       pixel.Rect
+	atlasVersion int
+}
+
+var _ pixelgl.GLPicture = (*Picture)(nil)
+
+// Picture returns the Picture of the picture file, loading it if it isn't loaded yet. The Loader of
+// the file must load a *pixel.PictureData. All the calls with the file return the same Picture.
+func (m *Manager) Picture(file string) (*Picture, error) {
+	a, err := m.Load(file)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := a.Value().(*pixel.PictureData); !ok {
+		return nil, fmt.Errorf("assets: %s is not a picture", a.File())
+	}
+	if a.picture == nil {
+		a.picture = &Picture{asset: a}
+		if pd := a.picture.PictureData(); m.Atlas && m.packable(pd) {
+			a.picture.atlas, a.picture.region = m.pack(pd)
+			a.picture.atlasVersion = a.Version()
+			a.picture.manager = m
+		}
+	}
+	return a.picture, nil
+}
+
+// Asset returns the Asset of the Picture.
+func (p *Picture) Asset() *Asset {
+	return p.asset
+}
+
+// PictureData returns the current content of the Picture, in its own coordinates even if the
+// Picture is packed into an atlas.
+func (p *Picture) PictureData() *pixel.PictureData {
+	return p.asset.Value().(*pixel.PictureData)
+}
+
+// Bounds returns the bounds of the current content of the Picture, or the bounds of its atlas if
+// it's packed into one.
+func (p *Picture) Bounds() pixel.Rect {
+	if p.packed() {
+		return p.atlas.pic.Bounds()
+	}
+	return p.PictureData().Bounds()
+}
+
+// Region returns the rectangle of the current content within the Bounds, which are just the Bounds
+// unless the Picture is packed into an atlas.
+func (p *Picture) Region() pixel.Rect {
+	return p.Frame(p.PictureData().Bounds())
+}
+
+// Frame converts a frame in the coordinates of the current content, such as a frame of a sprite
+// sheet, into the coordinates of the Bounds, which is only needed if the Picture is packed into an
+// atlas. See Picture.
+func (p *Picture) Frame(r pixel.Rect) pixel.Rect {
+	if !p.packed() {
+		return r
+	}
+	return r.Moved(p.region.Min.Sub(p.PictureData().Bounds().Min))
+}
+
+// Color returns the color of the current content of the Picture at the position, in the
+// coordinates of the Bounds.
+func (p *Picture) Color(at pixel.Vec) pixel.RGBA {
+	if p.packed() {
+		return p.atlas.pic.Color(at)
+	}
+	return p.PictureData().Color(at)
+}
+
+// packed returns whether the Picture is packed into an atlas, repacking it after a reload: into
+// the same region if it has the same size, otherwise into a new one, or into its own texture if
+// it's not small anymore.
+func (p *Picture) packed() bool {
+	if p.atlas == nil {
+		return false
+	}
+	if p.atlasVersion != p.asset.Version() {
+		pd := p.PictureData()
+		_, _, w, h := intRect(pd.Bounds())
+		_, _, rw, rh := intRect(p.region)
+		switch {
+		case w == rw && h == rh:
+			p.atlas.copy(pd, int(p.region.Min.X), int(p.region.Min.Y))
+		case p.manager.packable(pd):
+			p.atlas, p.region = p.manager.pack(pd)
+		default:
+			p.atlas = nil
+			return false
+		}
+		p.atlasVersion = p.asset.Version()
+	}
+	return true
+}
+
+// Texture returns the OpenGL texture of the current content of the Picture, uploading it after a
+// reload.
+func (p *Picture) Texture() pixelgl.Texture {
+	if p.packed() {
+		return p.atlas.texture()
+	}
+	if p.gl == nil || p.version != p.asset.Version() {
+		p.gl = pixelgl.NewGLPicture(p.PictureData())
+		p.version = p.asset.Version()
+	}
+	return p.gl.Texture()
+}
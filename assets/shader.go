@@ -0,0 +1,145 @@
+package assets
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+)
+
+// LoadShader is the Loader of the GLSL shaders, the value is the source as a string.
+func LoadShader(file string, open func(file string) (io.ReadCloser, error)) (interface{}, error) {
+	f, err := open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	src, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(src), nil
+}
+
+// ShaderTarget is a target of a Shader, such as a *pixelgl.Canvas.
+type ShaderTarget interface {
+	TrySetFragmentShader(src string) error
+}
+
+var _ ShaderTarget = (*pixelgl.Canvas)(nil)
+
+// Shader is a fragment shader Asset set to the ShaderTargets, which is recompiled when its file
+// changes. If it fails to compile, the targets keep the last working shader and Err returns the
+// error with the GLSL error log, which DrawOverlay shows on the screen until the shader is fixed:
+//
+//   shader, err := assets.Shader("shaders/water.frag", canvas)
+//   if err != nil {
+//       panic(err)
+//   }
+//   for !win.Closed() {
+//       assets.Update(dt)
+//       ...
+//       canvas.Draw(win, pixel.IM.Moved(win.Bounds().Center()))
+//       shader.DrawOverlay(win, win.Bounds())
+//       win.Update()
+//   }
+//
+// The uniforms of the shader must be set on the targets before the Shader is created, like before
+// SetFragmentShader.
+type Shader struct {
+	asset   *Asset
+	targets []ShaderTarget
+	err     error
+
+	txt *text.Text
+	imd *imdraw.IMDraw
+}
+
+// Shader loads the fragment shader file, sets it to the targets and returns the Shader recompiling
+// it on each reload. The Loader of the file must load a string, such as LoadShader. An error is
+// returned if the shader can't be loaded or compiled.
+func (m *Manager) Shader(file string, targets ...ShaderTarget) (*Shader, error) {
+	a, err := m.Load(file)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := a.Value().(string); !ok {
+		return nil, fmt.Errorf("assets: %s is not a shader", a.File())
+	}
+	s := &Shader{asset: a, targets: targets}
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	a.OnChange(func(*Asset) {
+		s.compile()
+	})
+	return s, nil
+}
+
+// Asset returns the Asset of the Shader.
+func (s *Shader) Asset() *Asset {
+	return s.asset
+}
+
+// Err returns the error of the last reload of the Shader, or nil if the targets have the current
+// content of its file.
+func (s *Shader) Err() error {
+	if err := s.asset.Err(); err != nil {
+		return err
+	}
+	return s.err
+}
+
+// DrawOverlay draws the error of the Shader, if any, over the top of the bounds of the Target.
+func (s *Shader) DrawOverlay(t pixel.Target, bounds pixel.Rect) {
+	err := s.Err()
+	if err == nil {
+		return
+	}
+	if s.txt == nil {
+		s.txt = text.New(pixel.ZV, text.Atlas7x13)
+		s.imd = imdraw.New(nil)
+	}
+
+	const pad = 4
+	lines := append([]string{s.asset.File()}, strings.Split(strings.TrimSpace(err.Error()), "\n")...)
+	lineHeight := s.txt.Atlas().LineHeight()
+	height := float64(len(lines))*lineHeight + 2*pad
+
+	s.imd.Clear()
+	s.imd.Color = pixel.Alpha(0.85)
+	s.imd.Push(pixel.V(bounds.Min.X, bounds.Max.Y-height), bounds.Max)
+	s.imd.Rectangle(0)
+	s.imd.Draw(t)
+
+	s.txt.Clear()
+	s.txt.Orig = pixel.V(bounds.Min.X+pad, bounds.Max.Y-pad-s.txt.Atlas().Ascent())
+	s.txt.Dot = s.txt.Orig
+	for i, line := range lines {
+		s.txt.Color = color.RGBA{R: 0xff, G: 0x60, B: 0x60, A: 0xff}
+		if i == 0 {
+			s.txt.Color = color.White
+		}
+		s.txt.WriteString(line)
+		s.txt.WriteByte('\n')
+	}
+	s.txt.Draw(t, pixel.IM)
+}
+
+// compile sets the current source of the Shader to its targets
+func (s *Shader) compile() error {
+	src := s.asset.Value().(string)
+	s.err = nil
+	for _, t := range s.targets {
+		if err := t.TrySetFragmentShader(src); err != nil {
+			s.err = fmt.Errorf("assets: failed to compile %s: %v", s.asset.File(), err)
+		}
+	}
+	return s.err
+}
@@ -9,6 +9,9 @@ import (
 //
 // To put an object into a Batch, just draw it onto it:
 //   object.Draw(batch)
+//
+// The vertices of large draws are transformed on multiple goroutines, if both the drawn Triangles
+// and the container are TrianglesData. See SetTransformWorkers.
 type Batch struct {
 	cont Drawer
 
@@ -49,6 +52,12 @@ func (b *Batch) Clear() {
 	b.cont.Dirty()
 }
 
+// Freeze tells the Batch that its objects won't change, so the Targets it's drawn onto keep its
+// content ready to be drawn. Drawing onto the Batch or clearing it unfreezes it. See Drawer.Freeze.
+func (b *Batch) Freeze() {
+	b.cont.Freeze()
+}
+
 // Draw draws all objects that are currently in the Batch onto another Target.
 func (b *Batch) Draw(t Target) {
 	b.cont.Draw(t)
@@ -126,6 +135,28 @@ func (bt *batchTriangles) Copy() Triangles {
 }
 
 func (bt *batchTriangles) draw(bp *batchPicture) {
+	defer BeginPhase(PhaseTransform).End()
+
+	// TrianglesData short path, the vertices are transformed straight into the container, in
+	// parallel if there's many of them
+	src, srcOk := bt.tri.(*TrianglesData)
+	dst, dstOk := bt.dst.cont.Triangles.(*TrianglesData)
+	if srcOk && dstOk {
+		off := dst.Len()
+		dst.SetLen(off + src.Len())
+		mat, col := bt.dst.mat, bt.dst.col
+		ParallelVertices(src.Len(), func(i, j int) {
+			added := (*dst)[off+i : off+j]
+			for k, v := range (*src)[i:j] {
+				v.Position = mat.Project(v.Position)
+				v.Color = col.Mul(v.Color)
+				added[k] = v
+			}
+		})
+		bt.dst.cont.Dirty()
+		return
+	}
+
 	bt.tmp.Update(bt.tri)
 
 	for i := range *bt.tmp {
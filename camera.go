@@ -0,0 +1,299 @@
+package pixel
+
+import "math"
+
+// Camera is a view of a 2D world on a screen: the position in the world it looks at, its zoom and
+// rotation. It produces the Matrix to draw the world with and converts the positions between the
+// world and the screen, e.g. to find what is under the mouse.
+//
+// The Screen is the rectangle of the Target in its own (screen) coordinates, usually the bounds of
+// the Window. The world position Pos appears at the center of the Screen.
+//
+//   cam := pixel.NewCamera(win.Bounds())
+//   cam.Pos = player.Pos
+//   win.SetMatrix(cam.Matrix())
+//   world.Draw(win)
+//
+//   win.SetMatrix(pixel.IM) // the UI is drawn in the screen coordinates
+//   hovered := world.At(cam.ScreenToWorld(win.MousePosition()))
+type Camera struct {
+	// Pos is the position in the world at the center of the Screen.
+	Pos Vec
+
+	// Zoom is the scale of the world on the Screen, 2 shows everything twice as big.
+	Zoom float64
+
+	// Angle is the rotation of the Camera in radians. The world appears rotated by the opposite
+	// angle.
+	Angle float64
+
+	// Screen is the rectangle which the Camera projects the world to.
+	Screen Rect
+
+	// Snap rounds the view to the whole pixels of the Screen, while the Pos stays as it is. With
+	// an unrotated Camera and a whole-number Zoom, the pixels of the pixel art then land exactly on
+	// the pixels of the Screen, which removes the shimmering and the cracks between the tiles of a
+	// smoothly moving Camera. Draw the moving objects at the Snapped positions to align them too.
+	Snap bool
+}
+
+// NewCamera creates a new Camera projecting to the screen rectangle, looking at the origin of the
+// world with the zoom of 1.
+func NewCamera(screen Rect) *Camera {
+	return &Camera{
+		Zoom:   1,
+		Screen: screen,
+	}
+}
+
+// Matrix returns the Matrix which transforms the world coordinates to the screen coordinates (the
+// view Matrix). Set it to the Target before drawing the world.
+func (c *Camera) Matrix() Matrix {
+	m := IM.
+		Moved(c.Pos.Scaled(-1)).
+		Rotated(ZV, -c.Angle).
+		Scaled(ZV, c.Zoom).
+		Moved(c.Screen.Center())
+	if c.Snap {
+		m[4], m[5] = math.Round(m[4]), math.Round(m[5])
+	}
+	return m
+}
+
+// Snapped returns the world position u moved to the nearest position that appears on the whole
+// pixels of the Screen.
+func (c *Camera) Snapped(u Vec) Vec {
+	m := c.Matrix()
+	return m.Unproject(m.Project(u).Map(math.Round))
+}
+
+// WorldToScreen returns the position on the screen where the world position u appears.
+func (c *Camera) WorldToScreen(u Vec) Vec {
+	return c.Matrix().Project(u)
+}
+
+// ScreenToWorld returns the position in the world that appears at the screen position u.
+func (c *Camera) ScreenToWorld(u Vec) Vec {
+	return c.Matrix().Unproject(u)
+}
+
+// VisibleBounds returns the smallest rectangle in the world that contains everything visible on
+// the Screen. It's larger than the visible area of a rotated Camera.
+func (c *Camera) VisibleBounds() Rect {
+	return c.Culler().View()
+}
+
+// Culler returns a Culler of the objects visible by the Camera.
+func (c *Camera) Culler() Culler {
+	return NewCuller(c.Matrix(), c.Screen)
+}
+
+// ZoomAround multiplies the zoom of the Camera by the factor, keeping the world position that
+// appears at the screen position around in its place. Zooming around the mouse position zooms to
+// what is under the mouse.
+func (c *Camera) ZoomAround(around Vec, factor float64) {
+	world := c.ScreenToWorld(around)
+	c.Zoom *= factor
+	// move the Camera by how much the world position moved away from around
+	c.Pos = c.Pos.Add(world.Sub(c.ScreenToWorld(around)))
+}
+
+// FitRect moves and zooms the Camera to show the whole world rectangle r as large as possible,
+// with at least padding screen pixels around it, e.g. to show the whole level or the area of a
+// cutscene. The Angle is kept, a rotated Camera fits the rotated rectangle.
+func (c *Camera) FitRect(r Rect, padding float64) {
+	// the size of r as it appears rotated on the screen
+	sin, cos := math.Sincos(c.Angle)
+	sin, cos = math.Abs(sin), math.Abs(cos)
+	w := r.W()*cos + r.H()*sin
+	h := r.W()*sin + r.H()*cos
+
+	c.Pos = r.Center()
+	c.Zoom = math.Min((c.Screen.W()-2*padding)/w, (c.Screen.H()-2*padding)/h)
+}
+
+// CameraController moves a Camera every frame: it follows a target smoothly or with a deadzone,
+// keeps the view inside the bounds of a level and shakes it. Configure it with its fields and call
+// Update with the time elapsed since the last frame.
+//
+//   ctrl := pixel.NewCameraController(cam)
+//   ctrl.Follow = true
+//   ctrl.Lag = 0.2
+//   ctrl.Deadzone = pixel.R(-40, -20, 40, 20)
+//   ctrl.Bounds = level.Bounds()
+//
+//   for !win.Closed() {
+//       dt := time.Since(last).Seconds()
+//       last = time.Now()
+//
+//       if player.Hit() {
+//           ctrl.AddTrauma(0.5)
+//       }
+//       ctrl.Target = player.Pos
+//       ctrl.Update(dt)
+//       win.SetMatrix(cam.Matrix())
+//       ...
+//   }
+//
+// The shake is added to the Camera's Pos and Angle on each Update and removed on the next one, so
+// the Camera can still be moved and rotated directly.
+type CameraController struct {
+	Camera *Camera
+
+	// Follow enables following the Target.
+	Follow bool
+
+	// Target is the world position the Camera follows.
+	Target Vec
+
+	// Lag is the time in seconds in which the Camera catches up a half of the distance to the
+	// Target. Zero makes it keep up immediately.
+	Lag float64
+
+	// Deadzone is a rectangle relative to the Camera's position, in the world units, inside which
+	// the Target moves freely. The Camera only follows the Target once it leaves the Deadzone.
+	Deadzone Rect
+
+	// Bounds are the bounds of the level in the world. If they're not empty, the Camera doesn't
+	// show anything outside of them. If the level is smaller than the view, the Camera stays in
+	// its center.
+	Bounds Rect
+
+	// MaxShakeOffset and MaxShakeAngle are the largest distance in the world units and the largest
+	// rotation in radians, by which the Camera is shaken at the full trauma.
+	MaxShakeOffset Vec
+	MaxShakeAngle  float64
+
+	// ShakeFrequency is the number of shakes per second. Zero means 15.
+	ShakeFrequency float64
+
+	// TraumaDecay is how much trauma is lost in a second. Zero means 1.
+	TraumaDecay float64
+
+	trauma      float64
+	time        float64
+	shakeOffset Vec
+	shakeAngle  float64
+}
+
+// NewCameraController creates a new CameraController moving the Camera. It shakes the Camera by
+// up to 10 world units and 0.05 radians.
+func NewCameraController(cam *Camera) *CameraController {
+	return &CameraController{
+		Camera:         cam,
+		MaxShakeOffset: V(10, 10),
+		MaxShakeAngle:  0.05,
+	}
+}
+
+// AddTrauma adds the amount of trauma, which makes the Camera shake. The trauma is within range
+// [0, 1]: the shake gets stronger with the square of it, so small hits make a little shake and big
+// ones a violent one. The trauma decays over time.
+func (cc *CameraController) AddTrauma(amount float64) {
+	cc.trauma = math.Max(0, math.Min(1, cc.trauma+amount))
+}
+
+// Trauma returns the current trauma of the CameraController.
+func (cc *CameraController) Trauma() float64 {
+	return cc.trauma
+}
+
+// Update moves the Camera by the time dt in seconds elapsed since the last Update.
+func (cc *CameraController) Update(dt float64) {
+	cam := cc.Camera
+
+	// remove the last shake
+	cam.Pos = cam.Pos.Sub(cc.shakeOffset)
+	cam.Angle -= cc.shakeAngle
+
+	if cc.Follow {
+		cam.Pos = cc.follow(cam.Pos, dt)
+	}
+	if cc.Bounds.Area() > 0 {
+		cam.Pos = cc.clamp(cam.Pos)
+	}
+
+	cc.time += dt
+	decay := cc.TraumaDecay
+	if decay == 0 {
+		decay = 1
+	}
+	cc.trauma = math.Max(0, cc.trauma-decay*dt)
+
+	freq := cc.ShakeFrequency
+	if freq == 0 {
+		freq = 15
+	}
+	shake := cc.trauma * cc.trauma
+	t := cc.time * freq
+	cc.shakeOffset = V(
+		cc.MaxShakeOffset.X*shake*smoothNoise(0, t),
+		cc.MaxShakeOffset.Y*shake*smoothNoise(1, t),
+	)
+	cc.shakeAngle = cc.MaxShakeAngle * shake * smoothNoise(2, t)
+
+	cam.Pos = cam.Pos.Add(cc.shakeOffset)
+	cam.Angle += cc.shakeAngle
+}
+
+// follow returns the Camera position moved towards the Target by the time dt.
+func (cc *CameraController) follow(pos Vec, dt float64) Vec {
+	// the nearest position which has the Target in the Deadzone
+	want := pos
+	rel := cc.Target.Sub(pos)
+	dz := cc.Deadzone.Norm()
+	if rel.X < dz.Min.X {
+		want.X += rel.X - dz.Min.X
+	} else if rel.X > dz.Max.X {
+		want.X += rel.X - dz.Max.X
+	}
+	if rel.Y < dz.Min.Y {
+		want.Y += rel.Y - dz.Min.Y
+	} else if rel.Y > dz.Max.Y {
+		want.Y += rel.Y - dz.Max.Y
+	}
+
+	if cc.Lag <= 0 {
+		return want
+	}
+	// a half of the distance remains after each Lag, independent of the frame rate
+	remain := math.Pow(0.5, dt/cc.Lag)
+	return want.Add(pos.Sub(want).Scaled(remain))
+}
+
+// clamp returns the Camera position moved so that the view stays inside the Bounds.
+func (cc *CameraController) clamp(pos Vec) Vec {
+	view := *cc.Camera
+	view.Pos = pos
+	vb := view.VisibleBounds()
+	half := vb.Size().Scaled(0.5)
+	b := cc.Bounds.Norm()
+
+	fit := func(p, half, min, max float64) float64 {
+		if max-min < 2*half {
+			return (min + max) / 2
+		}
+		return math.Max(min+half, math.Min(max-half, p))
+	}
+	return V(
+		fit(pos.X, half.X, b.Min.X, b.Max.X),
+		fit(pos.Y, half.Y, b.Min.Y, b.Max.Y),
+	)
+}
+
+// smoothNoise returns a smooth pseudo-random value within range [-1, 1], which changes about once
+// per unit of t. Each seed gives a different sequence.
+func smoothNoise(seed int, t float64) float64 {
+	hash := func(i int64) float64 {
+		h := uint64(i)*0x9e3779b97f4a7c15 + uint64(seed)*0xbf58476d1ce4e5b9
+		h ^= h >> 31
+		h *= 0x94d049bb133111eb
+		h ^= h >> 29
+		return float64(h%(1<<24))/(1<<23) - 1
+	}
+	fl := math.Floor(t)
+	f := t - fl
+	f = f * f * (3 - 2*f)
+	a, b := hash(int64(fl)), hash(int64(fl)+1)
+	return a + (b-a)*f
+}
@@ -0,0 +1,161 @@
+package pixel_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertVec(t *testing.T, want, got pixel.Vec) {
+	t.Helper()
+	assert.InDelta(t, want.X, got.X, 1e-9)
+	assert.InDelta(t, want.Y, got.Y, 1e-9)
+}
+
+func TestCamera(t *testing.T) {
+	cam := pixel.NewCamera(pixel.R(0, 0, 800, 600))
+	cam.Pos = pixel.V(100, 50)
+
+	assertVec(t, pixel.V(400, 300), cam.WorldToScreen(pixel.V(100, 50)))
+	assertVec(t, pixel.V(410, 300), cam.WorldToScreen(pixel.V(110, 50)))
+	assert.Equal(t, pixel.R(-300, -250, 500, 350), cam.VisibleBounds())
+
+	cam.Zoom = 2
+	assertVec(t, pixel.V(420, 300), cam.WorldToScreen(pixel.V(110, 50)))
+	assert.Equal(t, pixel.R(-100, -100, 300, 200), cam.VisibleBounds())
+
+	cam.Angle = math.Pi / 2
+	// the world turns the other way
+	assertVec(t, pixel.V(400, 280), cam.WorldToScreen(pixel.V(110, 50)))
+	bounds := cam.VisibleBounds()
+	assertVec(t, pixel.V(-50, -150), bounds.Min)
+	assertVec(t, pixel.V(250, 250), bounds.Max)
+
+	for _, u := range []pixel.Vec{pixel.ZV, pixel.V(13, -7), pixel.V(400, 300)} {
+		assertVec(t, u, cam.ScreenToWorld(cam.WorldToScreen(u)))
+	}
+}
+
+func TestCamera_ZoomAround(t *testing.T) {
+	cam := pixel.NewCamera(pixel.R(0, 0, 800, 600))
+	cam.Angle = 1
+
+	mouse := pixel.V(700, 100)
+	under := cam.ScreenToWorld(mouse)
+	cam.ZoomAround(mouse, 3)
+
+	assert.InDelta(t, 3, cam.Zoom, 1e-9)
+	assertVec(t, under, cam.ScreenToWorld(mouse))
+}
+
+func TestCameraController_Follow(t *testing.T) {
+	cam := pixel.NewCamera(pixel.R(0, 0, 800, 600))
+	ctrl := pixel.NewCameraController(cam)
+	ctrl.Follow = true
+
+	ctrl.Target = pixel.V(100, 200)
+	ctrl.Update(1.0 / 60)
+	assertVec(t, pixel.V(100, 200), cam.Pos)
+
+	// a half of the distance after each Lag, whatever the frame rate
+	ctrl.Lag = 0.5
+	ctrl.Target = pixel.V(200, 200)
+	for i := 0; i < 30; i++ {
+		ctrl.Update(1.0 / 60)
+	}
+	assertVec(t, pixel.V(150, 200), cam.Pos)
+	ctrl.Update(0.5)
+	assertVec(t, pixel.V(175, 200), cam.Pos)
+
+	// the Target moves freely inside the Deadzone
+	ctrl.Lag = 0
+	ctrl.Deadzone = pixel.R(-50, -20, 50, 20)
+	cam.Pos = pixel.ZV
+	ctrl.Target = pixel.V(40, -10)
+	ctrl.Update(1)
+	assertVec(t, pixel.ZV, cam.Pos)
+	ctrl.Target = pixel.V(70, -30)
+	ctrl.Update(1)
+	assertVec(t, pixel.V(20, -10), cam.Pos)
+}
+
+func TestCameraController_Bounds(t *testing.T) {
+	cam := pixel.NewCamera(pixel.R(0, 0, 800, 600))
+	ctrl := pixel.NewCameraController(cam)
+	ctrl.Bounds = pixel.R(0, 0, 2000, 500)
+
+	cam.Pos = pixel.V(100, 100)
+	ctrl.Update(0)
+	// the level is lower than the view, it stays vertically centered
+	assertVec(t, pixel.V(400, 250), cam.Pos)
+
+	cam.Zoom = 2
+	cam.Pos = pixel.V(1990, 0)
+	ctrl.Update(0)
+	assertVec(t, pixel.V(1800, 150), cam.Pos)
+}
+
+func TestCameraController_Shake(t *testing.T) {
+	cam := pixel.NewCamera(pixel.R(0, 0, 800, 600))
+	ctrl := pixel.NewCameraController(cam)
+
+	ctrl.AddTrauma(2)
+	assert.Equal(t, 1.0, ctrl.Trauma())
+
+	shaken := false
+	for i := 0; i < 30; i++ {
+		ctrl.Update(1.0 / 60)
+		if cam.Pos.Len() > 10*math.Sqrt2 || math.Abs(cam.Angle) > 0.05 {
+			t.Fatalf("shaken too much to %v, angle %v", cam.Pos, cam.Angle)
+		}
+		if cam.Pos != pixel.ZV {
+			shaken = true
+		}
+	}
+	assert.True(t, shaken)
+	assert.InDelta(t, 0.5, ctrl.Trauma(), 1e-9)
+
+	// the shake stops and the Camera returns back once the trauma is gone
+	ctrl.Update(1)
+	assert.Equal(t, 0.0, ctrl.Trauma())
+	assertVec(t, pixel.ZV, cam.Pos)
+	assert.InDelta(t, 0, cam.Angle, 1e-12)
+}
+
+func TestCamera_Snap(t *testing.T) {
+	cam := pixel.NewCamera(pixel.R(0, 0, 320, 180))
+	cam.Zoom = 2
+	cam.Pos = pixel.V(10.3, 20.8)
+	cam.Snap = true
+
+	m := cam.Matrix()
+	assert.Equal(t, pixel.V(10.3, 20.8), cam.Pos)
+	for _, u := range []pixel.Vec{pixel.ZV, pixel.V(16, 32), pixel.V(-5, 7)} {
+		p := m.Project(u)
+		assert.Equal(t, p, p.Map(math.Round), "world %v projected to %v", u, p)
+	}
+
+	// the snapped positions appear on the whole pixels, the closest to the original ones
+	u := pixel.V(3.3, 4.1)
+	snapped := cam.Snapped(u)
+	p := cam.WorldToScreen(snapped)
+	assertVec(t, p.Map(math.Round), p)
+	assert.True(t, snapped.Sub(u).Len() <= math.Sqrt2/4)
+}
+
+func TestCamera_FitRect(t *testing.T) {
+	cam := pixel.NewCamera(pixel.R(0, 0, 800, 600))
+	cam.FitRect(pixel.R(100, 100, 300, 200), 100)
+
+	assertVec(t, pixel.V(200, 150), cam.Pos)
+	assert.InDelta(t, 3, cam.Zoom, 1e-9)
+	assertVec(t, pixel.V(100, 150), cam.WorldToScreen(pixel.V(100, 100)))
+	assertVec(t, pixel.V(700, 450), cam.WorldToScreen(pixel.V(300, 200)))
+
+	// rotated by 90 degrees, the rectangle is higher than wide on the screen
+	cam.Angle = math.Pi / 2
+	cam.FitRect(pixel.R(100, 100, 300, 200), 100)
+	assert.InDelta(t, 2, cam.Zoom, 1e-9)
+}
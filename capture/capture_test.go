@@ -0,0 +1,216 @@
+package capture_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/faiface/pixel/capture"
+	"github.com/stretchr/testify/assert"
+)
+
+// frame returns an image of the size, its left half in the color a and its right half in b
+func frame(w, h int, a, b color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.SetRGBA(x, y, a)
+			} else {
+				img.SetRGBA(x, y, b)
+			}
+		}
+	}
+	return img
+}
+
+var (
+	red   = color.RGBA{R: 255, A: 255}
+	green = color.RGBA{G: 255, A: 255}
+	blue  = color.RGBA{B: 255, A: 255}
+)
+
+func TestGIF_Update(t *testing.T) {
+	g := capture.NewGIF(1, 10, 1)
+	var due int
+	for i := 0; i < 60; i++ {
+		if g.Update(1.0 / 60) {
+			due++
+		}
+	}
+	assert.InDelta(t, 10, due, 1)
+}
+
+func TestGIF_Encode(t *testing.T) {
+	g := capture.NewGIF(0.3, 10, 0.5)
+	var buf bytes.Buffer
+	assert.Error(t, g.Encode(&buf), "no frames")
+
+	g.Add(frame(40, 20, blue, blue))
+	g.Add(frame(40, 20, red, green))
+	g.Add(frame(40, 20, green, red))
+	g.Add(frame(40, 20, red, red))
+	assert.Equal(t, 3, g.Len(), "the last 0.3 seconds")
+
+	assert.NoError(t, g.Encode(&buf))
+	anim, err := gif.DecodeAll(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, anim.Image, 3)
+	assert.Equal(t, []int{10, 10, 10}, anim.Delay)
+	assert.Equal(t, 0, anim.LoopCount)
+
+	for i, want := range [][2]color.RGBA{{red, green}, {green, red}, {red, red}} {
+		img := anim.Image[i]
+		assert.Equal(t, image.Rect(0, 0, 20, 10), img.Bounds())
+		assert.Equal(t, want[0], rgba(img.At(2, 5)), "frame %d", i)
+		assert.Equal(t, want[1], rgba(img.At(17, 5)), "frame %d", i)
+	}
+
+	g.Reset()
+	assert.Equal(t, 0, g.Len())
+}
+
+func TestGIF_Dither(t *testing.T) {
+	// a gradient of more colors than a GIF can have
+	img := image.NewRGBA(image.Rect(0, 0, 512, 4))
+	for x := 0; x < 512; x++ {
+		for y := 0; y < 4; y++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x / 2), G: uint8(255 - x/2), B: uint8(x % 256), A: 255})
+		}
+	}
+	for _, dither := range []bool{false, true} {
+		g := capture.NewGIF(1, 10, 1)
+		g.Dither = dither
+		g.Add(img)
+		var buf bytes.Buffer
+		assert.NoError(t, g.Encode(&buf))
+		anim, err := gif.DecodeAll(&buf)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(anim.Image[0].Palette), 256)
+
+		// the average error stays small
+		var sum float64
+		for x := 0; x < 512; x++ {
+			got, want := rgba(anim.Image[0].At(x, 1)), img.RGBAAt(x, 1)
+			sum += abs(int(got.R)-int(want.R)) + abs(int(got.G)-int(want.G)) + abs(int(got.B)-int(want.B))
+		}
+		assert.Less(t, sum/512/3, 12.0, "dither %v", dither)
+	}
+}
+
+// TestMain runs the test binary as a fake ffmpeg, if the Video tests start it
+func TestMain(m *testing.M) {
+	if os.Getenv("CAPTURE_FAKE_FFMPEG") == "1" {
+		fakeFFmpeg(os.Args[1:])
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeFFmpeg counts the bytes of the video on the stdin and the audio on the file descriptor 3 and
+// writes them with the arguments to the output file
+func fakeFFmpeg(args []string) {
+	var (
+		wg           sync.WaitGroup
+		video, audio int64
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		video, _ = io.Copy(ioutil.Discard, os.Stdin)
+	}()
+	for _, arg := range args {
+		if arg == "pipe:3" {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				audio, _ = io.Copy(ioutil.Discard, os.NewFile(3, "audio"))
+			}()
+		}
+	}
+	wg.Wait()
+	out := args[len(args)-1]
+	if strings.HasSuffix(out, ".fail") {
+		fmt.Fprintln(os.Stderr, "unknown format")
+		os.Exit(1)
+	}
+	ioutil.WriteFile(out, []byte(fmt.Sprintf("video=%d audio=%d args=%s", video, audio, strings.Join(args, " "))), 0644)
+}
+
+func newFakeVideo(t *testing.T, path string, opts capture.VideoOptions) *capture.Video {
+	os.Setenv("CAPTURE_FAKE_FFMPEG", "1")
+	defer os.Unsetenv("CAPTURE_FAKE_FFMPEG")
+	opts.FFmpeg = os.Args[0]
+	v, err := capture.NewVideo(path, 41, 20, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestVideo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "capture")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "clip.mp4")
+	v := newFakeVideo(t, path, capture.VideoOptions{FPS: 10, SampleRate: 100})
+
+	// 0.35 seconds of the game at 10 frames per second
+	var due []int
+	for i := 0; i < 7; i++ {
+		due = append(due, v.Update(0.05))
+	}
+	assert.Equal(t, []int{0, 1, 0, 1, 0, 1, 0}, due)
+	assert.Equal(t, 2, v.Update(0.2), "a slow frame")
+
+	assert.NoError(t, v.Add(frame(80, 40, red, green)))
+	assert.NoError(t, v.Add(frame(40, 20, red, green)))
+	assert.Equal(t, 2, v.Frames())
+	assert.InDelta(t, 0.2, v.Time(), 1e-9)
+	assert.NoError(t, v.WriteAudio(make([]float32, 40)))
+
+	assert.NoError(t, v.Close())
+	assert.Error(t, v.Add(frame(40, 20, red, green)), "closed")
+	assert.Error(t, v.Close())
+
+	out, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), fmt.Sprintf("video=%d audio=%d ", 2*40*20*4, 40*4))
+	assert.Contains(t, string(out), "-s 40x20 -r 10 -i pipe:0")
+	assert.Contains(t, string(out), "-f f32le -ar 100 -ac 2 -i pipe:3")
+	assert.Contains(t, string(out), "-c:v libx264")
+
+	v = newFakeVideo(t, filepath.Join(dir, "clip.fail"), capture.VideoOptions{})
+	assert.Error(t, v.WriteAudio(nil), "no audio")
+	err = v.Close()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown format")
+	}
+
+	_, err = capture.NewVideo(path, 1, 1, capture.VideoOptions{})
+	assert.Error(t, err, "too small")
+	_, err = capture.NewVideo(path, 40, 20, capture.VideoOptions{FFmpeg: filepath.Join(dir, "nope")})
+	assert.Error(t, err, "no ffmpeg")
+}
+
+func rgba(c color.Color) color.RGBA {
+	r, g, b, a := c.RGBA()
+	return color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(a >> 8)}
+}
+
+func abs(v int) float64 {
+	if v < 0 {
+		return float64(-v)
+	}
+	return float64(v)
+}
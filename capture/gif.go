@@ -0,0 +1,202 @@
+// Package capture records the gameplay of a Window, for sharing the clips and the bug reports.
+//
+// A GIF keeps the last few seconds of the frames and encodes them as an animated GIF on demand:
+//
+//   rec := capture.NewGIF(5, 15, 0.5)
+//   for !win.Closed() {
+//       ...
+//       win.Update()
+//       rec.Record(win, dt)
+//       if win.JustPressed(pixelgl.KeyF12) {
+//           go rec.Save("clip.gif")
+//       }
+//   }
+//
+// A Video streams the frames to ffmpeg, which encodes them into an MP4 or a WebM file.
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/faiface/pixel/pixelgl"
+	xdraw "golang.org/x/image/draw"
+)
+
+// GIF records the last Seconds of the frames at the FPS, scaled by the Scale, and encodes them
+// into an animated GIF with a palette of 256 colors quantized from all the frames.
+//
+// The frames can be added from any goroutine.
+type GIF struct {
+	// Seconds is the length of the recorded clip.
+	Seconds float64
+
+	// FPS is the number of the frames recorded per second, at most 50 in a GIF.
+	FPS float64
+
+	// Scale is the scale of the recorded frames to the Window, e.g. 0.5 halves the width and the
+	// height of the clip.
+	Scale float64
+
+	// Dither enables the Floyd-Steinberg dithering of the quantized colors, smoother gradients
+	// for a larger file.
+	Dither bool
+
+	mu     sync.Mutex
+	frames []gifFrame
+	seq    int
+	timer  float64
+}
+
+type gifFrame struct {
+	seq int
+	img *image.RGBA
+}
+
+// NewGIF creates a new GIF recorder of the last seconds of the frames, recorded at the fps and
+// scaled by the scale.
+func NewGIF(seconds, fps, scale float64) *GIF {
+	return &GIF{Seconds: seconds, FPS: fps, Scale: scale}
+}
+
+// Update advances the time of the recording by dt and reports whether the next frame is due.
+func (g *GIF) Update(dt float64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.timer += dt
+	if g.timer < 1/g.FPS {
+		return false
+	}
+	g.timer = math.Mod(g.timer, 1/g.FPS)
+	return true
+}
+
+// Record takes a screenshot of the Window without waiting for it, if the next frame is due after
+// dt. It should be called once per frame, after the Window is updated.
+func (g *GIF) Record(win *pixelgl.Window, dt float64) {
+	if !g.Update(dt) {
+		return
+	}
+	seq := g.reserve()
+	win.ScreenshotAsync(func(img *image.RGBA) {
+		g.put(seq, img)
+	})
+}
+
+// Add adds the image as the next frame, scaled by the Scale.
+func (g *GIF) Add(img image.Image) {
+	g.put(g.reserve(), img)
+}
+
+// Len returns the number of the recorded frames.
+func (g *GIF) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.frames)
+}
+
+// Reset removes all the recorded frames.
+func (g *GIF) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.frames, g.timer = nil, 0
+}
+
+// Encode writes the recorded frames to the writer as an animated GIF, looping forever.
+func (g *GIF) Encode(w io.Writer) error {
+	g.mu.Lock()
+	frames := append([]gifFrame(nil), g.frames...)
+	fps, dither := g.FPS, g.Dither
+	g.mu.Unlock()
+
+	if len(frames) == 0 {
+		return errors.New("capture: no frames recorded")
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i].seq < frames[j].seq })
+
+	images := make([]*image.RGBA, len(frames))
+	for i := range frames {
+		images[i] = frames[i].img
+	}
+	q := newQuantizer(images, 256)
+
+	delay := int(math.Round(100 / fps))
+	if delay < 2 {
+		delay = 2
+	}
+	anim := &gif.GIF{
+		Image: make([]*image.Paletted, len(images)),
+		Delay: make([]int, len(images)),
+	}
+	for i, img := range images {
+		anim.Image[i] = q.paletted(img, dither)
+		anim.Delay[i] = delay
+	}
+	if err := gif.EncodeAll(w, anim); err != nil {
+		return fmt.Errorf("capture: failed to encode GIF: %v", err)
+	}
+	return nil
+}
+
+// Save encodes the recorded frames as an animated GIF to the file at the path.
+func (g *GIF) Save(path string) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("capture: failed to save GIF: %v", err)
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("capture: failed to save GIF: %v", cerr)
+		}
+	}()
+	return g.Encode(file)
+}
+
+// reserve returns the sequence number of the next frame
+func (g *GIF) reserve() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.seq++
+	return g.seq
+}
+
+// put adds the frame of the sequence number, dropping the frames older than Seconds
+func (g *GIF) put(seq int, img image.Image) {
+	scaled := scale(img, g.Scale)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.frames = append(g.frames, gifFrame{seq: seq, img: scaled})
+	max := int(math.Ceil(g.Seconds * g.FPS))
+	if max < 1 {
+		max = 1
+	}
+	if len(g.frames) > max {
+		sort.Slice(g.frames, func(i, j int) bool { return g.frames[i].seq < g.frames[j].seq })
+		g.frames = append(g.frames[:0], g.frames[len(g.frames)-max:]...)
+	}
+}
+
+// scale returns a copy of the image scaled by the scale
+func scale(img image.Image, s float64) *image.RGBA {
+	b := img.Bounds()
+	if s <= 0 {
+		s = 1
+	}
+	w := int(math.Max(1, math.Round(float64(b.Dx())*s)))
+	h := int(math.Max(1, math.Round(float64(b.Dy())*s)))
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if w == b.Dx() && h == b.Dy() {
+		xdraw.Draw(dst, dst.Rect, img, b.Min, xdraw.Src)
+	} else {
+		xdraw.ApproxBiLinear.Scale(dst, dst.Rect, img, b, xdraw.Src, nil)
+	}
+	return dst
+}
@@ -0,0 +1,179 @@
+package capture
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// quantizer maps the colors to a palette, through a lookup table of the colors reduced to 5 bits
+// per component
+type quantizer struct {
+	palette color.Palette
+	rgb     [][3]int
+	lut     []int16
+}
+
+// maxSamples is the maximal number of the pixels of all the frames the palette is made of
+const maxSamples = 1 << 17
+
+// newQuantizer creates a quantizer of the palette of at most n colors, made of the pixels of the
+// images by the median cut
+func newQuantizer(images []*image.RGBA, n int) *quantizer {
+	var total int
+	for _, img := range images {
+		total += img.Rect.Dx() * img.Rect.Dy()
+	}
+	step := total/maxSamples + 1
+
+	samples := make([][3]uint8, 0, total/step+len(images))
+	for _, img := range images {
+		for i := 0; i+3 < len(img.Pix); i += 4 * step {
+			samples = append(samples, [3]uint8{img.Pix[i], img.Pix[i+1], img.Pix[i+2]})
+		}
+	}
+
+	q := &quantizer{lut: make([]int16, 1<<15)}
+	for i := range q.lut {
+		q.lut[i] = -1
+	}
+	for _, c := range medianCut(samples, n) {
+		q.palette = append(q.palette, color.RGBA{R: c[0], G: c[1], B: c[2], A: 0xff})
+		q.rgb = append(q.rgb, [3]int{int(c[0]), int(c[1]), int(c[2])})
+	}
+	if len(q.palette) == 0 {
+		q.palette = color.Palette{color.Black}
+		q.rgb = [][3]int{{0, 0, 0}}
+	}
+	return q
+}
+
+// index returns the index of the color of the palette nearest to the color
+func (q *quantizer) index(r, g, b int) uint8 {
+	key := r>>3<<10 | g>>3<<5 | b>>3
+	if i := q.lut[key]; i >= 0 {
+		return uint8(i)
+	}
+	// the center of the reduced color
+	r, g, b = r>>3<<3|4, g>>3<<3|4, b>>3<<3|4
+	best, bestDist := 0, 1<<30
+	for i, c := range q.rgb {
+		dr, dg, db := r-c[0], g-c[1], b-c[2]
+		if d := dr*dr + dg*dg + db*db; d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	q.lut[key] = int16(best)
+	return uint8(best)
+}
+
+// paletted returns the image in the colors of the palette, dithered by the Floyd-Steinberg error
+// diffusion if dither is true
+func (q *quantizer) paletted(img *image.RGBA, dither bool) *image.Paletted {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	dst := image.NewPaletted(image.Rect(0, 0, w, h), q.palette)
+
+	// the errors of the current and the next row, with a pixel of margin on both sides
+	var cur, next [][3]int
+	if dither {
+		cur, next = make([][3]int, w+2), make([][3]int, w+2)
+	}
+	for y := 0; y < h; y++ {
+		src := img.Pix[y*img.Stride:]
+		for x := 0; x < w; x++ {
+			r, g, b := int(src[4*x]), int(src[4*x+1]), int(src[4*x+2])
+			if !dither {
+				dst.Pix[y*dst.Stride+x] = q.index(r, g, b)
+				continue
+			}
+			e := cur[x+1]
+			r, g, b = clamp8(r+e[0]/16), clamp8(g+e[1]/16), clamp8(b+e[2]/16)
+			i := q.index(r, g, b)
+			dst.Pix[y*dst.Stride+x] = i
+			c := q.rgb[i]
+			for k, v := range [3]int{r - c[0], g - c[1], b - c[2]} {
+				cur[x+2][k] += 7 * v
+				next[x][k] += 3 * v
+				next[x+1][k] += 5 * v
+				next[x+2][k] += v
+			}
+		}
+		if dither {
+			cur, next = next, cur
+			for i := range next {
+				next[i] = [3]int{}
+			}
+		}
+	}
+	return dst
+}
+
+// medianCut returns at most n colors representing the samples, by splitting the box of the colors
+// with the largest range at the median until there are n boxes.
+func medianCut(samples [][3]uint8, n int) [][3]uint8 {
+	if len(samples) == 0 {
+		return nil
+	}
+	boxes := [][][3]uint8{samples}
+	for len(boxes) < n {
+		best, bestChannel, bestRange := -1, 0, 0
+		for i, box := range boxes {
+			if channel, rng := widest(box); rng > bestRange {
+				best, bestChannel, bestRange = i, channel, rng
+			}
+		}
+		if best < 0 {
+			break
+		}
+		box := boxes[best]
+		sort.Slice(box, func(i, j int) bool { return box[i][bestChannel] < box[j][bestChannel] })
+		mid := len(box) / 2
+		boxes[best] = box[:mid]
+		boxes = append(boxes, box[mid:])
+	}
+
+	colors := make([][3]uint8, len(boxes))
+	for i, box := range boxes {
+		var sum [3]int
+		for _, c := range box {
+			sum[0] += int(c[0])
+			sum[1] += int(c[1])
+			sum[2] += int(c[2])
+		}
+		for k := range sum {
+			colors[i][k] = uint8((sum[k] + len(box)/2) / len(box))
+		}
+	}
+	return colors
+}
+
+// widest returns the component of the colors with the largest range, and the range
+func widest(box [][3]uint8) (channel, rng int) {
+	min, max := [3]uint8{255, 255, 255}, [3]uint8{}
+	for _, c := range box {
+		for k := range c {
+			if c[k] < min[k] {
+				min[k] = c[k]
+			}
+			if c[k] > max[k] {
+				max[k] = c[k]
+			}
+		}
+	}
+	for k := range min {
+		if r := int(max[k]) - int(min[k]); r > rng {
+			channel, rng = k, r
+		}
+	}
+	return channel, rng
+}
+
+func clamp8(v int) int {
+	switch {
+	case v < 0:
+		return 0
+	case v > 255:
+		return 255
+	}
+	return v
+}
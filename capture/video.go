@@ -0,0 +1,330 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/faiface/pixel/pixelgl"
+	xdraw "golang.org/x/image/draw"
+)
+
+// VideoOptions are the options of a Video.
+type VideoOptions struct {
+	// FFmpeg is the path of the ffmpeg executable, "ffmpeg" by default.
+	FFmpeg string
+
+	// FPS is the frame rate of the video, 60 by default.
+	FPS float64
+
+	// SampleRate and Channels are the format of the audio written by WriteAudio. There's no audio
+	// if SampleRate is zero.
+	SampleRate int
+	Channels   int
+
+	// Args are the arguments of ffmpeg for the output, such as the codecs, replacing the defaults
+	// of the format of the file.
+	Args []string
+}
+
+// Video streams the frames and the audio to an ffmpeg subprocess, which encodes them into a video
+// file, such as MP4 or WebM. The frames are written by another goroutine, so the encoding doesn't
+// slow down the game.
+//
+//   vid, err := capture.NewVideo("trailer.mp4", 1280, 720, capture.VideoOptions{FPS: 60})
+//   if err != nil {
+//       panic(err)
+//   }
+//   for !win.Closed() {
+//       ...
+//       win.Update()
+//       vid.Record(win, dt)
+//   }
+//   if err := vid.Close(); err != nil {
+//       panic(err)
+//   }
+type Video struct {
+	width, height int
+	fps           float64
+	audio         bool
+
+	cmd     *exec.Cmd
+	stderr  bytes.Buffer
+	frames  chan []byte
+	samples chan []byte
+	wg      sync.WaitGroup
+
+	mu     sync.Mutex
+	err    error
+	count  int
+	timer  float64
+	closed bool
+}
+
+// NewVideo starts ffmpeg encoding a video of the size to the file at the path, replacing it. The
+// size is rounded down to even numbers, which most codecs need.
+func NewVideo(path string, width, height int, opts VideoOptions) (*Video, error) {
+	if opts.FFmpeg == "" {
+		opts.FFmpeg = "ffmpeg"
+	}
+	if opts.FPS <= 0 {
+		opts.FPS = 60
+	}
+	if opts.SampleRate > 0 && opts.Channels <= 0 {
+		opts.Channels = 2
+	}
+	width, height = width&^1, height&^1
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("capture: invalid video size %dx%d", width, height)
+	}
+
+	v := &Video{
+		width:  width,
+		height: height,
+		fps:    opts.FPS,
+		audio:  opts.SampleRate > 0,
+		frames: make(chan []byte, 8),
+	}
+	v.cmd = exec.Command(opts.FFmpeg, ffmpegArgs(path, width, height, opts)...)
+	v.cmd.Stderr = &limitedWriter{buf: &v.stderr, n: 4096}
+	stdin, err := v.cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("capture: failed to start ffmpeg: %v", err)
+	}
+
+	var audioR, audioW *os.File
+	if v.audio {
+		if audioR, audioW, err = os.Pipe(); err != nil {
+			return nil, fmt.Errorf("capture: failed to start ffmpeg: %v", err)
+		}
+		v.cmd.ExtraFiles = []*os.File{audioR}
+		v.samples = make(chan []byte, 64)
+	}
+
+	if err := v.cmd.Start(); err != nil {
+		if v.audio {
+			audioR.Close()
+			audioW.Close()
+		}
+		return nil, fmt.Errorf("capture: failed to start ffmpeg: %v", err)
+	}
+	v.wg.Add(1)
+	go v.pipe(stdin, v.frames)
+	if v.audio {
+		audioR.Close()
+		v.wg.Add(1)
+		go v.pipe(audioW, v.samples)
+	}
+	return v, nil
+}
+
+// Update advances the time of the recording by dt and returns the number of the frames due, so
+// the video keeps the pace of the game. It's more than one when the game is slower than the video,
+// and zero when it's faster.
+func (v *Video) Update(dt float64) int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.timer += dt * v.fps
+	n := int(v.timer)
+	v.timer -= float64(n)
+	return n
+}
+
+// Record takes a screenshot of the Window and writes it as many times as the frames due after dt.
+// It should be called once per frame, after the Window is updated.
+func (v *Video) Record(win *pixelgl.Window, dt float64) error {
+	n := v.Update(dt)
+	if n == 0 {
+		return v.Err()
+	}
+	return v.write(win.Screenshot(), n)
+}
+
+// Add writes the image as the next frame, scaled to the size of the Video. Unlike Record, it
+// ignores the time, e.g. for rendering a replay offline at an exact frame rate.
+func (v *Video) Add(img image.Image) error {
+	return v.write(img, 1)
+}
+
+// WriteAudio writes the interleaved samples within range [-1, 1] of the audio. To keep the audio
+// in sync, the game should write as many samples as the Time of the video takes, e.g. by mixing its
+// audio to a buffer each frame and writing it after Record.
+func (v *Video) WriteAudio(samples []float32) error {
+	if !v.audio {
+		return errors.New("capture: video without audio")
+	}
+	if err := v.check(); err != nil {
+		return err
+	}
+	data := make([]byte, 4*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(s))
+	}
+	v.samples <- data
+	return nil
+}
+
+// Frames returns the number of the frames written.
+func (v *Video) Frames() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.count
+}
+
+// Time returns the length of the video written, in seconds.
+func (v *Video) Time() float64 {
+	return float64(v.Frames()) / v.fps
+}
+
+// Err returns the error of writing to ffmpeg, if it failed.
+func (v *Video) Err() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.err
+}
+
+// Close finishes the video, waits for ffmpeg to encode the rest of it and returns the first error
+// of the recording. It must not be called concurrently with the methods writing to the Video.
+func (v *Video) Close() error {
+	v.mu.Lock()
+	if v.closed {
+		v.mu.Unlock()
+		return errors.New("capture: video already closed")
+	}
+	v.closed = true
+	v.mu.Unlock()
+
+	close(v.frames)
+	if v.audio {
+		close(v.samples)
+	}
+	v.wg.Wait()
+	werr := v.cmd.Wait()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.err == nil && werr != nil {
+		v.err = fmt.Errorf("capture: ffmpeg failed: %v: %s", werr, strings.TrimSpace(v.stderr.String()))
+	}
+	return v.err
+}
+
+func (v *Video) write(img image.Image, n int) error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	frame := fit(img, v.width, v.height)
+	for i := 0; i < n; i++ {
+		v.frames <- frame.Pix
+	}
+	v.mu.Lock()
+	v.count += n
+	v.mu.Unlock()
+	return nil
+}
+
+// check returns the error of the Video or whether it's closed
+func (v *Video) check() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.closed {
+		return errors.New("capture: video closed")
+	}
+	return v.err
+}
+
+// pipe writes the data to the writer until the channel is closed, draining it after an error
+func (v *Video) pipe(w io.WriteCloser, ch chan []byte) {
+	defer v.wg.Done()
+	var failed bool
+	for data := range ch {
+		if failed {
+			continue
+		}
+		if _, err := w.Write(data); err != nil {
+			v.mu.Lock()
+			if v.err == nil {
+				v.err = fmt.Errorf("capture: failed to write to ffmpeg: %v", err)
+			}
+			v.mu.Unlock()
+			failed = true
+		}
+	}
+	w.Close()
+}
+
+// ffmpegArgs returns the arguments of ffmpeg reading the raw frames from the stdin and the raw audio
+// from the file descriptor 3
+func ffmpegArgs(path string, width, height int, opts VideoOptions) []string {
+	args := []string{
+		"-y", "-loglevel", "error",
+		"-f", "rawvideo", "-pix_fmt", "rgba",
+		"-s", fmt.Sprintf("%dx%d", width, height),
+		"-r", strconv.FormatFloat(opts.FPS, 'f', -1, 64),
+		"-i", "pipe:0",
+	}
+	if opts.SampleRate > 0 {
+		args = append(args,
+			"-f", "f32le",
+			"-ar", strconv.Itoa(opts.SampleRate),
+			"-ac", strconv.Itoa(opts.Channels),
+			"-i", "pipe:3",
+		)
+	}
+
+	output := opts.Args
+	if output == nil {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".mp4", ".mov", ".mkv":
+			output = []string{"-c:v", "libx264", "-preset", "veryfast", "-crf", "18", "-pix_fmt", "yuv420p"}
+			if opts.SampleRate > 0 {
+				output = append(output, "-c:a", "aac", "-b:a", "192k")
+			}
+		case ".webm":
+			output = []string{"-c:v", "libvpx-vp9", "-b:v", "0", "-crf", "30", "-pix_fmt", "yuv420p"}
+			if opts.SampleRate > 0 {
+				output = append(output, "-c:a", "libopus")
+			}
+		}
+	}
+	args = append(args, output...)
+	return append(args, path)
+}
+
+// fit returns a copy of the image as an RGBA image of the size, scaled if needed
+func fit(img image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	b := img.Bounds()
+	if b.Dx() == width && b.Dy() == height {
+		xdraw.Draw(dst, dst.Rect, img, b.Min, xdraw.Src)
+	} else {
+		xdraw.ApproxBiLinear.Scale(dst, dst.Rect, img, b, xdraw.Src, nil)
+	}
+	return dst
+}
+
+// limitedWriter keeps the first n bytes written to it
+type limitedWriter struct {
+	buf *bytes.Buffer
+	n   int
+}
+
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	if rest := lw.n - lw.buf.Len(); rest > 0 {
+		if len(p) < rest {
+			rest = len(p)
+		}
+		lw.buf.Write(p[:rest])
+	}
+	return len(p), nil
+}
@@ -0,0 +1,162 @@
+package collide_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/collide"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertVec(t *testing.T, want, got pixel.Vec, msgAndArgs ...interface{}) {
+	t.Helper()
+	assert.InDelta(t, want.X, got.X, 1e-4, msgAndArgs...)
+	assert.InDelta(t, want.Y, got.Y, 1e-4, msgAndArgs...)
+}
+
+func TestSweep(t *testing.T) {
+	box := collide.RectShape(pixel.R(0, 0, 2, 2))
+	wall := collide.RectShape(pixel.R(4, -10, 5, 10))
+	ball := collide.CircleShape(pixel.C(pixel.V(10, 10), 1))
+
+	tests := []struct {
+		name   string
+		a      collide.Shape
+		delta  pixel.Vec
+		b      collide.Shape
+		ok     bool
+		time   float64
+		normal pixel.Vec
+	}{
+		{"rect into rect", box, pixel.V(4, 0), wall, true, 0.5, pixel.V(-1, 0)},
+		{"rect short of rect", box, pixel.V(1, 0), wall, false, 0, pixel.ZV},
+		{"rect away from rect", box, pixel.V(-4, 0), wall, false, 0, pixel.ZV},
+		{"rect along rect", box.Moved(pixel.V(0, 10)), pixel.V(10, 0), box, false, 0, pixel.ZV},
+		{"rect touching rect", box.Moved(pixel.V(2, 0)), pixel.V(1, 0), wall, true, 0, pixel.V(-1, 0)},
+		{"circle into circle", ball.Moved(pixel.V(-10, 0)), pixel.V(16, 0), ball, true, 0.5, pixel.V(-1, 0)},
+		{"rect into circle edge", box.Moved(pixel.V(-1, 3)), pixel.V(0, 8), ball.Moved(pixel.V(-10, 0)), true, 0.5, pixel.V(0, -1)},
+		{
+			"rect into circle corner", box, pixel.V(10, 10), ball, true,
+			(8 - 1/math.Sqrt2) / 10, pixel.V(-1, -1).Unit(),
+		},
+		{"rect past circle corner", box, pixel.V(20, 12.2), ball, false, 0, pixel.ZV},
+	}
+	for _, test := range tests {
+		hit, ok := collide.Sweep(test.a, test.delta, test.b)
+		if !assert.Equal(t, test.ok, ok, test.name) || !ok {
+			continue
+		}
+		assert.InDelta(t, test.time, hit.Time, 1e-9, test.name)
+		assertVec(t, test.normal, hit.Normal, test.name)
+	}
+}
+
+func TestSweep_Overlapping(t *testing.T) {
+	a := collide.RectShape(pixel.R(0, 0, 2, 2))
+	b := collide.RectShape(pixel.R(1.5, 0, 10, 2))
+
+	hit, ok := collide.Sweep(a, pixel.V(1, 0), b)
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, hit.Time)
+	assert.Equal(t, pixel.V(-1, 0), hit.Normal)
+
+	_, ok = collide.Sweep(a, pixel.V(-1, 0), b)
+	assert.False(t, ok)
+}
+
+func TestPenetration(t *testing.T) {
+	a := collide.RectShape(pixel.R(0, 0, 2, 2))
+
+	push, ok := collide.Penetration(a, collide.RectShape(pixel.R(1.5, -5, 10, 5)))
+	assert.True(t, ok)
+	assert.Equal(t, pixel.V(-0.5, 0), push)
+
+	push, ok = collide.Penetration(a, collide.CircleShape(pixel.C(pixel.V(3, 3), 2)))
+	assert.True(t, ok)
+	assertVec(t, pixel.V(-1, -1).Unit().Scaled(2-math.Sqrt2), push)
+
+	_, ok = collide.Penetration(a, collide.RectShape(pixel.R(2, 0, 4, 2)))
+	assert.False(t, ok, "touching")
+}
+
+func TestMoveAndSlide(t *testing.T) {
+	floor := collide.RectShape(pixel.R(-100, -1, 100, 0))
+	wall := collide.RectShape(pixel.R(10, 0, 11, 100))
+	space := collide.Shapes{floor, wall}
+	body := collide.RectShape(pixel.R(0, 0, 2, 2)).Moved(pixel.V(0, 1))
+
+	// falling onto the floor and sliding along it
+	move, contacts := collide.MoveAndSlide(body, pixel.V(4, -2), space)
+	assertVec(t, pixel.V(4, -1), move)
+	assert.Equal(t, []collide.Contact{{ID: 0, Normal: pixel.V(0, 1)}}, contacts)
+
+	// sliding on the floor
+	body = body.Moved(move)
+	move, contacts = collide.MoveAndSlide(body, pixel.V(2, 0), space)
+	assertVec(t, pixel.V(2, 0), move)
+	assert.Empty(t, contacts)
+
+	// into the corner
+	body = body.Moved(move)
+	move, contacts = collide.MoveAndSlide(body, pixel.V(10, -1), space)
+	assertVec(t, pixel.V(2, 0), move)
+	assert.Equal(t, []collide.Contact{{ID: 0, Normal: pixel.V(0, 1)}, {ID: 1, Normal: pixel.V(-1, 0)}}, contacts)
+
+	// pushed out of the wall
+	move, contacts = collide.MoveAndSlide(body.Moved(pixel.V(3, 0)), pixel.ZV, space)
+	assertVec(t, pixel.V(-1, 0), move)
+	assert.Equal(t, []collide.Contact{{ID: 1, Normal: pixel.V(-1, 0)}}, contacts)
+}
+
+func TestMoveAndSlide_Circle(t *testing.T) {
+	// a ball rolls off the corner of a box
+	box := collide.RectShape(pixel.R(0, 0, 10, 10))
+	ball := collide.CircleShape(pixel.C(pixel.V(-2, 5), 1))
+	move, contacts := collide.MoveAndSlide(ball, pixel.V(2, 0), collide.Shapes{box})
+	assertVec(t, pixel.V(1, 0), move)
+	assert.Len(t, contacts, 1)
+
+	ball = collide.CircleShape(pixel.C(pixel.V(-1, 10.5), 1))
+	move, contacts = collide.MoveAndSlide(ball, pixel.V(2, 0), collide.Shapes{box})
+	assert.Len(t, contacts, 1)
+	assert.True(t, move.Y > 0, "slides up over the corner")
+	assert.True(t, move.X > 0)
+}
+
+func TestSlide(t *testing.T) {
+	assert.Equal(t, pixel.V(3, 0), collide.Slide(pixel.V(3, -4), pixel.V(0, 1)))
+	assert.Equal(t, pixel.V(3, 4), collide.Slide(pixel.V(3, 4), pixel.V(0, 1)))
+}
+
+func TestGrid(t *testing.T) {
+	g := collide.NewGrid(10)
+	g.Set(1, collide.RectShape(pixel.R(0, 0, 5, 5)))
+	g.Set(2, collide.RectShape(pixel.R(5, 5, 35, 15)))
+	g.Set(3, collide.CircleShape(pixel.C(pixel.V(-20, -20), 2)))
+	assert.Equal(t, 3, g.Len())
+
+	query := func(r pixel.Rect) []int {
+		var ids []int
+		g.Query(r, func(id int, s collide.Shape) { ids = append(ids, id) })
+		return ids
+	}
+	assert.Equal(t, []int{2}, query(pixel.R(20, 0, 100, 100)))
+	assert.ElementsMatch(t, []int{1, 2}, query(pixel.R(-5, -5, 50, 50)))
+	assert.Equal(t, []int{3}, query(pixel.R(-30, -30, -18, -18)))
+	assert.Empty(t, query(pixel.R(-30, -30, -23, -23)))
+
+	g.Set(2, collide.RectShape(pixel.R(-30, -30, -25, -25)))
+	assert.Empty(t, query(pixel.R(20, 0, 100, 100)))
+	assert.ElementsMatch(t, []int{2, 3}, query(pixel.R(-30, -30, -18, -18)))
+
+	g.Remove(3)
+	_, ok := g.Shape(3)
+	assert.False(t, ok)
+	assert.Equal(t, []int{2}, query(pixel.R(-30, -30, -18, -18)))
+
+	// the Grid works as a Space
+	move, contacts := collide.MoveAndSlide(collide.RectShape(pixel.R(-10, 0, -8, 2)), pixel.V(10, 0), g)
+	assertVec(t, pixel.V(8, 0), move)
+	assert.Equal(t, []collide.Contact{{ID: 1, Normal: pixel.V(-1, 0)}}, contacts)
+}
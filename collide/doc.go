@@ -0,0 +1,4 @@
+// Package collide implements the kinematic movement through obstacles: sweeping of the shapes,
+// pushing them out of each other and moving with sliding along the surfaces, which covers most
+// games without a physics engine.
+package collide
@@ -0,0 +1,183 @@
+package collide
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Shape is a collision shape: a rectangle with the corners rounded by the Radius. A Shape with a
+// zero Radius is a rectangle and a Shape with an empty Rect is a circle.
+type Shape struct {
+	Rect   pixel.Rect
+	Radius float64
+}
+
+// RectShape returns the Shape of the rectangle.
+func RectShape(r pixel.Rect) Shape {
+	return Shape{Rect: r.Norm()}
+}
+
+// CircleShape returns the Shape of the circle.
+func CircleShape(c pixel.Circle) Shape {
+	c = c.Norm()
+	return Shape{Rect: pixel.Rect{Min: c.Center, Max: c.Center}, Radius: c.Radius}
+}
+
+// Bounds returns the bounding rectangle of the Shape.
+func (s Shape) Bounds() pixel.Rect {
+	r := pixel.V(s.Radius, s.Radius)
+	return pixel.Rect{Min: s.Rect.Min.Sub(r), Max: s.Rect.Max.Add(r)}
+}
+
+// Moved returns the Shape moved by delta.
+func (s Shape) Moved(delta pixel.Vec) Shape {
+	s.Rect = s.Rect.Moved(delta)
+	return s
+}
+
+// Hit is the first contact of a moving Shape with an obstacle.
+type Hit struct {
+	// Time is the fraction of the movement before the contact, within range [0, 1].
+	Time float64
+
+	// Normal is the unit normal of the obstacle's surface at the contact, pointing out of it.
+	Normal pixel.Vec
+}
+
+// Sweep moves the Shape a by delta and returns its first contact with the Shape b. If they already
+// overlap, the contact is at the Time 0 with the Normal of their Penetration, but only if a moves
+// deeper into b, so it can always move out.
+func Sweep(a Shape, delta pixel.Vec, b Shape) (Hit, bool) {
+	m := minkowski(a, b)
+	p := a.Rect.Center()
+	if push, ok := m.push(p); ok {
+		n := push.Unit()
+		if delta.Dot(n) >= 0 {
+			return Hit{}, false
+		}
+		return Hit{Time: 0, Normal: n}, true
+	}
+	return m.ray(p, delta)
+}
+
+// Penetration returns the shortest vector moving the Shape a out of the Shape b, if they overlap.
+// The Shapes which only touch don't overlap.
+func Penetration(a, b Shape) (pixel.Vec, bool) {
+	return minkowski(a, b).push(a.Rect.Center())
+}
+
+// minkowski returns the Shape b grown by the Shape a. The center of a's Rect is inside of it
+// exactly when a overlaps b.
+func minkowski(a, b Shape) Shape {
+	half := a.Rect.Size().Scaled(0.5)
+	return Shape{
+		Rect:   pixel.Rect{Min: b.Rect.Min.Sub(half), Max: b.Rect.Max.Add(half)},
+		Radius: a.Radius + b.Radius,
+	}
+}
+
+// closest returns the point of the Shape's Rect closest to p.
+func (s Shape) closest(p pixel.Vec) pixel.Vec {
+	return pixel.V(
+		pixel.Clamp(p.X, s.Rect.Min.X, s.Rect.Max.X),
+		pixel.Clamp(p.Y, s.Rect.Min.Y, s.Rect.Max.Y),
+	)
+}
+
+// push returns the shortest vector moving the point p out of the Shape, if it's inside.
+func (s Shape) push(p pixel.Vec) (pixel.Vec, bool) {
+	if q := s.closest(p); q != p {
+		d := p.Sub(q)
+		dist := d.Len()
+		if dist >= s.Radius {
+			return pixel.ZV, false
+		}
+		return d.Scaled((s.Radius - dist) / dist), true
+	}
+
+	// inside the Rect, out through its closest side
+	sides := [...]struct {
+		dist float64
+		dir  pixel.Vec
+	}{
+		{p.X - s.Rect.Min.X, pixel.V(-1, 0)},
+		{s.Rect.Max.X - p.X, pixel.V(1, 0)},
+		{p.Y - s.Rect.Min.Y, pixel.V(0, -1)},
+		{s.Rect.Max.Y - p.Y, pixel.V(0, 1)},
+	}
+	closest := sides[0]
+	for _, side := range sides[1:] {
+		if side.dist < closest.dist {
+			closest = side
+		}
+	}
+	if closest.dist+s.Radius <= 0 {
+		return pixel.ZV, false
+	}
+	return closest.dir.Scaled(closest.dist + s.Radius), true
+}
+
+// ray returns the first contact of the point p moving by delta from the outside of the Shape.
+func (s Shape) ray(p, delta pixel.Vec) (Hit, bool) {
+	if delta == pixel.ZV {
+		return Hit{}, false
+	}
+
+	// the slabs of the bounds
+	b := s.Bounds()
+	enter, exit := math.Inf(-1), math.Inf(1)
+	var normal pixel.Vec
+	for axis := 0; axis < 2; axis++ {
+		pos, d, min, max, n := p.X, delta.X, b.Min.X, b.Max.X, pixel.V(1, 0)
+		if axis == 1 {
+			pos, d, min, max, n = p.Y, delta.Y, b.Min.Y, b.Max.Y, pixel.V(0, 1)
+		}
+		if d == 0 {
+			if pos <= min || pos >= max {
+				return Hit{}, false
+			}
+			continue
+		}
+		t1, t2, n1 := (min-pos)/d, (max-pos)/d, n.Scaled(-1)
+		if t1 > t2 {
+			t1, t2, n1 = t2, t1, n
+		}
+		if t1 > enter {
+			enter, normal = t1, n1
+		}
+		if t2 < exit {
+			exit = t2
+		}
+	}
+	if enter >= exit || enter > 1 || exit <= 0 {
+		return Hit{}, false
+	}
+	if s.Radius == 0 {
+		return Hit{Time: enter, Normal: normal}, true
+	}
+
+	// an edge, unless the point starts in a corner of the bounds
+	h := p.Add(delta.Scaled(math.Max(enter, 0)))
+	inX := s.Rect.Min.X <= h.X && h.X <= s.Rect.Max.X
+	inY := s.Rect.Min.Y <= h.Y && h.Y <= s.Rect.Max.Y
+	if enter >= 0 && (inX || inY) {
+		return Hit{Time: enter, Normal: normal}, true
+	}
+
+	// a rounded corner
+	c := s.closest(h)
+	f := p.Sub(c)
+	qa := delta.Dot(delta)
+	qb := f.Dot(delta)
+	qc := f.Dot(f) - s.Radius*s.Radius
+	disc := qb*qb - qa*qc
+	if disc < 0 {
+		return Hit{}, false
+	}
+	t := (-qb - math.Sqrt(disc)) / qa
+	if t < 0 || t > 1 {
+		return Hit{}, false
+	}
+	return Hit{Time: t, Normal: p.Add(delta.Scaled(t)).Sub(c).Unit()}, true
+}
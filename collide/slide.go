@@ -0,0 +1,85 @@
+package collide
+
+import "github.com/faiface/pixel"
+
+const (
+	// iterations limits the hits during a single movement, sliding into a corner takes two
+	iterations = 4
+
+	// skin is the distance kept from the hit obstacles, so that sliding along a surface doesn't hit
+	// it again
+	skin = 1e-6
+)
+
+// Contact is a contact of a moving Shape with an obstacle of a Space.
+type Contact struct {
+	// ID is the ID of the obstacle in the Space.
+	ID int
+
+	// Normal is the unit normal of the obstacle's surface at the contact, pointing out of it.
+	Normal pixel.Vec
+}
+
+// MoveAndSlide moves the body by delta through the obstacles of the Space. When it hits one, it
+// slides along its surface by the rest of the movement. A body overlapping the obstacles at the
+// start is pushed out of them first.
+//
+// It returns the resolved movement of the body and its contacts, in the order they happened.
+//
+//   move, contacts := collide.MoveAndSlide(player.Shape(), player.Vel.Scaled(dt), walls)
+//   player.Pos = player.Pos.Add(move)
+//   for _, c := range contacts {
+//       player.Vel = collide.Slide(player.Vel, c.Normal)
+//   }
+func MoveAndSlide(body Shape, delta pixel.Vec, space Space) (pixel.Vec, []Contact) {
+	start := body.Rect.Center()
+	var contacts []Contact
+
+	space.Query(body.Bounds(), func(id int, s Shape) {
+		if push, ok := Penetration(body, s); ok {
+			n := push.Unit()
+			body = body.Moved(push.Add(n.Scaled(skin)))
+			contacts = append(contacts, Contact{ID: id, Normal: n})
+		}
+	})
+
+	var prev pixel.Vec
+	for i := 0; i < iterations && delta != pixel.ZV; i++ {
+		id, hit, ok := first(body, delta, space)
+		if !ok {
+			body = body.Moved(delta)
+			break
+		}
+		body = body.Moved(delta.Scaled(hit.Time).Add(hit.Normal.Scaled(skin)))
+		contacts = append(contacts, Contact{ID: id, Normal: hit.Normal})
+
+		delta = Slide(delta.Scaled(1-hit.Time), hit.Normal)
+		if delta.Dot(prev) < 0 {
+			// stuck in a corner
+			break
+		}
+		prev = hit.Normal
+	}
+
+	return body.Rect.Center().Sub(start), contacts
+}
+
+// Slide returns the vector v without its component going into the surface with the unit normal,
+// e.g. the velocity of a body after a contact.
+func Slide(v, normal pixel.Vec) pixel.Vec {
+	if d := v.Dot(normal); d < 0 {
+		return v.Sub(normal.Scaled(d))
+	}
+	return v
+}
+
+// first returns the first hit of the body moving by delta with an obstacle of the Space.
+func first(body Shape, delta pixel.Vec, space Space) (id int, hit Hit, ok bool) {
+	bounds := body.Bounds().Union(body.Moved(delta).Bounds())
+	space.Query(bounds, func(i int, s Shape) {
+		if h, hitOK := Sweep(body, delta, s); hitOK && (!ok || h.Time < hit.Time) {
+			id, hit, ok = i, h, true
+		}
+	})
+	return id, hit, ok
+}
@@ -0,0 +1,131 @@
+package collide
+
+import (
+	"errors"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Space is a set of the obstacles identified by their IDs, such as a spatial index.
+type Space interface {
+	// Query calls the function for each obstacle which may overlap or touch the Rect, at most once
+	// for each.
+	Query(r pixel.Rect, fn func(id int, s Shape))
+}
+
+// Shapes is a Space of the Shapes in the slice, identified by their indices. It checks all of them,
+// which is fine for a few obstacles.
+type Shapes []Shape
+
+// Query calls the function for the Shapes whose bounds overlap or touch the Rect.
+func (ss Shapes) Query(r pixel.Rect, fn func(id int, s Shape)) {
+	for i, s := range ss {
+		if touches(r, s.Bounds()) {
+			fn(i, s)
+		}
+	}
+}
+
+// Grid is a spatial index of the Shapes, which finds them by the square cells of the grid they
+// overlap. The size of the cells should be about the size of the Shapes.
+//
+//   walls := collide.NewGrid(64)
+//   for i, r := range level.Walls {
+//       walls.Set(i, collide.RectShape(r))
+//   }
+type Grid struct {
+	cellSize float64
+	cells    map[[2]int][]int
+	shapes   map[int]Shape
+	marks    map[int]int
+	mark     int
+}
+
+// NewGrid creates a new empty Grid with the cells of the size.
+func NewGrid(cellSize float64) *Grid {
+	if cellSize <= 0 {
+		panic(errors.New("NewGrid: cell size must be positive"))
+	}
+	return &Grid{
+		cellSize: cellSize,
+		cells:    make(map[[2]int][]int),
+		shapes:   make(map[int]Shape),
+		marks:    make(map[int]int),
+	}
+}
+
+// Set adds the Shape with the ID to the Grid, or moves it, if the ID is already there.
+func (g *Grid) Set(id int, s Shape) {
+	g.Remove(id)
+	g.shapes[id] = s
+	g.eachCell(s.Bounds(), func(cell [2]int) {
+		g.cells[cell] = append(g.cells[cell], id)
+	})
+}
+
+// Remove removes the Shape with the ID from the Grid.
+func (g *Grid) Remove(id int) {
+	s, ok := g.shapes[id]
+	if !ok {
+		return
+	}
+	delete(g.shapes, id)
+	delete(g.marks, id)
+	g.eachCell(s.Bounds(), func(cell [2]int) {
+		ids := g.cells[cell]
+		for i := range ids {
+			if ids[i] == id {
+				ids = append(ids[:i], ids[i+1:]...)
+				break
+			}
+		}
+		if len(ids) == 0 {
+			delete(g.cells, cell)
+		} else {
+			g.cells[cell] = ids
+		}
+	})
+}
+
+// Shape returns the Shape with the ID and reports whether it's in the Grid.
+func (g *Grid) Shape(id int) (Shape, bool) {
+	s, ok := g.shapes[id]
+	return s, ok
+}
+
+// Len returns the number of the Shapes in the Grid.
+func (g *Grid) Len() int {
+	return len(g.shapes)
+}
+
+// Query calls the function for the Shapes whose bounds overlap or touch the Rect. The function must
+// not change the Grid.
+func (g *Grid) Query(r pixel.Rect, fn func(id int, s Shape)) {
+	g.mark++
+	g.eachCell(r, func(cell [2]int) {
+		for _, id := range g.cells[cell] {
+			if g.marks[id] == g.mark {
+				continue
+			}
+			g.marks[id] = g.mark
+			if s := g.shapes[id]; touches(r, s.Bounds()) {
+				fn(id, s)
+			}
+		}
+	})
+}
+
+func (g *Grid) eachCell(r pixel.Rect, fn func(cell [2]int)) {
+	minX, minY := int(math.Floor(r.Min.X/g.cellSize)), int(math.Floor(r.Min.Y/g.cellSize))
+	maxX, maxY := int(math.Floor(r.Max.X/g.cellSize)), int(math.Floor(r.Max.Y/g.cellSize))
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			fn([2]int{x, y})
+		}
+	}
+}
+
+func touches(a, b pixel.Rect) bool {
+	return a.Min.X <= b.Max.X && b.Min.X <= a.Max.X && a.Min.Y <= b.Max.Y && b.Min.Y <= a.Max.Y
+}
@@ -1,6 +1,9 @@
 package pixel
 
-import "image/color"
+import (
+	"image/color"
+	"math"
+)
 
 // RGBA represents an alpha-premultiplied RGBA color with components within range [0, 1].
 //
@@ -65,6 +68,108 @@ func (c RGBA) Scaled(scale float64) RGBA {
 	}
 }
 
+// Lerp returns the linear interpolation between colors c and d by t within range [0, 1]. The
+// components are interpolated as they are, in sRGB.
+func (c RGBA) Lerp(d RGBA, t float64) RGBA {
+	return c.Add(d.Sub(c).Scaled(t))
+}
+
+// Mix returns the mix of colors c and d by t within range [0, 1] in linear RGB, the way light
+// mixes. Unlike Lerp, the middle of a mix of two bright colors is not darker than they are.
+func (c RGBA) Mix(d RGBA, t float64) RGBA {
+	return fromLinear(toLinear(c).Lerp(toLinear(d), t))
+}
+
+// Luminance returns the relative luminance (the perceived brightness) of the color within range
+// [0, 1], premultiplied by its alpha.
+func (c RGBA) Luminance() float64 {
+	l := toLinear(c)
+	return 0.2126*l.R + 0.7152*l.G + 0.0722*l.B
+}
+
+// Brightened returns the color brightened by amount within range [-1, 1]. A positive amount mixes
+// the color with white, 1 makes it white. A negative amount mixes it with black, -1 makes it
+// black. The alpha is kept.
+func (c RGBA) Brightened(amount float64) RGBA {
+	to := Alpha(c.A)
+	if amount < 0 {
+		to = RGBA{A: c.A}
+		amount = -amount
+	}
+	return c.Lerp(to, math.Min(amount, 1))
+}
+
+// Saturated returns the color with its saturation adjusted by amount. A positive amount makes the
+// color more saturated, a negative amount less so, -1 makes it gray of the same luminance. The
+// components are clamped to the valid range.
+func (c RGBA) Saturated(amount float64) RGBA {
+	if c.A == 0 {
+		return c
+	}
+	// adjusted in linear RGB, so that the luminance is kept
+	l := toLinear(c)
+	gray := Alpha(c.Luminance())
+	gray.A = c.A
+	return fromLinear(clampRGBA(gray.Lerp(l, math.Max(1+amount, 0))))
+}
+
+// Tinted returns the color tinted by amount within range [0, 1] towards the tint's color, keeping
+// its alpha. Unlike Mul, a tint colors even the black and the white.
+func (c RGBA) Tinted(tint RGBA, amount float64) RGBA {
+	if tint.A != 0 {
+		tint = tint.Scaled(c.A / tint.A)
+	}
+	tint.A = c.A
+	return c.Lerp(tint, amount)
+}
+
+// Kelvin returns the color of the light with the color temperature in kelvins, from the orange of a
+// candle (about 1900 K), through the white of the daylight (6500 K), to the blue of the clear sky
+// (10000 K and above).
+func Kelvin(temperature float64) RGBA {
+	// Tanner Helland's approximation of the black body colors
+	t := math.Max(temperature, 1000) / 100
+	var r, g, b float64
+	if t <= 66 {
+		r = 255
+		g = 99.4708025861*math.Log(t) - 161.1195681661
+	} else {
+		r = 329.698727446 * math.Pow(t-60, -0.1332047592)
+		g = 288.1221695283 * math.Pow(t-60, -0.0755148492)
+	}
+	switch {
+	case t >= 66:
+		b = 255
+	case t <= 19:
+		b = 0
+	default:
+		b = 138.5177312231*math.Log(t-10) - 305.0447927307
+	}
+	return clampRGBA(RGB(r/255, g/255, b/255))
+}
+
+// WhiteBalanced returns the color as lit by the light with the color temperature in kelvins,
+// instead of the daylight (6500 K). A lower temperature makes the color warmer, a higher one
+// colder. The components are clamped to the valid range.
+func (c RGBA) WhiteBalanced(temperature float64) RGBA {
+	light, daylight := Kelvin(temperature), Kelvin(6500)
+	return clampRGBA(RGBA{
+		R: c.R * light.R / daylight.R,
+		G: c.G * light.G / daylight.G,
+		B: c.B * light.B / daylight.B,
+		A: c.A,
+	})
+}
+
+// clampRGBA clamps the components of the premultiplied color into range [0, A].
+func clampRGBA(c RGBA) RGBA {
+	c.A = math.Max(0, math.Min(1, c.A))
+	clamp := func(v float64) float64 {
+		return math.Max(0, math.Min(c.A, v))
+	}
+	return RGBA{clamp(c.R), clamp(c.G), clamp(c.B), c.A}
+}
+
 // RGBA returns alpha-premultiplied red, green, blue and alpha components of the RGBA color.
 func (c RGBA) RGBA() (r, g, b, a uint32) {
 	r = uint32(0xffff * c.R)
@@ -77,8 +182,13 @@ func (c RGBA) RGBA() (r, g, b, a uint32) {
 // ToRGBA converts a color to RGBA format. Using this function is preferred to using RGBAModel, for
 // performance (using RGBAModel introduces additional unnecessary allocations).
 func ToRGBA(c color.Color) RGBA {
-	if c, ok := c.(RGBA); ok {
+	switch c := c.(type) {
+	case RGBA:
 		return c
+	case HSV:
+		return c.toRGBA()
+	case HSL:
+		return c.toRGBA()
 	}
 	r, g, b, a := c.RGBA()
 	return RGBA{
@@ -22,3 +22,78 @@ func BenchmarkColorToRGBA(b *testing.B) {
 		})
 	}
 }
+
+func TestRGBALerpMix(t *testing.T) {
+	red, green := pixel.RGB(1, 0, 0), pixel.RGB(0, 1, 0)
+	if got, want := red.Lerp(green, 0.25), pixel.RGB(0.75, 0.25, 0); !eqColors(got, want) {
+		t.Errorf("Lerp = %v, want %v", got, want)
+	}
+	if got := red.Mix(green, 0.5); got.R < 0.73 || got.R > 0.74 || got.R != got.G {
+		t.Errorf("Mix = %v, want about RGB(0.735, 0.735, 0)", got)
+	}
+	if got := red.Mix(green, 1); !eqColors(got, green) {
+		t.Errorf("Mix = %v, want %v", got, green)
+	}
+}
+
+func TestRGBAAdjustments(t *testing.T) {
+	c := pixel.RGB(0.2, 0.4, 0.6).Scaled(0.5)
+
+	if got, want := c.Brightened(1), pixel.Alpha(0.5); !eqColors(got, want) {
+		t.Errorf("Brightened(1) = %v, want %v", got, want)
+	}
+	if got, want := c.Brightened(-0.5), pixel.RGB(0.1, 0.2, 0.3).Scaled(0.5); !eqColors(got, want) {
+		t.Errorf("Brightened(-0.5) = %v, want %v", got, want)
+	}
+
+	gray := c.Saturated(-1)
+	if !eqColors(pixel.RGBA{R: gray.G, G: gray.B, B: gray.R, A: gray.A}, gray) || gray.A != c.A {
+		t.Errorf("Saturated(-1) = %v, want gray", gray)
+	}
+	if got, want := gray.Luminance(), c.Luminance(); got-want > 1e-9 || want-got > 1e-9 {
+		t.Errorf("Saturated(-1) luminance = %v, want %v", got, want)
+	}
+	if got := c.Saturated(0); !eqColors(got, c) {
+		t.Errorf("Saturated(0) = %v, want %v", got, c)
+	}
+	if got := c.Saturated(10); got.R != 0 || got.B < c.A-1e-9 || got.B > c.A {
+		t.Errorf("Saturated(10) = %v, want clamped", got)
+	}
+
+	if got, want := pixel.RGB(1, 1, 1).Tinted(pixel.RGB(1, 0, 0), 0.5), pixel.RGB(1, 0.5, 0.5); !eqColors(got, want) {
+		t.Errorf("Tinted = %v, want %v", got, want)
+	}
+	if got := c.Tinted(pixel.RGB(1, 0, 0).Scaled(0.1), 1); !eqColors(got, pixel.RGB(1, 0, 0).Scaled(0.5)) {
+		t.Errorf("Tinted keeps alpha, got %v", got)
+	}
+}
+
+func TestKelvin(t *testing.T) {
+	if got := pixel.Kelvin(6600); got.R < 0.99 || got.G < 0.99 || got.B < 0.99 {
+		t.Errorf("Kelvin(6600) = %v, want about white", got)
+	}
+	if warm := pixel.Kelvin(2000); warm.R != 1 || warm.B >= warm.G || warm.G >= warm.R {
+		t.Errorf("Kelvin(2000) = %v, want orange", warm)
+	}
+	if cold := pixel.Kelvin(15000); cold.B != 1 || cold.R >= cold.B {
+		t.Errorf("Kelvin(15000) = %v, want blue", cold)
+	}
+
+	c := pixel.RGB(0.5, 0.5, 0.5)
+	if got := c.WhiteBalanced(6500); !eqColors(got, c) {
+		t.Errorf("WhiteBalanced(6500) = %v, want %v", got, c)
+	}
+	if got := c.WhiteBalanced(3000); got.R <= got.B {
+		t.Errorf("WhiteBalanced(3000) = %v, want warmer", got)
+	}
+}
+
+func TestComposeMultiply(t *testing.T) {
+	gray := pixel.RGB(0.5, 0.5, 0.5)
+	if got, want := pixel.ComposeMultiply.Compose(pixel.RGB(0.5, 1, 0), gray), pixel.RGB(0.25, 0.5, 0); !eqColors(got, want) {
+		t.Errorf("ComposeMultiply = %v, want %v", got, want)
+	}
+	if got := pixel.ComposeMultiply.Compose(pixel.Alpha(0), gray); !eqColors(got, gray) {
+		t.Errorf("ComposeMultiply with transparent = %v, want %v", got, gray)
+	}
+}
@@ -0,0 +1,157 @@
+package pixel
+
+import "errors"
+
+// ColorMatrix is a 3x3 matrix, which transforms the linear RGB (decoded from sRGB) components of
+// colors. The elements are in the row-major order, the first row makes the red component.
+//
+//   [0] [1] [2]   r
+//   [3] [4] [5] * g
+//   [6] [7] [8]   b
+type ColorMatrix [9]float64
+
+// IdentityColorMatrix is a ColorMatrix that leaves all colors as they are.
+var IdentityColorMatrix = ColorMatrix{
+	1, 0, 0,
+	0, 1, 0,
+	0, 0, 1,
+}
+
+// Chained returns a ColorMatrix that applies the ColorMatrix m and then the ColorMatrix next.
+func (m ColorMatrix) Chained(next ColorMatrix) ColorMatrix {
+	var r ColorMatrix
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				r[i*3+j] += next[i*3+k] * m[k*3+j]
+			}
+		}
+	}
+	return r
+}
+
+// Apply transforms the color by the ColorMatrix in linear RGB. The alpha is left as it is and the
+// resulting components are clamped to the valid range.
+func (m ColorMatrix) Apply(c RGBA) RGBA {
+	if c.A == 0 {
+		return c
+	}
+	l := toLinear(c)
+	r := m[0]*l.R + m[1]*l.G + m[2]*l.B
+	g := m[3]*l.R + m[4]*l.G + m[5]*l.B
+	b := m[6]*l.R + m[7]*l.G + m[8]*l.B
+	return fromLinear(clampRGBA(RGBA{r, g, b, c.A}))
+}
+
+// ColorBlindness is a type of color vision deficiency, in which one of the three kinds of the
+// cones in the eye is missing.
+//
+// The Simulation of a color blindness shows how the colors look to the people with it, which helps
+// to check that the important colors of a game don't get confused. The Daltonization corrects the
+// colors, so that the differences lost by the missing cones are moved to the colors that can still
+// be seen. Both are ColorMatrices, which can transform single colors, or be used as a post-process
+// filter of everything drawn (see pixelgl.ColorMatrixFragmentShader).
+//
+//   sim := pixel.Deuteranopia.Simulation()
+//   fmt.Println(sim.Apply(colornames.Red), sim.Apply(colornames.Green)) // both brownish yellow
+type ColorBlindness int
+
+const (
+	// Protanopia is the lack of the red (long wavelength) cones, reds look dark and are confused
+	// with greens.
+	Protanopia ColorBlindness = iota
+
+	// Deuteranopia is the lack of the green (medium wavelength) cones, the most common color
+	// blindness, in which reds and greens are confused.
+	Deuteranopia
+
+	// Tritanopia is the lack of the blue (short wavelength) cones, in which blues are confused with
+	// greens and yellows with violets.
+	Tritanopia
+)
+
+// simulations are the matrices of the full severity color blindness by Machado, Oliveira and
+// Fernandes (2009).
+var simulations = [...]ColorMatrix{
+	Protanopia: {
+		0.152286, 1.052583, -0.204868,
+		0.114503, 0.786281, 0.099216,
+		-0.003882, -0.048116, 1.051998,
+	},
+	Deuteranopia: {
+		0.367322, 0.860646, -0.227968,
+		0.280085, 0.672501, 0.047413,
+		-0.011820, 0.042940, 0.968881,
+	},
+	Tritanopia: {
+		1.255528, -0.076749, -0.178779,
+		-0.078411, 0.930809, 0.147602,
+		0.004733, 0.691367, 0.303900,
+	},
+}
+
+// errorShifts move the difference between a color and its simulation into the components, which
+// remain distinguishable with the color blindness.
+var errorShifts = [...]ColorMatrix{
+	Protanopia: {
+		0, 0, 0,
+		0.7, 1, 0,
+		0.7, 0, 1,
+	},
+	Deuteranopia: {
+		0, 0, 0,
+		0.7, 1, 0,
+		0.7, 0, 1,
+	},
+	Tritanopia: {
+		1, 0, 0.7,
+		0, 1, 0.7,
+		0, 0, 0,
+	},
+}
+
+// String returns a human-readable representation of the ColorBlindness.
+func (cb ColorBlindness) String() string {
+	switch cb {
+	case Protanopia:
+		return "protanopia"
+	case Deuteranopia:
+		return "deuteranopia"
+	case Tritanopia:
+		return "tritanopia"
+	default:
+		return "unknown"
+	}
+}
+
+// Simulation returns a ColorMatrix that transforms colors to how they look with the color
+// blindness.
+func (cb ColorBlindness) Simulation() ColorMatrix {
+	if !cb.valid() {
+		panic(errors.New("Simulation: invalid ColorBlindness"))
+	}
+	return simulations[cb]
+}
+
+// Daltonization returns a ColorMatrix that corrects colors for the color blindness, so that they
+// stay distinguishable with it. Grays are left nearly as they are.
+func (cb ColorBlindness) Daltonization() ColorMatrix {
+	if !cb.valid() {
+		panic(errors.New("Daltonization: invalid ColorBlindness"))
+	}
+	// c + shift * (c - sim * c)
+	sim, shift := simulations[cb], errorShifts[cb]
+	var lost ColorMatrix
+	for i := range lost {
+		lost[i] = IdentityColorMatrix[i] - sim[i]
+	}
+	corr := lost.Chained(shift)
+	for i := range corr {
+		corr[i] += IdentityColorMatrix[i]
+	}
+	return corr
+}
+
+func (cb ColorBlindness) valid() bool {
+	return cb >= 0 && int(cb) < len(simulations)
+}
@@ -0,0 +1,63 @@
+package pixel_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+func TestColorMatrix(t *testing.T) {
+	c := pixel.RGB(0.2, 0.5, 0.9).Scaled(0.5)
+	if got := pixel.IdentityColorMatrix.Apply(c); !eqColors(got, c) {
+		t.Errorf("identity Apply(%v) = %v", c, got)
+	}
+
+	swap := pixel.ColorMatrix{
+		0, 0, 1,
+		0, 1, 0,
+		1, 0, 0,
+	}
+	if got, want := swap.Apply(c), pixel.RGB(0.9, 0.5, 0.2).Scaled(0.5); !eqColors(got, want) {
+		t.Errorf("swap Apply(%v) = %v, want %v", c, got, want)
+	}
+	if got := swap.Chained(swap); got != pixel.IdentityColorMatrix {
+		t.Errorf("swap chained with swap = %v, want identity", got)
+	}
+
+	// clamped into the valid range
+	over := pixel.ColorMatrix{
+		2, 0, 0,
+		0, -1, 0,
+		0, 0, 1,
+	}
+	if got, want := over.Apply(pixel.RGB(1, 1, 1)), pixel.RGB(1, 0, 1); !eqColors(got, want) {
+		t.Errorf("Apply = %v, want %v", got, want)
+	}
+}
+
+func TestColorBlindness(t *testing.T) {
+	for _, cb := range []pixel.ColorBlindness{pixel.Protanopia, pixel.Deuteranopia, pixel.Tritanopia} {
+		for _, m := range []pixel.ColorMatrix{cb.Simulation(), cb.Daltonization()} {
+			for _, c := range []pixel.RGBA{pixel.RGB(0, 0, 0), pixel.RGB(1, 1, 1), pixel.RGB(0.5, 0.5, 0.5)} {
+				got := m.Apply(c)
+				if math.Abs(got.R-c.R) > 1e-3 || math.Abs(got.G-c.G) > 1e-3 || math.Abs(got.B-c.B) > 1e-3 {
+					t.Errorf("%v: gray %v transformed to %v", cb, c, got)
+				}
+			}
+		}
+	}
+
+	// red and green are confused with deuteranopia, less so after the correction
+	dist := func(m pixel.ColorMatrix, a, b pixel.RGBA) float64 {
+		a, b = m.Apply(a), m.Apply(b)
+		return math.Abs(a.R-b.R) + math.Abs(a.G-b.G) + math.Abs(a.B-b.B)
+	}
+	red, green := pixel.RGB(0.8, 0.2, 0.1), pixel.RGB(0.5, 0.5, 0.1)
+	sim := pixel.Deuteranopia.Simulation()
+	before := dist(sim, red, green)
+	after := dist(pixel.Deuteranopia.Daltonization().Chained(sim), red, green)
+	if after <= 2*before {
+		t.Errorf("distance of simulated red and green is %v, %v after daltonization", before, after)
+	}
+}
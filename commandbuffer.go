@@ -0,0 +1,207 @@
+package pixel
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// CommandBuffer is a Target which records the draws onto it, the geometry together with the
+// Matrix, the color mask and the composition method it was drawn with, and replays them onto any
+// other Target, as many times as needed.
+//
+// A complex static scene is recorded once and then re-rendered cheaply, e.g. onto several Canvases
+// or in every frame:
+//
+//   cb := pixel.NewCommandBuffer()
+//   background.Draw(cb, pixel.IM)
+//   for _, tile := range level {
+//       tile.Draw(cb)
+//   }
+//
+//   for !win.Closed() {
+//       cb.Draw(win)
+//       minimap.Clear(colornames.Black)
+//       cb.DrawMatrix(minimap, pixel.IM.Scaled(pixel.ZV, 0.1))
+//       ...
+//   }
+//
+// The geometry of each draw is copied when it's recorded, unless it wasn't updated since the
+// previous draw of the same Triangles, so the recorded draws don't change with the drawn objects.
+// The geometry is kept in the Targets it's replayed onto in the form most efficient for drawing,
+// like by a frozen Drawer (see Drawer.Freeze).
+type CommandBuffer struct {
+	mat Matrix
+	col RGBA
+	cmp ComposeMethod
+
+	commands []drawCommand
+}
+
+var _ ComposeTarget = (*CommandBuffer)(nil)
+
+// drawCommand is a recorded draw, the Drawer is shared by the following draws of the same geometry
+// with the same Picture.
+type drawCommand struct {
+	drawer *Drawer
+	mat    Matrix
+	col    RGBA
+	cmp    ComposeMethod
+}
+
+// NewCommandBuffer creates a new empty CommandBuffer.
+func NewCommandBuffer() *CommandBuffer {
+	return &CommandBuffer{mat: IM, col: Alpha(1)}
+}
+
+// SetMatrix sets a Matrix that the following recorded draws are projected by.
+func (cb *CommandBuffer) SetMatrix(m Matrix) {
+	cb.mat = m
+}
+
+// SetColorMask sets a mask color the following recorded draws are multiplied by.
+func (cb *CommandBuffer) SetColorMask(c color.Color) {
+	if c == nil {
+		cb.col = Alpha(1)
+		return
+	}
+	cb.col = ToRGBA(c)
+}
+
+// SetComposeMethod sets a Porter-Duff composition method of the following recorded draws. It's
+// only replayed onto ComposeTargets.
+func (cb *CommandBuffer) SetComposeMethod(cmp ComposeMethod) {
+	cb.cmp = cmp
+}
+
+// Clear removes all the recorded draws.
+func (cb *CommandBuffer) Clear() {
+	cb.commands = nil
+}
+
+// Len returns the number of the recorded draws.
+func (cb *CommandBuffer) Len() int {
+	return len(cb.commands)
+}
+
+// Draw replays the recorded draws onto the Target, with their Matrices, color masks and
+// composition methods, if the Target is a BasicTarget and a ComposeTarget. The Target keeps the
+// state of the last draw.
+func (cb *CommandBuffer) Draw(t Target) {
+	cb.draw(t, IM, false)
+}
+
+// DrawMatrix replays the recorded draws like Draw, but with the Matrix applied after the
+// Matrices of the draws, e.g. to show the recording scaled down or moved.
+func (cb *CommandBuffer) DrawMatrix(t BasicTarget, m Matrix) {
+	cb.draw(t, m, true)
+}
+
+func (cb *CommandBuffer) draw(t Target, m Matrix, chain bool) {
+	bt, basic := t.(BasicTarget)
+	ct, compose := t.(ComposeTarget)
+	for _, c := range cb.commands {
+		if basic {
+			if chain {
+				bt.SetMatrix(c.mat.Chained(m))
+			} else {
+				bt.SetMatrix(c.mat)
+			}
+			bt.SetColorMask(c.col)
+		}
+		if compose {
+			ct.SetComposeMethod(c.cmp)
+		}
+		c.drawer.Draw(t)
+	}
+}
+
+// record records a draw of the triangles with the Picture, which may be nil
+func (cb *CommandBuffer) record(ct *commandTriangles, pic Picture) {
+	if ct.snapshot == nil || ct.snapshot.Picture != pic || ct.snapshotGen != *ct.gen {
+		d := &Drawer{Triangles: ct.tri.Copy(), Picture: pic}
+		d.Freeze()
+		ct.snapshot = d
+		ct.snapshotGen = *ct.gen
+	}
+	cb.commands = append(cb.commands, drawCommand{
+		drawer: ct.snapshot,
+		mat:    cb.mat,
+		col:    cb.col,
+		cmp:    cb.cmp,
+	})
+}
+
+// MakeTriangles returns a specialized copy of the provided Triangles that records its draws into
+// this CommandBuffer.
+func (cb *CommandBuffer) MakeTriangles(t Triangles) TargetTriangles {
+	return &commandTriangles{tri: t.Copy(), dst: cb, gen: new(int)}
+}
+
+// MakePicture returns a specialized copy of the provided Picture that records its draws into this
+// CommandBuffer.
+func (cb *CommandBuffer) MakePicture(p Picture) TargetPicture {
+	return &commandPicture{pic: p, dst: cb}
+}
+
+type commandTriangles struct {
+	tri Triangles
+	dst *CommandBuffer
+
+	// the Drawer of the copy of tri recorded last, and the generation of the geometry it was
+	// copied at. The generation is shared with the Slices and the triangles sliced from, which
+	// change together, and each change increments it.
+	snapshot    *Drawer
+	snapshotGen int
+	gen         *int
+}
+
+// changed makes the snapshots of the triangles, their Slices and the ones they're sliced from
+// outdated
+func (ct *commandTriangles) changed() {
+	*ct.gen++
+}
+
+func (ct *commandTriangles) Len() int {
+	return ct.tri.Len()
+}
+
+func (ct *commandTriangles) SetLen(len int) {
+	if len != ct.tri.Len() {
+		ct.tri.SetLen(len)
+		ct.changed()
+	}
+}
+
+func (ct *commandTriangles) Slice(i, j int) Triangles {
+	return &commandTriangles{tri: ct.tri.Slice(i, j), dst: ct.dst, gen: ct.gen}
+}
+
+func (ct *commandTriangles) Update(t Triangles) {
+	ct.tri.Update(t)
+	ct.changed()
+}
+
+func (ct *commandTriangles) Copy() Triangles {
+	return &commandTriangles{tri: ct.tri.Copy(), dst: ct.dst, gen: new(int)}
+}
+
+func (ct *commandTriangles) Draw() {
+	ct.dst.record(ct, nil)
+}
+
+type commandPicture struct {
+	pic Picture
+	dst *CommandBuffer
+}
+
+func (cp *commandPicture) Bounds() Rect {
+	return cp.pic.Bounds()
+}
+
+func (cp *commandPicture) Draw(t TargetTriangles) {
+	ct := t.(*commandTriangles)
+	if cp.dst != ct.dst {
+		panic(fmt.Errorf("(%T).Draw: TargetTriangles generated by different CommandBuffer", cp))
+	}
+	cp.dst.record(ct, cp.pic)
+}
@@ -0,0 +1,95 @@
+package pixel_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandBuffer(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 1))
+	pic.Pix[0] = color.RGBA{R: 255, A: 255}
+	pic.Pix[1] = color.RGBA{B: 255, A: 255}
+	red := pixel.NewSprite(pic, pixel.R(0, 0, 1, 1))
+
+	cb := pixel.NewCommandBuffer()
+	red.Draw(cb, pixel.IM.Moved(pixel.V(0.5, 0.5)))
+	red.DrawColorMask(cb, pixel.IM.Moved(pixel.V(2.5, 0.5)), pixel.RGB(0, 1, 0))
+	// the sprite changes its frame after it's recorded
+	red.Set(pic, pixel.R(1, 0, 2, 1))
+	red.Draw(cb, pixel.IM.Moved(pixel.V(3.5, 0.5)))
+	assert.Equal(t, 3, cb.Len())
+
+	want := []pixel.RGBA{pixel.RGB(1, 0, 0), pixel.Alpha(0), pixel.RGB(0, 0, 0), pixel.RGB(0, 0, 1)}
+	for i := 0; i < 2; i++ {
+		// replayed onto several Targets
+		c := raster.NewCanvas(pixel.R(0, 0, 4, 1))
+		cb.Draw(c)
+		for x, w := range want {
+			assert.Equal(t, w, c.Color(pixel.V(float64(x), 0)), "pixel %d", x)
+		}
+	}
+
+	// replaying draws the geometry as it was recorded
+	red.Set(pic, pixel.R(0, 0, 1, 1))
+	c := raster.NewCanvas(pixel.R(0, 0, 4, 1))
+	cb.Draw(c)
+	assert.Equal(t, pixel.RGB(0, 0, 1), c.Color(pixel.V(3, 0)))
+
+	c = raster.NewCanvas(pixel.R(0, 0, 4, 1))
+	cb.DrawMatrix(c, pixel.IM.Moved(pixel.V(1, 0)))
+	assert.Equal(t, pixel.Alpha(0), c.Color(pixel.V(0, 0)))
+	assert.Equal(t, pixel.RGB(1, 0, 0), c.Color(pixel.V(1, 0)))
+
+	cb.Clear()
+	assert.Equal(t, 0, cb.Len())
+}
+
+func TestCommandBuffer_state(t *testing.T) {
+	tri := pixel.MakeTrianglesData(3)
+	(*tri)[0].Position = pixel.V(0, 0)
+	(*tri)[1].Position = pixel.V(4, 0)
+	(*tri)[2].Position = pixel.V(0, 4)
+
+	cb := pixel.NewCommandBuffer()
+	tt := cb.MakeTriangles(tri)
+	cb.SetColorMask(pixel.RGB(1, 0, 0))
+	tt.Draw()
+	cb.SetMatrix(pixel.IM.Moved(pixel.V(-4, 0)))
+	cb.SetColorMask(nil)
+	cb.SetComposeMethod(pixel.ComposeCopy)
+	tt.Draw()
+
+	// the second draw reuses the recorded geometry and replaces the first one where they overlap
+	c := raster.NewCanvas(pixel.R(-4, 0, 4, 4))
+	cb.Draw(c)
+	assert.Equal(t, pixel.RGB(1, 0, 0), c.Color(pixel.V(1, 0.5)))
+	assert.Equal(t, pixel.RGB(1, 1, 1), c.Color(pixel.V(-3, 0.5)))
+}
+
+func TestCommandBuffer_slice(t *testing.T) {
+	tri := pixel.MakeTrianglesData(3)
+	(*tri)[0].Position = pixel.V(0, 0)
+	(*tri)[1].Position = pixel.V(4, 0)
+	(*tri)[2].Position = pixel.V(0, 4)
+
+	cb := pixel.NewCommandBuffer()
+	tt := cb.MakeTriangles(tri)
+	slice := tt.Slice(0, 3).(pixel.TargetTriangles)
+	slice.Draw()
+
+	// the Slice changes with the triangles it's sliced from
+	for i := range *tri {
+		(*tri)[i].Position = (*tri)[i].Position.Sub(pixel.V(4, 0))
+	}
+	tt.Update(tri)
+	slice.Draw()
+
+	c := raster.NewCanvas(pixel.R(-4, 0, 4, 4))
+	cb.Draw(c)
+	assert.Equal(t, pixel.RGB(1, 1, 1), c.Color(pixel.V(1, 0.5)))
+	assert.Equal(t, pixel.RGB(1, 1, 1), c.Color(pixel.V(-3, 0.5)))
+}
@@ -27,11 +27,20 @@ const (
 	ComposeXor
 	ComposePlus
 	ComposeCopy
+
+	// ComposeMultiply is not a Porter-Duff method, it multiplies the colors of the foreground and
+	// the background, e.g. to darken a scene by a lightmap. A transparent foreground leaves the
+	// background as it is.
+	ComposeMultiply
 )
 
 // Compose composes two colors together according to the ComposeMethod. A is the foreground, B is
 // the background.
 func (cm ComposeMethod) Compose(a, b RGBA) RGBA {
+	if cm == ComposeMultiply {
+		return a.Mul(b).Add(b.Scaled(1 - a.A))
+	}
+
 	var fa, fb float64
 
 	switch cm {
@@ -0,0 +1,373 @@
+// Package console implements an in-game drop-down console for running the commands registered by
+// the game and tweaking its variables at runtime.
+//
+//   c := console.New()
+//   c.Register("spawn", "spawn <name> <count>", func(name string, count int) error {
+//       return world.Spawn(name, count)
+//   })
+//   c.Var("gravity", &world.Gravity)
+//   log.SetOutput(c)
+//
+//   for !win.Closed() {
+//       if !c.Update(win) {
+//           // the game input
+//       }
+//       ...
+//       c.Draw(win, win.Bounds())
+//       win.Update()
+//   }
+package console
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+)
+
+// Console is a drop-down console with the commands, the history of the entered lines and the log
+// of the output.
+type Console struct {
+	// Toggle is the key which opens and closes the Console.
+	Toggle pixelgl.Button
+
+	// Open is whether the Console is open, drawn and taking the input.
+	Open bool
+
+	// Height is the part of the height of the bounds covered by the Console.
+	Height float64
+
+	// MaxLines is the number of the lines of the log kept.
+	MaxLines int
+
+	Prompt     string
+	Atlas      *text.Atlas
+	Background color.Color
+	Foreground color.Color
+
+	commands map[string]*command
+	log      []string
+	partial  string
+	history  []string
+	histPos  int
+	line     []rune
+	cursor   int
+	scroll   int
+	bounds   pixel.Rect
+
+	txt *text.Text
+	imd *imdraw.IMDraw
+}
+
+type command struct {
+	help string
+	fn   reflect.Value
+}
+
+// New creates a new closed Console toggled by the grave accent key, with the help and the clear
+// commands.
+func New() *Console {
+	c := &Console{
+		Toggle:     pixelgl.KeyGraveAccent,
+		Height:     0.4,
+		MaxLines:   500,
+		Prompt:     "> ",
+		Atlas:      text.Atlas7x13,
+		Background: pixel.RGBA{R: 0, G: 0, B: 0, A: 0.8},
+		Foreground: pixel.RGB(0.9, 0.9, 0.9),
+		commands:   make(map[string]*command),
+	}
+	c.Register("help", "help [command]: lists the commands", func(args ...string) {
+		names := args
+		if len(names) == 0 {
+			names = c.Commands()
+		}
+		for _, name := range names {
+			if cmd, ok := c.commands[name]; ok {
+				c.Println(cmd.help)
+			} else {
+				c.Printf("unknown command %q\n", name)
+			}
+		}
+	})
+	c.Register("clear", "clear: clears the log", func() {
+		c.log, c.partial, c.scroll = nil, "", 0
+	})
+	return c
+}
+
+// Register adds the command of the name, replacing any previous one. The function fn is called
+// with the arguments of the command converted to the types of its parameters, which can be the
+// strings, the bools, the integers and the floats. The last parameter can be variadic. If fn
+// returns an error as its last result, it's the error of the command, the other results are
+// printed.
+func (c *Console) Register(name, help string, fn interface{}) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		panic(fmt.Errorf("(%T).Register: %T is not a function", c, fn))
+	}
+	for i := 0; i < v.Type().NumIn(); i++ {
+		t := v.Type().In(i)
+		if v.Type().IsVariadic() && i == v.Type().NumIn()-1 {
+			t = t.Elem()
+		}
+		if !supported(t) {
+			panic(fmt.Errorf("(%T).Register: unsupported parameter type %v", c, t))
+		}
+	}
+	c.commands[name] = &command{help: help, fn: v}
+}
+
+// Var adds the command of the name, which prints the variable the pointer points to, or sets it to
+// its argument. The variable can be a string, a bool, an integer or a float.
+func (c *Console) Var(name string, ptr interface{}) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || !supported(v.Type().Elem()) {
+		panic(fmt.Errorf("(%T).Var: %T is not a pointer to a supported type", c, ptr))
+	}
+	c.Register(name, fmt.Sprintf("%s [value]: prints or sets the %v", name, v.Type().Elem()), func(args ...string) error {
+		switch len(args) {
+		case 0:
+		case 1:
+			value, err := parse(args[0], v.Type().Elem())
+			if err != nil {
+				return err
+			}
+			v.Elem().Set(value)
+		default:
+			return errors.New("too many arguments")
+		}
+		c.Printf("%s = %v\n", name, v.Elem().Interface())
+		return nil
+	})
+}
+
+// Unregister removes the command of the name.
+func (c *Console) Unregister(name string) {
+	delete(c.commands, name)
+}
+
+// Commands returns the names of all the commands, sorted.
+func (c *Console) Commands() []string {
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Exec runs the command line and returns the error of the command. The arguments are separated by
+// the spaces, unless they're in the double quotes.
+func (c *Console) Exec(line string) error {
+	args, err := split(line)
+	if err != nil || len(args) == 0 {
+		return err
+	}
+	name, args := args[0], args[1:]
+	cmd, ok := c.commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	t := cmd.fn.Type()
+	n := t.NumIn()
+	if t.IsVariadic() {
+		if len(args) < n-1 {
+			return fmt.Errorf("%s: expected at least %d arguments, got %d", name, n-1, len(args))
+		}
+	} else if len(args) != n {
+		return fmt.Errorf("%s: expected %d arguments, got %d", name, n, len(args))
+	}
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		var pt reflect.Type
+		if t.IsVariadic() && i >= n-1 {
+			pt = t.In(n - 1).Elem()
+		} else {
+			pt = t.In(i)
+		}
+		if in[i], err = parse(arg, pt); err != nil {
+			return fmt.Errorf("%s: argument %d: %v", name, i+1, err)
+		}
+	}
+
+	out := cmd.fn.Call(in)
+	if n := len(out); n > 0 && t.Out(n-1) == errorType {
+		if err, _ := out[n-1].Interface().(error); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		out = out[:n-1]
+	}
+	for _, v := range out {
+		c.Println(v.Interface())
+	}
+	return nil
+}
+
+// Submit adds the command line to the history and the log, runs it and logs its error.
+func (c *Console) Submit(line string) {
+	c.Println(c.Prompt + line)
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if len(c.history) == 0 || c.history[len(c.history)-1] != line {
+		c.history = append(c.history, line)
+	}
+	c.histPos = len(c.history)
+	if err := c.Exec(line); err != nil {
+		c.Println("error:", err)
+	}
+}
+
+// History returns the entered lines, the last one last.
+func (c *Console) History() []string {
+	return c.history
+}
+
+// Complete returns the command line with the command name completed as far as it's unambiguous,
+// and the names of all the commands starting with the typed one.
+func (c *Console) Complete(line string) (completed string, candidates []string) {
+	trimmed := strings.TrimLeft(line, " ")
+	if strings.Contains(trimmed, " ") {
+		return line, nil
+	}
+	for _, name := range c.Commands() {
+		if strings.HasPrefix(name, trimmed) {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		return line, nil
+	}
+	prefix := candidates[0]
+	for _, name := range candidates[1:] {
+		for !strings.HasPrefix(name, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(candidates) == 1 {
+		prefix += " "
+	}
+	return prefix, candidates
+}
+
+// Write adds the text to the log, so the Console can be the output of a log.Logger. The last line
+// without a newline stays open for the next Write.
+func (c *Console) Write(p []byte) (n int, err error) {
+	lines := strings.Split(c.partial+string(p), "\n")
+	c.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		c.log = append(c.log, line)
+	}
+	if c.MaxLines > 0 && len(c.log) > c.MaxLines {
+		c.log = append(c.log[:0], c.log[len(c.log)-c.MaxLines:]...)
+	}
+	return len(p), nil
+}
+
+// Printf formats and adds the text to the log like fmt.Printf.
+func (c *Console) Printf(format string, a ...interface{}) {
+	fmt.Fprintf(c, format, a...)
+}
+
+// Println adds the operands to the log like fmt.Println.
+func (c *Console) Println(a ...interface{}) {
+	fmt.Fprintln(c, a...)
+}
+
+// Lines returns the lines of the log, including the last one without a newline.
+func (c *Console) Lines() []string {
+	if c.partial != "" {
+		return append(c.log[:len(c.log):len(c.log)], c.partial)
+	}
+	return c.log
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+func supported(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func parse(s string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, fmt.Errorf("invalid bool %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, t.Bits())
+		if err != nil {
+			return v, fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 0, t.Bits())
+		if err != nil {
+			return v, fmt.Errorf("invalid unsigned integer %q", s)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return v, fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	}
+	return v, nil
+}
+
+// split returns the arguments of the command line separated by the spaces, except in the double
+// quotes
+func split(line string) ([]string, error) {
+	var (
+		args   []string
+		arg    strings.Builder
+		inArg  bool
+		quoted bool
+	)
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+		case r == ' ' && !quoted:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
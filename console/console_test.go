@@ -0,0 +1,196 @@
+package console_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/console"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/raster"
+	"github.com/stretchr/testify/assert"
+)
+
+// keys is the Input of a single frame
+type keys struct {
+	pressed []pixelgl.Button
+	typed   string
+}
+
+func (k keys) JustPressed(button pixelgl.Button) bool {
+	for _, b := range k.pressed {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
+
+func (k keys) Repeated(button pixelgl.Button) bool { return k.JustPressed(button) }
+func (k keys) Typed() string                       { return k.typed }
+
+func press(buttons ...pixelgl.Button) keys { return keys{pressed: buttons} }
+func typed(s string) keys                  { return keys{typed: s} }
+
+func TestConsole_Exec(t *testing.T) {
+	c := console.New()
+	var (
+		name  string
+		count int
+		scale float64
+		words []string
+	)
+	c.Register("spawn", "spawn <name> <count>", func(n string, k int) {
+		name, count = n, k
+	})
+	c.Register("scale", "", func(s float64, rest ...string) (string, error) {
+		if s < 0 {
+			return "", errors.New("negative")
+		}
+		scale, words = s, rest
+		return "scaled", nil
+	})
+
+	assert.NoError(t, c.Exec(`spawn "big rat" 0x10`))
+	assert.Equal(t, "big rat", name)
+	assert.Equal(t, 16, count)
+
+	assert.NoError(t, c.Exec("scale 1.5"))
+	assert.Equal(t, 1.5, scale)
+	assert.Empty(t, words)
+	assert.NoError(t, c.Exec("  scale 2 a  b "))
+	assert.Equal(t, []string{"a", "b"}, words)
+	assert.Equal(t, []string{"scaled", "scaled"}, c.Lines())
+
+	assert.EqualError(t, c.Exec("scale -1"), "scale: negative")
+	assert.EqualError(t, c.Exec("spawn rat"), "spawn: expected 2 arguments, got 1")
+	assert.EqualError(t, c.Exec("spawn rat many"), `spawn: argument 2: invalid integer "many"`)
+	assert.EqualError(t, c.Exec("scale"), "scale: expected at least 1 arguments, got 0")
+	assert.EqualError(t, c.Exec("jump"), `unknown command "jump"`)
+	assert.Error(t, c.Exec(`spawn "rat 1`))
+	assert.NoError(t, c.Exec("   "))
+
+	assert.Panics(t, func() { c.Register("bad", "", 42) })
+	assert.Panics(t, func() { c.Register("bad", "", func(v pixel.Vec) {}) })
+}
+
+func TestConsole_Var(t *testing.T) {
+	c := console.New()
+	gravity, god := 9.8, false
+	c.Var("gravity", &gravity)
+	c.Var("god", &god)
+
+	assert.NoError(t, c.Exec("gravity 20"))
+	assert.Equal(t, 20.0, gravity)
+	assert.NoError(t, c.Exec("god true"))
+	assert.True(t, god)
+	assert.NoError(t, c.Exec("gravity"))
+	assert.Equal(t, []string{"gravity = 20", "god = true", "gravity = 20"}, c.Lines())
+	assert.Error(t, c.Exec("god maybe"))
+	assert.Error(t, c.Exec("god true false"))
+
+	assert.Panics(t, func() { c.Var("bad", gravity) })
+}
+
+func TestConsole_Complete(t *testing.T) {
+	c := console.New()
+	c.Register("spawn", "", func() {})
+	c.Register("speed", "", func() {})
+	c.Register("quit", "", func() {})
+
+	line, candidates := c.Complete("sp")
+	assert.Equal(t, "sp", line)
+	assert.Equal(t, []string{"spawn", "speed"}, candidates)
+	line, candidates = c.Complete("q")
+	assert.Equal(t, "quit ", line)
+	assert.Equal(t, []string{"quit"}, candidates)
+	line, candidates = c.Complete("x")
+	assert.Equal(t, "x", line)
+	assert.Empty(t, candidates)
+	line, _ = c.Complete("spawn s")
+	assert.Equal(t, "spawn s", line)
+	assert.Equal(t, []string{"clear", "help", "quit", "spawn", "speed"}, c.Commands())
+}
+
+func TestConsole_Write(t *testing.T) {
+	c := console.New()
+	c.MaxLines = 3
+	c.Printf("a\nb")
+	assert.Equal(t, []string{"a", "b"}, c.Lines())
+	c.Printf("c\nd\ne\n")
+	assert.Equal(t, []string{"bc", "d", "e"}, c.Lines())
+	assert.NoError(t, c.Exec("clear"))
+	assert.Empty(t, c.Lines())
+}
+
+func TestConsole_Update(t *testing.T) {
+	c := console.New()
+	var said []string
+	c.Register("say", "", func(words ...string) { said = append(said, words...) })
+
+	assert.False(t, c.Update(typed("x")), "closed")
+	assert.True(t, c.Update(keys{pressed: []pixelgl.Button{pixelgl.KeyGraveAccent}, typed: "`"}))
+	assert.True(t, c.Open)
+	assert.Equal(t, "", c.Line(), "the toggle key isn't typed")
+
+	c.Update(typed("sa"))
+	c.Update(press(pixelgl.KeyTab))
+	assert.Equal(t, "say ", c.Line())
+	c.Update(typed("hllo"))
+	for i := 0; i < 3; i++ {
+		c.Update(press(pixelgl.KeyLeft))
+	}
+	c.Update(typed("e"))
+	c.Update(press(pixelgl.KeyEnd))
+	c.Update(press(pixelgl.KeyBackspace))
+	c.Update(typed("o"))
+	assert.Equal(t, "say hello", c.Line())
+	c.Update(press(pixelgl.KeyEnter))
+	assert.Equal(t, []string{"hello"}, said)
+	assert.Equal(t, "", c.Line())
+	assert.Equal(t, []string{"> say hello"}, c.Lines())
+
+	c.Update(typed("nope"))
+	c.Update(press(pixelgl.KeyEnter))
+	assert.Equal(t, []string{"say hello", "nope"}, c.History())
+	assert.Equal(t, `error: unknown command "nope"`, c.Lines()[2])
+
+	c.Update(press(pixelgl.KeyUp))
+	assert.Equal(t, "nope", c.Line())
+	c.Update(press(pixelgl.KeyUp))
+	c.Update(press(pixelgl.KeyUp))
+	assert.Equal(t, "say hello", c.Line())
+	c.Update(press(pixelgl.KeyDown))
+	assert.Equal(t, "nope", c.Line())
+	c.Update(press(pixelgl.KeyDown))
+	assert.Equal(t, "", c.Line())
+
+	c.Update(press(pixelgl.KeyEscape))
+	assert.False(t, c.Open)
+}
+
+func TestConsole_Draw(t *testing.T) {
+	canvas := raster.NewCanvas(pixel.R(0, 0, 100, 100))
+	c := console.New()
+	c.Background = pixel.RGB(0, 0, 1)
+	c.Height = 0.5
+	c.Println("hello")
+
+	c.Draw(canvas, canvas.Bounds())
+	assert.Equal(t, pixel.Alpha(0), canvas.Color(pixel.V(90, 90)), "closed")
+
+	c.Open = true
+	c.Draw(canvas, canvas.Bounds())
+	assert.Equal(t, pixel.RGB(0, 0, 1), canvas.Color(pixel.V(90, 90)))
+	assert.Equal(t, pixel.Alpha(0), canvas.Color(pixel.V(90, 40)))
+
+	var drawn bool
+	for x := 0.0; x < 50; x++ {
+		for y := 50.0; y < 100; y++ {
+			if col := canvas.Color(pixel.V(x, y)); col.R > 0.5 {
+				drawn = true
+			}
+		}
+	}
+	assert.True(t, drawn, "the text")
+}
@@ -0,0 +1,177 @@
+package console
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+)
+
+// Input is the keyboard input of the Console, such as a *pixelgl.Window.
+type Input interface {
+	JustPressed(button pixelgl.Button) bool
+	Repeated(button pixelgl.Button) bool
+	Typed() string
+}
+
+// Update toggles the Console by the Toggle key and, when it's open, edits the command line by the
+// input and submits it by the Enter key. It returns whether the Console is open and took the
+// input, so the game should ignore it.
+//
+// The Tab key completes the command, the Up and Down keys walk the history, the Page Up and Page
+// Down keys scroll the log and the Escape key closes the Console.
+func (c *Console) Update(in Input) bool {
+	if in.JustPressed(c.Toggle) {
+		c.Open = !c.Open
+		return true
+	}
+	if !c.Open {
+		return false
+	}
+	if in.JustPressed(pixelgl.KeyEscape) {
+		c.Open = false
+		return true
+	}
+
+	for _, r := range in.Typed() {
+		c.line = append(c.line, 0)
+		copy(c.line[c.cursor+1:], c.line[c.cursor:])
+		c.line[c.cursor] = r
+		c.cursor++
+	}
+
+	switch {
+	case in.Repeated(pixelgl.KeyBackspace):
+		if c.cursor > 0 {
+			c.line = append(c.line[:c.cursor-1], c.line[c.cursor:]...)
+			c.cursor--
+		}
+	case in.Repeated(pixelgl.KeyDelete):
+		if c.cursor < len(c.line) {
+			c.line = append(c.line[:c.cursor], c.line[c.cursor+1:]...)
+		}
+	case in.Repeated(pixelgl.KeyLeft):
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case in.Repeated(pixelgl.KeyRight):
+		if c.cursor < len(c.line) {
+			c.cursor++
+		}
+	case in.JustPressed(pixelgl.KeyHome):
+		c.cursor = 0
+	case in.JustPressed(pixelgl.KeyEnd):
+		c.cursor = len(c.line)
+	case in.Repeated(pixelgl.KeyUp):
+		if c.histPos > 0 {
+			c.histPos--
+			c.setLine(c.history[c.histPos])
+		}
+	case in.Repeated(pixelgl.KeyDown):
+		if c.histPos < len(c.history) {
+			c.histPos++
+			if c.histPos == len(c.history) {
+				c.setLine("")
+			} else {
+				c.setLine(c.history[c.histPos])
+			}
+		}
+	case in.Repeated(pixelgl.KeyPageUp):
+		c.scroll = minInt(c.scroll+c.pageLines(), maxInt(len(c.Lines())-1, 0))
+	case in.Repeated(pixelgl.KeyPageDown):
+		c.scroll = maxInt(c.scroll-c.pageLines(), 0)
+	case in.JustPressed(pixelgl.KeyTab):
+		completed, candidates := c.Complete(string(c.line))
+		if len(candidates) > 1 && completed == string(c.line) {
+			c.Println(candidates)
+		}
+		c.setLine(completed)
+	case in.JustPressed(pixelgl.KeyEnter) || in.JustPressed(pixelgl.KeyKPEnter):
+		line := string(c.line)
+		c.setLine("")
+		c.scroll = 0
+		c.Submit(line)
+	}
+	return true
+}
+
+// Line returns the command line being edited.
+func (c *Console) Line() string {
+	return string(c.line)
+}
+
+func (c *Console) setLine(line string) {
+	c.line = []rune(line)
+	c.cursor = len(c.line)
+}
+
+// Draw draws the open Console over the top part of the bounds onto the Target: the log, and the
+// command line with the cursor below it.
+func (c *Console) Draw(t pixel.Target, bounds pixel.Rect) {
+	if !c.Open {
+		return
+	}
+	if c.txt == nil || c.txt.Atlas() != c.Atlas {
+		c.txt = text.New(pixel.ZV, c.Atlas)
+		c.imd = imdraw.New(nil)
+	}
+	c.bounds = bounds
+
+	const pad = 4
+	lineHeight := c.Atlas.LineHeight()
+	area := pixel.R(bounds.Min.X, bounds.Max.Y-bounds.H()*c.Height, bounds.Max.X, bounds.Max.Y)
+	inputDot := pixel.V(area.Min.X+pad, area.Min.Y+pad+c.Atlas.Descent())
+	separator := area.Min.Y + 2*pad + lineHeight
+
+	c.imd.Clear()
+	c.imd.Color = c.Background
+	c.imd.Push(area.Min, area.Max)
+	c.imd.Rectangle(0)
+	c.imd.Color = c.Foreground
+	c.imd.Push(pixel.V(area.Min.X, separator), pixel.V(area.Max.X, separator))
+	c.imd.Line(1)
+	cursorX := math.Round(inputDot.X + c.txt.BoundsOf(c.Prompt+string(c.line[:c.cursor])).W())
+	c.imd.Push(pixel.V(cursorX+0.5, area.Min.Y+pad), pixel.V(cursorX+0.5, area.Min.Y+pad+lineHeight))
+	c.imd.Line(1)
+	c.imd.Draw(t)
+
+	lines := c.Lines()
+	end := len(lines) - c.scroll
+	start := maxInt(end-c.pageLines(), 0)
+
+	c.txt.Clear()
+	c.txt.Color = c.Foreground
+	c.txt.Orig = pixel.V(area.Min.X+pad, separator+pad+c.Atlas.Descent()+float64(end-start-1)*lineHeight)
+	c.txt.Dot = c.txt.Orig
+	for _, line := range lines[start:end] {
+		c.txt.WriteString(line)
+		c.txt.WriteByte('\n')
+	}
+	c.txt.Dot = inputDot
+	c.txt.WriteString(c.Prompt)
+	c.txt.WriteString(string(c.line))
+	c.txt.Draw(t, pixel.IM)
+}
+
+// pageLines returns the number of the lines of the log fitting into the Console last drawn
+func (c *Console) pageLines() int {
+	const pad = 4
+	lineHeight := c.Atlas.LineHeight()
+	return maxInt(int((c.bounds.H()*c.Height-3*pad-lineHeight)/lineHeight), 1)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
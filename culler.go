@@ -0,0 +1,75 @@
+package pixel
+
+import (
+	"math"
+	"reflect"
+)
+
+// Culler tells which objects in the world are visible on a Target, so the ones off the screen
+// don't have to be drawn.
+//
+//   culler := pixel.NewCuller(cam.Matrix(), win.Bounds())
+//   n := culler.Cull(enemies, func(i int) pixel.Rect { return enemies[i].Bounds() })
+//   for _, e := range enemies[:n] {
+//       e.Draw(win)
+//   }
+type Culler struct {
+	view Rect
+}
+
+// NewCuller creates a new Culler for the bounds of a Target drawn with the view Matrix, which
+// transforms the world coordinates into the coordinates of the Target.
+func NewCuller(matrix Matrix, bounds Rect) Culler {
+	corners := [...]Vec{
+		bounds.Min,
+		V(bounds.Max.X, bounds.Min.Y),
+		bounds.Max,
+		V(bounds.Min.X, bounds.Max.Y),
+	}
+	view := Rect{
+		Min: V(math.Inf(1), math.Inf(1)),
+		Max: V(math.Inf(-1), math.Inf(-1)),
+	}
+	for _, corner := range corners {
+		u := matrix.Unproject(corner)
+		view.Min = V(math.Min(view.Min.X, u.X), math.Min(view.Min.Y, u.Y))
+		view.Max = V(math.Max(view.Max.X, u.X), math.Max(view.Max.Y, u.Y))
+	}
+	return Culler{view: view}
+}
+
+// View returns the smallest rectangle in the world containing everything visible on the Target.
+// It's larger than the visible area if the view Matrix rotates.
+func (c Culler) View() Rect {
+	return c.view
+}
+
+// Visible reports whether any part of the world rectangle r may be visible on the Target.
+func (c Culler) Visible(r Rect) bool {
+	return overlaps(r, c.view)
+}
+
+// Cull moves the visible elements of the slice to its beginning and returns their number. The
+// bounds function returns the world rectangle of the i-th element. The visible elements keep their
+// order, the order of the rest is undefined.
+//
+// Cull panics if the provided interface is not a slice.
+func (c Culler) Cull(slice interface{}, bounds func(i int) Rect) int {
+	swap := reflect.Swapper(slice)
+	n := reflect.ValueOf(slice).Len()
+	visible := 0
+	for i := 0; i < n; i++ {
+		if c.Visible(bounds(i)) {
+			swap(visible, i)
+			visible++
+		}
+	}
+	return visible
+}
+
+// overlaps reports whether the rectangles a and b overlap or touch.
+func overlaps(a, b Rect) bool {
+	a, b = a.Norm(), b.Norm()
+	return a.Min.X <= b.Max.X && b.Min.X <= a.Max.X &&
+		a.Min.Y <= b.Max.Y && b.Min.Y <= a.Max.Y
+}
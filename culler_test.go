@@ -0,0 +1,30 @@
+package pixel_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCuller(t *testing.T) {
+	// the world is zoomed twice, the Target shows the world rectangle (0, 0, 50, 25)
+	c := pixel.NewCuller(pixel.IM.Scaled(pixel.ZV, 2), pixel.R(0, 0, 100, 50))
+	assert.Equal(t, pixel.R(0, 0, 50, 25), c.View())
+
+	assert.True(t, c.Visible(pixel.R(10, 10, 20, 20)))
+	assert.True(t, c.Visible(pixel.R(-10, -10, 1, 1)))
+	assert.True(t, c.Visible(pixel.R(50, 25, 60, 30)))
+	assert.False(t, c.Visible(pixel.R(51, 0, 60, 10)))
+	assert.False(t, c.Visible(pixel.R(0, -10, 10, -1)))
+
+	rects := []pixel.Rect{
+		pixel.R(100, 100, 110, 110),
+		pixel.R(0, 0, 10, 10),
+		pixel.R(-20, 0, -10, 10),
+		pixel.R(40, 20, 60, 30),
+	}
+	n := c.Cull(rects, func(i int) pixel.Rect { return rects[i] })
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []pixel.Rect{pixel.R(0, 0, 10, 10), pixel.R(40, 20, 60, 30)}, rects[:n])
+}
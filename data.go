@@ -146,42 +146,34 @@ func MakePictureData(rect Rect) *PictureData {
 	return pd
 }
 
-func verticalFlip(rgba *image.RGBA) {
-	bounds := rgba.Bounds()
-	width := bounds.Dx()
-
-	tmpRow := make([]uint8, width*4)
-	for i, j := 0, bounds.Dy()-1; i < j; i, j = i+1, j-1 {
-		iRow := rgba.Pix[i*rgba.Stride : i*rgba.Stride+width*4]
-		jRow := rgba.Pix[j*rgba.Stride : j*rgba.Stride+width*4]
-
-		copy(tmpRow, iRow)
-		copy(iRow, jRow)
-		copy(jRow, tmpRow)
-	}
-}
-
 // PictureDataFromImage converts an image.Image into PictureData.
 //
 // The resulting PictureData's Bounds will be the equivalent of the supplied image.Image's Bounds.
+//
+// An *image.RGBA is copied row by row, the other images are converted by image/draw first.
 func PictureDataFromImage(img image.Image) *PictureData {
-	rgba := image.NewRGBA(img.Bounds())
-	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
-
-	verticalFlip(rgba)
+	rgba, ok := img.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(img.Bounds())
+		draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	}
 
+	bounds := rgba.Bounds()
 	pd := MakePictureData(R(
-		float64(rgba.Bounds().Min.X),
-		float64(rgba.Bounds().Min.Y),
-		float64(rgba.Bounds().Max.X),
-		float64(rgba.Bounds().Max.Y),
+		float64(bounds.Min.X),
+		float64(bounds.Min.Y),
+		float64(bounds.Max.X),
+		float64(bounds.Max.Y),
 	))
 
-	for i := range pd.Pix {
-		pd.Pix[i].R = rgba.Pix[i*4+0]
-		pd.Pix[i].G = rgba.Pix[i*4+1]
-		pd.Pix[i].B = rgba.Pix[i*4+2]
-		pd.Pix[i].A = rgba.Pix[i*4+3]
+	w, h := bounds.Dx(), bounds.Dy()
+	for y := 0; y < h; y++ {
+		// the first row of the image is the top one, the last one of the PictureData
+		src := rgba.Pix[y*rgba.Stride : y*rgba.Stride+w*4]
+		dst := pd.Pix[(h-1-y)*pd.Stride : (h-1-y)*pd.Stride+w]
+		for x := range dst {
+			dst[x] = color.RGBA{R: src[x*4+0], G: src[x*4+1], B: src[x*4+2], A: src[x*4+3]}
+		}
 	}
 
 	return pd
@@ -225,31 +217,34 @@ func PictureDataFromPicture(pic Picture) *PictureData {
 //
 // The resulting image.RGBA's Bounds will be equivalent of the PictureData's Bounds.
 func (pd *PictureData) Image() *image.RGBA {
-	bounds := image.Rect(
-		int(math.Floor(pd.Rect.Min.X)),
-		int(math.Floor(pd.Rect.Min.Y)),
-		int(math.Ceil(pd.Rect.Max.X)),
-		int(math.Ceil(pd.Rect.Max.Y)),
-	)
+	bounds := pd.imageBounds()
 	rgba := image.NewRGBA(bounds)
 
-	i := 0
-	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			off := pd.Index(V(float64(x), float64(y)))
-			rgba.Pix[i*4+0] = pd.Pix[off].R
-			rgba.Pix[i*4+1] = pd.Pix[off].G
-			rgba.Pix[i*4+2] = pd.Pix[off].B
-			rgba.Pix[i*4+3] = pd.Pix[off].A
-			i++
+	w, h := bounds.Dx(), bounds.Dy()
+	for y := 0; y < h; y++ {
+		src := pd.Pix[(h-1-y)*pd.Stride : (h-1-y)*pd.Stride+w]
+		dst := rgba.Pix[y*rgba.Stride : y*rgba.Stride+w*4]
+		for x, c := range src {
+			dst[x*4+0] = c.R
+			dst[x*4+1] = c.G
+			dst[x*4+2] = c.B
+			dst[x*4+3] = c.A
 		}
 	}
 
-	verticalFlip(rgba)
-
 	return rgba
 }
 
+// imageBounds returns the bounds of the PictureData rounded out to the whole pixels
+func (pd *PictureData) imageBounds() image.Rectangle {
+	return image.Rect(
+		int(math.Floor(pd.Rect.Min.X)),
+		int(math.Floor(pd.Rect.Min.Y)),
+		int(math.Ceil(pd.Rect.Max.X)),
+		int(math.Ceil(pd.Rect.Max.Y)),
+	)
+}
+
 // Index returns the index of the pixel at the specified position inside the Pix slice.
 func (pd *PictureData) Index(at Vec) int {
 	at = at.Sub(pd.Rect.Min.Map(math.Floor))
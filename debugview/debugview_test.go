@@ -0,0 +1,171 @@
+package debugview_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/debugview"
+	"github.com/stretchr/testify/assert"
+)
+
+// dial connects to the WebSocket of the server, with the key of the example of RFC 6455
+func dial(t *testing.T, addr string) (net.Conn, *bufio.Reader) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(conn, "GET /ws HTTP/1.1\r\n"+
+		"Host: "+addr+"\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+	return conn, r
+}
+
+// readFrame reads an unmasked frame sent by the server
+func readFrame(t *testing.T, r *bufio.Reader) (op byte, data []byte) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, byte(0x80), header[0]&0x80, "final")
+	assert.Equal(t, byte(0), header[1]&0x80, "unmasked")
+	n := uint64(header[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(r, ext[:])
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	data = make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		t.Fatal(err)
+	}
+	return header[0] & 0x0f, data
+}
+
+// writeFrame writes a masked frame, as a browser does
+func writeFrame(w io.Writer, op byte, data []byte) {
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | op, 0x80 | byte(len(data))}
+	frame = append(frame, mask[:]...)
+	for i, b := range data {
+		frame = append(frame, b^mask[i%4])
+	}
+	w.Write(frame)
+}
+
+func waitClients(t *testing.T, s *debugview.Server, n int) {
+	for i := 0; i < 200 && s.Clients() != n; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, n, s.Clients())
+}
+
+func TestServer(t *testing.T) {
+	s := debugview.NewServer()
+	hs := httptest.NewServer(s)
+	defer hs.Close()
+
+	resp, err := http.Get(hs.URL)
+	assert.NoError(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Contains(t, string(body), "WebSocket")
+
+	resp, err = http.Get(hs.URL + "/ws")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "not a WebSocket")
+
+	assert.False(t, s.Update(1), "no clients")
+
+	addr := strings.TrimPrefix(hs.URL, "http://")
+	conn, r := dial(t, addr)
+	defer conn.Close()
+	waitClients(t, s, 1)
+
+	s.AddBox("gone", pixel.R(0, 0, 1, 1), nil)
+	assert.False(t, s.Update(0.05), "before the frame")
+	assert.True(t, s.Update(0.05))
+
+	s.SetStat("entities", 12)
+	s.SetStat("draws", 3)
+	s.SetStat("gone", 1)
+	s.SetStat("gone", nil)
+	s.AddBox("player", pixel.R(10, 20, 30, 60), color.RGBA{R: 255, A: 255})
+	img := image.NewRGBA(image.Rect(0, 0, 80, 60))
+	s.SendFrame(img, pixel.R(0, 0, 80, 60))
+
+	op, data := readFrame(t, r)
+	assert.Equal(t, byte(0x1), op, "text")
+	var info struct {
+		Bounds [4]float64
+		Stats  [][2]string
+		Boxes  []struct {
+			Label  string
+			Bounds [4]float64
+			Color  string
+		}
+	}
+	assert.NoError(t, json.Unmarshal(data, &info))
+	assert.Equal(t, [4]float64{0, 0, 80, 60}, info.Bounds)
+	// the FPS is averaged over the updates of 1, 0.05 and 0.05 seconds
+	assert.Equal(t, [][2]string{{"FPS", "7.8"}, {"draws", "3"}, {"entities", "12"}}, info.Stats)
+	if assert.Len(t, info.Boxes, 1) {
+		assert.Equal(t, "player", info.Boxes[0].Label)
+		assert.Equal(t, [4]float64{10, 20, 30, 60}, info.Boxes[0].Bounds)
+		assert.Equal(t, "#ff0000", info.Boxes[0].Color)
+	}
+
+	op, data = readFrame(t, r)
+	assert.Equal(t, byte(0x2), op, "binary")
+	frame, err := jpeg.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 40, 30), frame.Bounds(), "scaled")
+
+	// the pings are answered
+	writeFrame(conn, 0x9, []byte("hi"))
+	op, data = readFrame(t, r)
+	assert.Equal(t, byte(0xa), op)
+	assert.Equal(t, "hi", string(data))
+
+	writeFrame(conn, 0x8, nil)
+	op, _ = readFrame(t, r)
+	assert.Equal(t, byte(0x8), op, "close")
+	waitClients(t, s, 0)
+
+	conn2, r2 := dial(t, addr)
+	defer conn2.Close()
+	waitClients(t, s, 1)
+	s.Close()
+	op, _ = readFrame(t, r2)
+	assert.Equal(t, byte(0x8), op, "closed by the server")
+	waitClients(t, s, 0)
+}
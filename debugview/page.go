@@ -0,0 +1,84 @@
+package debugview
+
+// page is the debug page, which draws the frames received from the WebSocket with the boxes over
+// them and lists the stats
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Pixel debug view</title>
+<style>
+body { margin: 0; background: #1e1e1e; color: #ddd; font: 13px monospace; display: flex; }
+#view { flex: 1; display: flex; align-items: center; justify-content: center; height: 100vh; }
+canvas { max-width: 100%; max-height: 100vh; image-rendering: pixelated; }
+#side { width: 240px; padding: 8px; background: #2a2a2a; overflow: auto; height: 100vh; box-sizing: border-box; }
+#status { color: #888; margin-bottom: 8px; }
+table { width: 100%; border-collapse: collapse; }
+td { padding: 1px 4px; }
+td:last-child { text-align: right; }
+label { display: block; margin-top: 8px; }
+</style>
+</head>
+<body>
+<div id="view"><canvas id="frame"></canvas></div>
+<div id="side">
+<div id="status">connecting</div>
+<table id="stats"></table>
+<label><input type="checkbox" id="boxes" checked> boxes</label>
+<label><input type="checkbox" id="labels" checked> labels</label>
+</div>
+<script>
+const canvas = document.getElementById("frame");
+const ctx = canvas.getContext("2d");
+const status = document.getElementById("status");
+let info = null;
+
+function show(info, img) {
+	canvas.width = img.width;
+	canvas.height = img.height;
+	ctx.drawImage(img, 0, 0);
+	const [x0, y0, x1, y1] = info.bounds;
+	const sx = img.width / (x1 - x0), sy = img.height / (y1 - y0);
+	if (document.getElementById("boxes").checked) {
+		ctx.lineWidth = 1;
+		ctx.font = "11px monospace";
+		for (const box of info.boxes) {
+			const [bx0, by0, bx1, by1] = box.bounds;
+			const x = (bx0 - x0) * sx, y = (y1 - by1) * sy;
+			ctx.strokeStyle = ctx.fillStyle = box.color;
+			ctx.strokeRect(Math.round(x) + 0.5, Math.round(y) + 0.5, (bx1 - bx0) * sx, (by1 - by0) * sy);
+			if (box.label && document.getElementById("labels").checked) {
+				ctx.fillText(box.label, x + 2, y - 3);
+			}
+		}
+	}
+	const rows = info.stats.map(([k, v]) => "<tr><td>" + escape(k) + "</td><td>" + escape(v) + "</td></tr>");
+	document.getElementById("stats").innerHTML = rows.join("");
+}
+
+function escape(s) {
+	return s.replace(/[&<>"]/g, c => ({"&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;"})[c]);
+}
+
+function connect() {
+	const ws = new WebSocket((location.protocol === "https:" ? "wss://" : "ws://") + location.host + "/ws");
+	ws.binaryType = "blob";
+	ws.onopen = () => status.textContent = "connected";
+	ws.onclose = () => {
+		status.textContent = "disconnected, reconnecting";
+		setTimeout(connect, 1000);
+	};
+	ws.onmessage = e => {
+		if (typeof e.data === "string") {
+			info = JSON.parse(e.data);
+			return;
+		}
+		const frameInfo = info;
+		createImageBitmap(e.data).then(img => show(frameInfo, img));
+	};
+}
+connect();
+</script>
+</body>
+</html>
+`
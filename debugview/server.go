@@ -0,0 +1,278 @@
+// Package debugview serves a web page showing the running game, for inspecting the headless or
+// embedded targets remotely from a browser.
+//
+// A Server streams the downscaled frames over a WebSocket, together with the stats of the
+// rendering and the overlays of the bounds of the entities:
+//
+//   debug := debugview.NewServer()
+//   go debug.ListenAndServe(":8080")
+//   for !win.Closed() {
+//       ...
+//       debug.SetStat("entities", len(entities))
+//       for _, e := range entities {
+//           debug.AddBox(e.Name, e.Bounds(), colornames.Lime)
+//       }
+//       win.Update()
+//       debug.Record(win, dt)
+//   }
+//
+// Open http://localhost:8080 in a browser to watch it. The frames are only captured while a browser
+// is connected.
+package debugview
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	xdraw "golang.org/x/image/draw"
+)
+
+// Server serves the debug page and streams the frames to the connected browsers. It's an
+// http.Handler, so it can be mounted on an existing HTTP server too.
+//
+// The methods of a Server are safe for concurrent use.
+type Server struct {
+	// FPS is the largest number of the frames sent per second, 10 by default.
+	FPS float64
+
+	// Scale is the scale of the sent frames to the Window, 0.5 by default.
+	Scale float64
+
+	// Quality is the JPEG quality of the sent frames, from 1 to 100, 70 by default.
+	Quality int
+
+	mu      sync.Mutex
+	clients map[*wsConn]struct{}
+	timer   float64
+	fps     float64
+	stats   map[string]string
+	boxes   []Box
+}
+
+// Box is an overlay of the bounds of an entity, drawn over the frame in the browser.
+type Box struct {
+	Label  string
+	Bounds pixel.Rect
+	Color  color.Color
+}
+
+// NewServer creates a new Server with the default options.
+func NewServer() *Server {
+	return &Server{
+		FPS:     10,
+		Scale:   0.5,
+		Quality: 70,
+		clients: make(map[*wsConn]struct{}),
+		stats:   make(map[string]string),
+	}
+}
+
+// ListenAndServe serves the debug page on the TCP network address, e.g. ":8080".
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s)
+}
+
+// ServeHTTP serves the debug page on the path "/" and the stream of the frames on "/ws".
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	case "/ws":
+		c, err := upgrade(w, r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.clients[c] = struct{}{}
+		s.mu.Unlock()
+		go func() {
+			<-c.Done()
+			s.mu.Lock()
+			delete(s.clients, c)
+			s.mu.Unlock()
+		}()
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Clients returns the number of the connected browsers.
+func (s *Server) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// SetStat sets the stat of the name shown next to the frames, such as the number of the draw calls.
+// A nil value removes the stat.
+func (s *Server) SetStat(name string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value == nil {
+		delete(s.stats, name)
+		return
+	}
+	s.stats[name] = fmt.Sprint(value)
+}
+
+// AddBox adds the overlay of the bounds, in the coordinates of the frame, labeled by the label. The
+// boxes are sent with the next frame, or removed by Update if no frame is due, so they should be
+// added every frame before Record.
+func (s *Server) AddBox(label string, bounds pixel.Rect, c color.Color) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.boxes = append(s.boxes, Box{Label: label, Bounds: bounds, Color: c})
+}
+
+// Update advances the time by dt, measuring the FPS of the game, and reports whether the next frame
+// is due. No frames are due if no browser is connected. The boxes are removed if no frame is due.
+func (s *Server) Update(dt float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if dt > 0 {
+		// the exponential moving average of the frame rate
+		if s.fps == 0 {
+			s.fps = 1 / dt
+		} else {
+			s.fps += (1/dt - s.fps) * math.Min(1, dt*4)
+		}
+	}
+	if len(s.clients) == 0 {
+		s.timer, s.boxes = 0, nil
+		return false
+	}
+	s.timer += dt
+	if s.timer < 1/s.FPS {
+		s.boxes = nil
+		return false
+	}
+	s.timer = math.Mod(s.timer, 1/s.FPS)
+	return true
+}
+
+// Record takes a screenshot of the Window without waiting for it and sends it, if the next frame is
+// due after dt. It should be called once per frame, after the Window is updated.
+func (s *Server) Record(win *pixelgl.Window, dt float64) {
+	if !s.Update(dt) {
+		return
+	}
+	bounds := win.Bounds()
+	boxes := s.takeBoxes()
+	win.ScreenshotAsync(func(img *image.RGBA) {
+		s.send(img, bounds, boxes)
+	})
+}
+
+// SendFrame sends the image of the frame of the bounds to the connected browsers, with the stats
+// and the boxes added since the last frame. Unlike Record, it ignores the time.
+func (s *Server) SendFrame(img image.Image, bounds pixel.Rect) {
+	s.send(img, bounds, s.takeBoxes())
+}
+
+// Close disconnects all the browsers.
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		c.Close()
+	}
+}
+
+func (s *Server) takeBoxes() []Box {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	boxes := s.boxes
+	s.boxes = nil
+	return boxes
+}
+
+// frameInfo is the JSON message sent before each frame
+type frameInfo struct {
+	Bounds [4]float64  `json:"bounds"`
+	Stats  [][2]string `json:"stats"`
+	Boxes  []boxInfo   `json:"boxes"`
+}
+
+type boxInfo struct {
+	Label  string     `json:"label"`
+	Bounds [4]float64 `json:"bounds"`
+	Color  string     `json:"color"`
+}
+
+func rect(r pixel.Rect) [4]float64 {
+	return [4]float64{r.Min.X, r.Min.Y, r.Max.X, r.Max.Y}
+}
+
+func (s *Server) send(img image.Image, bounds pixel.Rect, boxes []Box) {
+	s.mu.Lock()
+	scale, quality := s.Scale, s.Quality
+	info := frameInfo{Bounds: rect(bounds), Stats: [][2]string{}, Boxes: []boxInfo{}}
+	if s.fps > 0 {
+		info.Stats = append(info.Stats, [2]string{"FPS", fmt.Sprintf("%.1f", s.fps)})
+	}
+	names := make([]string, 0, len(s.stats))
+	for name := range s.stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		info.Stats = append(info.Stats, [2]string{name, s.stats[name]})
+	}
+	clients := make([]*wsConn, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+	if len(clients) == 0 {
+		return
+	}
+
+	for _, b := range boxes {
+		var c color.Color = color.White
+		if b.Color != nil {
+			c = b.Color
+		}
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		info.Boxes = append(info.Boxes, boxInfo{
+			Label:  b.Label,
+			Bounds: rect(b.Bounds),
+			Color:  fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B),
+		})
+	}
+	msg, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+	var frame bytes.Buffer
+	if err := jpeg.Encode(&frame, scaled(img, scale), &jpeg.Options{Quality: quality}); err != nil {
+		return
+	}
+
+	for _, c := range clients {
+		c.queue(wsMessage{opText, msg}, wsMessage{opBinary, frame.Bytes()})
+	}
+}
+
+// scaled returns the image scaled by the scale
+func scaled(img image.Image, scale float64) image.Image {
+	if scale <= 0 || scale == 1 {
+		return img
+	}
+	b := img.Bounds()
+	w := int(math.Max(1, math.Round(float64(b.Dx())*scale)))
+	h := int(math.Max(1, math.Round(float64(b.Dy())*scale)))
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.ApproxBiLinear.Scale(dst, dst.Rect, img, b, xdraw.Src, nil)
+	return dst
+}
@@ -0,0 +1,246 @@
+package debugview
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// the opcodes of the WebSocket frames
+const (
+	opText   = 0x1
+	opBinary = 0x2
+	opClose  = 0x8
+	opPing   = 0x9
+	opPong   = 0xa
+)
+
+// wsGUID is the key suffix of the WebSocket handshake, RFC 6455
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxClientFrame is the largest frame accepted from a client, which only sends control frames
+const maxClientFrame = 1 << 16
+
+type wsMessage struct {
+	op   byte
+	data []byte
+}
+
+// wsConn is the server side of a WebSocket connection. The messages are written by a goroutine
+// from a short queue, so a slow client skips the messages instead of slowing down the game.
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	send chan []wsMessage
+
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+// upgrade performs the WebSocket handshake of the request and takes over its connection
+func upgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "expected a WebSocket connection", http.StatusBadRequest)
+		return nil, errors.New("not a WebSocket request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket version", http.StatusBadRequest)
+		return nil, errors.New("unsupported WebSocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("connection can't be hijacked")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + wsGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c := &wsConn{
+		conn: conn,
+		rw:   rw,
+		send: make(chan []wsMessage, 2),
+		done: make(chan struct{}),
+	}
+	go c.writeLoop()
+	go c.readLoop()
+	return c, nil
+}
+
+func headerContains(h http.Header, name, value string) bool {
+	for _, v := range h[http.CanonicalHeaderKey(name)] {
+		for _, s := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// queue queues the messages to be sent together, and reports false if the queue is full
+func (c *wsConn) queue(msgs ...wsMessage) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return false
+	}
+	select {
+	case c.send <- msgs:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the connection, sending the close frame first
+func (c *wsConn) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.send)
+}
+
+// Done returns a channel closed when the connection is closed
+func (c *wsConn) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *wsConn) writeLoop() {
+	defer close(c.done)
+	defer c.conn.Close()
+	for msgs := range c.send {
+		for _, m := range msgs {
+			if err := writeFrame(c.rw.Writer, m.op, m.data); err != nil {
+				c.Close()
+				c.drain()
+				return
+			}
+		}
+		if err := c.rw.Flush(); err != nil {
+			c.Close()
+			c.drain()
+			return
+		}
+	}
+	writeFrame(c.rw.Writer, opClose, nil)
+	c.rw.Flush()
+}
+
+func (c *wsConn) drain() {
+	for range c.send {
+	}
+}
+
+// readLoop answers the pings and the close frame of the client, the other messages are ignored
+func (c *wsConn) readLoop() {
+	for {
+		op, data, err := readFrame(c.rw.Reader)
+		if err != nil {
+			c.Close()
+			return
+		}
+		switch op {
+		case opClose:
+			c.Close()
+			return
+		case opPing:
+			c.queue(wsMessage{opPong, data})
+		}
+	}
+}
+
+// writeFrame writes an unmasked, unfragmented frame, as sent by a server
+func writeFrame(w io.Writer, op byte, data []byte) error {
+	header := []byte{0x80 | op, 0}
+	switch n := len(data); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xffff:
+		header[1] = 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header[1] = 127
+		header = append(header, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// readFrame reads a frame, unmasking it if it's masked, as sent by a client
+func readFrame(r io.Reader) (op byte, data []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	op = header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+	n := uint64(header[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxClientFrame {
+		return 0, nil, fmt.Errorf("frame of %d bytes too large", n)
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	data = make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range data {
+			data[i] ^= mask[i%4]
+		}
+	}
+	return op, data, nil
+}
@@ -0,0 +1,186 @@
+package pixel
+
+import (
+	"image/color"
+	"math"
+)
+
+// Dither is an ordered dithering, which reduces the colors to a few levels of each component, or
+// to the colors of a palette, and hides the steps between them with a regular pattern of pixels
+// (the Bayer matrix). It emulates the output of old hardware, such as the 16 color palettes or the
+// four greens of the Game Boy.
+//
+// Dither transforms single colors and PictureData, a pixelgl.Canvas applies it as a post-process
+// with pixelgl.DitherFragmentShader.
+//
+//   gameBoy := pixel.Dither{Palette: []color.Color{
+//       color.RGBA{0x0f, 0x38, 0x0f, 0xff},
+//       color.RGBA{0x30, 0x62, 0x30, 0xff},
+//       color.RGBA{0x8b, 0xac, 0x0f, 0xff},
+//       color.RGBA{0x9b, 0xbc, 0x0f, 0xff},
+//   }}
+type Dither struct {
+	// Size is the size of the Bayer matrix, 2, 4 or 8. Larger matrices make more shades between
+	// the colors, smaller ones a coarser pattern. Zero means 4.
+	Size int
+
+	// Levels is the number of levels of each of the red, green and blue components, if there's no
+	// Palette. Zero means 2, which makes 8 colors.
+	Levels int
+
+	// Palette are the colors of the output. If it's not empty, the Levels are ignored and each
+	// pixel takes the nearest color of the Palette after adding the pattern. The alpha of the
+	// Palette colors is ignored.
+	Palette []color.Color
+}
+
+// Matrix returns the Bayer matrix of the Dither with Size*Size thresholds within range (0, 1), in
+// rows. A Size other than 2, 4 or 8 is rounded up to the next one.
+func (d Dither) Matrix() []float64 {
+	n := d.size()
+	m := []int{0}
+	for k := 1; k < n; k *= 2 {
+		// each cell of the smaller matrix splits into four
+		next := make([]int, 4*k*k)
+		for y := 0; y < k; y++ {
+			for x := 0; x < k; x++ {
+				v := 4 * m[y*k+x]
+				next[2*y*2*k+2*x] = v
+				next[2*y*2*k+2*x+1] = v + 2
+				next[(2*y+1)*2*k+2*x] = v + 3
+				next[(2*y+1)*2*k+2*x+1] = v + 1
+			}
+		}
+		m = next
+	}
+	thresholds := make([]float64, len(m))
+	for i, v := range m {
+		thresholds[i] = (float64(v) + 0.5) / float64(len(m))
+	}
+	return thresholds
+}
+
+// Spread returns how far the Bayer matrix moves the colors before the nearest color of the Palette
+// is taken, the average distance between the nearest colors of the Palette. It's zero without a
+// Palette.
+func (d Dither) Spread() float64 {
+	palette := d.palette()
+	if len(palette) < 2 {
+		return 0
+	}
+	sum := 0.0
+	for i, p := range palette {
+		nearest := math.Inf(1)
+		for j, q := range palette {
+			if i != j {
+				nearest = math.Min(nearest, dist(p, q))
+			}
+		}
+		sum += math.Sqrt(nearest)
+	}
+	return sum / float64(len(palette))
+}
+
+// At returns the dithered color of the pixel at the position x, y. The alpha is kept.
+func (d Dither) At(x, y int, c RGBA) RGBA {
+	return d.ditherer().dither(x, y, c)
+}
+
+// Apply dithers all pixels of the PictureData in place. The pattern starts at the bottom-left
+// pixel of its Rect.
+func (d Dither) Apply(pd *PictureData) {
+	dt := d.ditherer()
+	w := int(math.Round(pd.Rect.W()))
+	h := int(math.Round(pd.Rect.H()))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*pd.Stride + x
+			c := ToRGBA(pd.Pix[i])
+			pd.Pix[i] = color.RGBAModel.Convert(dt.dither(x, y, c)).(color.RGBA)
+		}
+	}
+}
+
+func (d Dither) size() int {
+	switch {
+	case d.Size <= 0:
+		return 4
+	case d.Size <= 2:
+		return 2
+	case d.Size <= 4:
+		return 4
+	}
+	return 8
+}
+
+// palette returns the opaque colors of the Palette.
+func (d Dither) palette() []RGBA {
+	palette := make([]RGBA, len(d.Palette))
+	for i, pc := range d.Palette {
+		p := ToRGBA(pc)
+		if p.A != 0 {
+			p = p.Scaled(1 / p.A)
+		}
+		palette[i] = p
+	}
+	return palette
+}
+
+// ditherer is a Dither prepared for dithering many colors.
+type ditherer struct {
+	size    int
+	matrix  []float64
+	levels  float64
+	palette []RGBA
+	spread  float64
+}
+
+func (d Dither) ditherer() *ditherer {
+	return &ditherer{
+		size:    d.size(),
+		matrix:  d.Matrix(),
+		levels:  math.Max(float64(d.Levels-1), 1),
+		palette: d.palette(),
+		spread:  d.Spread(),
+	}
+}
+
+// dither returns the dithered color of the pixel at the position x, y.
+func (dt *ditherer) dither(x, y int, c RGBA) RGBA {
+	if c.A == 0 {
+		return c
+	}
+	threshold := dt.matrix[mod(y, dt.size)*dt.size+mod(x, dt.size)]
+	a := c.A
+	c = RGB(c.R/a, c.G/a, c.B/a)
+
+	if len(dt.palette) == 0 {
+		q := func(v float64) float64 {
+			return math.Min(math.Floor(v*dt.levels+threshold), dt.levels) / dt.levels
+		}
+		return RGB(q(c.R), q(c.G), q(c.B)).Scaled(a)
+	}
+
+	off := (threshold - 0.5) * dt.spread
+	c = c.Add(RGBA{off, off, off, 0})
+	best, bestDist := RGBA{}, math.Inf(1)
+	for _, p := range dt.palette {
+		if d := dist(p, c); d < bestDist {
+			best, bestDist = p, d
+		}
+	}
+	return RGB(best.R, best.G, best.B).Scaled(a)
+}
+
+// dist returns the squared distance between the colors, ignoring their alpha.
+func dist(c, d RGBA) float64 {
+	return (c.R-d.R)*(c.R-d.R) + (c.G-d.G)*(c.G-d.G) + (c.B-d.B)*(c.B-d.B)
+}
+
+func mod(a, n int) int {
+	a %= n
+	if a < 0 {
+		a += n
+	}
+	return a
+}
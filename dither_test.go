@@ -0,0 +1,84 @@
+package pixel_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+func TestDitherMatrix(t *testing.T) {
+	got := pixel.Dither{Size: 2}.Matrix()
+	want := []float64{0.5 / 4, 2.5 / 4, 3.5 / 4, 1.5 / 4}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Matrix() = %v, want %v", got, want)
+		}
+	}
+
+	for _, size := range []int{0, 3, 4, 8, 100} {
+		m := pixel.Dither{Size: size}.Matrix()
+		seen := make(map[float64]bool)
+		for _, v := range m {
+			if v <= 0 || v >= 1 || seen[v] {
+				t.Errorf("Size %d: invalid matrix %v", size, m)
+				break
+			}
+			seen[v] = true
+		}
+		if n := len(m); n != 16 && n != 64 {
+			t.Errorf("Size %d: matrix with %d thresholds", size, n)
+		}
+	}
+}
+
+func TestDitherLevels(t *testing.T) {
+	d := pixel.Dither{Size: 4}
+
+	// a half gray makes a pattern of a half black and a half white pixels
+	white := 0
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			c := d.At(x, y, pixel.RGB(0.5, 0.5, 0.5))
+			switch c {
+			case pixel.RGB(1, 1, 1):
+				white++
+			case pixel.RGB(0, 0, 0):
+			default:
+				t.Fatalf("At(%d, %d) = %v, want black or white", x, y, c)
+			}
+		}
+	}
+	if white != 8 {
+		t.Errorf("%d of 16 pixels white, want 8", white)
+	}
+
+	// the pattern repeats and the levels stay
+	d.Levels = 3
+	if got, want := d.At(-1, 5, pixel.RGB(0.5, 1, 0)), d.At(3, 1, pixel.RGB(0.5, 1, 0)); got != want {
+		t.Errorf("At(-1, 5) = %v, want %v", got, want)
+	}
+	if got := d.At(2, 2, pixel.RGB(0.5, 1, 0).Scaled(0.5)); got != pixel.RGB(0.5, 1, 0).Scaled(0.5) {
+		t.Errorf("At(2, 2) = %v, want the exact level", got)
+	}
+}
+
+func TestDitherPalette(t *testing.T) {
+	black, gray, white := color.RGBA{A: 255}, color.RGBA{R: 128, G: 128, B: 128, A: 255}, color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	d := pixel.Dither{Palette: []color.Color{black, gray, white}}
+
+	pd := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	for i := range pd.Pix {
+		pd.Pix[i] = color.RGBA{R: 64, G: 64, B: 64, A: 255}
+	}
+	pd.Pix[5] = color.RGBA{}
+	d.Apply(pd)
+
+	counts := make(map[color.RGBA]int)
+	for _, c := range pd.Pix {
+		counts[c]++
+	}
+	if counts[black] == 0 || counts[gray] == 0 || counts[white] != 0 || counts[color.RGBA{}] != 1 {
+		t.Errorf("dithered a dark gray to %v", counts)
+	}
+}
@@ -20,12 +20,24 @@ package pixel
 // it's set to. What it means is that using a Drawer with an unbounded number of Pictures leads to a
 // memory leak, since Drawer caches them and never forgets. In such a situation, create a new Drawer
 // for each Picture.
+//
+// Geometry that never changes, such as the tiles of a level collected in a Batch, can be frozen by
+// Freeze, so that Targets keep it in the form most efficient for drawing.
 type Drawer struct {
 	Triangles Triangles
 	Picture   Picture
 
 	targets map[Target]*drawerTarget
 	inited  bool
+	frozen  bool
+}
+
+// StaticTriangles are TargetTriangles which can be told that their content won't change, see
+// Drawer.Freeze. Static TargetTriangles are drawn straight from the data they already hold, e.g.
+// from the GPU memory of pixelgl, without any copying per draw.
+type StaticTriangles interface {
+	TargetTriangles
+	SetStatic(static bool)
 }
 
 type drawerTarget struct {
@@ -43,14 +55,57 @@ func (d *Drawer) lazyInit() {
 
 // Dirty marks the Triangles of this Drawer as changed. If not called, changes will not be visible
 // when drawing.
+//
+// Dirty unfreezes a frozen Drawer, see Freeze.
 func (d *Drawer) Dirty() {
 	d.lazyInit()
 
+	if d.frozen {
+		d.setStatic(false)
+		d.frozen = false
+	}
 	for _, t := range d.targets {
 		t.clean = false
 	}
 }
 
+// Freeze tells the Drawer that its Triangles won't change anymore, until the next call to Dirty.
+//
+// The Triangles are transformed and copied into each Target once, on the first draw onto it, and
+// the following draws only draw them, which makes drawing static geometry nearly free:
+//
+//   batch := pixel.NewBatch(&pixel.TrianglesData{}, tiles)
+//   for _, tile := range level {
+//       tile.Draw(batch)
+//   }
+//   batch.Freeze()
+//   ...
+//   batch.Draw(win) // every frame
+//
+// Targets whose TargetTriangles implement StaticTriangles are told that the Triangles are static.
+func (d *Drawer) Freeze() {
+	d.lazyInit()
+
+	if d.frozen {
+		return
+	}
+	d.frozen = true
+	d.setStatic(true)
+}
+
+// Frozen returns whether the Drawer is frozen, see Freeze.
+func (d *Drawer) Frozen() bool {
+	return d.frozen
+}
+
+func (d *Drawer) setStatic(static bool) {
+	for _, t := range d.targets {
+		if st, ok := t.tris.(StaticTriangles); ok {
+			st.SetStatic(static)
+		}
+	}
+}
+
 // Draw efficiently draws Triangles with Picture onto the provided Target.
 //
 // If Triangles is nil, nothing will be drawn. If Picture is nil, Triangles will be drawn without a
@@ -73,6 +128,9 @@ func (d *Drawer) Draw(t Target) {
 	if dt.tris == nil {
 		dt.tris = t.MakeTriangles(d.Triangles)
 		dt.clean = true
+		if st, ok := dt.tris.(StaticTriangles); ok && d.frozen {
+			st.SetStatic(true)
+		}
 	}
 
 	if !dt.clean {
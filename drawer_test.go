@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
 )
 
 func BenchmarkSpriteDrawBatch(b *testing.B) {
@@ -16,3 +17,85 @@ func BenchmarkSpriteDrawBatch(b *testing.B) {
 		sprite.Draw(batch, pixel.IM)
 	}
 }
+
+// staticTarget is a Target which records the updates and the static flag of its triangles.
+type staticTarget struct {
+	updates int
+	tris    []*staticTriangles
+}
+
+func (st *staticTarget) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	tris := &staticTriangles{TrianglesData: pixel.MakeTrianglesData(t.Len()), dst: st}
+	tris.TrianglesData.Update(t)
+	st.tris = append(st.tris, tris)
+	return tris
+}
+
+func (st *staticTarget) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	panic("unused")
+}
+
+type staticTriangles struct {
+	*pixel.TrianglesData
+	dst    *staticTarget
+	static bool
+	draws  int
+}
+
+func (st *staticTriangles) Update(t pixel.Triangles) {
+	st.TrianglesData.Update(t)
+	st.dst.updates++
+}
+
+func (st *staticTriangles) SetStatic(static bool) { st.static = static }
+func (st *staticTriangles) Draw()                 { st.draws++ }
+
+func TestDrawer_Freeze(t *testing.T) {
+	tris := pixel.MakeTrianglesData(3)
+	d := pixel.Drawer{Triangles: tris}
+	a, b := &staticTarget{}, &staticTarget{}
+
+	d.Draw(a)
+	d.Freeze()
+	assert.True(t, d.Frozen())
+	assert.True(t, a.tris[0].static)
+
+	// the Targets made after freezing get static triangles too
+	d.Draw(b)
+	assert.True(t, b.tris[0].static)
+
+	for i := 0; i < 3; i++ {
+		d.Draw(a)
+	}
+	assert.Equal(t, 0, a.updates)
+	assert.Equal(t, 4, a.tris[0].draws)
+
+	// Dirty unfreezes the Drawer, the triangles are updated once
+	(*tris)[0].Position = pixel.V(1, 2)
+	d.Dirty()
+	assert.False(t, d.Frozen())
+	assert.False(t, a.tris[0].static)
+	d.Draw(a)
+	d.Draw(a)
+	assert.Equal(t, 1, a.updates)
+	assert.Equal(t, pixel.V(1, 2), a.tris[0].Position(0))
+}
+
+func TestBatch_Freeze(t *testing.T) {
+	batch := pixel.NewBatch(&pixel.TrianglesData{}, nil)
+	tris := pixel.MakeTrianglesData(3)
+	batch.MakeTriangles(tris).Draw()
+
+	target := &staticTarget{}
+	batch.Freeze()
+	batch.Draw(target)
+	batch.Draw(target)
+	assert.True(t, target.tris[0].static)
+	assert.Equal(t, 0, target.updates)
+
+	// drawing onto the Batch unfreezes it
+	batch.MakeTriangles(tris).Draw()
+	batch.Draw(target)
+	assert.False(t, target.tris[0].static)
+	assert.Equal(t, 6, target.tris[0].Len())
+}
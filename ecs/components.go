@@ -0,0 +1,85 @@
+package ecs
+
+import (
+	"image/color"
+
+	"github.com/faiface/pixel"
+)
+
+// Transform is the position, the rotation and the scale of an Entity in the world.
+type Transform struct {
+	Pos   pixel.Vec
+	Angle float64
+
+	// Scale is the scale along each axis, a zero Scale means no scaling.
+	Scale pixel.Vec
+}
+
+// Matrix returns the Matrix transforming the Entity from its own coordinates into the world.
+func (t *Transform) Matrix() pixel.Matrix {
+	scale := t.Scale
+	if scale == pixel.ZV {
+		scale = pixel.V(1, 1)
+	}
+	return pixel.IM.ScaledXY(pixel.ZV, scale).Rotated(pixel.ZV, t.Angle).Moved(t.Pos)
+}
+
+// Sprite draws a pixel.Sprite at the Transform of its Entity.
+type Sprite struct {
+	Sprite *pixel.Sprite
+
+	// Layer is the layer of the Sprite, the higher layers are drawn over the lower ones.
+	Layer int
+
+	// Mask multiplies the colors of the Sprite, nil means no effect.
+	Mask color.Color
+}
+
+// Animation changes the frame of the Sprite of its Entity over time.
+type Animation struct {
+	// Picture is the Picture of the Frames, nil means the Picture of the Sprite.
+	Picture pixel.Picture
+
+	Frames []pixel.Rect
+
+	// FrameTime is the time in seconds each frame is shown for.
+	FrameTime float64
+
+	// Loop repeats the Animation, otherwise it stops on its last frame.
+	Loop bool
+
+	time float64
+}
+
+// Frame returns the index of the current frame.
+func (a *Animation) Frame() int {
+	if len(a.Frames) == 0 || a.FrameTime <= 0 {
+		return 0
+	}
+	i := int(a.time / a.FrameTime)
+	if a.Loop {
+		return i % len(a.Frames)
+	}
+	if i >= len(a.Frames) {
+		return len(a.Frames) - 1
+	}
+	return i
+}
+
+// Done reports whether an Animation which doesn't loop has reached its end.
+func (a *Animation) Done() bool {
+	return !a.Loop && a.time >= a.FrameTime*float64(len(a.Frames))
+}
+
+// Reset rewinds the Animation to the first frame.
+func (a *Animation) Reset() {
+	a.time = 0
+}
+
+// Camera makes a pixel.Camera follow its Entity.
+type Camera struct {
+	Camera *pixel.Camera
+
+	// Offset is the position of the Camera relative to the Entity.
+	Offset pixel.Vec
+}
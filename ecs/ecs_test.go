@@ -0,0 +1,139 @@
+package ecs_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/ecs"
+	"github.com/faiface/pixel/raster"
+	"github.com/stretchr/testify/assert"
+)
+
+type health struct {
+	points int
+}
+
+func TestWorld_Components(t *testing.T) {
+	w := ecs.NewWorld()
+	a, b, c := w.NewEntity(), w.NewEntity(), w.NewEntity()
+	assert.Equal(t, 3, w.Len())
+
+	w.Add(a, &ecs.Transform{}, &health{10})
+	w.Add(b, &ecs.Transform{})
+	w.Add(c, &health{5}, &ecs.Transform{})
+
+	assert.Equal(t, []ecs.Entity{a, b, c}, w.With((*ecs.Transform)(nil)))
+	assert.Equal(t, []ecs.Entity{a, c}, w.With((*health)(nil), (*ecs.Transform)(nil)))
+	assert.True(t, w.Has(a, (*health)(nil)))
+	assert.False(t, w.Has(b, (*health)(nil)))
+
+	var h *health
+	assert.True(t, w.Get(c, &h))
+	h.points--
+	w.Get(c, &h)
+	assert.Equal(t, 4, h.points)
+	assert.False(t, w.Get(b, &h))
+
+	w.Delete(a, (*health)(nil))
+	assert.Equal(t, []ecs.Entity{c}, w.With((*health)(nil)))
+
+	w.Remove(c)
+	assert.False(t, w.Alive(c))
+	assert.Equal(t, 2, w.Len())
+	assert.Equal(t, []ecs.Entity{a, b}, w.With((*ecs.Transform)(nil)))
+
+	assert.Panics(t, func() { w.Add(c, &health{}) })
+	assert.Panics(t, func() { w.Add(a, health{}) })
+}
+
+func TestWorld_Systems(t *testing.T) {
+	w := ecs.NewWorld()
+	var order []string
+	w.AddSystem(ecs.SystemFunc(func(w *ecs.World, dt float64) { order = append(order, "first") }))
+	w.AddSystem(ecs.SystemFunc(func(w *ecs.World, dt float64) { order = append(order, "second") }))
+	w.Update(1)
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestTransform_Matrix(t *testing.T) {
+	tr := ecs.Transform{Pos: pixel.V(10, 0), Angle: math.Pi / 2}
+	assert.InDelta(t, 10, tr.Matrix().Project(pixel.V(1, 0)).X, 1e-9)
+	assert.InDelta(t, 1, tr.Matrix().Project(pixel.V(1, 0)).Y, 1e-9)
+
+	tr = ecs.Transform{Scale: pixel.V(2, 3)}
+	assert.Equal(t, pixel.V(2, 3), tr.Matrix().Project(pixel.V(1, 1)))
+}
+
+func TestAnimationSystem(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 3, 1))
+	frames := []pixel.Rect{pixel.R(0, 0, 1, 1), pixel.R(1, 0, 2, 1), pixel.R(2, 0, 3, 1)}
+
+	w := ecs.NewWorld()
+	w.AddSystem(ecs.AnimationSystem{})
+	loop, once := w.NewEntity(), w.NewEntity()
+	loopSprite := &ecs.Sprite{Sprite: pixel.NewSprite(pic, frames[0])}
+	onceSprite := &ecs.Sprite{Sprite: pixel.NewSprite(pic, frames[0])}
+	loopAnim := &ecs.Animation{Frames: frames, FrameTime: 1, Loop: true}
+	onceAnim := &ecs.Animation{Frames: frames, FrameTime: 1}
+	w.Add(loop, loopSprite, loopAnim)
+	w.Add(once, onceSprite, onceAnim)
+
+	w.Update(1.5)
+	assert.Equal(t, frames[1], loopSprite.Sprite.Frame())
+	assert.Equal(t, frames[1], onceSprite.Sprite.Frame())
+
+	w.Update(2)
+	assert.Equal(t, 0, loopAnim.Frame())
+	assert.Equal(t, frames[0], loopSprite.Sprite.Frame())
+	assert.Equal(t, frames[2], onceSprite.Sprite.Frame())
+	assert.False(t, loopAnim.Done())
+	assert.True(t, onceAnim.Done())
+
+	onceAnim.Reset()
+	w.Update(0)
+	assert.Equal(t, frames[0], onceSprite.Sprite.Frame())
+}
+
+func TestCameraSystem(t *testing.T) {
+	cam := pixel.NewCamera(pixel.R(0, 0, 10, 10))
+	w := ecs.NewWorld()
+	w.AddSystem(ecs.CameraSystem{})
+	e := w.NewEntity()
+	w.Add(e, &ecs.Transform{Pos: pixel.V(5, 7)}, &ecs.Camera{Camera: cam, Offset: pixel.V(0, 1)})
+	w.Update(1)
+	assert.Equal(t, pixel.V(5, 8), cam.Pos)
+}
+
+func TestWorld_Draw(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 1))
+	pic.Pix[0] = color.RGBA{R: 255, A: 255}
+	pic.Pix[1] = color.RGBA{B: 255, A: 255}
+
+	w := ecs.NewWorld()
+	sprite := func(x float64, frame pixel.Rect, layer int) {
+		e := w.NewEntity()
+		w.Add(e,
+			&ecs.Transform{Pos: pixel.V(x, 0.5)},
+			&ecs.Sprite{Sprite: pixel.NewSprite(pic, frame), Layer: layer},
+		)
+	}
+	red, blue := pixel.R(0, 0, 1, 1), pixel.R(1, 0, 2, 1)
+	sprite(1.5, red, 1)
+	sprite(1.5, blue, 0) // under the red one
+	sprite(2.5, blue, 0)
+	sprite(100, red, 0) // culled
+	w.NewEntity()       // without a Sprite
+
+	cam := pixel.NewCamera(pixel.R(0, 0, 4, 1))
+	cam.Pos = pixel.V(2, 0.5)
+	c := raster.NewCanvas(cam.Screen)
+	c.SetMatrix(cam.Matrix())
+	assert.Equal(t, 3, w.Draw(c, cam))
+
+	want := []pixel.RGBA{pixel.Alpha(0), pixel.RGB(1, 0, 0), pixel.RGB(0, 0, 1), pixel.Alpha(0)}
+	for x, wc := range want {
+		assert.Equal(t, wc, c.Color(pixel.V(float64(x), 0)), "pixel %d", x)
+	}
+}
@@ -0,0 +1,82 @@
+package ecs
+
+import "github.com/faiface/pixel"
+
+// AnimationSystem advances the Animations and sets the frames of the Sprites of their Entities.
+type AnimationSystem struct{}
+
+// Update updates the Entities with an Animation and a Sprite.
+func (AnimationSystem) Update(w *World, dt float64) {
+	for _, e := range w.With((*Animation)(nil), (*Sprite)(nil)) {
+		var (
+			a *Animation
+			s *Sprite
+		)
+		w.Get(e, &a)
+		w.Get(e, &s)
+		if len(a.Frames) == 0 || s.Sprite == nil {
+			continue
+		}
+		a.time += dt
+		if a.Loop && a.FrameTime > 0 {
+			// keep the time in a single loop for the precision
+			total := a.FrameTime * float64(len(a.Frames))
+			for a.time >= total {
+				a.time -= total
+			}
+		}
+		pic := a.Picture
+		if pic == nil {
+			pic = s.Sprite.Picture()
+		}
+		frame := a.Frames[a.Frame()]
+		if pic != s.Sprite.Picture() || frame != s.Sprite.Frame() {
+			s.Sprite.Set(pic, frame)
+		}
+	}
+}
+
+// CameraSystem moves the Cameras to their Entities.
+type CameraSystem struct{}
+
+// Update updates the Entities with a Camera and a Transform.
+func (CameraSystem) Update(w *World, dt float64) {
+	for _, e := range w.With((*Camera)(nil), (*Transform)(nil)) {
+		var (
+			c *Camera
+			t *Transform
+		)
+		w.Get(e, &c)
+		w.Get(e, &t)
+		if c.Camera != nil {
+			c.Camera.Pos = t.Pos.Add(c.Offset)
+		}
+	}
+}
+
+// Draw draws the Sprites of the Entities with a Transform visible by the Camera onto the Target,
+// by their layers. The Sprites are culled and batched by a pixel.Renderer. The Target's Matrix
+// must be the Camera's Matrix. It returns the number of the drawn Sprites.
+func (w *World) Draw(t pixel.Target, cam *pixel.Camera) int {
+	entities := w.With((*Sprite)(nil), (*Transform)(nil))
+	if cap(w.sprites) < len(entities) {
+		w.sprites = make([]pixel.SpriteRenderable, len(entities))
+	}
+	w.sprites = w.sprites[:len(entities)]
+
+	w.renderer.Clear()
+	for i, e := range entities {
+		var (
+			s  *Sprite
+			tr *Transform
+		)
+		w.Get(e, &s)
+		w.Get(e, &tr)
+		if s.Sprite == nil {
+			continue
+		}
+		w.sprites[i] = pixel.SpriteRenderable{Sprite: s.Sprite, Matrix: tr.Matrix(), Mask: s.Mask}
+		w.renderer.Add(s.Layer, &w.sprites[i])
+	}
+	return w.renderer.Draw(t, cam)
+}
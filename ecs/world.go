@@ -0,0 +1,190 @@
+// Package ecs implements a small entity-component-system for the games built on Pixel, with the
+// components and the systems for the transforms, the sprites, their animations and the cameras.
+//
+// An entity is just an ID, its data are the components: any pointers, stored by their types. The
+// systems update the entities with certain components every frame.
+//
+//   w := ecs.NewWorld()
+//   w.AddSystem(ecs.AnimationSystem{})
+//   w.AddSystem(ecs.CameraSystem{})
+//
+//   player := w.NewEntity()
+//   w.Add(player,
+//       &ecs.Transform{Pos: pixel.V(100, 50)},
+//       &ecs.Sprite{Sprite: pixel.NewSprite(sheet, frames[0]), Layer: 1},
+//       &ecs.Animation{Frames: frames, FrameTime: 0.1, Loop: true},
+//       &ecs.Camera{Camera: cam},
+//       &Health{100}, // a component of the game
+//   )
+//
+//   for !win.Closed() {
+//       w.Update(dt)
+//       win.SetMatrix(cam.Matrix())
+//       w.Draw(win, cam)
+//       win.Update()
+//   }
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/faiface/pixel"
+)
+
+// Entity is an object of a World, identified by a number. The Entity 0 is never used.
+type Entity uint32
+
+// System updates the Entities of a World every frame.
+type System interface {
+	Update(w *World, dt float64)
+}
+
+// SystemFunc is a function which is a System.
+type SystemFunc func(w *World, dt float64)
+
+// Update calls the function.
+func (sf SystemFunc) Update(w *World, dt float64) {
+	sf(w, dt)
+}
+
+// World is a set of Entities with their components and the Systems updating them.
+type World struct {
+	last       Entity
+	alive      map[Entity]bool
+	components map[reflect.Type]map[Entity]interface{}
+	systems    []System
+
+	renderer *pixel.Renderer
+	sprites  []pixel.SpriteRenderable
+}
+
+// NewWorld creates a new empty World.
+func NewWorld() *World {
+	return &World{
+		alive:      make(map[Entity]bool),
+		components: make(map[reflect.Type]map[Entity]interface{}),
+		renderer:   pixel.NewRenderer(),
+	}
+}
+
+// NewEntity creates a new Entity without any components.
+func (w *World) NewEntity() Entity {
+	w.last++
+	w.alive[w.last] = true
+	return w.last
+}
+
+// Remove removes the Entity with all its components.
+func (w *World) Remove(e Entity) {
+	delete(w.alive, e)
+	for _, store := range w.components {
+		delete(store, e)
+	}
+}
+
+// Alive reports whether the Entity exists in the World.
+func (w *World) Alive(e Entity) bool {
+	return w.alive[e]
+}
+
+// Len returns the number of the Entities in the World.
+func (w *World) Len() int {
+	return len(w.alive)
+}
+
+// Add adds the components to the Entity, replacing its components of the same types. The
+// components must be pointers, so they can be changed in place.
+func (w *World) Add(e Entity, components ...interface{}) {
+	if !w.alive[e] {
+		panic(fmt.Errorf("(%T).Add: entity %d doesn't exist", w, e))
+	}
+	for _, c := range components {
+		typ := reflect.TypeOf(c)
+		if typ == nil || typ.Kind() != reflect.Ptr {
+			panic(fmt.Errorf("(%T).Add: component %T is not a pointer", w, c))
+		}
+		store := w.components[typ]
+		if store == nil {
+			store = make(map[Entity]interface{})
+			w.components[typ] = store
+		}
+		store[e] = c
+	}
+}
+
+// Delete removes the component of the type of the example from the Entity, e.g.
+//
+//   w.Delete(e, (*ecs.Sprite)(nil))
+func (w *World) Delete(e Entity, example interface{}) {
+	delete(w.components[reflect.TypeOf(example)], e)
+}
+
+// Get sets the pointer pointed to by target to the component of the Entity of its type and reports
+// whether the Entity has it.
+//
+//   var h *Health
+//   if w.Get(e, &h) {
+//       h.Points -= damage
+//   }
+func (w *World) Get(e Entity, target interface{}) bool {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Ptr {
+		panic(fmt.Errorf("(%T).Get: target %T is not a pointer to a pointer", w, target))
+	}
+	c, ok := w.components[v.Elem().Type()][e]
+	if ok {
+		v.Elem().Set(reflect.ValueOf(c))
+	}
+	return ok
+}
+
+// Has reports whether the Entity has the component of the type of the example.
+func (w *World) Has(e Entity, example interface{}) bool {
+	_, ok := w.components[reflect.TypeOf(example)][e]
+	return ok
+}
+
+// With returns the Entities having the components of the types of all the examples, in the order
+// they were created.
+//
+//   for _, e := range w.With((*ecs.Transform)(nil), (*Velocity)(nil)) {
+//       ...
+//   }
+func (w *World) With(examples ...interface{}) []Entity {
+	if len(examples) == 0 {
+		return nil
+	}
+	stores := make([]map[Entity]interface{}, len(examples))
+	for i, ex := range examples {
+		stores[i] = w.components[reflect.TypeOf(ex)]
+	}
+	// go through the smallest store
+	sort.Slice(stores, func(i, j int) bool { return len(stores[i]) < len(stores[j]) })
+
+	var entities []Entity
+outer:
+	for e := range stores[0] {
+		for _, store := range stores[1:] {
+			if _, ok := store[e]; !ok {
+				continue outer
+			}
+		}
+		entities = append(entities, e)
+	}
+	sort.Slice(entities, func(i, j int) bool { return entities[i] < entities[j] })
+	return entities
+}
+
+// AddSystem adds the System, which is updated after the ones added before it.
+func (w *World) AddSystem(s System) {
+	w.systems = append(w.systems, s)
+}
+
+// Update updates all the Systems by the time dt in seconds.
+func (w *World) Update(dt float64) {
+	for _, s := range w.systems {
+		s.Update(w, dt)
+	}
+}
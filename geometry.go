@@ -642,3 +642,24 @@ func (m Matrix) Unproject(u Vec) Vec {
 		(-m[1]*(u.X-m[4]) + m[0]*(u.Y-m[5])) / det,
 	}
 }
+
+// FitMatrix returns a Matrix that scales and moves the rectangle src to fit inside the rectangle
+// dst as large as possible, centered and keeping its aspect ratio. The rest of dst stays empty on
+// two of its sides.
+//
+//   // the whole level in the minimap
+//   minimap.SetMatrix(pixel.FitMatrix(level.Bounds(), minimap.Bounds()))
+func FitMatrix(src, dst Rect) Matrix {
+	return IM.Moved(src.Center().Scaled(-1)).
+		Scaled(ZV, math.Min(dst.W()/src.W(), dst.H()/src.H())).
+		Moved(dst.Center())
+}
+
+// FillMatrix returns a Matrix that scales and moves the rectangle src to cover the whole rectangle
+// dst as small as possible, centered and keeping its aspect ratio. The parts of src on two of its
+// sides end up outside of dst.
+func FillMatrix(src, dst Rect) Matrix {
+	return IM.Moved(src.Center().Scaled(-1)).
+		Scaled(ZV, math.Max(dst.W()/src.W(), dst.H()/src.H())).
+		Moved(dst.Center())
+}
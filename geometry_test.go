@@ -676,3 +676,15 @@ func TestRect_IntersectCircle(t *testing.T) {
 		})
 	}
 }
+
+func TestFitMatrix(t *testing.T) {
+	src := pixel.R(0, 0, 20, 10)
+
+	fit := pixel.FitMatrix(src, pixel.R(100, 100, 200, 200))
+	assert.Equal(t, pixel.V(100, 125), fit.Project(src.Min))
+	assert.Equal(t, pixel.V(200, 175), fit.Project(src.Max))
+
+	fill := pixel.FillMatrix(src, pixel.R(100, 100, 200, 200))
+	assert.Equal(t, pixel.V(50, 100), fill.Project(src.Min))
+	assert.Equal(t, pixel.V(250, 200), fill.Project(src.Max))
+}
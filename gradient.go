@@ -0,0 +1,153 @@
+package pixel
+
+import (
+	"image/color"
+	"math"
+	"sort"
+)
+
+// Interpolation is a color space in which a Gradient interpolates between its colors.
+type Interpolation int
+
+const (
+	// InterpolateSRGB interpolates the components of the colors as they are. It's the cheapest
+	// and the most common interpolation, but the middle of a gradient between two bright colors
+	// is darker than they are.
+	InterpolateSRGB Interpolation = iota
+
+	// InterpolateLinear interpolates the colors in linear RGB (decoded from sRGB), which mixes
+	// them like light does, with an even brightness.
+	InterpolateLinear
+
+	// InterpolateHSV interpolates the hue, saturation and value of the colors, taking the shorter
+	// way around the hue circle. This goes through the colors of the rainbow between the hues.
+	InterpolateHSV
+)
+
+// GradientStop is a color at a position of a Gradient.
+type GradientStop struct {
+	Pos   float64
+	Color RGBA
+}
+
+// Gradient is a smooth transition between a number of colors, the stops. It samples a color at
+// any position, which makes it useful for filling shapes, coloring particles by their age, or
+// animating a tint over time.
+//
+// The Stops must be sorted by their positions. A position between two stops gets a mix of their
+// colors, and a position outside of the stops gets the color of the nearest one. Two stops at the
+// same position make a sharp edge between them.
+//
+//   fire := pixel.NewGradient(colornames.Yellow, colornames.Orange, colornames.Red)
+//   fire.Interpolation = pixel.InterpolateLinear
+//   particle.Color = fire.At(particle.Age / particle.Life)
+type Gradient struct {
+	Stops         []GradientStop
+	Interpolation Interpolation
+}
+
+// NewGradient creates a new Gradient of the colors evenly spaced from position 0 to 1.
+func NewGradient(colors ...color.Color) Gradient {
+	g := Gradient{Stops: make([]GradientStop, len(colors))}
+	for i, c := range colors {
+		pos := 0.0
+		if len(colors) > 1 {
+			pos = float64(i) / float64(len(colors)-1)
+		}
+		g.Stops[i] = GradientStop{Pos: pos, Color: ToRGBA(c)}
+	}
+	return g
+}
+
+// At returns the color of the Gradient at position t. A Gradient without any stops is transparent.
+func (g Gradient) At(t float64) RGBA {
+	n := len(g.Stops)
+	if n == 0 {
+		return RGBA{}
+	}
+	// the first stop after t
+	i := sort.Search(n, func(i int) bool { return g.Stops[i].Pos > t })
+	if i == 0 {
+		return g.Stops[0].Color
+	}
+	if i == n {
+		return g.Stops[n-1].Color
+	}
+
+	a, b := g.Stops[i-1], g.Stops[i]
+	return g.Interpolation.lerp(a.Color, b.Color, (t-a.Pos)/(b.Pos-a.Pos))
+}
+
+// lerp interpolates between the colors c and d in the color space by t within range [0, 1].
+func (in Interpolation) lerp(c, d RGBA, t float64) RGBA {
+	switch in {
+	case InterpolateLinear:
+		return c.Mix(d, t)
+	case InterpolateHSV:
+		hc, hd := ToHSV(c), ToHSV(d)
+		// the transparent colors have no color at all and the grays have no hue, they take them
+		// from the other color
+		if hc.A == 0 {
+			hc = HSV{H: hd.H, S: hd.S, V: hd.V}
+		}
+		if hd.A == 0 {
+			hd = HSV{H: hc.H, S: hc.S, V: hc.V}
+		}
+		if hc.S == 0 {
+			hc.H = hd.H
+		}
+		if hd.S == 0 {
+			hd.H = hc.H
+		}
+		dh := math.Mod(hd.H-hc.H+540, 360) - 180
+		return HSV{
+			H: normHue(hc.H + dh*t),
+			S: hc.S + (hd.S-hc.S)*t,
+			V: hc.V + (hd.V-hc.V)*t,
+			A: hc.A + (hd.A-hc.A)*t,
+		}.toRGBA()
+	}
+	return c.Lerp(d, t)
+}
+
+// toLinear converts the premultiplied sRGB color to the premultiplied linear RGB.
+func toLinear(c RGBA) RGBA {
+	if c.A == 0 {
+		return c
+	}
+	return RGBA{
+		R: srgbToLinear(c.R/c.A) * c.A,
+		G: srgbToLinear(c.G/c.A) * c.A,
+		B: srgbToLinear(c.B/c.A) * c.A,
+		A: c.A,
+	}
+}
+
+// fromLinear converts the premultiplied linear RGB color to the premultiplied sRGB.
+func fromLinear(c RGBA) RGBA {
+	if c.A == 0 {
+		return c
+	}
+	return RGBA{
+		R: linearToSRGB(c.R/c.A) * c.A,
+		G: linearToSRGB(c.G/c.A) * c.A,
+		B: linearToSRGB(c.B/c.A) * c.A,
+		A: c.A,
+	}
+}
+
+// srgbToLinear decodes an sRGB component into linear RGB.
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB encodes a linear RGB component into sRGB.
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
@@ -0,0 +1,98 @@
+package pixel_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+func TestGradientAt(t *testing.T) {
+	red, green, blue := pixel.RGB(1, 0, 0), pixel.RGB(0, 1, 0), pixel.RGB(0, 0, 1)
+	g := pixel.NewGradient(red, green, blue)
+
+	tests := []struct {
+		t    float64
+		want pixel.RGBA
+	}{
+		{-1, red},
+		{0, red},
+		{0.25, pixel.RGB(0.5, 0.5, 0)},
+		{0.5, green},
+		{0.75, pixel.RGB(0, 0.5, 0.5)},
+		{1, blue},
+		{2, blue},
+	}
+	for _, tt := range tests {
+		if got := g.At(tt.t); !eqColors(got, tt.want) {
+			t.Errorf("At(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+
+	if got := (pixel.Gradient{}).At(0.5); got != (pixel.RGBA{}) {
+		t.Errorf("empty gradient At(0.5) = %v, want transparent", got)
+	}
+	if got := pixel.NewGradient(green).At(0.7); got != green {
+		t.Errorf("single stop At(0.7) = %v, want %v", got, green)
+	}
+}
+
+func TestGradientSharpEdge(t *testing.T) {
+	red, blue := pixel.RGB(1, 0, 0), pixel.RGB(0, 0, 1)
+	g := pixel.Gradient{Stops: []pixel.GradientStop{
+		{Pos: 0, Color: red},
+		{Pos: 0.5, Color: red},
+		{Pos: 0.5, Color: blue},
+		{Pos: 1, Color: blue},
+	}}
+	if got := g.At(0.49); got != red {
+		t.Errorf("At(0.49) = %v, want %v", got, red)
+	}
+	if got := g.At(0.5); got != blue {
+		t.Errorf("At(0.5) = %v, want %v", got, blue)
+	}
+}
+
+func TestGradientInterpolation(t *testing.T) {
+	red, green := pixel.RGB(1, 0, 0), pixel.RGB(0, 1, 0)
+
+	g := pixel.NewGradient(red, green)
+	g.Interpolation = pixel.InterpolateLinear
+	// half of the light of each in linear RGB
+	mid := g.At(0.5)
+	if mid.R < 0.73 || mid.R > 0.74 || mid.R != mid.G || mid.B != 0 {
+		t.Errorf("linear At(0.5) = %v, want about RGB(0.735, 0.735, 0)", mid)
+	}
+	if got := g.At(0); !eqColors(got, red) {
+		t.Errorf("linear At(0) = %v, want %v", got, red)
+	}
+
+	g.Interpolation = pixel.InterpolateHSV
+	if got, want := g.At(0.5), pixel.RGB(1, 1, 0); !eqColors(got, want) {
+		t.Errorf("HSV At(0.5) = %v, want %v", got, want)
+	}
+	// the shorter way from magenta to yellow goes through red
+	g = pixel.NewGradient(pixel.RGB(1, 0, 1), pixel.RGB(1, 1, 0))
+	g.Interpolation = pixel.InterpolateHSV
+	if got := g.At(0.5); !eqColors(got, red) {
+		t.Errorf("HSV At(0.5) = %v, want %v", got, red)
+	}
+	// white has no hue and keeps the hue of red
+	g = pixel.NewGradient(red, pixel.RGB(1, 1, 1))
+	g.Interpolation = pixel.InterpolateHSV
+	if got, want := g.At(0.5), pixel.RGB(1, 0.5, 0.5); !eqColors(got, want) {
+		t.Errorf("HSV At(0.5) = %v, want %v", got, want)
+	}
+}
+
+func TestGradientPremultiplied(t *testing.T) {
+	// fading to transparent doesn't darken the color in any interpolation
+	red := pixel.RGB(1, 0, 0)
+	for _, in := range []pixel.Interpolation{pixel.InterpolateSRGB, pixel.InterpolateLinear, pixel.InterpolateHSV} {
+		g := pixel.NewGradient(red, pixel.Alpha(0))
+		g.Interpolation = in
+		got := g.At(0.5)
+		if got.G != 0 || got.B != 0 || got.R/got.A < 0.999 {
+			t.Errorf("interpolation %v: At(0.5) = %v, want half transparent red", in, got)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package pixel
+
+import (
+	"image/color"
+	"math"
+)
+
+// HSV represents a color by its hue, saturation, value and alpha. Unlike RGBA, the color is not
+// alpha-premultiplied.
+//
+// H is the hue in degrees within range [0, 360), going from red through yellow, green, cyan,
+// blue and magenta back to red. S, V and A are within range [0, 1].
+//
+// HSV is handy for procedural colors, such as a rainbow:
+//   for i := 0; i < 12; i++ {
+//       imd.Color = pixel.HSV{H: float64(i) * 30, S: 1, V: 1, A: 1}
+//       ...
+//   }
+type HSV struct {
+	H, S, V, A float64
+}
+
+// HSL represents a color by its hue, saturation, lightness and alpha. Unlike RGBA, the color is
+// not alpha-premultiplied.
+//
+// H is the hue in degrees within range [0, 360), the same as the hue of HSV. S, L and A are within
+// range [0, 1]. Lightness 0 is black and 1 is white, the pure colors have lightness 0.5.
+type HSL struct {
+	H, S, L, A float64
+}
+
+// HueRotated returns the color with the hue rotated by the angle in degrees.
+func (c HSV) HueRotated(angle float64) HSV {
+	c.H = normHue(c.H + angle)
+	return c
+}
+
+// HueRotated returns the color with the hue rotated by the angle in degrees.
+func (c HSL) HueRotated(angle float64) HSL {
+	c.H = normHue(c.H + angle)
+	return c
+}
+
+// HueRotated returns the color with the hue rotated by the angle in degrees, keeping its
+// saturation, value and alpha.
+func (c RGBA) HueRotated(angle float64) RGBA {
+	return ToHSV(c).HueRotated(angle).toRGBA()
+}
+
+// RGBA returns alpha-premultiplied red, green, blue and alpha components of the HSV color.
+func (c HSV) RGBA() (r, g, b, a uint32) {
+	return c.toRGBA().RGBA()
+}
+
+// RGBA returns alpha-premultiplied red, green, blue and alpha components of the HSL color.
+func (c HSL) RGBA() (r, g, b, a uint32) {
+	return c.toRGBA().RGBA()
+}
+
+func (c HSV) toRGBA() RGBA {
+	chroma := c.V * c.S
+	return hueRGB(c.H, chroma, c.V-chroma, c.A)
+}
+
+func (c HSL) toRGBA() RGBA {
+	chroma := (1 - math.Abs(2*c.L-1)) * c.S
+	return hueRGB(c.H, chroma, c.L-chroma/2, c.A)
+}
+
+// hueRGB returns the premultiplied RGBA color of the hue with the chroma, lifted by min.
+func hueRGB(hue, chroma, min, alpha float64) RGBA {
+	h := normHue(hue) / 60
+	x := chroma * (1 - math.Abs(math.Mod(h, 2)-1))
+
+	var r, g, b float64
+	switch {
+	case h < 1:
+		r, g, b = chroma, x, 0
+	case h < 2:
+		r, g, b = x, chroma, 0
+	case h < 3:
+		r, g, b = 0, chroma, x
+	case h < 4:
+		r, g, b = 0, x, chroma
+	case h < 5:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	return RGBA{
+		R: (r + min) * alpha,
+		G: (g + min) * alpha,
+		B: (b + min) * alpha,
+		A: alpha,
+	}
+}
+
+// normHue returns the hue within range [0, 360).
+func normHue(hue float64) float64 {
+	hue = math.Mod(hue, 360)
+	if hue < 0 {
+		hue += 360
+	}
+	return hue
+}
+
+// ToHSV converts a color to HSV format. The hue of the grays (including black and white) is zero.
+func ToHSV(c color.Color) HSV {
+	if c, ok := c.(HSV); ok {
+		return c
+	}
+	r, g, b, a, max, chroma := unpremultiplied(ToRGBA(c))
+
+	v := max
+	s := 0.0
+	if v > 0 {
+		s = chroma / v
+	}
+	return HSV{H: hue(r, g, b, max, chroma), S: s, V: v, A: a}
+}
+
+// ToHSL converts a color to HSL format. The hue of the grays (including black and white) is zero.
+func ToHSL(c color.Color) HSL {
+	if c, ok := c.(HSL); ok {
+		return c
+	}
+	r, g, b, a, max, chroma := unpremultiplied(ToRGBA(c))
+
+	l := max - chroma/2
+	s := 0.0
+	if l > 0 && l < 1 {
+		s = chroma / (1 - math.Abs(2*l-1))
+	}
+	return HSL{H: hue(r, g, b, max, chroma), S: s, L: l, A: a}
+}
+
+// unpremultiplied returns the non-premultiplied components of the color, together with the maximum
+// component and the chroma (the difference between the maximum and the minimum component).
+func unpremultiplied(c RGBA) (r, g, b, a, max, chroma float64) {
+	if c.A == 0 {
+		return 0, 0, 0, 0, 0, 0
+	}
+	r, g, b, a = c.R/c.A, c.G/c.A, c.B/c.A, c.A
+	max = math.Max(r, math.Max(g, b))
+	chroma = max - math.Min(r, math.Min(g, b))
+	return r, g, b, a, max, chroma
+}
+
+// hue returns the hue of the non-premultiplied components.
+func hue(r, g, b, max, chroma float64) float64 {
+	if chroma == 0 {
+		return 0
+	}
+	var h float64
+	switch max {
+	case r:
+		h = (g - b) / chroma
+	case g:
+		h = (b-r)/chroma + 2
+	default:
+		h = (r-g)/chroma + 4
+	}
+	return normHue(h * 60)
+}
+
+// HSVModel converts colors to HSV format.
+var HSVModel = color.ModelFunc(hsvModel)
+
+func hsvModel(c color.Color) color.Color {
+	return ToHSV(c)
+}
+
+// HSLModel converts colors to HSL format.
+var HSLModel = color.ModelFunc(hslModel)
+
+func hslModel(c color.Color) color.Color {
+	return ToHSL(c)
+}
@@ -0,0 +1,100 @@
+package pixel_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+func eqComponents(a, b [4]float64) bool {
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func eqColors(a, b pixel.RGBA) bool {
+	return eqComponents([4]float64{a.R, a.G, a.B, a.A}, [4]float64{b.R, b.G, b.B, b.A})
+}
+
+func eqHSV(a, b pixel.HSV) bool {
+	return eqComponents([4]float64{a.H, a.S, a.V, a.A}, [4]float64{b.H, b.S, b.V, b.A})
+}
+
+func eqHSL(a, b pixel.HSL) bool {
+	return eqComponents([4]float64{a.H, a.S, a.L, a.A}, [4]float64{b.H, b.S, b.L, b.A})
+}
+
+func TestHSV(t *testing.T) {
+	tests := []struct {
+		rgba pixel.RGBA
+		hsv  pixel.HSV
+		hsl  pixel.HSL
+	}{
+		{pixel.RGB(1, 0, 0), pixel.HSV{H: 0, S: 1, V: 1, A: 1}, pixel.HSL{H: 0, S: 1, L: 0.5, A: 1}},
+		{pixel.RGB(1, 1, 0), pixel.HSV{H: 60, S: 1, V: 1, A: 1}, pixel.HSL{H: 60, S: 1, L: 0.5, A: 1}},
+		{pixel.RGB(0, 0.5, 0), pixel.HSV{H: 120, S: 1, V: 0.5, A: 1}, pixel.HSL{H: 120, S: 1, L: 0.25, A: 1}},
+		{pixel.RGB(0.5, 1, 1), pixel.HSV{H: 180, S: 0.5, V: 1, A: 1}, pixel.HSL{H: 180, S: 1, L: 0.75, A: 1}},
+		{pixel.RGB(0.25, 0.25, 0.75), pixel.HSV{H: 240, S: 2.0 / 3, V: 0.75, A: 1}, pixel.HSL{H: 240, S: 0.5, L: 0.5, A: 1}},
+		{pixel.RGB(1, 0, 0.5), pixel.HSV{H: 330, S: 1, V: 1, A: 1}, pixel.HSL{H: 330, S: 1, L: 0.5, A: 1}},
+		{pixel.RGB(0, 0, 0), pixel.HSV{A: 1}, pixel.HSL{A: 1}},
+		{pixel.RGB(1, 1, 1), pixel.HSV{V: 1, A: 1}, pixel.HSL{L: 1, A: 1}},
+		{pixel.RGB(0.5, 0.5, 0.5), pixel.HSV{V: 0.5, A: 1}, pixel.HSL{L: 0.5, A: 1}},
+		// premultiplied
+		{pixel.RGB(1, 0, 0).Scaled(0.5), pixel.HSV{H: 0, S: 1, V: 1, A: 0.5}, pixel.HSL{H: 0, S: 1, L: 0.5, A: 0.5}},
+		{pixel.Alpha(0), pixel.HSV{}, pixel.HSL{}},
+	}
+	for _, tt := range tests {
+		if got := pixel.ToHSV(tt.rgba); !eqHSV(got, tt.hsv) {
+			t.Errorf("ToHSV(%v) = %v, want %v", tt.rgba, got, tt.hsv)
+		}
+		if got := pixel.ToHSL(tt.rgba); !eqHSL(got, tt.hsl) {
+			t.Errorf("ToHSL(%v) = %v, want %v", tt.rgba, got, tt.hsl)
+		}
+		if got := pixel.ToRGBA(tt.hsv); !eqColors(got, tt.rgba) {
+			t.Errorf("ToRGBA(%v) = %v, want %v", tt.hsv, got, tt.rgba)
+		}
+		if got := pixel.ToRGBA(tt.hsl); !eqColors(got, tt.rgba) {
+			t.Errorf("ToRGBA(%v) = %v, want %v", tt.hsl, got, tt.rgba)
+		}
+	}
+}
+
+func TestHSVColor(t *testing.T) {
+	// the HSV and HSL colors work as any color.Color
+	want := color.RGBA64Model.Convert(color.NRGBA{R: 0xff, G: 0x80, A: 0x80})
+	for _, c := range []color.Color{
+		pixel.HSVModel.Convert(want),
+		pixel.HSLModel.Convert(want),
+		pixel.HSV{H: 30, S: 1, V: 1, A: 0.5},
+		pixel.HSL{H: 30, S: 1, L: 0.5, A: 0.5},
+	} {
+		got := color.RGBA64Model.Convert(c).(color.RGBA64)
+		w := want.(color.RGBA64)
+		for _, d := range []int{int(got.R) - int(w.R), int(got.G) - int(w.G), int(got.B) - int(w.B), int(got.A) - int(w.A)} {
+			if d < -0x200 || d > 0x200 {
+				t.Errorf("%v converted to %v, want %v", c, got, w)
+				break
+			}
+		}
+	}
+}
+
+func TestHueRotated(t *testing.T) {
+	if got, want := (pixel.HSV{H: 300, S: 1, V: 1, A: 1}).HueRotated(120).H, 60.0; got != want {
+		t.Errorf("HSV hue rotated to %v, want %v", got, want)
+	}
+	if got, want := (pixel.HSL{H: 30, S: 1, L: 0.5, A: 1}).HueRotated(-90).H, 300.0; got != want {
+		t.Errorf("HSL hue rotated to %v, want %v", got, want)
+	}
+	if got, want := pixel.RGB(1, 0, 0).Scaled(0.5).HueRotated(240), pixel.RGB(0, 0, 1).Scaled(0.5); !eqColors(got, want) {
+		t.Errorf("RGBA hue rotated to %v, want %v", got, want)
+	}
+	if got, want := pixel.RGB(0.3, 0.3, 0.3).HueRotated(90), pixel.RGB(0.3, 0.3, 0.3); !eqColors(got, want) {
+		t.Errorf("gray hue rotated to %v, want %v", got, want)
+	}
+}
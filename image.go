@@ -0,0 +1,120 @@
+package pixel
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// PictureImage is an image.Image sharing the pixels of a PictureData, so it converts without
+// copying. Drawing onto it with image/draw changes the PictureData and the changes of the
+// PictureData show in the image.
+//
+// Like in image.RGBA, the colors are alpha-premultiplied and the first row is the top one, which
+// is the last one of the PictureData.
+//
+// The other Go graphics libraries accept image.Image, so a PictureData passes to them as either
+// PictureImage or Image. They copy it into their own textures, which is faster from an
+// *image.RGBA. The packages pixelebiten and pixelgio convert the Pictures to and from the images
+// of Ebiten and Gio.
+type PictureImage struct {
+	pd   *PictureData
+	rect image.Rectangle
+}
+
+var _ draw.Image = (*PictureImage)(nil)
+
+// ImageView returns a PictureImage sharing the pixels of the PictureData.
+func (pd *PictureData) ImageView() *PictureImage {
+	return &PictureImage{pd: pd, rect: pd.imageBounds()}
+}
+
+// PictureData returns the PictureData of the PictureImage.
+func (pi *PictureImage) PictureData() *PictureData {
+	return pi.pd
+}
+
+// ColorModel returns color.RGBAModel.
+func (pi *PictureImage) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+// Bounds returns the bounds of the PictureData as the image.Rectangle enclosing them.
+func (pi *PictureImage) Bounds() image.Rectangle {
+	return pi.rect
+}
+
+// At returns the color of the pixel at (x, y), or transparent outside of the bounds.
+func (pi *PictureImage) At(x, y int) color.Color {
+	return pi.RGBAAt(x, y)
+}
+
+// RGBAAt returns the color of the pixel at (x, y), or transparent outside of the bounds.
+func (pi *PictureImage) RGBAAt(x, y int) color.RGBA {
+	if !image.Pt(x, y).In(pi.rect) {
+		return color.RGBA{}
+	}
+	return pi.pd.Pix[pi.offset(x, y)]
+}
+
+// Set sets the color of the pixel at (x, y), doing nothing outside of the bounds.
+func (pi *PictureImage) Set(x, y int, c color.Color) {
+	pi.SetRGBA(x, y, color.RGBAModel.Convert(c).(color.RGBA))
+}
+
+// SetRGBA sets the color of the pixel at (x, y), doing nothing outside of the bounds.
+func (pi *PictureImage) SetRGBA(x, y int, c color.RGBA) {
+	if !image.Pt(x, y).In(pi.rect) {
+		return
+	}
+	pi.pd.Pix[pi.offset(x, y)] = c
+}
+
+// Opaque reports whether all the pixels are opaque.
+func (pi *PictureImage) Opaque() bool {
+	w, h := pi.rect.Dx(), pi.rect.Dy()
+	for y := 0; y < h; y++ {
+		for _, c := range pi.pd.Pix[y*pi.pd.Stride : y*pi.pd.Stride+w] {
+			if c.A != 0xff {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// offset returns the index of the pixel of the image at (x, y) in the Pix of the PictureData
+func (pi *PictureImage) offset(x, y int) int {
+	return (pi.rect.Max.Y-1-y)*pi.pd.Stride + x - pi.rect.Min.X
+}
+
+// ImagePicture is a PictureColor showing an image.Image without copying it, so the changes of the
+// image show in the Picture. The image may be from any Go graphics library, e.g. an
+// *ebiten.Image.
+//
+// The Targets copy the Pictures drawn onto them, which reads the whole image through its At
+// method. A PictureData made by PictureDataFromImage copies faster, if the image doesn't change.
+type ImagePicture struct {
+	Image image.Image
+}
+
+var _ PictureColor = ImagePicture{}
+
+// Bounds returns the bounds of the image.
+func (ip ImagePicture) Bounds() Rect {
+	b := ip.Image.Bounds()
+	return R(float64(b.Min.X), float64(b.Min.Y), float64(b.Max.X), float64(b.Max.Y))
+}
+
+// Color returns the color of the image at the position, which is transparent outside of the
+// bounds. Just like a PictureData made by PictureDataFromImage, the top row of the image is at the
+// top of the bounds.
+func (ip ImagePicture) Color(at Vec) RGBA {
+	b := ip.Image.Bounds()
+	x, y := int(math.Floor(at.X)), b.Min.Y+b.Max.Y-1-int(math.Floor(at.Y))
+	if !image.Pt(x, y).In(b) {
+		return RGBA{}
+	}
+	return ToRGBA(ip.Image.At(x, y))
+}
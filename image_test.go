@@ -0,0 +1,87 @@
+package pixel_test
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+// testImage returns an image of the bounds, with a different color in every pixel
+func testImage(bounds image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x * 10), G: uint8(y * 10), B: 0x80, A: 0xff})
+		}
+	}
+	return img
+}
+
+func TestPictureDataFromImage(t *testing.T) {
+	img := testImage(image.Rect(-2, 3, 3, 7))
+	pd := pixel.PictureDataFromImage(img)
+	assert.Equal(t, pixel.R(-2, 3, 3, 7), pd.Bounds())
+	// the top row of the image is at the top of the PictureData
+	assert.Equal(t, color.RGBA{R: 0xec, G: 30, B: 0x80, A: 0xff}, pd.Pix[pd.Index(pixel.V(-2, 6))])
+	assert.Equal(t, img, pd.Image())
+
+	// the other images are converted too
+	nrgba := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	nrgba.SetNRGBA(1, 0, color.NRGBA{R: 0xff, A: 0x80})
+	pd = pixel.PictureDataFromImage(nrgba)
+	assert.Equal(t, color.RGBA{R: 0x80, A: 0x80}, pd.Pix[1], "premultiplied")
+
+	sub := img.SubImage(image.Rect(0, 4, 2, 6)).(*image.RGBA)
+	pd = pixel.PictureDataFromImage(sub)
+	assert.Equal(t, pixel.R(0, 4, 2, 6), pd.Bounds())
+	assert.Equal(t, color.RGBA{R: 10, G: 50, B: 0x80, A: 0xff}, pd.Pix[pd.Index(pixel.V(1, 4))])
+}
+
+func TestPictureImage(t *testing.T) {
+	img := testImage(image.Rect(-2, 3, 3, 7))
+	pd := pixel.PictureDataFromImage(img)
+	view := pd.ImageView()
+	assert.True(t, view.PictureData() == pd)
+	assert.Equal(t, img.Bounds(), view.Bounds())
+	for y := 3; y < 7; y++ {
+		for x := -2; x < 3; x++ {
+			assert.Equal(t, img.RGBAAt(x, y), view.RGBAAt(x, y))
+		}
+	}
+	assert.Equal(t, color.RGBA{}, view.At(3, 3))
+	assert.True(t, view.Opaque())
+
+	// drawing onto the view changes the PictureData
+	draw.Draw(view, image.Rect(0, 3, 1, 4), image.NewUniform(color.RGBA{G: 0xff, A: 0xff}), image.Point{}, draw.Src)
+	assert.Equal(t, color.RGBA{G: 0xff, A: 0xff}, pd.Pix[pd.Index(pixel.V(0, 6))])
+	view.Set(1, 6, color.Transparent)
+	view.Set(10, 10, color.White)
+	assert.Equal(t, color.RGBA{}, pd.Pix[pd.Index(pixel.V(1, 3))])
+	assert.False(t, view.Opaque())
+
+	// copying it back by image/draw
+	back := image.NewRGBA(view.Bounds())
+	draw.Draw(back, back.Rect, view, back.Rect.Min, draw.Src)
+	assert.Equal(t, pd.Image(), back)
+}
+
+func TestImagePicture(t *testing.T) {
+	img := testImage(image.Rect(-2, 3, 3, 7))
+	pd := pixel.PictureDataFromImage(img)
+	pic := pixel.ImagePicture{Image: img}
+	assert.Equal(t, pd.Bounds(), pic.Bounds())
+	for y := 3.0; y < 7; y++ {
+		for x := -2.0; x < 3; x++ {
+			assert.Equal(t, pd.Color(pixel.V(x+0.5, y+0.5)), pic.Color(pixel.V(x+0.5, y+0.5)))
+		}
+	}
+	assert.Equal(t, pixel.RGBA{}, pic.Color(pixel.V(-2.5, 4)))
+	assert.Equal(t, pixel.RGBA{}, pic.Color(pixel.V(0, 7)))
+
+	img.SetRGBA(0, 3, color.RGBA{A: 0xff})
+	assert.Equal(t, pixel.RGB(0, 0, 0), pic.Color(pixel.V(0, 6)), "not copied")
+}
@@ -0,0 +1,166 @@
+package imdraw
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// curveTolerance is the largest allowed distance between a curve and the line segments
+// approximating it, after the matrix is applied.
+const curveTolerance = 0.1
+
+// curveMaxDepth limits the number of subdivisions of a curve, so that a curve is approximated by at
+// most 2^curveMaxDepth line segments.
+const curveMaxDepth = 10
+
+// CurveTo Pushes points along a cubic Bézier curve starting at the last Pushed point, ending at the
+// end point and shaped by the two control points. The curve is split into as many points as needed
+// for it to look smooth after being transformed by the current matrix. The starting point is not
+// Pushed again.
+//
+//   imd.Push(pixel.V(0, 0))
+//   imd.CurveTo(pixel.V(0, 100), pixel.V(100, 100), pixel.V(100, 0))
+//   imd.Line(4)
+//
+// All Pushed points get the current point properties, just like with Push. If no point has been
+// Pushed yet, only the end point is Pushed.
+func (imd *IMDraw) CurveTo(ctrl1, ctrl2, end pixel.Vec) {
+	if len(imd.points) == 0 {
+		imd.Push(end)
+		return
+	}
+	start := imd.points[len(imd.points)-1].pos
+	opts := imd.pointOpts()
+	imd.flattenCubic(start, ctrl1, ctrl2, end, 0, 1, curveMaxDepth, func(pos pixel.Vec, t float64) {
+		imd.pushPt(pos, opts)
+	})
+}
+
+// QuadTo Pushes points along a quadratic Bézier curve starting at the last Pushed point, ending at
+// the end point and shaped by the control point. It works just like CurveTo.
+func (imd *IMDraw) QuadTo(ctrl, end pixel.Vec) {
+	if len(imd.points) == 0 {
+		imd.Push(end)
+		return
+	}
+	start := imd.points[len(imd.points)-1].pos
+	// a quadratic curve is a cubic curve with the control points at 2/3 of the way to the
+	// quadratic control point
+	imd.CurveTo(
+		start.Add(start.To(ctrl).Scaled(2.0/3)),
+		end.Add(end.To(ctrl).Scaled(2.0/3)),
+		end,
+	)
+}
+
+// CatmullRom replaces the Pushed points with a smooth Catmull-Rom spline passing through all of
+// them. The properties of the new points (color, picture, ...) are interpolated between the
+// properties of the original points.
+//
+// If closed is true, the spline also continues from the last point back to the first one, which is
+// what you want before drawing a Polygon outline. Don't Push the first point again at the end in
+// that case.
+//
+//   imd.Push(pixel.V(0, 0), pixel.V(50, 80), pixel.V(100, 0), pixel.V(150, 80))
+//   imd.CatmullRom(false)
+//   imd.Line(4)
+func (imd *IMDraw) CatmullRom(closed bool) {
+	if len(imd.points) < 3 && !(closed && len(imd.points) == 2) {
+		return
+	}
+
+	points := imd.getAndClearPoints()
+	n := len(points)
+	at := func(i int) point {
+		if closed {
+			return points[((i%n)+n)%n]
+		}
+		// repeat the end points, so that the spline ends in them
+		if i < 0 {
+			i = 0
+		}
+		if i >= n {
+			i = n - 1
+		}
+		return points[i]
+	}
+
+	segments := n - 1
+	if closed {
+		segments = n
+	}
+
+	imd.pushPt(points[0].pos, points[0])
+	for i := 0; i < segments; i++ {
+		p0, p1, p2, p3 := at(i-1), at(i), at(i+1), at(i+2)
+		// the same curve as a cubic Bézier
+		ctrl1 := p1.pos.Add(p0.pos.To(p2.pos).Scaled(1.0 / 6))
+		ctrl2 := p2.pos.Sub(p1.pos.To(p3.pos).Scaled(1.0 / 6))
+		last := closed && i == segments-1
+		imd.flattenCubic(p1.pos, ctrl1, ctrl2, p2.pos, 0, 1, curveMaxDepth, func(pos pixel.Vec, t float64) {
+			if last && t == 1 {
+				// don't repeat the first point of a closed spline
+				return
+			}
+			imd.pushPt(pos, lerpPoint(p1, p2, t))
+		})
+	}
+
+	// the original points are not needed anymore, keep them for reuse
+	imd.pool = append(imd.pool, points[:0])
+}
+
+// flattenCubic calls emit with the points approximating the cubic Bézier curve (p0, p1, p2, p3)
+// with line segments, together with their parameters in range (t0, t1]. The starting point p0 is
+// not emitted.
+func (imd *IMDraw) flattenCubic(p0, p1, p2, p3 pixel.Vec, t0, t1 float64, depth int, emit func(pixel.Vec, float64)) {
+	if depth == 0 || imd.isFlat(p0, p1, p2, p3) {
+		emit(p3, t1)
+		return
+	}
+
+	// de Casteljau subdivision in the middle
+	p01 := lerp(p0, p1, 0.5)
+	p12 := lerp(p1, p2, 0.5)
+	p23 := lerp(p2, p3, 0.5)
+	p012 := lerp(p01, p12, 0.5)
+	p123 := lerp(p12, p23, 0.5)
+	mid := lerp(p012, p123, 0.5)
+	tm := (t0 + t1) / 2
+
+	imd.flattenCubic(p0, p01, p012, mid, t0, tm, depth-1, emit)
+	imd.flattenCubic(mid, p123, p23, p3, tm, t1, depth-1, emit)
+}
+
+// isFlat reports whether the control points of the cubic Bézier curve are close enough to the line
+// between its end points, after transforming them by the matrix.
+func (imd *IMDraw) isFlat(p0, p1, p2, p3 pixel.Vec) bool {
+	p0 = imd.matrix.Project(p0)
+	p1 = imd.matrix.Project(p1)
+	p2 = imd.matrix.Project(p2)
+	p3 = imd.matrix.Project(p3)
+	return distToLine(p1, p0, p3) <= curveTolerance && distToLine(p2, p0, p3) <= curveTolerance
+}
+
+// distToLine returns the distance of the point from the line going through a and b.
+func distToLine(p, a, b pixel.Vec) float64 {
+	ab := a.To(b)
+	if ab.Len() == 0 {
+		return a.To(p).Len()
+	}
+	return math.Abs(ab.Cross(a.To(p))) / ab.Len()
+}
+
+func lerp(a, b pixel.Vec, t float64) pixel.Vec {
+	return a.Add(a.To(b).Scaled(t))
+}
+
+// lerpPoint interpolates the properties of two points, except for the position.
+func lerpPoint(a, b point, t float64) point {
+	p := a
+	p.col = a.col.Scaled(1 - t).Add(b.col.Scaled(t))
+	p.pic = lerp(a.pic, b.pic, t)
+	p.in = a.in*(1-t) + b.in*t
+	return p
+}
@@ -0,0 +1,130 @@
+package imdraw
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// featherMaxMiter limits how far the fringe reaches at sharp corners, in multiples of its width.
+const featherMaxMiter = 4
+
+// beginShape starts drawing a shape and returns the offset of its triangles.
+func (imd *IMDraw) beginShape() int {
+	imd.shapes++
+	return imd.tri.Len()
+}
+
+// endShape finishes drawing the shape with the triangles starting at off. If it's not a part of
+// another shape, it's feathered.
+func (imd *IMDraw) endShape(off int, feather float64) {
+	imd.shapes--
+	if imd.shapes == 0 {
+		imd.applyFeather(off, feather)
+	}
+}
+
+// applyFeather adds a fringe of the width around the triangles starting at off. The fringe starts
+// with the color of the triangles at their edges and fades to transparent. The edges are found as
+// the edges not shared by two triangles, so they must share their vertices exactly.
+//
+// The triangles must already be transformed by the matrix, so that the width is in the units of
+// the Target.
+func (imd *IMDraw) applyFeather(off int, width float64) {
+	if width <= 0 {
+		return
+	}
+
+	type edgeKey struct {
+		a, b pixel.Vec
+	}
+	key := func(a, b pixel.Vec) edgeKey {
+		if a.X > b.X || (a.X == b.X && a.Y > b.Y) {
+			a, b = b, a
+		}
+		return edgeKey{a, b}
+	}
+
+	tris := make([]vertex, imd.tri.Len()-off)
+	for i := range tris {
+		tris[i] = (*imd.tri)[off+i]
+	}
+	degenerate := func(i int) bool {
+		a, b, c := tris[i].Position, tris[i+1].Position, tris[i+2].Position
+		return math.Abs(a.To(b).Cross(a.To(c))) < 1e-9
+	}
+
+	count := make(map[edgeKey]int)
+	for i := 0; i+2 < len(tris); i += 3 {
+		if degenerate(i) {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			count[key(tris[i+k].Position, tris[i+(k+1)%3].Position)]++
+		}
+	}
+
+	// outward normals of the edges around each vertex
+	normals := make(map[pixel.Vec][]pixel.Vec)
+	type edge struct {
+		a, b vertex
+	}
+	var edges []edge
+	for i := 0; i+2 < len(tris); i += 3 {
+		if degenerate(i) {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			a, b, c := tris[i+k], tris[i+(k+1)%3], tris[i+(k+2)%3]
+			if count[key(a.Position, b.Position)] != 1 {
+				continue
+			}
+			normal := a.Position.To(b.Position).Normal().Unit()
+			if normal.Dot(a.Position.To(c.Position)) > 0 {
+				normal = normal.Scaled(-1)
+			}
+			normals[a.Position] = append(normals[a.Position], normal)
+			normals[b.Position] = append(normals[b.Position], normal)
+			edges = append(edges, edge{a, b})
+		}
+	}
+	if len(edges) == 0 {
+		return
+	}
+
+	offsets := make(map[pixel.Vec]pixel.Vec, len(normals))
+	for pos, ns := range normals {
+		sum := pixel.ZV
+		for _, n := range ns {
+			sum = sum.Add(n)
+		}
+		if sum.Len() < 1e-9 {
+			offsets[pos] = ns[0].Scaled(width)
+			continue
+		}
+		dir := sum.Unit()
+		// reach the width in the direction of each normal, like a miter join
+		scale := featherMaxMiter * width
+		for _, n := range ns {
+			if d := dir.Dot(n); d > 0 {
+				scale = math.Min(scale, width/d)
+			}
+		}
+		offsets[pos] = dir.Scaled(scale)
+	}
+
+	fringe := func(v vertex) vertex {
+		v.Position = v.Position.Add(offsets[v.Position])
+		v.Color = pixel.RGBA{}
+		return v
+	}
+
+	start := imd.tri.Len()
+	imd.setLen(start + 6*len(edges))
+	for i, e := range edges {
+		for k, v := range [...]vertex{e.a, e.b, fringe(e.b), e.a, fringe(e.b), fringe(e.a)} {
+			(*imd.tri)[start+6*i+k] = v
+		}
+	}
+	imd.batch.Dirty()
+}
@@ -0,0 +1,291 @@
+package imdraw
+
+import (
+	"sort"
+
+	"github.com/faiface/pixel"
+)
+
+// FillRule specifies which parts of a filled Polygon are inside of it.
+type FillRule int
+
+const (
+	// FanFill draws a triangle between each two adjacent points and the first point of each
+	// contour, the same way as in the previous versions of IMDraw. It's the fastest and it's
+	// correct for convex polygons.
+	FanFill FillRule = iota
+
+	// EvenOddFill fills the parts of the plane that are inside an odd number of contours. Holes
+	// are made by contours inside other contours, regardless of their direction.
+	EvenOddFill
+
+	// NonZeroFill fills the parts of the plane around which the contours wind a non-zero number of
+	// times. Holes are made by contours going in the opposite direction than the contours around
+	// them.
+	NonZeroFill
+)
+
+func (rule FillRule) inside(winding int) bool {
+	switch rule {
+	case EvenOddFill:
+		return winding%2 != 0
+	case NonZeroFill:
+		return winding != 0
+	}
+	return false
+}
+
+// NextContour ends the current contour of Pushed points and starts a new one with the next Pushed
+// point. A filled Polygon with multiple contours is filled according to the FillRule, which makes
+// it possible to draw shapes with holes:
+//
+//   imd.FillRule = imdraw.EvenOddFill
+//   imd.Push(pixel.V(0, 0), pixel.V(100, 0), pixel.V(100, 100), pixel.V(0, 100))
+//   imd.NextContour()
+//   imd.Push(pixel.V(25, 25), pixel.V(75, 25), pixel.V(75, 75), pixel.V(25, 75))
+//   imd.Polygon(0)
+//
+// An outlined Polygon outlines each contour separately. Other shapes ignore contours.
+func (imd *IMDraw) NextContour() {
+	imd.newContour = len(imd.points) > 0
+}
+
+// splitContours splits the points into contours at the points starting a new contour.
+func splitContours(points []point) [][]point {
+	var contours [][]point
+	start := 0
+	for i := 1; i < len(points); i++ {
+		if points[i].contour {
+			contours = append(contours, points[start:i])
+			start = i
+		}
+	}
+	return append(contours, points[start:])
+}
+
+// hasContours reports whether the points make up more than one contour.
+func hasContours(points []point) bool {
+	for _, pt := range points[1:] {
+		if pt.contour {
+			return true
+		}
+	}
+	return false
+}
+
+func (imd *IMDraw) outlineContours(thickness float64) {
+	points := imd.getAndClearPoints()
+
+	for _, contour := range splitContours(points) {
+		for _, pt := range contour {
+			imd.pushPt(pt.pos, pt)
+		}
+		imd.polyline(thickness, true)
+	}
+
+	imd.restorePoints(points)
+}
+
+// fillContours fills each contour separately with FanFill.
+func (imd *IMDraw) fillContours() {
+	points := imd.getAndClearPoints()
+
+	for _, contour := range splitContours(points) {
+		for _, pt := range contour {
+			imd.pushPt(pt.pos, pt)
+		}
+		imd.fillPolygon()
+	}
+
+	imd.restorePoints(points)
+}
+
+// fillEdge is a non-horizontal edge of a contour, lo is its lower end.
+type fillEdge struct {
+	lo, hi vertex
+	dir    int
+}
+
+// at returns the vertex on the edge at the height.
+func (e fillEdge) at(y float64) vertex {
+	return lerpVertex(e.lo, e.hi, (y-e.lo.Position.Y)/(e.hi.Position.Y-e.lo.Position.Y))
+}
+
+// fillRule fills arbitrary contours, which may be concave or self-intersecting, according to the
+// fill rule. The plane is cut into horizontal slabs at the heights of all vertices and edge
+// intersections, so that the edges crossing a slab don't cross each other inside it. Each span
+// between two such edges is then either completely inside or outside and it's drawn as a
+// trapezoid.
+func (imd *IMDraw) fillRule(rule FillRule) {
+	points := imd.getAndClearPoints()
+
+	if len(points) < 3 {
+		imd.restorePoints(points)
+		return
+	}
+
+	var edges []fillEdge
+	for _, contour := range splitContours(points) {
+		for i := range contour {
+			a, b := contour[i], contour[(i+1)%len(contour)]
+			e := fillEdge{
+				lo:  vertex{Position: a.pos, Color: a.col, Picture: a.pic, Intensity: a.in},
+				hi:  vertex{Position: b.pos, Color: b.col, Picture: b.pic, Intensity: b.in},
+				dir: 1,
+			}
+			if e.lo.Position.Y == e.hi.Position.Y {
+				continue
+			}
+			if e.lo.Position.Y > e.hi.Position.Y {
+				e.lo, e.hi, e.dir = e.hi, e.lo, -1
+			}
+			edges = append(edges, e)
+		}
+	}
+
+	var ys []float64
+	for i, e := range edges {
+		ys = append(ys, e.lo.Position.Y, e.hi.Position.Y)
+		for _, f := range edges[i+1:] {
+			if y, ok := intersectionY(e, f); ok {
+				ys = append(ys, y)
+			}
+		}
+	}
+	sort.Float64s(ys)
+
+	type crossing struct {
+		x        float64
+		bot, top vertex
+		dir      int
+	}
+	type slab struct {
+		y0, y1 float64
+		active []crossing
+	}
+	var slabs []slab
+
+	for i := 0; i+1 < len(ys); i++ {
+		y0, y1 := ys[i], ys[i+1]
+		if y1-y0 < 1e-9 {
+			continue
+		}
+		mid := (y0 + y1) / 2
+
+		var active []crossing
+		for _, e := range edges {
+			if e.lo.Position.Y < mid && mid < e.hi.Position.Y {
+				active = append(active, crossing{
+					x:   e.at(mid).Position.X,
+					bot: e.at(y0),
+					top: e.at(y1),
+					dir: e.dir,
+				})
+			}
+		}
+		sort.Slice(active, func(i, j int) bool {
+			return active[i].x < active[j].x
+		})
+		slabs = append(slabs, slab{y0, y1, active})
+	}
+
+	var tris []vertex
+	for i, sl := range slabs {
+		// the edges of the trapezoids are split at the vertices of the trapezoids of the
+		// neighboring slabs, so that the trapezoids share their edges exactly
+		var bots, tops []pixel.Vec
+		for _, c := range sl.active {
+			bots = append(bots, c.bot.Position)
+			tops = append(tops, c.top.Position)
+		}
+		if i > 0 && slabs[i-1].y1 == sl.y0 {
+			for _, c := range slabs[i-1].active {
+				bots = append(bots, c.top.Position)
+			}
+		}
+		if i+1 < len(slabs) && slabs[i+1].y0 == sl.y1 {
+			for _, c := range slabs[i+1].active {
+				tops = append(tops, c.bot.Position)
+			}
+		}
+
+		winding := 0
+		for k := 0; k+1 < len(sl.active); k++ {
+			winding += sl.active[k].dir
+			if !rule.inside(winding) {
+				continue
+			}
+			l, r := sl.active[k], sl.active[k+1]
+			tris = appendStrip(tris, splitSide(l.bot, r.bot, bots), splitSide(l.top, r.top, tops))
+		}
+	}
+
+	off := imd.beginShape()
+	imd.setLen(off + len(tris))
+	for i, v := range tris {
+		(*imd.tri)[off+i] = v
+	}
+
+	imd.applyPictureMapping(off, points[0].mapping)
+	imd.applyGradient(off, points[0].grad)
+	imd.applyMatrixAndMask(off)
+	imd.endShape(off, points[0].feather)
+	imd.batch.Dirty()
+
+	imd.restorePoints(points)
+}
+
+// intersectionY returns the height at which the two edges cross each other.
+func intersectionY(e, f fillEdge) (y float64, ok bool) {
+	p, r := e.lo.Position, e.lo.Position.To(e.hi.Position)
+	q, s := f.lo.Position, f.lo.Position.To(f.hi.Position)
+	d := r.Cross(s)
+	if d == 0 {
+		return 0, false
+	}
+	t := p.To(q).Cross(s) / d
+	u := p.To(q).Cross(r) / d
+	if t <= 0 || t >= 1 || u <= 0 || u >= 1 {
+		return 0, false
+	}
+	return p.Y + t*r.Y, true
+}
+
+// splitSide returns the side of a trapezoid going from a to b, split at the points between them.
+func splitSide(a, b vertex, points []pixel.Vec) []vertex {
+	side := []vertex{a}
+	for _, p := range points {
+		if a.Position.X < p.X && p.X < b.Position.X {
+			v := lerpVertex(a, b, (p.X-a.Position.X)/(b.Position.X-a.Position.X))
+			v.Position = p
+			side = append(side, v)
+		}
+	}
+	sort.Slice(side, func(i, j int) bool {
+		return side[i].Position.X < side[j].Position.X
+	})
+	// the same point may come from both slabs
+	unique := side[:1]
+	for _, v := range side[1:] {
+		if v.Position != unique[len(unique)-1].Position {
+			unique = append(unique, v)
+		}
+	}
+	return append(unique, b)
+}
+
+// appendStrip appends triangles covering the area between the bottom and the top side of a
+// trapezoid, both going from left to right.
+func appendStrip(tris []vertex, bot, top []vertex) []vertex {
+	i, j := 0, 0
+	for i+1 < len(bot) || j+1 < len(top) {
+		if j+1 == len(top) || (i+1 < len(bot) && bot[i+1].Position.X <= top[j+1].Position.X) {
+			tris = append(tris, bot[i], bot[i+1], top[j])
+			i++
+		} else {
+			tris = append(tris, bot[i], top[j+1], top[j])
+			j++
+		}
+	}
+	return tris
+}
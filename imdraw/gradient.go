@@ -0,0 +1,205 @@
+package imdraw
+
+import (
+	"math"
+	"sort"
+
+	"github.com/faiface/pixel"
+)
+
+// GradientStop is a color of a Gradient at an offset. The offset 0 is the start of the Gradient and
+// the offset 1 is its end.
+type GradientStop struct {
+	Offset float64
+	Color  pixel.RGBA
+}
+
+// Gradient is a linear or a radial color gradient. Set it as the Gradient point property of IMDraw
+// to color the drawn shapes with it.
+//
+// Before the first stop and after the last stop, the Gradient has the color of the nearest stop.
+type Gradient struct {
+	radial bool
+	from   pixel.Vec
+	to     pixel.Vec
+	radius float64
+	stops  []GradientStop
+}
+
+// LinearGradient creates a Gradient changing its color along the line going from the start point
+// (offset 0) to the end point (offset 1). The color is constant along the lines perpendicular to it.
+//
+//   imd.Gradient = imdraw.LinearGradient(pixel.V(0, 0), pixel.V(0, 100),
+//       imdraw.GradientStop{Offset: 0, Color: pixel.RGB(1, 0, 0)},
+//       imdraw.GradientStop{Offset: 1, Color: pixel.RGB(0, 0, 1)},
+//   )
+func LinearGradient(start, end pixel.Vec, stops ...GradientStop) *Gradient {
+	return &Gradient{
+		from:  start,
+		to:    end,
+		stops: sortStops(stops),
+	}
+}
+
+// RadialGradient creates a Gradient changing its color with the distance from the center. The
+// center has offset 0 and the circle with the radius has offset 1.
+func RadialGradient(center pixel.Vec, radius float64, stops ...GradientStop) *Gradient {
+	return &Gradient{
+		radial: true,
+		from:   center,
+		radius: radius,
+		stops:  sortStops(stops),
+	}
+}
+
+func sortStops(stops []GradientStop) []GradientStop {
+	stops = append([]GradientStop(nil), stops...)
+	sort.SliceStable(stops, func(i, j int) bool {
+		return stops[i].Offset < stops[j].Offset
+	})
+	return stops
+}
+
+// Offset returns the offset of the Gradient at the position.
+func (g *Gradient) Offset(at pixel.Vec) float64 {
+	if g.radial {
+		if g.radius == 0 {
+			return 1
+		}
+		return g.from.To(at).Len() / g.radius
+	}
+	dir := g.from.To(g.to)
+	if dir.Len() == 0 {
+		return 0
+	}
+	return g.from.To(at).Dot(dir) / dir.Dot(dir)
+}
+
+// Color returns the color of the Gradient at the offset. If the Gradient has no stops, this is
+// always opaque white.
+func (g *Gradient) Color(offset float64) pixel.RGBA {
+	if len(g.stops) == 0 {
+		return pixel.Alpha(1)
+	}
+	if offset <= g.stops[0].Offset {
+		return g.stops[0].Color
+	}
+	for i := 1; i < len(g.stops); i++ {
+		a, b := g.stops[i-1], g.stops[i]
+		if offset <= b.Offset {
+			t := (offset - a.Offset) / (b.Offset - a.Offset)
+			return a.Color.Scaled(1 - t).Add(b.Color.Scaled(t))
+		}
+	}
+	return g.stops[len(g.stops)-1].Color
+}
+
+// At returns the color of the Gradient at the position.
+func (g *Gradient) At(at pixel.Vec) pixel.RGBA {
+	return g.Color(g.Offset(at))
+}
+
+// vertex is the element type of pixel.TrianglesData.
+type vertex struct {
+	Position  pixel.Vec
+	Color     pixel.RGBA
+	Picture   pixel.Vec
+	Intensity float64
+}
+
+func lerpVertex(a, b vertex, t float64) vertex {
+	return vertex{
+		Position:  lerp(a.Position, b.Position, t),
+		Color:     a.Color.Scaled(1 - t).Add(b.Color.Scaled(t)),
+		Picture:   lerp(a.Picture, b.Picture, t),
+		Intensity: a.Intensity*(1-t) + b.Intensity*t,
+	}
+}
+
+// gradientMaxDepth limits the subdivision of triangles for radial gradients.
+const gradientMaxDepth = 5
+
+// applyGradient multiplies the colors of the triangles starting at off by the Gradient. The
+// triangles are split, so that the colors are correct even though they're only computed at the
+// vertices: along the lines of the stops for linear gradients, and into small triangles for radial
+// gradients.
+func (imd *IMDraw) applyGradient(off int, g *Gradient) {
+	if g == nil {
+		return
+	}
+
+	var tris []vertex
+	for i := off; i+2 < imd.tri.Len(); i += 3 {
+		a, b, c := vertex((*imd.tri)[i]), vertex((*imd.tri)[i+1]), vertex((*imd.tri)[i+2])
+		if g.radial {
+			tris = subdivideTriangle(tris, a, b, c, g.radius/8, gradientMaxDepth)
+		} else {
+			tris = g.splitTriangle(tris, a, b, c)
+		}
+	}
+
+	imd.setLen(off + len(tris))
+	for i, v := range tris {
+		v.Color = v.Color.Mul(g.At(v.Position))
+		(*imd.tri)[off+i] = v
+	}
+}
+
+// splitTriangle appends the triangle to tris, split along the lines of the stops of a linear
+// Gradient.
+func (g *Gradient) splitTriangle(tris []vertex, a, b, c vertex) []vertex {
+	poly := []vertex{a, b, c}
+	for _, stop := range g.stops {
+		var below []vertex
+		below, poly = splitPolygon(poly, func(v vertex) float64 {
+			return g.Offset(v.Position) - stop.Offset
+		})
+		tris = appendFan(tris, below)
+	}
+	return appendFan(tris, poly)
+}
+
+// splitPolygon splits a convex polygon into the parts where the function is negative and positive.
+func splitPolygon(poly []vertex, f func(vertex) float64) (neg, pos []vertex) {
+	for i := range poly {
+		a, b := poly[i], poly[(i+1)%len(poly)]
+		fa, fb := f(a), f(b)
+		if fa <= 0 {
+			neg = append(neg, a)
+		}
+		if fa >= 0 {
+			pos = append(pos, a)
+		}
+		if (fa < 0 && fb > 0) || (fa > 0 && fb < 0) {
+			v := lerpVertex(a, b, fa/(fa-fb))
+			neg = append(neg, v)
+			pos = append(pos, v)
+		}
+	}
+	return neg, pos
+}
+
+// appendFan appends the convex polygon to tris as a triangle fan.
+func appendFan(tris []vertex, poly []vertex) []vertex {
+	for i := 1; i+1 < len(poly); i++ {
+		tris = append(tris, poly[0], poly[i], poly[i+1])
+	}
+	return tris
+}
+
+// subdivideTriangle appends the triangle to tris, recursively split into four until its edges are
+// not longer than the maximum length.
+func subdivideTriangle(tris []vertex, a, b, c vertex, maxLen float64, depth int) []vertex {
+	longest := math.Max(
+		a.Position.To(b.Position).Len(),
+		math.Max(b.Position.To(c.Position).Len(), c.Position.To(a.Position).Len()),
+	)
+	if depth == 0 || longest <= maxLen {
+		return append(tris, a, b, c)
+	}
+	ab, bc, ca := lerpVertex(a, b, 0.5), lerpVertex(b, c, 0.5), lerpVertex(c, a, 0.5)
+	tris = subdivideTriangle(tris, a, ab, ca, maxLen, depth-1)
+	tris = subdivideTriangle(tris, ab, b, bc, maxLen, depth-1)
+	tris = subdivideTriangle(tris, ca, bc, c, maxLen, depth-1)
+	return subdivideTriangle(tris, ab, bc, ca, maxLen, depth-1)
+}
@@ -0,0 +1,114 @@
+package imdraw
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Group is a retained group of shapes drawn by an IMDraw. A Group can be drawn any number of times
+// with a different Matrix and color mask, without drawing the shapes again, which is useful for
+// static scenery.
+//
+// Create a Group by drawing the shapes between BeginGroup and EndGroup:
+//
+//   imd.BeginGroup()
+//   imd.Push(pixel.V(0, 0), pixel.V(100, 0), pixel.V(50, 80))
+//   imd.Polygon(0)
+//   tree := imd.EndGroup()
+//   imd.Clear()
+//
+//   tree.Draw(win, pixel.IM.Moved(pixel.V(200, 100)))
+//   tree.Draw(win, pixel.IM.Moved(pixel.V(350, 120)))
+type Group struct {
+	orig *pixel.TrianglesData
+	tri  *pixel.TrianglesData
+	d    pixel.Drawer
+
+	matrix pixel.Matrix
+	mask   pixel.RGBA
+}
+
+// BeginGroup starts recording the shapes drawn by the IMDraw into a Group. The shapes are drawn
+// into the IMDraw as usual. Groups can be nested.
+func (imd *IMDraw) BeginGroup() {
+	imd.groups = append(imd.groups, imd.tri.Len())
+}
+
+// EndGroup returns a Group of the shapes drawn since the last call to BeginGroup. The shapes stay
+// in the IMDraw, call Clear to remove them if they should only be drawn through the Group. It
+// panics if there's no BeginGroup to end.
+//
+// The Group has the Picture of the IMDraw.
+func (imd *IMDraw) EndGroup() *Group {
+	if len(imd.groups) == 0 {
+		panic(fmt.Errorf("(%T).EndGroup: no group to end", imd))
+	}
+	last := len(imd.groups) - 1
+	off := imd.groups[last]
+	imd.groups = imd.groups[:last]
+
+	if off > imd.tri.Len() {
+		// the IMDraw was cleared in the meantime
+		off = imd.tri.Len()
+	}
+
+	orig := imd.tri.Slice(off, imd.tri.Len()).Copy().(*pixel.TrianglesData)
+	tri := orig.Copy().(*pixel.TrianglesData)
+	return &Group{
+		orig:   orig,
+		tri:    tri,
+		d:      pixel.Drawer{Triangles: tri, Picture: imd.pic},
+		matrix: pixel.IM,
+		mask:   pixel.Alpha(1),
+	}
+}
+
+// Bounds returns the smallest Rect containing all shapes of the Group, before it's transformed by
+// a Matrix.
+func (g *Group) Bounds() pixel.Rect {
+	if g.orig.Len() == 0 {
+		return pixel.Rect{}
+	}
+	min := pixel.V(math.Inf(+1), math.Inf(+1))
+	max := pixel.V(math.Inf(-1), math.Inf(-1))
+	for _, v := range *g.orig {
+		min = pixel.V(math.Min(min.X, v.Position.X), math.Min(min.Y, v.Position.Y))
+		max = pixel.V(math.Max(max.X, v.Position.X), math.Max(max.Y, v.Position.Y))
+	}
+	return pixel.Rect{Min: min, Max: max}
+}
+
+// Draw draws the Group onto the provided Target. The Group will be transformed by the given Matrix.
+//
+// This method is equivalent to calling DrawColorMask with nil color mask.
+func (g *Group) Draw(t pixel.Target, matrix pixel.Matrix) {
+	g.DrawColorMask(t, matrix, nil)
+}
+
+// DrawColorMask draws the Group onto the provided Target. The Group will be transformed by the
+// given Matrix and all of it's color will be multiplied by the given mask.
+//
+// If the mask is nil, a fully opaque white mask will be used, which causes no effect.
+func (g *Group) DrawColorMask(t pixel.Target, matrix pixel.Matrix, mask color.Color) {
+	if mask == nil {
+		mask = pixel.Alpha(1)
+	}
+	rgba := pixel.ToRGBA(mask)
+	if matrix != g.matrix || rgba != g.mask {
+		g.matrix = matrix
+		g.mask = rgba
+		g.calcData()
+	}
+	g.d.Draw(t)
+}
+
+func (g *Group) calcData() {
+	for i, v := range *g.orig {
+		(*g.tri)[i].Position = g.matrix.Project(v.Position)
+		(*g.tri)[i].Color = g.mask.Mul(v.Color)
+	}
+	g.d.Dirty()
+}
@@ -3,6 +3,7 @@
 package imdraw
 
 import (
+	"fmt"
 	"image/color"
 	"math"
 
@@ -35,6 +36,34 @@ import (
 //   - Intensity - picture intensity, only applies to filled polygons
 //   - Precision - curve drawing precision, only applies to circles and ellipses
 //   - EndShape  - shape of the end of a line, only applies to lines and outlines
+//   - LineJoin  - shape of the join of two line segments, overrides EndShape at joins
+//   - LineCap   - shape of the end of a line, overrides EndShape at ends
+//   - MiterLimit - the longest allowed MiterJoin, only applies to lines and outlines
+//   - ScreenThickness - thickness unaffected by the matrix, only applies to lines and outlines
+//   - Gradient  - color gradient multiplying Color, applies to all
+//   - PictureMapping - computes Picture from the position, only applies to filled shapes
+//   - FillRule  - which parts of the contours are inside, only applies to filled polygons
+//   - Feather   - width of the anti-aliased fringe around the shapes, applies to all
+//
+// Besides Push, points can be Pushed along curves with CurveTo and QuadTo, and the Pushed points
+// can be smoothed into a spline with CatmullRom. Shapes can also be drawn with an HTML canvas-like
+// path API, see BeginPath.
+//
+// Filled shapes can be textured with the Picture passed to New. Either set the Picture coordinates
+// of each point before Pushing it, or set PictureMapping to compute them from the positions of the
+// points (before the matrix is applied), which is handy for polygons of any shape, such as
+// terrain:
+//
+//   mapping := pixel.IM.Scaled(pixel.ZV, 0.5).Moved(pic.Bounds().Min)
+//   imd.PictureMapping = &mapping
+//   imd.Intensity = 1
+//   imd.Push(pixel.V(0, 0), pixel.V(200, 50), pixel.V(120, 180))
+//   imd.Polygon(0)
+//
+// Lines and outlines which keep their thickness regardless of the zoom, such as debug overlays or
+// editor gizmos, are drawn with ScreenThickness set. Their thickness is then in the units after
+// the matrix of the IMDraw is applied, so set the camera Matrix with SetMatrix instead of setting
+// it on the Target.
 //
 // And here's the list of all shapes that can be drawn (all, except for line, can be filled or
 // outlined):
@@ -42,8 +71,14 @@ import (
 //   - Polygon
 //   - Circle
 //   - Circle arc
+//   - Ring (always filled)
+//   - Ring arc (always filled)
 //   - Ellipse
 //   - Ellipse arc
+//   - Rotated ellipse
+//   - Rotated ellipse arc
+//
+// Drawn shapes can be retained in a Group and drawn many times, see BeginGroup.
 type IMDraw struct {
 	Color     color.Color
 	Picture   pixel.Vec
@@ -51,13 +86,41 @@ type IMDraw struct {
 	Precision int
 	EndShape  EndShape
 
-	points []point
-	pool   [][]point
-	matrix pixel.Matrix
-	mask   pixel.RGBA
+	LineJoin   LineJoin
+	LineCap    LineCap
+	MiterLimit float64
+
+	ScreenThickness bool
+
+	Gradient *Gradient
+
+	PictureMapping *pixel.Matrix
+
+	FillRule FillRule
+
+	Feather float64
 
+	points   []point
+	pool     [][]point
+	matrix   pixel.Matrix
+	matrices []pixel.Matrix
+	mask     pixel.RGBA
+
+	// the next Pushed point starts a new contour
+	newContour bool
+	// the number of shapes being drawn, only the outermost one is feathered
+	shapes int
+	// the offsets of the triangles of the groups begun by BeginGroup
+	groups []int
+
+	pic   pixel.Picture
 	tri   *pixel.TrianglesData
 	batch *pixel.Batch
+
+	// the Arena of the triangles, release is registered to be called by its Reset
+	arena     *pixel.Arena
+	release   func()
+	releasing bool
 }
 
 var _ pixel.BasicTarget = (*IMDraw)(nil)
@@ -69,6 +132,16 @@ type point struct {
 	in        float64
 	precision int
 	endshape  EndShape
+	join      LineJoin
+	cap       LineCap
+	miter     float64
+	screen    bool
+	grad      *Gradient
+	mapping   *pixel.Matrix
+	fill      FillRule
+	contour   bool
+	closed    bool
+	feather   float64
 }
 
 // EndShape specifies the shape of an end of a line or a curve.
@@ -85,12 +158,50 @@ const (
 	RoundEndShape
 )
 
+// LineJoin specifies the shape of a join between two segments of a line.
+type LineJoin int
+
+const (
+	// EndShapeJoin joins the segments according to the EndShape of the point, the same way as in
+	// the previous versions of IMDraw.
+	EndShapeJoin LineJoin = iota
+
+	// MiterJoin extends the outer edges of the segments until they meet in a sharp corner. If the
+	// corner would be longer than MiterLimit, BevelJoin is used instead.
+	MiterJoin
+
+	// RoundJoin rounds the outer corner with a circular arc.
+	RoundJoin
+
+	// BevelJoin cuts the outer corner off with a straight edge.
+	BevelJoin
+)
+
+// LineCap specifies the shape of an end of a line.
+type LineCap int
+
+const (
+	// EndShapeCap ends the line according to the EndShape of the point, the same way as in the
+	// previous versions of IMDraw.
+	EndShapeCap LineCap = iota
+
+	// ButtCap ends the line exactly at its end point.
+	ButtCap
+
+	// RoundCap ends the line with a half circle around its end point.
+	RoundCap
+
+	// SquareCap extends the line by half of its thickness beyond its end point.
+	SquareCap
+)
+
 // New creates a new empty IMDraw. An optional Picture can be used to draw with a Picture.
 //
 // If you just want to draw primitive shapes, pass nil as the Picture.
 func New(pic pixel.Picture) *IMDraw {
 	tri := &pixel.TrianglesData{}
 	im := &IMDraw{
+		pic:   pic,
 		tri:   tri,
 		batch: pixel.NewBatch(tri, pic),
 	}
@@ -116,35 +227,102 @@ func (imd *IMDraw) Reset() {
 	imd.Intensity = 0
 	imd.Precision = 64
 	imd.EndShape = NoEndShape
+	imd.LineJoin = EndShapeJoin
+	imd.LineCap = EndShapeCap
+	imd.MiterLimit = 4
+	imd.ScreenThickness = false
+	imd.Gradient = nil
+	imd.PictureMapping = nil
+	imd.FillRule = FanFill
+	imd.Feather = 0
+}
+
+// SetArena sets an Arena which the triangles of the shapes are allocated from, so that they're
+// freed together with the other geometry of the frame. Resetting the Arena clears the IMDraw, like
+// Clear. Passing nil stops allocating from the Arena.
+func (imd *IMDraw) SetArena(arena *pixel.Arena) {
+	if arena != imd.arena {
+		// register with the new Arena too, the triangles may end up in either
+		imd.releasing = false
+	}
+	imd.arena = arena
+	if imd.release == nil {
+		imd.release = func() {
+			imd.releasing = false
+			*imd.tri = nil
+			imd.batch.Dirty()
+		}
+	}
+}
+
+// Arena returns the Arena set by SetArena.
+func (imd *IMDraw) Arena() *pixel.Arena {
+	return imd.arena
+}
+
+// setLen resizes the triangles, in the Arena if there's one
+func (imd *IMDraw) setLen(len int) {
+	if imd.arena == nil {
+		imd.tri.SetLen(len)
+		return
+	}
+	imd.releaseOnReset()
+	imd.arena.SetLen(imd.tri, len)
+}
+
+// releaseOnReset makes the next Reset of the Arena clear the IMDraw
+func (imd *IMDraw) releaseOnReset() {
+	if !imd.releasing {
+		imd.arena.OnReset(imd.release)
+		imd.releasing = true
+	}
 }
 
 // Draw draws all currently drawn shapes inside the IM onto another Target.
 //
 // Note, that IMDraw's matrix and color mask have no effect here.
 func (imd *IMDraw) Draw(t pixel.Target) {
+	if imd.arena != nil && imd.tri.Len() > 0 {
+		imd.releaseOnReset()
+	}
 	imd.batch.Draw(t)
 }
 
 // Push adds some points to the IM queue. All Pushed points will have the same properties except for
 // the position.
 func (imd *IMDraw) Push(pts ...pixel.Vec) {
+	opts := imd.pointOpts()
+	for _, pt := range pts {
+		imd.pushPt(pt, opts)
+	}
+}
+
+// pointOpts returns a point with the current point properties.
+func (imd *IMDraw) pointOpts() point {
 	if _, ok := imd.Color.(pixel.RGBA); !ok {
 		imd.Color = pixel.ToRGBA(imd.Color)
 	}
-	opts := point{
+	return point{
 		col:       imd.Color.(pixel.RGBA),
 		pic:       imd.Picture,
 		in:        imd.Intensity,
 		precision: imd.Precision,
 		endshape:  imd.EndShape,
-	}
-	for _, pt := range pts {
-		imd.pushPt(pt, opts)
+		join:      imd.LineJoin,
+		cap:       imd.LineCap,
+		miter:     imd.MiterLimit,
+		screen:    imd.ScreenThickness,
+		grad:      imd.Gradient,
+		mapping:   imd.PictureMapping,
+		fill:      imd.FillRule,
+		feather:   imd.Feather,
 	}
 }
 
 func (imd *IMDraw) pushPt(pos pixel.Vec, pt point) {
 	pt.pos = pos
+	pt.contour = imd.newContour
+	imd.newContour = false
 	imd.points = append(imd.points, pt)
 }
 
@@ -154,6 +332,44 @@ func (imd *IMDraw) SetMatrix(m pixel.Matrix) {
 	imd.batch.SetMatrix(imd.matrix)
 }
 
+// Matrix returns the Matrix set by SetMatrix or PushMatrix.
+func (imd *IMDraw) Matrix() pixel.Matrix {
+	return imd.matrix
+}
+
+// PushMatrix saves the current Matrix on a stack and sets a new one, which transforms the further
+// points by the provided Matrix first and then by the current Matrix. PopMatrix restores the saved
+// Matrix.
+//
+// This makes it easy to draw hierarchical drawings, where each part is drawn relative to its
+// parent:
+//
+//   imd.PushMatrix(pixel.IM.Rotated(pixel.ZV, shoulder).Moved(body))
+//   imd.Push(pixel.ZV, pixel.V(50, 0)) // the upper arm
+//   imd.Line(8)
+//   imd.PushMatrix(pixel.IM.Rotated(pixel.ZV, elbow).Moved(pixel.V(50, 0)))
+//   imd.Push(pixel.ZV, pixel.V(40, 0)) // the forearm
+//   imd.Line(6)
+//   imd.PopMatrix()
+//   imd.PopMatrix()
+//
+// Note, that the points are transformed when a shape is drawn, not when they're Pushed.
+func (imd *IMDraw) PushMatrix(m pixel.Matrix) {
+	imd.matrices = append(imd.matrices, imd.matrix)
+	imd.SetMatrix(m.Chained(imd.matrix))
+}
+
+// PopMatrix restores the Matrix saved by the last call to PushMatrix. It panics if there's no
+// saved Matrix.
+func (imd *IMDraw) PopMatrix() {
+	if len(imd.matrices) == 0 {
+		panic(fmt.Errorf("(%T).PopMatrix: no matrix to pop", imd))
+	}
+	last := len(imd.matrices) - 1
+	imd.SetMatrix(imd.matrices[last])
+	imd.matrices = imd.matrices[:last]
+}
+
 // SetColorMask sets a color that all further point's color will be multiplied by.
 func (imd *IMDraw) SetColorMask(color color.Color) {
 	imd.mask = pixel.ToRGBA(color)
@@ -189,18 +405,28 @@ func (imd *IMDraw) Rectangle(thickness float64) {
 	}
 }
 
-// Polygon draws a polygon from the Pushed points. If the thickness is 0, the convex polygon will be
-// filled. Otherwise, an outline of the specified thickness will be drawn. The outline does not have
-// to be convex.
+// Polygon draws a polygon from the Pushed points. If the thickness is 0, the polygon will be filled
+// according to the FillRule of the first Pushed point. Otherwise, an outline of the specified
+// thickness will be drawn. The outline does not have to be convex.
 //
-// Note, that the filled polygon does not have to be strictly convex. The way it's drawn is that a
-// triangle is drawn between each two adjacent points and the first Pushed point. You can use this
-// property to draw certain kinds of concave polygons.
+// With the default FanFill, the filled polygon does not have to be strictly convex. The way it's
+// drawn is that a triangle is drawn between each two adjacent points and the first Pushed point.
+// You can use this property to draw certain kinds of concave polygons. Use EvenOddFill or
+// NonZeroFill for any other polygons, including polygons with holes made of multiple contours (see
+// NextContour).
 func (imd *IMDraw) Polygon(thickness float64) {
-	if thickness == 0 {
-		imd.fillPolygon()
-	} else {
+	multiple := len(imd.points) > 0 && hasContours(imd.points)
+	switch {
+	case thickness != 0 && multiple:
+		imd.outlineContours(thickness)
+	case thickness != 0:
 		imd.polyline(thickness, true)
+	case len(imd.points) > 0 && imd.points[0].fill != FanFill:
+		imd.fillRule(imd.points[0].fill)
+	case multiple:
+		imd.fillContours()
+	default:
+		imd.fillPolygon()
 	}
 }
 
@@ -208,26 +434,55 @@ func (imd *IMDraw) Polygon(thickness float64) {
 // the circle will be filled, otherwise a circle outline of the specified thickness will be drawn.
 func (imd *IMDraw) Circle(radius, thickness float64) {
 	if thickness == 0 {
-		imd.fillEllipseArc(pixel.V(radius, radius), 0, 2*math.Pi)
+		imd.fillEllipseArc(pixel.V(radius, radius), 0, 0, 2*math.Pi)
 	} else {
-		imd.outlineEllipseArc(pixel.V(radius, radius), 0, 2*math.Pi, thickness, false)
+		imd.outlineEllipseArc(pixel.V(radius, radius), 0, 0, 2*math.Pi, thickness, false)
 	}
 }
 
 // CircleArc draws a circle arc of the specified radius around each Pushed point. If the thickness
-// is 0, the arc will be filled, otherwise will be outlined. The arc starts at the low angle and
-// continues to the high angle. If low<high, the arc will be drawn counterclockwise. Otherwise it
-// will be clockwise. The angles are not normalized by any means.
+// is 0, the arc will be filled as a pie slice, otherwise will be outlined. The arc starts at the low
+// angle and continues to the high angle. If low<high, the arc will be drawn counterclockwise.
+// Otherwise it will be clockwise. The angles are not normalized by any means.
 //
 //   imd.CircleArc(40, 0, 8*math.Pi, 0)
 //
 // This line will fill the whole circle 4 times.
 func (imd *IMDraw) CircleArc(radius, low, high, thickness float64) {
 	if thickness == 0 {
-		imd.fillEllipseArc(pixel.V(radius, radius), low, high)
+		imd.fillEllipseArc(pixel.V(radius, radius), 0, low, high)
 	} else {
-		imd.outlineEllipseArc(pixel.V(radius, radius), low, high, thickness, true)
+		imd.outlineEllipseArc(pixel.V(radius, radius), 0, low, high, thickness, true)
+	}
+}
+
+// Ring draws a filled ring (annulus) between the inner and the outer radius around each Pushed
+// point.
+func (imd *IMDraw) Ring(inner, outer float64) {
+	imd.RingArc(inner, outer, 0, 2*math.Pi)
+}
+
+// RingArc draws a filled section of a ring (annulus) between the inner and the outer radius around
+// each Pushed point. The section starts at the low angle and continues to the high angle, just like
+// with CircleArc. Its ends are straight cuts along the radius, regardless of EndShape and LineCap.
+//
+//   imd.RingArc(30, 40, math.Pi/2, math.Pi/2-2*math.Pi*progress)
+//
+// This line draws a clockwise progress indicator, such as a cooldown, starting at the top.
+func (imd *IMDraw) RingArc(inner, outer, low, high float64) {
+	if outer < inner {
+		inner, outer = outer, inner
+	}
+	if outer == inner {
+		imd.restorePoints(imd.getAndClearPoints())
+		return
+	}
+	// the width of a ring is not a line thickness
+	for i := range imd.points {
+		imd.points[i].screen = false
 	}
+	radius := (inner + outer) / 2
+	imd.outlineEllipseArc(pixel.V(radius, radius), 0, low, high, outer-inner, false)
 }
 
 // Ellipse draws an ellipse of the specified radius in each axis around each Pushed points. If the
@@ -235,9 +490,9 @@ func (imd *IMDraw) CircleArc(radius, low, high, thickness float64) {
 // thickness will be drawn.
 func (imd *IMDraw) Ellipse(radius pixel.Vec, thickness float64) {
 	if thickness == 0 {
-		imd.fillEllipseArc(radius, 0, 2*math.Pi)
+		imd.fillEllipseArc(radius, 0, 0, 2*math.Pi)
 	} else {
-		imd.outlineEllipseArc(radius, 0, 2*math.Pi, thickness, false)
+		imd.outlineEllipseArc(radius, 0, 0, 2*math.Pi, thickness, false)
 	}
 }
 
@@ -251,13 +506,38 @@ func (imd *IMDraw) Ellipse(radius pixel.Vec, thickness float64) {
 // This line will fill the whole ellipse 4 times.
 func (imd *IMDraw) EllipseArc(radius pixel.Vec, low, high, thickness float64) {
 	if thickness == 0 {
-		imd.fillEllipseArc(radius, low, high)
+		imd.fillEllipseArc(radius, 0, low, high)
+	} else {
+		imd.outlineEllipseArc(radius, 0, low, high, thickness, true)
+	}
+}
+
+// RotatedEllipse draws an ellipse just like Ellipse, except that the ellipse is rotated by the
+// rotation angle (counterclockwise) around each Pushed point.
+//
+// The outline keeps the specified thickness all around the ellipse. That's unlike an ellipse made
+// by scaling a circle with SetMatrix, where the outline gets thicker along the longer axis.
+func (imd *IMDraw) RotatedEllipse(radius pixel.Vec, rotation, thickness float64) {
+	if thickness == 0 {
+		imd.fillEllipseArc(radius, rotation, 0, 2*math.Pi)
 	} else {
-		imd.outlineEllipseArc(radius, low, high, thickness, true)
+		imd.outlineEllipseArc(radius, rotation, 0, 2*math.Pi, thickness, false)
+	}
+}
+
+// RotatedEllipseArc draws an ellipse arc just like EllipseArc, except that the ellipse is rotated
+// by the rotation angle (counterclockwise) around each Pushed point. The low and high angles are
+// measured on the ellipse before the rotation.
+func (imd *IMDraw) RotatedEllipseArc(radius pixel.Vec, rotation, low, high, thickness float64) {
+	if thickness == 0 {
+		imd.fillEllipseArc(radius, rotation, low, high)
+	} else {
+		imd.outlineEllipseArc(radius, rotation, low, high, thickness, true)
 	}
 }
 
 func (imd *IMDraw) getAndClearPoints() []point {
+	imd.newContour = false
 	points := imd.points
 	// use one of the existing pools so we don't reallocate as often
 	if len(imd.pool) > 0 {
@@ -282,6 +562,28 @@ func (imd *IMDraw) applyMatrixAndMask(off int) {
 	}
 }
 
+// applyPictureMapping sets the Picture coordinates of the triangles starting at off to their
+// positions projected by the mapping.
+func (imd *IMDraw) applyPictureMapping(off int, mapping *pixel.Matrix) {
+	if mapping == nil {
+		return
+	}
+	for i := range (*imd.tri)[off:] {
+		(*imd.tri)[off+i].Picture = mapping.Project((*imd.tri)[off+i].Position)
+	}
+}
+
+// rotateTriangles rotates the triangles starting at off around the center by the angle.
+func (imd *IMDraw) rotateTriangles(off int, center pixel.Vec, angle float64) {
+	if angle == 0 {
+		return
+	}
+	for i := range (*imd.tri)[off:] {
+		tri := &(*imd.tri)[off+i]
+		tri.Position = center.Add(center.To(tri.Position).Rotated(angle))
+	}
+}
+
 func (imd *IMDraw) fillRectangle() {
 	points := imd.getAndClearPoints()
 
@@ -290,8 +592,8 @@ func (imd *IMDraw) fillRectangle() {
 		return
 	}
 
-	off := imd.tri.Len()
-	imd.tri.SetLen(imd.tri.Len() + 6*(len(points)-1))
+	off := imd.beginShape()
+	imd.setLen(imd.tri.Len() + 6*(len(points)-1))
 
 	for i, j := 0, off; i+1 < len(points); i, j = i+1, j+6 {
 		a, b := points[i], points[i+1]
@@ -316,7 +618,10 @@ func (imd *IMDraw) fillRectangle() {
 		}
 	}
 
+	imd.applyPictureMapping(off, points[0].mapping)
+	imd.applyGradient(off, points[0].grad)
 	imd.applyMatrixAndMask(off)
+	imd.endShape(off, points[0].feather)
 	imd.batch.Dirty()
 
 	imd.restorePoints(points)
@@ -354,8 +659,8 @@ func (imd *IMDraw) fillPolygon() {
 		return
 	}
 
-	off := imd.tri.Len()
-	imd.tri.SetLen(imd.tri.Len() + 3*(len(points)-2))
+	off := imd.beginShape()
+	imd.setLen(imd.tri.Len() + 3*(len(points)-2))
 
 	for i, j := 1, off; i+1 < len(points); i, j = i+1, j+3 {
 		for k, p := range [...]int{0, i, i + 1} {
@@ -367,26 +672,32 @@ func (imd *IMDraw) fillPolygon() {
 		}
 	}
 
+	imd.applyPictureMapping(off, points[0].mapping)
+	imd.applyGradient(off, points[0].grad)
 	imd.applyMatrixAndMask(off)
+	imd.endShape(off, points[0].feather)
 	imd.batch.Dirty()
 
 	imd.restorePoints(points)
 }
 
-func (imd *IMDraw) fillEllipseArc(radius pixel.Vec, low, high float64) {
+func (imd *IMDraw) fillEllipseArc(radius pixel.Vec, rotation, low, high float64) {
 	points := imd.getAndClearPoints()
 
 	for _, pt := range points {
 		num := math.Ceil(math.Abs(high-low) / (2 * math.Pi) * float64(pt.precision))
 		delta := (high - low) / num
 
-		off := imd.tri.Len()
-		imd.tri.SetLen(imd.tri.Len() + 3*int(num))
+		off := imd.beginShape()
+		imd.setLen(imd.tri.Len() + 3*int(num))
 
 		for i := range (*imd.tri)[off:] {
 			(*imd.tri)[off+i].Color = pt.col
 			(*imd.tri)[off+i].Picture = pixel.ZV
 			(*imd.tri)[off+i].Intensity = 0
+			if pt.mapping != nil {
+				(*imd.tri)[off+i].Intensity = pt.in
+			}
 		}
 
 		for i, j := 0.0, off; i < num; i, j = i+1, j+3 {
@@ -409,22 +720,38 @@ func (imd *IMDraw) fillEllipseArc(radius pixel.Vec, low, high float64) {
 			(*imd.tri)[j+2].Position = b
 		}
 
+		imd.rotateTriangles(off, pt.pos, rotation)
+		imd.applyPictureMapping(off, pt.mapping)
+		imd.applyGradient(off, pt.grad)
 		imd.applyMatrixAndMask(off)
+		imd.endShape(off, pt.feather)
 		imd.batch.Dirty()
 	}
 
 	imd.restorePoints(points)
 }
 
-func (imd *IMDraw) outlineEllipseArc(radius pixel.Vec, low, high, thickness float64, doEndShape bool) {
+func (imd *IMDraw) outlineEllipseArc(radius pixel.Vec, rotation, low, high, thickness float64, doEndShape bool) {
 	points := imd.getAndClearPoints()
 
 	for _, pt := range points {
 		num := math.Ceil(math.Abs(high-low) / (2 * math.Pi) * float64(pt.precision))
 		delta := (high - low) / num
 
-		off := imd.tri.Len()
-		imd.tri.SetLen(imd.tri.Len() + 6*int(num))
+		if pt.screen {
+			// the outline must be built after the matrix is applied, so it's drawn as a line
+			closed := math.Abs(high-low) >= 2*math.Pi
+			for i := 0.0; i < num || (!closed && i == num); i++ {
+				sin, cos := math.Sincos(low + i*delta)
+				offset := pixel.V(radius.X*cos, radius.Y*sin).Rotated(rotation)
+				imd.pushPt(pt.pos.Add(offset), pt)
+			}
+			imd.polyline(thickness, closed)
+			continue
+		}
+
+		off := imd.beginShape()
+		imd.setLen(imd.tri.Len() + 6*int(num))
 
 		for i := range (*imd.tri)[off:] {
 			(*imd.tri)[off+i].Color = pt.col
@@ -465,6 +792,8 @@ func (imd *IMDraw) outlineEllipseArc(radius pixel.Vec, low, high, thickness floa
 			(*imd.tri)[j+5].Position = d
 		}
 
+		imd.rotateTriangles(off, pt.pos, rotation)
+		imd.applyGradient(off, pt.grad)
 		imd.applyMatrixAndMask(off)
 		imd.batch.Dirty()
 
@@ -475,7 +804,7 @@ func (imd *IMDraw) outlineEllipseArc(radius pixel.Vec, low, high, thickness floa
 				radius.Y*lowSin,
 			))
 			normalLowSin, normalLowCos := pixel.V(lowSin, lowCos).ScaledXY(radius).Unit().XY()
-			normalLow := pixel.V(normalLowCos, normalLowSin).Angle()
+			normalLow := pixel.V(normalLowCos, normalLowSin).Angle() + rotation
 
 			highSin, highCos := math.Sincos(high)
 			highCenter := pt.pos.Add(pixel.V(
@@ -483,34 +812,25 @@ func (imd *IMDraw) outlineEllipseArc(radius pixel.Vec, low, high, thickness floa
 				radius.Y*highSin,
 			))
 			normalHighSin, normalHighCos := pixel.V(highSin, highCos).ScaledXY(radius).Unit().XY()
-			normalHigh := pixel.V(normalHighCos, normalHighSin).Angle()
+			normalHigh := pixel.V(normalHighCos, normalHighSin).Angle() + rotation
 
 			orientation := 1.0
 			if low > high {
 				orientation = -1.0
 			}
 
-			switch pt.endshape {
-			case NoEndShape:
-				// nothing
-			case SharpEndShape:
-				thick := pixel.V(thickness/2, 0).Rotated(normalLow)
-				imd.pushPt(lowCenter.Add(thick), pt)
-				imd.pushPt(lowCenter.Sub(thick), pt)
-				imd.pushPt(lowCenter.Sub(thick.Normal().Scaled(orientation)), pt)
-				imd.fillPolygon()
-				thick = pixel.V(thickness/2, 0).Rotated(normalHigh)
-				imd.pushPt(highCenter.Add(thick), pt)
-				imd.pushPt(highCenter.Sub(thick), pt)
-				imd.pushPt(highCenter.Add(thick.Normal().Scaled(orientation)), pt)
-				imd.fillPolygon()
-			case RoundEndShape:
-				imd.pushPt(lowCenter, pt)
-				imd.fillEllipseArc(pixel.V(thickness/2, thickness/2), normalLow, normalLow-math.Pi*orientation)
-				imd.pushPt(highCenter, pt)
-				imd.fillEllipseArc(pixel.V(thickness/2, thickness/2), normalHigh, normalHigh+math.Pi*orientation)
-			}
+			lowCenter = pt.pos.Add(pt.pos.To(lowCenter).Rotated(rotation))
+			highCenter = pt.pos.Add(pt.pos.To(highCenter).Rotated(rotation))
+
+			thick := pixel.V(thickness/2, 0).Rotated(normalLow)
+			pt.pos = lowCenter
+			imd.lineCap(pt, thick, thick.Normal().Scaled(-orientation), thickness)
+			thick = pixel.V(thickness/2, 0).Rotated(normalHigh)
+			pt.pos = highCenter
+			imd.lineCap(pt, thick, thick.Normal().Scaled(orientation), thickness)
 		}
+
+		imd.endShape(off, pt.feather)
 	}
 
 	imd.restorePoints(points)
@@ -528,23 +848,27 @@ func (imd *IMDraw) polyline(thickness float64, closed bool) {
 		points = append(points, points[0])
 	}
 
+	if points[0].screen {
+		// build the line after the matrix is applied, so that the thickness is not transformed
+		matrix := imd.matrix
+		for i := range points {
+			points[i].pos = matrix.Project(points[i].pos)
+		}
+		imd.matrix = pixel.IM
+		defer func() {
+			imd.matrix = matrix
+		}()
+	}
+
+	// the segments, joins and caps are feathered together
+	off := imd.beginShape()
+
 	// first point
 	j, i := 0, 1
 	ijNormal := points[0].pos.To(points[1].pos).Normal().Unit().Scaled(thickness / 2)
 
 	if !closed {
-		switch points[j].endshape {
-		case NoEndShape:
-			// nothing
-		case SharpEndShape:
-			imd.pushPt(points[j].pos.Add(ijNormal), points[j])
-			imd.pushPt(points[j].pos.Sub(ijNormal), points[j])
-			imd.pushPt(points[j].pos.Add(ijNormal.Normal()), points[j])
-			imd.fillPolygon()
-		case RoundEndShape:
-			imd.pushPt(points[j].pos, points[j])
-			imd.fillEllipseArc(pixel.V(thickness/2, thickness/2), ijNormal.Angle(), ijNormal.Angle()+math.Pi)
-		}
+		imd.lineCap(points[j], ijNormal, ijNormal.Normal(), thickness)
 	}
 
 	imd.pushPt(points[j].pos.Add(ijNormal), points[j])
@@ -577,20 +901,7 @@ func (imd *IMDraw) polyline(thickness float64, closed bool) {
 		imd.pushPt(points[j].pos.Add(ijNormal), points[j])
 		imd.fillPolygon()
 
-		switch points[j].endshape {
-		case NoEndShape:
-			// nothing
-		case SharpEndShape:
-			imd.pushPt(points[j].pos, points[j])
-			imd.pushPt(points[j].pos.Add(ijNormal.Scaled(orientation)), points[j])
-			imd.pushPt(points[j].pos.Add(jkNormal.Scaled(orientation)), points[j])
-			imd.fillPolygon()
-		case RoundEndShape:
-			imd.pushPt(points[j].pos, points[j])
-			imd.fillEllipseArc(pixel.V(thickness/2, thickness/2), ijNormal.Angle(), ijNormal.Angle()-math.Pi)
-			imd.pushPt(points[j].pos, points[j])
-			imd.fillEllipseArc(pixel.V(thickness/2, thickness/2), jkNormal.Angle(), jkNormal.Angle()+math.Pi)
-		}
+		imd.lineJoin(points[j], ijNormal, jkNormal, orientation, thickness)
 
 		if !closing {
 			imd.pushPt(points[j].pos.Add(jkNormal), points[j])
@@ -609,19 +920,111 @@ func (imd *IMDraw) polyline(thickness float64, closed bool) {
 	imd.fillPolygon()
 
 	if !closed {
-		switch points[j].endshape {
+		imd.lineCap(points[j], ijNormal, ijNormal.Normal().Scaled(-1), thickness)
+	}
+
+	imd.endShape(off, points[0].feather)
+
+	imd.restorePoints(points)
+}
+
+// lineCap draws the end of a line at the point. The normal is perpendicular to the line and the
+// outward vector points away from the line, both have the length of half of the thickness.
+func (imd *IMDraw) lineCap(pt point, normal, outward pixel.Vec, thickness float64) {
+	lineCap := pt.cap
+	if lineCap == EndShapeCap {
+		switch pt.endshape {
 		case NoEndShape:
-			// nothing
+			lineCap = ButtCap
 		case SharpEndShape:
-			imd.pushPt(points[j].pos.Add(ijNormal), points[j])
-			imd.pushPt(points[j].pos.Sub(ijNormal), points[j])
-			imd.pushPt(points[j].pos.Add(ijNormal.Normal().Scaled(-1)), points[j])
+			imd.pushPt(pt.pos.Add(normal), pt)
+			imd.pushPt(pt.pos.Sub(normal), pt)
+			imd.pushPt(pt.pos.Add(outward), pt)
 			imd.fillPolygon()
+			return
 		case RoundEndShape:
-			imd.pushPt(points[j].pos, points[j])
-			imd.fillEllipseArc(pixel.V(thickness/2, thickness/2), ijNormal.Angle(), ijNormal.Angle()-math.Pi)
+			lineCap = RoundCap
 		}
 	}
 
-	imd.restorePoints(points)
+	switch lineCap {
+	case ButtCap:
+		// nothing
+	case RoundCap:
+		// the half circle goes from the normal over the outward vector to the opposite side
+		orientation := 1.0
+		if normal.Cross(outward) < 0 {
+			orientation = -1.0
+		}
+		imd.pushPt(pt.pos, pt)
+		imd.fillEllipseArc(pixel.V(thickness/2, thickness/2), 0, normal.Angle(), normal.Angle()+math.Pi*orientation)
+	case SquareCap:
+		imd.pushPt(pt.pos.Add(normal), pt)
+		imd.pushPt(pt.pos.Sub(normal), pt)
+		imd.pushPt(pt.pos.Sub(normal).Add(outward), pt)
+		imd.pushPt(pt.pos.Add(normal).Add(outward), pt)
+		imd.fillPolygon()
+	}
+}
+
+// lineJoin fills the gap on the outer side of the join of two line segments at the point. The
+// normals of the segments have the length of half of the thickness and the orientation turns them
+// to the outer side.
+func (imd *IMDraw) lineJoin(pt point, ijNormal, jkNormal pixel.Vec, orientation, thickness float64) {
+	join := pt.join
+	if join == EndShapeJoin {
+		switch pt.endshape {
+		case NoEndShape:
+			return
+		case SharpEndShape:
+			join = BevelJoin
+		case RoundEndShape:
+			imd.pushPt(pt.pos, pt)
+			imd.fillEllipseArc(pixel.V(thickness/2, thickness/2), 0, ijNormal.Angle(), ijNormal.Angle()-math.Pi)
+			imd.pushPt(pt.pos, pt)
+			imd.fillEllipseArc(pixel.V(thickness/2, thickness/2), 0, jkNormal.Angle(), jkNormal.Angle()+math.Pi)
+			return
+		}
+	}
+
+	a, b := ijNormal.Scaled(orientation), jkNormal.Scaled(orientation)
+
+	switch join {
+	case MiterJoin:
+		// the ratio of the miter length to the thickness is 1/cos of half of the angle between the
+		// normals
+		bisector := a.Add(b)
+		cos := bisector.Unit().Dot(a.Unit())
+		if bisector.Len() > thickness*1e-9 && cos > 0 && 1/cos <= pt.miter {
+			imd.pushPt(pt.pos, pt)
+			imd.pushPt(pt.pos.Add(a), pt)
+			imd.pushPt(pt.pos.Add(bisector.Unit().Scaled(thickness/2/cos)), pt)
+			imd.pushPt(pt.pos.Add(b), pt)
+			imd.fillPolygon()
+			return
+		}
+		// too long, fall back to bevel
+		imd.pushPt(pt.pos, pt)
+		imd.pushPt(pt.pos.Add(a), pt)
+		imd.pushPt(pt.pos.Add(b), pt)
+		imd.fillPolygon()
+	case RoundJoin:
+		low := a.Angle()
+		delta := b.Angle() - low
+		for delta > math.Pi {
+			delta -= 2 * math.Pi
+		}
+		for delta < -math.Pi {
+			delta += 2 * math.Pi
+		}
+		if delta != 0 {
+			imd.pushPt(pt.pos, pt)
+			imd.fillEllipseArc(pixel.V(thickness/2, thickness/2), 0, low, low+delta)
+		}
+	case BevelJoin:
+		imd.pushPt(pt.pos, pt)
+		imd.pushPt(pt.pos.Add(a), pt)
+		imd.pushPt(pt.pos.Add(b), pt)
+		imd.fillPolygon()
+	}
 }
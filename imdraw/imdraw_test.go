@@ -2,11 +2,14 @@ package imdraw_test
 
 import (
 	"fmt"
+	"image/color"
+	"math"
 	"math/rand"
 	"testing"
 
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/raster"
 )
 
 func BenchmarkPush(b *testing.B) {
@@ -94,3 +97,597 @@ func BenchmarkEllipseOutline(b *testing.B) {
 		})
 	}
 }
+
+func drawCorner(join imdraw.LineJoin, lineCap imdraw.LineCap) *raster.Canvas {
+	imd := imdraw.New(nil)
+	imd.LineJoin = join
+	imd.LineCap = lineCap
+	imd.Push(pixel.V(10, 50), pixel.V(50, 50), pixel.V(50, 10))
+	imd.Line(10)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 64, 64))
+	imd.Draw(c)
+	return c
+}
+
+func TestLineJoin(t *testing.T) {
+	outerCorner := pixel.V(54, 54)
+	tests := []struct {
+		join   imdraw.LineJoin
+		filled bool
+	}{
+		{imdraw.MiterJoin, true},
+		{imdraw.BevelJoin, false},
+		{imdraw.RoundJoin, false},
+	}
+	for _, tt := range tests {
+		c := drawCorner(tt.join, imdraw.ButtCap)
+		if filled := c.Color(outerCorner).A > 0; filled != tt.filled {
+			t.Errorf("join %v: outer corner filled = %v, want %v", tt.join, filled, tt.filled)
+		}
+		// the outer side of the join must be covered without gaps for all joins
+		if c.Color(pixel.V(51, 51)).A == 0 {
+			t.Errorf("join %v: gap at the join", tt.join)
+		}
+	}
+}
+
+func TestLineMiterLimit(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.LineJoin = imdraw.MiterJoin
+	imd.MiterLimit = 1.2 // a right angle miter has a ratio of sqrt(2)
+	imd.Push(pixel.V(10, 50), pixel.V(50, 50), pixel.V(50, 10))
+	imd.Line(10)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 64, 64))
+	imd.Draw(c)
+	if c.Color(pixel.V(54, 54)).A > 0 {
+		t.Errorf("miter exceeding the limit was not beveled")
+	}
+}
+
+func TestLineCap(t *testing.T) {
+	beyondStart := pixel.V(7, 50)
+	tests := []struct {
+		cap    imdraw.LineCap
+		filled bool
+	}{
+		{imdraw.ButtCap, false},
+		{imdraw.SquareCap, true},
+		{imdraw.RoundCap, true},
+	}
+	for _, tt := range tests {
+		c := drawCorner(imdraw.BevelJoin, tt.cap)
+		if filled := c.Color(beyondStart).A > 0; filled != tt.filled {
+			t.Errorf("cap %v: pixel beyond the start filled = %v, want %v", tt.cap, filled, tt.filled)
+		}
+	}
+	// a round cap doesn't cover the corners of a square cap
+	c := drawCorner(imdraw.BevelJoin, imdraw.RoundCap)
+	if c.Color(pixel.V(5, 54)).A > 0 {
+		t.Errorf("round cap covers the corner of the square")
+	}
+}
+
+func TestRing(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.Push(pixel.V(32, 32))
+	imd.Ring(10, 20)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 64, 64))
+	imd.Draw(c)
+	if c.Color(pixel.V(32, 32)).A > 0 {
+		t.Errorf("ring center is filled")
+	}
+	if c.Color(pixel.V(32+15, 32)).A == 0 || c.Color(pixel.V(32, 32-15)).A == 0 {
+		t.Errorf("ring is not filled between the radii")
+	}
+	if c.Color(pixel.V(32+22, 32)).A > 0 {
+		t.Errorf("ring is filled beyond the outer radius")
+	}
+}
+
+func TestRingArc(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.Push(pixel.V(32, 32))
+	imd.RingArc(10, 20, 0, math.Pi/2) // the top right quarter
+
+	c := raster.NewCanvas(pixel.R(0, 0, 64, 64))
+	imd.Draw(c)
+	if c.Color(pixel.V(32+10, 32+10)).A == 0 {
+		t.Errorf("ring arc is not filled inside its angle range")
+	}
+	if c.Color(pixel.V(32-15, 32)).A > 0 || c.Color(pixel.V(32, 32-15)).A > 0 {
+		t.Errorf("ring arc is filled outside its angle range")
+	}
+}
+
+func TestCurveTo(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.Push(pixel.V(0, 0))
+	imd.CurveTo(pixel.V(0, 100), pixel.V(100, 100), pixel.V(100, 0))
+	imd.Line(4)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 128, 128))
+	imd.Draw(c)
+	// the middle of this curve is at (50, 75)
+	if c.Color(pixel.V(50, 75)).A == 0 {
+		t.Errorf("curve doesn't pass through its middle point")
+	}
+	if c.Color(pixel.V(50, 50)).A > 0 {
+		t.Errorf("curve is drawn as a straight line")
+	}
+}
+
+func TestCatmullRom(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.EndShape = imdraw.RoundEndShape
+	pts := []pixel.Vec{pixel.V(10, 10), pixel.V(50, 80), pixel.V(90, 10), pixel.V(120, 80)}
+	imd.Push(pts...)
+	imd.CatmullRom(false)
+	imd.Line(4)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 128, 128))
+	imd.Draw(c)
+	for _, pt := range pts {
+		if c.Color(pt).A == 0 {
+			t.Errorf("spline doesn't pass through %v", pt)
+		}
+	}
+}
+
+func TestLinearGradient(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.Gradient = imdraw.LinearGradient(pixel.V(0, 0), pixel.V(100, 0),
+		imdraw.GradientStop{Offset: 0, Color: pixel.RGB(1, 0, 0)},
+		imdraw.GradientStop{Offset: 0.5, Color: pixel.RGB(0, 1, 0)},
+		imdraw.GradientStop{Offset: 1, Color: pixel.RGB(0, 0, 1)},
+	)
+	imd.Push(pixel.V(0, 0), pixel.V(100, 10))
+	imd.Rectangle(0)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 100, 10))
+	imd.Draw(c)
+	// the middle stop is only exact if the rectangle was split along it
+	if got := c.Color(pixel.V(49.5, 5)); got.G < 0.95 || got.R > 0.05 || got.B > 0.05 {
+		t.Errorf("color at the middle stop = %v, want green", got)
+	}
+	if got := c.Color(pixel.V(0, 5)); got.R < 0.95 {
+		t.Errorf("color at the start = %v, want red", got)
+	}
+}
+
+func TestRadialGradient(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.Gradient = imdraw.RadialGradient(pixel.V(32, 32), 30,
+		imdraw.GradientStop{Offset: 0, Color: pixel.RGB(1, 1, 1)},
+		imdraw.GradientStop{Offset: 1, Color: pixel.RGB(0, 0, 0)},
+	)
+	imd.Push(pixel.V(32, 32))
+	imd.Circle(30, 0)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 64, 64))
+	imd.Draw(c)
+	center, middle := c.Color(pixel.V(32, 32)), c.Color(pixel.V(32+15, 32))
+	if center.R < 0.9 {
+		t.Errorf("color at the center = %v, want white", center)
+	}
+	if middle.R < 0.4 || middle.R > 0.6 {
+		t.Errorf("color at half of the radius = %v, want gray", middle)
+	}
+}
+
+func TestGradientColor(t *testing.T) {
+	g := imdraw.LinearGradient(pixel.V(0, 0), pixel.V(10, 0),
+		imdraw.GradientStop{Offset: 1, Color: pixel.RGB(0, 0, 1)},
+		imdraw.GradientStop{Offset: 0, Color: pixel.RGB(1, 0, 0)},
+	)
+	tests := []struct {
+		at   pixel.Vec
+		want pixel.RGBA
+	}{
+		{pixel.V(-5, 3), pixel.RGB(1, 0, 0)},
+		{pixel.V(5, -3), pixel.RGB(0.5, 0, 0.5)},
+		{pixel.V(20, 0), pixel.RGB(0, 0, 1)},
+	}
+	for _, tt := range tests {
+		if got := g.At(tt.at); got != tt.want {
+			t.Errorf("At(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestPictureMapping(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 1))
+	pic.Pix[0] = color.RGBA{255, 0, 0, 255}
+	pic.Pix[1] = color.RGBA{0, 0, 255, 255}
+
+	imd := imdraw.New(pic)
+	mapping := pixel.IM.ScaledXY(pixel.ZV, pixel.V(0.1, 0.1))
+	imd.PictureMapping = &mapping
+	imd.Intensity = 1
+	imd.Push(pixel.V(0, 0), pixel.V(20, 0), pixel.V(20, 10), pixel.V(0, 10))
+	imd.Polygon(0)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 20, 10))
+	imd.Draw(c)
+	if got := c.Color(pixel.V(4, 5)); got != pixel.RGB(1, 0, 0) {
+		t.Errorf("color on the left = %v, want red", got)
+	}
+	if got := c.Color(pixel.V(16, 5)); got != pixel.RGB(0, 0, 1) {
+		t.Errorf("color on the right = %v, want blue", got)
+	}
+}
+
+func drawSquares(rule imdraw.FillRule, reverseHole bool) *raster.Canvas {
+	imd := imdraw.New(nil)
+	imd.FillRule = rule
+	imd.Push(pixel.V(0, 0), pixel.V(30, 0), pixel.V(30, 30), pixel.V(0, 30))
+	imd.NextContour()
+	hole := []pixel.Vec{pixel.V(10, 10), pixel.V(20, 10), pixel.V(20, 20), pixel.V(10, 20)}
+	if reverseHole {
+		hole[1], hole[3] = hole[3], hole[1]
+	}
+	imd.Push(hole...)
+	imd.Polygon(0)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 30, 30))
+	imd.Draw(c)
+	return c
+}
+
+func TestFillRule(t *testing.T) {
+	tests := []struct {
+		rule        imdraw.FillRule
+		reverseHole bool
+		hole        bool
+	}{
+		{imdraw.EvenOddFill, false, true},
+		{imdraw.EvenOddFill, true, true},
+		{imdraw.NonZeroFill, false, false},
+		{imdraw.NonZeroFill, true, true},
+	}
+	for _, tt := range tests {
+		c := drawSquares(tt.rule, tt.reverseHole)
+		if got := c.Color(pixel.V(5, 15)).A; got != 1 {
+			t.Errorf("rule %d, reversed %v: alpha in the ring = %v, want 1", tt.rule, tt.reverseHole, got)
+		}
+		want := 1.0
+		if tt.hole {
+			want = 0
+		}
+		if got := c.Color(pixel.V(15, 15)).A; got != want {
+			t.Errorf("rule %d, reversed %v: alpha in the hole = %v, want %v", tt.rule, tt.reverseHole, got, want)
+		}
+	}
+}
+
+func TestFillRuleSelfIntersecting(t *testing.T) {
+	// a pentagram, its center is wound around twice
+	var star []pixel.Vec
+	for i := 0; i < 5; i++ {
+		star = append(star, pixel.V(50, 50).Add(pixel.V(0, 40).Rotated(float64(i)*4*math.Pi/5)))
+	}
+
+	for _, rule := range []imdraw.FillRule{imdraw.EvenOddFill, imdraw.NonZeroFill} {
+		imd := imdraw.New(nil)
+		imd.FillRule = rule
+		imd.Push(star...)
+		imd.Polygon(0)
+
+		c := raster.NewCanvas(pixel.R(0, 0, 100, 100))
+		imd.Draw(c)
+
+		want := 1.0
+		if rule == imdraw.EvenOddFill {
+			want = 0
+		}
+		if got := c.Color(pixel.V(50, 50)).A; got != want {
+			t.Errorf("rule %d: alpha in the center = %v, want %v", rule, got, want)
+		}
+		if got := c.Color(pixel.V(50, 85)).A; got != 1 {
+			t.Errorf("rule %d: alpha in a tip = %v, want 1", rule, got)
+		}
+	}
+}
+
+func TestFeather(t *testing.T) {
+	for _, shape := range []string{"rectangle", "polygon", "line"} {
+		imd := imdraw.New(nil)
+		imd.Feather = 1
+		switch shape {
+		case "rectangle":
+			imd.Push(pixel.V(10, 10), pixel.V(20, 20))
+			imd.Rectangle(0)
+		case "polygon":
+			imd.FillRule = imdraw.EvenOddFill
+			imd.Push(pixel.V(10, 10), pixel.V(20, 10), pixel.V(20, 20), pixel.V(10, 20))
+			imd.Polygon(0)
+		case "line":
+			imd.Push(pixel.V(10, 15), pixel.V(20, 15))
+			imd.Line(10)
+		}
+
+		c := raster.NewCanvas(pixel.R(0, 0, 30, 30))
+		imd.Draw(c)
+
+		tests := []struct {
+			at   pixel.Vec
+			want float64
+		}{
+			{pixel.V(15, 15), 1},
+			{pixel.V(10.5, 15), 1},
+			{pixel.V(9.5, 15), 0.5},
+			{pixel.V(15, 20.5), 0.5},
+			{pixel.V(8.5, 15), 0},
+		}
+		for _, tt := range tests {
+			if got := c.Color(tt.at).A; math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("%s: alpha at %v = %v, want %v", shape, tt.at, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestFeatherHole(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.Color = pixel.Alpha(0.5)
+	imd.Feather = 1
+	imd.FillRule = imdraw.EvenOddFill
+	imd.Push(pixel.V(0, 0), pixel.V(30, 0), pixel.V(30, 30), pixel.V(0, 30))
+	imd.NextContour()
+	imd.Push(pixel.V(10, 10), pixel.V(20, 10), pixel.V(20, 20), pixel.V(10, 20))
+	imd.Polygon(0)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 30, 30))
+	imd.Draw(c)
+
+	// no fringe must be drawn inside of the shape, where the trapezoids of the fill meet
+	for _, at := range []pixel.Vec{pixel.V(5, 9.5), pixel.V(5, 10.5), pixel.V(25, 19.5)} {
+		if got := c.Color(at).A; math.Abs(got-0.5) > 0.01 {
+			t.Errorf("alpha at %v = %v, want 0.5", at, got)
+		}
+	}
+	if got := c.Color(pixel.V(15, 10.5)).A; math.Abs(got-0.25) > 0.01 {
+		t.Errorf("alpha at the edge of the hole = %v, want 0.25", got)
+	}
+}
+
+func TestRotatedEllipse(t *testing.T) {
+	center := pixel.V(32, 32)
+	tests := []struct {
+		thickness float64
+		filled    []pixel.Vec
+		empty     []pixel.Vec
+	}{
+		{0, []pixel.Vec{pixel.V(0, 18), pixel.V(0, -18), pixel.V(4, 0)}, []pixel.Vec{pixel.V(18, 0), pixel.V(0, 22)}},
+		{2, []pixel.Vec{pixel.V(0, 19.5), pixel.V(5.5, 0)}, []pixel.Vec{pixel.V(0, 17), pixel.V(0, 0), pixel.V(18, 0)}},
+	}
+	for _, tt := range tests {
+		imd := imdraw.New(nil)
+		imd.Push(center)
+		imd.RotatedEllipse(pixel.V(20, 5), math.Pi/2, tt.thickness)
+
+		c := raster.NewCanvas(pixel.R(0, 0, 64, 64))
+		imd.Draw(c)
+		for _, at := range tt.filled {
+			if got := c.Color(center.Add(at)).A; got != 1 {
+				t.Errorf("thickness %v: alpha at %v = %v, want 1", tt.thickness, at, got)
+			}
+		}
+		for _, at := range tt.empty {
+			if got := c.Color(center.Add(at)).A; got != 0 {
+				t.Errorf("thickness %v: alpha at %v = %v, want 0", tt.thickness, at, got)
+			}
+		}
+	}
+}
+
+func TestRotatedEllipseArc(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.EndShape = imdraw.RoundEndShape
+	imd.Push(pixel.V(32, 32))
+	// the upper half of the ellipse, rotated to the left half
+	imd.RotatedEllipseArc(pixel.V(20, 10), math.Pi/2, 0, math.Pi, 2)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 64, 64))
+	imd.Draw(c)
+	if got := c.Color(pixel.V(32-10, 32)).A; got != 1 {
+		t.Errorf("alpha in the middle of the arc = %v, want 1", got)
+	}
+	if got := c.Color(pixel.V(32+10, 32)).A; got != 0 {
+		t.Errorf("alpha in the missing half = %v, want 0", got)
+	}
+	// the round end shape sticks out at the end of the arc
+	if got := c.Color(pixel.V(32.5, 32+20)).A; got != 1 {
+		t.Errorf("alpha at the end of the arc = %v, want 1", got)
+	}
+}
+
+func TestPushMatrix(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.SetMatrix(pixel.IM.Moved(pixel.V(10, 0)))
+	imd.PushMatrix(pixel.IM.Rotated(pixel.ZV, math.Pi/2))
+	imd.PushMatrix(pixel.IM.Moved(pixel.V(5, 0)))
+
+	want := pixel.V(10, 5)
+	if got := imd.Matrix().Project(pixel.ZV); got.To(want).Len() > 1e-9 {
+		t.Errorf("nested matrix projects the origin to %v, want %v", got, want)
+	}
+
+	imd.PopMatrix()
+	imd.PopMatrix()
+	if got := imd.Matrix(); got != pixel.IM.Moved(pixel.V(10, 0)) {
+		t.Errorf("matrix after popping = %v, want the original one", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("PopMatrix with an empty stack didn't panic")
+		}
+	}()
+	imd.PopMatrix()
+}
+
+func TestPath(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.MoveTo(pixel.V(10, 10))
+	imd.LineTo(pixel.V(50, 10))
+	imd.LineTo(pixel.V(50, 50))
+	imd.LineTo(pixel.V(10, 50))
+	imd.ClosePath()
+	// a hole going the other way
+	imd.MoveTo(pixel.V(20, 20))
+	imd.LineTo(pixel.V(20, 40))
+	imd.LineTo(pixel.V(40, 40))
+	imd.LineTo(pixel.V(40, 20))
+	imd.ClosePath()
+
+	// the points have already taken their color, this doesn't change it
+	imd.Color = pixel.RGB(1, 0, 0)
+	imd.Fill()
+	c := raster.NewCanvas(pixel.R(0, 0, 60, 60))
+	imd.Draw(c)
+	if got := c.Color(pixel.V(15, 30)); got != pixel.RGB(1, 1, 1) {
+		t.Errorf("filled color = %v, want the color of the points", got)
+	}
+	if got := c.Color(pixel.V(30, 30)).A; got != 0 {
+		t.Errorf("alpha in the hole = %v, want 0", got)
+	}
+
+	// the path is kept, stroke it too
+	imd.Clear()
+	imd.Stroke(2)
+	c.Clear(pixel.Alpha(0))
+	imd.Draw(c)
+	for _, at := range []pixel.Vec{pixel.V(30, 10.5), pixel.V(10.5, 30), pixel.V(30, 39.5)} {
+		if got := c.Color(at).A; got != 1 {
+			t.Errorf("alpha on the stroke at %v = %v, want 1", at, got)
+		}
+	}
+	if got := c.Color(pixel.V(15, 30)).A; got != 0 {
+		t.Errorf("alpha inside the stroke = %v, want 0", got)
+	}
+
+	imd.BeginPath()
+	imd.Clear()
+	imd.Fill()
+	imd.Stroke(2)
+	c.Clear(pixel.Alpha(0))
+	imd.Draw(c)
+	if got := c.Color(pixel.V(30, 10.5)).A; got != 0 {
+		t.Errorf("alpha after BeginPath = %v, want 0", got)
+	}
+}
+
+func TestPathArc(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.MoveTo(pixel.V(30, 30))
+	imd.Arc(pixel.V(30, 30), 20, 0, math.Pi/2)
+	imd.Fill()
+
+	c := raster.NewCanvas(pixel.R(0, 0, 60, 60))
+	imd.Draw(c)
+	if got := c.Color(pixel.V(40, 40)).A; got != 1 {
+		t.Errorf("alpha inside the pie = %v, want 1", got)
+	}
+	if got := c.Color(pixel.V(20, 40)).A; got != 0 {
+		t.Errorf("alpha outside the pie = %v, want 0", got)
+	}
+}
+
+func TestScreenThickness(t *testing.T) {
+	for _, screen := range []bool{false, true} {
+		imd := imdraw.New(nil)
+		imd.SetMatrix(pixel.IM.Scaled(pixel.ZV, 4))
+		imd.ScreenThickness = screen
+		imd.Push(pixel.V(2, 4), pixel.V(12, 4))
+		imd.Line(2)
+		imd.Push(pixel.V(8, 10))
+		imd.Circle(4, 2)
+
+		c := raster.NewCanvas(pixel.R(0, 0, 64, 64))
+		imd.Draw(c)
+
+		// the line is 8 units thick when scaled and 2 units thick otherwise
+		want := 1.0
+		if screen {
+			want = 0
+		}
+		if got := c.Color(pixel.V(20, 16+2.5)).A; got != want {
+			t.Errorf("screen %v: alpha 2.5 units from the line = %v, want %v", screen, got, want)
+		}
+		if got := c.Color(pixel.V(32+16+2.5, 40)).A; got != want {
+			t.Errorf("screen %v: alpha 2.5 units from the circle = %v, want %v", screen, got, want)
+		}
+		if got := c.Color(pixel.V(20, 16.5)).A; got != 1 {
+			t.Errorf("screen %v: alpha on the line = %v, want 1", screen, got)
+		}
+		if got := c.Color(pixel.V(32+16.5, 40)).A; got != 1 {
+			t.Errorf("screen %v: alpha on the circle = %v, want 1", screen, got)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	imd := imdraw.New(nil)
+	imd.Push(pixel.V(0, 0))
+	imd.Circle(2, 0)
+
+	imd.BeginGroup()
+	imd.Push(pixel.V(0, 0), pixel.V(10, 10))
+	imd.Rectangle(0)
+	g := imd.EndGroup()
+
+	if got, want := g.Bounds(), pixel.R(0, 0, 10, 10); got != want {
+		t.Errorf("Bounds() = %v, want %v", got, want)
+	}
+
+	c := raster.NewCanvas(pixel.R(0, 0, 64, 64))
+	g.Draw(c, pixel.IM.Moved(pixel.V(20, 20)))
+	g.DrawColorMask(c, pixel.IM.Moved(pixel.V(40, 40)), pixel.RGB(1, 0, 0))
+
+	if got := c.Color(pixel.V(25, 25)); got != pixel.RGB(1, 1, 1) {
+		t.Errorf("color of the first copy = %v, want white", got)
+	}
+	if got := c.Color(pixel.V(45, 45)); got != pixel.RGB(1, 0, 0) {
+		t.Errorf("color of the second copy = %v, want red", got)
+	}
+	// the circle was drawn before BeginGroup
+	if got := c.Color(pixel.V(20.5, 20.5)).A; got != 1 {
+		t.Errorf("alpha at the corner of the group = %v, want 1", got)
+	}
+	if got := c.Color(pixel.V(19.5, 19.5)).A; got != 0 {
+		t.Errorf("alpha outside of the group = %v, want 0", got)
+	}
+}
+
+func TestArena(t *testing.T) {
+	arena := pixel.NewArena()
+	imd := imdraw.New(nil)
+	imd.SetArena(arena)
+
+	imd.Push(pixel.V(0, 0), pixel.V(10, 10))
+	imd.Rectangle(0)
+	c := raster.NewCanvas(pixel.R(0, 0, 16, 16))
+	imd.Draw(c)
+	if got := c.Color(pixel.V(5, 5)); got != pixel.RGB(1, 1, 1) {
+		t.Errorf("color of the rectangle = %v, want white", got)
+	}
+	if arena.Len() == 0 {
+		t.Errorf("no triangles were allocated from the Arena")
+	}
+
+	// resetting the Arena clears the IMDraw
+	arena.Reset()
+	c.Clear(pixel.Alpha(0))
+	imd.Push(pixel.V(12, 12))
+	imd.Circle(2, 0)
+	imd.Draw(c)
+	if got := c.Color(pixel.V(5, 5)).A; got != 0 {
+		t.Errorf("alpha of the cleared rectangle = %v, want 0", got)
+	}
+	if got := c.Color(pixel.V(12, 12)); got != pixel.RGB(1, 1, 1) {
+		t.Errorf("color of the circle = %v, want white", got)
+	}
+}
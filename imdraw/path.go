@@ -0,0 +1,118 @@
+package imdraw
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// BeginPath removes all Pushed points, which makes up an empty path.
+//
+// The path functions (MoveTo, LineTo, Arc, ClosePath, Fill and Stroke) provide an alternative way
+// of drawing, similar to the HTML canvas. A path is made of the Pushed points and it consists of
+// subpaths, which are the contours of the Pushed points (see NextContour). CurveTo and QuadTo can
+// be used to add curves to a path as well. Unlike the other shapes, Fill and Stroke don't remove
+// the path, so one path can be both filled and stroked:
+//
+//   imd.BeginPath()
+//   imd.MoveTo(pixel.V(100, 100))
+//   imd.LineTo(pixel.V(200, 100))
+//   imd.QuadTo(pixel.V(250, 150), pixel.V(200, 200))
+//   imd.ClosePath()
+//   imd.Color = colornames.Yellow
+//   imd.Fill()
+//   imd.Color = colornames.Black
+//   imd.Stroke(2)
+//
+// Note, that the points take the properties, such as Color, when they're added to the path.
+// Fill and Stroke only draw with the taken properties.
+func (imd *IMDraw) BeginPath() {
+	imd.points = imd.points[:0]
+	imd.newContour = false
+}
+
+// MoveTo starts a new subpath at the position.
+func (imd *IMDraw) MoveTo(pos pixel.Vec) {
+	imd.NextContour()
+	imd.Push(pos)
+}
+
+// LineTo adds a straight line from the last point of the path to the position. If the path is
+// empty, it's the same as MoveTo.
+func (imd *IMDraw) LineTo(pos pixel.Vec) {
+	imd.Push(pos)
+}
+
+// Arc adds a circular arc of the radius around the center to the path, from the low angle to the
+// high angle (counterclockwise if low<high). If the path isn't empty, a straight line connects its
+// last point with the start of the arc. The number of points of the arc is given by the Precision.
+func (imd *IMDraw) Arc(center pixel.Vec, radius, low, high float64) {
+	num := math.Max(1, math.Ceil(math.Abs(high-low)/(2*math.Pi)*float64(imd.Precision)))
+	delta := (high - low) / num
+
+	opts := imd.pointOpts()
+	for i := 0.0; i <= num; i++ {
+		sin, cos := math.Sincos(low + i*delta)
+		imd.pushPt(center.Add(pixel.V(radius*cos, radius*sin)), opts)
+	}
+}
+
+// ClosePath closes the current subpath with a straight line from its last point to its first
+// point. The next point, if any, starts a new subpath.
+//
+// Fill treats all subpaths as closed, ClosePath only matters for Stroke.
+func (imd *IMDraw) ClosePath() {
+	if len(imd.points) == 0 {
+		return
+	}
+	contours := splitContours(imd.points)
+	contours[len(contours)-1][0].closed = true
+	imd.NextContour()
+}
+
+// Fill fills the path according to the FillRule. Because paths are rarely convex, FanFill is
+// treated as NonZeroFill here.
+func (imd *IMDraw) Fill() {
+	rule := imd.FillRule
+	if rule == FanFill {
+		rule = NonZeroFill
+	}
+
+	path, newContour := imd.savePath()
+	imd.pushPath(path)
+	imd.fillRule(rule)
+	imd.restorePath(path, newContour)
+}
+
+// Stroke draws the outline of each subpath of the path with the thickness. The subpaths closed by
+// ClosePath are outlined as closed polygons, the other ones as lines.
+func (imd *IMDraw) Stroke(thickness float64) {
+	if len(imd.points) == 0 {
+		return
+	}
+	path, newContour := imd.savePath()
+	for _, contour := range splitContours(path) {
+		imd.pushPath(contour)
+		imd.polyline(thickness, contour[0].closed)
+	}
+	imd.restorePath(path, newContour)
+}
+
+// savePath removes the path from the Pushed points and returns it, so that it can be drawn
+// multiple times.
+func (imd *IMDraw) savePath() (path []point, newContour bool) {
+	newContour = imd.newContour
+	path = imd.getAndClearPoints()
+	return path, newContour
+}
+
+func (imd *IMDraw) pushPath(path []point) {
+	imd.points = append(imd.points, path...)
+}
+
+// restorePath makes the saved path the Pushed points again.
+func (imd *IMDraw) restorePath(path []point, newContour bool) {
+	imd.restorePoints(path)
+	imd.points = path
+	imd.newContour = newContour
+}
@@ -0,0 +1,184 @@
+// Package input implements the mapping of the named actions of a game to the keys, the mouse
+// buttons and the joysticks, which the players can rebind and save in the settings.
+//
+//   m := input.NewMap()
+//   m.Bind("left", input.Key(pixelgl.KeyA), input.Key(pixelgl.KeyLeft), input.JoystickAxis(pixelgl.Joystick1, 0, -1))
+//   m.Bind("right", input.Key(pixelgl.KeyD), input.Key(pixelgl.KeyRight), input.JoystickAxis(pixelgl.Joystick1, 0, 1))
+//   m.Bind("jump", input.Key(pixelgl.KeySpace), input.JoystickButton(pixelgl.Joystick1, 0))
+//
+//   for !win.Closed() {
+//       win.Update()
+//       m.Update(win)
+//       player.Run(m.Axis("left", "right"))
+//       if m.JustPressed("jump") {
+//           player.Jump()
+//       }
+//   }
+//
+// A Recorder records the input frame by frame and a Playback replays it, for attract modes,
+// recordings of bugs and deterministic tests. Shortcuts handle the keyboard shortcuts of tools and
+// editors, such as "CmdOrCtrl+Shift+S".
+package input
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// Kind is the kind of a Binding.
+type Kind int
+
+const (
+	// ButtonKind is a key or a mouse button.
+	ButtonKind Kind = iota
+
+	// JoystickButtonKind is a button of a joystick.
+	JoystickButtonKind
+
+	// JoystickAxisKind is one direction of an axis of a joystick.
+	JoystickAxisKind
+)
+
+// Binding is a physical input, which triggers an action.
+type Binding struct {
+	Kind Kind
+
+	// Button is the key or the mouse button of a ButtonKind Binding.
+	Button pixelgl.Button
+
+	// Joystick, Index and Dir are the joystick, the index of its button or axis, and the
+	// direction of the axis, -1 or 1, of a joystick Binding.
+	Joystick pixelgl.Joystick
+	Index    int
+	Dir      int
+}
+
+// Key returns the Binding of the key or the mouse button.
+func Key(button pixelgl.Button) Binding {
+	return Binding{Kind: ButtonKind, Button: button}
+}
+
+// JoystickButton returns the Binding of the button of the joystick.
+func JoystickButton(js pixelgl.Joystick, button int) Binding {
+	return Binding{Kind: JoystickButtonKind, Joystick: js, Index: button}
+}
+
+// JoystickAxis returns the Binding of the axis of the joystick moved in the direction, -1 or 1.
+func JoystickAxis(js pixelgl.Joystick, axis int, dir int) Binding {
+	if dir < 0 {
+		dir = -1
+	} else {
+		dir = 1
+	}
+	return Binding{Kind: JoystickAxisKind, Joystick: js, Index: axis, Dir: dir}
+}
+
+// String returns the name of the Binding, such as "Space", "Joystick1 Button2" or
+// "Joystick1 Axis0-".
+func (b Binding) String() string {
+	switch b.Kind {
+	case JoystickButtonKind:
+		return fmt.Sprintf("Joystick%d Button%d", b.Joystick-pixelgl.Joystick1+1, b.Index)
+	case JoystickAxisKind:
+		sign := "+"
+		if b.Dir < 0 {
+			sign = "-"
+		}
+		return fmt.Sprintf("Joystick%d Axis%d%s", b.Joystick-pixelgl.Joystick1+1, b.Index, sign)
+	}
+	return b.Button.String()
+}
+
+// ParseBinding returns the Binding of the name returned by its String method.
+func ParseBinding(name string) (Binding, error) {
+	if !strings.HasPrefix(name, "Joystick") {
+		button, ok := buttons()[name]
+		if !ok {
+			return Binding{}, fmt.Errorf("input: unknown button %q", name)
+		}
+		return Key(button), nil
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(name, "Joystick"))
+	if len(fields) != 2 {
+		return Binding{}, fmt.Errorf("input: invalid binding %q", name)
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil || n < 1 || pixelgl.Joystick(n-1)+pixelgl.Joystick1 > pixelgl.JoystickLast {
+		return Binding{}, fmt.Errorf("input: invalid joystick in %q", name)
+	}
+	js := pixelgl.Joystick(n-1) + pixelgl.Joystick1
+
+	switch {
+	case strings.HasPrefix(fields[1], "Button"):
+		i, err := strconv.Atoi(strings.TrimPrefix(fields[1], "Button"))
+		if err != nil || i < 0 {
+			return Binding{}, fmt.Errorf("input: invalid button in %q", name)
+		}
+		return JoystickButton(js, i), nil
+
+	case strings.HasPrefix(fields[1], "Axis") && len(fields[1]) > len("Axis"):
+		axis := strings.TrimPrefix(fields[1], "Axis")
+		dir := 1
+		switch axis[len(axis)-1] {
+		case '+':
+		case '-':
+			dir = -1
+		default:
+			return Binding{}, fmt.Errorf("input: invalid axis direction in %q", name)
+		}
+		i, err := strconv.Atoi(axis[:len(axis)-1])
+		if err != nil || i < 0 {
+			return Binding{}, fmt.Errorf("input: invalid axis in %q", name)
+		}
+		return JoystickAxis(js, i, dir), nil
+	}
+	return Binding{}, fmt.Errorf("input: invalid binding %q", name)
+}
+
+// MarshalText returns the name of the Binding.
+func (b Binding) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText sets the Binding to the one of the name.
+func (b *Binding) UnmarshalText(text []byte) error {
+	parsed, err := ParseBinding(string(text))
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
+
+var (
+	buttonsOnce   sync.Once
+	buttonsByName map[string]pixelgl.Button
+	buttonList    []pixelgl.Button
+)
+
+func initButtons() {
+	buttonsByName = make(map[string]pixelgl.Button)
+	for b := pixelgl.Button(0); b <= pixelgl.KeyLast; b++ {
+		if name := b.String(); name != "Invalid" {
+			buttonsByName[name] = b
+			buttonList = append(buttonList, b)
+		}
+	}
+}
+
+// buttons returns the keys and the mouse buttons by their names
+func buttons() map[string]pixelgl.Button {
+	buttonsOnce.Do(initButtons)
+	return buttonsByName
+}
+
+// allButtons returns all the keys and the mouse buttons in the increasing order
+func allButtons() []pixelgl.Button {
+	buttonsOnce.Do(initButtons)
+	return buttonList
+}
@@ -0,0 +1,95 @@
+package input
+
+import (
+	"math"
+
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// Capture waits for the next physical input, e.g. to rebind an action in a settings menu.
+//
+//   var c input.Capture
+//   for !win.Closed() {
+//       win.Update()
+//       if b, ok := c.Update(win); ok {
+//           m.Rebind("jump", 0, b)
+//       }
+//   }
+type Capture struct {
+	// Threshold is the value a joystick axis must exceed to be captured, 0.5 if zero.
+	Threshold float64
+
+	prev    map[Binding]bool
+	started bool
+}
+
+// Update returns the Binding which became active since the previous Update, if any. The first
+// Update only records the state of the Device, so the input which started the Capture, such as the
+// key which opened the menu, isn't captured.
+func (c *Capture) Update(d Device) (Binding, bool) {
+	threshold := c.Threshold
+	if threshold == 0 {
+		threshold = 0.5
+	}
+
+	active := make(map[Binding]bool)
+	for _, button := range allButtons() {
+		if d.Pressed(button) {
+			active[Key(button)] = true
+		}
+	}
+	for js := pixelgl.Joystick1; js <= pixelgl.JoystickLast; js++ {
+		if !d.JoystickPresent(js) {
+			continue
+		}
+		for i := 0; i < d.JoystickButtonCount(js); i++ {
+			if d.JoystickPressed(js, i) {
+				active[JoystickButton(js, i)] = true
+			}
+		}
+		for i := 0; i < d.JoystickAxisCount(js); i++ {
+			if v := d.JoystickAxis(js, i); math.Abs(v) > threshold {
+				active[JoystickAxis(js, i, int(math.Copysign(1, v)))] = true
+			}
+		}
+	}
+
+	prev, started := c.prev, c.started
+	c.prev, c.started = active, true
+	if !started {
+		return Binding{}, false
+	}
+
+	// the lowest new Binding, so the result doesn't depend on the order of the map
+	var (
+		found Binding
+		ok    bool
+	)
+	for b := range active {
+		if !prev[b] && (!ok || less(b, found)) {
+			found, ok = b, true
+		}
+	}
+	return found, ok
+}
+
+// Reset makes the next Update only record the state of the Device again.
+func (c *Capture) Reset() {
+	c.prev, c.started = nil, false
+}
+
+func less(a, b Binding) bool {
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	if a.Button != b.Button {
+		return a.Button < b.Button
+	}
+	if a.Joystick != b.Joystick {
+		return a.Joystick < b.Joystick
+	}
+	if a.Index != b.Index {
+		return a.Index < b.Index
+	}
+	return a.Dir < b.Dir
+}
@@ -0,0 +1,349 @@
+package input_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/faiface/pixel/input"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/stretchr/testify/assert"
+)
+
+// device is a Device with a single joystick of 4 buttons and 2 axes
+type device struct {
+	pressed  map[pixelgl.Button]bool
+	joystick bool
+	buttons  [4]bool
+	axes     [2]float64
+}
+
+func newDevice() *device {
+	return &device{pressed: make(map[pixelgl.Button]bool), joystick: true}
+}
+
+func (d *device) Pressed(button pixelgl.Button) bool { return d.pressed[button] }
+
+func (d *device) JoystickPresent(js pixelgl.Joystick) bool {
+	return d.joystick && js == pixelgl.Joystick1
+}
+
+func (d *device) JoystickButtonCount(js pixelgl.Joystick) int { return len(d.buttons) }
+func (d *device) JoystickAxisCount(js pixelgl.Joystick) int   { return len(d.axes) }
+
+func (d *device) JoystickPressed(js pixelgl.Joystick, button int) bool {
+	return d.buttons[button]
+}
+
+func (d *device) JoystickAxis(js pixelgl.Joystick, axis int) float64 {
+	return d.axes[axis]
+}
+
+func TestBinding_String(t *testing.T) {
+	tests := []struct {
+		binding input.Binding
+		name    string
+	}{
+		{input.Key(pixelgl.KeySpace), "Space"},
+		{input.Key(pixelgl.MouseButtonLeft), "MouseButtonLeft"},
+		{input.JoystickButton(pixelgl.Joystick2, 3), "Joystick2 Button3"},
+		{input.JoystickAxis(pixelgl.Joystick1, 0, -5), "Joystick1 Axis0-"},
+		{input.JoystickAxis(pixelgl.Joystick1, 1, 1), "Joystick1 Axis1+"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.name, test.binding.String())
+		b, err := input.ParseBinding(test.name)
+		assert.NoError(t, err)
+		assert.Equal(t, test.binding, b)
+	}
+
+	for _, name := range []string{"", "Nope", "Joystick0 Button1", "Joystick1 Axis1", "Joystick1 Button-1", "Joystick1"} {
+		_, err := input.ParseBinding(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestMap_State(t *testing.T) {
+	d := newDevice()
+	m := input.NewMap()
+	m.Bind("jump", input.Key(pixelgl.KeySpace), input.JoystickButton(pixelgl.Joystick1, 0))
+
+	m.Update(d)
+	assert.False(t, m.Pressed("jump"))
+
+	d.pressed[pixelgl.KeySpace] = true
+	m.Update(d)
+	assert.True(t, m.Pressed("jump"))
+	assert.True(t, m.JustPressed("jump"))
+
+	d.buttons[0] = true
+	m.Update(d)
+	assert.True(t, m.Pressed("jump"))
+	assert.False(t, m.JustPressed("jump"))
+
+	d.pressed[pixelgl.KeySpace] = false
+	d.buttons[0] = false
+	m.Update(d)
+	assert.False(t, m.Pressed("jump"))
+	assert.True(t, m.JustReleased("jump"))
+
+	m.Update(d)
+	assert.False(t, m.JustReleased("jump"))
+	assert.False(t, m.Pressed("unknown"))
+}
+
+func TestMap_Axis(t *testing.T) {
+	d := newDevice()
+	m := input.NewMap()
+	m.Bind("left", input.Key(pixelgl.KeyA), input.JoystickAxis(pixelgl.Joystick1, 0, -1))
+	m.Bind("right", input.Key(pixelgl.KeyD), input.JoystickAxis(pixelgl.Joystick1, 0, 1))
+
+	d.axes[0] = 0.1
+	m.Update(d)
+	assert.Equal(t, 0.0, m.Axis("left", "right"), "deadzone")
+
+	d.axes[0] = -0.8
+	m.Update(d)
+	assert.InDelta(t, -0.75, m.Axis("left", "right"), 1e-9)
+	assert.True(t, m.Pressed("left"))
+
+	d.pressed[pixelgl.KeyA] = true
+	m.Update(d)
+	assert.Equal(t, -1.0, m.Axis("left", "right"))
+
+	d.pressed[pixelgl.KeyD] = true
+	m.Update(d)
+	assert.InDelta(t, 0.0, m.Axis("left", "right"), 1e-9)
+
+	d.joystick = false
+	d.pressed[pixelgl.KeyA] = false
+	m.Update(d)
+	assert.Equal(t, 1.0, m.Axis("left", "right"))
+}
+
+func TestMap_Bindings(t *testing.T) {
+	m := input.NewMap()
+	m.Bind("jump", input.Key(pixelgl.KeySpace), input.Key(pixelgl.KeySpace))
+	m.Bind("fire", input.Key(pixelgl.KeyLeftControl), input.Key(pixelgl.MouseButtonLeft))
+	assert.Equal(t, []string{"fire", "jump"}, m.Actions())
+	assert.Len(t, m.Bindings("jump"), 1)
+
+	m.Rebind("fire", 0, input.Key(pixelgl.KeySpace))
+	m.Rebind("fire", 2, input.JoystickButton(pixelgl.Joystick1, 1))
+	assert.Equal(t, []input.Binding{
+		input.Key(pixelgl.KeySpace),
+		input.Key(pixelgl.MouseButtonLeft),
+		input.JoystickButton(pixelgl.Joystick1, 1),
+	}, m.Bindings("fire"))
+
+	assert.Equal(t, []string{"fire", "jump"}, m.ActionsOf(input.Key(pixelgl.KeySpace)))
+	assert.Equal(t, []input.Conflict{
+		{Binding: input.Key(pixelgl.KeySpace), Actions: []string{"fire", "jump"}},
+	}, m.Conflicts())
+
+	m.Unbind("fire", input.Key(pixelgl.KeySpace))
+	assert.Empty(t, m.Conflicts())
+
+	m.Clear("jump")
+	assert.Equal(t, []string{"fire"}, m.Actions())
+}
+
+func TestMap_SaveLoad(t *testing.T) {
+	m := input.NewMap()
+	m.Bind("jump", input.Key(pixelgl.KeySpace))
+	m.Bind("left", input.Key(pixelgl.KeyA), input.JoystickAxis(pixelgl.Joystick1, 0, -1))
+
+	var buf bytes.Buffer
+	assert.NoError(t, m.Save(&buf))
+
+	defaults := input.NewMap()
+	defaults.Bind("jump", input.Key(pixelgl.KeyW))
+	defaults.Bind("crouch", input.Key(pixelgl.KeyS))
+	assert.NoError(t, defaults.Load(&buf))
+	assert.Equal(t, []string{"crouch", "jump", "left"}, defaults.Actions())
+	assert.Equal(t, m.Bindings("jump"), defaults.Bindings("jump"))
+	assert.Equal(t, m.Bindings("left"), defaults.Bindings("left"))
+	assert.Equal(t, []input.Binding{input.Key(pixelgl.KeyS)}, defaults.Bindings("crouch"))
+
+	assert.Error(t, defaults.Load(bytes.NewBufferString(`{"jump": ["Nope"]}`)))
+}
+
+func TestCapture(t *testing.T) {
+	d := newDevice()
+	d.pressed[pixelgl.KeyEnter] = true
+
+	var c input.Capture
+	_, ok := c.Update(d)
+	assert.False(t, ok, "the first update only records")
+	_, ok = c.Update(d)
+	assert.False(t, ok, "still held")
+
+	d.pressed[pixelgl.KeyEnter] = false
+	d.axes[1] = -0.9
+	b, ok := c.Update(d)
+	assert.True(t, ok)
+	assert.Equal(t, input.JoystickAxis(pixelgl.Joystick1, 1, -1), b)
+
+	d.pressed[pixelgl.KeyX] = true
+	d.buttons[2] = true
+	b, ok = c.Update(d)
+	assert.True(t, ok)
+	assert.Equal(t, input.Key(pixelgl.KeyX), b)
+
+	c.Reset()
+	d.pressed[pixelgl.KeyY] = true
+	_, ok = c.Update(d)
+	assert.False(t, ok)
+}
+
+func TestRecorder_Playback(t *testing.T) {
+	d := newDevice()
+	rec := input.NewRecorder(d)
+	m := input.NewMap()
+	m.Bind("jump", input.Key(pixelgl.KeySpace))
+	m.Bind("right", input.JoystickAxis(pixelgl.Joystick1, 0, 1))
+
+	var jumps []bool
+	for i := 0; i < 6; i++ {
+		d.pressed[pixelgl.KeySpace] = i%3 == 1
+		d.axes[0] = float64(i) / 5
+		rec.Update(0.5)
+		m.Update(rec)
+		jumps = append(jumps, m.JustPressed("jump"))
+	}
+	assert.Equal(t, []bool{false, true, false, false, true, false}, jumps)
+	assert.False(t, rec.Pressed(pixelgl.KeySpace))
+	assert.True(t, rec.JustReleased(pixelgl.KeySpace))
+
+	var buf bytes.Buffer
+	assert.NoError(t, rec.Recording().Save(&buf))
+	loaded, err := input.LoadRecording(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Frames, 6)
+
+	p := input.NewPlayback(loaded)
+	m = input.NewMap()
+	m.Bind("jump", input.Key(pixelgl.KeySpace))
+	m.Bind("right", input.JoystickAxis(pixelgl.Joystick1, 0, 1))
+	assert.Equal(t, -1, p.Frame())
+	var replayed []bool
+	var time float64
+	for !p.Done() {
+		time += p.Update()
+		m.Update(p)
+		replayed = append(replayed, m.JustPressed("jump"))
+	}
+	assert.Equal(t, jumps, replayed)
+	assert.Equal(t, 3.0, time)
+	assert.Equal(t, 5, p.Frame())
+	assert.Equal(t, 1.0, m.Value("right"))
+	assert.Equal(t, 0.0, p.Update(), "after the last frame")
+
+	p.Rewind()
+	p.Update()
+	assert.Equal(t, 0, p.Frame())
+	assert.False(t, p.Pressed(pixelgl.KeySpace))
+	p.Update()
+	assert.True(t, p.JustPressed(pixelgl.KeySpace))
+	assert.True(t, p.JoystickPresent(pixelgl.Joystick1))
+	assert.False(t, p.JoystickPresent(pixelgl.Joystick2))
+	assert.InDelta(t, 0.2, p.JoystickAxis(pixelgl.Joystick1, 0), 1e-9)
+
+	p.Loop = true
+	for i := 0; i < 4; i++ {
+		p.Update()
+	}
+	assert.False(t, p.Done())
+	assert.Equal(t, 0.5, p.Update())
+	assert.Equal(t, 0, p.Frame(), "looped")
+
+	_, err = input.LoadRecording(bytes.NewBufferString("nope"))
+	assert.Error(t, err)
+}
+
+func TestParseShortcut(t *testing.T) {
+	sc, err := input.ParseShortcut("ctrl+SHIFT+s")
+	assert.NoError(t, err)
+	assert.Equal(t, input.Shortcut{{Mods: input.ModControl | input.ModShift, Key: pixelgl.KeyS}}, sc)
+	assert.Equal(t, "Ctrl+Shift+S", sc.String())
+
+	sc, err = input.ParseShortcut("CmdOrCtrl+K  Cmd+Esc")
+	assert.NoError(t, err)
+	assert.Equal(t, "CmdOrCtrl+K Super+Escape", sc.String())
+
+	for _, def := range []string{"", "Ctrl+", "Hyper+S", "Ctrl+Shift", "Ctrl+LeftAlt", "Nope"} {
+		_, err := input.ParseShortcut(def)
+		assert.Error(t, err, def)
+	}
+}
+
+func TestShortcuts(t *testing.T) {
+	d := newDevice()
+	sc := input.NewShortcuts()
+	sc.MacOS = false
+
+	saves := 0
+	assert.NoError(t, sc.Add("save", "CmdOrCtrl+S", func() { saves++ }))
+	assert.NoError(t, sc.Add("save as", "Ctrl+Shift+S", nil))
+	assert.NoError(t, sc.Add("comment", "Ctrl+K Ctrl+C", nil))
+	assert.NoError(t, sc.Add("uncomment", "Ctrl+K Ctrl+U", nil))
+
+	assert.Error(t, sc.Add("store", "Ctrl+S", nil), "same as save off macOS")
+	assert.Error(t, sc.Add("kill", "Ctrl+K", nil), "beginning of comment")
+	assert.Error(t, sc.Add("bad", "Ctrl+", nil))
+	assert.NoError(t, sc.Add("save", "CmdOrCtrl+S", func() { saves++ }), "replacing itself")
+	assert.Equal(t, []string{"save as", "comment", "uncomment", "save"}, sc.Names())
+
+	press := func(keys ...pixelgl.Button) {
+		for _, k := range keys {
+			d.pressed[k] = true
+		}
+		sc.Update(d)
+	}
+	release := func() {
+		d.pressed = make(map[pixelgl.Button]bool)
+		sc.Update(d)
+	}
+
+	press(pixelgl.KeyLeftControl, pixelgl.KeyS)
+	assert.True(t, sc.JustTriggered("save"))
+	assert.Equal(t, 1, saves)
+	press()
+	assert.False(t, sc.JustTriggered("save"), "held")
+	release()
+
+	press(pixelgl.KeyRightControl, pixelgl.KeyLeftShift, pixelgl.KeyS)
+	assert.True(t, sc.JustTriggered("save as"))
+	assert.False(t, sc.JustTriggered("save"), "exact modifiers")
+	release()
+
+	press(pixelgl.KeyS)
+	assert.False(t, sc.JustTriggered("save"), "no modifiers")
+	release()
+
+	press(pixelgl.KeyLeftControl, pixelgl.KeyK)
+	assert.Equal(t, "Ctrl+K", sc.Pending().String())
+	release()
+	press(pixelgl.KeyLeftControl, pixelgl.KeyU)
+	assert.True(t, sc.JustTriggered("uncomment"))
+	assert.Nil(t, sc.Pending())
+	release()
+
+	press(pixelgl.KeyLeftControl, pixelgl.KeyK)
+	release()
+	press(pixelgl.KeyLeftControl, pixelgl.KeyS)
+	assert.True(t, sc.JustTriggered("save"), "cancels the sequence and starts over")
+	assert.Nil(t, sc.Pending())
+	release()
+
+	sc.MacOS = true
+	press(pixelgl.KeyLeftControl, pixelgl.KeyS)
+	assert.False(t, sc.JustTriggered("save"))
+	release()
+	press(pixelgl.KeyLeftSuper, pixelgl.KeyS)
+	assert.True(t, sc.JustTriggered("save"))
+	assert.Equal(t, 3, saves)
+
+	sc.Remove("save")
+	_, ok := sc.Shortcut("save")
+	assert.False(t, ok)
+}
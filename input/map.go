@@ -0,0 +1,261 @@
+package input
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// Device is the source of the input, such as a *pixelgl.Window.
+type Device interface {
+	Pressed(button pixelgl.Button) bool
+	JoystickPresent(js pixelgl.Joystick) bool
+	JoystickButtonCount(js pixelgl.Joystick) int
+	JoystickAxisCount(js pixelgl.Joystick) int
+	JoystickPressed(js pixelgl.Joystick, button int) bool
+	JoystickAxis(js pixelgl.Joystick, axis int) float64
+}
+
+// Map maps the named actions to their Bindings. An action is triggered by any of its Bindings.
+//
+// The state of the actions changes only by Update, once per frame.
+type Map struct {
+	// Deadzone is the part of the range of the joystick axes around the center, which is ignored.
+	Deadzone float64
+
+	// Threshold is the value of an action, from which it's pressed.
+	Threshold float64
+
+	bindings map[string][]Binding
+	values   map[string]float64
+	prev     map[string]float64
+}
+
+// NewMap creates a new Map without any actions, with the Deadzone 0.2 and the Threshold 0.5.
+func NewMap() *Map {
+	return &Map{
+		Deadzone:  0.2,
+		Threshold: 0.5,
+		bindings:  make(map[string][]Binding),
+		values:    make(map[string]float64),
+		prev:      make(map[string]float64),
+	}
+}
+
+// Bind adds the Bindings to the action.
+func (m *Map) Bind(action string, bindings ...Binding) {
+	for _, b := range bindings {
+		if !m.Bound(action, b) {
+			m.bindings[action] = append(m.bindings[action], b)
+		}
+	}
+}
+
+// Rebind replaces the i-th Binding of the action, e.g. with a Binding from a Capture in a settings
+// menu. If i is the number of the Bindings of the action, it adds a new one.
+func (m *Map) Rebind(action string, i int, b Binding) {
+	bindings := m.bindings[action]
+	if i == len(bindings) {
+		m.bindings[action] = append(bindings, b)
+		return
+	}
+	bindings[i] = b
+}
+
+// Unbind removes the Binding from the action.
+func (m *Map) Unbind(action string, b Binding) {
+	bindings := m.bindings[action]
+	for i := range bindings {
+		if bindings[i] == b {
+			m.bindings[action] = append(bindings[:i], bindings[i+1:]...)
+			return
+		}
+	}
+}
+
+// Clear removes all the Bindings of the action.
+func (m *Map) Clear(action string) {
+	delete(m.bindings, action)
+}
+
+// Bindings returns the Bindings of the action.
+func (m *Map) Bindings(action string) []Binding {
+	return m.bindings[action]
+}
+
+// Bound reports whether the Binding is bound to the action.
+func (m *Map) Bound(action string, b Binding) bool {
+	for _, bb := range m.bindings[action] {
+		if bb == b {
+			return true
+		}
+	}
+	return false
+}
+
+// Actions returns the names of all the actions with Bindings, sorted.
+func (m *Map) Actions() []string {
+	actions := make([]string, 0, len(m.bindings))
+	for action, bindings := range m.bindings {
+		if len(bindings) > 0 {
+			actions = append(actions, action)
+		}
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// ActionsOf returns the actions the Binding is bound to, sorted. A settings menu can warn about a
+// new Binding, which is already used.
+func (m *Map) ActionsOf(b Binding) []string {
+	var actions []string
+	for _, action := range m.Actions() {
+		if m.Bound(action, b) {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// Conflict is a Binding bound to multiple actions.
+type Conflict struct {
+	Binding Binding
+	Actions []string
+}
+
+// Conflicts returns all the Bindings bound to multiple actions, sorted by their names.
+func (m *Map) Conflicts() []Conflict {
+	seen := make(map[Binding]bool)
+	var conflicts []Conflict
+	for _, action := range m.Actions() {
+		for _, b := range m.bindings[action] {
+			if seen[b] {
+				continue
+			}
+			seen[b] = true
+			if actions := m.ActionsOf(b); len(actions) > 1 {
+				conflicts = append(conflicts, Conflict{Binding: b, Actions: actions})
+			}
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Binding.String() < conflicts[j].Binding.String()
+	})
+	return conflicts
+}
+
+// Update updates the state of all the actions from the Device. It should be called once per frame,
+// after the Device is updated.
+func (m *Map) Update(d Device) {
+	m.prev, m.values = m.values, m.prev
+	for action := range m.values {
+		delete(m.values, action)
+	}
+	for action, bindings := range m.bindings {
+		var value float64
+		for _, b := range bindings {
+			value = math.Max(value, m.value(d, b))
+		}
+		m.values[action] = value
+	}
+}
+
+// Value returns the strength of the action, within range [0, 1]. It's 0 or 1 from the buttons
+// and anything between from the joystick axes.
+func (m *Map) Value(action string) float64 {
+	return m.values[action]
+}
+
+// Pressed reports whether the action is pressed.
+func (m *Map) Pressed(action string) bool {
+	return m.values[action] >= m.Threshold
+}
+
+// JustPressed reports whether the action was pressed in the last Update.
+func (m *Map) JustPressed(action string) bool {
+	return m.values[action] >= m.Threshold && m.prev[action] < m.Threshold
+}
+
+// JustReleased reports whether the action was released in the last Update.
+func (m *Map) JustReleased(action string) bool {
+	return m.values[action] < m.Threshold && m.prev[action] >= m.Threshold
+}
+
+// Axis returns the value of an axis made of the actions in its negative and positive direction,
+// within range [-1, 1].
+func (m *Map) Axis(negative, positive string) float64 {
+	return m.Value(positive) - m.Value(negative)
+}
+
+// Vector returns the vector made of the actions in the four directions, its length is at most 1.
+func (m *Map) Vector(left, right, down, up string) pixel.Vec {
+	v := pixel.V(m.Axis(left, right), m.Axis(down, up))
+	if v.Len() > 1 {
+		return v.Unit()
+	}
+	return v
+}
+
+func (m *Map) value(d Device, b Binding) float64 {
+	switch b.Kind {
+	case ButtonKind:
+		if d.Pressed(b.Button) {
+			return 1
+		}
+	case JoystickButtonKind:
+		if d.JoystickPresent(b.Joystick) && d.JoystickPressed(b.Joystick, b.Index) {
+			return 1
+		}
+	case JoystickAxisKind:
+		if !d.JoystickPresent(b.Joystick) {
+			return 0
+		}
+		v := d.JoystickAxis(b.Joystick, b.Index) * float64(b.Dir)
+		if v <= m.Deadzone {
+			return 0
+		}
+		return math.Min(1, (v-m.Deadzone)/(1-m.Deadzone))
+	}
+	return 0
+}
+
+// MarshalJSON returns the JSON object of the Bindings by the actions.
+func (m *Map) MarshalJSON() ([]byte, error) {
+	obj := make(map[string][]Binding)
+	for _, action := range m.Actions() {
+		obj[action] = m.bindings[action]
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON sets the Bindings of the actions in the JSON object. The other actions keep
+// their Bindings, so the actions added to a game after the settings were saved keep the defaults.
+func (m *Map) UnmarshalJSON(data []byte) error {
+	var obj map[string][]Binding
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	if m.bindings == nil {
+		*m = *NewMap()
+	}
+	for action, bindings := range obj {
+		m.bindings[action] = bindings
+	}
+	return nil
+}
+
+// Save writes the Bindings of the actions to the writer in JSON.
+func (m *Map) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// Load reads the Bindings of the actions saved by Save from the reader, like UnmarshalJSON.
+func (m *Map) Load(r io.Reader) error {
+	return json.NewDecoder(r).Decode(m)
+}
@@ -0,0 +1,288 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// Frame is the state of a Device in one frame.
+type Frame struct {
+	// Dt is the time of the frame, passed to the Update of the Recorder. A game replaying a
+	// Recording should update by the recorded Dt, not by the real time, to be deterministic.
+	Dt float64 `json:"dt"`
+
+	// Pressed is the pressed keys and mouse buttons, in the increasing order.
+	Pressed []pixelgl.Button `json:"pressed,omitempty"`
+
+	MousePosition pixel.Vec `json:"mouse"`
+	MouseScroll   pixel.Vec `json:"scroll"`
+	Typed         string    `json:"typed,omitempty"`
+
+	// Joysticks is the state of the present joysticks.
+	Joysticks []JoystickState `json:"joysticks,omitempty"`
+}
+
+// JoystickState is the state of a joystick in a Frame.
+type JoystickState struct {
+	Joystick pixelgl.Joystick `json:"joystick"`
+	Buttons  []bool           `json:"buttons"`
+	Axes     []float64        `json:"axes"`
+}
+
+// Recording is the sequence of the Frames of a game, recorded by a Recorder.
+type Recording struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Save writes the Recording to the writer in JSON.
+func (r *Recording) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// LoadRecording reads a Recording saved by Save from the reader.
+func LoadRecording(r io.Reader) (*Recording, error) {
+	rec := new(Recording)
+	if err := json.NewDecoder(r).Decode(rec); err != nil {
+		return nil, fmt.Errorf("input: failed to load recording: %v", err)
+	}
+	return rec, nil
+}
+
+// Recorder records the state of a Device frame by frame. The Recorder is a Device itself, it
+// returns the recorded state of the current frame. The mouse and the typed text are recorded only
+// if the Device has the MousePosition, MouseScroll and Typed methods, like a *pixelgl.Window.
+//
+//   rec := input.NewRecorder(win)
+//   for !win.Closed() {
+//       win.Update()
+//       rec.Update(dt)
+//       m.Update(rec)
+//       ...
+//   }
+//   rec.Recording().Save(file)
+type Recorder struct {
+	frameDevice
+	device    Device
+	recording Recording
+}
+
+// NewRecorder creates a new Recorder of the Device with an empty Recording.
+func NewRecorder(d Device) *Recorder {
+	return &Recorder{device: d}
+}
+
+// Update records the state of the Device in a new Frame of the time dt. It should be called once
+// per frame, after the Device is updated.
+func (r *Recorder) Update(dt float64) {
+	f := Frame{Dt: dt}
+	for _, button := range allButtons() {
+		if r.device.Pressed(button) {
+			f.Pressed = append(f.Pressed, button)
+		}
+	}
+	if mouse, ok := r.device.(interface {
+		MousePosition() pixel.Vec
+		MouseScroll() pixel.Vec
+	}); ok {
+		f.MousePosition = mouse.MousePosition()
+		f.MouseScroll = mouse.MouseScroll()
+	}
+	if typer, ok := r.device.(interface{ Typed() string }); ok {
+		f.Typed = typer.Typed()
+	}
+	for js := pixelgl.Joystick1; js <= pixelgl.JoystickLast; js++ {
+		if !r.device.JoystickPresent(js) {
+			continue
+		}
+		state := JoystickState{
+			Joystick: js,
+			Buttons:  make([]bool, r.device.JoystickButtonCount(js)),
+			Axes:     make([]float64, r.device.JoystickAxisCount(js)),
+		}
+		for i := range state.Buttons {
+			state.Buttons[i] = r.device.JoystickPressed(js, i)
+		}
+		for i := range state.Axes {
+			state.Axes[i] = r.device.JoystickAxis(js, i)
+		}
+		f.Joysticks = append(f.Joysticks, state)
+	}
+
+	r.recording.Frames = append(r.recording.Frames, f)
+	r.set(&r.recording.Frames[len(r.recording.Frames)-1])
+}
+
+// Recording returns the Recording of all the Frames recorded so far.
+func (r *Recorder) Recording() *Recording {
+	return &r.recording
+}
+
+// Playback replays a Recording. The Playback is a Device, which returns the state of the current
+// Frame, so the game can't tell it from the real input.
+//
+//   p := input.NewPlayback(rec)
+//   for !win.Closed() && !p.Done() {
+//       win.Update()
+//       dt := p.Update()
+//       m.Update(p)
+//       world.Update(dt)
+//       ...
+//   }
+type Playback struct {
+	frameDevice
+
+	// Loop makes the Playback start over after the last Frame, e.g. in an attract mode.
+	Loop bool
+
+	recording *Recording
+	next      int
+}
+
+// NewPlayback creates a new Playback of the Recording, before its first Frame.
+func NewPlayback(r *Recording) *Playback {
+	return &Playback{recording: r}
+}
+
+// Update moves the Playback to the next Frame and returns its time. After the last Frame, it
+// returns 0 and the Playback doesn't change, unless it loops.
+func (p *Playback) Update() (dt float64) {
+	if p.next >= len(p.recording.Frames) {
+		if !p.Loop || len(p.recording.Frames) == 0 {
+			return 0
+		}
+		p.next = 0
+	}
+	f := &p.recording.Frames[p.next]
+	p.next++
+	p.set(f)
+	return f.Dt
+}
+
+// Frame returns the index of the current Frame, or -1 before the first Update.
+func (p *Playback) Frame() int {
+	return p.next - 1
+}
+
+// Done reports whether the Playback replayed the last Frame. It's never done, if it loops.
+func (p *Playback) Done() bool {
+	return !p.Loop && p.next >= len(p.recording.Frames)
+}
+
+// Rewind moves the Playback before its first Frame.
+func (p *Playback) Rewind() {
+	p.next = 0
+	p.frameDevice = frameDevice{}
+}
+
+// frameDevice is the Device of the current and the previous Frame
+type frameDevice struct {
+	cur, prev *Frame
+}
+
+var emptyFrame Frame
+
+func (fd *frameDevice) set(f *Frame) {
+	fd.prev, fd.cur = fd.cur, f
+}
+
+func (fd *frameDevice) frame() *Frame {
+	if fd.cur == nil {
+		return &emptyFrame
+	}
+	return fd.cur
+}
+
+// Pressed returns whether the key or the mouse button is pressed in the current Frame.
+func (fd *frameDevice) Pressed(button pixelgl.Button) bool {
+	return pressed(fd.cur, button)
+}
+
+// JustPressed returns whether the key or the mouse button is pressed in the current Frame, but
+// wasn't in the previous one.
+func (fd *frameDevice) JustPressed(button pixelgl.Button) bool {
+	return pressed(fd.cur, button) && !pressed(fd.prev, button)
+}
+
+// JustReleased returns whether the key or the mouse button was pressed in the previous Frame, but
+// isn't in the current one.
+func (fd *frameDevice) JustReleased(button pixelgl.Button) bool {
+	return !pressed(fd.cur, button) && pressed(fd.prev, button)
+}
+
+// MousePosition returns the position of the mouse in the current Frame.
+func (fd *frameDevice) MousePosition() pixel.Vec {
+	return fd.frame().MousePosition
+}
+
+// MouseScroll returns the scroll of the mouse in the current Frame.
+func (fd *frameDevice) MouseScroll() pixel.Vec {
+	return fd.frame().MouseScroll
+}
+
+// Typed returns the text typed in the current Frame.
+func (fd *frameDevice) Typed() string {
+	return fd.frame().Typed
+}
+
+// JoystickPresent returns whether the joystick is present in the current Frame.
+func (fd *frameDevice) JoystickPresent(js pixelgl.Joystick) bool {
+	return fd.joystick(js) != nil
+}
+
+// JoystickButtonCount returns the number of the buttons of the joystick in the current Frame.
+func (fd *frameDevice) JoystickButtonCount(js pixelgl.Joystick) int {
+	if s := fd.joystick(js); s != nil {
+		return len(s.Buttons)
+	}
+	return 0
+}
+
+// JoystickAxisCount returns the number of the axes of the joystick in the current Frame.
+func (fd *frameDevice) JoystickAxisCount(js pixelgl.Joystick) int {
+	if s := fd.joystick(js); s != nil {
+		return len(s.Axes)
+	}
+	return 0
+}
+
+// JoystickPressed returns whether the button of the joystick is pressed in the current Frame.
+func (fd *frameDevice) JoystickPressed(js pixelgl.Joystick, button int) bool {
+	if s := fd.joystick(js); s != nil && button >= 0 && button < len(s.Buttons) {
+		return s.Buttons[button]
+	}
+	return false
+}
+
+// JoystickAxis returns the value of the axis of the joystick in the current Frame.
+func (fd *frameDevice) JoystickAxis(js pixelgl.Joystick, axis int) float64 {
+	if s := fd.joystick(js); s != nil && axis >= 0 && axis < len(s.Axes) {
+		return s.Axes[axis]
+	}
+	return 0
+}
+
+func (fd *frameDevice) joystick(js pixelgl.Joystick) *JoystickState {
+	f := fd.frame()
+	for i := range f.Joysticks {
+		if f.Joysticks[i].Joystick == js {
+			return &f.Joysticks[i]
+		}
+	}
+	return nil
+}
+
+func pressed(f *Frame, button pixelgl.Button) bool {
+	if f == nil {
+		return false
+	}
+	for _, b := range f.Pressed {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,379 @@
+package input
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// Modifier is a set of the modifier keys of a Chord, either the left or the right one of each.
+type Modifier int
+
+// List of all the modifiers.
+const (
+	ModControl Modifier = 1 << iota
+	ModAlt
+	ModShift
+	ModSuper
+
+	// ModPrimary is the modifier of the common shortcuts of the platform, ModSuper (Command) on
+	// macOS and ModControl elsewhere.
+	ModPrimary
+)
+
+var modifierNames = []struct {
+	mod  Modifier
+	name string
+}{
+	{ModPrimary, "CmdOrCtrl"},
+	{ModControl, "Ctrl"},
+	{ModAlt, "Alt"},
+	{ModShift, "Shift"},
+	{ModSuper, "Super"},
+}
+
+// modifierAliases are the names of the modifiers accepted by ParseChord, in lower case
+var modifierAliases = map[string]Modifier{
+	"cmdorctrl": ModPrimary,
+	"primary":   ModPrimary,
+	"ctrl":      ModControl,
+	"control":   ModControl,
+	"alt":       ModAlt,
+	"option":    ModAlt,
+	"shift":     ModShift,
+	"super":     ModSuper,
+	"cmd":       ModSuper,
+	"command":   ModSuper,
+	"win":       ModSuper,
+}
+
+// modifierKeys are the keys of each modifier
+var modifierKeys = []struct {
+	mod         Modifier
+	left, right pixelgl.Button
+}{
+	{ModControl, pixelgl.KeyLeftControl, pixelgl.KeyRightControl},
+	{ModAlt, pixelgl.KeyLeftAlt, pixelgl.KeyRightAlt},
+	{ModShift, pixelgl.KeyLeftShift, pixelgl.KeyRightShift},
+	{ModSuper, pixelgl.KeyLeftSuper, pixelgl.KeyRightSuper},
+}
+
+// resolve replaces ModPrimary by the modifier of the platform
+func (m Modifier) resolve(macOS bool) Modifier {
+	if m&ModPrimary == 0 {
+		return m
+	}
+	m &^= ModPrimary
+	if macOS {
+		return m | ModSuper
+	}
+	return m | ModControl
+}
+
+// Chord is a key pressed while exactly the modifiers are held.
+type Chord struct {
+	Mods Modifier
+	Key  pixelgl.Button
+}
+
+// ParseChord returns the Chord of the definition, such as "Ctrl+Shift+S", the modifiers followed
+// by the name of the key, separated by '+'. The names are case-insensitive. The modifiers are
+// "Ctrl", "Alt" (or "Option"), "Shift", "Super" (or "Cmd", "Win") and "CmdOrCtrl", which is
+// ModPrimary. The keys are named as by pixelgl.Button.String, e.g. "F4" or "Escape".
+func ParseChord(def string) (Chord, error) {
+	parts := strings.Split(def, "+")
+	keyName := strings.TrimSpace(parts[len(parts)-1])
+	var c Chord
+	for _, part := range parts[:len(parts)-1] {
+		mod, ok := modifierAliases[strings.ToLower(strings.TrimSpace(part))]
+		if !ok {
+			return Chord{}, fmt.Errorf("input: unknown modifier %q in %q", part, def)
+		}
+		c.Mods |= mod
+	}
+	key, ok := keysLower()[strings.ToLower(keyName)]
+	if !ok {
+		return Chord{}, fmt.Errorf("input: unknown key %q in %q", keyName, def)
+	}
+	if isModifierKey(key) {
+		return Chord{}, fmt.Errorf("input: modifier key %q used as the key of %q", keyName, def)
+	}
+	c.Key = key
+	return c, nil
+}
+
+// String returns the definition of the Chord, with the modifiers in a fixed order, e.g.
+// "Ctrl+Shift+S".
+func (c Chord) String() string {
+	var b strings.Builder
+	for _, mn := range modifierNames {
+		if c.Mods&mn.mod != 0 {
+			b.WriteString(mn.name)
+			b.WriteByte('+')
+		}
+	}
+	b.WriteString(c.Key.String())
+	return b.String()
+}
+
+// Shortcut is a sequence of Chords pressed one after another, usually just one, like "Ctrl+S", or
+// two, like "Ctrl+K Ctrl+C".
+type Shortcut []Chord
+
+// ParseShortcut returns the Shortcut of the definition, the definitions of its Chords (see
+// ParseChord) separated by spaces.
+func ParseShortcut(def string) (Shortcut, error) {
+	fields := strings.Fields(def)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("input: empty shortcut")
+	}
+	s := make(Shortcut, len(fields))
+	for i, field := range fields {
+		c, err := ParseChord(field)
+		if err != nil {
+			return nil, err
+		}
+		s[i] = c
+	}
+	return s, nil
+}
+
+// String returns the definition of the Shortcut, e.g. "Ctrl+K Ctrl+C".
+func (s Shortcut) String() string {
+	chords := make([]string, len(s))
+	for i, c := range s {
+		chords[i] = c.String()
+	}
+	return strings.Join(chords, " ")
+}
+
+// resolve returns the Shortcut with ModPrimary replaced by the modifier of the platform
+func (s Shortcut) resolve(macOS bool) Shortcut {
+	r := make(Shortcut, len(s))
+	for i, c := range s {
+		r[i] = Chord{Mods: c.Mods.resolve(macOS), Key: c.Key}
+	}
+	return r
+}
+
+// hasPrefix reports whether the prefix is the beginning of the Shortcut, or all of it
+func (s Shortcut) hasPrefix(prefix Shortcut) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Shortcuts is a registry of the named Shortcuts of a program, which calls their functions when
+// they're triggered.
+//
+//   sc := input.NewShortcuts()
+//   sc.Add("save", "CmdOrCtrl+S", editor.Save)
+//   sc.Add("save as", "CmdOrCtrl+Shift+S", editor.SaveAs)
+//   sc.Add("comment", "CmdOrCtrl+K CmdOrCtrl+C", nil)
+//
+//   for !win.Closed() {
+//       win.Update()
+//       sc.Update(win)
+//       if sc.JustTriggered("comment") {
+//           editor.CommentSelection()
+//       }
+//   }
+//
+// A Chord matches only with exactly its modifiers held, so "Ctrl+S" isn't triggered by pressing
+// Ctrl+Shift+S. A key pressed in the middle of a sequence which doesn't continue it cancels the
+// sequence.
+type Shortcuts struct {
+	// MacOS sets whether ModPrimary is ModSuper (Command), it's set by NewShortcuts on macOS.
+	MacOS bool
+
+	entries   []shortcutEntry
+	prev      map[pixelgl.Button]bool
+	progress  Shortcut
+	triggered map[string]bool
+}
+
+type shortcutEntry struct {
+	name     string
+	shortcut Shortcut
+	f        func()
+}
+
+// NewShortcuts creates a new empty Shortcuts for the current platform.
+func NewShortcuts() *Shortcuts {
+	return &Shortcuts{
+		MacOS:     runtime.GOOS == "darwin",
+		prev:      make(map[pixelgl.Button]bool),
+		triggered: make(map[string]bool),
+	}
+}
+
+// Add adds the Shortcut of the definition (see ParseShortcut) under the name. The function, if not
+// nil, is called by Update when the Shortcut is triggered. A Shortcut already added under the name
+// is replaced.
+//
+// An error is returned if the definition is invalid, or if the Shortcut conflicts with another
+// one on this platform: they're the same, or one is the beginning of the other, so it could never
+// be triggered.
+func (s *Shortcuts) Add(name, def string, f func()) error {
+	sc, err := ParseShortcut(def)
+	if err != nil {
+		return err
+	}
+	if other, ok := s.conflict(name, sc); ok {
+		return fmt.Errorf("input: shortcut %v of %q conflicts with %q", sc, name, other)
+	}
+	s.Remove(name)
+	s.entries = append(s.entries, shortcutEntry{name: name, shortcut: sc, f: f})
+	return nil
+}
+
+// conflict returns the name of another Shortcut which conflicts with the one, if any
+func (s *Shortcuts) conflict(name string, sc Shortcut) (string, bool) {
+	r := sc.resolve(s.MacOS)
+	for _, e := range s.entries {
+		if e.name == name {
+			continue
+		}
+		other := e.shortcut.resolve(s.MacOS)
+		if r.hasPrefix(other) || other.hasPrefix(r) {
+			return e.name, true
+		}
+	}
+	return "", false
+}
+
+// Remove removes the Shortcut of the name.
+func (s *Shortcuts) Remove(name string) {
+	for i := range s.entries {
+		if s.entries[i].name == name {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// Shortcut returns the Shortcut added under the name.
+func (s *Shortcuts) Shortcut(name string) (Shortcut, bool) {
+	for _, e := range s.entries {
+		if e.name == name {
+			return e.shortcut, true
+		}
+	}
+	return nil, false
+}
+
+// Names returns the names of all the Shortcuts in the order they were added.
+func (s *Shortcuts) Names() []string {
+	names := make([]string, len(s.entries))
+	for i, e := range s.entries {
+		names[i] = e.name
+	}
+	return names
+}
+
+// Update matches the keys pressed since the previous Update against the Shortcuts and calls the
+// functions of the triggered ones. It should be called once per frame, after the Device is updated.
+func (s *Shortcuts) Update(d Device) {
+	for name := range s.triggered {
+		delete(s.triggered, name)
+	}
+
+	var mods Modifier
+	for _, mk := range modifierKeys {
+		if d.Pressed(mk.left) || d.Pressed(mk.right) {
+			mods |= mk.mod
+		}
+	}
+
+	for _, button := range allButtons() {
+		pressed := d.Pressed(button)
+		if pressed == s.prev[button] {
+			continue
+		}
+		s.prev[button] = pressed
+		if pressed && !isModifierKey(button) {
+			s.press(Chord{Mods: mods, Key: button})
+		}
+	}
+}
+
+// press continues the sequence in progress with the Chord, or starts a new one
+func (s *Shortcuts) press(c Chord) {
+	if s.advance(append(s.progress, c)) {
+		return
+	}
+	if len(s.progress) > 0 {
+		s.progress = nil
+		s.advance(Shortcut{c})
+	}
+}
+
+// advance triggers the Shortcut equal to the sequence, or keeps the sequence in progress if it's
+// the beginning of a Shortcut, and reports whether it did either
+func (s *Shortcuts) advance(seq Shortcut) bool {
+	for _, e := range s.entries {
+		r := e.shortcut.resolve(s.MacOS)
+		if !r.hasPrefix(seq) {
+			continue
+		}
+		if len(r) > len(seq) {
+			s.progress = seq
+			return true
+		}
+		s.progress = nil
+		s.triggered[e.name] = true
+		if e.f != nil {
+			e.f()
+		}
+		return true
+	}
+	return false
+}
+
+// JustTriggered reports whether the Shortcut of the name was triggered in the last Update.
+func (s *Shortcuts) JustTriggered(name string) bool {
+	return s.triggered[name]
+}
+
+// Pending returns the beginning of a longer Shortcut pressed so far, e.g. to show "Ctrl+K was
+// pressed, waiting for the next key" in a status bar. It's nil if no sequence is in progress.
+func (s *Shortcuts) Pending() Shortcut {
+	return s.progress
+}
+
+func isModifierKey(button pixelgl.Button) bool {
+	for _, mk := range modifierKeys {
+		if button == mk.left || button == mk.right {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	keysLowerOnce   sync.Once
+	keysByLowerName map[string]pixelgl.Button
+)
+
+// keysLower returns the keys and the mouse buttons by their names in lower case, with a few
+// common abbreviations
+func keysLower() map[string]pixelgl.Button {
+	keysLowerOnce.Do(func() {
+		keysByLowerName = make(map[string]pixelgl.Button)
+		for name, button := range buttons() {
+			keysByLowerName[strings.ToLower(name)] = button
+		}
+		keysByLowerName["esc"] = pixelgl.KeyEscape
+		keysByLowerName["del"] = pixelgl.KeyDelete
+	})
+	return keysByLowerName
+}
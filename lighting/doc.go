@@ -0,0 +1,3 @@
+// Package lighting implements 2D dynamic lights with shadows. The lights are accumulated on a
+// lightmap, with the shadows cast by line segments, and the lightmap darkens the scene.
+package lighting
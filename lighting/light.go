@@ -0,0 +1,181 @@
+package lighting
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Light is a point light, or a cone light if its Cone is set. Its brightness fades from the full
+// Color in the Pos to nothing at the Radius.
+type Light struct {
+	Pos    pixel.Vec
+	Radius float64
+
+	// Color is the color of the Light, which also sets its brightness. The colors of the Lights and
+	// the Ambient add up.
+	Color pixel.RGBA
+
+	// Direction is the angle a cone light shines to and the Cone is the angle it spreads to on each
+	// side of it, in radians. Zero Cone (or Pi and more) is a point light shining to all directions.
+	Direction, Cone float64
+
+	// Shadows makes the occluders of the Lightmap cast shadows of the Light.
+	Shadows bool
+}
+
+// Polygon returns the area lit by the Light, blocked by the segments, e.g. to check what's lit or
+// what an enemy with a vision cone sees. For a cone light, the first point is the Pos and the rest
+// goes around the cone counter-clockwise. For a point light, the points go around the Pos.
+func (l *Light) Polygon(segments []Segment) []pixel.Vec {
+	if !l.Shadows {
+		segments = nil
+	}
+	if l.isCone() {
+		points := visibility(l.Pos, l.Radius, l.Direction-l.Cone, l.Direction+l.Cone, segments)
+		return append([]pixel.Vec{l.Pos}, points...)
+	}
+	points := visibility(l.Pos, l.Radius, -math.Pi, math.Pi, segments)
+	// the first and the last point are the same ray
+	return points[:len(points)-1]
+}
+
+func (l *Light) isCone() bool {
+	return l.Cone > 0 && l.Cone < math.Pi
+}
+
+// Canvas is an off-screen Target, that a Lightmap draws the lights onto. pixelgl.Canvas and
+// raster.Canvas are Canvases.
+type Canvas interface {
+	pixel.ComposeTarget
+	pixel.Picture
+	Clear(color color.Color)
+	DrawColorMask(t pixel.Target, matrix pixel.Matrix, mask color.Color)
+}
+
+// Lightmap accumulates the Lights on a Canvas and darkens a scene by it. The parts of the scene
+// without any light are darkened to the Ambient color.
+//
+//   lm := lighting.NewLightmap(pixelgl.NewCanvas(win.Bounds()))
+//   lm.Ambient = pixel.RGB(0.1, 0.1, 0.2)
+//   lm.Occluders = lighting.RectSegments(crate.Bounds())
+//   lm.Lights = append(lm.Lights, &lighting.Light{
+//       Pos:     torch.Pos,
+//       Radius:  200,
+//       Color:   pixel.RGB(1, 0.8, 0.5),
+//       Shadows: true,
+//   })
+//
+//   win.SetMatrix(cam.Matrix())
+//   world.Draw(win)
+//   lm.Render(cam.Matrix())
+//   win.SetMatrix(pixel.IM)
+//   lm.Draw(win)
+type Lightmap struct {
+	// Ambient is the light everywhere, black if nil.
+	Ambient color.Color
+
+	Lights    []*Light
+	Occluders []Segment
+
+	canvas  Canvas
+	falloff *pixel.PictureData
+	tris    *pixel.TrianglesData
+	d       pixel.Drawer
+}
+
+// NewLightmap creates a new Lightmap accumulating the lights on the Canvas, which must be the size
+// of the Target the Lightmap is drawn onto.
+func NewLightmap(canvas Canvas) *Lightmap {
+	return &Lightmap{
+		canvas:  canvas,
+		falloff: falloff(64),
+		tris:    pixel.MakeTrianglesData(0),
+	}
+}
+
+// Canvas returns the Canvas of the Lightmap.
+func (lm *Lightmap) Canvas() Canvas {
+	return lm.canvas
+}
+
+// Render draws the Ambient and all the Lights onto the Canvas. The matrix transforms the world to
+// the Canvas, usually it's the Matrix of the Camera.
+func (lm *Lightmap) Render(matrix pixel.Matrix) {
+	ambient := pixel.RGB(0, 0, 0)
+	if lm.Ambient != nil {
+		ambient = pixel.ToRGBA(lm.Ambient)
+	}
+	// the Lightmap is opaque, so it only multiplies the scene
+	ambient.A = 1
+	lm.canvas.Clear(ambient)
+	lm.canvas.SetMatrix(matrix)
+	lm.canvas.SetColorMask(nil)
+	lm.canvas.SetComposeMethod(pixel.ComposePlus)
+
+	fb := lm.falloff.Bounds()
+	lm.tris.SetLen(0)
+	for _, l := range lm.Lights {
+		if l.Radius <= 0 {
+			continue
+		}
+		poly := l.Polygon(lm.Occluders)
+		// map the circle of the Light onto the falloff Picture
+		tex := func(u pixel.Vec) pixel.Vec {
+			return fb.Center().Add(u.Sub(l.Pos).Scaled(fb.W() / 2 / l.Radius))
+		}
+		n := len(poly)
+		triangles := n
+		if l.isCone() {
+			// the cone is a fan around its first point
+			triangles = n - 2
+		}
+		base := len(*lm.tris)
+		lm.tris.SetLen(base + 3*triangles)
+		for i := 0; i < triangles; i++ {
+			a, b, c := l.Pos, poly[i], poly[(i+1)%n]
+			if l.isCone() {
+				a, b, c = poly[0], poly[i+1], poly[i+2]
+			}
+			for j, v := range [...]pixel.Vec{a, b, c} {
+				(*lm.tris)[base+3*i+j].Position = v
+				(*lm.tris)[base+3*i+j].Picture = tex(v)
+				(*lm.tris)[base+3*i+j].Color = l.Color
+				(*lm.tris)[base+3*i+j].Intensity = 1
+			}
+		}
+	}
+	lm.d.Triangles = lm.tris
+	lm.d.Picture = lm.falloff
+	lm.d.Dirty()
+	lm.d.Draw(lm.canvas)
+
+	lm.canvas.SetComposeMethod(pixel.ComposeOver)
+	lm.canvas.SetMatrix(pixel.IM)
+}
+
+// Draw darkens the Target by the Canvas of the Lightmap, rendered by Render. The Canvas is drawn
+// over the bounds of the Target like a Sprite, the Target's Matrix must be pixel.IM.
+func (lm *Lightmap) Draw(t pixel.ComposeTarget) {
+	t.SetComposeMethod(pixel.ComposeMultiply)
+	lm.canvas.DrawColorMask(t, pixel.IM.Moved(lm.canvas.Bounds().Center()), nil)
+	t.SetComposeMethod(pixel.ComposeOver)
+}
+
+// falloff returns a square Picture of the size with a white circle fading to transparent from the
+// center to the edge.
+func falloff(size int) *pixel.PictureData {
+	pd := pixel.MakePictureData(pixel.R(0, 0, float64(size), float64(size)))
+	half := float64(size) / 2
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			d := pixel.V(float64(x)+0.5-half, float64(y)+0.5-half).Len() / half
+			v := math.Max(0, 1-d)
+			v *= v
+			c := uint8(math.Round(v * 255))
+			pd.Pix[y*size+x] = color.RGBA{R: c, G: c, B: c, A: c}
+		}
+	}
+	return pd
+}
@@ -0,0 +1,70 @@
+package lighting_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/lighting"
+	"github.com/faiface/pixel/raster"
+	"github.com/stretchr/testify/assert"
+)
+
+// inside reports whether the point is inside the polygon.
+func inside(poly []pixel.Vec, u pixel.Vec) bool {
+	in := false
+	for i := range poly {
+		a, b := poly[i], poly[(i+1)%len(poly)]
+		if (a.Y > u.Y) != (b.Y > u.Y) && u.X < a.X+(u.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			in = !in
+		}
+	}
+	return in
+}
+
+func TestLight_Polygon(t *testing.T) {
+	wall := lighting.RectSegments(pixel.R(2, -1, 3, 1))
+	l := &lighting.Light{Pos: pixel.ZV, Radius: 10, Shadows: true}
+
+	poly := l.Polygon(wall)
+	assert.True(t, inside(poly, pixel.V(1, 0)))
+	assert.True(t, inside(poly, pixel.V(-5, 0)))
+	assert.True(t, inside(poly, pixel.V(5, 3)))
+	// in the shadow of the wall
+	assert.False(t, inside(poly, pixel.V(5, 0)))
+	// out of the reach
+	assert.False(t, inside(poly, pixel.V(0, 11)))
+
+	l.Shadows = false
+	assert.True(t, inside(l.Polygon(wall), pixel.V(5, 0)))
+
+	// a cone to the right
+	cone := &lighting.Light{Pos: pixel.ZV, Radius: 10, Cone: math.Pi / 4}
+	poly = cone.Polygon(nil)
+	assert.Equal(t, pixel.ZV, poly[0])
+	assert.True(t, inside(poly, pixel.V(5, 1)))
+	assert.False(t, inside(poly, pixel.V(-5, 0)))
+	assert.False(t, inside(poly, pixel.V(1, 5)))
+}
+
+func TestLightmap(t *testing.T) {
+	bounds := pixel.R(0, 0, 20, 10)
+	lm := lighting.NewLightmap(raster.NewCanvas(bounds))
+	lm.Ambient = pixel.RGB(0.2, 0.2, 0.2)
+	lm.Occluders = lighting.RectSegments(pixel.R(12, 0, 13, 10))
+	lm.Lights = []*lighting.Light{{Pos: pixel.V(10, 5), Radius: 8, Color: pixel.RGB(1, 1, 1), Shadows: true}}
+	lm.Render(pixel.IM)
+
+	// a white scene lit by the lightmap
+	scene := raster.NewCanvas(bounds)
+	scene.Clear(pixel.RGB(1, 1, 1))
+	lm.Draw(scene)
+
+	lit := scene.Color(pixel.V(10, 5))
+	shadow := scene.Color(pixel.V(15, 5))
+	dark := scene.Color(pixel.V(1, 9))
+	assert.True(t, lit.R > 0.9, "lit %v", lit)
+	assert.InDelta(t, 0.2, shadow.R, 0.01)
+	assert.InDelta(t, 0.2, dark.R, 0.01)
+	assert.Equal(t, 1.0, lit.A)
+}
@@ -0,0 +1,95 @@
+package lighting
+
+import (
+	"math"
+	"sort"
+
+	"github.com/faiface/pixel"
+)
+
+// Segment is a line segment from A to B which blocks the light, an edge of a wall or an object.
+type Segment struct {
+	A, B pixel.Vec
+}
+
+// RectSegments returns the four edges of the rectangle.
+func RectSegments(r pixel.Rect) []Segment {
+	return PolygonSegments([]pixel.Vec{
+		r.Min,
+		pixel.V(r.Max.X, r.Min.Y),
+		r.Max,
+		pixel.V(r.Min.X, r.Max.Y),
+	})
+}
+
+// PolygonSegments returns the edges of the closed polygon.
+func PolygonSegments(points []pixel.Vec) []Segment {
+	segments := make([]Segment, len(points))
+	for i := range points {
+		segments[i] = Segment{points[i], points[(i+1)%len(points)]}
+	}
+	return segments
+}
+
+// arcSteps is the number of the points a full circle of the light is made of.
+const arcSteps = 64
+
+// visibility returns the points of the area lit from the position within the radius, between the
+// angles from and to in the counter-clockwise order. The segments block the light.
+func visibility(pos pixel.Vec, radius, from, to float64, segments []Segment) []pixel.Vec {
+	// the rays go along the edges of the arc and to the ends of the segments, around which the
+	// shadows begin
+	angles := []float64{from, to}
+	for i := 1; i < arcSteps; i++ {
+		angles = append(angles, from+(to-from)*float64(i)/arcSteps)
+	}
+	const eps = 1e-4
+	for _, s := range segments {
+		for _, end := range [...]pixel.Vec{s.A, s.B} {
+			d := end.Sub(pos)
+			if d.Len() > radius {
+				continue
+			}
+			a := d.Angle()
+			for _, da := range [...]float64{-eps, 0, eps} {
+				// move the angle into the range of the arc
+				angle := a + da
+				angle -= 2 * math.Pi * math.Floor((angle-from)/(2*math.Pi))
+				if angle <= to {
+					angles = append(angles, angle)
+				}
+			}
+		}
+	}
+	sort.Float64s(angles)
+
+	points := make([]pixel.Vec, 0, len(angles))
+	for _, angle := range angles {
+		dir := pixel.Unit(angle)
+		dist := radius
+		for _, s := range segments {
+			if t, ok := raySegment(pos, dir, s); ok && t < dist {
+				dist = t
+			}
+		}
+		points = append(points, pos.Add(dir.Scaled(dist)))
+	}
+	return points
+}
+
+// raySegment returns the distance along the ray from the origin in the unit direction to the
+// Segment, if the ray hits it.
+func raySegment(origin, dir pixel.Vec, s Segment) (float64, bool) {
+	e := s.B.Sub(s.A)
+	denom := dir.Cross(e)
+	if denom == 0 {
+		return 0, false
+	}
+	w := s.A.Sub(origin)
+	t := w.Cross(e) / denom
+	u := w.Cross(dir) / denom
+	if t < 0 || u < 0 || u > 1 {
+		return 0, false
+	}
+	return t, true
+}
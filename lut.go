@@ -0,0 +1,148 @@
+package pixel
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LUT is a 3D color lookup table, which maps every color to another one. It's the usual way to
+// apply a color grading made in an image or a video editor (such as a cinematic look, or a colder
+// palette for the night) to a game.
+//
+// The Table holds the output colors for a grid of Size*Size*Size input colors evenly spread over
+// the domain, with the red changing the fastest and the blue the slowest. The colors between the
+// grid points are interpolated.
+//
+// A LUT transforms single colors, a pixelgl.Canvas applies it as a post-process (see
+// pixelgl.LUTFragmentShader).
+type LUT struct {
+	Title     string
+	Size      int
+	DomainMin [3]float64
+	DomainMax [3]float64
+	Table     [][3]float64
+}
+
+// LoadCubeLUT loads a 3D LUT in the Adobe/Resolve .cube format, which most color grading tools
+// export.
+func LoadCubeLUT(r io.Reader) (*LUT, error) {
+	lut := &LUT{DomainMax: [3]float64{1, 1, 1}}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		var err error
+		switch fields[0] {
+		case "TITLE":
+			lut.Title = strings.Trim(strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "TITLE")), `"`)
+		case "LUT_3D_SIZE":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("pixel: line %d: invalid LUT_3D_SIZE", line)
+			}
+			lut.Size, err = strconv.Atoi(fields[1])
+			if err == nil && (lut.Size < 2 || lut.Size > 256) {
+				err = fmt.Errorf("size %d out of range [2, 256]", lut.Size)
+			}
+		case "LUT_1D_SIZE":
+			return nil, fmt.Errorf("pixel: line %d: 1D LUT not supported", line)
+		case "DOMAIN_MIN":
+			lut.DomainMin, err = parseTriple(fields[1:])
+		case "DOMAIN_MAX":
+			lut.DomainMax, err = parseTriple(fields[1:])
+		default:
+			if lut.Size == 0 {
+				return nil, fmt.Errorf("pixel: line %d: LUT data before LUT_3D_SIZE", line)
+			}
+			var rgb [3]float64
+			rgb, err = parseTriple(fields)
+			lut.Table = append(lut.Table, rgb)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("pixel: line %d: %v", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("pixel: reading LUT: %v", err)
+	}
+
+	if lut.Size == 0 {
+		return nil, fmt.Errorf("pixel: missing LUT_3D_SIZE")
+	}
+	if want := lut.Size * lut.Size * lut.Size; len(lut.Table) != want {
+		return nil, fmt.Errorf("pixel: LUT has %d entries, want %d", len(lut.Table), want)
+	}
+	for i := range lut.DomainMin {
+		if lut.DomainMin[i] >= lut.DomainMax[i] {
+			return nil, fmt.Errorf("pixel: invalid LUT domain")
+		}
+	}
+	return lut, nil
+}
+
+func parseTriple(fields []string) ([3]float64, error) {
+	var t [3]float64
+	if len(fields) != 3 {
+		return t, fmt.Errorf("expected 3 numbers, got %d", len(fields))
+	}
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return t, err
+		}
+		t[i] = v
+	}
+	return t, nil
+}
+
+// Apply returns the color mapped by the LUT, interpolated between the nearest entries of the
+// Table. The colors outside of the domain are clamped into it. The alpha is kept and the resulting
+// components are clamped to the valid range.
+func (l *LUT) Apply(c RGBA) RGBA {
+	if c.A == 0 {
+		return c
+	}
+	in := [3]float64{c.R / c.A, c.G / c.A, c.B / c.A}
+
+	// the position in the grid and the fractions between the grid points
+	var i0, i1 [3]int
+	var f [3]float64
+	n := float64(l.Size - 1)
+	for k := range in {
+		v := (in[k] - l.DomainMin[k]) / (l.DomainMax[k] - l.DomainMin[k])
+		v = math.Max(0, math.Min(1, v)) * n
+		fl := math.Floor(v)
+		i0[k] = int(fl)
+		i1[k] = int(math.Min(fl+1, n))
+		f[k] = v - fl
+	}
+
+	at := func(r, g, b int) [3]float64 {
+		return l.Table[(b*l.Size+g)*l.Size+r]
+	}
+	lerp := func(a, b [3]float64, t float64) [3]float64 {
+		return [3]float64{
+			a[0] + (b[0]-a[0])*t,
+			a[1] + (b[1]-a[1])*t,
+			a[2] + (b[2]-a[2])*t,
+		}
+	}
+
+	// trilinear interpolation
+	c00 := lerp(at(i0[0], i0[1], i0[2]), at(i1[0], i0[1], i0[2]), f[0])
+	c10 := lerp(at(i0[0], i1[1], i0[2]), at(i1[0], i1[1], i0[2]), f[0])
+	c01 := lerp(at(i0[0], i0[1], i1[2]), at(i1[0], i0[1], i1[2]), f[0])
+	c11 := lerp(at(i0[0], i1[1], i1[2]), at(i1[0], i1[1], i1[2]), f[0])
+	out := lerp(lerp(c00, c10, f[1]), lerp(c01, c11, f[1]), f[2])
+
+	return clampRGBA(RGB(out[0], out[1], out[2]).Scaled(c.A))
+}
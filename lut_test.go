@@ -0,0 +1,73 @@
+package pixel_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+const invertCube = `# inverts the colors
+TITLE "Invert"
+LUT_3D_SIZE 2
+
+1 1 1
+0 1 1
+1 0 1
+0 0 1
+1 1 0
+0 1 0
+1 0 0
+0 0 0
+`
+
+func TestLoadCubeLUT(t *testing.T) {
+	lut, err := pixel.LoadCubeLUT(strings.NewReader(invertCube))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lut.Title != "Invert" || lut.Size != 2 || len(lut.Table) != 8 {
+		t.Errorf("loaded %q of size %d with %d entries", lut.Title, lut.Size, len(lut.Table))
+	}
+
+	tests := []struct {
+		in, want pixel.RGBA
+	}{
+		{pixel.RGB(0, 0, 0), pixel.RGB(1, 1, 1)},
+		{pixel.RGB(1, 0, 0), pixel.RGB(0, 1, 1)},
+		{pixel.RGB(0.25, 0.5, 1), pixel.RGB(0.75, 0.5, 0)},
+		{pixel.RGB(0.2, 0.4, 0.6).Scaled(0.5), pixel.RGB(0.8, 0.6, 0.4).Scaled(0.5)},
+		{pixel.Alpha(0), pixel.Alpha(0)},
+	}
+	for _, tt := range tests {
+		if got := lut.Apply(tt.in); !eqColors(got, tt.want) {
+			t.Errorf("Apply(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLoadCubeLUTDomain(t *testing.T) {
+	lut, err := pixel.LoadCubeLUT(strings.NewReader("DOMAIN_MIN 0 0 0\nDOMAIN_MAX 2 2 2\n" + invertCube))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := lut.Apply(pixel.RGB(1, 0, 0)), pixel.RGB(0.5, 1, 1); !eqColors(got, want) {
+		t.Errorf("Apply = %v, want %v", got, want)
+	}
+}
+
+func TestLoadCubeLUTErrors(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"0 0 0\n",
+		"LUT_3D_SIZE 2\n0 0 0\n",
+		"LUT_3D_SIZE 1\n0 0 0\n",
+		"LUT_1D_SIZE 2\n0 0 0\n1 1 1\n",
+		"LUT_3D_SIZE 2\n" + strings.Repeat("0 0\n", 8),
+		"DOMAIN_MIN 1 0 0\nDOMAIN_MAX 1 1 1\n" + invertCube,
+	} {
+		if _, err := pixel.LoadCubeLUT(strings.NewReader(src)); err == nil {
+			t.Errorf("LoadCubeLUT(%q) succeeded, want error", src)
+		}
+	}
+}
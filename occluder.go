@@ -0,0 +1,145 @@
+package pixel
+
+import "math"
+
+// Occluder is a Renderable which hides what's drawn behind it. OpaqueBounds returns a rectangle
+// fully covered by the object's opaque pixels, or a zero Rect if there's no such rectangle.
+//
+// With Renderer.Occlusion on, the objects behind the OpaqueBounds of an Occluder drawn after them
+// are not drawn at all, which saves the shading of the hidden pixels in heavily overdrawn scenes,
+// such as stacked tile layers.
+type Occluder interface {
+	Renderable
+	OpaqueBounds() Rect
+}
+
+// OpaqueDrawer is a Renderable which tells whether all the pixels it draws are opaque.
+//
+// On a DepthTarget, a Renderer with Occlusion on draws the opaque objects first, from the front to
+// the back, writing their depth, and the rest after them, from the back to the front, so the GPU
+// skips shading the pixels hidden behind opaque ones, even behind the opaque parts of rotated or
+// overlapping objects the Occluders can't describe.
+type OpaqueDrawer interface {
+	Renderable
+	DrawsOpaque() bool
+}
+
+// DepthTarget is a Target with a depth buffer, such as the pixelgl Canvas and Window.
+type DepthTarget interface {
+	Target
+
+	// SetDepth sets the depth of the following draws, from 0, the nearest, to 1, the farthest.
+	// The draws only cover the pixels where nothing nearer was drawn, and write their depth if
+	// write is true. A negative depth turns the depth test off, which is the default.
+	SetDepth(depth float64, write bool)
+
+	// ClearDepth sets the depth of all the pixels to the farthest.
+	ClearDepth()
+}
+
+var (
+	_ Occluder     = (*SpriteRenderable)(nil)
+	_ OpaqueDrawer = (*SpriteRenderable)(nil)
+)
+
+// OpaqueBounds returns the Bounds of the Sprite if it's Opaque, drawn with an opaque Mask and not
+// rotated other than by a multiple of 90 degrees, otherwise a zero Rect.
+func (sr *SpriteRenderable) OpaqueBounds() Rect {
+	if !sr.Opaque {
+		return Rect{}
+	}
+	if sr.Mask != nil && ToRGBA(sr.Mask).A < 1 {
+		return Rect{}
+	}
+	m := sr.Matrix
+	if !(zero(m[1]) && zero(m[2])) && !(zero(m[0]) && zero(m[3])) {
+		// the bounds of a rotated Sprite aren't covered by it
+		return Rect{}
+	}
+	return sr.Bounds()
+}
+
+// DrawsOpaque returns whether the Sprite is Opaque and drawn with an opaque Mask.
+func (sr *SpriteRenderable) DrawsOpaque() bool {
+	return sr.Opaque && (sr.Mask == nil || ToRGBA(sr.Mask).A >= 1)
+}
+
+// zero returns whether x is zero up to the error of the rotation by a multiple of 90 degrees
+func zero(x float64) bool {
+	return math.Abs(x) < 1e-9
+}
+
+// occlusionGridSize is the number of cells of an occlusionGrid in each direction.
+const occlusionGridSize = 16
+
+// occlusionGrid is a software coverage buffer, it keeps the opaque rectangles within a view in
+// the cells they overlap, so that finding the ones covering a rectangle only goes over one cell.
+type occlusionGrid struct {
+	view  Rect
+	cells [occlusionGridSize * occlusionGridSize][]Rect
+}
+
+// reset removes all the rectangles from the grid and sets its view
+func (g *occlusionGrid) reset(view Rect) {
+	g.view = view.Norm()
+	for i := range g.cells {
+		g.cells[i] = g.cells[i][:0]
+	}
+}
+
+// cell returns the coordinates of the cell the point lies in, the points outside of the view
+// belong to the nearest cell
+func (g *occlusionGrid) cell(u Vec) (x, y int) {
+	if g.view.W() > 0 {
+		x = int(math.Floor((u.X - g.view.Min.X) / g.view.W() * occlusionGridSize))
+	}
+	if g.view.H() > 0 {
+		y = int(math.Floor((u.Y - g.view.Min.Y) / g.view.H() * occlusionGridSize))
+	}
+	if x < 0 {
+		x = 0
+	}
+	if x > occlusionGridSize-1 {
+		x = occlusionGridSize - 1
+	}
+	if y < 0 {
+		y = 0
+	}
+	if y > occlusionGridSize-1 {
+		y = occlusionGridSize - 1
+	}
+	return x, y
+}
+
+// add adds the opaque rectangle to the grid
+func (g *occlusionGrid) add(r Rect) {
+	r = r.Norm().Intersect(g.view)
+	if r.Area() == 0 {
+		return
+	}
+	x0, y0 := g.cell(r.Min)
+	x1, y1 := g.cell(r.Max)
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			i := y*occlusionGridSize + x
+			g.cells[i] = append(g.cells[i], r)
+		}
+	}
+}
+
+// hidden returns whether the part of the rectangle within the view is covered by one of the opaque
+// rectangles of the grid
+func (g *occlusionGrid) hidden(r Rect) bool {
+	r = r.Norm().Intersect(g.view)
+	if r.Area() == 0 {
+		return false
+	}
+	// an opaque rectangle covering r contains its Min, so it was added to the cell of r.Min
+	x, y := g.cell(r.Min)
+	for _, o := range g.cells[y*occlusionGridSize+x] {
+		if o.Min.X <= r.Min.X && o.Min.Y <= r.Min.Y && r.Max.X <= o.Max.X && r.Max.Y <= o.Max.Y {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package pixel
+
+import (
+	"image/color"
+	"math"
+)
+
+// Repeat is a way a ParallaxLayer repeats its Picture.
+type Repeat int
+
+const (
+	// RepeatNone draws the Picture once.
+	RepeatNone Repeat = iota
+
+	// RepeatX repeats the Picture horizontally, e.g. for hills or clouds.
+	RepeatX
+
+	// RepeatY repeats the Picture vertically.
+	RepeatY
+
+	// RepeatXY repeats the Picture in both directions, e.g. for a starfield.
+	RepeatXY
+)
+
+// ParallaxLayer is a background (or foreground) layer of a Parallax.
+type ParallaxLayer struct {
+	// Picture and Frame are the image of the layer, the Frame is a portion of the Picture. A zero
+	// Frame means the Picture's bounds.
+	Picture Picture
+	Frame   Rect
+
+	// Scroll is how fast the layer scrolls with the Camera along each axis. 1 scrolls as fast as
+	// the world, so the layer is a part of it. 0 doesn't scroll at all, so it stays fixed on the
+	// screen, like the sky. The values between make the layers appear far away, the values above 1
+	// make them appear closer than the world.
+	Scroll Vec
+
+	// Pos is the position of the Frame's bottom-left corner in the world when the Camera looks at
+	// the origin of the world.
+	Pos Vec
+
+	// Repeat is how the Picture repeats to fill the screen.
+	Repeat Repeat
+
+	// Color multiplies the colors of the layer, e.g. to fade the distant layers into the sky. Nil
+	// means no effect.
+	Color color.Color
+
+	d Drawer
+}
+
+// Parallax draws layers of backgrounds behind the world, which scroll slower than it. This makes
+// them appear far away.
+//
+//   bg := pixel.NewParallax()
+//   bg.Add(&pixel.ParallaxLayer{Picture: sky, Scroll: pixel.V(0, 0)})
+//   bg.Add(&pixel.ParallaxLayer{Picture: mountains, Scroll: pixel.V(0.2, 0.1), Repeat: pixel.RepeatX})
+//   bg.Add(&pixel.ParallaxLayer{Picture: trees, Scroll: pixel.V(0.6, 0.5), Repeat: pixel.RepeatX})
+//
+//   win.SetMatrix(cam.Matrix())
+//   bg.Draw(win, cam)
+//   world.Draw(win)
+type Parallax struct {
+	// Layers are drawn in their order, the farthest should be the first.
+	Layers []*ParallaxLayer
+
+	// Snap aligns the layers to the whole pixels of the Target. With slowly scrolling layers and no
+	// smoothing, it removes the shimmering of the pixel art.
+	Snap bool
+}
+
+// NewParallax creates a new Parallax without any layers.
+func NewParallax() *Parallax {
+	return &Parallax{}
+}
+
+// Add adds the layer in front of the existing ones.
+func (p *Parallax) Add(layer *ParallaxLayer) {
+	p.Layers = append(p.Layers, layer)
+}
+
+// Draw draws the layers covering everything visible by the Camera onto the Target. The Target's
+// Matrix must be the Camera's Matrix, the same one the world is drawn with.
+func (p *Parallax) Draw(t Target, cam *Camera) {
+	visible := cam.VisibleBounds()
+	for _, layer := range p.Layers {
+		layer.draw(t, cam, visible, p.Snap)
+	}
+}
+
+func (l *ParallaxLayer) draw(t Target, cam *Camera, visible Rect, snap bool) {
+	if l.Picture == nil {
+		return
+	}
+	frame := l.Frame
+	if frame == (Rect{}) {
+		frame = l.Picture.Bounds()
+	}
+	size := frame.Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return
+	}
+
+	// the layer lags behind the Camera by the part it doesn't scroll
+	origin := l.Pos.Add(cam.Pos.ScaledXY(V(1-l.Scroll.X, 1-l.Scroll.Y)))
+	if snap {
+		origin = cam.Snapped(origin)
+	}
+
+	// the range of the tiles covering the visible rectangle
+	tiles := func(repeat bool, origin, size, min, max float64) (first, last int) {
+		if !repeat {
+			return 0, 0
+		}
+		return int(math.Floor((min - origin) / size)), int(math.Ceil((max-origin)/size)) - 1
+	}
+	x0, x1 := tiles(l.Repeat == RepeatX || l.Repeat == RepeatXY, origin.X, size.X, visible.Min.X, visible.Max.X)
+	y0, y1 := tiles(l.Repeat == RepeatY || l.Repeat == RepeatXY, origin.Y, size.Y, visible.Min.Y, visible.Max.Y)
+
+	mask := Alpha(1)
+	if l.Color != nil {
+		mask = ToRGBA(l.Color)
+	}
+
+	td, ok := l.d.Triangles.(*TrianglesData)
+	if !ok {
+		td = MakeTrianglesData(0)
+		l.d.Triangles = td
+	}
+	td.SetLen(6 * (x1 - x0 + 1) * (y1 - y0 + 1))
+	i := 0
+	for ty := y0; ty <= y1; ty++ {
+		for tx := x0; tx <= x1; tx++ {
+			min := origin.Add(V(float64(tx)*size.X, float64(ty)*size.Y))
+			corners := [...][2]Vec{
+				{min, frame.Min},
+				{min.Add(V(size.X, 0)), V(frame.Max.X, frame.Min.Y)},
+				{min.Add(size), frame.Max},
+				{min.Add(V(0, size.Y)), V(frame.Min.X, frame.Max.Y)},
+			}
+			for _, k := range [...]int{0, 1, 2, 0, 2, 3} {
+				(*td)[i].Position = corners[k][0]
+				(*td)[i].Picture = corners[k][1]
+				(*td)[i].Color = mask
+				(*td)[i].Intensity = 1
+				i++
+			}
+		}
+	}
+	l.d.Picture = l.Picture
+	l.d.Dirty()
+	l.d.Draw(t)
+}
@@ -0,0 +1,88 @@
+package pixel_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+)
+
+func TestParallax(t *testing.T) {
+	// two pixels, red and blue
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 1))
+	pic.Pix[0] = color.RGBA{R: 255, A: 255}
+	pic.Pix[1] = color.RGBA{B: 255, A: 255}
+	red, blue := pixel.RGB(1, 0, 0), pixel.RGB(0, 0, 1)
+
+	cam := pixel.NewCamera(pixel.R(0, 0, 8, 1))
+	bg := pixel.NewParallax()
+	bg.Add(&pixel.ParallaxLayer{Picture: pic, Scroll: pixel.V(0.5, 1), Repeat: pixel.RepeatX})
+
+	draw := func() *raster.Canvas {
+		c := raster.NewCanvas(cam.Screen)
+		c.SetMatrix(cam.Matrix())
+		bg.Draw(c, cam)
+		return c
+	}
+	row := func(c *raster.Canvas) []pixel.RGBA {
+		var colors []pixel.RGBA
+		for x := 0.0; x < 8; x++ {
+			colors = append(colors, c.Color(pixel.V(x, 0)))
+		}
+		return colors
+	}
+	check := func(name string, got []pixel.RGBA, first, second pixel.RGBA) {
+		t.Helper()
+		for i, c := range got {
+			want := first
+			if i%2 == 1 {
+				want = second
+			}
+			if c != want {
+				t.Errorf("%s: pixel %d is %v, want %v", name, i, c, want)
+			}
+		}
+	}
+
+	// the layer repeats over the whole screen
+	cam.Pos = pixel.V(4, 0.5)
+	check("start", row(draw()), red, blue)
+
+	// the layer moves by a half of the Camera's movement
+	cam.Pos = pixel.V(6, 0.5)
+	check("moved", row(draw()), blue, red)
+	cam.Pos = pixel.V(-100, 0.5)
+	check("far", row(draw()), red, blue)
+
+	// a half pixel shift is rounded to a whole pixel
+	cam.Pos = pixel.V(5, 0.5)
+	bg.Snap = true
+	check("snapped", row(draw()), red, blue)
+}
+
+func TestParallaxNoRepeat(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 1))
+	for i := range pic.Pix {
+		pic.Pix[i] = color.RGBA{G: 255, A: 255}
+	}
+
+	cam := pixel.NewCamera(pixel.R(0, 0, 8, 1))
+	cam.Pos = pixel.V(4, 0.5)
+	bg := pixel.NewParallax()
+	bg.Add(&pixel.ParallaxLayer{Picture: pic, Scroll: pixel.V(0, 0), Pos: pixel.V(-1, -0.5)})
+
+	c := raster.NewCanvas(cam.Screen)
+	c.SetMatrix(cam.Matrix())
+	bg.Draw(c, cam)
+	// the layer doesn't scroll, it's fixed in the center of the screen
+	for x := 0.0; x < 8; x++ {
+		want := pixel.Alpha(0)
+		if x == 3 || x == 4 {
+			want = pixel.RGB(0, 1, 0)
+		}
+		if got := c.Color(pixel.V(x, 0)); got != want {
+			t.Errorf("pixel %v is %v, want %v", x, got, want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package particles
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Affector changes the particles of an Emitter on every Update, usually their velocity.
+type Affector interface {
+	Affect(p *Particle, dt float64)
+}
+
+// AffectorFunc is a function which is an Affector.
+type AffectorFunc func(p *Particle, dt float64)
+
+// Affect calls the function.
+func (af AffectorFunc) Affect(p *Particle, dt float64) {
+	af(p, dt)
+}
+
+// Gravity returns an Affector accelerating the particles by the acceleration, in the world units
+// per second squared.
+func Gravity(acceleration pixel.Vec) Affector {
+	return AffectorFunc(func(p *Particle, dt float64) {
+		p.Vel = p.Vel.Add(acceleration.Scaled(dt))
+	})
+}
+
+// Drag returns an Affector slowing the particles down, like the air. The particles lose the part
+// of their velocity given by the amount in a second.
+func Drag(amount float64) Affector {
+	return AffectorFunc(func(p *Particle, dt float64) {
+		p.Vel = p.Vel.Scaled(math.Pow(1-amount, dt))
+	})
+}
+
+// Attractor is an Affector pulling the particles towards its position, or pushing them away with a
+// negative Strength.
+type Attractor struct {
+	Pos pixel.Vec
+
+	// Strength is the acceleration towards the Pos, in the world units per second squared.
+	Strength float64
+
+	// Radius limits the distance the Attractor reaches to, with the acceleration going down to zero
+	// towards it. Zero means unlimited with the same acceleration everywhere.
+	Radius float64
+}
+
+// Affect accelerates the particle towards the Attractor.
+func (a *Attractor) Affect(p *Particle, dt float64) {
+	d := a.Pos.Sub(p.Pos)
+	dist := d.Len()
+	if dist == 0 || (a.Radius > 0 && dist >= a.Radius) {
+		return
+	}
+	strength := a.Strength
+	if a.Radius > 0 {
+		strength *= 1 - dist/a.Radius
+	}
+	p.Vel = p.Vel.Add(d.Scaled(strength * dt / dist))
+}
@@ -0,0 +1,80 @@
+package particles
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/faiface/pixel"
+)
+
+// Range is a range of values, a random one of which is picked for each particle.
+type Range struct {
+	Min, Max float64
+}
+
+// Fixed returns a Range of a single value.
+func Fixed(v float64) Range {
+	return Range{v, v}
+}
+
+// pick returns a random value within the Range.
+func (r Range) pick(rnd *rand.Rand) float64 {
+	if r.Min == r.Max {
+		return r.Min
+	}
+	return r.Min + rnd.Float64()*(r.Max-r.Min)
+}
+
+// Key is a value of a Curve at the time T, within range [0, 1] from the birth to the death of a
+// particle.
+type Key struct {
+	T, Value float64
+}
+
+// Curve is a value changing over the life of a particle, linearly between its Keys. The Keys must be
+// sorted by their T. Before the first Key and after the last one, the value stays the same. An
+// empty Curve is 1 all the time.
+type Curve []Key
+
+// At returns the value of the Curve at the time t.
+func (c Curve) At(t float64) float64 {
+	if len(c) == 0 {
+		return 1
+	}
+	i := sort.Search(len(c), func(i int) bool { return c[i].T > t })
+	if i == 0 {
+		return c[0].Value
+	}
+	if i == len(c) {
+		return c[len(c)-1].Value
+	}
+	a, b := c[i-1], c[i]
+	return a.Value + (b.Value-a.Value)*(t-a.T)/(b.T-a.T)
+}
+
+// ColorKey is a color of a ColorCurve at the time T, within range [0, 1] from the birth to the
+// death of a particle.
+type ColorKey struct {
+	T     float64
+	Color pixel.RGBA
+}
+
+// ColorCurve is a color changing over the life of a particle, linearly between its Keys. The Keys
+// must be sorted by their T. An empty ColorCurve is opaque white all the time.
+type ColorCurve []ColorKey
+
+// At returns the color of the ColorCurve at the time t.
+func (c ColorCurve) At(t float64) pixel.RGBA {
+	if len(c) == 0 {
+		return pixel.Alpha(1)
+	}
+	i := sort.Search(len(c), func(i int) bool { return c[i].T > t })
+	if i == 0 {
+		return c[0].Color
+	}
+	if i == len(c) {
+		return c[len(c)-1].Color
+	}
+	a, b := c[i-1], c[i]
+	return a.Color.Lerp(b.Color, (t-a.T)/(b.T-a.T))
+}
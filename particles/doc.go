@@ -0,0 +1,4 @@
+// Package particles implements particle systems: emitters spawning particles in shapes, moving
+// them with affectors such as gravity and attractors, changing them over their life by curves and
+// drawing them in batches.
+package particles
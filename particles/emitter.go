@@ -0,0 +1,236 @@
+package particles
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/faiface/pixel"
+)
+
+// Particle is a single particle of an Emitter.
+type Particle struct {
+	Pos, Vel pixel.Vec
+
+	// Age and Life are the time the particle lives and the time it dies at, in seconds.
+	Age, Life float64
+
+	// Size is the starting size of the particle, multiplied by the SizeOverLife over its life.
+	Size float64
+
+	// Angle is its rotation in radians and Spin is the speed of the rotation in radians per second.
+	Angle, Spin float64
+}
+
+// Emitter spawns particles, moves them and draws them all at once.
+//
+//   fire := particles.NewEmitter()
+//   fire.Shape = particles.CircleShape(4)
+//   fire.Rate = 200
+//   fire.Life = particles.Range{Min: 0.5, Max: 1}
+//   fire.Direction, fire.Spread = math.Pi/2, 0.5
+//   fire.Speed = particles.Range{Min: 40, Max: 80}
+//   fire.Color = particles.ColorCurve{
+//       {T: 0, Color: pixel.RGB(1, 0.9, 0.3)},
+//       {T: 1, Color: pixel.RGB(1, 0.2, 0).Scaled(0)},
+//   }
+//   fire.Affectors = append(fire.Affectors, particles.Gravity(pixel.V(0, 30)))
+//
+//   fire.Pos = torch.Pos
+//   fire.Update(dt)
+//   fire.Draw(win)
+//
+// The particles are drawn as squares with the Picture, or untextured if it's nil. The whole Emitter
+// is drawn with a single draw call.
+type Emitter struct {
+	// Pos is the position of the Emitter in the world. Moving it doesn't move the living particles.
+	Pos pixel.Vec
+
+	// Shape is where the new particles spawn around the Pos, nil is the Pos itself.
+	Shape Shape
+
+	// Rate is the number of particles spawned in a second.
+	Rate float64
+
+	// Max limits the number of the living particles, zero means unlimited.
+	Max int
+
+	// Life is how long the particles live in seconds.
+	Life Range
+
+	// Direction is the angle the particles fly to and the Spread is how much it deviates on each
+	// side, in radians. The Spread of Pi spreads them to all directions.
+	Direction, Spread float64
+
+	// Speed is the starting speed of the particles, in the world units per second.
+	Speed Range
+
+	// StartSize is the starting size of the particles in the world units, the width of the Frame.
+	StartSize Range
+
+	// StartAngle and Spin are the starting rotation of the particles and its speed.
+	StartAngle, Spin Range
+
+	// SpeedOverLife, SizeOverLife and Color change the particles over their life. SpeedOverLife
+	// multiplies their velocity and SizeOverLife their StartSize.
+	SpeedOverLife Curve
+	SizeOverLife  Curve
+	Color         ColorCurve
+
+	// Affectors change the particles on every Update.
+	Affectors []Affector
+
+	// Picture and Frame are the image of the particles. A zero Frame means the Picture's bounds.
+	Picture pixel.Picture
+	Frame   pixel.Rect
+
+	// Arena, if set, is where the vertices of the particles are allocated on every draw, instead of
+	// a buffer kept by the Emitter. It must be reset between the frames.
+	Arena *pixel.Arena
+
+	particles []Particle
+	spawn     float64
+	rnd       *rand.Rand
+	tris      *pixel.TrianglesData
+	d         pixel.Drawer
+}
+
+// NewEmitter creates a new Emitter of the particles of the size 1 living for 1 second, flying to
+// all directions.
+func NewEmitter() *Emitter {
+	return &Emitter{
+		Life:      Fixed(1),
+		Spread:    math.Pi,
+		StartSize: Fixed(1),
+		rnd:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		tris:      pixel.MakeTrianglesData(0),
+	}
+}
+
+// Seed seeds the random numbers of the Emitter, so it spawns the same particles every time.
+func (e *Emitter) Seed(seed int64) {
+	e.rnd.Seed(seed)
+}
+
+// Len returns the number of the living particles.
+func (e *Emitter) Len() int {
+	return len(e.particles)
+}
+
+// Particles returns the living particles, which can be changed directly.
+func (e *Emitter) Particles() []Particle {
+	return e.particles
+}
+
+// Clear removes all particles.
+func (e *Emitter) Clear() {
+	e.particles = e.particles[:0]
+	e.spawn = 0
+}
+
+// Burst spawns n particles at once, e.g. for an explosion.
+func (e *Emitter) Burst(n int) {
+	for i := 0; i < n; i++ {
+		if e.Max > 0 && len(e.particles) >= e.Max {
+			return
+		}
+		e.particles = append(e.particles, e.newParticle())
+	}
+}
+
+func (e *Emitter) newParticle() Particle {
+	pos := e.Pos
+	if e.Shape != nil {
+		pos = pos.Add(e.Shape.Sample(e.rnd))
+	}
+	dir := e.Direction + (e.rnd.Float64()*2-1)*e.Spread
+	return Particle{
+		Pos:   pos,
+		Vel:   pixel.Unit(dir).Scaled(e.Speed.pick(e.rnd)),
+		Life:  e.Life.pick(e.rnd),
+		Size:  e.StartSize.pick(e.rnd),
+		Angle: e.StartAngle.pick(e.rnd),
+		Spin:  e.Spin.pick(e.rnd),
+	}
+}
+
+// Update spawns the new particles by the Rate, moves the living ones by the time dt in seconds and
+// removes the dead ones.
+func (e *Emitter) Update(dt float64) {
+	alive := e.particles[:0]
+	for _, p := range e.particles {
+		p.Age += dt
+		if p.Age >= p.Life {
+			continue
+		}
+		for _, a := range e.Affectors {
+			a.Affect(&p, dt)
+		}
+		p.Pos = p.Pos.Add(p.Vel.Scaled(e.SpeedOverLife.At(p.Age/p.Life) * dt))
+		p.Angle += p.Spin * dt
+		alive = append(alive, p)
+	}
+	e.particles = alive
+
+	e.spawn += e.Rate * dt
+	n := int(e.spawn)
+	e.spawn -= float64(n)
+	e.Burst(n)
+}
+
+// Draw draws the particles onto the Target.
+func (e *Emitter) Draw(t pixel.Target) {
+	e.DrawColorMask(t, nil)
+}
+
+// DrawColorMask draws the particles onto the Target with all their colors multiplied by the mask.
+// If the mask is nil, it has no effect.
+func (e *Emitter) DrawColorMask(t pixel.Target, mask color.Color) {
+	m := pixel.Alpha(1)
+	if mask != nil {
+		m = pixel.ToRGBA(mask)
+	}
+
+	frame := e.Frame
+	if e.Picture != nil && frame == (pixel.Rect{}) {
+		frame = e.Picture.Bounds()
+	}
+	aspect := 1.0
+	if frame.W() > 0 {
+		aspect = frame.H() / frame.W()
+	}
+	texCorners := [...]pixel.Vec{frame.Min, pixel.V(frame.Max.X, frame.Min.Y), frame.Max, pixel.V(frame.Min.X, frame.Max.Y)}
+
+	tris := e.tris
+	if e.Arena != nil {
+		tris = e.Arena.Triangles(6 * len(e.particles))
+	} else {
+		tris.SetLen(6 * len(e.particles))
+	}
+	for i, p := range e.particles {
+		life := p.Age / p.Life
+		half := pixel.V(1, aspect).Scaled(p.Size * e.SizeOverLife.At(life) / 2)
+		col := e.Color.At(life).Mul(m)
+		corners := [...]pixel.Vec{
+			pixel.V(-half.X, -half.Y),
+			pixel.V(half.X, -half.Y),
+			half,
+			pixel.V(-half.X, half.Y),
+		}
+		for j, k := range [...]int{0, 1, 2, 0, 2, 3} {
+			v := &(*tris)[i*6+j]
+			v.Position = p.Pos.Add(corners[k].Rotated(p.Angle))
+			v.Picture = texCorners[k]
+			v.Color = col
+			v.Intensity = 0
+			if e.Picture != nil {
+				v.Intensity = 1
+			}
+		}
+	}
+	e.d.Triangles = tris
+	e.d.Picture = e.Picture
+	e.d.Dirty()
+	e.d.Draw(t)
+}
@@ -0,0 +1,136 @@
+package particles_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/particles"
+	"github.com/faiface/pixel/raster"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurve(t *testing.T) {
+	assert.Equal(t, 1.0, particles.Curve(nil).At(0.5))
+
+	c := particles.Curve{{T: 0, Value: 2}, {T: 0.5, Value: 4}, {T: 1, Value: 0}}
+	assert.Equal(t, 2.0, c.At(-1))
+	assert.Equal(t, 3.0, c.At(0.25))
+	assert.Equal(t, 4.0, c.At(0.5))
+	assert.Equal(t, 2.0, c.At(0.75))
+	assert.Equal(t, 0.0, c.At(2))
+
+	cc := particles.ColorCurve{{T: 0, Color: pixel.RGB(1, 0, 0)}, {T: 1, Color: pixel.RGB(0, 0, 1)}}
+	assert.Equal(t, pixel.RGB(0.5, 0, 0.5), cc.At(0.5))
+	assert.Equal(t, pixel.Alpha(1), particles.ColorCurve(nil).At(0.5))
+}
+
+func TestShapes(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	r := pixel.R(-2, 1, 3, 4)
+	a, b := pixel.V(0, 0), pixel.V(4, 2)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, pixel.ZV, particles.PointShape().Sample(rnd))
+		assert.True(t, r.Contains(particles.RectShape(r).Sample(rnd)))
+		assert.True(t, particles.CircleShape(3).Sample(rnd).Len() <= 3)
+		u := particles.EdgeShape(a, b).Sample(rnd)
+		assert.InDelta(t, u.X/2, u.Y, 1e-9)
+		assert.True(t, u.X >= 0 && u.X <= 4)
+	}
+}
+
+func TestEmitter_Update(t *testing.T) {
+	e := particles.NewEmitter()
+	e.Seed(1)
+	e.Rate = 10
+	e.Life = particles.Fixed(1)
+
+	// the spawning is independent of the frame rate
+	for i := 0; i < 4; i++ {
+		e.Update(0.125)
+	}
+	assert.Equal(t, 5, e.Len())
+
+	// the particles die after their life
+	e.Rate = 0
+	e.Update(0.6)
+	assert.Equal(t, 5, e.Len())
+	e.Update(0.5)
+	assert.Equal(t, 0, e.Len())
+
+	e.Max = 3
+	e.Burst(10)
+	assert.Equal(t, 3, e.Len())
+	e.Clear()
+	assert.Equal(t, 0, e.Len())
+}
+
+func TestEmitter_Motion(t *testing.T) {
+	e := particles.NewEmitter()
+	e.Pos = pixel.V(10, 0)
+	e.Direction, e.Spread = 0, 0
+	e.Speed = particles.Fixed(2)
+	e.Life = particles.Fixed(10)
+	e.Burst(1)
+
+	e.Update(1)
+	assert.InDelta(t, 12, e.Particles()[0].Pos.X, 1e-9)
+
+	e.SpeedOverLife = particles.Curve{{T: 0, Value: 0.5}}
+	e.Update(1)
+	assert.InDelta(t, 13, e.Particles()[0].Pos.X, 1e-9)
+
+	e.SpeedOverLife = nil
+	e.Affectors = []particles.Affector{particles.Gravity(pixel.V(0, -4))}
+	e.Update(0.5)
+	assert.Equal(t, pixel.V(2, -2), e.Particles()[0].Vel)
+
+	e.Affectors = []particles.Affector{&particles.Attractor{Pos: pixel.V(0, -2), Strength: 10, Radius: 5}}
+	e.Particles()[0].Vel = pixel.ZV
+	before := e.Particles()[0].Pos
+	e.Update(0.1)
+	// out of the reach of the attractor
+	assert.Equal(t, before, e.Particles()[0].Pos)
+
+	e.Particles()[0].Pos = pixel.V(4, -2)
+	e.Update(0.1)
+	assert.True(t, e.Particles()[0].Vel.X < 0)
+	assert.InDelta(t, 0, e.Particles()[0].Vel.Y, 1e-9)
+
+	e.Affectors = []particles.Affector{particles.Drag(0.75)}
+	e.Particles()[0].Vel = pixel.V(8, 0)
+	e.Update(0.5)
+	assert.InDelta(t, 4, e.Particles()[0].Vel.X, 1e-9)
+}
+
+func TestEmitter_Draw(t *testing.T) {
+	e := particles.NewEmitter()
+	e.Pos = pixel.V(2, 2)
+	e.Spread = 0
+	e.StartSize = particles.Fixed(2)
+	e.StartAngle = particles.Fixed(math.Pi / 4)
+	e.Color = particles.ColorCurve{{T: 0, Color: pixel.RGB(1, 0, 0)}}
+	e.Burst(1)
+
+	c := raster.NewCanvas(pixel.R(0, 0, 4, 4))
+	e.Draw(c)
+	assert.Equal(t, pixel.RGB(1, 0, 0), c.Color(pixel.V(2, 2)))
+	assert.Equal(t, pixel.Alpha(0), c.Color(pixel.V(0, 0)))
+}
+
+func TestEmitter_Arena(t *testing.T) {
+	e := particles.NewEmitter()
+	e.Pos = pixel.V(2, 2)
+	e.StartSize = particles.Fixed(2)
+	e.Arena = pixel.NewArena()
+	e.Burst(3)
+
+	for i := 0; i < 2; i++ {
+		c := raster.NewCanvas(pixel.R(0, 0, 4, 4))
+		e.Draw(c)
+		assert.Equal(t, 18, e.Arena.Len())
+		assert.Equal(t, pixel.RGB(1, 1, 1), c.Color(pixel.V(2, 2)))
+		e.Arena.Reset()
+	}
+}
@@ -0,0 +1,66 @@
+package particles
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/faiface/pixel"
+)
+
+// Shape is an area the particles are spawned in, relative to the position of the Emitter.
+type Shape interface {
+	// Sample returns a random position in the Shape.
+	Sample(rnd *rand.Rand) pixel.Vec
+}
+
+// PointShape returns a Shape which spawns all particles in the position of the Emitter.
+func PointShape() Shape {
+	return pointShape{}
+}
+
+type pointShape struct{}
+
+func (pointShape) Sample(rnd *rand.Rand) pixel.Vec {
+	return pixel.ZV
+}
+
+// RectShape returns a Shape which spawns the particles evenly in the rectangle, e.g. the snow
+// falling from the top of the screen.
+func RectShape(r pixel.Rect) Shape {
+	return rectShape(r.Norm())
+}
+
+type rectShape pixel.Rect
+
+func (rs rectShape) Sample(rnd *rand.Rand) pixel.Vec {
+	return pixel.V(
+		rs.Min.X+rnd.Float64()*(rs.Max.X-rs.Min.X),
+		rs.Min.Y+rnd.Float64()*(rs.Max.Y-rs.Min.Y),
+	)
+}
+
+// CircleShape returns a Shape which spawns the particles evenly in the circle around the Emitter.
+func CircleShape(radius float64) Shape {
+	return circleShape(radius)
+}
+
+type circleShape float64
+
+func (cs circleShape) Sample(rnd *rand.Rand) pixel.Vec {
+	// the square root spreads the particles evenly over the area
+	return pixel.Unit(rnd.Float64() * 2 * math.Pi).Scaled(float64(cs) * math.Sqrt(rnd.Float64()))
+}
+
+// EdgeShape returns a Shape which spawns the particles evenly along the line segment from a to b,
+// e.g. the sparks from a blade.
+func EdgeShape(a, b pixel.Vec) Shape {
+	return edgeShape{a, b}
+}
+
+type edgeShape struct {
+	a, b pixel.Vec
+}
+
+func (es edgeShape) Sample(rnd *rand.Rand) pixel.Vec {
+	return pixel.Lerp(es.a, es.b, rnd.Float64())
+}
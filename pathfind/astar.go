@@ -0,0 +1,74 @@
+// Package pathfind implements the A* pathfinding over the grids of tiles, with the costs of the
+// cells and the rules of the diagonal moves, and over the navigation meshes of convex polygons,
+// with the paths straightened by the funnel algorithm.
+package pathfind
+
+import "container/heap"
+
+// search finds the cheapest path of the nodes from start to goal by A*. The neighbors function
+// visits the neighbors of a node with the costs of moving to them and the heuristic estimates the
+// cost from a node to the goal, never more than the real cost. A positive limit is the maximal
+// number of the expanded nodes.
+func search(start, goal int64, neighbors func(n int64, visit func(m int64, cost float64)), heuristic func(n int64) float64, limit int) ([]int64, bool) {
+	cost := map[int64]float64{start: 0}
+	from := make(map[int64]int64)
+	closed := make(map[int64]bool)
+	open := &queue{{key: start, f: heuristic(start)}}
+
+	expanded := 0
+	for open.Len() > 0 {
+		n := heap.Pop(open).(entry).key
+		if closed[n] {
+			continue
+		}
+		if n == goal {
+			path := []int64{n}
+			for n != start {
+				n = from[n]
+				path = append(path, n)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, true
+		}
+		closed[n] = true
+		expanded++
+		if limit > 0 && expanded > limit {
+			break
+		}
+
+		g := cost[n]
+		neighbors(n, func(m int64, c float64) {
+			if closed[m] {
+				return
+			}
+			if old, ok := cost[m]; ok && old <= g+c {
+				return
+			}
+			cost[m] = g + c
+			from[m] = n
+			heap.Push(open, entry{key: m, f: g + c + heuristic(m)})
+		})
+	}
+	return nil, false
+}
+
+type entry struct {
+	key int64
+	f   float64
+}
+
+// queue is a priority queue of the open nodes, the cheapest first
+type queue []entry
+
+func (q queue) Len() int            { return len(q) }
+func (q queue) Less(i, j int) bool  { return q[i].f < q[j].f }
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(entry)) }
+func (q *queue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
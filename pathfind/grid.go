@@ -0,0 +1,226 @@
+package pathfind
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Diagonal is the rule of the diagonal moves on a Grid.
+type Diagonal int
+
+const (
+	// Never moves diagonally.
+	Never Diagonal = iota
+
+	// NoCorners moves diagonally only between two open cells, so the paths never cut the corners
+	// of the blocked cells.
+	NoCorners
+
+	// OneCorner moves diagonally past at most one blocked cell.
+	OneCorner
+
+	// Always moves diagonally, even between two blocked cells.
+	Always
+)
+
+// Grid is a grid of the cells to find the paths on, such as the tiles of a tilemap.Map.
+//
+//   g := &pathfind.Grid{
+//       CellSize: pixel.V(16, 16),
+//       Diagonal: pathfind.NoCorners,
+//       Cost: func(x, y int) float64 {
+//           if x < 0 || y < 0 || x >= w || y >= h || walls[y][x] {
+//               return math.Inf(1)
+//           }
+//           return 1
+//       },
+//   }
+//   path, ok := g.FindPath(enemy.Pos, player.Pos)
+type Grid struct {
+	// CellSize is the size of the cells in the world. The cell (x, y) covers the rectangle from
+	// (x, y) to (x+1, y+1) scaled by the CellSize, like the cells of a tilemap.Map.
+	CellSize pixel.Vec
+
+	// Cost returns the cost of moving through the cell (x, y) per cell, at least 1. An infinite
+	// cost blocks the cell.
+	Cost func(x, y int) float64
+
+	Diagonal Diagonal
+
+	// Limit is the maximal number of the cells examined by a search, zero means no limit. An
+	// unbounded Grid needs a Limit, or the search for an unreachable cell never ends.
+	Limit int
+}
+
+// FindCells returns the cheapest path of the cells from the cell from to the cell to, including
+// both, and reports whether there is one.
+func (g *Grid) FindCells(from, to [2]int) ([][2]int, bool) {
+	if !g.open(to[0], to[1]) {
+		return nil, false
+	}
+	keys, ok := search(cellKey(from), cellKey(to), g.neighbors, func(n int64) float64 {
+		x, y := keyCell(n)
+		return octile(to[0]-x, to[1]-y)
+	}, g.Limit)
+	if !ok {
+		return nil, false
+	}
+	cells := make([][2]int, len(keys))
+	for i, k := range keys {
+		x, y := keyCell(k)
+		cells[i] = [2]int{x, y}
+	}
+	return cells, true
+}
+
+// FindPath returns the cheapest path from the position from to the position to in the world,
+// through the centers of the cells between them, and reports whether there is one.
+func (g *Grid) FindPath(from, to pixel.Vec) ([]pixel.Vec, bool) {
+	cells, ok := g.FindCells(g.CellAt(from), g.CellAt(to))
+	if !ok {
+		return nil, false
+	}
+	path := make([]pixel.Vec, len(cells))
+	for i, c := range cells {
+		path[i] = g.Center(c)
+	}
+	path[0] = from
+	if len(path) > 1 {
+		path = append(path[:len(path)-1], to)
+	} else {
+		path = append(path, to)
+	}
+	return path, true
+}
+
+// Smooth returns the path without the points which can be skipped by moving in the straight lines
+// through the open cells. It doesn't consider the costs, only whether the cells are blocked.
+func (g *Grid) Smooth(path []pixel.Vec) []pixel.Vec {
+	if len(path) <= 2 {
+		return path
+	}
+	smooth := []pixel.Vec{path[0]}
+	for i := 1; i < len(path)-1; i++ {
+		if !g.Visible(smooth[len(smooth)-1], path[i+1]) {
+			smooth = append(smooth, path[i])
+		}
+	}
+	return append(smooth, path[len(path)-1])
+}
+
+// Visible reports whether the straight line from a to b goes only through the open cells. The line
+// passing exactly through a corner needs both cells at the corner open.
+func (g *Grid) Visible(a, b pixel.Vec) bool {
+	p := pixel.V(a.X/g.CellSize.X, a.Y/g.CellSize.Y)
+	q := pixel.V(b.X/g.CellSize.X, b.Y/g.CellSize.Y)
+	x, y := int(math.Floor(p.X)), int(math.Floor(p.Y))
+	endX, endY := int(math.Floor(q.X)), int(math.Floor(q.Y))
+
+	// the traversal of the cells along the line
+	d := q.Sub(p)
+	stepX, nextX, deltaX := traversal(p.X, d.X)
+	stepY, nextY, deltaY := traversal(p.Y, d.Y)
+	for {
+		if !g.open(x, y) {
+			return false
+		}
+		if x == endX && y == endY {
+			return true
+		}
+		switch {
+		case nextX < nextY:
+			if nextX > 1 {
+				return true
+			}
+			x += stepX
+			nextX += deltaX
+		case nextY < nextX:
+			if nextY > 1 {
+				return true
+			}
+			y += stepY
+			nextY += deltaY
+		default:
+			if nextX > 1 {
+				return true
+			}
+			if !g.open(x+stepX, y) || !g.open(x, y+stepY) {
+				return false
+			}
+			x += stepX
+			y += stepY
+			nextX += deltaX
+			nextY += deltaY
+		}
+	}
+}
+
+// CellAt returns the cell at the position in the world.
+func (g *Grid) CellAt(pos pixel.Vec) [2]int {
+	return [2]int{int(math.Floor(pos.X / g.CellSize.X)), int(math.Floor(pos.Y / g.CellSize.Y))}
+}
+
+// Center returns the center of the cell in the world.
+func (g *Grid) Center(cell [2]int) pixel.Vec {
+	return pixel.V((float64(cell[0])+0.5)*g.CellSize.X, (float64(cell[1])+0.5)*g.CellSize.Y)
+}
+
+func (g *Grid) open(x, y int) bool {
+	return !math.IsInf(g.Cost(x, y), 1)
+}
+
+func (g *Grid) neighbors(n int64, visit func(m int64, cost float64)) {
+	x, y := keyCell(n)
+	for _, d := range [...][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		if c := g.Cost(x+d[0], y+d[1]); !math.IsInf(c, 1) {
+			visit(cellKey([2]int{x + d[0], y + d[1]}), c)
+		}
+	}
+	if g.Diagonal == Never {
+		return
+	}
+	for _, d := range [...][2]int{{1, 1}, {-1, 1}, {1, -1}, {-1, -1}} {
+		c := g.Cost(x+d[0], y+d[1])
+		if math.IsInf(c, 1) {
+			continue
+		}
+		blocked := 0
+		if !g.open(x+d[0], y) {
+			blocked++
+		}
+		if !g.open(x, y+d[1]) {
+			blocked++
+		}
+		if (g.Diagonal == NoCorners && blocked > 0) || (g.Diagonal == OneCorner && blocked > 1) {
+			continue
+		}
+		visit(cellKey([2]int{x + d[0], y + d[1]}), c*math.Sqrt2)
+	}
+}
+
+// traversal returns the direction of the steps along an axis, the fraction of the line to the
+// first cell boundary and the fraction between the boundaries.
+func traversal(p, d float64) (step int, next, delta float64) {
+	switch {
+	case d > 0:
+		return 1, (math.Floor(p) + 1 - p) / d, 1 / d
+	case d < 0:
+		return -1, (p - math.Floor(p)) / -d, 1 / -d
+	}
+	return 0, math.Inf(1), math.Inf(1)
+}
+
+// octile returns the distance of a cell by the straight and the diagonal moves.
+func octile(dx, dy int) float64 {
+	ax, ay := math.Abs(float64(dx)), math.Abs(float64(dy))
+	return math.Max(ax, ay) + (math.Sqrt2-1)*math.Min(ax, ay)
+}
+
+func cellKey(c [2]int) int64 {
+	return int64(c[0])<<32 | int64(uint32(c[1]))
+}
+
+func keyCell(k int64) (x, y int) {
+	return int(int32(k >> 32)), int(int32(k))
+}
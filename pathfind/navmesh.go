@@ -0,0 +1,268 @@
+package pathfind
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// NavMesh is a walkable area made of convex polygons, connected where their edges touch.
+//
+//   nm := pathfind.NavMeshFromGrid(pixel.V(16, 16), w, h, func(x, y int) bool {
+//       return !walls[y][x]
+//   })
+//   path, ok := nm.FindPath(enemy.Pos, player.Pos)
+type NavMesh struct {
+	polys []navPoly
+}
+
+type navPoly struct {
+	points  []pixel.Vec
+	center  pixel.Vec
+	portals []portal
+}
+
+// portal is a part of an edge shared with the next polygon, left and right looking out of the
+// polygon
+type portal struct {
+	poly        int
+	left, right pixel.Vec
+}
+
+// tolerance of the navmesh geometry
+const navEps = 1e-9
+
+// NewNavMesh creates a new NavMesh of the convex polygons. The polygons are connected through the
+// parts of their edges which lie on each other, the vertices don't have to match.
+func NewNavMesh(polygons ...[]pixel.Vec) *NavMesh {
+	nm := &NavMesh{polys: make([]navPoly, len(polygons))}
+	for i, points := range polygons {
+		points = append([]pixel.Vec(nil), points...)
+		if area(points) < 0 {
+			for a, b := 0, len(points)-1; a < b; a, b = a+1, b-1 {
+				points[a], points[b] = points[b], points[a]
+			}
+		}
+		var center pixel.Vec
+		for _, p := range points {
+			center = center.Add(p)
+		}
+		nm.polys[i] = navPoly{points: points, center: center.Scaled(1 / float64(len(points)))}
+	}
+
+	for i := range nm.polys {
+		for j := range nm.polys {
+			if i != j {
+				nm.connect(i, j)
+			}
+		}
+	}
+	return nm
+}
+
+// NavMeshFromGrid creates a new NavMesh of the walkable cells of the grid of w by h cells of the
+// size, merged into rectangles. The cell (x, y) covers the rectangle from (x, y) to (x+1, y+1)
+// scaled by the cell size.
+func NavMeshFromGrid(cellSize pixel.Vec, w, h int, walkable func(x, y int) bool) *NavMesh {
+	used := make([]bool, w*h)
+	free := func(x, y int) bool {
+		return !used[y*w+x] && walkable(x, y)
+	}
+
+	var rects [][]pixel.Vec
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if !free(x, y) {
+				continue
+			}
+			// as wide as possible, then as high as possible
+			x1 := x + 1
+			for x1 < w && free(x1, y) {
+				x1++
+			}
+			y1 := y + 1
+		grow:
+			for y1 < h {
+				for i := x; i < x1; i++ {
+					if !free(i, y1) {
+						break grow
+					}
+				}
+				y1++
+			}
+			for j := y; j < y1; j++ {
+				for i := x; i < x1; i++ {
+					used[j*w+i] = true
+				}
+			}
+			min := pixel.V(float64(x)*cellSize.X, float64(y)*cellSize.Y)
+			max := pixel.V(float64(x1)*cellSize.X, float64(y1)*cellSize.Y)
+			rects = append(rects, []pixel.Vec{min, pixel.V(max.X, min.Y), max, pixel.V(min.X, max.Y)})
+		}
+	}
+	return NewNavMesh(rects...)
+}
+
+// Len returns the number of the polygons of the NavMesh.
+func (nm *NavMesh) Len() int {
+	return len(nm.polys)
+}
+
+// Polygon returns the points of the i-th polygon, in the counter-clockwise order.
+func (nm *NavMesh) Polygon(i int) []pixel.Vec {
+	return nm.polys[i].points
+}
+
+// PolygonAt returns the index of the polygon containing the point, or -1, if it's outside of the
+// NavMesh.
+func (nm *NavMesh) PolygonAt(p pixel.Vec) int {
+	for i := range nm.polys {
+		if nm.polys[i].contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindPath returns the shortest path from the point from to the point to through the polygons of
+// the NavMesh, straightened by pulling it tight around the corners, and reports whether there is
+// one. Both points must be inside of the NavMesh.
+func (nm *NavMesh) FindPath(from, to pixel.Vec) ([]pixel.Vec, bool) {
+	start, goal := nm.PolygonAt(from), nm.PolygonAt(to)
+	if start < 0 || goal < 0 {
+		return nil, false
+	}
+	pos := func(n int64) pixel.Vec {
+		switch int(n) {
+		case start:
+			return from
+		case goal:
+			return to
+		}
+		return nm.polys[n].center
+	}
+	keys, ok := search(int64(start), int64(goal), func(n int64, visit func(m int64, cost float64)) {
+		for _, pt := range nm.polys[n].portals {
+			mid := pixel.Lerp(pt.left, pt.right, 0.5)
+			visit(int64(pt.poly), pos(n).To(mid).Len()+mid.To(pos(int64(pt.poly))).Len())
+		}
+	}, func(n int64) float64 {
+		return pos(n).To(to).Len()
+	}, 0)
+	if !ok {
+		return nil, false
+	}
+
+	portals := make([]portal, 0, len(keys)+1)
+	portals = append(portals, portal{left: from, right: from})
+	for i := 0; i+1 < len(keys); i++ {
+		for _, pt := range nm.polys[keys[i]].portals {
+			if pt.poly == int(keys[i+1]) {
+				portals = append(portals, pt)
+				break
+			}
+		}
+	}
+	portals = append(portals, portal{left: to, right: to})
+	return funnel(portals), true
+}
+
+// connect adds the portals from the polygon i to the polygon j, where their edges lie on each other
+func (nm *NavMesh) connect(i, j int) {
+	p, q := &nm.polys[i], &nm.polys[j]
+	for a := range p.points {
+		pa, pb := p.points[a], p.points[(a+1)%len(p.points)]
+		edge := pa.To(pb)
+		length2 := edge.Dot(edge)
+		for b := range q.points {
+			qa, qb := q.points[b], q.points[(b+1)%len(q.points)]
+			// the edges must be on the same line, in the opposite directions
+			if math.Abs(edge.Cross(pa.To(qa))) > navEps*length2 || math.Abs(edge.Cross(pa.To(qb))) > navEps*length2 {
+				continue
+			}
+			if edge.Dot(qa.To(qb)) >= 0 {
+				continue
+			}
+			t0 := math.Max(0, pa.To(qb).Dot(edge)/length2)
+			t1 := math.Min(1, pa.To(qa).Dot(edge)/length2)
+			if t1-t0 <= navEps {
+				continue
+			}
+			p.portals = append(p.portals, portal{
+				poly:  j,
+				right: pixel.Lerp(pa, pb, t0),
+				left:  pixel.Lerp(pa, pb, t1),
+			})
+		}
+	}
+}
+
+func (p *navPoly) contains(u pixel.Vec) bool {
+	for i := range p.points {
+		a, b := p.points[i], p.points[(i+1)%len(p.points)]
+		if a.To(b).Cross(a.To(u)) < -navEps {
+			return false
+		}
+	}
+	return true
+}
+
+// funnel returns the shortest path through the portals, from the first one to the last one, by the
+// simple stupid funnel algorithm
+func funnel(portals []portal) []pixel.Vec {
+	apex, left, right := portals[0].left, portals[0].left, portals[0].right
+	apexIndex, leftIndex, rightIndex := 0, 0, 0
+	path := []pixel.Vec{apex}
+
+	for i := 1; i < len(portals); i++ {
+		l, r := portals[i].left, portals[i].right
+
+		// tighten the right side
+		if triArea(apex, right, r) >= 0 {
+			if apex == right || triArea(apex, left, r) < 0 {
+				right, rightIndex = r, i
+			} else {
+				// the right side crossed the left one, the left one is a corner of the path
+				apex, apexIndex = left, leftIndex
+				path = append(path, apex)
+				left, right = apex, apex
+				leftIndex, rightIndex = apexIndex, apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+
+		// tighten the left side
+		if triArea(apex, left, l) <= 0 {
+			if apex == left || triArea(apex, right, l) > 0 {
+				left, leftIndex = l, i
+			} else {
+				apex, apexIndex = right, rightIndex
+				path = append(path, apex)
+				left, right = apex, apex
+				leftIndex, rightIndex = apexIndex, apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+	}
+
+	if end := portals[len(portals)-1].left; path[len(path)-1] != end {
+		path = append(path, end)
+	}
+	return path
+}
+
+// triArea returns twice the signed area of the triangle, positive if c is on the left of a to b
+func triArea(a, b, c pixel.Vec) float64 {
+	return a.To(b).Cross(a.To(c))
+}
+
+// area returns the signed area of the polygon, positive if it's counter-clockwise
+func area(points []pixel.Vec) float64 {
+	var sum float64
+	for i := range points {
+		sum += points[i].Cross(points[(i+1)%len(points)])
+	}
+	return sum / 2
+}
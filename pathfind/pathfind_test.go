@@ -0,0 +1,210 @@
+package pathfind_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pathfind"
+	"github.com/stretchr/testify/assert"
+)
+
+// grid returns a Grid of the cells 1 by 1 drawn by the rows, the top one first and the bottom one
+// at y = 0: '#' is blocked, '~' costs 5 and the rest costs 1. Outside is blocked.
+func grid(diagonal pathfind.Diagonal, rows ...string) *pathfind.Grid {
+	return &pathfind.Grid{
+		CellSize: pixel.V(1, 1),
+		Diagonal: diagonal,
+		Cost: func(x, y int) float64 {
+			if y < 0 || y >= len(rows) || x < 0 || x >= len(rows[0]) {
+				return math.Inf(1)
+			}
+			switch rows[len(rows)-1-y][x] {
+			case '#':
+				return math.Inf(1)
+			case '~':
+				return 5
+			}
+			return 1
+		},
+	}
+}
+
+func TestGrid_FindCells(t *testing.T) {
+	g := grid(pathfind.Never,
+		"... ",
+		".#. ",
+		".#..",
+	)
+	cells, ok := g.FindCells([2]int{0, 0}, [2]int{2, 0})
+	assert.True(t, ok)
+	assert.Equal(t, [][2]int{{0, 0}, {0, 1}, {0, 2}, {1, 2}, {2, 2}, {2, 1}, {2, 0}}, cells)
+
+	_, ok = g.FindCells([2]int{0, 0}, [2]int{1, 1})
+	assert.False(t, ok, "blocked")
+
+	cells, ok = g.FindCells([2]int{3, 2}, [2]int{3, 2})
+	assert.True(t, ok)
+	assert.Equal(t, [][2]int{{3, 2}}, cells)
+}
+
+func TestGrid_Costs(t *testing.T) {
+	g := grid(pathfind.Never,
+		"....",
+		".~~.",
+		"....",
+	)
+	cells, ok := g.FindCells([2]int{0, 1}, [2]int{3, 1})
+	assert.True(t, ok)
+	assert.Len(t, cells, 6, "around the expensive cells")
+
+	g = grid(pathfind.Never,
+		"####",
+		".~~.",
+		"####",
+	)
+	cells, ok = g.FindCells([2]int{0, 1}, [2]int{3, 1})
+	assert.True(t, ok)
+	assert.Len(t, cells, 4, "through the expensive cells")
+}
+
+func TestGrid_Diagonal(t *testing.T) {
+	rows := []string{
+		"..",
+		"#.",
+		".#",
+	}
+	tests := []struct {
+		diagonal pathfind.Diagonal
+		ok       bool
+		length   int
+	}{
+		{pathfind.Never, false, 0},
+		{pathfind.NoCorners, false, 0},
+		{pathfind.OneCorner, false, 0},
+		{pathfind.Always, true, 3},
+	}
+	for _, test := range tests {
+		cells, ok := grid(test.diagonal, rows...).FindCells([2]int{0, 0}, [2]int{0, 2})
+		assert.Equal(t, test.ok, ok, "diagonal %d", test.diagonal)
+		assert.Len(t, cells, test.length, "diagonal %d", test.diagonal)
+	}
+
+	rows = []string{
+		"..",
+		".#",
+	}
+	for _, test := range []struct {
+		diagonal pathfind.Diagonal
+		length   int
+	}{{pathfind.Never, 3}, {pathfind.NoCorners, 3}, {pathfind.OneCorner, 2}} {
+		cells, ok := grid(test.diagonal, rows...).FindCells([2]int{0, 0}, [2]int{1, 1})
+		assert.True(t, ok)
+		assert.Len(t, cells, test.length, "diagonal %d", test.diagonal)
+	}
+}
+
+func TestGrid_Limit(t *testing.T) {
+	g := &pathfind.Grid{
+		CellSize: pixel.V(1, 1),
+		Cost: func(x, y int) float64 {
+			if x == 0 && y == 0 {
+				return math.Inf(1)
+			}
+			return 1
+		},
+		Limit: 1000,
+	}
+	_, ok := g.FindCells([2]int{5, 5}, [2]int{0, 1})
+	assert.True(t, ok)
+	g.Cost = func(x, y int) float64 {
+		if x == 0 && (y == 1 || y == -1) || y == 0 && (x == 1 || x == -1) {
+			return math.Inf(1)
+		}
+		return 1
+	}
+	_, ok = g.FindCells([2]int{5, 5}, [2]int{0, 0})
+	assert.False(t, ok, "unreachable in an unbounded grid")
+}
+
+func TestGrid_FindPath(t *testing.T) {
+	g := grid(pathfind.NoCorners,
+		".....",
+		".###.",
+		".....",
+	)
+	g.CellSize = pixel.V(10, 10)
+	path, ok := g.FindPath(pixel.V(2, 12), pixel.V(48, 18))
+	assert.True(t, ok)
+	assert.Equal(t, pixel.V(2, 12), path[0])
+	assert.Equal(t, pixel.V(48, 18), path[len(path)-1])
+	assert.Len(t, path, 7)
+
+	smooth := g.Smooth(path)
+	assert.Equal(t, []pixel.Vec{pixel.V(2, 12), pixel.V(5, 25), pixel.V(45, 25), pixel.V(48, 18)}, smooth)
+}
+
+func TestGrid_Visible(t *testing.T) {
+	g := grid(pathfind.Always,
+		"...",
+		".#.",
+		"...",
+	)
+	assert.True(t, g.Visible(pixel.V(0.5, 0.5), pixel.V(2.5, 0.5)))
+	assert.False(t, g.Visible(pixel.V(0.5, 0.5), pixel.V(2.5, 2.5)))
+	assert.False(t, g.Visible(pixel.V(0.5, 1.5), pixel.V(2.5, 1.5)))
+	assert.True(t, g.Visible(pixel.V(0.5, 0.5), pixel.V(0.5, 2.5)))
+	assert.False(t, g.Visible(pixel.V(0.5, 0.5), pixel.V(3.5, 0.5)), "outside")
+
+	// through a corner of two blocked cells
+	g = grid(pathfind.Always,
+		"..",
+		"..",
+	)
+	assert.True(t, g.Visible(pixel.V(0.5, 0.5), pixel.V(1.5, 1.5)))
+	g = grid(pathfind.Always,
+		".#",
+		"..",
+	)
+	assert.False(t, g.Visible(pixel.V(0.5, 0.5), pixel.V(1.5, 1.5)))
+}
+
+func TestNavMesh(t *testing.T) {
+	// an L shape: a corridor up and a corridor to the right, of the polygons with different
+	// vertices along the shared edge
+	nm := pathfind.NewNavMesh(
+		[]pixel.Vec{pixel.V(0, 0), pixel.V(2, 0), pixel.V(2, 10), pixel.V(0, 10)},
+		[]pixel.Vec{pixel.V(2, 8), pixel.V(2, 12), pixel.V(10, 12), pixel.V(10, 8)}, // clockwise
+	)
+	assert.Equal(t, 2, nm.Len())
+	assert.Equal(t, 0, nm.PolygonAt(pixel.V(1, 1)))
+	assert.Equal(t, 1, nm.PolygonAt(pixel.V(5, 10)))
+	assert.Equal(t, -1, nm.PolygonAt(pixel.V(5, 5)))
+
+	path, ok := nm.FindPath(pixel.V(1, 1), pixel.V(9, 10))
+	assert.True(t, ok)
+	assert.Equal(t, []pixel.Vec{pixel.V(1, 1), pixel.V(2, 8), pixel.V(9, 10)}, path)
+
+	path, ok = nm.FindPath(pixel.V(1, 1), pixel.V(1, 9))
+	assert.True(t, ok)
+	assert.Equal(t, []pixel.Vec{pixel.V(1, 1), pixel.V(1, 9)}, path)
+
+	_, ok = nm.FindPath(pixel.V(1, 1), pixel.V(5, 5))
+	assert.False(t, ok)
+}
+
+func TestNavMeshFromGrid(t *testing.T) {
+	rows := []string{
+		"#....",
+		"#.##.",
+		"..##.",
+	}
+	nm := pathfind.NavMeshFromGrid(pixel.V(10, 10), 5, 3, func(x, y int) bool {
+		return rows[2-y][x] != '#'
+	})
+	assert.Equal(t, 4, nm.Len())
+
+	path, ok := nm.FindPath(pixel.V(5, 5), pixel.V(45, 5))
+	assert.True(t, ok)
+	assert.Equal(t, []pixel.Vec{pixel.V(5, 5), pixel.V(10, 10), pixel.V(20, 20), pixel.V(40, 20), pixel.V(45, 5)}, path)
+}
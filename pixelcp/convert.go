@@ -0,0 +1,87 @@
+// Package pixelcp connects Pixel with the Chipmunk physics engine, github.com/jakecoffman/cp. It
+// converts the vectors, the rectangles and the transforms, creates the shapes of the bodies from
+// the Pixel geometry, draws the bodies with IMDraw for debugging and keeps the sprites on their
+// bodies.
+//
+//   space := cp.NewSpace()
+//   space.SetGravity(cp.Vector{Y: -500})
+//
+//   crate := space.AddBody(cp.NewBody(1, cp.MomentForBox(1, 32, 32)))
+//   pixelcp.SetTransform(crate, pixel.V(100, 200), 0)
+//   space.AddShape(pixelcp.NewRect(crate, pixel.R(-16, -16, 16, 16), 0))
+//
+//   var sprites pixelcp.Sprites
+//   sprites.Add(crate, crateSprite, pixel.IM)
+//
+//   for !win.Closed() {
+//       space.Step(dt)
+//       sprites.Draw(win)
+//       ...
+//   }
+package pixelcp
+
+import (
+	"github.com/faiface/pixel"
+	"github.com/jakecoffman/cp"
+)
+
+// Vec returns the Pixel vector of the Chipmunk vector.
+func Vec(v cp.Vector) pixel.Vec {
+	return pixel.V(v.X, v.Y)
+}
+
+// Vector returns the Chipmunk vector of the Pixel vector.
+func Vector(v pixel.Vec) cp.Vector {
+	return cp.Vector{X: v.X, Y: v.Y}
+}
+
+// Rect returns the Pixel rectangle of the Chipmunk bounding box.
+func Rect(bb cp.BB) pixel.Rect {
+	return pixel.R(bb.L, bb.B, bb.R, bb.T)
+}
+
+// BB returns the Chipmunk bounding box of the Pixel rectangle.
+func BB(r pixel.Rect) cp.BB {
+	r = r.Norm()
+	return cp.BB{L: r.Min.X, B: r.Min.Y, R: r.Max.X, T: r.Max.Y}
+}
+
+// Matrix returns the Matrix transforming the local coordinates of the body into the world, e.g. to
+// draw a Sprite on it.
+func Matrix(body *cp.Body) pixel.Matrix {
+	return pixel.IM.Rotated(pixel.ZV, body.Angle()).Moved(Vec(body.Position()))
+}
+
+// SetTransform sets the position and the angle of the body.
+func SetTransform(body *cp.Body, pos pixel.Vec, angle float64) {
+	body.SetPosition(Vector(pos))
+	body.SetAngle(angle)
+}
+
+// NewRect creates a new box shape of the rectangle in the local coordinates of the body, with the
+// corners rounded by the radius.
+func NewRect(body *cp.Body, r pixel.Rect, radius float64) *cp.Shape {
+	return cp.NewBox2(body, BB(r), radius)
+}
+
+// NewCircle creates a new circle shape of the circle in the local coordinates of the body.
+func NewCircle(body *cp.Body, c pixel.Circle) *cp.Shape {
+	c = c.Norm()
+	return cp.NewCircle(body, c.Radius, Vector(c.Center))
+}
+
+// NewSegment creates a new segment shape from a to b in the local coordinates of the body, with the
+// thickness of twice the radius.
+func NewSegment(body *cp.Body, a, b pixel.Vec, radius float64) *cp.Shape {
+	return cp.NewSegment(body, Vector(a), Vector(b), radius)
+}
+
+// NewPolygon creates a new shape of the convex hull of the points in the local coordinates of the
+// body, with the corners rounded by the radius.
+func NewPolygon(body *cp.Body, points []pixel.Vec, radius float64) *cp.Shape {
+	verts := make([]cp.Vector, len(points))
+	for i, p := range points {
+		verts[i] = Vector(p)
+	}
+	return cp.NewPolyShape(body, len(verts), verts, cp.NewTransformIdentity(), radius)
+}
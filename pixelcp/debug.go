@@ -0,0 +1,85 @@
+package pixelcp
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/jakecoffman/cp"
+)
+
+// Debug draws the shapes of the bodies with IMDraw, in the colors by the types of the bodies.
+//
+//   debug := pixelcp.NewDebug()
+//   imd.Clear()
+//   debug.DrawSpace(imd, space)
+//   imd.Draw(win)
+type Debug struct {
+	Dynamic, Kinematic, Static, Sleeping color.Color
+
+	// Thickness is the thickness of the outlines, zero means that the shapes are filled.
+	Thickness float64
+}
+
+// NewDebug creates a new Debug with the default colors and the outlines 1 pixel thick.
+func NewDebug() *Debug {
+	return &Debug{
+		Dynamic:   pixel.RGB(1, 0.8, 0.2),
+		Kinematic: pixel.RGB(0.3, 0.6, 1),
+		Static:    pixel.RGB(0.5, 0.5, 0.5),
+		Sleeping:  pixel.RGB(0.4, 0.3, 0.1),
+		Thickness: 1,
+	}
+}
+
+// DrawSpace draws all the bodies of the space.
+func (d *Debug) DrawSpace(imd *imdraw.IMDraw, space *cp.Space) {
+	space.EachBody(func(body *cp.Body) {
+		d.DrawBody(imd, body)
+	})
+}
+
+// DrawBody draws all the shapes of the body.
+func (d *Debug) DrawBody(imd *imdraw.IMDraw, body *cp.Body) {
+	body.EachShape(func(shape *cp.Shape) {
+		d.DrawShape(imd, shape)
+	})
+}
+
+// DrawShape draws the shape. The circles have a line from the center, showing the angle of the
+// body.
+func (d *Debug) DrawShape(imd *imdraw.IMDraw, shape *cp.Shape) {
+	imd.Color = d.color(shape.Body())
+	switch s := shape.Class.(type) {
+	case *cp.Circle:
+		c := Vec(s.TransformC())
+		imd.Push(c)
+		imd.Circle(s.Radius(), d.Thickness)
+		imd.Push(c, c.Add(pixel.Unit(shape.Body().Angle()).Scaled(s.Radius())))
+		imd.Line(math.Max(d.Thickness, 1))
+
+	case *cp.Segment:
+		imd.Push(Vec(s.TransformA()), Vec(s.TransformB()))
+		imd.Line(math.Max(2*s.Radius(), math.Max(d.Thickness, 1)))
+
+	case *cp.PolyShape:
+		for i := 0; i < s.Count(); i++ {
+			imd.Push(Vec(s.TransformVert(i)))
+		}
+		imd.Polygon(d.Thickness)
+	}
+}
+
+func (d *Debug) color(body *cp.Body) color.Color {
+	switch {
+	case body.GetType() == cp.BODY_STATIC:
+		return d.Static
+	case body.GetType() == cp.BODY_KINEMATIC:
+		return d.Kinematic
+	case body.IsSleeping():
+		return d.Sleeping
+	default:
+		return d.Dynamic
+	}
+}
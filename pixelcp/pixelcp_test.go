@@ -0,0 +1,70 @@
+package pixelcp_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/ecs"
+	"github.com/faiface/pixel/pixelcp"
+	"github.com/faiface/pixel/raster"
+	"github.com/jakecoffman/cp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	assert.Equal(t, cp.Vector{X: 1, Y: 2}, pixelcp.Vector(pixel.V(1, 2)))
+	assert.Equal(t, pixel.V(1, 2), pixelcp.Vec(cp.Vector{X: 1, Y: 2}))
+	assert.Equal(t, cp.BB{L: 1, B: 2, R: 3, T: 4}, pixelcp.BB(pixel.R(3, 4, 1, 2)))
+	assert.Equal(t, pixel.R(1, 2, 3, 4), pixelcp.Rect(cp.BB{L: 1, B: 2, R: 3, T: 4}))
+}
+
+func TestMatrix(t *testing.T) {
+	body := cp.NewKinematicBody()
+	pixelcp.SetTransform(body, pixel.V(10, 5), math.Pi/2)
+	assert.Equal(t, pixel.V(10, 5), pixelcp.Vec(body.Position()))
+
+	p := pixelcp.Matrix(body).Project(pixel.V(1, 0))
+	assert.InDelta(t, 10, p.X, 1e-9)
+	assert.InDelta(t, 6, p.Y, 1e-9)
+}
+
+func TestSprites(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 1, 1))
+	pic.Pix[0] = color.RGBA{R: 255, A: 255}
+	sprite := pixel.NewSprite(pic, pic.Bounds())
+
+	a, b := cp.NewKinematicBody(), cp.NewKinematicBody()
+	pixelcp.SetTransform(a, pixel.V(0.5, 0.5), 0)
+	pixelcp.SetTransform(b, pixel.V(2.5, 0.5), 0)
+
+	var sprites pixelcp.Sprites
+	sprites.Add(a, sprite, pixel.IM)
+	sprites.Add(b, sprite, pixel.IM.Moved(pixel.V(1, 0)))
+	sprites.Add(b, sprite, pixel.IM)
+	sprites.Remove(b)
+	sprites.Add(b, sprite, pixel.IM.Moved(pixel.V(1, 0)))
+	assert.Equal(t, 2, sprites.Len())
+
+	c := raster.NewCanvas(pixel.R(0, 0, 4, 1))
+	sprites.Draw(c)
+	want := []pixel.RGBA{pixel.RGB(1, 0, 0), pixel.Alpha(0), pixel.Alpha(0), pixel.RGB(1, 0, 0)}
+	for x, w := range want {
+		assert.Equal(t, w, c.Color(pixel.V(float64(x), 0)), "pixel %d", x)
+	}
+}
+
+func TestSyncSystem(t *testing.T) {
+	body := cp.NewKinematicBody()
+	pixelcp.SetTransform(body, pixel.V(3, 4), 1)
+
+	w := ecs.NewWorld()
+	w.AddSystem(pixelcp.SyncSystem{})
+	e := w.NewEntity()
+	tr := &ecs.Transform{}
+	w.Add(e, tr, &pixelcp.Body{Body: body})
+	w.Update(1)
+	assert.Equal(t, pixel.V(3, 4), tr.Pos)
+	assert.Equal(t, 1.0, tr.Angle)
+}
@@ -0,0 +1,89 @@
+package pixelcp
+
+import (
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/ecs"
+	"github.com/jakecoffman/cp"
+)
+
+// Sprites draws the Sprites at the transforms of their bodies, so they follow the bodies moved by
+// the physics.
+type Sprites struct {
+	items []bodySprite
+}
+
+type bodySprite struct {
+	body   *cp.Body
+	sprite *pixel.Sprite
+	matrix pixel.Matrix
+	mask   color.Color
+}
+
+// Add adds the Sprite of the body. The Matrix transforms the Sprite into the local coordinates of
+// the body, e.g. to offset it from the center of gravity.
+func (s *Sprites) Add(body *cp.Body, sprite *pixel.Sprite, matrix pixel.Matrix) {
+	s.items = append(s.items, bodySprite{body: body, sprite: sprite, matrix: matrix})
+}
+
+// AddColorMask adds the Sprite of the body, like Add, drawn with the color mask.
+func (s *Sprites) AddColorMask(body *cp.Body, sprite *pixel.Sprite, matrix pixel.Matrix, mask color.Color) {
+	s.items = append(s.items, bodySprite{body: body, sprite: sprite, matrix: matrix, mask: mask})
+}
+
+// Remove removes all the Sprites of the body.
+func (s *Sprites) Remove(body *cp.Body) {
+	items := s.items[:0]
+	for _, it := range s.items {
+		if it.body != body {
+			items = append(items, it)
+		}
+	}
+	for i := len(items); i < len(s.items); i++ {
+		s.items[i] = bodySprite{}
+	}
+	s.items = items
+}
+
+// Len returns the number of the Sprites.
+func (s *Sprites) Len() int {
+	return len(s.items)
+}
+
+// Draw draws all the Sprites at their bodies onto the Target, in the order they were added.
+func (s *Sprites) Draw(t pixel.Target) {
+	for _, it := range s.items {
+		it.sprite.DrawColorMask(t, it.matrix.Chained(Matrix(it.body)), it.mask)
+	}
+}
+
+// Body is the ecs component of an Entity moved by the physics.
+type Body struct {
+	Body *cp.Body
+}
+
+// SyncSystem copies the positions and the angles of the Bodies of the Entities into their
+// ecs.Transforms, so that their Sprites are drawn at the Bodies. It should be updated after the
+// space is stepped.
+//
+//   w.AddSystem(ecs.SystemFunc(func(w *ecs.World, dt float64) { space.Step(dt) }))
+//   w.AddSystem(pixelcp.SyncSystem{})
+type SyncSystem struct{}
+
+// Update updates the Entities with a Body and an ecs.Transform.
+func (SyncSystem) Update(w *ecs.World, dt float64) {
+	for _, e := range w.With((*Body)(nil), (*ecs.Transform)(nil)) {
+		var (
+			b *Body
+			t *ecs.Transform
+		)
+		w.Get(e, &b)
+		w.Get(e, &t)
+		if b.Body == nil {
+			continue
+		}
+		t.Pos = Vec(b.Body.Position())
+		t.Angle = b.Body.Angle()
+	}
+}
@@ -0,0 +1,62 @@
+// Package pixelebiten converts the Pictures of Pixel to and from the images of the Ebiten game
+// engine, github.com/hajimehoshi/ebiten/v2, e.g. to move a game between them or to use the assets
+// of one in the other.
+//
+//   img := pixelebiten.NewImage(pic)
+//   screen.DrawImage(img, nil)
+//
+//   pd := pixelebiten.PictureData(img)
+//   sprite := pixel.NewSprite(pd, pd.Bounds())
+//
+// The Ebiten images live on the GPU, so the conversions copy the pixels once. Both sides use
+// alpha-premultiplied RGBA bytes, so the pixels are copied as they are, only the rows are flipped.
+package pixelebiten
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/faiface/pixel"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// NewImage creates an Ebiten image with the pixels of the Picture. The Picture is converted to a
+// PictureData first, see pixel.PictureDataFromPicture.
+func NewImage(pic pixel.Picture) *ebiten.Image {
+	pd := pixel.PictureDataFromPicture(pic)
+	rgba := pd.Image()
+	img := ebiten.NewImage(rgba.Rect.Dx(), rgba.Rect.Dy())
+	img.WritePixels(rgba.Pix)
+	return img
+}
+
+// WritePixels replaces the pixels of the Ebiten image by the ones of the PictureData, e.g. to
+// update a texture each frame without creating a new image. The sizes of both must match.
+func WritePixels(img *ebiten.Image, pd *pixel.PictureData) {
+	rgba := pd.Image()
+	if rgba.Rect.Size() != img.Bounds().Size() {
+		panic(fmt.Errorf("pixelebiten.WritePixels: PictureData %v doesn't match image %v",
+			rgba.Rect.Size(), img.Bounds().Size()))
+	}
+	img.WritePixels(rgba.Pix)
+}
+
+// PictureData returns a PictureData with a copy of the pixels of the Ebiten image. The Bounds are
+// the ones of the image, like in pixel.PictureDataFromImage.
+//
+// Ebiten reads the pixels from the GPU, which is only possible once the game runs, e.g. in Update.
+func PictureData(img *ebiten.Image) *pixel.PictureData {
+	bounds := img.Bounds()
+	pix := make([]byte, 4*bounds.Dx()*bounds.Dy())
+	img.ReadPixels(pix)
+	return pictureData(bounds, pix)
+}
+
+// pictureData returns a PictureData with the RGBA bytes of the bounds, the top row first
+func pictureData(bounds image.Rectangle, pix []byte) *pixel.PictureData {
+	return pixel.PictureDataFromImage(&image.RGBA{
+		Pix:    pix,
+		Stride: 4 * bounds.Dx(),
+		Rect:   bounds,
+	})
+}
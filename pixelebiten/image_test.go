@@ -0,0 +1,35 @@
+package pixelebiten
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPictureDataRoundTrip(t *testing.T) {
+	pd := pixel.MakePictureData(pixel.R(0, 0, 2, 2))
+	// the bottom row of the PictureData is the first one
+	pd.Pix[0] = color.RGBA{R: 255, A: 255}
+	pd.Pix[1] = color.RGBA{G: 255, A: 255}
+	pd.Pix[2] = color.RGBA{B: 255, A: 255}
+	pd.Pix[3] = color.RGBA{R: 128, G: 128, B: 128, A: 128}
+
+	pix := pd.Image().Pix
+	// Ebiten's bytes start at the top-left pixel
+	assert.Equal(t, []byte{0, 0, 255, 255}, pix[0:4])
+	assert.Equal(t, []byte{128, 128, 128, 128}, pix[4:8])
+	assert.Equal(t, []byte{255, 0, 0, 255}, pix[8:12])
+
+	back := pictureData(image.Rect(0, 0, 2, 2), pix)
+	assert.Equal(t, pd.Bounds(), back.Bounds())
+	assert.Equal(t, pd.Pix, back.Pix)
+}
+
+func TestPictureDataBounds(t *testing.T) {
+	pd := pictureData(image.Rect(3, 4, 5, 7), make([]byte, 4*2*3))
+	assert.Equal(t, pixel.R(3, 4, 5, 7), pd.Bounds())
+	assert.Len(t, pd.Pix, 6)
+}
@@ -0,0 +1,82 @@
+// Package pixelgio connects Pixel with the Gio UI toolkit, gioui.org. ImageOp shows a Picture in a
+// Gio UI, and Renderer renders Gio operations into a PictureData, e.g. to draw a Gio UI over a
+// game.
+//
+//   ui, err := pixelgio.NewRenderer(320, 240)
+//   if err != nil {
+//       panic(err)
+//   }
+//   defer ui.Release()
+//
+//   var ops op.Ops
+//   for !win.Closed() {
+//       ops.Reset()
+//       layoutUI(&ops)
+//       pd, err := ui.Render(&ops)
+//       if err != nil {
+//           panic(err)
+//       }
+//       pixel.NewSprite(pd, pd.Bounds()).Draw(win, pixel.IM.Moved(win.Bounds().Center()))
+//       win.Update()
+//   }
+package pixelgio
+
+import (
+	"fmt"
+	"image"
+
+	"gioui.org/gpu/headless"
+	"gioui.org/op"
+	"gioui.org/op/paint"
+	"github.com/faiface/pixel"
+)
+
+// ImageOp returns a Gio image operation showing the Picture. The Picture is converted to a
+// PictureData first, see pixel.PictureDataFromPicture.
+//
+// Gio keeps the *image.RGBA converted from the PictureData without copying it, so the ImageOp
+// doesn't change with the Picture. Call ImageOp again after the Picture changes.
+func ImageOp(pic pixel.Picture) paint.ImageOp {
+	return paint.NewImageOp(pixel.PictureDataFromPicture(pic).Image())
+}
+
+// Renderer renders Gio operations into PictureDatas, by an offscreen GPU context of Gio.
+type Renderer struct {
+	win *headless.Window
+	img *image.RGBA
+}
+
+// NewRenderer creates a Renderer of the size in pixels. It returns an error if Gio can't create
+// its GPU context.
+func NewRenderer(width, height int) (*Renderer, error) {
+	win, err := headless.NewWindow(width, height)
+	if err != nil {
+		return nil, fmt.Errorf("pixelgio: creating renderer: %v", err)
+	}
+	return &Renderer{
+		win: win,
+		img: image.NewRGBA(image.Rect(0, 0, width, height)),
+	}, nil
+}
+
+// Render renders the operations and returns a new PictureData with the result, the Bounds are
+// (0, 0) to the size of the Renderer.
+func (r *Renderer) Render(ops *op.Ops) (*pixel.PictureData, error) {
+	if err := r.win.Frame(ops); err != nil {
+		return nil, fmt.Errorf("pixelgio: rendering frame: %v", err)
+	}
+	if err := r.win.Screenshot(r.img); err != nil {
+		return nil, fmt.Errorf("pixelgio: reading frame: %v", err)
+	}
+	return pixel.PictureDataFromImage(r.img), nil
+}
+
+// Size returns the size of the Renderer in pixels.
+func (r *Renderer) Size() (width, height int) {
+	return r.img.Rect.Dx(), r.img.Rect.Dy()
+}
+
+// Release releases the GPU context of the Renderer, which can't be used any further.
+func (r *Renderer) Release() {
+	r.win.Release()
+}
@@ -0,0 +1,48 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+// Backend is a windowing system a Window is created with.
+type Backend int
+
+// List of all the Backends.
+const (
+	BackendX11 Backend = iota
+	BackendWayland
+	BackendCocoa
+	BackendWin32
+)
+
+// String returns the name of the Backend.
+func (b Backend) String() string {
+	switch b {
+	case BackendX11:
+		return "X11"
+	case BackendWayland:
+		return "Wayland"
+	case BackendCocoa:
+		return "Cocoa"
+	case BackendWin32:
+		return "Win32"
+	default:
+		return "Invalid"
+	}
+}
+
+// Backend returns the windowing system of the Window.
+//
+// The windowing system is chosen when the program is built, because GLFW 3.2, which pixelgl uses,
+// compiles in exactly one. On Linux and FreeBSD, it's X11 by default, which also runs on Wayland
+// sessions through XWayland, and Wayland with the wayland build tag:
+//
+//   go build -tags wayland
+//
+// A Wayland build doesn't fall back to X11 when no Wayland compositor is running, and GLFW 3.2
+// supports neither fractional scaling nor a choice of decorations there, so pixelgl has no options
+// for them. Only a Window of pixelsw selects the windowing system at run time, scales fractionally
+// (pixelsw.WindowConfig.HiDPI) and draws its own decorations on Wayland
+// (pixelsw.WindowConfig.ClientDecorations).
+func (w *Window) Backend() Backend {
+	return platformBackend
+}
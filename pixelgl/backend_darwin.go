@@ -0,0 +1,6 @@
+//go:build !ios && !purego
+// +build !ios,!purego
+
+package pixelgl
+
+const platformBackend = BackendCocoa
@@ -0,0 +1,8 @@
+//go:build (linux || freebsd) && wayland && !android && !purego
+// +build linux freebsd
+// +build wayland
+// +build !android,!purego
+
+package pixelgl
+
+const platformBackend = BackendWayland
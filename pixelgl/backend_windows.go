@@ -0,0 +1,6 @@
+//go:build !purego
+// +build !purego
+
+package pixelgl
+
+const platformBackend = BackendWin32
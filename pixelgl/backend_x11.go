@@ -0,0 +1,10 @@
+//go:build !windows && !darwin && !js && !android && !purego && !((linux || freebsd) && wayland)
+// +build !windows
+// +build !darwin
+// +build !js,!android
+// +build !purego
+// +build !linux,!freebsd !wayland
+
+package pixelgl
+
+const platformBackend = BackendX11
@@ -0,0 +1,274 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"sync"
+
+	"github.com/faiface/glhf"
+	"github.com/faiface/mainthread"
+	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// drawState is the state of a draw onto a Canvas, taken when the draw is made, because the state
+// of the Canvas may change before the draw is executed.
+type drawState struct {
+	dst    *Canvas
+	shader *glhf.Shader
+	tex    Texture
+	cmp    pixel.ComposeMethod
+	smooth bool
+	label  string
+	timer  *GPUTimer
+	linear bool
+	clip   scissor
+	depth  depthTest
+
+	// values of the uniforms, including the transform and the color mask, and the textures bound
+	// to the sampler2D uniforms
+	values   []interface{}
+	textures []Texture
+}
+
+// same returns whether the draws with the two states can be merged into one
+func (ds *drawState) same(other *drawState) bool {
+	if ds.dst != other.dst ||
+		ds.shader != other.shader ||
+		ds.tex != other.tex ||
+		ds.cmp != other.cmp ||
+		ds.smooth != other.smooth ||
+		ds.label != other.label ||
+		ds.timer != other.timer ||
+		ds.linear != other.linear ||
+		ds.clip != other.clip ||
+		ds.depth != other.depth ||
+		len(ds.values) != len(other.values) ||
+		len(ds.textures) != len(other.textures) {
+		return false
+	}
+	// all the types of the uniforms are comparable
+	for i := range ds.values {
+		if ds.values[i] != other.values[i] {
+			return false
+		}
+	}
+	for i := range ds.textures {
+		if ds.textures[i] != other.textures[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// issue executes the draw in mainthread, draw is called with everything but the vertices set up.
+func (ds *drawState) issue(draw func()) {
+	mainthread.CallNonBlock(debugWrap(func() {
+		defer beginPhase(pixel.PhaseDraw).End()
+
+		if ds.label != "" {
+			pushDebugGroup(ds.label)
+			defer popDebugGroup()
+		}
+		if ds.timer != nil {
+			ds.timer.start()
+			defer ds.timer.stop()
+		}
+
+		ds.dst.setGlhfBounds()
+		if ds.clip.on {
+			ds.clip.set()
+			defer ds.dst.setGlhfBounds()
+		}
+		setBlendFunc(ds.cmp)
+		if ds.depth.on {
+			ds.depth.set()
+			defer ds.depth.unset()
+		}
+		if ds.linear {
+			gl.Enable(gl.FRAMEBUFFER_SRGB)
+			defer gl.Disable(gl.FRAMEBUFFER_SRGB)
+		}
+
+		frame := ds.dst.gf.frame
+
+		frame.Begin()
+		ds.shader.Begin()
+
+		for loc, value := range ds.values {
+			ds.shader.SetUniformAttr(loc, value)
+		}
+		if len(ds.textures) > 0 {
+			bindTextures(ds.textures)
+			defer bindTextures(make([]Texture, len(ds.textures)))
+		}
+
+		if ds.tex == nil {
+			draw()
+		} else {
+			ds.tex.Begin()
+
+			if ds.tex.Smooth() != ds.smooth {
+				ds.tex.SetSmooth(ds.smooth)
+			}
+
+			draw()
+
+			ds.tex.End()
+		}
+
+		ds.shader.End()
+		frame.End()
+	}))
+}
+
+const (
+	// mergeMaxLen is the number of vertices up to which a draw is merged with the following ones,
+	// larger draws are issued directly from their own VertexSlice
+	mergeMaxLen = 1024
+
+	// mergeMaxBatch is the number of vertices after which the merged draws are issued
+	mergeMaxBatch = 64 * 1024
+)
+
+// drawBatch is a draw that hasn't been issued yet, the following draws with the same state are
+// merged into it by appending their vertices.
+type drawBatch struct {
+	drawState
+	data []float32
+	len  int
+}
+
+// pendingBatch holds the GLFrame whose batch is waiting to be issued. Each GLFrame keeps its own
+// batch, but only the current target's batch is pending: it's issued when a draw goes to another
+// target, so that the draws are executed in the order they were made, e.g. the draws onto a Canvas
+// before the Canvas is drawn onto the Window.
+//
+// The mutex also guards the batches of the GLFrames.
+var pendingBatch struct {
+	mu  sync.Mutex
+	dst *GLFrame
+}
+
+// mergeDraw merges the draw of the length vertices in data with the batch of its target if the
+// states match, otherwise it issues the batch and starts a new one.
+func mergeDraw(ds *drawState, data []float32, length int) {
+	pendingBatch.mu.Lock()
+	defer pendingBatch.mu.Unlock()
+
+	gf := ds.dst.gf
+	if prev := pendingBatch.dst; prev != nil && prev != gf {
+		prev.issueBatch()
+	}
+
+	b := gf.batch
+	if b != nil && (!b.same(ds) || b.len+length > mergeMaxBatch) {
+		gf.issueBatch()
+		b = nil
+	}
+	if b == nil {
+		b = &drawBatch{drawState: *ds}
+		gf.batch = b
+	}
+	pendingBatch.dst = gf
+	b.data = append(b.data, data...)
+	b.len += length
+}
+
+// issueBatch issues the batch of the GLFrame, if it has one
+//
+// must be called with pendingBatch.mu locked
+func (gf *GLFrame) issueBatch() {
+	if b := gf.batch; b != nil {
+		b.issue(b.draw)
+		gf.batch = nil
+	}
+	if pendingBatch.dst == gf {
+		pendingBatch.dst = nil
+	}
+}
+
+// flushDraws issues the pending batch, whichever target it draws onto. Call it before any OpenGL
+// work which depends on the order of the draws, such as changing a Canvas outside of drawing,
+// because the pending batch may be drawing the Canvas onto another target.
+//
+// must not be called inside mainthread
+func flushDraws() {
+	pendingBatch.mu.Lock()
+	defer pendingBatch.mu.Unlock()
+
+	if gf := pendingBatch.dst; gf != nil {
+		gf.issueBatch()
+	}
+}
+
+// flushDraws issues the batch of the GLFrame only. It's enough before reading the GLFrame or
+// changing the state its draws use, the batches of the other targets don't change it.
+//
+// must not be called inside mainthread
+func (gf *GLFrame) flushDraws() {
+	pendingBatch.mu.Lock()
+	defer pendingBatch.mu.Unlock()
+
+	gf.issueBatch()
+}
+
+// Flush issues the draws merged so far, so that the OpenGL work executed afterwards sees their
+// result.
+//
+// Consecutive draws onto a Canvas (or a Window) with the same Picture, composition method, shader
+// and uniforms are merged into one OpenGL draw call. Pixel flushes them automatically before
+// reading, copying, clearing or showing a Canvas, so calling Flush is only needed before custom
+// OpenGL code, e.g. code using the Canvas's Frame inside mainthread.
+func Flush() {
+	flushDraws()
+}
+
+// draw draws the merged vertices with the shader's VertexSlice for merged draws
+//
+// must be manually called inside mainthread
+func (b *drawBatch) draw() {
+	vs := b.dst.shader.mergeSlice(b.shader)
+	vs.Begin()
+	vs.SetLen(b.len)
+	vs.SetVertexData(b.data)
+	vs.Draw()
+	vs.End()
+}
+
+// mergeSlice returns the next VertexSlice of the merged draws with the current shader, the merged
+// draws take turns with them like GLTriangles. New ones are created when the shader was
+// recompiled.
+//
+// must be manually called inside mainthread
+func (gs *glShader) mergeSlice(shader *glhf.Shader) *glhf.VertexSlice {
+	if gs.mergedShader != shader {
+		gs.merged = [vertexBuffers]*glhf.VertexSlice{}
+		gs.mergedShader = shader
+	}
+	gs.mergedNext = (gs.mergedNext + 1) % vertexBuffers
+	if gs.merged[gs.mergedNext] == nil {
+		gs.merged[gs.mergedNext] = glhf.MakeVertexSlice(shader, 0, mergeMaxLen)
+	}
+	return gs.merged[gs.mergedNext]
+}
+
+// SetDrawMerging sets whether consecutive draws onto this Canvas with the same state are merged
+// into one OpenGL draw call, which is on by default. See Flush.
+//
+// Merging copies the vertices of every small draw, which is much cheaper than a separate draw call,
+// but useless when all the geometry is already batched, e.g. by pixel.Batch or imdraw.IMDraw. With
+// debugging enabled (see WindowConfig.Debug), the draws aren't merged, so that debug messages
+// carry their positions.
+func (c *Canvas) SetDrawMerging(merge bool) {
+	if !merge {
+		flushDraws()
+	}
+	c.noMerge = !merge
+}
+
+// DrawMerging returns whether consecutive draws onto this Canvas are merged, see SetDrawMerging.
+func (c *Canvas) DrawMerging() bool {
+	return !c.noMerge
+}
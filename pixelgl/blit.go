@@ -0,0 +1,164 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"github.com/faiface/glhf"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/pkg/errors"
+)
+
+// blitFramebuffer copies the pixels inside the src rectangle of the read framebuffer into the dst
+// rectangle of the draw framebuffer (0 is the framebuffer of the current Window), just like
+// glBlitFramebuffer. The framebuffer bindings are preserved.
+//
+// If the context can't blit framebuffers (see canBlit), tex, the color texture of the read
+// framebuffer, is drawn as a quad instead. The viewport and the blending function are changed
+// then, the following draws must set them.
+//
+// must be manually called inside mainthread
+func blitFramebuffer(
+	read uint32, tex Texture, draw uint32,
+	sx0, sy0, sx1, sy1, dx0, dy0, dx1, dy1 int32,
+	filter uint32,
+) {
+	if canBlit {
+		var prevRead, prevDraw int32
+		gl.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &prevRead)
+		gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &prevDraw)
+
+		gl.BindFramebuffer(gl.READ_FRAMEBUFFER, read)
+		gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, draw)
+		gl.BlitFramebuffer(
+			sx0, sy0, sx1, sy1,
+			dx0, dy0, dx1, dy1,
+			gl.COLOR_BUFFER_BIT, filter,
+		)
+
+		gl.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(prevRead))
+		gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, uint32(prevDraw))
+		return
+	}
+
+	// the viewport can't be flipped, the texture coordinates are flipped instead
+	if dx1 < dx0 {
+		dx0, dx1, sx0, sx1 = dx1, dx0, sx1, sx0
+	}
+	if dy1 < dy0 {
+		dy0, dy1, sy0, sy1 = dy1, dy0, sy1, sy0
+	}
+	if dx0 == dx1 || dy0 == dy1 || tex.Width() == 0 || tex.Height() == 0 {
+		return
+	}
+
+	if blitter == nil {
+		blitter = newQuadBlitter()
+	}
+
+	var prev int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &prev)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, draw)
+
+	// the quad covers the whole viewport, which is the dst rectangle
+	glhf.Bounds(int(dx0), int(dy0), int(dx1-dx0), int(dy1-dy0))
+	glhf.BlendFunc(glhf.One, glhf.Zero)
+
+	// the filter only matters if the pixels are stretched, which spares changing the filter of
+	// external textures
+	if abs32(sx1-sx0) != dx1-dx0 || abs32(sy1-sy0) != dy1-dy0 {
+		if smooth := filter == gl.LINEAR; tex.Smooth() != smooth {
+			tex.SetSmooth(smooth)
+		}
+	}
+
+	tw, th := float32(tex.Width()), float32(tex.Height())
+	s0, t0 := float32(sx0)/tw, float32(sy0)/th
+	s1, t1 := float32(sx1)/tw, float32(sy1)/th
+
+	blitter.shader.Begin()
+	tex.Begin()
+	blitter.slice.Begin()
+	blitter.slice.SetVertexData([]float32{
+		-1, -1, s0, t0,
+		+1, -1, s1, t0,
+		+1, +1, s1, t1,
+		-1, -1, s0, t0,
+		+1, +1, s1, t1,
+		-1, +1, s0, t1,
+	})
+	blitter.slice.Draw()
+	blitter.slice.End()
+	tex.End()
+	blitter.shader.End()
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(prev))
+}
+
+// quadBlitter draws a texture over the whole viewport, replacing blits of framebuffers on OpenGL ES
+// 2.0 contexts without them.
+type quadBlitter struct {
+	shader *glhf.Shader
+	slice  *glhf.VertexSlice
+}
+
+// blitter is created by the first blitFramebuffer drawing a quad.
+//
+// accessed only inside mainthread
+var blitter *quadBlitter
+
+func newQuadBlitter() *quadBlitter {
+	shader, err := glhf.NewShader(
+		blitVertexFormat,
+		glhf.AttrFormat{},
+		shaderSource(blitVertexShader, false),
+		shaderSource(blitFragmentShader, true),
+	)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to create blit shader, there's a bug in the shader"))
+	}
+	return &quadBlitter{
+		shader: shader,
+		slice:  glhf.MakeVertexSlice(shader, 6, 6),
+	}
+}
+
+func abs32(x int32) int32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+var blitVertexFormat = glhf.AttrFormat{
+	{Name: "aPosition", Type: glhf.Vec2},
+	{Name: "aTexCoords", Type: glhf.Vec2},
+}
+
+var blitVertexShader = `
+#version 330 core
+
+in vec2 aPosition;
+in vec2 aTexCoords;
+
+out vec2 vTexCoords;
+
+void main() {
+	gl_Position = vec4(aPosition, 0.0, 1.0);
+	vTexCoords = aTexCoords;
+}
+`
+
+var blitFragmentShader = `
+#version 330 core
+
+in vec2 vTexCoords;
+
+out vec4 fragColor;
+
+uniform sampler2D uTexture;
+
+void main() {
+	fragColor = texture(uTexture, vTexCoords);
+}
+`
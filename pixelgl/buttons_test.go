@@ -0,0 +1,183 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"testing"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/stretchr/testify/assert"
+)
+
+// the values of the Buttons and the Joysticks are spelled out, they must stay the ones of GLFW
+func TestButton_GLFWValues(t *testing.T) {
+	for _, b := range []struct {
+		button Button
+		glfw   int
+	}{
+		{MouseButton1, int(glfw.MouseButton1)},
+		{MouseButton2, int(glfw.MouseButton2)},
+		{MouseButton3, int(glfw.MouseButton3)},
+		{MouseButton4, int(glfw.MouseButton4)},
+		{MouseButton5, int(glfw.MouseButton5)},
+		{MouseButton6, int(glfw.MouseButton6)},
+		{MouseButton7, int(glfw.MouseButton7)},
+		{MouseButton8, int(glfw.MouseButton8)},
+		{MouseButtonLast, int(glfw.MouseButtonLast)},
+		{MouseButtonLeft, int(glfw.MouseButtonLeft)},
+		{MouseButtonRight, int(glfw.MouseButtonRight)},
+		{MouseButtonMiddle, int(glfw.MouseButtonMiddle)},
+		{KeyUnknown, int(glfw.KeyUnknown)},
+		{KeySpace, int(glfw.KeySpace)},
+		{KeyApostrophe, int(glfw.KeyApostrophe)},
+		{KeyComma, int(glfw.KeyComma)},
+		{KeyMinus, int(glfw.KeyMinus)},
+		{KeyPeriod, int(glfw.KeyPeriod)},
+		{KeySlash, int(glfw.KeySlash)},
+		{Key0, int(glfw.Key0)},
+		{Key1, int(glfw.Key1)},
+		{Key2, int(glfw.Key2)},
+		{Key3, int(glfw.Key3)},
+		{Key4, int(glfw.Key4)},
+		{Key5, int(glfw.Key5)},
+		{Key6, int(glfw.Key6)},
+		{Key7, int(glfw.Key7)},
+		{Key8, int(glfw.Key8)},
+		{Key9, int(glfw.Key9)},
+		{KeySemicolon, int(glfw.KeySemicolon)},
+		{KeyEqual, int(glfw.KeyEqual)},
+		{KeyA, int(glfw.KeyA)},
+		{KeyB, int(glfw.KeyB)},
+		{KeyC, int(glfw.KeyC)},
+		{KeyD, int(glfw.KeyD)},
+		{KeyE, int(glfw.KeyE)},
+		{KeyF, int(glfw.KeyF)},
+		{KeyG, int(glfw.KeyG)},
+		{KeyH, int(glfw.KeyH)},
+		{KeyI, int(glfw.KeyI)},
+		{KeyJ, int(glfw.KeyJ)},
+		{KeyK, int(glfw.KeyK)},
+		{KeyL, int(glfw.KeyL)},
+		{KeyM, int(glfw.KeyM)},
+		{KeyN, int(glfw.KeyN)},
+		{KeyO, int(glfw.KeyO)},
+		{KeyP, int(glfw.KeyP)},
+		{KeyQ, int(glfw.KeyQ)},
+		{KeyR, int(glfw.KeyR)},
+		{KeyS, int(glfw.KeyS)},
+		{KeyT, int(glfw.KeyT)},
+		{KeyU, int(glfw.KeyU)},
+		{KeyV, int(glfw.KeyV)},
+		{KeyW, int(glfw.KeyW)},
+		{KeyX, int(glfw.KeyX)},
+		{KeyY, int(glfw.KeyY)},
+		{KeyZ, int(glfw.KeyZ)},
+		{KeyLeftBracket, int(glfw.KeyLeftBracket)},
+		{KeyBackslash, int(glfw.KeyBackslash)},
+		{KeyRightBracket, int(glfw.KeyRightBracket)},
+		{KeyGraveAccent, int(glfw.KeyGraveAccent)},
+		{KeyWorld1, int(glfw.KeyWorld1)},
+		{KeyWorld2, int(glfw.KeyWorld2)},
+		{KeyEscape, int(glfw.KeyEscape)},
+		{KeyEnter, int(glfw.KeyEnter)},
+		{KeyTab, int(glfw.KeyTab)},
+		{KeyBackspace, int(glfw.KeyBackspace)},
+		{KeyInsert, int(glfw.KeyInsert)},
+		{KeyDelete, int(glfw.KeyDelete)},
+		{KeyRight, int(glfw.KeyRight)},
+		{KeyLeft, int(glfw.KeyLeft)},
+		{KeyDown, int(glfw.KeyDown)},
+		{KeyUp, int(glfw.KeyUp)},
+		{KeyPageUp, int(glfw.KeyPageUp)},
+		{KeyPageDown, int(glfw.KeyPageDown)},
+		{KeyHome, int(glfw.KeyHome)},
+		{KeyEnd, int(glfw.KeyEnd)},
+		{KeyCapsLock, int(glfw.KeyCapsLock)},
+		{KeyScrollLock, int(glfw.KeyScrollLock)},
+		{KeyNumLock, int(glfw.KeyNumLock)},
+		{KeyPrintScreen, int(glfw.KeyPrintScreen)},
+		{KeyPause, int(glfw.KeyPause)},
+		{KeyF1, int(glfw.KeyF1)},
+		{KeyF2, int(glfw.KeyF2)},
+		{KeyF3, int(glfw.KeyF3)},
+		{KeyF4, int(glfw.KeyF4)},
+		{KeyF5, int(glfw.KeyF5)},
+		{KeyF6, int(glfw.KeyF6)},
+		{KeyF7, int(glfw.KeyF7)},
+		{KeyF8, int(glfw.KeyF8)},
+		{KeyF9, int(glfw.KeyF9)},
+		{KeyF10, int(glfw.KeyF10)},
+		{KeyF11, int(glfw.KeyF11)},
+		{KeyF12, int(glfw.KeyF12)},
+		{KeyF13, int(glfw.KeyF13)},
+		{KeyF14, int(glfw.KeyF14)},
+		{KeyF15, int(glfw.KeyF15)},
+		{KeyF16, int(glfw.KeyF16)},
+		{KeyF17, int(glfw.KeyF17)},
+		{KeyF18, int(glfw.KeyF18)},
+		{KeyF19, int(glfw.KeyF19)},
+		{KeyF20, int(glfw.KeyF20)},
+		{KeyF21, int(glfw.KeyF21)},
+		{KeyF22, int(glfw.KeyF22)},
+		{KeyF23, int(glfw.KeyF23)},
+		{KeyF24, int(glfw.KeyF24)},
+		{KeyF25, int(glfw.KeyF25)},
+		{KeyKP0, int(glfw.KeyKP0)},
+		{KeyKP1, int(glfw.KeyKP1)},
+		{KeyKP2, int(glfw.KeyKP2)},
+		{KeyKP3, int(glfw.KeyKP3)},
+		{KeyKP4, int(glfw.KeyKP4)},
+		{KeyKP5, int(glfw.KeyKP5)},
+		{KeyKP6, int(glfw.KeyKP6)},
+		{KeyKP7, int(glfw.KeyKP7)},
+		{KeyKP8, int(glfw.KeyKP8)},
+		{KeyKP9, int(glfw.KeyKP9)},
+		{KeyKPDecimal, int(glfw.KeyKPDecimal)},
+		{KeyKPDivide, int(glfw.KeyKPDivide)},
+		{KeyKPMultiply, int(glfw.KeyKPMultiply)},
+		{KeyKPSubtract, int(glfw.KeyKPSubtract)},
+		{KeyKPAdd, int(glfw.KeyKPAdd)},
+		{KeyKPEnter, int(glfw.KeyKPEnter)},
+		{KeyKPEqual, int(glfw.KeyKPEqual)},
+		{KeyLeftShift, int(glfw.KeyLeftShift)},
+		{KeyLeftControl, int(glfw.KeyLeftControl)},
+		{KeyLeftAlt, int(glfw.KeyLeftAlt)},
+		{KeyLeftSuper, int(glfw.KeyLeftSuper)},
+		{KeyRightShift, int(glfw.KeyRightShift)},
+		{KeyRightControl, int(glfw.KeyRightControl)},
+		{KeyRightAlt, int(glfw.KeyRightAlt)},
+		{KeyRightSuper, int(glfw.KeyRightSuper)},
+		{KeyMenu, int(glfw.KeyMenu)},
+		{KeyLast, int(glfw.KeyLast)},
+	} {
+		assert.Equal(t, b.glfw, int(b.button), b.button.String())
+	}
+}
+
+func TestJoystick_GLFWValues(t *testing.T) {
+	for _, j := range []struct {
+		joystick Joystick
+		glfw     glfw.Joystick
+	}{
+		{Joystick1, glfw.Joystick1},
+		{Joystick2, glfw.Joystick2},
+		{Joystick3, glfw.Joystick3},
+		{Joystick4, glfw.Joystick4},
+		{Joystick5, glfw.Joystick5},
+		{Joystick6, glfw.Joystick6},
+		{Joystick7, glfw.Joystick7},
+		{Joystick8, glfw.Joystick8},
+		{Joystick9, glfw.Joystick9},
+		{Joystick10, glfw.Joystick10},
+		{Joystick11, glfw.Joystick11},
+		{Joystick12, glfw.Joystick12},
+		{Joystick13, glfw.Joystick13},
+		{Joystick14, glfw.Joystick14},
+		{Joystick15, glfw.Joystick15},
+		{Joystick16, glfw.Joystick16},
+		{JoystickLast, glfw.JoystickLast},
+	} {
+		assert.Equal(t, int(j.glfw), int(j.joystick))
+	}
+}
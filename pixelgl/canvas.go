@@ -1,3 +1,6 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
 package pixelgl
 
 import (
@@ -7,6 +10,7 @@ import (
 	"github.com/faiface/glhf"
 	"github.com/faiface/mainthread"
 	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/pkg/errors"
 )
@@ -23,6 +27,15 @@ type Canvas struct {
 	mat    mgl32.Mat3
 	col    mgl32.Vec4
 	smooth bool
+	label  string
+	timer  *GPUTimer
+	depth  depthTest
+
+	noMerge   bool
+	lazyClear bool
+
+	dirtyMode bool
+	dirty     pixel.Rect
 
 	sprite *pixel.Sprite
 }
@@ -50,13 +63,40 @@ func (c *Canvas) SetUniform(name string, value interface{}) {
 	c.shader.setUniform(name, value)
 }
 
+// SetUniformTexture binds the texture of the GLPicture to the named sampler2D uniform, so that a
+// custom fragment shader can sample another texture than the one of the drawn Picture. If the
+// uniform already has a texture, it will be replaced, which takes effect for the following draws.
+//
+// The whole texture is bound, whatever the bounds of the GLPicture are. Like with SetUniform, a
+// new uniform must be set before SetFragmentShader.
+func (c *Canvas) SetUniformTexture(name string, pic GLPicture) {
+	c.shader.setTexture(name, pic)
+}
+
 // SetFragmentShader allows you to set a new fragment shader on the underlying
 // framebuffer. Argument "src" is the GLSL source, not a filename.
 func (c *Canvas) SetFragmentShader(src string) {
+	c.gf.flushDraws()
 	c.shader.fs = src
 	c.shader.update()
 }
 
+// TrySetFragmentShader is like SetFragmentShader, but returns the error of compiling the shader,
+// including the GLSL error log, instead of panicking. If it fails, the Canvas keeps its previous
+// shader.
+//
+// This makes it possible to edit shaders while the program is running, see the assets package.
+func (c *Canvas) TrySetFragmentShader(src string) error {
+	c.gf.flushDraws()
+	prev := c.shader.fs
+	c.shader.fs = src
+	if err := c.shader.compile(); err != nil {
+		c.shader.fs = prev
+		return errors.Wrap(err, "failed to set fragment shader")
+	}
+	return nil
+}
+
 // MakeTriangles creates a specialized copy of the supplied Triangles that draws onto this Canvas.
 //
 // TrianglesPosition, TrianglesColor and TrianglesPicture are supported.
@@ -124,6 +164,10 @@ func (c *Canvas) SetComposeMethod(cmp pixel.ComposeMethod) {
 // SetBounds resizes the Canvas to the new bounds. Old content will be preserved.
 func (c *Canvas) SetBounds(bounds pixel.Rect) {
 	c.gf.SetBounds(bounds)
+	if c.label != "" {
+		// the framebuffer was recreated
+		c.SetLabel(c.label)
+	}
 	if c.sprite == nil {
 		c.sprite = pixel.NewSprite(nil, pixel.Rect{})
 	}
@@ -148,6 +192,34 @@ func (c *Canvas) Smooth() bool {
 	return c.smooth
 }
 
+// SetLabel sets a name of the Canvas used by frame debuggers, such as RenderDoc or apitrace. The
+// Canvas's framebuffer and texture are labeled with it and all draws onto the Canvas are grouped
+// under it (see PushDebugGroup).
+func (c *Canvas) SetLabel(label string) {
+	c.label = label
+	frame := c.gf.frame
+	mainthread.CallNonBlock(func() {
+		labelObject(gl.FRAMEBUFFER, frame.ID(), label)
+		labelObject(gl.TEXTURE, frame.Texture().ID(), label)
+	})
+}
+
+// Label returns the name of the Canvas set by SetLabel.
+func (c *Canvas) Label() string {
+	return c.label
+}
+
+// SetGPUTimer sets a GPUTimer measuring the GPU time of all draws and clears onto the Canvas.
+// Passing nil stops measuring.
+func (c *Canvas) SetGPUTimer(timer *GPUTimer) {
+	c.timer = timer
+}
+
+// GPUTimer returns the GPUTimer set by SetGPUTimer.
+func (c *Canvas) GPUTimer() *GPUTimer {
+	return c.timer
+}
+
 // must be manually called inside mainthread
 func (c *Canvas) setGlhfBounds() {
 	_, _, bw, bh := intBounds(c.gf.Bounds())
@@ -179,13 +251,57 @@ func setBlendFunc(cmp pixel.ComposeMethod) {
 		glhf.BlendFunc(glhf.One, glhf.One)
 	case pixel.ComposeCopy:
 		glhf.BlendFunc(glhf.One, glhf.Zero)
+	case pixel.ComposeMultiply:
+		glhf.BlendFunc(glhf.BlendFactor(gl.DST_COLOR), glhf.OneMinusSrcAlpha)
 	default:
 		panic(errors.New("Canvas: invalid compose method"))
 	}
 }
 
-// Clear fills the whole Canvas with a single color.
+// Clear fills the whole Canvas with a single color. In the dirty rendering mode, only the dirty
+// region is filled, see SetDirtyRendering. With lazy clearing, Clear does nothing, see
+// SetLazyClear.
 func (c *Canvas) Clear(color color.Color) {
+	if c.lazyClear {
+		return
+	}
+	flushDraws()
+	clip, ok := c.scissor()
+	if !ok {
+		return
+	}
+	c.clear(clip, color)
+}
+
+// ClearRect fills the rectangle of the Canvas with a single color, which is much cheaper than
+// Clear for a small part of a large Canvas. The rectangle is in the coordinates of the Canvas's
+// bounds, the Matrix set by SetMatrix doesn't apply to it.
+//
+// ClearRect clears even with lazy clearing. In the dirty rendering mode, only the rectangle's part
+// within the dirty region is filled.
+func (c *Canvas) ClearRect(r pixel.Rect, color color.Color) {
+	flushDraws()
+	clip, ok := c.scissorRect(r)
+	if !ok {
+		return
+	}
+	c.clear(clip, color)
+}
+
+// SetLazyClear sets whether Clear skips filling the Canvas, because every frame draws over all of
+// it anyway, e.g. with an opaque background or a tile map covering the view. A full clear of a
+// large Canvas, especially a linear (HDR) one, takes a measurable part of a frame on weak GPUs.
+func (c *Canvas) SetLazyClear(lazy bool) {
+	c.lazyClear = lazy
+}
+
+// LazyClear returns whether Clear skips filling the Canvas, see SetLazyClear.
+func (c *Canvas) LazyClear() bool {
+	return c.lazyClear
+}
+
+// clear fills the part of the Canvas within the scissor with the color
+func (c *Canvas) clear(clip scissor, color color.Color) {
 	c.gf.Dirty()
 
 	rgba := pixel.ToRGBA(color)
@@ -198,17 +314,36 @@ func (c *Canvas) Clear(color color.Color) {
 		A: float64(c.col[3]),
 	})
 
-	mainthread.CallNonBlock(func() {
+	label := c.label
+	timer := c.timer
+
+	mainthread.CallNonBlock(debugWrap(func() {
+		defer beginPhase(pixel.PhaseDraw).End()
+
+		if label != "" {
+			pushDebugGroup(label)
+			defer popDebugGroup()
+		}
+		if timer != nil {
+			timer.start()
+			defer timer.stop()
+		}
+
 		c.setGlhfBounds()
-		c.gf.Frame().Begin()
+		if clip.on {
+			clip.set()
+			defer c.setGlhfBounds()
+		}
+		c.gf.frame.Begin()
+		// the clear color is written as it is, in sRGB, even onto a linear Canvas
 		glhf.Clear(
 			float32(rgba.R),
 			float32(rgba.G),
 			float32(rgba.B),
 			float32(rgba.A),
 		)
-		c.gf.Frame().End()
-	})
+		c.gf.frame.End()
+	}))
 }
 
 // Color returns the color of the pixel over the given position inside the Canvas.
@@ -219,42 +354,84 @@ func (c *Canvas) Color(at pixel.Vec) pixel.RGBA {
 // Texture returns the underlying OpenGL Texture of this Canvas.
 //
 // Implements GLPicture interface.
-func (c *Canvas) Texture() *glhf.Texture {
+func (c *Canvas) Texture() Texture {
 	return c.gf.Texture()
 }
 
-// Frame returns the underlying OpenGL Frame of this Canvas.
-func (c *Canvas) Frame() *glhf.Frame {
-	return c.gf.frame
+// Frame returns the underlying OpenGL Framebuffer of this Canvas.
+func (c *Canvas) Frame() Framebuffer {
+	flushDraws()
+	return c.gf.Frame()
 }
 
 // SetPixels replaces the content of the Canvas with the provided pixels. The provided slice must be
 // an alpha-premultiplied RGBA sequence of correct length (4 * width * height).
 func (c *Canvas) SetPixels(pixels []uint8) {
+	flushDraws()
 	c.gf.Dirty()
 
-	mainthread.Call(func() {
+	mainthread.Call(debugWrap(func() {
+		defer beginPhase(pixel.PhaseUpload).End()
+
 		tex := c.Texture()
 		tex.Begin()
 		tex.SetPixels(0, 0, tex.Width(), tex.Height(), pixels)
 		tex.End()
-	})
+	}))
 }
 
 // Pixels returns an alpha-premultiplied RGBA sequence of the content of the Canvas.
 func (c *Canvas) Pixels() []uint8 {
+	c.gf.flushDraws()
 	var pixels []uint8
 
-	mainthread.Call(func() {
-		tex := c.Texture()
-		tex.Begin()
-		pixels = tex.Pixels(0, 0, tex.Width(), tex.Height())
-		tex.End()
-	})
+	mainthread.Call(debugWrap(func() {
+		pixels = framePixels(c.gf.frame)
+	}))
 
 	return pixels
 }
 
+// CopyTo copies the pixels inside the srcRect of this Canvas into the dstRect of the dst Canvas.
+// Both rectangles are in the coordinates of their respective Canvas's Bounds.
+//
+// This is a direct framebuffer copy, which is much faster than drawing the Canvas as a Sprite. The
+// matrix, color mask and composition method of the dst Canvas have no effect, the pixels inside
+// the dstRect are simply replaced. If the sizes of the rectangles differ, the pixels are stretched
+// smoothly or pixely according to the dst Canvas's Smooth setting.
+//
+// Copying to the same Canvas is allowed only if the rectangles don't overlap.
+func (c *Canvas) CopyTo(dst *Canvas, srcRect, dstRect pixel.Rect) {
+	flushDraws()
+	dst.gf.Dirty()
+
+	sx0, sy0, sx1, sy1 := framebufferRect(c.Bounds(), srcRect)
+	dx0, dy0, dx1, dy1 := framebufferRect(dst.Bounds(), dstRect)
+
+	filter := uint32(gl.NEAREST)
+	if dst.smooth {
+		filter = gl.LINEAR
+	}
+
+	mainthread.CallNonBlock(debugWrap(func() {
+		blitFramebuffer(
+			c.gf.frame.ID(), c.gf.frame.Texture(), dst.gf.frame.ID(),
+			sx0, sy0, sx1, sy1,
+			dx0, dy0, dx1, dy1,
+			filter,
+		)
+	}))
+}
+
+// framebufferRect converts a rectangle in the coordinates of the bounds into integer framebuffer
+// coordinates, which have their origin at the bounds' Min.
+func framebufferRect(bounds, r pixel.Rect) (x0, y0, x1, y1 int32) {
+	bx, by, _, _ := intBounds(bounds)
+	rx, ry, rw, rh := intBounds(r)
+	x0, y0 = int32(rx-bx), int32(ry-by)
+	return x0, y0, x0 + int32(rw), y0 + int32(rh)
+}
+
 // Draw draws the content of the Canvas onto another Target, transformed by the given Matrix, just
 // like if it was a Sprite containing the whole Canvas.
 func (c *Canvas) Draw(t pixel.Target, matrix pixel.Matrix) {
@@ -271,75 +448,100 @@ func (c *Canvas) DrawColorMask(t pixel.Target, matrix pixel.Matrix, mask color.C
 
 type canvasTriangles struct {
 	*GLTriangles
-	dst *Canvas
+	dst    *Canvas
+	static bool
+}
+
+var _ pixel.StaticTriangles = (*canvasTriangles)(nil)
+
+// SetStatic sets whether the triangles are static, static triangles are always drawn straight from
+// their VertexSlice, they're never merged with other draws.
+func (ct *canvasTriangles) SetStatic(static bool) {
+	ct.static = static
 }
 
-func (ct *canvasTriangles) draw(tex *glhf.Texture, bounds pixel.Rect) {
+// draw draws the triangles with the texture, which is in linear RGB if texLinear is true.
+func (ct *canvasTriangles) draw(tex Texture, bounds pixel.Rect, texLinear bool) {
+	clip, ok := ct.dst.scissor()
+	if !ok {
+		return
+	}
 	ct.dst.gf.Dirty()
 
 	// save the current state vars to avoid race condition
-	cmp := ct.dst.cmp
-	smt := ct.dst.smooth
 	mat := ct.dst.mat
 	col := ct.dst.col
+	linear := ct.dst.gf.linear
+	depth := ct.dst.depth
+
+	ct.dst.shader.uniformDefaults.transform = mat
+	ct.dst.shader.uniformDefaults.colormask = col
+	ct.dst.shader.uniformDefaults.linear = bool2int32(linear)
+	ct.dst.shader.uniformDefaults.texLinear = bool2int32(texLinear)
+	ct.dst.shader.uniformDefaults.depth = depth.z
+	dstBounds := ct.dst.Bounds()
+	ct.dst.shader.uniformDefaults.bounds = mgl32.Vec4{
+		float32(dstBounds.Min.X),
+		float32(dstBounds.Min.Y),
+		float32(dstBounds.W()),
+		float32(dstBounds.H()),
+	}
 
-	mainthread.CallNonBlock(func() {
-		ct.dst.setGlhfBounds()
-		setBlendFunc(cmp)
-
-		frame := ct.dst.gf.Frame()
-		shader := ct.dst.shader.s
-
-		frame.Begin()
-		shader.Begin()
-
-		ct.dst.shader.uniformDefaults.transform = mat
-		ct.dst.shader.uniformDefaults.colormask = col
-		dstBounds := ct.dst.Bounds()
-		ct.dst.shader.uniformDefaults.bounds = mgl32.Vec4{
-			float32(dstBounds.Min.X),
-			float32(dstBounds.Min.Y),
-			float32(dstBounds.W()),
-			float32(dstBounds.H()),
-		}
-
-		bx, by, bw, bh := intBounds(bounds)
-		ct.dst.shader.uniformDefaults.texbounds = mgl32.Vec4{
-			float32(bx),
-			float32(by),
-			float32(bw),
-			float32(bh),
-		}
+	bx, by, bw, bh := intBounds(bounds)
+	ct.dst.shader.uniformDefaults.texbounds = mgl32.Vec4{
+		float32(bx),
+		float32(by),
+		float32(bw),
+		float32(bh),
+	}
 
-		for loc, u := range ct.dst.shader.uniforms {
-			ct.dst.shader.s.SetUniformAttr(loc, u.Value())
+	// the values of the uniforms and the textures are taken now, they may change before the
+	// triangles are drawn
+	values := make([]interface{}, len(ct.dst.shader.uniforms))
+	for loc, u := range ct.dst.shader.uniforms {
+		values[loc] = u.Value()
+	}
+	textures := make([]Texture, len(ct.dst.shader.textures))
+	for i, t := range ct.dst.shader.textures {
+		if t.pic != nil {
+			textures[i] = t.pic.Texture()
 		}
+	}
 
-		if tex == nil {
-			ct.vs.Begin()
-			ct.vs.Draw()
-			ct.vs.End()
-		} else {
-			tex.Begin()
-
-			if tex.Smooth() != smt {
-				tex.SetSmooth(smt)
-			}
-
-			ct.vs.Begin()
-			ct.vs.Draw()
-			ct.vs.End()
+	ds := &drawState{
+		dst:      ct.dst,
+		shader:   ct.dst.shader.s,
+		tex:      tex,
+		cmp:      ct.dst.cmp,
+		smooth:   ct.dst.smooth,
+		label:    ct.dst.label,
+		timer:    ct.dst.timer,
+		linear:   linear,
+		clip:     clip,
+		depth:    depth,
+		values:   values,
+		textures: textures,
+	}
 
-			tex.End()
-		}
+	// small draws are merged with the following ones with the same state, their vertices are
+	// copied, because the triangles may be updated before the merged draw is issued
+	if !ct.static && !ct.dst.noMerge && !debug.enabled && ct.Len() <= mergeMaxLen {
+		mergeDraw(ds, ct.data, ct.Len())
+		return
+	}
 
-		shader.End()
-		frame.End()
+	flushDraws()
+	vs := ct.vs
+	ct.drawn = true
+	ds.issue(func() {
+		vs.Begin()
+		vs.Draw()
+		vs.End()
 	})
 }
 
 func (ct *canvasTriangles) Draw() {
-	ct.draw(nil, pixel.Rect{})
+	ct.draw(nil, pixel.Rect{}, false)
 }
 
 type canvasPicture struct {
@@ -352,7 +554,7 @@ func (cp *canvasPicture) Draw(t pixel.TargetTriangles) {
 	if cp.dst != ct.dst {
 		panic(fmt.Errorf("(%T).Draw: TargetTriangles generated by different Canvas", cp))
 	}
-	ct.draw(cp.GLPicture.Texture(), cp.GLPicture.Bounds())
+	ct.draw(cp.GLPicture.Texture(), cp.GLPicture.Bounds(), linearPicture(cp.GLPicture))
 }
 
 const (
@@ -0,0 +1,392 @@
+//go:build js || android || ios
+// +build js android ios
+
+package pixelgl
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pkg/errors"
+)
+
+// Canvas is an off-screen rectangular BasicTarget and Picture at the same time, that you can draw
+// onto. In the browser and on the phones, its pixels are in an OpenGL ES framebuffer.
+//
+// It supports TrianglesPosition, TrianglesColor, TrianglesPicture and PictureColor.
+type Canvas struct {
+	ctx    *context
+	shader *glShader
+	bounds pixel.Rect
+	fbo    glFBO
+	tex    *texture
+
+	cmp    pixel.ComposeMethod
+	mat    mgl32.Mat3
+	col    mgl32.Vec4
+	smooth bool
+
+	// the pixels read by Color, nil after the Canvas changes
+	pixels []uint8
+
+	sprite *pixel.Sprite
+}
+
+var _ pixel.ComposeTarget = (*Canvas)(nil)
+
+// NewCanvas creates a new empty, fully transparent Canvas with given bounds. The Canvases live in
+// the OpenGL ES context of the Window, so in the browser and on the phones NewCanvas panics if
+// it's called before NewWindow.
+func NewCanvas(bounds pixel.Rect) *Canvas {
+	return newCanvas(mustContext("NewCanvas"), bounds)
+}
+
+func newCanvas(ctx *context, bounds pixel.Rect) *Canvas {
+	c := &Canvas{
+		ctx: ctx,
+		mat: mgl32.Ident3(),
+		col: mgl32.Vec4{1, 1, 1, 1},
+	}
+
+	baseShader(c)
+	c.SetBounds(bounds)
+	c.shader.update()
+	return c
+}
+
+// SetUniform will update the named uniform with the value of any supported underlying
+// attribute variable. If the uniform already exists, including defaults, they will be reassigned
+// to the new value. The value can be a pointer.
+func (c *Canvas) SetUniform(name string, value interface{}) {
+	c.shader.setUniform(name, value)
+}
+
+// SetFragmentShader allows you to set a new fragment shader on the underlying
+// framebuffer. Argument "src" is the GLSL source, not a filename.
+//
+// The source is written in GLSL 3.30 like on the desktop, it's translated to GLSL ES 3.00.
+func (c *Canvas) SetFragmentShader(src string) {
+	c.shader.fs = src
+	c.shader.update()
+}
+
+// TrySetFragmentShader is like SetFragmentShader, but returns the error of compiling the shader,
+// including the GLSL error log, instead of panicking. If it fails, the Canvas keeps its previous
+// shader.
+func (c *Canvas) TrySetFragmentShader(src string) error {
+	prev := c.shader.fs
+	c.shader.fs = src
+	if err := c.shader.compile(); err != nil {
+		c.shader.fs = prev
+		return errors.Wrap(err, "failed to set fragment shader")
+	}
+	return nil
+}
+
+// MakeTriangles creates a specialized copy of the supplied Triangles that draws onto this Canvas.
+//
+// TrianglesPosition, TrianglesColor and TrianglesPicture are supported.
+func (c *Canvas) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	return &canvasTriangles{
+		triangles: newTriangles(c.ctx, t),
+		dst:       c,
+	}
+}
+
+// MakePicture create a specialized copy of the supplied Picture that draws onto this Canvas.
+//
+// PictureColor is supported.
+func (c *Canvas) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	if cp, ok := p.(*canvasPicture); ok {
+		return &canvasPicture{
+			GLPicture: cp.GLPicture,
+			dst:       c,
+		}
+	}
+	if gp, ok := p.(GLPicture); ok {
+		return &canvasPicture{
+			GLPicture: gp,
+			dst:       c,
+		}
+	}
+	return &canvasPicture{
+		GLPicture: newPicture(c.ctx, p),
+		dst:       c,
+	}
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (c *Canvas) SetMatrix(m pixel.Matrix) {
+	// pixel.Matrix is 3x2 with an implicit 0, 0, 1 row after it, see the desktop Canvas
+	for i, j := range [...]int{0, 1, 3, 4, 6, 7} {
+		c.mat[j] = float32(m[i])
+	}
+}
+
+// SetColorMask sets a color that every color in triangles or a picture will be multiplied by.
+func (c *Canvas) SetColorMask(col color.Color) {
+	rgba := pixel.Alpha(1)
+	if col != nil {
+		rgba = pixel.ToRGBA(col)
+	}
+	c.col = mgl32.Vec4{
+		float32(rgba.R),
+		float32(rgba.G),
+		float32(rgba.B),
+		float32(rgba.A),
+	}
+}
+
+// SetComposeMethod sets a Porter-Duff composition method to be used in the following draws onto
+// this Canvas.
+func (c *Canvas) SetComposeMethod(cmp pixel.ComposeMethod) {
+	c.cmp = cmp
+}
+
+// SetBounds resizes the Canvas to the new bounds. Old content will be preserved.
+func (c *Canvas) SetBounds(bounds pixel.Rect) {
+	if c.tex != nil && bounds == c.bounds {
+		return
+	}
+
+	w, h := frameSize(bounds)
+
+	ctx := c.ctx
+	tex := ctx.newTexture(w, h, nil)
+	fbo := ctx.createFramebuffer()
+	ctx.bindFramebuffer(glFramebuffer, fbo)
+	ctx.framebufferTexture2D(tex.id)
+
+	// preserve old content
+	if c.tex != nil {
+		cw, ch := c.tex.width, c.tex.height
+		if cw > w {
+			cw = w
+		}
+		if ch > h {
+			ch = h
+		}
+		ctx.bindFramebuffer(glReadFramebuffer, c.fbo)
+		ctx.blitFramebuffer(0, 0, cw, ch, 0, 0, cw, ch, glNearest)
+		ctx.deleteFramebuffer(c.fbo)
+		ctx.deleteTexture(c.tex.id)
+	}
+	ctx.unbindFramebuffer()
+
+	c.bounds = bounds
+	c.fbo = fbo
+	c.tex = tex
+	c.pixels = nil
+
+	if c.sprite == nil {
+		c.sprite = pixel.NewSprite(nil, pixel.Rect{})
+	}
+	c.sprite.Set(c, c.Bounds())
+}
+
+// Bounds returns the rectangular bounds of the Canvas.
+func (c *Canvas) Bounds() pixel.Rect {
+	return c.bounds
+}
+
+// SetSmooth sets whether stretched Pictures drawn onto this Canvas should be drawn smooth or
+// pixely.
+func (c *Canvas) SetSmooth(smooth bool) {
+	c.smooth = smooth
+}
+
+// Smooth returns whether stretched Pictures drawn onto this Canvas are set to be drawn smooth or
+// pixely.
+func (c *Canvas) Smooth() bool {
+	return c.smooth
+}
+
+// Clear fills the whole Canvas with a single color.
+func (c *Canvas) Clear(color color.Color) {
+	c.clear(c.bounds, color)
+}
+
+// ClearRect fills the rectangle of the Canvas with a single color. The rectangle is in the
+// coordinates of the Canvas's bounds, the Matrix set by SetMatrix doesn't apply to it.
+func (c *Canvas) ClearRect(r pixel.Rect, color color.Color) {
+	r = r.Norm().Intersect(c.bounds)
+	if r.Area() == 0 {
+		return
+	}
+	c.clear(r, color)
+}
+
+// clear fills the rectangle of the Canvas with the color
+func (c *Canvas) clear(r pixel.Rect, color color.Color) {
+	c.pixels = nil
+
+	// color masking
+	rgba := pixel.ToRGBA(color).Mul(pixel.RGBA{
+		R: float64(c.col[0]),
+		G: float64(c.col[1]),
+		B: float64(c.col[2]),
+		A: float64(c.col[3]),
+	})
+
+	ctx := c.ctx
+	ctx.bindFramebuffer(glFramebuffer, c.fbo)
+	ctx.enable(glScissorTest)
+	x0, y0, x1, y1 := framebufferRect(c.bounds, r)
+	ctx.scissor(x0, y0, x1-x0, y1-y0)
+	ctx.clearColor(float32(rgba.R), float32(rgba.G), float32(rgba.B), float32(rgba.A))
+	ctx.clear(glColorBufferBit)
+	ctx.disable(glScissorTest)
+	ctx.unbindFramebuffer()
+}
+
+// Color returns the color of the pixel over the given position inside the Canvas.
+//
+// The first call after the Canvas changes reads all of its pixels from the GPU, which waits for
+// the draws, so avoid calling it between draws every frame.
+func (c *Canvas) Color(at pixel.Vec) pixel.RGBA {
+	if c.pixels == nil {
+		c.pixels = c.Pixels()
+	}
+	return pixelColor(c.pixels, c.bounds, at)
+}
+
+// Texture returns the underlying OpenGL ES Texture of this Canvas.
+//
+// Implements GLPicture interface.
+func (c *Canvas) Texture() Texture {
+	return c.tex
+}
+
+// SetPixels replaces the content of the Canvas with the provided pixels. The provided slice must be
+// an alpha-premultiplied RGBA sequence of correct length (4 * width * height).
+func (c *Canvas) SetPixels(pixels []uint8) {
+	if len(pixels) != 4*c.tex.width*c.tex.height {
+		panic(fmt.Errorf("(%T).SetPixels: invalid pixels len", c))
+	}
+	c.pixels = nil
+
+	c.tex.Begin()
+	c.tex.SetPixels(0, 0, c.tex.width, c.tex.height, pixels)
+	c.tex.End()
+}
+
+// Pixels returns an alpha-premultiplied RGBA sequence of the content of the Canvas.
+func (c *Canvas) Pixels() []uint8 {
+	c.ctx.bindFramebuffer(glFramebuffer, c.fbo)
+	pixels := c.ctx.readPixels(0, 0, c.tex.width, c.tex.height)
+	c.ctx.unbindFramebuffer()
+	return pixels
+}
+
+// CopyTo copies the pixels inside the srcRect of this Canvas into the dstRect of the dst Canvas.
+// Both rectangles are in the coordinates of their respective Canvas's Bounds. See the desktop
+// Canvas.CopyTo.
+func (c *Canvas) CopyTo(dst *Canvas, srcRect, dstRect pixel.Rect) {
+	dst.pixels = nil
+
+	sx0, sy0, sx1, sy1 := framebufferRect(c.Bounds(), srcRect)
+	dx0, dy0, dx1, dy1 := framebufferRect(dst.Bounds(), dstRect)
+
+	filter := glNearest
+	if dst.smooth {
+		filter = glLinear
+	}
+
+	ctx := c.ctx
+	ctx.bindFramebuffer(glReadFramebuffer, c.fbo)
+	ctx.bindFramebuffer(glDrawFramebuffer, dst.fbo)
+	ctx.blitFramebuffer(sx0, sy0, sx1, sy1, dx0, dy0, dx1, dy1, filter)
+	ctx.unbindFramebuffer()
+}
+
+// Draw draws the content of the Canvas onto another Target, transformed by the given Matrix, just
+// like if it was a Sprite containing the whole Canvas.
+func (c *Canvas) Draw(t pixel.Target, matrix pixel.Matrix) {
+	c.sprite.Draw(t, matrix)
+}
+
+// DrawColorMask draws the content of the Canvas onto another Target, transformed by the given
+// Matrix and multiplied by the given mask, just like if it was a Sprite containing the whole Canvas.
+//
+// If the color mask is nil, a fully opaque white mask will be used causing no effect.
+func (c *Canvas) DrawColorMask(t pixel.Target, matrix pixel.Matrix, mask color.Color) {
+	c.sprite.DrawColorMask(t, matrix, mask)
+}
+
+// draw draws the triangles with the texture, whose Picture has the bounds
+func (c *Canvas) draw(gt *triangles, tex Texture, texBounds pixel.Rect) {
+	if gt.Len() == 0 {
+		return
+	}
+	c.pixels = nil
+
+	s := c.shader
+	s.uniformDefaults.transform = c.mat
+	s.uniformDefaults.colormask = c.col
+	s.uniformDefaults.bounds = mgl32.Vec4{
+		float32(c.bounds.Min.X),
+		float32(c.bounds.Min.Y),
+		float32(c.bounds.W()),
+		float32(c.bounds.H()),
+	}
+	bx, by, bw, bh := intBounds(texBounds)
+	s.uniformDefaults.texbounds = mgl32.Vec4{
+		float32(bx),
+		float32(by),
+		float32(bw),
+		float32(bh),
+	}
+
+	ctx := c.ctx
+	ctx.bindFramebuffer(glFramebuffer, c.fbo)
+	ctx.viewport(0, 0, c.tex.width, c.tex.height)
+	ctx.setBlendFunc(c.cmp)
+	s.begin()
+	if tex != nil {
+		tex.Begin()
+		if tex.Smooth() != c.smooth {
+			tex.SetSmooth(c.smooth)
+		}
+	}
+
+	ctx.bindVertexArray(gt.buf.vao)
+	ctx.drawArrays(gt.offset, gt.Len())
+	ctx.unbindVertexArray()
+	if tex != nil {
+		tex.End()
+	}
+	ctx.unbindFramebuffer()
+}
+
+// framebufferRect converts a rectangle in the coordinates of the bounds into integer framebuffer
+// coordinates, which have their origin at the bounds' Min.
+func framebufferRect(bounds, r pixel.Rect) (x0, y0, x1, y1 int) {
+	bx, by, _, _ := intBounds(bounds)
+	rx, ry, rw, rh := intBounds(r)
+	x0, y0 = rx-bx, ry-by
+	return x0, y0, x0 + rw, y0 + rh
+}
+
+type canvasTriangles struct {
+	*triangles
+	dst *Canvas
+}
+
+func (ct *canvasTriangles) Draw() {
+	ct.dst.draw(ct.triangles, nil, pixel.Rect{})
+}
+
+type canvasPicture struct {
+	GLPicture
+	dst *Canvas
+}
+
+func (cp *canvasPicture) Draw(t pixel.TargetTriangles) {
+	ct := t.(*canvasTriangles)
+	if cp.dst != ct.dst {
+		panic(fmt.Errorf("(%T).Draw: TargetTriangles generated by different Canvas", cp))
+	}
+	ct.dst.draw(ct.triangles, cp.GLPicture.Texture(), cp.GLPicture.Bounds())
+}
@@ -0,0 +1,174 @@
+//go:build purego && !js && !android && !ios
+// +build purego,!js,!android,!ios
+
+package pixelgl
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+	"github.com/pkg/errors"
+)
+
+// Canvas is an off-screen rectangular BasicTarget and Picture at the same time, that you can draw
+// onto. With the purego build tag, it's a raster.Canvas drawn on the CPU, without shaders.
+//
+// It supports TrianglesPosition, TrianglesColor, TrianglesPicture and PictureColor.
+type Canvas struct {
+	canvas *raster.Canvas
+
+	// col is the color mask, applied by ClearRect
+	col pixel.RGBA
+}
+
+var _ pixel.ComposeTarget = (*Canvas)(nil)
+
+// NewCanvas creates a new empty, fully transparent Canvas with given bounds.
+func NewCanvas(bounds pixel.Rect) *Canvas {
+	return &Canvas{
+		canvas: raster.NewCanvas(bounds),
+		col:    pixel.Alpha(1),
+	}
+}
+
+// SetUniform panics, there are no shaders without OpenGL.
+func (c *Canvas) SetUniform(name string, value interface{}) {
+	panic(fmt.Errorf("(%T).SetUniform: no shaders with the purego build tag", c))
+}
+
+// SetFragmentShader panics, there are no shaders without OpenGL.
+func (c *Canvas) SetFragmentShader(src string) {
+	panic(fmt.Errorf("(%T).SetFragmentShader: no shaders with the purego build tag", c))
+}
+
+// TrySetFragmentShader returns an error, there are no shaders without OpenGL. The Canvas keeps
+// drawing like with the default shader.
+func (c *Canvas) TrySetFragmentShader(src string) error {
+	return errors.New("failed to set fragment shader: no shaders with the purego build tag")
+}
+
+// MakeTriangles creates a specialized copy of the supplied Triangles that draws onto this Canvas.
+//
+// TrianglesPosition, TrianglesColor and TrianglesPicture are supported.
+func (c *Canvas) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	return c.canvas.MakeTriangles(t)
+}
+
+// MakePicture create a specialized copy of the supplied Picture that draws onto this Canvas.
+//
+// PictureColor is supported. Drawing another Canvas always uses its current content.
+func (c *Canvas) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	switch p := p.(type) {
+	case *Canvas:
+		return c.canvas.MakePicture(p.canvas)
+	case *picture:
+		return c.canvas.MakePicture(p.pd)
+	}
+	return c.canvas.MakePicture(p)
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (c *Canvas) SetMatrix(m pixel.Matrix) {
+	c.canvas.SetMatrix(m)
+}
+
+// SetColorMask sets a color that every color in triangles or a picture will be multiplied by.
+func (c *Canvas) SetColorMask(col color.Color) {
+	c.col = pixel.Alpha(1)
+	if col != nil {
+		c.col = pixel.ToRGBA(col)
+	}
+	c.canvas.SetColorMask(col)
+}
+
+// SetComposeMethod sets a Porter-Duff composition method to be used in the following draws onto
+// this Canvas.
+func (c *Canvas) SetComposeMethod(cmp pixel.ComposeMethod) {
+	c.canvas.SetComposeMethod(cmp)
+}
+
+// SetBounds resizes the Canvas to the new bounds. Old content will be preserved.
+func (c *Canvas) SetBounds(bounds pixel.Rect) {
+	c.canvas.SetBounds(bounds)
+}
+
+// Bounds returns the rectangular bounds of the Canvas.
+func (c *Canvas) Bounds() pixel.Rect {
+	return c.canvas.Bounds()
+}
+
+// SetSmooth sets whether stretched Pictures drawn onto this Canvas should be drawn smooth or
+// pixely.
+func (c *Canvas) SetSmooth(smooth bool) {
+	c.canvas.SetSmooth(smooth)
+}
+
+// Smooth returns whether stretched Pictures drawn onto this Canvas are set to be drawn smooth or
+// pixely.
+func (c *Canvas) Smooth() bool {
+	return c.canvas.Smooth()
+}
+
+// Clear fills the whole Canvas with a single color.
+func (c *Canvas) Clear(color color.Color) {
+	c.canvas.Clear(color)
+}
+
+// ClearRect fills the rectangle of the Canvas with a single color. The rectangle is in the
+// coordinates of the Canvas's bounds, the Matrix set by SetMatrix doesn't apply to it.
+func (c *Canvas) ClearRect(r pixel.Rect, col color.Color) {
+	bounds := c.Bounds()
+	r = r.Norm().Intersect(bounds)
+	if r.Area() == 0 {
+		return
+	}
+	rgba := pixel.ToRGBA(col).Mul(c.col)
+	px := [4]uint8{
+		uint8(rgba.R * 255),
+		uint8(rgba.G * 255),
+		uint8(rgba.B * 255),
+		uint8(rgba.A * 255),
+	}
+
+	bx, by, bw, _ := intBounds(bounds)
+	rx, ry, rw, rh := intBounds(r)
+	pixels := c.canvas.Pixels()
+	for y := ry - by; y < ry-by+rh; y++ {
+		for x := rx - bx; x < rx-bx+rw; x++ {
+			copy(pixels[(y*bw+x)*4:], px[:])
+		}
+	}
+	c.canvas.SetPixels(pixels)
+}
+
+// Color returns the color of the pixel over the given position inside the Canvas.
+func (c *Canvas) Color(at pixel.Vec) pixel.RGBA {
+	return c.canvas.Color(at)
+}
+
+// SetPixels replaces the content of the Canvas with the provided pixels. The provided slice must be
+// an alpha-premultiplied RGBA sequence of correct length (4 * width * height).
+func (c *Canvas) SetPixels(pixels []uint8) {
+	c.canvas.SetPixels(pixels)
+}
+
+// Pixels returns an alpha-premultiplied RGBA sequence of the content of the Canvas.
+func (c *Canvas) Pixels() []uint8 {
+	return c.canvas.Pixels()
+}
+
+// Draw draws the content of the Canvas onto another Target, transformed by the given Matrix, just
+// like if it was a Sprite containing the whole Canvas.
+func (c *Canvas) Draw(t pixel.Target, matrix pixel.Matrix) {
+	c.canvas.Draw(t, matrix)
+}
+
+// DrawColorMask draws the content of the Canvas onto another Target, transformed by the given
+// Matrix and multiplied by the given mask, just like if it was a Sprite containing the whole Canvas.
+//
+// If the color mask is nil, a fully opaque white mask will be used causing no effect.
+func (c *Canvas) DrawColorMask(t pixel.Target, matrix pixel.Matrix, mask color.Color) {
+	c.canvas.DrawColorMask(t, matrix, mask)
+}
@@ -0,0 +1,182 @@
+//go:build purego && !js && !android && !ios
+// +build purego,!js,!android,!ios
+
+package pixelgl
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelsw"
+	"github.com/stretchr/testify/assert"
+)
+
+// the Window passes the Buttons to pixelsw unconverted, their values must be the same
+func TestButton_PixelswValues(t *testing.T) {
+	for _, b := range []struct {
+		button Button
+		sw     pixelsw.Button
+	}{
+		{MouseButton1, pixelsw.MouseButton1},
+		{MouseButton2, pixelsw.MouseButton2},
+		{MouseButton3, pixelsw.MouseButton3},
+		{MouseButton4, pixelsw.MouseButton4},
+		{MouseButton5, pixelsw.MouseButton5},
+		{MouseButton6, pixelsw.MouseButton6},
+		{MouseButton7, pixelsw.MouseButton7},
+		{MouseButton8, pixelsw.MouseButton8},
+		{MouseButtonLast, pixelsw.MouseButtonLast},
+		{MouseButtonLeft, pixelsw.MouseButtonLeft},
+		{MouseButtonRight, pixelsw.MouseButtonRight},
+		{MouseButtonMiddle, pixelsw.MouseButtonMiddle},
+		{KeyUnknown, pixelsw.KeyUnknown},
+		{KeySpace, pixelsw.KeySpace},
+		{KeyApostrophe, pixelsw.KeyApostrophe},
+		{KeyComma, pixelsw.KeyComma},
+		{KeyMinus, pixelsw.KeyMinus},
+		{KeyPeriod, pixelsw.KeyPeriod},
+		{KeySlash, pixelsw.KeySlash},
+		{Key0, pixelsw.Key0},
+		{Key1, pixelsw.Key1},
+		{Key2, pixelsw.Key2},
+		{Key3, pixelsw.Key3},
+		{Key4, pixelsw.Key4},
+		{Key5, pixelsw.Key5},
+		{Key6, pixelsw.Key6},
+		{Key7, pixelsw.Key7},
+		{Key8, pixelsw.Key8},
+		{Key9, pixelsw.Key9},
+		{KeySemicolon, pixelsw.KeySemicolon},
+		{KeyEqual, pixelsw.KeyEqual},
+		{KeyA, pixelsw.KeyA},
+		{KeyB, pixelsw.KeyB},
+		{KeyC, pixelsw.KeyC},
+		{KeyD, pixelsw.KeyD},
+		{KeyE, pixelsw.KeyE},
+		{KeyF, pixelsw.KeyF},
+		{KeyG, pixelsw.KeyG},
+		{KeyH, pixelsw.KeyH},
+		{KeyI, pixelsw.KeyI},
+		{KeyJ, pixelsw.KeyJ},
+		{KeyK, pixelsw.KeyK},
+		{KeyL, pixelsw.KeyL},
+		{KeyM, pixelsw.KeyM},
+		{KeyN, pixelsw.KeyN},
+		{KeyO, pixelsw.KeyO},
+		{KeyP, pixelsw.KeyP},
+		{KeyQ, pixelsw.KeyQ},
+		{KeyR, pixelsw.KeyR},
+		{KeyS, pixelsw.KeyS},
+		{KeyT, pixelsw.KeyT},
+		{KeyU, pixelsw.KeyU},
+		{KeyV, pixelsw.KeyV},
+		{KeyW, pixelsw.KeyW},
+		{KeyX, pixelsw.KeyX},
+		{KeyY, pixelsw.KeyY},
+		{KeyZ, pixelsw.KeyZ},
+		{KeyLeftBracket, pixelsw.KeyLeftBracket},
+		{KeyBackslash, pixelsw.KeyBackslash},
+		{KeyRightBracket, pixelsw.KeyRightBracket},
+		{KeyGraveAccent, pixelsw.KeyGraveAccent},
+		{KeyWorld1, pixelsw.KeyWorld1},
+		{KeyWorld2, pixelsw.KeyWorld2},
+		{KeyEscape, pixelsw.KeyEscape},
+		{KeyEnter, pixelsw.KeyEnter},
+		{KeyTab, pixelsw.KeyTab},
+		{KeyBackspace, pixelsw.KeyBackspace},
+		{KeyInsert, pixelsw.KeyInsert},
+		{KeyDelete, pixelsw.KeyDelete},
+		{KeyRight, pixelsw.KeyRight},
+		{KeyLeft, pixelsw.KeyLeft},
+		{KeyDown, pixelsw.KeyDown},
+		{KeyUp, pixelsw.KeyUp},
+		{KeyPageUp, pixelsw.KeyPageUp},
+		{KeyPageDown, pixelsw.KeyPageDown},
+		{KeyHome, pixelsw.KeyHome},
+		{KeyEnd, pixelsw.KeyEnd},
+		{KeyCapsLock, pixelsw.KeyCapsLock},
+		{KeyScrollLock, pixelsw.KeyScrollLock},
+		{KeyNumLock, pixelsw.KeyNumLock},
+		{KeyPrintScreen, pixelsw.KeyPrintScreen},
+		{KeyPause, pixelsw.KeyPause},
+		{KeyF1, pixelsw.KeyF1},
+		{KeyF2, pixelsw.KeyF2},
+		{KeyF3, pixelsw.KeyF3},
+		{KeyF4, pixelsw.KeyF4},
+		{KeyF5, pixelsw.KeyF5},
+		{KeyF6, pixelsw.KeyF6},
+		{KeyF7, pixelsw.KeyF7},
+		{KeyF8, pixelsw.KeyF8},
+		{KeyF9, pixelsw.KeyF9},
+		{KeyF10, pixelsw.KeyF10},
+		{KeyF11, pixelsw.KeyF11},
+		{KeyF12, pixelsw.KeyF12},
+		{KeyF13, pixelsw.KeyF13},
+		{KeyF14, pixelsw.KeyF14},
+		{KeyF15, pixelsw.KeyF15},
+		{KeyF16, pixelsw.KeyF16},
+		{KeyF17, pixelsw.KeyF17},
+		{KeyF18, pixelsw.KeyF18},
+		{KeyF19, pixelsw.KeyF19},
+		{KeyF20, pixelsw.KeyF20},
+		{KeyF21, pixelsw.KeyF21},
+		{KeyF22, pixelsw.KeyF22},
+		{KeyF23, pixelsw.KeyF23},
+		{KeyF24, pixelsw.KeyF24},
+		{KeyF25, pixelsw.KeyF25},
+		{KeyKP0, pixelsw.KeyKP0},
+		{KeyKP1, pixelsw.KeyKP1},
+		{KeyKP2, pixelsw.KeyKP2},
+		{KeyKP3, pixelsw.KeyKP3},
+		{KeyKP4, pixelsw.KeyKP4},
+		{KeyKP5, pixelsw.KeyKP5},
+		{KeyKP6, pixelsw.KeyKP6},
+		{KeyKP7, pixelsw.KeyKP7},
+		{KeyKP8, pixelsw.KeyKP8},
+		{KeyKP9, pixelsw.KeyKP9},
+		{KeyKPDecimal, pixelsw.KeyKPDecimal},
+		{KeyKPDivide, pixelsw.KeyKPDivide},
+		{KeyKPMultiply, pixelsw.KeyKPMultiply},
+		{KeyKPSubtract, pixelsw.KeyKPSubtract},
+		{KeyKPAdd, pixelsw.KeyKPAdd},
+		{KeyKPEnter, pixelsw.KeyKPEnter},
+		{KeyKPEqual, pixelsw.KeyKPEqual},
+		{KeyLeftShift, pixelsw.KeyLeftShift},
+		{KeyLeftControl, pixelsw.KeyLeftControl},
+		{KeyLeftAlt, pixelsw.KeyLeftAlt},
+		{KeyLeftSuper, pixelsw.KeyLeftSuper},
+		{KeyRightShift, pixelsw.KeyRightShift},
+		{KeyRightControl, pixelsw.KeyRightControl},
+		{KeyRightAlt, pixelsw.KeyRightAlt},
+		{KeyRightSuper, pixelsw.KeyRightSuper},
+		{KeyMenu, pixelsw.KeyMenu},
+		{KeyLast, pixelsw.KeyLast},
+	} {
+		assert.Equal(t, int(b.sw), int(b.button), b.button.String())
+	}
+}
+
+func TestCanvas_ClearRect(t *testing.T) {
+	c := NewCanvas(pixel.R(0, 0, 4, 4))
+	c.SetColorMask(pixel.RGB(1, 0, 0))
+	c.ClearRect(pixel.R(2, 2, 8, 8), color.White)
+
+	assert.Equal(t, pixel.RGB(1, 0, 0), c.Color(pixel.V(3, 3)))
+	assert.Equal(t, pixel.Alpha(0), c.Color(pixel.V(1, 1)))
+}
+
+func TestNewGLPicture(t *testing.T) {
+	pd := pixel.MakePictureData(pixel.R(0, 0, 2, 2))
+	pd.Pix[pd.Index(pixel.V(1, 0))] = color.RGBA{G: 255, A: 255}
+
+	pic := NewGLPicture(pd)
+	assert.Equal(t, pd.Bounds(), pic.Bounds())
+	assert.Equal(t, []uint8{0, 255, 0, 255}, pic.Texture().Pixels(1, 0, 1, 1))
+
+	c := NewCanvas(pixel.R(0, 0, 2, 2))
+	pixel.NewSprite(pic, pic.Bounds()).Draw(c, pixel.IM.Moved(c.Bounds().Center()))
+	assert.Equal(t, pixel.RGB(0, 1, 0), c.Color(pixel.V(1.5, 0.5)))
+
+	assert.Error(t, c.TrySetFragmentShader("void main() {}"))
+}
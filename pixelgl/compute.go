@@ -0,0 +1,352 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/faiface/mainthread"
+	"github.com/go-gl/gl/v4.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pkg/errors"
+)
+
+// ComputeSupported returns whether compute shaders are available. Compute shaders require OpenGL
+// 4.3.
+//
+// A Window must be created before calling this function.
+func ComputeSupported() bool {
+	var supported bool
+	mainthread.Call(func() {
+		supported = initGL43()
+	})
+	return supported
+}
+
+// ComputeShader is an OpenGL compute shader program. It can read from and write to StorageBuffers
+// and to the textures of Canvases bound as images, which makes it possible to run simulations on
+// the GPU and draw their results with the usual Pixel rendering.
+//
+// Compute shaders require OpenGL 4.3, check ComputeSupported before using them.
+type ComputeShader struct {
+	program uint32
+
+	uniforms []gsUniformAttr
+	buffers  map[int]*StorageBuffer
+	images   map[int]computeImage
+}
+
+type computeImage struct {
+	pic    GLPicture
+	access ImageAccess
+}
+
+// ImageAccess specifies how a ComputeShader accesses an image bound to it.
+type ImageAccess int
+
+const (
+	// ReadOnly allows the ComputeShader only to read from the image.
+	ReadOnly ImageAccess = iota
+
+	// WriteOnly allows the ComputeShader only to write to the image.
+	WriteOnly
+
+	// ReadWrite allows the ComputeShader to read from and write to the image.
+	ReadWrite
+)
+
+func (ia ImageAccess) glEnum() uint32 {
+	switch ia {
+	case ReadOnly:
+		return gl.READ_ONLY
+	case WriteOnly:
+		return gl.WRITE_ONLY
+	case ReadWrite:
+		return gl.READ_WRITE
+	default:
+		panic(errors.New("ComputeShader: invalid image access"))
+	}
+}
+
+// NewComputeShader compiles and links a new ComputeShader from the provided GLSL source (not a
+// filename).
+//
+// Images bound with BindImage use the rgba8 format, so declare them in the shader like this:
+//
+//   layout(rgba8, binding = 0) uniform image2D uImage;
+func NewComputeShader(src string) (*ComputeShader, error) {
+	if !ComputeSupported() {
+		return nil, errors.New("compute shaders are not supported, OpenGL 4.3 required")
+	}
+
+	cs := &ComputeShader{
+		buffers: make(map[int]*StorageBuffer),
+		images:  make(map[int]computeImage),
+	}
+
+	err := mainthread.CallErr(func() error {
+		var err error
+		cs.program, err = compileCompute(src)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ComputeShader")
+	}
+
+	runtime.SetFinalizer(cs, (*ComputeShader).delete)
+
+	return cs, nil
+}
+
+// must be manually called inside mainthread
+func compileCompute(src string) (uint32, error) {
+	shader := gl.CreateShader(gl.COMPUTE_SHADER)
+	defer gl.DeleteShader(shader)
+
+	csrc, free := gl.Strs(src + "\x00")
+	gl.ShaderSource(shader, 1, csrc, nil)
+	free()
+	gl.CompileShader(shader)
+
+	var status int32
+	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
+	if status == gl.FALSE {
+		var logLen int32
+		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLen)
+		log := strings.Repeat("\x00", int(logLen+1))
+		gl.GetShaderInfoLog(shader, logLen, nil, gl.Str(log))
+		return 0, fmt.Errorf("error compiling compute shader: %s", log)
+	}
+
+	program := gl.CreateProgram()
+	gl.AttachShader(program, shader)
+	gl.LinkProgram(program)
+
+	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
+	if status == gl.FALSE {
+		var logLen int32
+		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLen)
+		log := strings.Repeat("\x00", int(logLen+1))
+		gl.GetProgramInfoLog(program, logLen, nil, gl.Str(log))
+		gl.DeleteProgram(program)
+		return 0, fmt.Errorf("error linking compute shader: %s", log)
+	}
+
+	return program, nil
+}
+
+func (cs *ComputeShader) delete() {
+	mainthread.CallNonBlock(func() {
+		gl.DeleteProgram(cs.program)
+	})
+}
+
+// SetUniform sets the named uniform to the value. The same value types as with Canvas.SetUniform
+// are supported. If the value is a pointer, it's dereferenced every time the ComputeShader is
+// dispatched.
+func (cs *ComputeShader) SetUniform(name string, value interface{}) {
+	t, p := getAttrType(value)
+	for i := range cs.uniforms {
+		if cs.uniforms[i].Name == name {
+			cs.uniforms[i].Type = t
+			cs.uniforms[i].ispointer = p
+			cs.uniforms[i].value = value
+			return
+		}
+	}
+	cs.uniforms = append(cs.uniforms, gsUniformAttr{
+		Name:      name,
+		Type:      t,
+		ispointer: p,
+		value:     value,
+	})
+}
+
+// BindBuffer binds the StorageBuffer to the shader storage block with the given binding index.
+//
+// Passing a nil StorageBuffer unbinds the index.
+func (cs *ComputeShader) BindBuffer(binding int, buf *StorageBuffer) {
+	if buf == nil {
+		delete(cs.buffers, binding)
+		return
+	}
+	cs.buffers[binding] = buf
+}
+
+// BindImage binds the texture of the GLPicture (such as a Canvas) to the image unit with the given
+// index.
+//
+// When writing to a Canvas's image, the Canvas's content is changed directly, without any matrix
+// or color mask applied. Passing a nil GLPicture unbinds the unit.
+func (cs *ComputeShader) BindImage(unit int, pic GLPicture, access ImageAccess) {
+	if pic == nil {
+		delete(cs.images, unit)
+		return
+	}
+	cs.images[unit] = computeImage{pic: pic, access: access}
+}
+
+// Dispatch runs the ComputeShader with the given number of work groups in each dimension. The
+// size of a work group is specified by the local_size layout qualifier in the shader source.
+//
+// Dispatch inserts a memory barrier after the compute work, so the results are visible to all
+// subsequent draws, buffer reads and image reads.
+func (cs *ComputeShader) Dispatch(x, y, z int) {
+	if x <= 0 || y <= 0 || z <= 0 {
+		return
+	}
+
+	// save the current state to avoid race condition
+	uniforms := make([]struct {
+		name  string
+		value interface{}
+	}, len(cs.uniforms))
+	for i := range cs.uniforms {
+		uniforms[i].name = cs.uniforms[i].Name
+		uniforms[i].value = cs.uniforms[i].Value()
+	}
+	buffers := make(map[int]*StorageBuffer, len(cs.buffers))
+	for binding, buf := range cs.buffers {
+		buffers[binding] = buf
+	}
+	images := make(map[int]computeImage, len(cs.images))
+	for unit, img := range cs.images {
+		images[unit] = img
+		if c, ok := img.pic.(*Canvas); ok && img.access != ReadOnly {
+			c.gf.Dirty()
+		}
+	}
+
+	flushDraws()
+	mainthread.CallNonBlock(debugWrap(func() {
+		gl.UseProgram(cs.program)
+
+		for _, u := range uniforms {
+			loc := gl.GetUniformLocation(cs.program, gl.Str(u.name+"\x00"))
+			setComputeUniform(loc, u.value)
+		}
+		for binding, buf := range buffers {
+			gl.BindBufferBase(gl.SHADER_STORAGE_BUFFER, uint32(binding), buf.id)
+		}
+		for unit, img := range images {
+			gl.BindImageTexture(uint32(unit), img.pic.Texture().ID(), 0, false, 0, img.access.glEnum(), gl.RGBA8)
+		}
+
+		gl.DispatchCompute(uint32(x), uint32(y), uint32(z))
+		gl.MemoryBarrier(gl.ALL_BARRIER_BITS)
+
+		gl.UseProgram(0)
+	}))
+}
+
+// must be manually called inside mainthread
+func setComputeUniform(loc int32, value interface{}) {
+	if loc < 0 {
+		return
+	}
+	switch value := value.(type) {
+	case int32:
+		gl.Uniform1i(loc, value)
+	case float32:
+		gl.Uniform1f(loc, value)
+	case mgl32.Vec2:
+		gl.Uniform2fv(loc, 1, &value[0])
+	case mgl32.Vec3:
+		gl.Uniform3fv(loc, 1, &value[0])
+	case mgl32.Vec4:
+		gl.Uniform4fv(loc, 1, &value[0])
+	case mgl32.Mat2:
+		gl.UniformMatrix2fv(loc, 1, false, &value[0])
+	case mgl32.Mat2x3:
+		gl.UniformMatrix2x3fv(loc, 1, false, &value[0])
+	case mgl32.Mat2x4:
+		gl.UniformMatrix2x4fv(loc, 1, false, &value[0])
+	case mgl32.Mat3:
+		gl.UniformMatrix3fv(loc, 1, false, &value[0])
+	case mgl32.Mat3x2:
+		gl.UniformMatrix3x2fv(loc, 1, false, &value[0])
+	case mgl32.Mat3x4:
+		gl.UniformMatrix3x4fv(loc, 1, false, &value[0])
+	case mgl32.Mat4:
+		gl.UniformMatrix4fv(loc, 1, false, &value[0])
+	case mgl32.Mat4x2:
+		gl.UniformMatrix4x2fv(loc, 1, false, &value[0])
+	case mgl32.Mat4x3:
+		gl.UniformMatrix4x3fv(loc, 1, false, &value[0])
+	default:
+		panic("invalid uniform value type")
+	}
+}
+
+// StorageBuffer is an OpenGL shader storage buffer (SSBO) of float32 values, which can be bound to
+// a ComputeShader.
+//
+// Use it to keep simulation state (such as particle positions) on the GPU. Read the results back
+// with Data to build Triangles from them, or have the ComputeShader write directly to a Canvas.
+type StorageBuffer struct {
+	id  uint32
+	len int
+	mem *gpuAlloc
+}
+
+// NewStorageBuffer creates a new StorageBuffer holding len float32 values initialized to zero.
+func NewStorageBuffer(len int) *StorageBuffer {
+	sb := &StorageBuffer{len: len}
+	data := make([]float32, len)
+	mainthread.Call(func() {
+		gl.GenBuffers(1, &sb.id)
+		gl.BindBuffer(gl.SHADER_STORAGE_BUFFER, sb.id)
+		gl.BufferData(gl.SHADER_STORAGE_BUFFER, 4*len, gl.Ptr(data), gl.DYNAMIC_COPY)
+		gl.BindBuffer(gl.SHADER_STORAGE_BUFFER, 0)
+	})
+	sb.mem = trackGPU(gpuBuffer, 4*int64(len))
+	runtime.SetFinalizer(sb, (*StorageBuffer).delete)
+	return sb
+}
+
+func (sb *StorageBuffer) delete() {
+	mainthread.CallNonBlock(func() {
+		gl.DeleteBuffers(1, &sb.id)
+	})
+}
+
+// Len returns the number of float32 values in the StorageBuffer.
+func (sb *StorageBuffer) Len() int {
+	return sb.len
+}
+
+// SetData replaces the content of the StorageBuffer starting at the offset (in float32 values)
+// with the provided data. The data must fit within the StorageBuffer.
+func (sb *StorageBuffer) SetData(offset int, data []float32) {
+	if offset < 0 || offset+len(data) > sb.len {
+		panic(fmt.Errorf("(%T).SetData: data out of range", sb))
+	}
+	if len(data) == 0 {
+		return
+	}
+	flushDraws()
+	mainthread.Call(func() {
+		gl.BindBuffer(gl.SHADER_STORAGE_BUFFER, sb.id)
+		gl.BufferSubData(gl.SHADER_STORAGE_BUFFER, 4*offset, 4*len(data), gl.Ptr(data))
+		gl.BindBuffer(gl.SHADER_STORAGE_BUFFER, 0)
+	})
+}
+
+// Data returns the whole content of the StorageBuffer. This waits for all dispatched compute work
+// to finish.
+func (sb *StorageBuffer) Data() []float32 {
+	data := make([]float32, sb.len)
+	if sb.len == 0 {
+		return data
+	}
+	flushDraws()
+	mainthread.Call(func() {
+		gl.BindBuffer(gl.SHADER_STORAGE_BUFFER, sb.id)
+		gl.GetBufferSubData(gl.SHADER_STORAGE_BUFFER, 0, 4*sb.len, gl.Ptr(data))
+		gl.BindBuffer(gl.SHADER_STORAGE_BUFFER, 0)
+	})
+	return data
+}
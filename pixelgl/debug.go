@@ -0,0 +1,438 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"unsafe"
+
+	"github.com/faiface/mainthread"
+	gl33 "github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/gl/v4.3-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// DebugSeverity is the severity of a DebugMessage.
+type DebugSeverity int
+
+// List of all debug message severities, from the least to the most severe.
+const (
+	DebugNotification DebugSeverity = iota
+	DebugLow
+	DebugMedium
+	DebugHigh
+)
+
+// String returns a human-readable representation of the DebugSeverity.
+func (ds DebugSeverity) String() string {
+	switch ds {
+	case DebugNotification:
+		return "notification"
+	case DebugLow:
+		return "low"
+	case DebugMedium:
+		return "medium"
+	case DebugHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// DebugMessage is an error, warning or other information reported by OpenGL when running with
+// debugging enabled (see WindowConfig.Debug).
+type DebugMessage struct {
+	Severity DebugSeverity
+
+	// Source and Type describe what produced the message, such as "API" and "error".
+	Source, Type string
+
+	// ID is an implementation specific identifier of the message.
+	ID uint32
+
+	// Message is the text of the message as reported by OpenGL.
+	Message string
+
+	// Caller is the position in the code outside of Pixel which issued the OpenGL work that
+	// produced the message. It's empty if the position is unknown.
+	Caller string
+}
+
+// Error returns a formatted representation of the DebugMessage.
+func (dm *DebugMessage) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "gl %s %s (%s): %s", dm.Source, dm.Type, dm.Severity, dm.Message)
+	if dm.Caller != "" {
+		fmt.Fprintf(&b, " [at %s]", dm.Caller)
+	}
+	return b.String()
+}
+
+var debug struct {
+	enabled     bool
+	callback    func(*DebugMessage)
+	logger      *log.Logger
+	minSeverity DebugSeverity
+	panicAt     DebugSeverity
+
+	// accessed only inside mainthread
+	khr    bool
+	caller string
+
+	// the first error at the panic severity reported by the callback of OpenGL, which must not
+	// panic through the driver, so debugWrap panics with it after the call
+	pending *DebugMessage
+}
+
+func init() {
+	debug.callback = logDebug
+	debug.minSeverity = DebugLow
+	debug.panicAt = DebugHigh + 1
+}
+
+func logDebug(dm *DebugMessage) {
+	if debug.logger != nil {
+		debug.logger.Println(dm)
+		return
+	}
+	log.Println(dm)
+}
+
+// SetDebugCallback sets the function called with every DebugMessage reported by OpenGL. The
+// function is called from the main thread, so it must not call any Pixel functions.
+//
+// Messages are only reported for Windows created with WindowConfig.Debug set, and only from the
+// severity set by SetDebugSeverity. The default callback logs the messages with the logger set by
+// SetDebugLogger. Passing nil restores the default callback.
+func SetDebugCallback(callback func(*DebugMessage)) {
+	if callback == nil {
+		callback = logDebug
+	}
+	debug.callback = callback
+}
+
+// SetDebugLogger sets the logger the default debug callback writes the messages to. Passing nil
+// restores the standard logger of the log package, which is the default.
+//
+//   pixelgl.SetDebugLogger(log.New(os.Stderr, "gl: ", log.Lmicroseconds))
+func SetDebugLogger(logger *log.Logger) {
+	debug.logger = logger
+}
+
+// SetDebugSeverity sets the least severe DebugMessages that are reported to the debug callback,
+// the less severe ones are dropped. It's DebugLow by default, so that the notifications, which
+// some drivers send for every buffer allocation, don't flood the log. Pass DebugNotification to
+// report all the messages, including the ones of DebugMarker.
+func SetDebugSeverity(severity DebugSeverity) {
+	debug.minSeverity = severity
+}
+
+// SetDebugPanic makes every OpenGL error (a DebugMessage of the "error" Type) of at least the given
+// severity panic after it's been passed to the debug callback. Warnings, such as performance or
+// deprecation messages, never panic. This is mostly useful in tests, where silently ignored OpenGL
+// errors are a bug. The panic is raised once the OpenGL calls which produced the message return,
+// never from inside the OpenGL driver.
+//
+//   pixelgl.SetDebugPanic(pixelgl.DebugHigh)
+func SetDebugPanic(severity DebugSeverity) {
+	debug.panicAt = severity
+}
+
+// enableDebug turns on debug output for the current OpenGL context. If the context supports
+// KHR_debug, messages are reported as they happen. Otherwise, glGetError is checked after every
+// call wrapped with debugWrap.
+//
+// must be manually called inside mainthread
+func enableDebug() {
+	debug.enabled = true
+
+	if !initKHRDebug() {
+		debug.khr = false
+		return
+	}
+	var flags int32
+	gl.GetIntegerv(gl.CONTEXT_FLAGS, &flags)
+	if flags&gl.CONTEXT_FLAG_DEBUG_BIT == 0 {
+		debug.khr = false
+		return
+	}
+	debug.khr = true
+
+	gl.Enable(gl.DEBUG_OUTPUT)
+	// synchronous output ensures the callback runs inside the offending call, so that the caller
+	// is known
+	gl.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS)
+	gl.DebugMessageControl(gl.DONT_CARE, gl.DONT_CARE, gl.DONT_CARE, 0, nil, true)
+	// debug groups are reported as messages too, but they're only noise in the callback
+	gl.DebugMessageControl(gl.DONT_CARE, gl.DEBUG_TYPE_PUSH_GROUP, gl.DONT_CARE, 0, nil, false)
+	gl.DebugMessageControl(gl.DONT_CARE, gl.DEBUG_TYPE_POP_GROUP, gl.DONT_CARE, 0, nil, false)
+	gl.DebugMessageCallback(func(
+		source, gltype, id, severity uint32,
+		length int32,
+		message string,
+		userParam unsafe.Pointer,
+	) {
+		// called from the driver's C code, a panic must not unwind through it
+		recordDebug(&DebugMessage{
+			Severity: debugSeverity(severity),
+			Source:   debugSource(source),
+			Type:     debugType(gltype),
+			ID:       id,
+			Message:  message,
+			Caller:   debug.caller,
+		})
+	}, nil)
+}
+
+// debugWrap wraps a function that will be executed inside mainthread, so that debug messages
+// produced by it carry the position of the code which issued it. If debugging is disabled, the
+// function is returned unchanged.
+func debugWrap(f func()) func() {
+	if !debug.enabled {
+		return f
+	}
+	caller := debugCaller()
+	return func() {
+		debug.caller = caller
+		f()
+		if !debug.khr {
+			for code := gl33.GetError(); code != gl33.NO_ERROR; code = gl33.GetError() {
+				recordDebug(&DebugMessage{
+					Severity: DebugHigh,
+					Source:   "API",
+					Type:     "error",
+					ID:       code,
+					Message:  glErrorString(code),
+					Caller:   caller,
+				})
+			}
+		}
+		debug.caller = ""
+		if dm := debug.pending; dm != nil {
+			debug.pending = nil
+			panic(dm)
+		}
+	}
+}
+
+// PushDebugGroup starts a named group of OpenGL commands. Frame debuggers, such as RenderDoc or
+// apitrace, show all commands issued until the matching PopDebugGroup nested under the name, which
+// makes captures much easier to navigate.
+//
+//   pixelgl.PushDebugGroup("lights")
+//   lights.Draw(win)
+//   pixelgl.PopDebugGroup()
+//
+// Groups require OpenGL 4.3 or the KHR_debug extension, without them they're silently ignored. A Window must be
+// created before calling this function.
+func PushDebugGroup(name string) {
+	flushDraws()
+	mainthread.CallNonBlock(func() {
+		pushDebugGroup(name)
+	})
+}
+
+// PopDebugGroup ends the group started by the last call to PushDebugGroup.
+func PopDebugGroup() {
+	flushDraws()
+	mainthread.CallNonBlock(func() {
+		popDebugGroup()
+	})
+}
+
+// DebugMarker inserts a message into the stream of OpenGL commands. Frame debuggers show it between
+// the commands issued before and after, and with WindowConfig.Debug it's also reported to the debug
+// callback as a notification, if SetDebugSeverity lets notifications through.
+//
+// Markers require OpenGL 4.3 or the KHR_debug extension, without them they're silently ignored.
+func DebugMarker(message string) {
+	flushDraws()
+	mainthread.CallNonBlock(func() {
+		if !initKHRDebug() {
+			return
+		}
+		gl.DebugMessageInsert(
+			gl.DEBUG_SOURCE_APPLICATION,
+			gl.DEBUG_TYPE_MARKER,
+			0,
+			gl.DEBUG_SEVERITY_NOTIFICATION,
+			-1,
+			gl.Str(message+"\x00"),
+		)
+	})
+}
+
+// must be manually called inside mainthread
+func pushDebugGroup(name string) {
+	if initKHRDebug() {
+		gl.PushDebugGroup(gl.DEBUG_SOURCE_APPLICATION, 0, -1, gl.Str(name+"\x00"))
+	}
+}
+
+// must be manually called inside mainthread
+func popDebugGroup() {
+	if initKHRDebug() {
+		gl.PopDebugGroup()
+	}
+}
+
+// labelObject sets the name of an OpenGL object shown by frame debuggers.
+//
+// must be manually called inside mainthread
+func labelObject(identifier, name uint32, label string) {
+	if initKHRDebug() {
+		gl.ObjectLabel(identifier, name, -1, gl.Str(label+"\x00"))
+	}
+}
+
+// khrDebugFunctions are the functions of KHR_debug used by Pixel
+var khrDebugFunctions = []string{
+	"glDebugMessageCallback", "glDebugMessageControl", "glDebugMessageInsert",
+	"glObjectLabel", "glPopDebugGroup", "glPushDebugGroup",
+}
+
+var khrDebug struct {
+	checked   bool
+	supported bool
+}
+
+// initKHRDebug loads the functions of KHR_debug and reports whether the current context has them,
+// either from OpenGL 4.3 or from the extension. On OpenGL ES, the functions of the extension have
+// the KHR suffix.
+//
+// must be manually called inside mainthread
+func initKHRDebug() bool {
+	if khrDebug.checked {
+		return khrDebug.supported
+	}
+	khrDebug.checked = true
+
+	if initGL43() {
+		khrDebug.supported = true
+		return true
+	}
+	if !glfw.ExtensionSupported("GL_KHR_debug") {
+		return false
+	}
+	// the functions of OpenGL 4.3 the context doesn't have are never called
+	missing := make(map[string]bool)
+	lookup := func(name string) unsafe.Pointer {
+		if p := glfw.GetProcAddress(name); p != nil {
+			return p
+		}
+		return glfw.GetProcAddress(name + "KHR")
+	}
+	if err := gl.InitWithProcAddrFunc(procLoader(lookup, missing)); err != nil {
+		return false
+	}
+	for _, name := range khrDebugFunctions {
+		if missing[name] {
+			return false
+		}
+	}
+	khrDebug.supported = true
+	return true
+}
+
+// recordDebug passes the message to the debug callback, unless it's filtered out by its severity,
+// and keeps it for debugWrap to panic with, if it's an error severe enough
+func recordDebug(dm *DebugMessage) {
+	if dm.Severity >= debug.minSeverity {
+		debug.callback(dm)
+	}
+	if dm.Type == "error" && dm.Severity >= debug.panicAt && debug.pending == nil {
+		debug.pending = dm
+	}
+}
+
+// debugCaller returns the position of the first function on the call stack, that isn't a part of
+// Pixel.
+func debugCaller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/faiface/pixel.") &&
+			!strings.HasPrefix(frame.Function, "github.com/faiface/pixel/") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+func debugSeverity(severity uint32) DebugSeverity {
+	switch severity {
+	case gl.DEBUG_SEVERITY_HIGH:
+		return DebugHigh
+	case gl.DEBUG_SEVERITY_MEDIUM:
+		return DebugMedium
+	case gl.DEBUG_SEVERITY_LOW:
+		return DebugLow
+	default:
+		return DebugNotification
+	}
+}
+
+func debugSource(source uint32) string {
+	switch source {
+	case gl.DEBUG_SOURCE_API:
+		return "API"
+	case gl.DEBUG_SOURCE_WINDOW_SYSTEM:
+		return "window system"
+	case gl.DEBUG_SOURCE_SHADER_COMPILER:
+		return "shader compiler"
+	case gl.DEBUG_SOURCE_THIRD_PARTY:
+		return "third party"
+	case gl.DEBUG_SOURCE_APPLICATION:
+		return "application"
+	default:
+		return "other"
+	}
+}
+
+func debugType(gltype uint32) string {
+	switch gltype {
+	case gl.DEBUG_TYPE_ERROR:
+		return "error"
+	case gl.DEBUG_TYPE_DEPRECATED_BEHAVIOR:
+		return "deprecated behavior"
+	case gl.DEBUG_TYPE_UNDEFINED_BEHAVIOR:
+		return "undefined behavior"
+	case gl.DEBUG_TYPE_PORTABILITY:
+		return "portability"
+	case gl.DEBUG_TYPE_PERFORMANCE:
+		return "performance"
+	case gl.DEBUG_TYPE_MARKER:
+		return "marker"
+	case gl.DEBUG_TYPE_PUSH_GROUP:
+		return "push group"
+	case gl.DEBUG_TYPE_POP_GROUP:
+		return "pop group"
+	default:
+		return "other"
+	}
+}
+
+func glErrorString(code uint32) string {
+	switch code {
+	case gl33.INVALID_ENUM:
+		return "invalid enum"
+	case gl33.INVALID_VALUE:
+		return "invalid value"
+	case gl33.INVALID_OPERATION:
+		return "invalid operation"
+	case gl33.INVALID_FRAMEBUFFER_OPERATION:
+		return "invalid framebuffer operation"
+	case gl33.OUT_OF_MEMORY:
+		return "out of memory"
+	default:
+		return fmt.Sprintf("error 0x%x", code)
+	}
+}
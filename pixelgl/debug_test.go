@@ -0,0 +1,55 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordDebug(t *testing.T) {
+	var got []*DebugMessage
+	SetDebugCallback(func(dm *DebugMessage) { got = append(got, dm) })
+	SetDebugPanic(DebugMedium)
+	defer func() {
+		SetDebugCallback(nil)
+		SetDebugSeverity(DebugLow)
+		SetDebugPanic(DebugHigh + 1)
+		debug.pending = nil
+	}()
+
+	note := &DebugMessage{Severity: DebugNotification, Type: "other"}
+	perf := &DebugMessage{Severity: DebugHigh, Type: "performance"}
+	low := &DebugMessage{Severity: DebugLow, Type: "error"}
+	high := &DebugMessage{Severity: DebugHigh, Type: "error"}
+
+	recordDebug(note)
+	assert.Empty(t, got, "notifications are filtered out by default")
+
+	recordDebug(perf)
+	assert.Equal(t, []*DebugMessage{perf}, got)
+	assert.Nil(t, debug.pending, "only errors panic")
+
+	recordDebug(low)
+	assert.Nil(t, debug.pending, "errors below the panic severity don't panic")
+
+	recordDebug(high)
+	assert.Equal(t, high, debug.pending)
+
+	SetDebugSeverity(DebugNotification)
+	recordDebug(note)
+	assert.Equal(t, []*DebugMessage{perf, low, high, note}, got)
+}
+
+func TestDebugLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetDebugLogger(log.New(&buf, "gl: ", 0))
+	defer SetDebugLogger(nil)
+
+	logDebug(&DebugMessage{Severity: DebugHigh, Source: "API", Type: "error", Message: "oops"})
+	assert.Equal(t, "gl: gl API error (high): oops\n", buf.String())
+}
@@ -0,0 +1,131 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"runtime"
+
+	"github.com/faiface/mainthread"
+	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+var _ pixel.DepthTarget = (*Canvas)(nil)
+
+// depthTest is the depth state of the draws onto a Canvas, the zero value has the test turned off
+type depthTest struct {
+	on, write bool
+
+	// z is the depth in normalized device coordinates, from -1 to 1
+	z float32
+}
+
+// set enables the depth test of the draws
+//
+// must be manually called inside mainthread
+func (dt depthTest) set() {
+	gl.Enable(gl.DEPTH_TEST)
+	gl.DepthFunc(gl.LESS)
+	gl.DepthMask(dt.write)
+}
+
+// unset restores the default state of the depth test
+//
+// must be manually called inside mainthread
+func (dt depthTest) unset() {
+	gl.DepthMask(true)
+	gl.Disable(gl.DEPTH_TEST)
+}
+
+// depthBuffer is the depth renderbuffer attached to the framebuffer of a GLFrame
+type depthBuffer struct {
+	id uint32
+}
+
+// newDepthBuffer creates a depth renderbuffer of the size and attaches it to the framebuffer
+//
+// must be manually called inside mainthread
+func newDepthBuffer(frame uint32, w, h int) *depthBuffer {
+	// OpenGL ES 2.0 has only 16 bit depth in the core API
+	format := uint32(gl.DEPTH_COMPONENT24)
+	if gles2 {
+		format = gl.DEPTH_COMPONENT16
+	}
+
+	db := &depthBuffer{}
+	gl.GenRenderbuffers(1, &db.id)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, db.id)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, format, int32(w), int32(h))
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, frame)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, db.id)
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	runtime.SetFinalizer(db, (*depthBuffer).delete)
+	return db
+}
+
+func (db *depthBuffer) delete() {
+	mainthread.CallNonBlock(func() {
+		gl.DeleteRenderbuffers(1, &db.id)
+	})
+}
+
+// attachDepth gives the GLFrame a depth buffer, unless it has one. The depth buffer is recreated
+// with the framebuffer by SetBounds, cleared.
+func (gf *GLFrame) attachDepth() {
+	if gf.depth != nil {
+		return
+	}
+	w, h := frameSize(gf.bounds)
+	mainthread.Call(debugWrap(func() {
+		gf.depth = newDepthBuffer(gf.frame.ID(), w, h)
+	}))
+	gf.mem.set(gf.frameBytes(w, h))
+}
+
+// SetDepth sets the depth of the following draws, from 0, the nearest, to 1, the farthest. The
+// draws only cover the pixels where nothing nearer was drawn, and write their depth if write is
+// true. A negative depth turns the depth test off, which is the default.
+//
+// The Canvas gets a depth buffer on the first call, which takes as much GPU memory as its
+// content. The depth is applied in the vertex shader, through the uDepth uniform.
+//
+// Implements pixel.DepthTarget interface.
+func (c *Canvas) SetDepth(depth float64, write bool) {
+	if depth < 0 {
+		c.depth = depthTest{}
+		return
+	}
+	c.gf.attachDepth()
+	c.depth = depthTest{on: true, write: write, z: float32(2*depth - 1)}
+}
+
+// ClearDepth sets the depth of all the pixels of the Canvas to the farthest.
+//
+// Implements pixel.DepthTarget interface.
+func (c *Canvas) ClearDepth() {
+	c.gf.attachDepth()
+	c.gf.flushDraws()
+	mainthread.CallNonBlock(debugWrap(func() {
+		c.setGlhfBounds()
+		c.gf.frame.Begin()
+		gl.Clear(gl.DEPTH_BUFFER_BIT)
+		c.gf.frame.End()
+	}))
+}
+
+// SetDepth sets the depth of the following draws onto the Window, see Canvas.SetDepth.
+func (w *Window) SetDepth(depth float64, write bool) {
+	w.canvas.SetDepth(depth, write)
+}
+
+// ClearDepth sets the depth of all the pixels of the Window to the farthest.
+func (w *Window) ClearDepth() {
+	w.canvas.ClearDepth()
+}
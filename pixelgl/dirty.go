@@ -0,0 +1,178 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"time"
+
+	"github.com/faiface/mainthread"
+	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// idleTimeout is the longest time Update waits for events when a Window in the dirty rendering
+// mode has nothing to redraw, so that the program's timers still advance once in a while.
+const idleTimeout = 100 * time.Millisecond
+
+// scissor is the part of a Canvas's framebuffer the draws are clipped to, if on.
+type scissor struct {
+	on         bool
+	x, y, w, h int32
+}
+
+// must be manually called inside mainthread
+func (s scissor) set() {
+	if s.on {
+		gl.Scissor(s.x, s.y, s.w, s.h)
+	}
+}
+
+// SetDirtyRendering sets whether the draws and clears onto this Canvas are clipped to its dirty
+// region, the union of the rectangles passed to Invalidate since the last ResetDirtyRegion. The
+// rest of the Canvas keeps its content. With nothing invalidated, draws and clears do nothing.
+//
+// Drawing only what changed saves a lot of GPU work in mostly static programs, such as tools,
+// editors or board games:
+//
+//   canvas.SetDirtyRendering(true)
+//   canvas.Invalidate(piece.Bounds())
+//   canvas.Clear(background) // clears only the piece's bounds
+//   board.Draw(canvas, pixel.IM) // and redraws the board there
+//
+// Large scenes can also skip what's outside of the dirty region with
+// pixel.NewCuller(matrix, canvas.DirtyRegion()). See also Window.SetDirtyRendering.
+func (c *Canvas) SetDirtyRendering(on bool) {
+	if on != c.dirtyMode {
+		flushDraws()
+	}
+	c.dirtyMode = on
+}
+
+// DirtyRendering returns whether this Canvas is in the dirty rendering mode, see
+// SetDirtyRendering.
+func (c *Canvas) DirtyRendering() bool {
+	return c.dirtyMode
+}
+
+// Invalidate adds the rectangle to the dirty region of this Canvas. The rectangle is in the
+// coordinates of the Canvas's bounds, the Matrix set by SetMatrix doesn't apply to it.
+func (c *Canvas) Invalidate(r pixel.Rect) {
+	r = r.Norm()
+	if r.Area() == 0 {
+		return
+	}
+	if c.dirty.Area() == 0 {
+		c.dirty = r
+		return
+	}
+	c.dirty = c.dirty.Union(r)
+}
+
+// DirtyRegion returns the bounding rectangle of the rectangles passed to Invalidate since the last
+// ResetDirtyRegion, clipped to the bounds of this Canvas. It's a zero Rect if nothing is dirty.
+func (c *Canvas) DirtyRegion() pixel.Rect {
+	r := c.dirty.Intersect(c.Bounds())
+	if r.Area() == 0 {
+		return pixel.Rect{}
+	}
+	return r
+}
+
+// ResetDirtyRegion empties the dirty region of this Canvas, usually after the frame is done.
+// Window.Update calls it on the Window's Canvas.
+func (c *Canvas) ResetDirtyRegion() {
+	c.dirty = pixel.Rect{}
+}
+
+// scissor returns the scissor of the following draws onto this Canvas, ok is false if they
+// would draw nothing.
+func (c *Canvas) scissor() (s scissor, ok bool) {
+	if !c.dirtyMode {
+		return scissor{}, true
+	}
+	r := c.DirtyRegion()
+	if r.Area() == 0 {
+		return scissor{}, false
+	}
+	x0, y0, x1, y1 := framebufferRect(c.Bounds(), r)
+	return scissor{on: true, x: x0, y: y0, w: x1 - x0, h: y1 - y0}, true
+}
+
+// scissorRect returns the scissor of drawing onto the rectangle of this Canvas, also clipped to
+// the dirty region in the dirty rendering mode. ok is false if nothing would be drawn.
+func (c *Canvas) scissorRect(r pixel.Rect) (s scissor, ok bool) {
+	r = r.Norm().Intersect(c.Bounds())
+	if c.dirtyMode {
+		r = r.Intersect(c.DirtyRegion())
+	}
+	if r.Area() == 0 {
+		return scissor{}, false
+	}
+	x0, y0, x1, y1 := framebufferRect(c.Bounds(), r)
+	return scissor{on: true, x: x0, y: y0, w: x1 - x0, h: y1 - y0}, true
+}
+
+// SetDirtyRendering sets the dirty rendering mode of the Window's Canvas, see
+// Canvas.SetDirtyRendering.
+//
+// In addition to clipping, Update redraws the Window only when something was invalidated during
+// the frame, the Window was resized or the system asked for a redraw. Otherwise Update only waits
+// for events, a bit at most, so that an idle program uses close to no CPU and GPU time. A resize
+// invalidates the whole Window.
+//
+//   win.SetDirtyRendering(true)
+//   for !win.Closed() {
+//       if win.JustPressed(pixelgl.MouseButtonLeft) {
+//           cell := board.CellAt(win.MousePosition())
+//           board.Toggle(cell)
+//           win.Invalidate(cell.Bounds())
+//       }
+//       win.Clear(colornames.White)
+//       board.Draw(win)
+//       win.Update()
+//   }
+func (w *Window) SetDirtyRendering(on bool) {
+	w.canvas.SetDirtyRendering(on)
+	// everything drawn in the normal mode is shown in the first frame of the dirty mode
+	w.canvas.Invalidate(w.canvas.Bounds())
+}
+
+// DirtyRendering returns whether the Window is in the dirty rendering mode, see
+// SetDirtyRendering.
+func (w *Window) DirtyRendering() bool {
+	return w.canvas.DirtyRendering()
+}
+
+// Invalidate adds the rectangle to the dirty region of the Window's Canvas, see
+// Canvas.Invalidate.
+func (w *Window) Invalidate(r pixel.Rect) {
+	w.canvas.Invalidate(r)
+}
+
+// DirtyRegion returns the dirty region of the Window's Canvas, see Canvas.DirtyRegion.
+func (w *Window) DirtyRegion() pixel.Rect {
+	return w.canvas.DirtyRegion()
+}
+
+// UpdateInputWait waits for window events for at most the timeout, a non-positive timeout waits
+// until an event arrives, and then updates the input like UpdateInput.
+//
+// Other goroutines can stop the waiting with PostEmptyEvent.
+func (w *Window) UpdateInputWait(timeout time.Duration) {
+	mainthread.Call(func() {
+		if timeout <= 0 {
+			glfw.WaitEvents()
+			return
+		}
+		glfw.WaitEventsTimeout(timeout.Seconds())
+	})
+	w.updateInput()
+}
+
+// PostEmptyEvent wakes up a Window waiting in UpdateInputWait or in Update of the dirty rendering
+// mode. It can be called from any goroutine.
+func PostEmptyEvent() {
+	glfw.PostEmptyEvent()
+}
@@ -0,0 +1,71 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+// dirtyCanvas returns a Canvas with the bounds, without an OpenGL framebuffer, which is enough
+// for the bookkeeping of the dirty region
+func dirtyCanvas(bounds pixel.Rect) *Canvas {
+	return &Canvas{gf: &GLFrame{bounds: bounds}}
+}
+
+func TestInvalidateUnion(t *testing.T) {
+	c := dirtyCanvas(pixel.R(0, 0, 100, 100))
+	assert.Equal(t, pixel.Rect{}, c.DirtyRegion())
+
+	c.Invalidate(pixel.R(30, 40, 10, 20))
+	assert.Equal(t, pixel.R(10, 20, 30, 40), c.DirtyRegion(), "the rectangles are normalized")
+
+	c.Invalidate(pixel.R(50, 5, 60, 10))
+	assert.Equal(t, pixel.R(10, 5, 60, 40), c.DirtyRegion())
+
+	c.Invalidate(pixel.R(70, 70, 70, 90))
+	assert.Equal(t, pixel.R(10, 5, 60, 40), c.DirtyRegion(), "empty rectangles are ignored")
+
+	c.ResetDirtyRegion()
+	assert.Equal(t, pixel.Rect{}, c.DirtyRegion())
+}
+
+func TestDirtyRegionClipping(t *testing.T) {
+	c := dirtyCanvas(pixel.R(-50, -50, 50, 50))
+
+	c.Invalidate(pixel.R(-80, 20, -10, 90))
+	assert.Equal(t, pixel.R(-50, 20, -10, 50), c.DirtyRegion())
+
+	c.ResetDirtyRegion()
+	c.Invalidate(pixel.R(60, 60, 70, 70))
+	assert.Equal(t, pixel.Rect{}, c.DirtyRegion(), "nothing is dirty outside of the bounds")
+}
+
+func TestScissor(t *testing.T) {
+	c := dirtyCanvas(pixel.R(-50, -50, 50, 50))
+
+	s, ok := c.scissor()
+	assert.True(t, ok)
+	assert.False(t, s.on, "no scissor outside of the dirty rendering mode")
+
+	c.dirtyMode = true
+	_, ok = c.scissor()
+	assert.False(t, ok, "nothing is drawn without a dirty region")
+
+	// the scissor is in the pixels of the framebuffer, whose origin is the Min of the bounds, and
+	// covers partial pixels
+	c.Invalidate(pixel.R(-10.5, 0, 10, 20.25))
+	s, ok = c.scissor()
+	assert.True(t, ok)
+	assert.Equal(t, scissor{on: true, x: 39, y: 50, w: 21, h: 21}, s)
+
+	s, ok = c.scissorRect(pixel.R(0, 10, 100, 100))
+	assert.True(t, ok)
+	assert.Equal(t, scissor{on: true, x: 50, y: 60, w: 10, h: 11}, s, "clipped to the dirty region")
+
+	_, ok = c.scissorRect(pixel.R(20, 30, 40, 40))
+	assert.False(t, ok)
+}
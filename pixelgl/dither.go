@@ -0,0 +1,124 @@
+package pixelgl
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/faiface/pixel"
+)
+
+// DitherFragmentShader returns a fragment shader for a Canvas, which draws the same way as the
+// default one, but dithers all colors drawn by the Dither. The pattern of the Dither is aligned to
+// the pixels of the Canvas.
+//
+// The dithering is usually the last pass: everything is drawn onto a Canvas in the full colors
+// first, which is then drawn onto the dithering Canvas. Translucent colors still blend with what is
+// underneath after the dithering, which introduces colors out of the Palette.
+//
+//   dithered := pixelgl.NewCanvas(scene.Bounds())
+//   dithered.SetFragmentShader(pixelgl.DitherFragmentShader(pixel.Dither{Levels: 4}))
+//
+//   scene.Draw(dithered, pixel.IM.Moved(dithered.Bounds().Center()))
+//   dithered.Draw(win, pixel.IM.Scaled(pixel.ZV, 4).Moved(win.Bounds().Center()))
+func DitherFragmentShader(d pixel.Dither) string {
+	m := d.Matrix()
+	n := int(math.Sqrt(float64(len(m))))
+
+	var matrix []string
+	for _, v := range m {
+		matrix = append(matrix, fmt.Sprintf("%f", v))
+	}
+
+	// GLSL doesn't allow empty arrays, the palette has at least one color then
+	palette := []string{"vec3(0.0)"}
+	if len(d.Palette) > 0 {
+		palette = palette[:0]
+		for _, c := range d.Palette {
+			p := color.NRGBAModel.Convert(c).(color.NRGBA)
+			palette = append(palette, fmt.Sprintf("vec3(%f, %f, %f)",
+				float64(p.R)/0xff, float64(p.G)/0xff, float64(p.B)/0xff))
+		}
+	}
+
+	levels := d.Levels - 1
+	if levels < 1 {
+		levels = 1
+	}
+
+	return fmt.Sprintf(ditherFragmentShader,
+		n, n*n, strings.Join(matrix, ", "),
+		float64(levels),
+		len(d.Palette), len(palette), strings.Join(palette, ", "),
+		d.Spread(),
+	)
+}
+
+const ditherFragmentShader = `
+#version 330 core
+
+in vec4  vColor;
+in vec2  vTexCoords;
+in float vIntensity;
+
+out vec4 fragColor;
+
+uniform vec4 uColorMask;
+uniform vec4 uTexBounds;
+uniform sampler2D uTexture;
+uniform int uLinear;
+
+const int matrixSize = %d;
+const float matrix[%d] = float[](%s);
+const float levels = %f;
+const int paletteSize = %d;
+const vec3 palette[%d] = vec3[](%s);
+const float spread = %f;
+
+vec4 toLinear(vec4 c) {
+	if (c.a == 0.0) {
+		return c;
+	}
+	vec3 s = c.rgb / c.a;
+	vec3 l = mix(s / 12.92, pow((s + 0.055) / 1.055, vec3(2.4)), step(0.04045, s));
+	return vec4(l * c.a, c.a);
+}
+
+vec3 dither(vec3 c, float threshold) {
+	if (paletteSize == 0) {
+		return min(floor(c * levels + threshold), levels) / levels;
+	}
+	c += (threshold - 0.5) * spread;
+	vec3 best = palette[0];
+	for (int i = 1; i < paletteSize; i++) {
+		vec3 d = palette[i] - c;
+		vec3 b = best - c;
+		if (dot(d, d) < dot(b, b)) {
+			best = palette[i];
+		}
+	}
+	return best;
+}
+
+void main() {
+	if (vIntensity == 0.0) {
+		fragColor = vColor;
+	} else {
+		fragColor = vec4(0.0, 0.0, 0.0, 0.0);
+		fragColor += (1.0 - vIntensity) * vColor;
+		vec2 t = (vTexCoords - uTexBounds.xy) / uTexBounds.zw;
+		fragColor += vIntensity * vColor * texture(uTexture, t);
+	}
+	fragColor *= uColorMask;
+
+	if (fragColor.a > 0.0) {
+		ivec2 p = ivec2(floor(gl_FragCoord.xy)) %% matrixSize;
+		float threshold = matrix[p.y * matrixSize + p.x];
+		fragColor.rgb = dither(fragColor.rgb / fragColor.a, threshold) * fragColor.a;
+	}
+	if (uLinear != 0) {
+		fragColor = toLinear(fragColor);
+	}
+}
+`
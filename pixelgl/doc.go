@@ -2,4 +2,40 @@
 // library, specifically Window and Canvas.
 //
 // It also contains a few additional utilities to help extend Pixel with OpenGL graphical effects.
+//
+// The textures and the framebuffers of the Pictures and the Targets are handed out as the Texture
+// and the Framebuffer interfaces, which glhf implements. Code drawing them, such as GLPicture
+// implementations from other packages, doesn't need to import glhf.
+//
+// The package also builds for web browsers, with GOOS=js GOARCH=wasm:
+//
+//   GOOS=js GOARCH=wasm go build -o game.wasm
+//
+// The same code runs there. The Window draws into an HTML canvas element by WebGL2, takes the input
+// from the DOM events of the page and the joysticks from the Gamepad API. The shaders, including
+// the custom ones set by SetFragmentShader, are written in GLSL 3.30 like on the desktop and
+// translated to GLSL ES 3.00. A page has one Window, and the Canvases and the Pictures can only be
+// created after it. The OpenGL utilities without a WebGL2 counterpart, such as the compute
+// shaders, the GPU timers and the debug output, are only on the desktop. The game is loaded by
+// wasm_exec.js of the Go distribution, see web/index.html.
+//
+// On Android and iOS, the package builds by gomobile (golang.org/x/mobile):
+//
+//   gomobile build -target=android
+//
+// The Window is the screen of the app, drawn by OpenGL ES 3.0, which the phone must support. The
+// Canvases, the Pictures and the shaders are the ones of the browser, so the same limits apply, and
+// the matrix uniforms which aren't square are ignored, gomobile has no calls for them. Run starts
+// the app and calls the run function once the screen is ready. The first finger touching the
+// screen drives the mouse, so the games made for the mouse work there too, and Touches returns all
+// of them. The phones have no joysticks.
+//
+// With the build tag purego, the package builds without cgo and forwards to pixelsw:
+//
+//   CGO_ENABLED=0 go build -tags purego
+//
+// Run and NewWindow open a pixelsw.Window, and the Canvases are raster.Canvases drawn on the CPU.
+// There are no shaders, so SetFragmentShader panics and TrySetFragmentShader returns an error, and
+// no monitors, no joysticks and no OpenGL utilities. On macOS, which pixelsw has no backend for,
+// NewWindow returns an error.
 package pixelgl
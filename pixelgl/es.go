@@ -0,0 +1,242 @@
+//go:build js || android || ios
+// +build js android ios
+
+package pixelgl
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/faiface/pixel"
+	"github.com/pkg/errors"
+)
+
+// In the browser and on the phones, the Canvases, the Pictures and the shaders draw by OpenGL ES
+// 3.0, which is WebGL2 in the browser (webgl_js.go) and the context of gomobile on the phones
+// (mobilegl.go). Both implement the same methods of context on their objects.
+
+// the OpenGL ES constants used by the package
+const (
+	glTriangles          = 0x0004
+	glOne                = 1
+	glZero               = 0
+	glSrcAlpha           = 0x0302
+	glOneMinusSrcAlpha   = 0x0303
+	glDstAlpha           = 0x0304
+	glOneMinusDstAlpha   = 0x0305
+	glDstColor           = 0x0306
+	glBlend              = 0x0BE2
+	glScissorTest        = 0x0C11
+	glUnpackAlignment    = 0x0CF5
+	glPackAlignment      = 0x0D05
+	glTexture2D          = 0x0DE1
+	glUnsignedByte       = 0x1401
+	glFloat              = 0x1406
+	glRGBA               = 0x1908
+	glNearest            = 0x2600
+	glLinear             = 0x2601
+	glTextureMagFilter   = 0x2800
+	glTextureMinFilter   = 0x2801
+	glTextureWrapS       = 0x2802
+	glTextureWrapT       = 0x2803
+	glColorBufferBit     = 0x4000
+	glTexture0           = 0x84C0
+	glClampToEdge        = 0x812F
+	glArrayBuffer        = 0x8892
+	glDynamicDraw        = 0x88E8
+	glFragmentShader     = 0x8B30
+	glVertexShader       = 0x8B31
+	glCompileStatus      = 0x8B81
+	glLinkStatus         = 0x8B82
+	glRGBA8              = 0x8058
+	glFramebufferBinding = 0x8CA6
+	glReadFramebuffer    = 0x8CA8
+	glDrawFramebuffer    = 0x8CA9
+	glColorAttachment0   = 0x8CE0
+	glFramebuffer        = 0x8D40
+)
+
+// the attributes of a vertex, at the locations bound to the names of the vertex shader
+const (
+	canvasPosition = iota
+	canvasColor
+	canvasTexCoords
+	canvasIntensity
+
+	// vertexStride is the number of floats of a vertex
+	vertexStride = 9
+)
+
+var canvasAttributes = [...]string{
+	canvasPosition:  "aPosition",
+	canvasColor:     "aColor",
+	canvasTexCoords: "aTexCoords",
+	canvasIntensity: "aIntensity",
+}
+
+// glctx is the context of the Window, nil until it's created
+var glctx *context
+
+// mustContext returns the context of the Window, the Canvases and the Pictures can't be created
+// before it
+func mustContext(caller string) *context {
+	if glctx == nil {
+		panic(fmt.Errorf("pixelgl.%s: called before NewWindow", caller))
+	}
+	return glctx
+}
+
+// floatBytes returns the memory of the floats as bytes, without copying
+func floatBytes(data []float32) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	n := 4 * len(data)
+	return (*[1 << 30]byte)(unsafe.Pointer(&data[0]))[:n:n]
+}
+
+// setBlendFunc sets the blending of the ComposeMethod
+func (c *context) setBlendFunc(cmp pixel.ComposeMethod) {
+	var src, dst int
+	switch cmp {
+	case pixel.ComposeOver:
+		src, dst = glOne, glOneMinusSrcAlpha
+	case pixel.ComposeIn:
+		src, dst = glDstAlpha, glZero
+	case pixel.ComposeOut:
+		src, dst = glOneMinusDstAlpha, glZero
+	case pixel.ComposeAtop:
+		src, dst = glDstAlpha, glOneMinusSrcAlpha
+	case pixel.ComposeRover:
+		src, dst = glOneMinusDstAlpha, glOne
+	case pixel.ComposeRin:
+		src, dst = glZero, glSrcAlpha
+	case pixel.ComposeRout:
+		src, dst = glZero, glOneMinusSrcAlpha
+	case pixel.ComposeRatop:
+		src, dst = glOneMinusDstAlpha, glSrcAlpha
+	case pixel.ComposeXor:
+		src, dst = glOneMinusDstAlpha, glOneMinusSrcAlpha
+	case pixel.ComposePlus:
+		src, dst = glOne, glOne
+	case pixel.ComposeCopy:
+		src, dst = glOne, glZero
+	case pixel.ComposeMultiply:
+		src, dst = glDstColor, glOneMinusSrcAlpha
+	default:
+		panic(errors.New("no such ComposeMethod"))
+	}
+	c.blendFunc(src, dst)
+}
+
+// Texture is an OpenGL ES texture with alpha-premultiplied RGBA pixels, the bottom row first. The
+// Pictures and the Targets of the package hand out their textures as this interface, like on the
+// desktop.
+//
+// WebGL textures have no numeric names, so ID is a number the package gives each texture. The
+// SetSmooth and the SetPixels methods are only valid between Begin and End.
+type Texture interface {
+	ID() uint32
+	Width() int
+	Height() int
+	Smooth() bool
+	SetSmooth(smooth bool)
+	Pixels(x, y, w, h int) []uint8
+	SetPixels(x, y, w, h int, pixels []uint8)
+	Begin()
+	End()
+}
+
+// texture is the Texture of a context
+type texture struct {
+	ctx           *context
+	id            glTex
+	name          uint32
+	width, height int
+	smooth        bool
+}
+
+var _ Texture = (*texture)(nil)
+
+// newTexture creates a texture with the pixels, or transparent if they're nil
+func (c *context) newTexture(width, height int, pixels []uint8) *texture {
+	c.names++
+	t := &texture{
+		ctx:    c,
+		id:     c.createTexture(),
+		name:   c.names,
+		width:  width,
+		height: height,
+	}
+	c.bindTexture(t.id)
+	c.texImage2D(width, height, pixels)
+	c.texParameteri(glTextureMinFilter, glNearest)
+	c.texParameteri(glTextureMagFilter, glNearest)
+	c.texParameteri(glTextureWrapS, glClampToEdge)
+	c.texParameteri(glTextureWrapT, glClampToEdge)
+	c.unbindTexture()
+	return t
+}
+
+// ID returns the number the package gave the texture.
+func (t *texture) ID() uint32 {
+	return t.name
+}
+
+// Width returns the width of the texture in pixels.
+func (t *texture) Width() int {
+	return t.width
+}
+
+// Height returns the height of the texture in pixels.
+func (t *texture) Height() int {
+	return t.height
+}
+
+// Smooth returns whether the texture is filtered linearly.
+func (t *texture) Smooth() bool {
+	return t.smooth
+}
+
+// SetSmooth sets whether the texture is filtered linearly or by the nearest pixel.
+func (t *texture) SetSmooth(smooth bool) {
+	t.smooth = smooth
+	filter := glNearest
+	if smooth {
+		filter = glLinear
+	}
+	t.ctx.texParameteri(glTextureMinFilter, filter)
+	t.ctx.texParameteri(glTextureMagFilter, filter)
+}
+
+// Pixels returns the RGBA bytes of the rectangle of the texture, read through a temporary
+// framebuffer.
+func (t *texture) Pixels(x, y, w, h int) []uint8 {
+	c := t.ctx
+	fbo := c.createFramebuffer()
+	c.bindFramebuffer(glFramebuffer, fbo)
+	c.framebufferTexture2D(t.id)
+	pixels := c.readPixels(x, y, w, h)
+	c.unbindFramebuffer()
+	c.deleteFramebuffer(fbo)
+	return pixels
+}
+
+// SetPixels replaces the RGBA bytes of the rectangle of the texture.
+func (t *texture) SetPixels(x, y, w, h int, pixels []uint8) {
+	if len(pixels) != w*h*4 {
+		panic(fmt.Errorf("(%T).SetPixels: invalid pixels len", t))
+	}
+	t.ctx.texSubImage2D(x, y, w, h, pixels)
+}
+
+// Begin binds the texture to the texture unit 0.
+func (t *texture) Begin() {
+	t.ctx.activeTexture(glTexture0)
+	t.ctx.bindTexture(t.id)
+}
+
+// End unbinds the texture.
+func (t *texture) End() {
+	t.ctx.unbindTexture()
+}
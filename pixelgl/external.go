@@ -0,0 +1,111 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"fmt"
+
+	"github.com/faiface/mainthread"
+	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/pkg/errors"
+)
+
+// ExternalPicture is a GLPicture showing the content of an OpenGL texture created outside of Pixel,
+// e.g. by a video decoder, libmpv or other OpenGL code running in the same process.
+//
+// The external texture is drawn directly, its pixels are never copied, so the ExternalPicture
+// always shows its current content. The external texture must be a GL_TEXTURE_2D with
+// alpha-premultiplied RGBA pixels, the bottom row first, and it must be created in the OpenGL
+// context of a Window, or in a context sharing objects with it. The external code owns the
+// texture: it must keep it alive while the ExternalPicture is drawn, and delete it afterwards.
+//
+// The draws are merged and issued later (see Flush), so call Update before the external code
+// renders a new frame into the texture, otherwise the draws made so far may show the new frame.
+// The smoothing of a Canvas drawing the ExternalPicture changes the filtering of the texture.
+//
+// To show an EGLImage or a DMABUF, import it into a texture with the EGL extensions of your
+// platform first and wrap the texture.
+type ExternalPicture struct {
+	tex    *externalTexture
+	bounds pixel.Rect
+
+	// the pixels read by Color, nil after Update
+	pixels []uint8
+}
+
+var _ GLPicture = (*ExternalPicture)(nil)
+
+// NewExternalPicture creates an ExternalPicture showing the OpenGL texture with the given ID. The
+// bounds must have the size of the texture, the texture's bottom-left pixel is placed at their Min.
+//
+// An error is returned if the ID isn't a texture.
+func NewExternalPicture(texture uint32, bounds pixel.Rect) (*ExternalPicture, error) {
+	_, _, w, h := intBounds(bounds)
+	ep := &ExternalPicture{
+		tex:    &externalTexture{id: texture, width: w, height: h},
+		bounds: bounds,
+	}
+
+	err := mainthread.CallErr(func() error {
+		if !gl.IsTexture(texture) {
+			return fmt.Errorf("%d is not a texture", texture)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ExternalPicture")
+	}
+	return ep, nil
+}
+
+// Update marks the content of the external texture as changed. Call it before the external code
+// renders a new frame into the texture.
+//
+// It issues the draws of the ExternalPicture made so far, so that they show the previous frame.
+// The external code is responsible for finishing its rendering (e.g. with a fence) before the
+// ExternalPicture is drawn again if it runs in a different context.
+func (ep *ExternalPicture) Update() {
+	flushDraws()
+	ep.pixels = nil
+}
+
+// ID returns the OpenGL ID of the external texture.
+func (ep *ExternalPicture) ID() uint32 {
+	return ep.tex.id
+}
+
+// Bounds returns the bounds of the ExternalPicture.
+func (ep *ExternalPicture) Bounds() pixel.Rect {
+	return ep.bounds
+}
+
+// Color returns the color of the pixel of the external texture under the specified position.
+// This downloads the whole content from the GPU after each Update, so avoid it if possible.
+func (ep *ExternalPicture) Color(at pixel.Vec) pixel.RGBA {
+	if !ep.bounds.Contains(at) {
+		return pixel.Alpha(0)
+	}
+	if ep.pixels == nil {
+		mainthread.Call(debugWrap(func() {
+			ep.pixels = ep.tex.Pixels(0, 0, ep.tex.width, ep.tex.height)
+		}))
+	}
+	bx, by, bw, _ := intBounds(ep.bounds)
+	x, y := int(at.X)-bx, int(at.Y)-by
+	off := y*bw + x
+	return pixel.RGBA{
+		R: float64(ep.pixels[off*4+0]) / 255,
+		G: float64(ep.pixels[off*4+1]) / 255,
+		B: float64(ep.pixels[off*4+2]) / 255,
+		A: float64(ep.pixels[off*4+3]) / 255,
+	}
+}
+
+// Texture returns the external texture, wrapped as a Texture.
+//
+// Implements GLPicture interface.
+func (ep *ExternalPicture) Texture() Texture {
+	return ep.tex
+}
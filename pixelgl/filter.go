@@ -0,0 +1,99 @@
+package pixelgl
+
+import (
+	"fmt"
+
+	"github.com/faiface/pixel"
+)
+
+// ColorMatrixFragmentShader returns a fragment shader for a Canvas, which draws the same way as
+// the default one, but transforms all colors drawn by the ColorMatrix in linear RGB. It has the
+// same effect as the ColorMatrix's Apply on each pixel.
+//
+// Set on the Canvas that everything is drawn onto, it works as a post-process filter. The usual
+// use is simulating a color blindness to check how the game looks with it, or correcting the
+// colors for it (see pixel.ColorBlindness):
+//
+//   filtered := pixelgl.NewCanvas(win.Bounds())
+//   filtered.SetFragmentShader(pixelgl.ColorMatrixFragmentShader(pixel.Deuteranopia.Simulation()))
+//
+//   scene.Draw(filtered, pixel.IM.Moved(filtered.Bounds().Center()))
+//   filtered.Draw(win, pixel.IM.Moved(win.Bounds().Center()))
+//
+// Drawing the whole frame onto a separate Canvas first applies the ColorMatrix once per pixel,
+// after the blending, which is exact. The filter can also be set directly on the Window's Canvas
+// (see Window.Canvas), but the color of Clear is not filtered then.
+func ColorMatrixFragmentShader(m pixel.ColorMatrix) string {
+	// GLSL matrices are constructed by the columns
+	return fmt.Sprintf(colorMatrixFragmentShader,
+		m[0], m[3], m[6],
+		m[1], m[4], m[7],
+		m[2], m[5], m[8],
+	)
+}
+
+const colorMatrixFragmentShader = `
+#version 330 core
+
+in vec4  vColor;
+in vec2  vTexCoords;
+in float vIntensity;
+
+out vec4 fragColor;
+
+uniform vec4 uColorMask;
+uniform vec4 uTexBounds;
+uniform sampler2D uTexture;
+uniform int uLinear;
+uniform int uTexLinear;
+
+const mat3 colorMatrix = mat3(
+	%f, %f, %f,
+	%f, %f, %f,
+	%f, %f, %f
+);
+
+// converts between the premultiplied sRGB and linear RGB
+vec4 toLinear(vec4 c) {
+	if (c.a == 0.0) {
+		return c;
+	}
+	vec3 s = c.rgb / c.a;
+	vec3 l = mix(s / 12.92, pow((s + 0.055) / 1.055, vec3(2.4)), step(0.04045, s));
+	return vec4(l * c.a, c.a);
+}
+
+vec4 toSRGB(vec4 c) {
+	if (c.a == 0.0) {
+		return c;
+	}
+	vec3 l = c.rgb / c.a;
+	vec3 s = mix(l * 12.92, 1.055 * pow(l, vec3(1.0 / 2.4)) - 0.055, step(0.0031308, l));
+	return vec4(s * c.a, c.a);
+}
+
+void main() {
+	// the colors are transformed in linear RGB on any Canvas
+	vec4 color = toLinear(vColor);
+	vec4 mask = toLinear(uColorMask);
+
+	if (vIntensity == 0.0) {
+		fragColor = mask * color;
+	} else {
+		fragColor = vec4(0.0, 0.0, 0.0, 0.0);
+		fragColor += (1.0 - vIntensity) * color;
+		vec2 t = (vTexCoords - uTexBounds.xy) / uTexBounds.zw;
+		vec4 tex = texture(uTexture, t);
+		if (uTexLinear == 0) {
+			tex = toLinear(tex);
+		}
+		fragColor += vIntensity * color * tex;
+		fragColor *= mask;
+	}
+
+	fragColor.rgb = clamp(colorMatrix * fragColor.rgb, 0.0, fragColor.a);
+	if (uLinear == 0) {
+		fragColor = toSRGB(fragColor);
+	}
+}
+`
@@ -0,0 +1,104 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFinalizersDontBlock checks that the finalizers of the package, which run on the goroutine of
+// the garbage collector, only post their OpenGL work with mainthread.CallNonBlock and never wait
+// for mainthread or call OpenGL directly.
+func TestFinalizersDontBlock(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	methods := make(map[string]*ast.FuncDecl)
+	var finalizers []string
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv != nil {
+				methods[recvName(fd.Recv.List[0].Type)+"."+fd.Name.Name] = fd
+			}
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || pkgCall(call) != "runtime.SetFinalizer" || len(call.Args) != 2 {
+				return true
+			}
+			// runtime.SetFinalizer(x, (*T).method)
+			if sel, ok := call.Args[1].(*ast.SelectorExpr); ok {
+				finalizers = append(finalizers, recvName(sel.X)+"."+sel.Sel.Name)
+			}
+			return true
+		})
+	}
+	assert.NotEmpty(t, finalizers)
+
+	for _, name := range finalizers {
+		fd, ok := methods[name]
+		if !assert.True(t, ok, "finalizer %s not found", name) {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			switch fn := pkgCall(call); {
+			case fn == "mainthread.CallNonBlock":
+				// the posted function runs inside mainthread
+				return false
+			case strings.HasPrefix(fn, "mainthread."):
+				t.Errorf("finalizer %s waits for mainthread with %s (%s)", name, fn, fset.Position(call.Pos()))
+			case strings.HasPrefix(fn, "gl.") || strings.HasPrefix(fn, "gl33.") ||
+				strings.HasPrefix(fn, "glhf.") || strings.HasPrefix(fn, "glfw."):
+				t.Errorf("finalizer %s calls %s outside of mainthread (%s)", name, fn, fset.Position(call.Pos()))
+			}
+			return true
+		})
+	}
+}
+
+// recvName returns the name of the type T of a receiver or a method expression, T, *T or (*T)
+func recvName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return recvName(e.X)
+	case *ast.StarExpr:
+		return recvName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// pkgCall returns the pkg.Func name of a call of a package function, or "" for other calls
+func pkgCall(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return pkg.Name + "." + sel.Sel.Name
+}
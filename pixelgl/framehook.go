@@ -0,0 +1,18 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+// SetFrameHook sets the function called by Window.Update with the texture of each frame of the
+// Window, after the frame is drawn. Setting nil removes it.
+//
+// The function is called on the main thread, with the OpenGL context of the Window current. The
+// texture is a GL_TEXTURE_2D with the content of the Window, its bottom row first and its colors
+// premultiplied by the alpha. Its content changes with the next frame and it's recreated when the
+// Window is resized, so the function must copy it if it needs it after the call.
+//
+// A Window has one frame hook. FrameSender uses it to share the frames with other applications
+// by Spout, Syphon or PipeWire.
+func (w *Window) SetFrameHook(hook func(texture uint32, width, height int)) {
+	w.frameHook = hook
+}
@@ -0,0 +1,67 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"github.com/faiface/mainthread"
+	"github.com/pkg/errors"
+)
+
+// FrameSender shares the frames of a Window with other applications, such as OBS or VJ tools,
+// without capturing the screen. It sends the texture of each frame by the platform API:
+//
+//   Windows: Spout, with the build tag spout, linking SpoutLibrary
+//   macOS:   Syphon, with the build tag syphon, linking Syphon.framework
+//   Linux:   PipeWire, with the build tag pipewire, linking libpipewire-0.3
+//
+// The SDKs aren't installed with the systems, so the senders are opt-in, like the Wayland backend.
+// Without the build tag, NewFrameSender returns an error.
+//
+// Spout and Syphon share the texture itself on the GPU. PipeWire gets a copy of the pixels, read
+// back on the CPU.
+//
+// A FrameSender sets the frame hook of the Window, see SetFrameHook.
+type FrameSender struct {
+	win    *Window
+	sender frameSender
+}
+
+// frameSender is the platform API sending the frames. The methods are called inside mainthread,
+// with the OpenGL context of the Window current.
+type frameSender interface {
+	send(texture uint32, width, height int)
+	close()
+}
+
+// NewFrameSender creates a FrameSender sending the frames of the Window under the given name,
+// which the other applications list it by.
+func NewFrameSender(win *Window, name string) (*FrameSender, error) {
+	s := &FrameSender{win: win}
+	err := mainthread.CallErr(func() error {
+		win.begin()
+		defer win.end()
+		_, _, width, height := intBounds(win.Bounds())
+		sender, err := newFrameSender(name, width, height)
+		if err != nil {
+			return err
+		}
+		s.sender = sender
+		win.SetFrameHook(sender.send)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating frame sender failed")
+	}
+	return s, nil
+}
+
+// Close stops sending the frames and removes the frame hook of the Window.
+func (s *FrameSender) Close() {
+	mainthread.Call(func() {
+		s.win.begin()
+		defer s.win.end()
+		s.win.SetFrameHook(nil)
+		s.sender.close()
+	})
+}
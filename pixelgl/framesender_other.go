@@ -0,0 +1,14 @@
+//go:build !js && !android && !ios && !purego && !(windows && spout) && !(darwin && syphon) && !(linux && pipewire)
+// +build !js,!android,!ios
+// +build !purego
+// +build !windows !spout
+// +build !darwin !syphon
+// +build !linux !pipewire
+
+package pixelgl
+
+import "github.com/pkg/errors"
+
+func newFrameSender(name string, width, height int) (frameSender, error) {
+	return nil, errors.New("no frame sharing, build with the tag spout on Windows, syphon on macOS or pipewire on Linux")
+}
@@ -0,0 +1,19 @@
+//go:build !js && !android && !ios && !purego && !(windows && spout) && !(darwin && syphon) && !(linux && pipewire)
+// +build !js,!android,!ios
+// +build !purego
+// +build !windows !spout
+// +build !darwin !syphon
+// +build !linux !pipewire
+
+package pixelgl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFrameSender_untagged(t *testing.T) {
+	_, err := newFrameSender("pixel", 64, 48)
+	assert.Error(t, err, "the senders need their build tags")
+}
@@ -0,0 +1,200 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"regexp"
+	"unsafe"
+
+	"github.com/faiface/glhf"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/pkg/errors"
+)
+
+// gles is true if the OpenGL context (shared by all Windows) is an OpenGL ES context, either
+// 3.0 or 2.0 (see gles2).
+//
+// accessed only inside mainthread
+var gles bool
+
+// gles2 is true if the OpenGL ES context is only 2.0, as requested by WindowConfig.GLES2.
+//
+// accessed only inside mainthread
+var gles2 bool
+
+// canBlit is false if the OpenGL context can't blit framebuffers, which OpenGL ES 2.0 does only
+// with the GL_NV_framebuffer_blit extension. blitFramebuffer draws a textured quad instead.
+//
+// accessed only inside mainthread
+var canBlit = true
+
+// gles2Aliases are the names of the OpenGL ES 2.0 extension functions providing the OpenGL 3.3
+// functions used by Pixel and glhf.
+var gles2Aliases = map[string]string{
+	"glGenVertexArrays":    "glGenVertexArraysOES",
+	"glBindVertexArray":    "glBindVertexArrayOES",
+	"glDeleteVertexArrays": "glDeleteVertexArraysOES",
+	"glIsVertexArray":      "glIsVertexArrayOES",
+	"glBlitFramebuffer":    "glBlitFramebufferNV",
+}
+
+// glesFunctions are the functions Pixel and glhf call on OpenGL ES, which the context must have.
+// The other functions of desktop OpenGL 3.3 are allowed to be missing.
+var glesFunctions = []string{
+	"glActiveTexture", "glAttachShader", "glBindBuffer", "glBindFramebuffer", "glBindRenderbuffer",
+	"glBindTexture", "glBindVertexArray", "glBlendEquation", "glBlendFunc", "glBufferData",
+	"glBufferSubData", "glClear", "glClearColor", "glCompileShader", "glCreateProgram",
+	"glCreateShader", "glDeleteBuffers", "glDeleteFramebuffers", "glDeleteProgram",
+	"glDeleteRenderbuffers", "glDeleteShader", "glDeleteTextures", "glDeleteVertexArrays",
+	"glDepthFunc", "glDepthMask", "glDisable", "glDrawArrays", "glEnable",
+	"glEnableVertexAttribArray", "glFramebufferRenderbuffer", "glFramebufferTexture2D",
+	"glGenBuffers", "glGenFramebuffers", "glGenRenderbuffers", "glGenTextures",
+	"glGenVertexArrays", "glGetAttribLocation", "glGetError", "glGetIntegerv",
+	"glGetProgramInfoLog", "glGetProgramiv", "glGetShaderInfoLog", "glGetShaderiv",
+	"glGetUniformLocation", "glLinkProgram", "glPixelStorei", "glReadPixels",
+	"glRenderbufferStorage", "glScissor", "glShaderSource", "glTexImage2D", "glTexParameteri",
+	"glTexSubImage2D", "glUniform1f", "glUniform1i", "glUniform2f", "glUniform3f", "glUniform4f",
+	"glUniformMatrix3fv", "glUniformMatrix4fv", "glUseProgram", "glVertexAttribPointer",
+	"glViewport",
+}
+
+// initGLES does the same initialization as glhf.Init, but for an OpenGL ES context.
+//
+// glhf.Init loads OpenGL functions using the platform's desktop OpenGL loader, which doesn't know
+// about EGL contexts (such as the ones created by ANGLE). The functions are loaded through GLFW
+// instead, which queries the API the context was actually created with. OpenGL ES drivers don't
+// have the functions only in desktop OpenGL (e.g. ANGLE has none of them), so only glesFunctions
+// are required.
+//
+// On OpenGL ES 2.0 (es2 is true), the functions missing from the core API are loaded from the
+// extensions providing them. Vertex array objects, which glhf draws every VertexSlice with, are
+// required (GL_OES_vertex_array_object), framebuffer blits are used only if available.
+//
+// must be manually called inside mainthread
+func initGLES(es2 bool) error {
+	getProcAddress := glfw.GetProcAddress
+	if es2 {
+		if !glfw.ExtensionSupported("GL_OES_vertex_array_object") {
+			return errors.New("OpenGL ES 2.0 context doesn't support GL_OES_vertex_array_object")
+		}
+		getProcAddress = func(name string) unsafe.Pointer {
+			if alias, ok := gles2Aliases[name]; ok {
+				if p := glfw.GetProcAddress(alias); p != nil {
+					return p
+				}
+			}
+			return glfw.GetProcAddress(name)
+		}
+	}
+	missing := make(map[string]bool)
+	if err := gl.InitWithProcAddrFunc(procLoader(getProcAddress, missing)); err != nil {
+		return errors.Wrap(err, "failed to load OpenGL ES functions")
+	}
+	for _, name := range glesFunctions {
+		if missing[name] {
+			return errors.Errorf("failed to load OpenGL ES functions: %s is missing", name)
+		}
+	}
+	canBlit = !es2 || glfw.ExtensionSupported("GL_NV_framebuffer_blit")
+	gl.Enable(gl.BLEND)
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.BlendEquation(gl.FUNC_ADD)
+	return nil
+}
+
+// shaderSource adapts a GLSL source for the current OpenGL context. On OpenGL ES, the #version
+// directive is replaced with GLSL ES 3.00 and a default float precision is declared, which GLSL ES
+// requires in fragment shaders. Otherwise the source is returned unchanged.
+//
+// On OpenGL ES 2.0, the source is translated to GLSL ES 1.00 instead, see glsl100.
+//
+// Note, that GLSL ES is stricter than desktop GLSL, e.g. ints are never implicitly converted to
+// floats, so write custom shaders using float literals (2.0 instead of 2).
+//
+// must be manually called inside mainthread
+func shaderSource(src string, fragment bool) string {
+	if !gles {
+		return src
+	}
+	if gles2 {
+		return glsl100(src, fragment)
+	}
+	return glsl300es(src)
+}
+
+// glsl100Header declares the highest float precision available in fragment shaders, many OpenGL ES
+// 2.0 GPUs only have a medium one.
+const glsl100Header = `#version 100
+#ifdef GL_FRAGMENT_PRECISION_HIGH
+precision highp float;
+#else
+precision mediump float;
+#endif
+`
+
+var (
+	glslVersion = regexp.MustCompile(`(?m)^[ \t]*#version.*$`)
+	glslLayout  = regexp.MustCompile(`layout\s*\([^)]*\)\s*`)
+	glslIn      = regexp.MustCompile(`(?m)^([ \t]*)in\s`)
+	glslOut     = regexp.MustCompile(`(?m)^([ \t]*)out\s`)
+	glslFragOut = regexp.MustCompile(`(?m)^[ \t]*out\s+\w+\s+(\w+)\s*;.*$`)
+	glslTexture = regexp.MustCompile(`\btexture\s*\(`)
+)
+
+// glsl100 translates a GLSL 3.30 shader into GLSL ES 1.00, the only version of OpenGL ES 2.0.
+//
+// The translation covers the shaders of Pixel and the custom shaders written in the same subset:
+// the in and out variables become attributes and varyings, the output of the fragment shader
+// becomes gl_FragColor and texture becomes texture2D. Shaders using the features GLSL ES 1.00
+// doesn't have (e.g. array constructors, integer operators, texelFetch) don't compile, such as
+// DitherFragmentShader and PaletteFragmentShader.
+func glsl100(src string, fragment bool) string {
+	src = glslVersion.ReplaceAllString(src, "")
+	src = glslLayout.ReplaceAllString(src, "")
+	if fragment {
+		if m := glslFragOut.FindStringSubmatch(src); m != nil {
+			src = glslFragOut.ReplaceAllString(src, "")
+			name := regexp.MustCompile(`\b` + regexp.QuoteMeta(m[1]) + `\b`)
+			src = name.ReplaceAllString(src, "gl_FragColor")
+		}
+		src = glslIn.ReplaceAllString(src, "${1}varying ")
+	} else {
+		src = glslIn.ReplaceAllString(src, "${1}attribute ")
+		src = glslOut.ReplaceAllString(src, "${1}varying ")
+	}
+	src = glslTexture.ReplaceAllString(src, "texture2D(")
+	return glsl100Header + src
+}
+
+// framePixels returns the content of the Frame as an alpha-premultiplied RGBA sequence.
+//
+// OpenGL ES can't read the pixels of a texture directly, so they're read from the Frame's
+// framebuffer instead.
+//
+// must be manually called inside mainthread
+func framePixels(frame *glhf.Frame) []uint8 {
+	tex := frame.Texture()
+	if !gles {
+		tex.Begin()
+		pixels := tex.Pixels(0, 0, tex.Width(), tex.Height())
+		tex.End()
+		return pixels
+	}
+
+	pixels := make([]uint8, 4*tex.Width()*tex.Height())
+	if len(pixels) == 0 {
+		return pixels
+	}
+	frame.Begin()
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(
+		0, 0,
+		int32(tex.Width()), int32(tex.Height()),
+		gl.RGBA, gl.UNSIGNED_BYTE,
+		gl.Ptr(pixels),
+	)
+	frame.End()
+	return pixels
+}
@@ -1,18 +1,28 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
 package pixelgl
 
 import (
 	"github.com/faiface/glhf"
 	"github.com/faiface/mainthread"
 	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v3.3-core/gl"
 )
 
 // GLFrame is a type that helps implementing OpenGL Targets. It implements most common methods to
-// avoid code redundancy. It contains an glhf.Frame that you can draw on.
+// avoid code redundancy. It contains a Framebuffer that you can draw on.
 type GLFrame struct {
 	frame  *glhf.Frame
 	bounds pixel.Rect
 	pixels []uint8
 	dirty  bool
+	linear bool
+	mem    *gpuAlloc
+	depth  *depthBuffer
+
+	// batch holds the merged draws onto the GLFrame that haven't been issued yet, see mergeDraw
+	batch *drawBatch
 }
 
 // NewGLFrame creates a new GLFrame with the given bounds.
@@ -24,38 +34,57 @@ func NewGLFrame(bounds pixel.Rect) *GLFrame {
 
 // SetBounds resizes the GLFrame to the new bounds.
 func (gf *GLFrame) SetBounds(bounds pixel.Rect) {
+	flushDraws()
+
 	if bounds == gf.Bounds() {
 		return
 	}
 
-	mainthread.Call(func() {
-		oldF := gf.frame
+	w, h := frameSize(bounds)
 
-		_, _, w, h := intBounds(bounds)
-		if w <= 0 {
-			w = 1
+	mainthread.Call(debugWrap(func() {
+		oldF := gf.frame
+		gf.frame = glhf.NewFrame(w, h, false)
+		if gf.linear {
+			setTextureFormat(gf.frame.Texture(), true, nil)
 		}
-		if h <= 0 {
-			h = 1
+		if gf.depth != nil {
+			gf.depth = newDepthBuffer(gf.frame.ID(), w, h)
 		}
-		gf.frame = glhf.NewFrame(w, h, false)
 
 		// preserve old content
 		if oldF != nil {
 			ox, oy, ow, oh := intBounds(bounds)
-			oldF.Blit(
-				gf.frame,
-				ox, oy, ox+ow, oy+oh,
-				ox, oy, ox+ow, oy+oh,
+			blitFramebuffer(
+				oldF.ID(), oldF.Texture(), gf.frame.ID(),
+				int32(ox), int32(oy), int32(ox+ow), int32(oy+oh),
+				int32(ox), int32(oy), int32(ox+ow), int32(oy+oh),
+				gl.NEAREST,
 			)
 		}
-	})
+	}))
+
+	if gf.mem == nil {
+		gf.mem = trackGPU(gpuFrame, gf.frameBytes(w, h))
+	} else {
+		gf.mem.set(gf.frameBytes(w, h))
+	}
 
 	gf.bounds = bounds
 	gf.pixels = nil
 	gf.dirty = true
 }
 
+// frameBytes returns the size of the GLFrame's framebuffer of the size, with its depth buffer
+func (gf *GLFrame) frameBytes(w, h int) int64 {
+	bytes := textureBytes(w, h)
+	if gf.depth != nil {
+		// 24 bit depth is stored in 32 bits
+		bytes += textureBytes(w, h)
+	}
+	return bytes
+}
+
 // Bounds returns the current GLFrame's bounds.
 func (gf *GLFrame) Bounds() pixel.Rect {
 	return gf.bounds
@@ -64,11 +93,9 @@ func (gf *GLFrame) Bounds() pixel.Rect {
 // Color returns the color of the pixel under the specified position.
 func (gf *GLFrame) Color(at pixel.Vec) pixel.RGBA {
 	if gf.dirty {
+		gf.flushDraws()
 		mainthread.Call(func() {
-			tex := gf.frame.Texture()
-			tex.Begin()
-			gf.pixels = tex.Pixels(0, 0, tex.Width(), tex.Height())
-			tex.End()
+			gf.pixels = framePixels(gf.frame)
 		})
 		gf.dirty = false
 	}
@@ -86,20 +113,20 @@ func (gf *GLFrame) Color(at pixel.Vec) pixel.RGBA {
 	}
 }
 
-// Frame returns the GLFrame's Frame that you can draw on.
-func (gf *GLFrame) Frame() *glhf.Frame {
-	return gf.frame
+// Frame returns the GLFrame's Framebuffer that you can draw on.
+func (gf *GLFrame) Frame() Framebuffer {
+	return glFramebuffer{gf.frame}
 }
 
-// Texture returns the underlying Texture of the GLFrame's Frame.
+// Texture returns the underlying Texture of the GLFrame's Framebuffer.
 //
 // Implements GLPicture interface.
-func (gf *GLFrame) Texture() *glhf.Texture {
+func (gf *GLFrame) Texture() Texture {
 	return gf.frame.Texture()
 }
 
 // Dirty marks the GLFrame as changed. Always call this method when you draw onto the GLFrame's
-// Frame.
+// Framebuffer.
 func (gf *GLFrame) Dirty() {
 	gf.dirty = true
 }
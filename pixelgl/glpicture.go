@@ -1,3 +1,6 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
 package pixelgl
 
 import (
@@ -15,12 +18,34 @@ import (
 // that Target onto them.
 type GLPicture interface {
 	pixel.PictureColor
-	Texture() *glhf.Texture
+	Texture() Texture
 }
 
 // NewGLPicture creates a new GLPicture with it's own static OpenGL texture. This function always
 // allocates a new texture that cannot (shouldn't) be further modified.
 func NewGLPicture(p pixel.Picture) GLPicture {
+	bounds := p.Bounds()
+	_, _, bw, bh := intBounds(bounds)
+	pixels := picturePixels(p)
+
+	var tex *glhf.Texture
+	mainthread.Call(debugWrap(func() {
+		defer beginPhase(pixel.PhaseUpload).End()
+		tex = glhf.NewTexture(bw, bh, false, pixels)
+	}))
+
+	gp := &glPicture{
+		bounds: bounds,
+		tex:    tex,
+		pixels: pixels,
+		mem:    trackGPU(gpuPicture, textureBytes(bw, bh)),
+	}
+	return gp
+}
+
+// picturePixels returns the RGBA bytes of the Picture's pixels within its bounds, the bottom row
+// first, as uploaded to a texture.
+func picturePixels(p pixel.Picture) []uint8 {
 	bounds := p.Bounds()
 	bx, by, bw, bh := intBounds(bounds)
 
@@ -54,31 +79,21 @@ func NewGLPicture(p pixel.Picture) GLPicture {
 			}
 		}
 	}
-
-	var tex *glhf.Texture
-	mainthread.Call(func() {
-		tex = glhf.NewTexture(bw, bh, false, pixels)
-	})
-
-	gp := &glPicture{
-		bounds: bounds,
-		tex:    tex,
-		pixels: pixels,
-	}
-	return gp
+	return pixels
 }
 
 type glPicture struct {
 	bounds pixel.Rect
 	tex    *glhf.Texture
 	pixels []uint8
+	mem    *gpuAlloc
 }
 
 func (gp *glPicture) Bounds() pixel.Rect {
 	return gp.bounds
 }
 
-func (gp *glPicture) Texture() *glhf.Texture {
+func (gp *glPicture) Texture() Texture {
 	return gp.tex
 }
 
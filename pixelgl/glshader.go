@@ -1,8 +1,12 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
 package pixelgl
 
 import (
 	"github.com/faiface/glhf"
 	"github.com/faiface/mainthread"
+	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/pkg/errors"
 )
@@ -16,13 +20,23 @@ type glShader struct {
 	vs, fs string
 
 	uniforms []gsUniformAttr
+	textures []gsTexture
 
 	uniformDefaults struct {
 		transform mgl32.Mat3
 		colormask mgl32.Vec4
 		bounds    mgl32.Vec4
 		texbounds mgl32.Vec4
+		linear    int32
+		texLinear int32
+		depth     float32
 	}
+
+	// VertexSlices of the merged draws taking turns, the index of the last used one and the
+	// shader they were made for, accessed only inside mainthread
+	merged       [vertexBuffers]*glhf.VertexSlice
+	mergedNext   int
+	mergedShader *glhf.Shader
 }
 
 type gsUniformAttr struct {
@@ -32,30 +46,47 @@ type gsUniformAttr struct {
 	ispointer bool
 }
 
+// gsTexture is a texture bound to a sampler2D uniform. The texture units are assigned in the
+// order of the textures, from 1, because the unit 0 is used by the drawn Picture.
+type gsTexture struct {
+	Name string
+	pic  GLPicture
+}
+
 // reinitialize GLShader data and recompile the underlying gl shader object
 func (gs *glShader) update() {
-	gs.uf = nil
+	if err := gs.compile(); err != nil {
+		panic(errors.Wrap(err, "failed to create Canvas, there's a bug in the shader"))
+	}
+}
+
+// compile recompiles the underlying gl shader object, keeping the previous one if it fails
+func (gs *glShader) compile() error {
+	var uf glhf.AttrFormat
 	for _, u := range gs.uniforms {
-		gs.uf = append(gs.uf, glhf.Attr{
+		uf = append(uf, glhf.Attr{
 			Name: u.Name,
 			Type: u.Type,
 		})
 	}
-	var shader *glhf.Shader
+	var (
+		shader *glhf.Shader
+		err    error
+	)
 	mainthread.Call(func() {
-		var err error
 		shader, err = glhf.NewShader(
 			gs.vf,
-			gs.uf,
-			gs.vs,
-			gs.fs,
+			uf,
+			shaderSource(gs.vs, false),
+			shaderSource(gs.fs, true),
 		)
-		if err != nil {
-			panic(errors.Wrap(err, "failed to create Canvas, there's a bug in the shader"))
-		}
 	})
+	if err != nil {
+		return err
+	}
 
-	gs.s = shader
+	gs.s, gs.uf = shader, uf
+	return nil
 }
 
 // gets the uniform index from GLShader
@@ -92,6 +123,35 @@ func (gs *glShader) setUniform(name string, value interface{}) {
 	})
 }
 
+// setTexture binds the GLPicture's texture to the sampler2D uniform with the name. If the uniform
+// already has a texture, it will simply be replaced.
+func (gs *glShader) setTexture(name string, pic GLPicture) {
+	for i := range gs.textures {
+		if gs.textures[i].Name == name {
+			gs.textures[i].pic = pic
+			return
+		}
+	}
+	gs.textures = append(gs.textures, gsTexture{Name: name, pic: pic})
+	gs.setUniform(name, int32(len(gs.textures)))
+}
+
+// bindTextures binds the textures to the texture units from 1, in order. A nil texture unbinds the
+// unit.
+//
+// must be manually called inside mainthread
+func bindTextures(textures []Texture) {
+	for i, tex := range textures {
+		var id uint32
+		if tex != nil {
+			id = tex.ID()
+		}
+		gl.ActiveTexture(gl.TEXTURE1 + uint32(i))
+		gl.BindTexture(gl.TEXTURE_2D, id)
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+}
+
 // Sets up a base shader with everything needed for a Pixel
 // canvas to render correctly. The defaults can be overridden
 // by simply using the SetUniform function.
@@ -106,6 +166,9 @@ func baseShader(c *Canvas) {
 	gs.setUniform("uColorMask", &gs.uniformDefaults.colormask)
 	gs.setUniform("uBounds", &gs.uniformDefaults.bounds)
 	gs.setUniform("uTexBounds", &gs.uniformDefaults.texbounds)
+	gs.setUniform("uLinear", &gs.uniformDefaults.linear)
+	gs.setUniform("uTexLinear", &gs.uniformDefaults.texLinear)
+	gs.setUniform("uDepth", &gs.uniformDefaults.depth)
 
 	c.shader = gs
 }
@@ -214,56 +277,3 @@ func getAttrType(v interface{}) (glhf.AttrType, bool) {
 		panic("invalid AttrType")
 	}
 }
-
-var baseCanvasVertexShader = `
-#version 330 core
-
-in vec2  aPosition;
-in vec4  aColor;
-in vec2  aTexCoords;
-in float aIntensity;
-
-out vec4  vColor;
-out vec2  vTexCoords;
-out float vIntensity;
-out vec2  vPosition;
-
-uniform mat3 uTransform;
-uniform vec4 uBounds;
-
-void main() {
-	vec2 transPos = (uTransform * vec3(aPosition, 1.0)).xy;
-	vec2 normPos = (transPos - uBounds.xy) / uBounds.zw * 2 - vec2(1, 1);
-	gl_Position = vec4(normPos, 0.0, 1.0);
-	vColor = aColor;
-	vPosition = aPosition;
-	vTexCoords = aTexCoords;
-	vIntensity = aIntensity;
-}
-`
-
-var baseCanvasFragmentShader = `
-#version 330 core
-
-in vec4  vColor;
-in vec2  vTexCoords;
-in float vIntensity;
-
-out vec4 fragColor;
-
-uniform vec4 uColorMask;
-uniform vec4 uTexBounds;
-uniform sampler2D uTexture;
-
-void main() {
-	if (vIntensity == 0) {
-		fragColor = uColorMask * vColor;
-	} else {
-		fragColor = vec4(0, 0, 0, 0);
-		fragColor += (1 - vIntensity) * vColor;
-		vec2 t = (vTexCoords - uTexBounds.xy) / uTexBounds.zw;
-		fragColor += vIntensity * vColor * texture(uTexture, t);
-		fragColor *= uColorMask;
-	}
-}
-`
@@ -0,0 +1,171 @@
+//go:build js || android || ios
+// +build js android ios
+
+package pixelgl
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pkg/errors"
+)
+
+// glShader is the OpenGL ES shader program of a Canvas with its uniforms. The shaders are the GLSL
+// 3.30 ones of the desktop, translated to GLSL ES 3.00.
+type glShader struct {
+	ctx     *context
+	program glProgram
+	linked  bool
+	vs, fs  string
+
+	uniforms []gsUniformAttr
+
+	// locations of the uniforms in the program, by their names
+	locations map[string]uniformLocation
+
+	uniformDefaults struct {
+		transform mgl32.Mat3
+		colormask mgl32.Vec4
+		bounds    mgl32.Vec4
+		texbounds mgl32.Vec4
+		linear    int32
+		texLinear int32
+		depth     float32
+	}
+}
+
+// uniformLocation is the location of a uniform, ok is false if the program doesn't use it
+type uniformLocation struct {
+	loc glUniform
+	ok  bool
+}
+
+type gsUniformAttr struct {
+	Name  string
+	value interface{}
+}
+
+// baseShader sets up the shader of the Canvas with the same uniforms as on the desktop
+func baseShader(c *Canvas) {
+	gs := &glShader{
+		ctx: c.ctx,
+		vs:  baseCanvasVertexShader,
+		fs:  baseCanvasFragmentShader,
+	}
+
+	gs.setUniform("uTransform", &gs.uniformDefaults.transform)
+	gs.setUniform("uColorMask", &gs.uniformDefaults.colormask)
+	gs.setUniform("uBounds", &gs.uniformDefaults.bounds)
+	gs.setUniform("uTexBounds", &gs.uniformDefaults.texbounds)
+	gs.setUniform("uLinear", &gs.uniformDefaults.linear)
+	gs.setUniform("uTexLinear", &gs.uniformDefaults.texLinear)
+	gs.setUniform("uDepth", &gs.uniformDefaults.depth)
+
+	c.shader = gs
+}
+
+// reinitialize the shader program
+func (gs *glShader) update() {
+	if err := gs.compile(); err != nil {
+		panic(errors.Wrap(err, "failed to create Canvas, there's a bug in the shader"))
+	}
+}
+
+// compile recompiles the shader program, keeping the previous one if it fails
+func (gs *glShader) compile() error {
+	ctx := gs.ctx
+	program, err := ctx.linkProgram(glsl300es(gs.vs), glsl300es(gs.fs))
+	if err != nil {
+		return err
+	}
+	if gs.linked {
+		ctx.deleteProgram(gs.program)
+	}
+	gs.program = program
+	gs.linked = true
+	gs.locations = make(map[string]uniformLocation)
+
+	ctx.useProgram(program)
+	if loc, ok := gs.location("uTexture"); ok {
+		ctx.uniform(loc, int32(0))
+	}
+	return nil
+}
+
+// location returns the location of the uniform in the program, false if the program doesn't use
+// it
+func (gs *glShader) location(name string) (glUniform, bool) {
+	l, ok := gs.locations[name]
+	if !ok {
+		l.loc, l.ok = gs.ctx.uniformLocation(gs.program, name)
+		gs.locations[name] = l
+	}
+	return l.loc, l.ok
+}
+
+// setUniform appends a custom uniform name and value to the shader. If the uniform already exists,
+// it will simply be overwritten.
+func (gs *glShader) setUniform(name string, value interface{}) {
+	// check the type now, not while drawing
+	uniformValue(value)
+	for i := range gs.uniforms {
+		if gs.uniforms[i].Name == name {
+			gs.uniforms[i].value = value
+			return
+		}
+	}
+	gs.uniforms = append(gs.uniforms, gsUniformAttr{Name: name, value: value})
+}
+
+// begin uses the program and sets the values of all the uniforms
+func (gs *glShader) begin() {
+	gs.ctx.useProgram(gs.program)
+	for _, u := range gs.uniforms {
+		if loc, ok := gs.location(u.Name); ok {
+			gs.ctx.uniform(loc, uniformValue(u.value))
+		}
+	}
+}
+
+// uniformValue returns the value of a uniform, dereferenced if it's a pointer. It supports the
+// same types as on the desktop.
+func uniformValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int32, float32,
+		mgl32.Vec2, mgl32.Vec3, mgl32.Vec4,
+		mgl32.Mat2, mgl32.Mat2x3, mgl32.Mat2x4,
+		mgl32.Mat3, mgl32.Mat3x2, mgl32.Mat3x4,
+		mgl32.Mat4, mgl32.Mat4x2, mgl32.Mat4x3:
+		return v
+	case *int32:
+		return *v
+	case *float32:
+		return *v
+	case *mgl32.Vec2:
+		return *v
+	case *mgl32.Vec3:
+		return *v
+	case *mgl32.Vec4:
+		return *v
+	case *mgl32.Mat2:
+		return *v
+	case *mgl32.Mat2x3:
+		return *v
+	case *mgl32.Mat2x4:
+		return *v
+	case *mgl32.Mat3:
+		return *v
+	case *mgl32.Mat3x2:
+		return *v
+	case *mgl32.Mat3x4:
+		return *v
+	case *mgl32.Mat4:
+		return *v
+	case *mgl32.Mat4x2:
+		return *v
+	case *mgl32.Mat4x3:
+		return *v
+	default:
+		panic(fmt.Errorf("invalid AttrType %T", value))
+	}
+}
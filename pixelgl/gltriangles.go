@@ -1,3 +1,6 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
 package pixelgl
 
 import (
@@ -8,14 +11,37 @@ import (
 	"github.com/faiface/pixel"
 )
 
+// vertexBuffers is the number of VertexSlices GLTriangles rewritten every frame take turns with,
+// so that writing the vertices of a frame doesn't wait for the GPU to finish reading the previous
+// frames.
+const vertexBuffers = 3
+
 // GLTriangles are OpenGL triangles implemented using glhf.VertexSlice.
 //
 // Triangles returned from this function support TrianglesPosition, TrianglesColor and
 // TrianglesPicture. If you need to support more, you can "override" SetLen and Update methods.
+//
+// If GLTriangles are updated after they were drawn, the vertices are written into another
+// VertexSlice, up to three of them in turns, because the GPU may still be reading the drawn one.
 type GLTriangles struct {
 	vs     *glhf.VertexSlice
 	data   []float32
 	shader *glhf.Shader
+
+	// the VertexSlices taking turns, which are allocated when the GLTriangles are first updated
+	// after a draw, and the index of vs among them
+	ring []*glhf.VertexSlice
+	next int
+
+	// drawn is set when vs may have been drawn since it was written, shared is set when vs is
+	// shared with a Slice, which must not be swapped then
+	drawn  bool
+	shared bool
+
+	// the largest length of the VertexSlices, which don't shrink, and their GPU memory, nil in a
+	// Slice
+	capacity int
+	mem      *gpuAlloc
 }
 
 var (
@@ -35,6 +61,7 @@ func NewGLTriangles(shader *glhf.Shader, t pixel.Triangles) *GLTriangles {
 			shader: shader,
 		}
 	})
+	gt.mem = trackGPU(gpuBuffer, 0)
 	gt.SetLen(t.Len())
 	gt.Update(t)
 	return gt
@@ -42,8 +69,10 @@ func NewGLTriangles(shader *glhf.Shader, t pixel.Triangles) *GLTriangles {
 
 // VertexSlice returns the VertexSlice of this GLTriangles.
 //
-// You can use it to draw them.
+// You can use it to draw them. The VertexSlice changes with the following Update, so get it for
+// each draw.
 func (gt *GLTriangles) VertexSlice() *glhf.VertexSlice {
+	gt.drawn = true
 	return gt.vs
 }
 
@@ -77,51 +106,60 @@ func (gt *GLTriangles) SetLen(length int) {
 	default:
 		return
 	}
-	mainthread.CallNonBlock(func() {
-		gt.vs.Begin()
-		gt.vs.SetLen(length)
-		gt.vs.End()
-	})
+	gt.account()
+	// the other VertexSlices of the ring are resized when they're written
+	vs := gt.vs
+	mainthread.CallNonBlock(debugWrap(func() {
+		vs.Begin()
+		vs.SetLen(length)
+		vs.End()
+	}))
 }
 
 // Slice returns a sub-Triangles of this GLTriangles in range [i, j).
 func (gt *GLTriangles) Slice(i, j int) pixel.Triangles {
+	gt.shared = true
 	return &GLTriangles{
 		vs:     gt.vs.Slice(i, j),
 		data:   gt.data[i*gt.vs.Stride() : j*gt.vs.Stride()],
 		shader: gt.shader,
+		shared: true,
 	}
 }
 
 func (gt *GLTriangles) updateData(t pixel.Triangles) {
+	defer pixel.BeginPhase(pixel.PhaseTransform).End()
+
 	// glTriangles short path
 	if t, ok := t.(*GLTriangles); ok {
 		copy(gt.data, t.data)
 		return
 	}
 
-	// TrianglesData short path
+	// TrianglesData short path, converted in parallel if there's many vertices
 	stride := gt.vs.Stride()
 	length := gt.Len()
 	if t, ok := t.(*pixel.TrianglesData); ok {
-		for i := 0; i < length; i++ {
-			var (
-				px, py = (*t)[i].Position.XY()
-				col    = (*t)[i].Color
-				tx, ty = (*t)[i].Picture.XY()
-				in     = (*t)[i].Intensity
-			)
-			d := gt.data[i*stride : i*stride+9]
-			d[0] = float32(px)
-			d[1] = float32(py)
-			d[2] = float32(col.R)
-			d[3] = float32(col.G)
-			d[4] = float32(col.B)
-			d[5] = float32(col.A)
-			d[6] = float32(tx)
-			d[7] = float32(ty)
-			d[8] = float32(in)
-		}
+		pixel.ParallelVertices(length, func(i, j int) {
+			for ; i < j; i++ {
+				var (
+					px, py = (*t)[i].Position.XY()
+					col    = (*t)[i].Color
+					tx, ty = (*t)[i].Picture.XY()
+					in     = (*t)[i].Intensity
+				)
+				d := gt.data[i*stride : i*stride+9]
+				d[0] = float32(px)
+				d[1] = float32(py)
+				d[2] = float32(col.R)
+				d[3] = float32(col.G)
+				d[4] = float32(col.B)
+				d[5] = float32(col.A)
+				d[6] = float32(tx)
+				d[7] = float32(ty)
+				d[8] = float32(in)
+			}
+		})
 		return
 	}
 
@@ -159,23 +197,96 @@ func (gt *GLTriangles) Update(t pixel.Triangles) {
 		panic(fmt.Errorf("(%T).Update: invalid triangles len", gt))
 	}
 	gt.updateData(t)
+	vs := gt.writeSlice()
+	length := gt.Len()
 
 	// this code is supposed to copy the vertex data and CallNonBlock the update if
 	// the data is small enough, otherwise it'll block and not copy the data
 	if len(gt.data) < 256 { // arbitrary heurestic constant
 		data := append([]float32{}, gt.data...)
-		mainthread.CallNonBlock(func() {
-			gt.vs.Begin()
-			gt.vs.SetVertexData(data)
-			gt.vs.End()
-		})
+		mainthread.CallNonBlock(debugWrap(func() {
+			defer beginPhase(pixel.PhaseUpload).End()
+
+			vs.Begin()
+			if vs.Len() != length {
+				vs.SetLen(length)
+			}
+			vs.SetVertexData(data)
+			vs.End()
+		}))
 	} else {
+		mainthread.Call(debugWrap(func() {
+			defer beginPhase(pixel.PhaseUpload).End()
+
+			vs.Begin()
+			if vs.Len() != length {
+				vs.SetLen(length)
+			}
+			vs.SetVertexData(gt.data)
+			vs.End()
+		}))
+	}
+}
+
+// writeSlice returns the VertexSlice the vertices are written to by Update and makes it the
+// current one. If the current one may have been drawn, the GPU may still be reading it, so the next
+// VertexSlice of the ring is used instead.
+func (gt *GLTriangles) writeSlice() *glhf.VertexSlice {
+	drawn := gt.drawn
+	gt.drawn = false
+	if !drawn || gt.shared {
+		return gt.vs
+	}
+
+	if gt.ring == nil {
+		gt.ring = []*glhf.VertexSlice{gt.vs}
+	}
+	var alloc bool
+	gt.next, alloc = ringNext(gt.next, len(gt.ring))
+	if alloc {
+		var vs *glhf.VertexSlice
+		length := gt.Len()
 		mainthread.Call(func() {
-			gt.vs.Begin()
-			gt.vs.SetVertexData(gt.data)
-			gt.vs.End()
+			vs = glhf.MakeVertexSlice(gt.shader, length, length)
 		})
+		gt.ring = append(gt.ring, vs)
+		gt.account()
+	}
+	gt.vs = gt.ring[gt.next]
+	return gt.vs
+}
+
+// ringNext returns the index of the VertexSlice following the i-th one in a ring of n allocated
+// ones, and whether it must be allocated first. The ring grows by one slice at a time up to
+// vertexBuffers slices, then the indices wrap around.
+func ringNext(i, n int) (next int, alloc bool) {
+	next = (i + 1) % vertexBuffers
+	return next, next == n
+}
+
+// account updates the estimate of the GPU memory of the VertexSlices
+func (gt *GLTriangles) account() {
+	if gt.mem == nil {
+		return
+	}
+	if length := gt.Len(); length > gt.capacity {
+		gt.capacity = length
+	}
+	gt.mem.set(gt.GPUMemory())
+}
+
+// GPUMemory returns the estimated number of bytes of the vertex buffers of this GLTriangles, which
+// grow with its length and the VertexSlices taking turns, but never shrink. It's zero for a Slice,
+// which shares the buffers of its GLTriangles.
+func (gt *GLTriangles) GPUMemory() int64 {
+	if gt.mem == nil {
+		return 0
+	}
+	slices := len(gt.ring)
+	if slices == 0 {
+		slices = 1
 	}
+	return 4 * int64(slices*gt.capacity*gt.vs.Stride())
 }
 
 // Copy returns an independent copy of this GLTriangles.
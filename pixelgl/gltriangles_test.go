@@ -0,0 +1,47 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingNext(t *testing.T) {
+	// the ring starts with the first VertexSlice and grows by one at a time
+	next, alloc := ringNext(0, 1)
+	assert.Equal(t, 1, next)
+	assert.True(t, alloc)
+
+	next, alloc = ringNext(1, 2)
+	assert.Equal(t, 2, next)
+	assert.True(t, alloc)
+
+	// a full ring wraps around without allocating
+	next, alloc = ringNext(2, vertexBuffers)
+	assert.Equal(t, 0, next)
+	assert.False(t, alloc)
+
+	next, alloc = ringNext(0, vertexBuffers)
+	assert.Equal(t, 1, next)
+	assert.False(t, alloc)
+}
+
+func TestRingNextBounds(t *testing.T) {
+	// simulates writeSlice after every draw, the indices stay within the ring and it never grows
+	// over vertexBuffers slices
+	i, n := 0, 1
+	for step := 0; step < 10*vertexBuffers; step++ {
+		next, alloc := ringNext(i, n)
+		if alloc {
+			assert.Equal(t, n, next, "a new slice is appended at the end of the ring")
+			n++
+		}
+		assert.True(t, next >= 0 && next < n, "step %d: index %d out of the ring of %d", step, next, n)
+		assert.True(t, n <= vertexBuffers, "step %d: ring of %d slices", step, n)
+		i = next
+	}
+	assert.Equal(t, vertexBuffers, n)
+}
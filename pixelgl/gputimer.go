@@ -0,0 +1,151 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/faiface/mainthread"
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// GPUTimer measures the time the GPU spends executing the OpenGL commands of a part of a frame,
+// such as drawing the world, the lights or the UI.
+//
+// Measure a section of code by calling Begin and End around it, or assign the GPUTimer to a Canvas
+// with Canvas.SetGPUTimer to measure all draws onto the Canvas. Sections can be nested and one
+// GPUTimer can measure any number of sections, the times are summed.
+//
+// The GPU runs behind the CPU, so the times of a frame usually become available a frame or two
+// later. A typical usage is to read and reset the timer once per frame:
+//
+//   lightsTime := lightsTimer.Elapsed()
+//   lightsTimer.Reset()
+//
+// GPUTimer doesn't work on OpenGL ES, where it always reports zero.
+type GPUTimer struct {
+	// accessed only inside mainthread
+	pending []gpuTimerSection
+	queries []uint32
+	elapsed time.Duration
+	begin   uint32
+	depth   int
+}
+
+type gpuTimerSection struct {
+	begin, end uint32
+}
+
+// NewGPUTimer creates a new GPUTimer with zero elapsed time.
+func NewGPUTimer() *GPUTimer {
+	gt := &GPUTimer{}
+	runtime.SetFinalizer(gt, (*GPUTimer).delete)
+	return gt
+}
+
+func (gt *GPUTimer) delete() {
+	mainthread.CallNonBlock(func() {
+		for _, s := range gt.pending {
+			gt.queries = append(gt.queries, s.begin, s.end)
+		}
+		if len(gt.queries) > 0 {
+			gl.DeleteQueries(int32(len(gt.queries)), &gt.queries[0])
+		}
+	})
+}
+
+// Begin starts measuring a section of OpenGL commands.
+func (gt *GPUTimer) Begin() {
+	flushDraws()
+	mainthread.CallNonBlock(gt.start)
+}
+
+// End ends the section started by the last call to Begin.
+func (gt *GPUTimer) End() {
+	flushDraws()
+	mainthread.CallNonBlock(gt.stop)
+}
+
+// Elapsed returns the total GPU time of all measured sections finished since the last call to
+// Reset. Sections which the GPU hasn't finished yet are not included.
+func (gt *GPUTimer) Elapsed() time.Duration {
+	var elapsed time.Duration
+	mainthread.Call(func() {
+		gt.poll()
+		elapsed = gt.elapsed
+	})
+	return elapsed
+}
+
+// Reset sets the elapsed time of the GPUTimer back to zero. Sections still being executed by the GPU
+// will be included in the next Elapsed.
+func (gt *GPUTimer) Reset() {
+	mainthread.CallNonBlock(func() {
+		gt.poll()
+		gt.elapsed = 0
+	})
+}
+
+// must be manually called inside mainthread
+func (gt *GPUTimer) query() uint32 {
+	if len(gt.queries) == 0 {
+		gt.queries = make([]uint32, 8)
+		gl.GenQueries(int32(len(gt.queries)), &gt.queries[0])
+	}
+	id := gt.queries[len(gt.queries)-1]
+	gt.queries = gt.queries[:len(gt.queries)-1]
+	return id
+}
+
+// must be manually called inside mainthread
+func (gt *GPUTimer) start() {
+	if gles {
+		return
+	}
+	// only the outermost section is measured, so that nested sections are not counted twice
+	gt.depth++
+	if gt.depth > 1 {
+		return
+	}
+	gt.begin = gt.query()
+	gl.QueryCounter(gt.begin, gl.TIMESTAMP)
+}
+
+// must be manually called inside mainthread
+func (gt *GPUTimer) stop() {
+	if gles || gt.depth == 0 {
+		return
+	}
+	gt.depth--
+	if gt.depth > 0 {
+		return
+	}
+	end := gt.query()
+	gl.QueryCounter(end, gl.TIMESTAMP)
+	gt.pending = append(gt.pending, gpuTimerSection{begin: gt.begin, end: end})
+}
+
+// poll adds the times of the finished sections to the elapsed time.
+//
+// must be manually called inside mainthread
+func (gt *GPUTimer) poll() {
+	done := 0
+	for _, s := range gt.pending {
+		// the GPU executes the commands in order, so the end of a section is the last to become
+		// available
+		var available int32
+		gl.GetQueryObjectiv(s.end, gl.QUERY_RESULT_AVAILABLE, &available)
+		if available == gl.FALSE {
+			break
+		}
+		var begin, end uint64
+		gl.GetQueryObjectui64v(s.begin, gl.QUERY_RESULT, &begin)
+		gl.GetQueryObjectui64v(s.end, gl.QUERY_RESULT, &end)
+		gt.elapsed += time.Duration(end - begin)
+		gt.queries = append(gt.queries, s.begin, s.end)
+		done++
+	}
+	gt.pending = append(gt.pending[:0], gt.pending[done:]...)
+}
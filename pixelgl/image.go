@@ -0,0 +1,54 @@
+package pixelgl
+
+import (
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SaveScreenshot takes a screenshot of the Window like Screenshot and saves it to the file at the
+// path. The file is in the JPEG format, if the path ends with ".jpg" or ".jpeg", and in the PNG
+// format otherwise.
+func (w *Window) SaveScreenshot(path string) error {
+	return saveImage(path, w.Screenshot())
+}
+
+// screenshotImage flips the premultiplied pixels read from a framebuffer, the bottom row first,
+// and composes them over black
+func screenshotImage(pixels []uint8, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	stride := 4 * width
+	for y := 0; y < height; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+stride]
+		copy(row, pixels[(height-1-y)*stride:(height-y)*stride])
+		for i := 3; i < len(row); i += 4 {
+			row[i] = 0xff
+		}
+	}
+	return img
+}
+
+func saveImage(path string, img image.Image) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to save screenshot")
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil && cerr != nil {
+			err = errors.Wrap(cerr, "failed to save screenshot")
+		}
+	}()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg":
+		err = jpeg.Encode(file, img, &jpeg.Options{Quality: 95})
+	default:
+		err = png.Encode(file, img)
+	}
+	return errors.Wrap(err, "failed to save screenshot")
+}
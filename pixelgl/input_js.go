@@ -0,0 +1,345 @@
+package pixelgl
+
+import (
+	"fmt"
+	"math"
+	"syscall/js"
+	"unicode/utf8"
+
+	"github.com/faiface/pixel"
+)
+
+// Pressed returns whether the Button is currently pressed down.
+func (w *Window) Pressed(button Button) bool {
+	return w.currInp.buttons[button]
+}
+
+// JustPressed returns whether the Button has just been pressed down.
+func (w *Window) JustPressed(button Button) bool {
+	return w.currInp.buttons[button] && !w.prevInp.buttons[button]
+}
+
+// JustReleased returns whether the Button has just been released up.
+func (w *Window) JustReleased(button Button) bool {
+	return !w.currInp.buttons[button] && w.prevInp.buttons[button]
+}
+
+// Repeated returns whether a repeat event has been triggered on button.
+//
+// Repeat event occurs repeatedly when a button is held down for some time.
+func (w *Window) Repeated(button Button) bool {
+	return w.currInp.repeat[button]
+}
+
+// MousePosition returns the current mouse position in the Window's Bounds.
+func (w *Window) MousePosition() pixel.Vec {
+	return w.currInp.mouse
+}
+
+// MousePreviousPosition returns the previous mouse position in the Window's Bounds.
+func (w *Window) MousePreviousPosition() pixel.Vec {
+	return w.prevInp.mouse
+}
+
+// SetMousePosition does nothing in the browser, a page can't move the mouse cursor.
+func (w *Window) SetMousePosition(v pixel.Vec) {}
+
+// MouseInsideWindow returns true if the mouse position is within the Window's Bounds.
+func (w *Window) MouseInsideWindow() bool {
+	return w.cursorInsideWindow
+}
+
+// MouseScroll returns the mouse scroll amount (in both axes) since the last call to Window.Update.
+//
+// X is the horizontal scroll, positive to the right, and Y is the vertical scroll, positive away
+// from the user. A notch of a mouse wheel scrolls by about 1, see the desktop MouseScroll.
+func (w *Window) MouseScroll() pixel.Vec {
+	return w.currInp.scroll
+}
+
+// MouseScrollPrecise returns whether the scroll since the last call to Window.Update came in
+// fractions of a notch, from a trackpad or a high-resolution wheel.
+func (w *Window) MouseScrollPrecise() bool {
+	return w.currInp.precise
+}
+
+// zoomStep is the zoom factor of one notch of a mouse wheel scrolled with Control held
+const zoomStep = 1.1
+
+// MouseZoom returns the factor the user zoomed by since the last call to Window.Update, 1 if they
+// didn't zoom. Greater than 1 means zooming in.
+//
+// The zoom comes from the vertical scroll with Control held, which is also how the browsers
+// report the pinch gestures of touchpads. The scroll is reported by MouseScroll too.
+func (w *Window) MouseZoom() float64 {
+	return math.Pow(zoomStep, w.currInp.zoom)
+}
+
+// Typed returns the text typed on the keyboard since the last call to Window.Update.
+func (w *Window) Typed() string {
+	return w.currInp.typed
+}
+
+// UpdateInput polls window events. Call this function to poll window events without showing the
+// content of the Window. Note that the Update method invokes UpdateInput.
+//
+// The page handles the events only while the game waits, e.g. in Update, so a loop calling only
+// UpdateInput doesn't receive new ones.
+func (w *Window) UpdateInput() {
+	w.prevInp = w.currInp
+	w.currInp = w.tempInp
+
+	w.tempInp.repeat = [KeyLast + 1]bool{}
+	w.tempInp.scroll = pixel.ZV
+	w.tempInp.precise = false
+	w.tempInp.zoom = 0
+	w.tempInp.typed = ""
+
+	w.updateJoystickInput()
+}
+
+// Used internally during Window.UpdateInput to update the state of the joysticks, which are the
+// gamepads of the browser's Gamepad API.
+func (w *Window) updateJoystickInput() {
+	var gamepads js.Value
+	if navigator := js.Global().Get("navigator"); navigator.Get("getGamepads").Truthy() {
+		gamepads = navigator.Call("getGamepads")
+	}
+
+	for joy := Joystick1; joy <= JoystickLast; joy++ {
+		var gamepad js.Value
+		if gamepads.Truthy() && int(joy) < gamepads.Length() {
+			gamepad = gamepads.Index(int(joy))
+		}
+		w.tempJoy.connected[joy] = gamepad.Truthy()
+
+		if !gamepad.Truthy() {
+			w.tempJoy.buttons[joy] = []byte{}
+			w.tempJoy.axis[joy] = []float32{}
+			w.tempJoy.name[joy] = ""
+			continue
+		}
+
+		buttons := gamepad.Get("buttons")
+		w.tempJoy.buttons[joy] = make([]byte, buttons.Length())
+		for i := range w.tempJoy.buttons[joy] {
+			if buttons.Index(i).Get("pressed").Bool() {
+				w.tempJoy.buttons[joy][i] = 1
+			}
+		}
+		axes := gamepad.Get("axes")
+		w.tempJoy.axis[joy] = make([]float32, axes.Length())
+		for i := range w.tempJoy.axis[joy] {
+			w.tempJoy.axis[joy][i] = float32(axes.Index(i).Float())
+		}
+		w.tempJoy.name[joy] = gamepad.Get("id").String()
+	}
+
+	w.prevJoy = w.currJoy
+	w.currJoy = w.tempJoy
+}
+
+// listener is an event listener added by a Window
+type listener struct {
+	target js.Value
+	event  string
+	f      js.Func
+}
+
+// on adds the listener of the event to the target, which is removed by Destroy
+func (w *Window) on(target js.Value, event string, handle func(e js.Value)) {
+	f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		handle(args[0])
+		return nil
+	})
+	target.Call("addEventListener", event, f, map[string]interface{}{"passive": false})
+	w.listeners = append(w.listeners, listener{target: target, event: event, f: f})
+}
+
+// listen adds the listeners of the input. The keys are taken from the whole page, unless another
+// element, such as a text field, has the focus.
+func (w *Window) listen() {
+	window := js.Global()
+	body := window.Get("document").Get("body")
+	ours := func(e js.Value) bool {
+		t := e.Get("target")
+		return t.Equal(w.element) || t.Equal(body)
+	}
+
+	w.on(window, "keydown", func(e js.Value) {
+		if !ours(e) {
+			return
+		}
+		if button, ok := keyCodes[e.Get("code").String()]; ok {
+			w.tempInp.buttons[button] = true
+			if e.Get("repeat").Bool() {
+				w.tempInp.repeat[button] = true
+			}
+			// e.g. the arrows and the space would scroll the page
+			e.Call("preventDefault")
+		}
+		key := e.Get("key").String()
+		if utf8.RuneCountInString(key) == 1 && !e.Get("ctrlKey").Bool() && !e.Get("metaKey").Bool() {
+			w.tempInp.typed += key
+		}
+	})
+	w.on(window, "keyup", func(e js.Value) {
+		if button, ok := keyCodes[e.Get("code").String()]; ok {
+			w.tempInp.buttons[button] = false
+		}
+	})
+
+	w.on(w.element, "mousedown", func(e js.Value) {
+		if button, ok := mouseButton(e.Get("button").Int()); ok {
+			w.tempInp.buttons[button] = true
+		}
+	})
+	// the buttons are released outside of the canvas too
+	w.on(window, "mouseup", func(e js.Value) {
+		if button, ok := mouseButton(e.Get("button").Int()); ok {
+			w.tempInp.buttons[button] = false
+		}
+	})
+	w.on(window, "mousemove", func(e js.Value) {
+		rect := w.element.Call("getBoundingClientRect")
+		width, height := rect.Get("width").Float(), rect.Get("height").Float()
+		if width == 0 || height == 0 {
+			return
+		}
+		// the position goes down from the top-left corner of the element, scaled by its CSS size
+		x := (e.Get("clientX").Float() - rect.Get("left").Float()) * w.bounds.W() / width
+		y := (e.Get("clientY").Float() - rect.Get("top").Float()) * w.bounds.H() / height
+		w.tempInp.mouse = pixel.V(
+			x+w.bounds.Min.X,
+			(w.bounds.H()-y)+w.bounds.Min.Y,
+		)
+	})
+	w.on(w.element, "mouseenter", func(e js.Value) {
+		w.cursorInsideWindow = true
+	})
+	w.on(w.element, "mouseleave", func(e js.Value) {
+		w.cursorInsideWindow = false
+	})
+	w.on(w.element, "wheel", func(e js.Value) {
+		e.Call("preventDefault")
+		scroll := wheelScroll(e)
+		w.tempInp.scroll = w.tempInp.scroll.Add(scroll)
+		if scroll.X != math.Trunc(scroll.X) || scroll.Y != math.Trunc(scroll.Y) {
+			w.tempInp.precise = true
+		}
+		if e.Get("ctrlKey").Bool() {
+			w.tempInp.zoom += scroll.Y
+		}
+	})
+	// the right button would open the menu of the page
+	w.on(w.element, "contextmenu", func(e js.Value) {
+		e.Call("preventDefault")
+	})
+
+	w.on(window, "focus", func(e js.Value) {
+		w.focused = true
+	})
+	w.on(window, "blur", func(e js.Value) {
+		w.focused = false
+		// the releases of the buttons go to the focused page
+		w.tempInp.buttons = [KeyLast + 1]bool{}
+	})
+}
+
+// wheelScroll returns the scroll of a wheel event in the notches of a wheel, up and right being
+// positive like in GLFW
+func wheelScroll(e js.Value) pixel.Vec {
+	var notch float64
+	switch e.Get("deltaMode").Int() {
+	case 0: // pixels
+		notch = 100
+	case 1: // lines
+		notch = 3
+	default: // pages
+		notch = 1
+	}
+	return pixel.V(-e.Get("deltaX").Float()/notch, -e.Get("deltaY").Float()/notch)
+}
+
+// mouseButton returns the Button of the button of a mouse event
+func mouseButton(b int) (Button, bool) {
+	switch b {
+	case 0:
+		return MouseButtonLeft, true
+	case 1:
+		return MouseButtonMiddle, true
+	case 2:
+		return MouseButtonRight, true
+	case 3:
+		return MouseButton4, true
+	case 4:
+		return MouseButton5, true
+	default:
+		return 0, false
+	}
+}
+
+// keyCodes are the Buttons of the physical keys, by the code of the keyboard events
+var keyCodes = func() map[string]Button {
+	codes := map[string]Button{
+		"Space":          KeySpace,
+		"Quote":          KeyApostrophe,
+		"Comma":          KeyComma,
+		"Minus":          KeyMinus,
+		"Period":         KeyPeriod,
+		"Slash":          KeySlash,
+		"Semicolon":      KeySemicolon,
+		"Equal":          KeyEqual,
+		"BracketLeft":    KeyLeftBracket,
+		"Backslash":      KeyBackslash,
+		"BracketRight":   KeyRightBracket,
+		"Backquote":      KeyGraveAccent,
+		"IntlBackslash":  KeyWorld1,
+		"Escape":         KeyEscape,
+		"Enter":          KeyEnter,
+		"Tab":            KeyTab,
+		"Backspace":      KeyBackspace,
+		"Insert":         KeyInsert,
+		"Delete":         KeyDelete,
+		"ArrowRight":     KeyRight,
+		"ArrowLeft":      KeyLeft,
+		"ArrowDown":      KeyDown,
+		"ArrowUp":        KeyUp,
+		"PageUp":         KeyPageUp,
+		"PageDown":       KeyPageDown,
+		"Home":           KeyHome,
+		"End":            KeyEnd,
+		"CapsLock":       KeyCapsLock,
+		"ScrollLock":     KeyScrollLock,
+		"NumLock":        KeyNumLock,
+		"PrintScreen":    KeyPrintScreen,
+		"Pause":          KeyPause,
+		"NumpadDecimal":  KeyKPDecimal,
+		"NumpadDivide":   KeyKPDivide,
+		"NumpadMultiply": KeyKPMultiply,
+		"NumpadSubtract": KeyKPSubtract,
+		"NumpadAdd":      KeyKPAdd,
+		"NumpadEnter":    KeyKPEnter,
+		"NumpadEqual":    KeyKPEqual,
+		"ShiftLeft":      KeyLeftShift,
+		"ControlLeft":    KeyLeftControl,
+		"AltLeft":        KeyLeftAlt,
+		"MetaLeft":       KeyLeftSuper,
+		"ShiftRight":     KeyRightShift,
+		"ControlRight":   KeyRightControl,
+		"AltRight":       KeyRightAlt,
+		"MetaRight":      KeyRightSuper,
+		"ContextMenu":    KeyMenu,
+	}
+	for i := 0; i < 26; i++ {
+		codes["Key"+string(rune('A'+i))] = KeyA + Button(i)
+	}
+	for i := 0; i < 10; i++ {
+		codes[fmt.Sprintf("Digit%d", i)] = Key0 + Button(i)
+		codes[fmt.Sprintf("Numpad%d", i)] = KeyKP0 + Button(i)
+	}
+	for i := 0; i < 25; i++ {
+		codes[fmt.Sprintf("F%d", i+1)] = KeyF1 + Button(i)
+	}
+	return codes
+}()
@@ -0,0 +1,250 @@
+//go:build android || ios
+// +build android ios
+
+package pixelgl
+
+import (
+	"github.com/faiface/pixel"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/touch"
+)
+
+// Touch is a finger touching the screen.
+type Touch struct {
+	// ID identifies the finger from the beginning to the end of the touch.
+	ID int64
+
+	// Pos is the position of the finger in the Window's Bounds.
+	Pos pixel.Vec
+}
+
+// input is the state of the input of a frame
+type input struct {
+	mouse   pixel.Vec
+	buttons [KeyLast + 1]bool
+	repeat  [KeyLast + 1]bool
+	typed   string
+	touches []Touch
+
+	// primary is the ID of the finger driving the mouse, if any
+	primary    int64
+	hasPrimary bool
+}
+
+// copy returns a copy of the input, not sharing the touches
+func (in *input) copy() input {
+	c := *in
+	c.touches = append([]Touch(nil), in.touches...)
+	return c
+}
+
+// next clears the input which only lasts for a frame
+func (in *input) next() {
+	in.repeat = [KeyLast + 1]bool{}
+	in.typed = ""
+}
+
+// release releases all the buttons and the touches, e.g. when the app loses the focus
+func (in *input) release() {
+	in.buttons = [KeyLast + 1]bool{}
+	in.touches = nil
+	in.hasPrimary = false
+}
+
+func (in *input) touch(id int64, at pixel.Vec, typ touch.Type) {
+	i := 0
+	for i < len(in.touches) && in.touches[i].ID != id {
+		i++
+	}
+	switch typ {
+	case touch.TypeBegin:
+		if i == len(in.touches) {
+			in.touches = append(in.touches, Touch{ID: id})
+		}
+		in.touches[i].Pos = at
+		if !in.hasPrimary {
+			in.primary, in.hasPrimary = id, true
+			in.buttons[MouseButtonLeft] = true
+		}
+	case touch.TypeMove:
+		if i < len(in.touches) {
+			in.touches[i].Pos = at
+		}
+	case touch.TypeEnd:
+		if i < len(in.touches) {
+			in.touches = append(in.touches[:i], in.touches[i+1:]...)
+		}
+		if in.hasPrimary && in.primary == id {
+			in.hasPrimary = false
+			in.buttons[MouseButtonLeft] = false
+		}
+	}
+	if in.hasPrimary && in.primary == id {
+		in.mouse = at
+	}
+}
+
+func (in *input) key(e key.Event) {
+	button, ok := keyButtons[e.Code]
+	if !ok && e.Code >= key.CodeA && e.Code <= key.CodeZ {
+		button, ok = KeyA+Button(e.Code-key.CodeA), true
+	}
+	switch e.Direction {
+	case key.DirPress:
+		if ok {
+			in.buttons[button] = true
+		}
+	case key.DirRelease:
+		if ok {
+			in.buttons[button] = false
+		}
+	case key.DirNone:
+		if ok {
+			in.repeat[button] = true
+		}
+	}
+	if e.Direction != key.DirRelease && e.Rune >= ' ' && e.Rune != 0x7f {
+		in.typed += string(e.Rune)
+	}
+}
+
+var keyButtons = map[key.Code]Button{
+	key.Code0:                  Key0,
+	key.Code1:                  Key1,
+	key.Code2:                  Key2,
+	key.Code3:                  Key3,
+	key.Code4:                  Key4,
+	key.Code5:                  Key5,
+	key.Code6:                  Key6,
+	key.Code7:                  Key7,
+	key.Code8:                  Key8,
+	key.Code9:                  Key9,
+	key.CodeReturnEnter:        KeyEnter,
+	key.CodeEscape:             KeyEscape,
+	key.CodeDeleteBackspace:    KeyBackspace,
+	key.CodeTab:                KeyTab,
+	key.CodeSpacebar:           KeySpace,
+	key.CodeHyphenMinus:        KeyMinus,
+	key.CodeEqualSign:          KeyEqual,
+	key.CodeLeftSquareBracket:  KeyLeftBracket,
+	key.CodeRightSquareBracket: KeyRightBracket,
+	key.CodeBackslash:          KeyBackslash,
+	key.CodeSemicolon:          KeySemicolon,
+	key.CodeApostrophe:         KeyApostrophe,
+	key.CodeGraveAccent:        KeyGraveAccent,
+	key.CodeComma:              KeyComma,
+	key.CodeFullStop:           KeyPeriod,
+	key.CodeSlash:              KeySlash,
+	key.CodeRightArrow:         KeyRight,
+	key.CodeLeftArrow:          KeyLeft,
+	key.CodeDownArrow:          KeyDown,
+	key.CodeUpArrow:            KeyUp,
+	key.CodePageUp:             KeyPageUp,
+	key.CodePageDown:           KeyPageDown,
+	key.CodeHome:               KeyHome,
+	key.CodeEnd:                KeyEnd,
+	key.CodeDeleteForward:      KeyDelete,
+	key.CodeLeftControl:        KeyLeftControl,
+	key.CodeLeftShift:          KeyLeftShift,
+	key.CodeLeftAlt:            KeyLeftAlt,
+	key.CodeLeftGUI:            KeyLeftSuper,
+	key.CodeRightControl:       KeyRightControl,
+	key.CodeRightShift:         KeyRightShift,
+	key.CodeRightAlt:           KeyRightAlt,
+	key.CodeRightGUI:           KeyRightSuper,
+}
+
+// Pressed returns whether the Button is currently pressed down. The first finger touching the
+// screen presses MouseButtonLeft.
+func (w *Window) Pressed(button Button) bool {
+	return w.currInp.buttons[button]
+}
+
+// JustPressed returns whether the Button has just been pressed down.
+func (w *Window) JustPressed(button Button) bool {
+	return w.currInp.buttons[button] && !w.prevInp.buttons[button]
+}
+
+// JustReleased returns whether the Button has just been released up.
+func (w *Window) JustReleased(button Button) bool {
+	return !w.currInp.buttons[button] && w.prevInp.buttons[button]
+}
+
+// Repeated returns whether a repeat event has been triggered on button.
+//
+// Repeat event occurs repeatedly when a button is held down for some time.
+func (w *Window) Repeated(button Button) bool {
+	return w.currInp.repeat[button]
+}
+
+// MousePosition returns the position of the first finger touching the screen in the Window's
+// Bounds, or the last one after the finger is lifted.
+func (w *Window) MousePosition() pixel.Vec {
+	return w.currInp.mouse
+}
+
+// MousePreviousPosition returns the previous mouse position in the Window's Bounds.
+func (w *Window) MousePreviousPosition() pixel.Vec {
+	return w.prevInp.mouse
+}
+
+// SetMousePosition does nothing on the phones, which have no mouse cursor.
+func (w *Window) SetMousePosition(v pixel.Vec) {}
+
+// MouseInsideWindow returns true while a finger touches the screen.
+func (w *Window) MouseInsideWindow() bool {
+	return w.currInp.hasPrimary
+}
+
+// MouseScroll returns the mouse scroll amount, which is always zero on the phones.
+func (w *Window) MouseScroll() pixel.Vec {
+	return pixel.ZV
+}
+
+// MouseScrollPrecise returns whether the scroll came in fractions of a notch, which is always
+// false on the phones.
+func (w *Window) MouseScrollPrecise() bool {
+	return false
+}
+
+// MouseZoom returns the factor the user zoomed by, which is always 1 on the phones. Use Touches to
+// follow the pinch gestures.
+func (w *Window) MouseZoom() float64 {
+	return 1
+}
+
+// Typed returns the text typed on the keyboard since the last call to Window.Update.
+func (w *Window) Typed() string {
+	return w.currInp.typed
+}
+
+// Touches returns the fingers touching the screen, in the order they started touching it.
+func (w *Window) Touches() []Touch {
+	return w.currInp.touches
+}
+
+// JustTouched returns whether the finger of the ID has just started touching the screen.
+func (w *Window) JustTouched(id int64) bool {
+	return touching(w.currInp.touches, id) && !touching(w.prevInp.touches, id)
+}
+
+func touching(touches []Touch, id int64) bool {
+	for _, t := range touches {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateInput polls window events. Call this function to poll window events without showing the
+// content of the Window. Note that the Update method invokes UpdateInput.
+//
+// The phones have no joysticks, so the joystick methods report none.
+func (w *Window) UpdateInput() {
+	theApp.mu.Lock()
+	w.prevInp = w.currInp
+	w.currInp = theApp.temp.copy()
+	theApp.temp.next()
+	theApp.mu.Unlock()
+}
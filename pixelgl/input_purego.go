@@ -0,0 +1,86 @@
+//go:build purego && !js && !android && !ios
+// +build purego,!js,!android,!ios
+
+package pixelgl
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelsw"
+)
+
+// Pressed returns whether the Button is currently pressed down.
+func (w *Window) Pressed(button Button) bool {
+	return w.win.Pressed(pixelsw.Button(button))
+}
+
+// JustPressed returns whether the Button has just been pressed down.
+func (w *Window) JustPressed(button Button) bool {
+	return w.win.JustPressed(pixelsw.Button(button))
+}
+
+// JustReleased returns whether the Button has just been released up.
+func (w *Window) JustReleased(button Button) bool {
+	return w.win.JustReleased(pixelsw.Button(button))
+}
+
+// Repeated returns whether a repeat event has been triggered on button.
+//
+// Repeat event occurs repeatedly when a button is held down for some time.
+func (w *Window) Repeated(button Button) bool {
+	return w.win.Repeated(pixelsw.Button(button))
+}
+
+// MousePosition returns the current mouse position in the Window's Bounds.
+func (w *Window) MousePosition() pixel.Vec {
+	return w.win.MousePosition()
+}
+
+// MousePreviousPosition returns the previous mouse position in the Window's Bounds.
+func (w *Window) MousePreviousPosition() pixel.Vec {
+	return w.win.MousePreviousPosition()
+}
+
+// SetMousePosition does nothing, pixelsw doesn't move the mouse cursor.
+func (w *Window) SetMousePosition(v pixel.Vec) {}
+
+// MouseInsideWindow returns true if the mouse position is within the Window's Bounds.
+func (w *Window) MouseInsideWindow() bool {
+	return w.win.MouseInsideWindow()
+}
+
+// MouseScroll returns the mouse scroll amount (in both axes) since the last call to Window.Update.
+//
+// X is the horizontal scroll, positive to the right, and Y is the vertical scroll, positive away
+// from the user. A notch of a mouse wheel scrolls by 1.
+func (w *Window) MouseScroll() pixel.Vec {
+	return w.win.MouseScroll()
+}
+
+// MouseScrollPrecise returns whether the scroll since the last call to Window.Update came in
+// fractions of a notch, from a trackpad or a high-resolution wheel.
+func (w *Window) MouseScrollPrecise() bool {
+	scroll := w.win.MouseScroll()
+	return scroll.X != math.Trunc(scroll.X) || scroll.Y != math.Trunc(scroll.Y)
+}
+
+// zoomStep is the zoom factor of one notch of a mouse wheel scrolled with Control held
+const zoomStep = 1.1
+
+// MouseZoom returns the factor the user zoomed by since the last call to Window.Update, 1 if they
+// didn't zoom. Greater than 1 means zooming in.
+//
+// The zoom comes from the vertical scroll with Control held, each notch zooms by 10%. The scroll
+// is reported by MouseScroll too.
+func (w *Window) MouseZoom() float64 {
+	if !w.Pressed(KeyLeftControl) && !w.Pressed(KeyRightControl) {
+		return 1
+	}
+	return math.Pow(zoomStep, w.win.MouseScroll().Y)
+}
+
+// Typed returns the text typed on the keyboard since the last call to Window.Update.
+func (w *Window) Typed() string {
+	return w.win.Typed()
+}
@@ -1,34 +1,5 @@
 package pixelgl
 
-import (
-	"github.com/go-gl/glfw/v3.2/glfw"
-)
-
-// Joystick is a joystick or controller.
-type Joystick int
-
-// List all of the joysticks.
-const (
-	Joystick1  = Joystick(glfw.Joystick1)
-	Joystick2  = Joystick(glfw.Joystick2)
-	Joystick3  = Joystick(glfw.Joystick3)
-	Joystick4  = Joystick(glfw.Joystick4)
-	Joystick5  = Joystick(glfw.Joystick5)
-	Joystick6  = Joystick(glfw.Joystick6)
-	Joystick7  = Joystick(glfw.Joystick7)
-	Joystick8  = Joystick(glfw.Joystick8)
-	Joystick9  = Joystick(glfw.Joystick9)
-	Joystick10 = Joystick(glfw.Joystick10)
-	Joystick11 = Joystick(glfw.Joystick11)
-	Joystick12 = Joystick(glfw.Joystick12)
-	Joystick13 = Joystick(glfw.Joystick13)
-	Joystick14 = Joystick(glfw.Joystick14)
-	Joystick15 = Joystick(glfw.Joystick15)
-	Joystick16 = Joystick(glfw.Joystick16)
-
-	JoystickLast = Joystick(glfw.JoystickLast)
-)
-
 // JoystickPresent returns if the joystick is currently connected.
 //
 // This API is experimental.
@@ -90,35 +61,6 @@ func (w *Window) JoystickAxis(js Joystick, axis int) float64 {
 	return w.currJoy.getAxis(js, axis)
 }
 
-// Used internally during Window.UpdateInput to update the state of the joysticks.
-func (w *Window) updateJoystickInput() {
-	for js := Joystick1; js <= JoystickLast; js++ {
-		// Determine and store if the joystick was connected
-		joystickPresent := glfw.JoystickPresent(glfw.Joystick(js))
-		w.tempJoy.connected[js] = joystickPresent
-
-		if joystickPresent {
-			w.tempJoy.buttons[js] = glfw.GetJoystickButtons(glfw.Joystick(js))
-			w.tempJoy.axis[js] = glfw.GetJoystickAxes(glfw.Joystick(js))
-
-			if !w.currJoy.connected[js] {
-				// The joystick was recently connected, we get the name
-				w.tempJoy.name[js] = glfw.GetJoystickName(glfw.Joystick(js))
-			} else {
-				// Use the name from the previous one
-				w.tempJoy.name[js] = w.currJoy.name[js]
-			}
-		} else {
-			w.tempJoy.buttons[js] = []byte{}
-			w.tempJoy.axis[js] = []float32{}
-			w.tempJoy.name[js] = ""
-		}
-	}
-
-	w.prevJoy = w.currJoy
-	w.currJoy = w.tempJoy
-}
-
 type joystickState struct {
 	connected [JoystickLast + 1]bool
 	name      [JoystickLast + 1]string
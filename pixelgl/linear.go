@@ -0,0 +1,96 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"unsafe"
+
+	"github.com/faiface/mainthread"
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// SetLinear sets whether the Canvas blends the colors drawn onto it in linear RGB (linear light)
+// instead of sRGB. Blending in sRGB, which is the default, makes dark halos around the edges of
+// antialiased or smooth Pictures and muddy midtones in the gradients, linear blending fixes them.
+//
+// The content of a linear Canvas is still stored, read (Color, Pixels) and cleared in sRGB, so
+// the colors are converted on the input and the output only and the rest of Pixel keeps working
+// with sRGB colors. Only the colors of translucent pixels differ slightly, because the conversion
+// is done on the alpha-premultiplied colors. A linear Canvas can be drawn onto any other Canvas.
+//
+// Custom fragment shaders (see SetFragmentShader) have to do the conversion themselves: the input
+// colors are sRGB and the output is expected in linear RGB, if the uLinear uniform is non-zero.
+// Textures are already decoded into linear RGB, if the uTexLinear uniform is non-zero.
+//
+// Linear blending is not supported on OpenGL ES (see WindowConfig.GLES), SetLinear does nothing
+// there.
+func (c *Canvas) SetLinear(linear bool) {
+	c.gf.SetLinear(linear)
+}
+
+// Linear returns whether the Canvas blends the colors in linear RGB. See SetLinear.
+func (c *Canvas) Linear() bool {
+	return c.gf.Linear()
+}
+
+// SetLinear sets whether the GLFrame's texture is an sRGB texture, which converts the colors
+// blended into it and sampled from it to linear RGB. The content is preserved.
+//
+// OpenGL ES doesn't allow turning the conversion off, so SetLinear does nothing there.
+func (gf *GLFrame) SetLinear(linear bool) {
+	if linear == gf.linear {
+		return
+	}
+	flushDraws()
+	mainthread.Call(debugWrap(func() {
+		if gles {
+			return
+		}
+		pixels := framePixels(gf.frame)
+		setTextureFormat(gf.frame.Texture(), linear, pixels)
+		gf.linear = linear
+	}))
+}
+
+// Linear returns whether the GLFrame's texture is an sRGB texture. See SetLinear.
+func (gf *GLFrame) Linear() bool {
+	return gf.linear
+}
+
+// setTextureFormat redefines the texture as an sRGB texture if linear is true, or as a plain RGBA
+// texture otherwise, with the pixels as its content (or undefined content, if they're nil).
+//
+// must be manually called inside mainthread
+func setTextureFormat(tex Texture, linear bool, pixels []uint8) {
+	format := int32(gl.RGBA8)
+	if linear {
+		format = gl.SRGB8_ALPHA8
+	}
+	var ptr unsafe.Pointer
+	if len(pixels) > 0 {
+		ptr = gl.Ptr(pixels)
+	}
+
+	tex.Begin()
+	gl.TexImage2D(
+		gl.TEXTURE_2D, 0, format,
+		int32(tex.Width()), int32(tex.Height()),
+		0, gl.RGBA, gl.UNSIGNED_BYTE, ptr,
+	)
+	tex.End()
+}
+
+// linearPicture reports whether the texture of the GLPicture is an sRGB texture, which is decoded
+// into linear RGB when sampled.
+func linearPicture(p GLPicture) bool {
+	lp, ok := p.(interface{ Linear() bool })
+	return ok && lp.Linear()
+}
+
+func bool2int32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
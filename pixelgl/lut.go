@@ -0,0 +1,145 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"math"
+
+	"github.com/faiface/glhf"
+	"github.com/faiface/mainthread"
+	"github.com/faiface/pixel"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// LUTPicture is a GLPicture of a pixel.LUT for SetLUT. The slices of the LUT by the blue component
+// are laid next to each other, so the picture is Size*Size pixels wide and Size pixels high.
+type LUTPicture struct {
+	GLPicture
+	min, max mgl32.Vec3
+}
+
+// NewLUTPicture creates a LUTPicture of the LUT. The entries are stored in 8 bits per component.
+func NewLUTPicture(lut *pixel.LUT) *LUTPicture {
+	n := lut.Size
+	w, h := n*n, n
+	pixels := make([]uint8, 4*w*h)
+	q := func(v float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+	for b := 0; b < n; b++ {
+		for g := 0; g < n; g++ {
+			for r := 0; r < n; r++ {
+				rgb := lut.Table[(b*n+g)*n+r]
+				off := (g*w + b*n + r) * 4
+				pixels[off+0] = q(rgb[0])
+				pixels[off+1] = q(rgb[1])
+				pixels[off+2] = q(rgb[2])
+				pixels[off+3] = 255
+			}
+		}
+	}
+
+	var tex *glhf.Texture
+	mainthread.Call(debugWrap(func() {
+		// the entries are interpolated by the texture sampling
+		tex = glhf.NewTexture(w, h, true, pixels)
+	}))
+
+	vec3 := func(v [3]float64) mgl32.Vec3 {
+		return mgl32.Vec3{float32(v[0]), float32(v[1]), float32(v[2])}
+	}
+	return &LUTPicture{
+		GLPicture: &glPicture{
+			bounds: pixel.R(0, 0, float64(w), float64(h)),
+			tex:    tex,
+			pixels: pixels,
+			mem:    trackGPU(gpuPicture, textureBytes(w, h)),
+		},
+		min: vec3(lut.DomainMin),
+		max: vec3(lut.DomainMax),
+	}
+}
+
+// SetLUT sets the LUT used by LUTFragmentShader for the following draws. The uniforms of the shader
+// are set by SetLUT, so it must be called before SetFragmentShader for the first time.
+//
+// Set on the Canvas that everything is drawn onto, it works as the final color grading pass:
+//
+//   lut, err := pixel.LoadCubeLUT(file)
+//   if err != nil {
+//       panic(err)
+//   }
+//   graded := pixelgl.NewCanvas(win.Bounds())
+//   graded.SetLUT(pixelgl.NewLUTPicture(lut))
+//   graded.SetFragmentShader(pixelgl.LUTFragmentShader)
+//
+//   scene.Draw(graded, pixel.IM.Moved(graded.Bounds().Center()))
+//   graded.Draw(win, pixel.IM.Moved(win.Bounds().Center()))
+func (c *Canvas) SetLUT(lut *LUTPicture) {
+	c.SetUniformTexture("uLUT", lut)
+	c.SetUniform("uLUTMin", lut.min)
+	c.SetUniform("uLUTMax", lut.max)
+}
+
+// LUTFragmentShader is a fragment shader for a Canvas, which draws the same way as the default
+// one, but maps all colors drawn by the LUT set by SetLUT. It has the same effect as the LUT's
+// Apply on each pixel, except for the lower precision of the entries.
+const LUTFragmentShader = `
+#version 330 core
+
+in vec4  vColor;
+in vec2  vTexCoords;
+in float vIntensity;
+
+out vec4 fragColor;
+
+uniform vec4 uColorMask;
+uniform vec4 uTexBounds;
+uniform sampler2D uTexture;
+uniform sampler2D uLUT;
+uniform vec3 uLUTMin;
+uniform vec3 uLUTMax;
+uniform int uLinear;
+
+vec4 toLinear(vec4 c) {
+	if (c.a == 0.0) {
+		return c;
+	}
+	vec3 s = c.rgb / c.a;
+	vec3 l = mix(s / 12.92, pow((s + 0.055) / 1.055, vec3(2.4)), step(0.04045, s));
+	return vec4(l * c.a, c.a);
+}
+
+vec3 lookup(vec3 c) {
+	float n = float(textureSize(uLUT, 0).y);
+	vec3 p = clamp((c - uLUTMin) / (uLUTMax - uLUTMin), 0.0, 1.0) * (n - 1.0);
+
+	// the two slices by blue around the color, interpolated by the sampling inside each
+	float b0 = floor(p.b);
+	float b1 = min(b0 + 1.0, n - 1.0);
+	vec2 rg = (p.rg + 0.5) / vec2(n * n, n);
+	vec3 s0 = texture(uLUT, rg + vec2(b0 / n, 0.0)).rgb;
+	vec3 s1 = texture(uLUT, rg + vec2(b1 / n, 0.0)).rgb;
+	return mix(s0, s1, p.b - b0);
+}
+
+void main() {
+	if (vIntensity == 0.0) {
+		fragColor = vColor;
+	} else {
+		fragColor = vec4(0.0, 0.0, 0.0, 0.0);
+		fragColor += (1.0 - vIntensity) * vColor;
+		vec2 t = (vTexCoords - uTexBounds.xy) / uTexBounds.zw;
+		fragColor += vIntensity * vColor * texture(uTexture, t);
+	}
+	fragColor *= uColorMask;
+
+	if (fragColor.a > 0.0) {
+		fragColor.rgb = lookup(fragColor.rgb / fragColor.a) * fragColor.a;
+	}
+	if (uLinear != 0) {
+		fragColor = toLinear(fragColor);
+	}
+}
+`
@@ -0,0 +1,181 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"runtime"
+	"sync"
+)
+
+// GPUMemoryStats is an estimate of the GPU memory held by the live OpenGL objects of Pixel. The
+// sizes are computed from the dimensions of the objects, without the driver's padding and
+// bookkeeping, so they're lower than what the GPU really uses, but they grow with it.
+//
+// The objects are counted until they're garbage collected, so call runtime.GC before reading the
+// stats to find leaks:
+//
+//   runtime.GC()
+//   mem := pixelgl.GPUMemory()
+//   log.Printf("%d canvases, %d MiB in total", mem.Frames, mem.Total()>>20)
+type GPUMemoryStats struct {
+	// Pictures is the number of GLPictures with their own textures, such as those created by
+	// NewGLPicture, and PictureBytes is the size of the textures.
+	Pictures     int
+	PictureBytes int64
+
+	// Frames is the number of Canvases (including those of Windows) and other GLFrames, and
+	// FrameBytes is the size of their framebuffers.
+	Frames     int
+	FrameBytes int64
+
+	// Buffers is the number of GLTriangles and StorageBuffers, and BufferBytes is the size of their
+	// vertex and storage buffers.
+	Buffers     int
+	BufferBytes int64
+}
+
+// Total returns the total number of bytes of the stats.
+func (s GPUMemoryStats) Total() int64 {
+	return s.PictureBytes + s.FrameBytes + s.BufferBytes
+}
+
+// gpuKind is the kind of a gpuAlloc, one of the groups of GPUMemoryStats
+type gpuKind int
+
+const (
+	gpuPicture gpuKind = iota
+	gpuFrame
+	gpuBuffer
+	numGPUKinds
+)
+
+var gpuMemory struct {
+	mu     sync.Mutex
+	counts [numGPUKinds]int
+	bytes  [numGPUKinds]int64
+
+	budget int64
+	warn   func(GPUMemoryStats)
+	over   bool
+}
+
+// GPUMemory returns the estimate of the GPU memory held by Pixel, see GPUMemoryStats.
+func GPUMemory() GPUMemoryStats {
+	gpuMemory.mu.Lock()
+	defer gpuMemory.mu.Unlock()
+	return gpuMemoryStats()
+}
+
+// SetGPUMemoryBudget sets the number of bytes the Total of GPUMemory should stay within. The warn
+// function is called when it exceeds the budget, with the stats at that moment, on the goroutine
+// which allocated the memory. It's called again only after the Total drops within the budget and
+// exceeds it once more. A non-positive budget or a nil function disables the warning.
+//
+//   pixelgl.SetGPUMemoryBudget(256<<20, func(s pixelgl.GPUMemoryStats) {
+//       log.Printf("GPU memory over budget: %+v", s)
+//   })
+func SetGPUMemoryBudget(budget int64, warn func(GPUMemoryStats)) {
+	gpuMemory.mu.Lock()
+	gpuMemory.budget = budget
+	gpuMemory.warn = warn
+	gpuMemory.over = false
+	f, stats := checkGPUBudget()
+	gpuMemory.mu.Unlock()
+
+	if f != nil {
+		f(stats)
+	}
+}
+
+// PictureGPUMemory returns the estimated number of bytes of the texture of the GLPicture, e.g. of a
+// Canvas. GLPictures sharing a texture, like the Pictures of an atlas, all report its whole size.
+func PictureGPUMemory(p GLPicture) int64 {
+	tex := p.Texture()
+	if tex == nil {
+		return 0
+	}
+	return textureBytes(tex.Width(), tex.Height())
+}
+
+// textureBytes returns the size of an RGBA texture, in 8 bits per component both as RGBA and sRGB
+func textureBytes(w, h int) int64 {
+	return 4 * int64(w) * int64(h)
+}
+
+// must be called with the mutex locked
+func gpuMemoryStats() GPUMemoryStats {
+	return GPUMemoryStats{
+		Pictures:     gpuMemory.counts[gpuPicture],
+		PictureBytes: gpuMemory.bytes[gpuPicture],
+		Frames:       gpuMemory.counts[gpuFrame],
+		FrameBytes:   gpuMemory.bytes[gpuFrame],
+		Buffers:      gpuMemory.counts[gpuBuffer],
+		BufferBytes:  gpuMemory.bytes[gpuBuffer],
+	}
+}
+
+// checkGPUBudget returns the warning function and its stats if the budget was just exceeded, must
+// be called with the mutex locked
+func checkGPUBudget() (func(GPUMemoryStats), GPUMemoryStats) {
+	if gpuMemory.budget <= 0 || gpuMemory.warn == nil {
+		return nil, GPUMemoryStats{}
+	}
+	stats := gpuMemoryStats()
+	switch {
+	case stats.Total() <= gpuMemory.budget:
+		gpuMemory.over = false
+	case !gpuMemory.over:
+		gpuMemory.over = true
+		return gpuMemory.warn, stats
+	}
+	return nil, GPUMemoryStats{}
+}
+
+// gpuAlloc is the GPU memory of an OpenGL object counted in GPUMemory. It's uncounted when it's
+// garbage collected together with its owner, so that the owners don't need their own finalizers.
+type gpuAlloc struct {
+	kind  gpuKind
+	bytes int64
+	live  bool
+}
+
+// trackGPU starts counting the GPU memory of an object of the kind
+func trackGPU(kind gpuKind, bytes int64) *gpuAlloc {
+	a := &gpuAlloc{kind: kind}
+	a.set(bytes)
+	runtime.SetFinalizer(a, (*gpuAlloc).free)
+	return a
+}
+
+// set changes the size of the allocation
+func (a *gpuAlloc) set(bytes int64) {
+	gpuMemory.mu.Lock()
+	if !a.live {
+		gpuMemory.counts[a.kind]++
+		a.live = true
+	}
+	gpuMemory.bytes[a.kind] += bytes - a.bytes
+	a.bytes = bytes
+	f, stats := checkGPUBudget()
+	gpuMemory.mu.Unlock()
+
+	if f != nil {
+		f(stats)
+	}
+}
+
+// free stops counting the allocation
+func (a *gpuAlloc) free() {
+	gpuMemory.mu.Lock()
+	defer gpuMemory.mu.Unlock()
+	if !a.live {
+		return
+	}
+	gpuMemory.counts[a.kind]--
+	gpuMemory.bytes[a.kind] -= a.bytes
+	a.live = false
+	a.bytes = 0
+	// only re-arms the warning, the memory never grows here
+	checkGPUBudget()
+}
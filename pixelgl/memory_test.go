@@ -0,0 +1,68 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGPUAllocTotals(t *testing.T) {
+	before := GPUMemory()
+
+	pic := trackGPU(gpuPicture, textureBytes(4, 2))
+	frame := trackGPU(gpuFrame, textureBytes(8, 8))
+	buf := trackGPU(gpuBuffer, 0)
+
+	mem := GPUMemory()
+	assert.Equal(t, before.Pictures+1, mem.Pictures)
+	assert.Equal(t, before.PictureBytes+32, mem.PictureBytes)
+	assert.Equal(t, before.Frames+1, mem.Frames)
+	assert.Equal(t, before.FrameBytes+256, mem.FrameBytes)
+	assert.Equal(t, before.Buffers+1, mem.Buffers, "an empty buffer is counted too")
+	assert.Equal(t, before.Total()+288, mem.Total())
+
+	buf.set(100)
+	buf.set(60)
+	mem = GPUMemory()
+	assert.Equal(t, before.Buffers+1, mem.Buffers, "resizing doesn't count the buffer again")
+	assert.Equal(t, before.BufferBytes+60, mem.BufferBytes)
+
+	pic.free()
+	pic.free()
+	frame.free()
+	buf.free()
+	assert.Equal(t, before, GPUMemory(), "freeing twice uncounts once")
+}
+
+func TestGPUMemoryBudget(t *testing.T) {
+	before := GPUMemory().Total()
+	var warnings []GPUMemoryStats
+	SetGPUMemoryBudget(before+100, func(s GPUMemoryStats) {
+		warnings = append(warnings, s)
+	})
+	defer SetGPUMemoryBudget(0, nil)
+
+	a := trackGPU(gpuBuffer, 80)
+	assert.Empty(t, warnings)
+
+	b := trackGPU(gpuBuffer, 40)
+	if assert.Len(t, warnings, 1) {
+		assert.Equal(t, before+120, warnings[0].Total())
+	}
+
+	a.set(90)
+	assert.Len(t, warnings, 1, "warned only once while over the budget")
+
+	b.free()
+	a.set(100)
+	assert.Len(t, warnings, 1)
+
+	b = trackGPU(gpuBuffer, 1)
+	assert.Len(t, warnings, 2, "warned again after dropping within the budget")
+
+	a.free()
+	b.free()
+}
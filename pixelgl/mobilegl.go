@@ -0,0 +1,267 @@
+//go:build android || ios
+// +build android ios
+
+package pixelgl
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pkg/errors"
+	"golang.org/x/mobile/gl"
+)
+
+// the objects of gomobile's OpenGL ES, zero if there's none
+type (
+	glTex     = gl.Texture
+	glFBO     = gl.Framebuffer
+	glProgram = gl.Program
+	glUniform = gl.Uniform
+	glBuffer  = gl.Buffer
+	glVAO     = gl.VertexArray
+)
+
+// context is the OpenGL ES 3.0 context of the app, which holds all the Canvases and Pictures
+type context struct {
+	gl gl.Context3
+
+	// screen is the framebuffer of the screen, which isn't 0 on iOS
+	screen gl.Framebuffer
+
+	// names is the last ID given to a texture
+	names uint32
+}
+
+// newContext wraps the context gomobile draws the app by, which must be OpenGL ES 3.0
+func newContext(glctx gl.Context) (*context, error) {
+	gl3, ok := glctx.(gl.Context3)
+	if !ok {
+		return nil, errors.New("OpenGL ES 3.0 is not supported by the device")
+	}
+	gl3.Enable(glBlend)
+	gl3.PixelStorei(glUnpackAlignment, 1)
+	gl3.PixelStorei(glPackAlignment, 1)
+	return &context{
+		gl:     gl3,
+		screen: gl.Framebuffer{Value: uint32(gl3.GetInteger(glFramebufferBinding))},
+	}, nil
+}
+
+func (c *context) createTexture() glTex {
+	return c.gl.CreateTexture()
+}
+
+func (c *context) deleteTexture(t glTex) {
+	c.gl.DeleteTexture(t)
+}
+
+func (c *context) activeTexture(unit int) {
+	c.gl.ActiveTexture(gl.Enum(unit))
+}
+
+func (c *context) bindTexture(t glTex) {
+	c.gl.BindTexture(glTexture2D, t)
+}
+
+func (c *context) unbindTexture() {
+	c.gl.BindTexture(glTexture2D, gl.Texture{})
+}
+
+// texImage2D allocates the bound texture with the pixels, or transparent if they're nil
+func (c *context) texImage2D(width, height int, pixels []uint8) {
+	if pixels == nil {
+		// unlike WebGL, OpenGL ES leaves the pixels of a texture without data undefined
+		pixels = make([]uint8, 4*width*height)
+	}
+	c.gl.TexImage2D(glTexture2D, 0, glRGBA8, width, height, glRGBA, glUnsignedByte, pixels)
+}
+
+func (c *context) texSubImage2D(x, y, w, h int, pixels []uint8) {
+	c.gl.TexSubImage2D(glTexture2D, 0, x, y, w, h, glRGBA, glUnsignedByte, pixels)
+}
+
+func (c *context) texParameteri(pname, param int) {
+	c.gl.TexParameteri(glTexture2D, gl.Enum(pname), param)
+}
+
+func (c *context) createFramebuffer() glFBO {
+	return c.gl.CreateFramebuffer()
+}
+
+func (c *context) deleteFramebuffer(fb glFBO) {
+	c.gl.DeleteFramebuffer(fb)
+}
+
+func (c *context) bindFramebuffer(target int, fb glFBO) {
+	c.gl.BindFramebuffer(gl.Enum(target), fb)
+}
+
+// unbindFramebuffer binds the screen
+func (c *context) unbindFramebuffer() {
+	c.gl.BindFramebuffer(glFramebuffer, c.screen)
+}
+
+// bindScreen binds the screen to the target
+func (c *context) bindScreen(target int) {
+	c.gl.BindFramebuffer(gl.Enum(target), c.screen)
+}
+
+// framebufferTexture2D attaches the texture to the bound framebuffer
+func (c *context) framebufferTexture2D(t glTex) {
+	c.gl.FramebufferTexture2D(glFramebuffer, glColorAttachment0, glTexture2D, t, 0)
+}
+
+func (c *context) blitFramebuffer(sx0, sy0, sx1, sy1, dx0, dy0, dx1, dy1, filter int) {
+	c.gl.BlitFramebuffer(sx0, sy0, sx1, sy1, dx0, dy0, dx1, dy1, glColorBufferBit, gl.Enum(filter))
+}
+
+// readPixels returns the RGBA bytes of the rectangle of the bound framebuffer
+func (c *context) readPixels(x, y, w, h int) []uint8 {
+	pixels := make([]uint8, 4*w*h)
+	c.gl.ReadPixels(pixels, x, y, w, h, glRGBA, glUnsignedByte)
+	return pixels
+}
+
+func (c *context) enable(cap int) {
+	c.gl.Enable(gl.Enum(cap))
+}
+
+func (c *context) disable(cap int) {
+	c.gl.Disable(gl.Enum(cap))
+}
+
+func (c *context) scissor(x, y, w, h int) {
+	c.gl.Scissor(int32(x), int32(y), int32(w), int32(h))
+}
+
+func (c *context) viewport(x, y, w, h int) {
+	c.gl.Viewport(x, y, w, h)
+}
+
+func (c *context) clearColor(r, g, b, a float32) {
+	c.gl.ClearColor(r, g, b, a)
+}
+
+func (c *context) clear(mask int) {
+	c.gl.Clear(gl.Enum(mask))
+}
+
+func (c *context) blendFunc(src, dst int) {
+	c.gl.BlendFunc(gl.Enum(src), gl.Enum(dst))
+}
+
+// linkProgram compiles and links the shaders with the attributes of the Canvas, returning the
+// error logs of OpenGL ES if it fails
+func (c *context) linkProgram(vs, fs string) (glProgram, error) {
+	program := c.gl.CreateProgram()
+	for _, s := range []struct {
+		typ  gl.Enum
+		name string
+		src  string
+	}{
+		{glVertexShader, "vertex", vs},
+		{glFragmentShader, "fragment", fs},
+	} {
+		shader := c.gl.CreateShader(s.typ)
+		c.gl.ShaderSource(shader, s.src)
+		c.gl.CompileShader(shader)
+		if c.gl.GetShaderi(shader, glCompileStatus) == 0 {
+			log := c.gl.GetShaderInfoLog(shader)
+			c.gl.DeleteShader(shader)
+			c.gl.DeleteProgram(program)
+			return gl.Program{}, fmt.Errorf("error compiling %s shader: %s", s.name, log)
+		}
+		c.gl.AttachShader(program, shader)
+		c.gl.DeleteShader(shader)
+	}
+	for loc, name := range canvasAttributes {
+		c.gl.BindAttribLocation(program, gl.Attrib{Value: uint(loc)}, name)
+	}
+	c.gl.LinkProgram(program)
+	if c.gl.GetProgrami(program, glLinkStatus) == 0 {
+		log := c.gl.GetProgramInfoLog(program)
+		c.gl.DeleteProgram(program)
+		return gl.Program{}, fmt.Errorf("error linking shader program: %s", log)
+	}
+	return program, nil
+}
+
+func (c *context) deleteProgram(p glProgram) {
+	c.gl.DeleteProgram(p)
+}
+
+func (c *context) useProgram(p glProgram) {
+	c.gl.UseProgram(p)
+}
+
+// uniformLocation returns the location of the uniform, false if the program doesn't use it
+func (c *context) uniformLocation(p glProgram, name string) (glUniform, bool) {
+	loc := c.gl.GetUniformLocation(p, name)
+	return loc, loc.Value >= 0
+}
+
+// uniform sets the uniform at the location to the value, one of the types returned by
+// uniformValue. gomobile only has the square matrices, the other ones are ignored.
+func (c *context) uniform(loc glUniform, value interface{}) {
+	switch v := value.(type) {
+	case int32:
+		c.gl.Uniform1i(loc, int(v))
+	case float32:
+		c.gl.Uniform1f(loc, v)
+	case mgl32.Vec2:
+		c.gl.Uniform2f(loc, v[0], v[1])
+	case mgl32.Vec3:
+		c.gl.Uniform3f(loc, v[0], v[1], v[2])
+	case mgl32.Vec4:
+		c.gl.Uniform4f(loc, v[0], v[1], v[2], v[3])
+	case mgl32.Mat2:
+		c.gl.UniformMatrix2fv(loc, v[:])
+	case mgl32.Mat3:
+		c.gl.UniformMatrix3fv(loc, v[:])
+	case mgl32.Mat4:
+		c.gl.UniformMatrix4fv(loc, v[:])
+	}
+}
+
+func (c *context) createVertexArray() glVAO {
+	return c.gl.CreateVertexArray()
+}
+
+func (c *context) bindVertexArray(vao glVAO) {
+	c.gl.BindVertexArray(vao)
+}
+
+func (c *context) unbindVertexArray() {
+	c.gl.BindVertexArray(gl.VertexArray{})
+}
+
+func (c *context) createBuffer() glBuffer {
+	return c.gl.CreateBuffer()
+}
+
+// bindBuffer binds the buffer of the vertices
+func (c *context) bindBuffer(b glBuffer) {
+	c.gl.BindBuffer(glArrayBuffer, b)
+}
+
+// vertexAttribPointer enables the attribute at the location, size floats at the offset of each
+// vertex
+func (c *context) vertexAttribPointer(loc, size, offset int) {
+	attr := gl.Attrib{Value: uint(loc)}
+	c.gl.EnableVertexAttribArray(attr)
+	c.gl.VertexAttribPointer(attr, size, glFloat, false, 4*vertexStride, 4*offset)
+}
+
+// bufferData allocates size bytes of the bound buffer
+func (c *context) bufferData(size int) {
+	c.gl.BufferInit(glArrayBuffer, size, glDynamicDraw)
+}
+
+// bufferSubData copies the floats into the bound buffer from the offset in bytes
+func (c *context) bufferSubData(offset int, data []float32) {
+	c.gl.BufferSubData(glArrayBuffer, offset, floatBytes(data))
+}
+
+func (c *context) drawArrays(first, count int) {
+	c.gl.DrawArrays(glTriangles, first, count)
+}
@@ -0,0 +1,84 @@
+package pixelgl
+
+import "syscall/js"
+
+// Monitor represents the screen of the browser. The page only sees the screen its window is on.
+type Monitor struct{}
+
+// VideoMode represents all properties of a video mode and is
+// associated with a monitor if it is used in fullscreen mode.
+type VideoMode struct {
+	// Width is the width of the vide mode in pixels.
+	Width int
+	// Height is the height of the video mode in pixels.
+	Height int
+	// RefreshRate holds the refresh rate of the associated monitor in Hz.
+	RefreshRate int
+}
+
+// cssPixelsPerMillimeter is the resolution of CSS pixels, which are 1/96 of an inch
+const cssPixelsPerMillimeter = 96 / 25.4
+
+// assumedRefreshRate is the refresh rate reported for the screen, the browsers don't tell it
+const assumedRefreshRate = 60
+
+// PrimaryMonitor returns the screen of the browser.
+func PrimaryMonitor() *Monitor {
+	return &Monitor{}
+}
+
+// Monitors returns a slice with the screen of the browser.
+func Monitors() []*Monitor {
+	return []*Monitor{PrimaryMonitor()}
+}
+
+// Name returns a human-readable name of the Monitor.
+func (m *Monitor) Name() string {
+	return "screen"
+}
+
+// PhysicalSize returns the size of the display area of the Monitor in millimeters, assuming the
+// CSS resolution of 96 pixels per inch.
+func (m *Monitor) PhysicalSize() (width, height float64) {
+	screen := js.Global().Get("screen")
+	width = screen.Get("width").Float() / cssPixelsPerMillimeter
+	height = screen.Get("height").Float() / cssPixelsPerMillimeter
+	return
+}
+
+// Position returns the position of the upper-left corner of the Monitor in screen coordinates.
+func (m *Monitor) Position() (x, y float64) {
+	return 0, 0
+}
+
+// Size returns the resolution of the Monitor in pixels.
+func (m *Monitor) Size() (width, height float64) {
+	window := js.Global()
+	screen := window.Get("screen")
+	ratio := window.Get("devicePixelRatio").Float()
+	width = screen.Get("width").Float() * ratio
+	height = screen.Get("height").Float() * ratio
+	return
+}
+
+// BitDepth returns the number of bits per color of the Monitor.
+func (m *Monitor) BitDepth() (red, green, blue int) {
+	bits := js.Global().Get("screen").Get("colorDepth").Int() / 3
+	return bits, bits, bits
+}
+
+// RefreshRate returns the refresh frequency of the Monitor in Hz (refreshes/second). The browsers
+// don't tell it, so it's always 60.
+func (m *Monitor) RefreshRate() (rate float64) {
+	return assumedRefreshRate
+}
+
+// VideoModes returns the current video mode of the screen, a page can't change it.
+func (m *Monitor) VideoModes() (vmodes []VideoMode) {
+	width, height := m.Size()
+	return []VideoMode{{
+		Width:       int(width),
+		Height:      int(height),
+		RefreshRate: assumedRefreshRate,
+	}}
+}
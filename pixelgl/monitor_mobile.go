@@ -0,0 +1,81 @@
+//go:build android || ios
+// +build android ios
+
+package pixelgl
+
+// Monitor represents the screen of the phone.
+type Monitor struct{}
+
+// VideoMode represents all properties of a video mode and is
+// associated with a monitor if it is used in fullscreen mode.
+type VideoMode struct {
+	// Width is the width of the vide mode in pixels.
+	Width int
+	// Height is the height of the video mode in pixels.
+	Height int
+	// RefreshRate holds the refresh rate of the associated monitor in Hz.
+	RefreshRate int
+}
+
+// millimetersPerPt is the size of a typographic point, which is 1/72 of an inch
+const millimetersPerPt = 25.4 / 72
+
+// assumedRefreshRate is the refresh rate reported for the screen, gomobile doesn't tell it
+const assumedRefreshRate = 60
+
+// PrimaryMonitor returns the screen of the phone.
+func PrimaryMonitor() *Monitor {
+	return &Monitor{}
+}
+
+// Monitors returns a slice with the screen of the phone.
+func Monitors() []*Monitor {
+	return []*Monitor{PrimaryMonitor()}
+}
+
+// Name returns a human-readable name of the Monitor.
+func (m *Monitor) Name() string {
+	return "screen"
+}
+
+// PhysicalSize returns the size of the display area of the Monitor in millimeters, from the size
+// in points reported by the phone.
+func (m *Monitor) PhysicalSize() (width, height float64) {
+	theApp.mu.Lock()
+	defer theApp.mu.Unlock()
+	width = float64(theApp.size.WidthPt) * millimetersPerPt
+	height = float64(theApp.size.HeightPt) * millimetersPerPt
+	return
+}
+
+// Position returns the position of the upper-left corner of the Monitor in screen coordinates.
+func (m *Monitor) Position() (x, y float64) {
+	return 0, 0
+}
+
+// Size returns the resolution of the Monitor in pixels, which changes when the phone rotates.
+func (m *Monitor) Size() (width, height float64) {
+	b := theApp.screenBounds()
+	return b.W(), b.H()
+}
+
+// BitDepth returns the number of bits per color of the Monitor.
+func (m *Monitor) BitDepth() (red, green, blue int) {
+	return 8, 8, 8
+}
+
+// RefreshRate returns the refresh frequency of the Monitor in Hz (refreshes/second). gomobile
+// doesn't tell it, so it's always 60.
+func (m *Monitor) RefreshRate() (rate float64) {
+	return assumedRefreshRate
+}
+
+// VideoModes returns the current video mode of the screen, an app can't change it.
+func (m *Monitor) VideoModes() (vmodes []VideoMode) {
+	width, height := m.Size()
+	return []VideoMode{{
+		Width:       int(width),
+		Height:      int(height),
+		RefreshRate: assumedRefreshRate,
+	}}
+}
@@ -0,0 +1,106 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"runtime"
+
+	"github.com/faiface/mainthread"
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// OcclusionQuery counts the pixels actually drawn by the draws between Begin and End. It's useful to
+// skip expensive work, such as an effect, when a cheap draw of its bounding geometry produced no
+// pixels.
+//
+// Pixel draws without a depth test, so a pixel counts whenever it lies within the bounds of the
+// Target and isn't discarded by the fragment shader. That makes OcclusionQuery useful for checking
+// whether any geometry ended up on the screen after all transformations, and together with a
+// custom fragment shader that discards hidden pixels (e.g. by reading a mask), for any kind of
+// visibility test.
+//
+// The result becomes available once the GPU has executed the draws, usually a frame or two later.
+// Occlusion queries can't be nested.
+type OcclusionQuery struct {
+	// accessed only inside mainthread
+	id      uint32
+	pending bool
+	done    bool
+	samples int
+}
+
+// NewOcclusionQuery creates a new OcclusionQuery without a result.
+//
+// A Window must be created before calling this function.
+func NewOcclusionQuery() *OcclusionQuery {
+	oq := &OcclusionQuery{}
+	mainthread.Call(func() {
+		gl.GenQueries(1, &oq.id)
+	})
+	runtime.SetFinalizer(oq, (*OcclusionQuery).delete)
+	return oq
+}
+
+func (oq *OcclusionQuery) delete() {
+	mainthread.CallNonBlock(func() {
+		gl.DeleteQueries(1, &oq.id)
+	})
+}
+
+// must be manually called inside mainthread
+func occlusionTarget() uint32 {
+	// OpenGL ES only tells whether there were any pixels, not how many
+	if gles {
+		return gl.ANY_SAMPLES_PASSED
+	}
+	return gl.SAMPLES_PASSED
+}
+
+// Begin starts counting the drawn pixels. The previous result is discarded.
+func (oq *OcclusionQuery) Begin() {
+	flushDraws()
+	mainthread.CallNonBlock(debugWrap(func() {
+		oq.pending = false
+		oq.done = false
+		gl.BeginQuery(occlusionTarget(), oq.id)
+	}))
+}
+
+// End stops counting the drawn pixels.
+func (oq *OcclusionQuery) End() {
+	flushDraws()
+	mainthread.CallNonBlock(debugWrap(func() {
+		gl.EndQuery(occlusionTarget())
+		oq.pending = true
+	}))
+}
+
+// Result returns the number of pixels drawn between Begin and End. The second return value is false
+// if the result isn't available yet, in which case call Result again later (e.g. in the next frame).
+//
+// On OpenGL ES, the number is 1 if any pixels were drawn and 0 otherwise.
+func (oq *OcclusionQuery) Result() (samples int, ok bool) {
+	mainthread.Call(func() {
+		if oq.pending {
+			var available int32
+			gl.GetQueryObjectiv(oq.id, gl.QUERY_RESULT_AVAILABLE, &available)
+			if available != gl.FALSE {
+				var result uint32
+				gl.GetQueryObjectuiv(oq.id, gl.QUERY_RESULT, &result)
+				oq.samples = int(result)
+				oq.pending = false
+				oq.done = true
+			}
+		}
+		samples, ok = oq.samples, oq.done
+	})
+	return samples, ok
+}
+
+// Visible returns whether any pixels were drawn between Begin and End. The second return value is
+// false if the result isn't available yet.
+func (oq *OcclusionQuery) Visible() (visible bool, ok bool) {
+	samples, ok := oq.Result()
+	return samples > 0, ok
+}
@@ -0,0 +1,110 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"image/color"
+
+	"github.com/faiface/pixel"
+)
+
+// NewPalettes creates a GLPicture for SetPalette with the palettes as its rows, the first palette
+// on the top. The rows are as wide as the longest palette and the shorter ones are filled with the
+// transparent color.
+func NewPalettes(palettes ...[]color.Color) GLPicture {
+	w := 0
+	for _, p := range palettes {
+		if len(p) > w {
+			w = len(p)
+		}
+	}
+	h := len(palettes)
+
+	pd := pixel.MakePictureData(pixel.R(0, 0, float64(w), float64(h)))
+	for i, p := range palettes {
+		// the rows of a PictureData go from the bottom
+		row := pd.Pix[(h-1-i)*pd.Stride:]
+		for j, c := range p {
+			row[j] = color.RGBAModel.Convert(c).(color.RGBA)
+		}
+	}
+	return NewGLPicture(pd)
+}
+
+// SetPalette sets the palettes used by PaletteFragmentShader and the index of the palette, the row
+// of the palettes from the top, that the following draws use. The palettes are usually an indexed
+// image of palettes loaded with NewGLPicture, or made by NewPalettes.
+//
+// Changing the palette between draws switches the colors of single sprites, e.g. for team colors
+// and character variants. The uniforms of the shader are set by SetPalette, so it must be called
+// before SetFragmentShader for the first time.
+//
+//   canvas.SetPalette(teams, 0)
+//   canvas.SetFragmentShader(pixelgl.PaletteFragmentShader)
+//
+//   for _, unit := range units {
+//       canvas.SetPalette(teams, unit.Team)
+//       unit.Sprite.Draw(canvas, unit.Matrix)
+//   }
+func (c *Canvas) SetPalette(palettes GLPicture, index int) {
+	c.SetUniformTexture("uPalettes", palettes)
+	c.SetUniform("uPalette", int32(index))
+}
+
+// PaletteFragmentShader is a fragment shader for a Canvas, which draws Pictures of color indices
+// with the colors of a palette set by SetPalette.
+//
+// The index of a pixel is its red component (usually a grayscale image), scaled to the width of
+// the palettes: 0 is the first color and 1 the last one. For palettes of 4 colors, the indices are
+// the shades 0, 1/3, 2/3 and 1. The alpha of the pixels is kept, so the Pictures can have
+// translucent edges. The Canvas should not be smooth (see SetSmooth), interpolated indices would
+// pick wrong colors.
+//
+// Triangles with zero Intensity are drawn the same way as with the default shader, so other simple
+// shapes can be drawn with it as well.
+const PaletteFragmentShader = `
+#version 330 core
+
+in vec4  vColor;
+in vec2  vTexCoords;
+in float vIntensity;
+
+out vec4 fragColor;
+
+uniform vec4 uColorMask;
+uniform vec4 uTexBounds;
+uniform sampler2D uTexture;
+uniform sampler2D uPalettes;
+uniform int uPalette;
+uniform int uLinear;
+
+vec4 toLinear(vec4 c) {
+	if (c.a == 0.0) {
+		return c;
+	}
+	vec3 s = c.rgb / c.a;
+	vec3 l = mix(s / 12.92, pow((s + 0.055) / 1.055, vec3(2.4)), step(0.04045, s));
+	return vec4(l * c.a, c.a);
+}
+
+void main() {
+	vec4 color = vColor;
+	if (vIntensity != 0.0) {
+		vec2 t = (vTexCoords - uTexBounds.xy) / uTexBounds.zw;
+		vec4 tex = texture(uTexture, t);
+		vec4 pal = vec4(0.0, 0.0, 0.0, 0.0);
+		if (tex.a > 0.0) {
+			ivec2 size = textureSize(uPalettes, 0);
+			int x = int(round(clamp(tex.r / tex.a, 0.0, 1.0) * float(size.x - 1)));
+			int y = clamp(size.y - 1 - uPalette, 0, size.y - 1);
+			pal = texelFetch(uPalettes, ivec2(x, y), 0) * tex.a;
+		}
+		color = mix(color, color * pal, vIntensity);
+	}
+	fragColor = color * uColorMask;
+	if (uLinear != 0) {
+		fragColor = toLinear(fragColor);
+	}
+}
+`
@@ -0,0 +1,112 @@
+//go:build js || android || ios
+// +build js android ios
+
+package pixelgl
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// GLPicture is a pixel.PictureColor with a Texture. All Pictures of the package, and the
+// Pictures of other packages implementing it, are drawn by the Canvases without uploading them
+// again.
+type GLPicture interface {
+	pixel.PictureColor
+	Texture() Texture
+}
+
+// NewGLPicture creates a new GLPicture with it's own static OpenGL ES texture. This function always
+// allocates a new texture that cannot (shouldn't) be further modified. Like NewCanvas, it panics
+// if it's called before NewWindow.
+func NewGLPicture(p pixel.Picture) GLPicture {
+	return newPicture(mustContext("NewGLPicture"), p)
+}
+
+// picture is a Picture uploaded into its own texture, which isn't modified any further
+type picture struct {
+	bounds pixel.Rect
+	tex    *texture
+	pixels []uint8
+}
+
+// newPicture uploads the Picture into a new texture
+func newPicture(ctx *context, p pixel.Picture) *picture {
+	bounds := p.Bounds()
+	_, _, bw, bh := intBounds(bounds)
+	pixels := picturePixels(p)
+	return &picture{
+		bounds: bounds,
+		tex:    ctx.newTexture(bw, bh, pixels),
+		pixels: pixels,
+	}
+}
+
+// picturePixels returns the RGBA bytes of the Picture's pixels within its bounds, the bottom row
+// first, as uploaded to a texture.
+func picturePixels(p pixel.Picture) []uint8 {
+	bounds := p.Bounds()
+	bx, by, bw, bh := intBounds(bounds)
+
+	pixels := make([]uint8, 4*bw*bh)
+
+	if pd, ok := p.(*pixel.PictureData); ok {
+		// PictureData short path
+		for y := 0; y < bh; y++ {
+			for x := 0; x < bw; x++ {
+				rgba := pd.Pix[y*pd.Stride+x]
+				off := (y*bw + x) * 4
+				pixels[off+0] = rgba.R
+				pixels[off+1] = rgba.G
+				pixels[off+2] = rgba.B
+				pixels[off+3] = rgba.A
+			}
+		}
+	} else if p, ok := p.(pixel.PictureColor); ok {
+		for y := 0; y < bh; y++ {
+			for x := 0; x < bw; x++ {
+				at := pixel.V(
+					math.Max(float64(bx+x), bounds.Min.X),
+					math.Max(float64(by+y), bounds.Min.Y),
+				)
+				color := p.Color(at)
+				off := (y*bw + x) * 4
+				pixels[off+0] = uint8(color.R * 255)
+				pixels[off+1] = uint8(color.G * 255)
+				pixels[off+2] = uint8(color.B * 255)
+				pixels[off+3] = uint8(color.A * 255)
+			}
+		}
+	}
+	return pixels
+}
+
+func (p *picture) Bounds() pixel.Rect {
+	return p.bounds
+}
+
+func (p *picture) Texture() Texture {
+	return p.tex
+}
+
+func (p *picture) Color(at pixel.Vec) pixel.RGBA {
+	return pixelColor(p.pixels, p.bounds, at)
+}
+
+// pixelColor returns the color at the position of the RGBA bytes of the pixels within the bounds,
+// the bottom row first
+func pixelColor(pixels []uint8, bounds pixel.Rect, at pixel.Vec) pixel.RGBA {
+	if !bounds.Contains(at) {
+		return pixel.Alpha(0)
+	}
+	bx, by, bw, _ := intBounds(bounds)
+	x, y := int(at.X)-bx, int(at.Y)-by
+	off := y*bw + x
+	return pixel.RGBA{
+		R: float64(pixels[off*4+0]) / 255,
+		G: float64(pixels[off*4+1]) / 255,
+		B: float64(pixels[off*4+2]) / 255,
+		A: float64(pixels[off*4+3]) / 255,
+	}
+}
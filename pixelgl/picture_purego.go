@@ -0,0 +1,106 @@
+//go:build purego && !js && !android && !ios
+// +build purego,!js,!android,!ios
+
+package pixelgl
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/faiface/pixel"
+)
+
+// Texture is the RGBA pixels of a GLPicture, alpha-premultiplied, the bottom row first. With the
+// purego build tag, the textures are in memory and the Begin and the End methods do nothing.
+type Texture interface {
+	ID() uint32
+	Width() int
+	Height() int
+	Smooth() bool
+	SetSmooth(smooth bool)
+	Pixels(x, y, w, h int) []uint8
+	SetPixels(x, y, w, h int, pixels []uint8)
+	Begin()
+	End()
+}
+
+// GLPicture is a pixel.PictureColor with a Texture. The Canvases draw it by its colors.
+type GLPicture interface {
+	pixel.PictureColor
+	Texture() Texture
+}
+
+// textureIDs is the last ID given to a texture
+var textureIDs uint32
+
+// texture is a Texture in memory
+type texture struct {
+	id            uint32
+	width, height int
+	smooth        bool
+	pixels        []uint8
+}
+
+var _ Texture = (*texture)(nil)
+
+func (t *texture) ID() uint32            { return t.id }
+func (t *texture) Width() int            { return t.width }
+func (t *texture) Height() int           { return t.height }
+func (t *texture) Smooth() bool          { return t.smooth }
+func (t *texture) SetSmooth(smooth bool) { t.smooth = smooth }
+func (t *texture) Begin()                {}
+func (t *texture) End()                  {}
+
+func (t *texture) Pixels(x, y, w, h int) []uint8 {
+	pixels := make([]uint8, 0, 4*w*h)
+	for row := y; row < y+h; row++ {
+		off := 4 * (row*t.width + x)
+		pixels = append(pixels, t.pixels[off:off+4*w]...)
+	}
+	return pixels
+}
+
+func (t *texture) SetPixels(x, y, w, h int, pixels []uint8) {
+	if len(pixels) != w*h*4 {
+		panic(fmt.Errorf("(%T).SetPixels: invalid pixels len", t))
+	}
+	for row := 0; row < h; row++ {
+		off := 4 * ((y+row)*t.width + x)
+		copy(t.pixels[off:off+4*w], pixels[4*w*row:4*w*(row+1)])
+	}
+}
+
+// picture is a Picture copied into memory with its Texture
+type picture struct {
+	pd  *pixel.PictureData
+	tex *texture
+}
+
+// NewGLPicture creates a new GLPicture with a copy of the Picture's pixels, which cannot
+// (shouldn't) be further modified.
+func NewGLPicture(p pixel.Picture) GLPicture {
+	pd := pixel.PictureDataFromPicture(p)
+	_, _, w, h := intBounds(pd.Bounds())
+	tex := &texture{
+		id:     atomic.AddUint32(&textureIDs, 1),
+		width:  w,
+		height: h,
+		pixels: make([]uint8, 0, 4*w*h),
+	}
+	for _, c := range pd.Pix {
+		tex.pixels = append(tex.pixels, c.R, c.G, c.B, c.A)
+	}
+	return &picture{pd: pd, tex: tex}
+}
+
+func (p *picture) Bounds() pixel.Rect {
+	return p.pd.Bounds()
+}
+
+func (p *picture) Color(at pixel.Vec) pixel.RGBA {
+	return p.pd.Color(at)
+}
+
+func (p *picture) Texture() Texture {
+	return p.tex
+}
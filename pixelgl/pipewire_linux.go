@@ -0,0 +1,173 @@
+//go:build pipewire && !android && !purego
+// +build pipewire,!android,!purego
+
+package pixelgl
+
+/*
+#cgo pkg-config: libpipewire-0.3
+
+#include <stdlib.h>
+#include <string.h>
+#include <pipewire/pipewire.h>
+#include <spa/param/video/format-utils.h>
+#include <spa/pod/builder.h>
+
+typedef struct {
+	struct pw_thread_loop *loop;
+	struct pw_stream *stream;
+	int width, height;
+	// frame is the last frame sent, the top row first
+	uint8_t *frame;
+} pixelPipeWire;
+
+static const struct spa_pod *pixelPipeWireFormat(struct spa_pod_builder *b, int width, int height) {
+	return spa_format_video_raw_build(b, SPA_PARAM_EnumFormat,
+		&SPA_VIDEO_INFO_RAW_INIT(
+			.format = SPA_VIDEO_FORMAT_RGBA,
+			.size = SPA_RECTANGLE(width, height),
+			.framerate = SPA_FRACTION(0, 1)));
+}
+
+static void pixelPipeWireParamChanged(void *data, uint32_t id, const struct spa_pod *param) {
+	pixelPipeWire *p = data;
+	if (param == NULL || id != SPA_PARAM_Format) {
+		return;
+	}
+	uint8_t buffer[1024];
+	struct spa_pod_builder b = SPA_POD_BUILDER_INIT(buffer, sizeof(buffer));
+	int stride = 4 * p->width;
+	const struct spa_pod *params[1];
+	params[0] = spa_pod_builder_add_object(&b,
+		SPA_TYPE_OBJECT_ParamBuffers, SPA_PARAM_Buffers,
+		SPA_PARAM_BUFFERS_buffers, SPA_POD_CHOICE_RANGE_Int(2, 1, 8),
+		SPA_PARAM_BUFFERS_blocks, SPA_POD_Int(1),
+		SPA_PARAM_BUFFERS_size, SPA_POD_Int(stride * p->height),
+		SPA_PARAM_BUFFERS_stride, SPA_POD_Int(stride));
+	pw_stream_update_params(p->stream, params, 1);
+}
+
+static void pixelPipeWireProcess(void *data) {
+	pixelPipeWire *p = data;
+	struct pw_buffer *b = pw_stream_dequeue_buffer(p->stream);
+	if (b == NULL) {
+		return;
+	}
+	struct spa_data *d = &b->buffer->datas[0];
+	int size = 4 * p->width * p->height;
+	if (d->data != NULL && d->maxsize >= (uint32_t)size) {
+		memcpy(d->data, p->frame, size);
+		d->chunk->offset = 0;
+		d->chunk->stride = 4 * p->width;
+		d->chunk->size = size;
+	}
+	pw_stream_queue_buffer(p->stream, b);
+}
+
+static const struct pw_stream_events pixelPipeWireEvents = {
+	PW_VERSION_STREAM_EVENTS,
+	.param_changed = pixelPipeWireParamChanged,
+	.process = pixelPipeWireProcess,
+};
+
+static void pixelPipeWireRelease(pixelPipeWire *p) {
+	if (p->loop != NULL) {
+		pw_thread_loop_stop(p->loop);
+	}
+	if (p->stream != NULL) {
+		pw_stream_destroy(p->stream);
+	}
+	if (p->loop != NULL) {
+		pw_thread_loop_destroy(p->loop);
+	}
+	free(p->frame);
+	free(p);
+}
+
+static pixelPipeWire *pixelPipeWireNew(const char *name, int width, int height) {
+	pw_init(NULL, NULL);
+	pixelPipeWire *p = calloc(1, sizeof(pixelPipeWire));
+	p->width = width;
+	p->height = height;
+	p->frame = calloc(4 * width * height, 1);
+	p->loop = pw_thread_loop_new(name, NULL);
+	if (p->loop == NULL) {
+		goto fail;
+	}
+	p->stream = pw_stream_new_simple(pw_thread_loop_get_loop(p->loop), name,
+		pw_properties_new(PW_KEY_MEDIA_CLASS, "Video/Source", PW_KEY_NODE_NAME, name, NULL),
+		&pixelPipeWireEvents, p);
+	if (p->stream == NULL) {
+		goto fail;
+	}
+	uint8_t buffer[1024];
+	struct spa_pod_builder b = SPA_POD_BUILDER_INIT(buffer, sizeof(buffer));
+	const struct spa_pod *params[1];
+	params[0] = pixelPipeWireFormat(&b, width, height);
+	if (pw_stream_connect(p->stream, PW_DIRECTION_OUTPUT, PW_ID_ANY,
+		PW_STREAM_FLAG_DRIVER | PW_STREAM_FLAG_MAP_BUFFERS, params, 1) < 0) {
+		goto fail;
+	}
+	if (pw_thread_loop_start(p->loop) < 0) {
+		goto fail;
+	}
+	return p;
+fail:
+	pixelPipeWireRelease(p);
+	return NULL;
+}
+
+// pixelPipeWireSend copies the frame, which has the bottom row first, and drives the graph
+static void pixelPipeWireSend(pixelPipeWire *p, const uint8_t *pixels, int width, int height) {
+	pw_thread_loop_lock(p->loop);
+	if (width != p->width || height != p->height) {
+		p->width = width;
+		p->height = height;
+		free(p->frame);
+		p->frame = calloc(4 * width * height, 1);
+		uint8_t buffer[1024];
+		struct spa_pod_builder b = SPA_POD_BUILDER_INIT(buffer, sizeof(buffer));
+		const struct spa_pod *params[1];
+		params[0] = pixelPipeWireFormat(&b, width, height);
+		pw_stream_update_params(p->stream, params, 1);
+	}
+	int stride = 4 * width;
+	for (int y = 0; y < height; y++) {
+		memcpy(p->frame + y*stride, pixels + (height-1-y)*stride, stride);
+	}
+	pw_stream_trigger_process(p->stream);
+	pw_thread_loop_unlock(p->loop);
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// pipeWireSender shares the frames by a PipeWire video source stream. PipeWire takes the frames in
+// the shared memory, so they're read back from the texture and copied on the CPU.
+type pipeWireSender struct {
+	p *C.pixelPipeWire
+}
+
+func newFrameSender(name string, width, height int) (frameSender, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	p := C.pixelPipeWireNew(cname, C.int(width), C.int(height))
+	if p == nil {
+		return nil, errors.New("failed to create PipeWire stream")
+	}
+	return &pipeWireSender{p: p}, nil
+}
+
+func (s *pipeWireSender) send(texture uint32, width, height int) {
+	tex := &externalTexture{id: texture, width: width, height: height}
+	pixels := tex.Pixels(0, 0, width, height)
+	C.pixelPipeWireSend(s.p, (*C.uint8_t)(unsafe.Pointer(&pixels[0])), C.int(width), C.int(height))
+}
+
+func (s *pipeWireSender) close() {
+	C.pixelPipeWireRelease(s.p)
+}
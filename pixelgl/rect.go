@@ -0,0 +1,27 @@
+package pixelgl
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+func intBounds(bounds pixel.Rect) (x, y, w, h int) {
+	x0 := int(math.Floor(bounds.Min.X))
+	y0 := int(math.Floor(bounds.Min.Y))
+	x1 := int(math.Ceil(bounds.Max.X))
+	y1 := int(math.Ceil(bounds.Max.Y))
+	return x0, y0, x1 - x0, y1 - y0
+}
+
+// frameSize returns the size of the framebuffer of the bounds, at least one pixel
+func frameSize(bounds pixel.Rect) (w, h int) {
+	_, _, w, h = intBounds(bounds)
+	if w <= 0 {
+		w = 1
+	}
+	if h <= 0 {
+		h = 1
+	}
+	return w, h
+}
@@ -0,0 +1,125 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"image"
+
+	"github.com/faiface/mainthread"
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// Screenshot returns the content of the Window as it was last drawn, the top row first. The pixels
+// are opaque, composed over black as they appear on the screen.
+//
+// The Window's framebuffer is read on the main thread, so Screenshot blocks until all the previous
+// drawing is done.
+func (w *Window) Screenshot() *image.RGBA {
+	var (
+		pixels        []uint8
+		width, height int
+	)
+	flushDraws()
+	mainthread.Call(debugWrap(func() {
+		pixels, width, height = w.screenshotPixels()
+	}))
+	return screenshotImage(pixels, width, height)
+}
+
+// ScreenshotAsync takes a screenshot of the Window like Screenshot, but returns immediately. The
+// screenshot is passed to the done function from another goroutine once it's read.
+//
+// The framebuffer is copied into a pixel buffer on the GPU, and the pixels are read from it by one
+// of the following calls to Update, once the GPU has finished the copy. Neither the frame nor the
+// caller waits for the GPU, e.g. when recording a screenshot every few frames. On OpenGL ES 2.0,
+// which has no pixel buffers, the framebuffer is read right away on the main thread, like by
+// Screenshot.
+func (w *Window) ScreenshotAsync(done func(img *image.RGBA)) {
+	flushDraws()
+	mainthread.CallNonBlock(debugWrap(func() {
+		if gles2 {
+			pixels, width, height := w.screenshotPixels()
+			go done(screenshotImage(pixels, width, height))
+			return
+		}
+		w.readScreenshot(done)
+	}))
+}
+
+// pendingScreenshot is a screenshot being copied into a pixel buffer on the GPU, which is done
+// when the fence is signaled
+type pendingScreenshot struct {
+	pbo           uint32
+	fence         uintptr
+	width, height int
+	done          func(img *image.RGBA)
+}
+
+// readScreenshot starts copying the framebuffer of the Window into a pixel buffer
+//
+// must be manually called inside mainthread
+func (w *Window) readScreenshot(done func(img *image.RGBA)) {
+	frame := w.canvas.gf.frame
+	s := pendingScreenshot{
+		width:  frame.Texture().Width(),
+		height: frame.Texture().Height(),
+		done:   done,
+	}
+	gl.GenBuffers(1, &s.pbo)
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, s.pbo)
+	gl.BufferData(gl.PIXEL_PACK_BUFFER, 4*s.width*s.height, nil, gl.STREAM_READ)
+	frame.Begin()
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(
+		0, 0,
+		int32(s.width), int32(s.height),
+		gl.RGBA, gl.UNSIGNED_BYTE,
+		gl.PtrOffset(0),
+	)
+	frame.End()
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+	s.fence = gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0)
+	w.screenshots = append(w.screenshots, s)
+}
+
+// finishScreenshots passes the pending screenshots the GPU has finished copying to their
+// functions. If wait is true, it waits for all of them.
+//
+// must be manually called inside mainthread
+func (w *Window) finishScreenshots(wait bool) {
+	pending := w.screenshots[:0]
+	for _, s := range w.screenshots {
+		// a zero timeout only checks the fence
+		status := gl.ClientWaitSync(s.fence, gl.SYNC_FLUSH_COMMANDS_BIT, 0)
+		if !wait && status != gl.ALREADY_SIGNALED && status != gl.CONDITION_SATISFIED && status != gl.WAIT_FAILED {
+			pending = append(pending, s)
+			continue
+		}
+		s.finish()
+	}
+	w.screenshots = pending
+}
+
+// finish reads the pixels from the pixel buffer, waiting for the GPU if it's not done yet
+//
+// must be manually called inside mainthread
+func (s pendingScreenshot) finish() {
+	gl.DeleteSync(s.fence)
+	size := 4 * s.width * s.height
+	pixels := make([]uint8, size)
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, s.pbo)
+	if p := gl.MapBufferRange(gl.PIXEL_PACK_BUFFER, 0, size, gl.MAP_READ_BIT); p != nil {
+		copy(pixels, (*[1 << 30]uint8)(p)[:size:size])
+		gl.UnmapBuffer(gl.PIXEL_PACK_BUFFER)
+	}
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+	gl.DeleteBuffers(1, &s.pbo)
+	go s.done(screenshotImage(pixels, s.width, s.height))
+}
+
+// must be manually called inside mainthread
+func (w *Window) screenshotPixels() (pixels []uint8, width, height int) {
+	tex := w.canvas.Texture()
+	return framePixels(w.canvas.gf.frame), tex.Width(), tex.Height()
+}
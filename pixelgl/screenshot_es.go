@@ -0,0 +1,21 @@
+//go:build js || android || ios
+// +build js android ios
+
+package pixelgl
+
+import "image"
+
+// Screenshot returns the content of the Window as it was last drawn, the top row first. The pixels
+// are opaque, composed over black as they appear on the screen.
+func (w *Window) Screenshot() *image.RGBA {
+	tex := w.canvas.tex
+	return screenshotImage(w.canvas.Pixels(), tex.width, tex.height)
+}
+
+// ScreenshotAsync takes a screenshot of the Window like Screenshot and passes it to the done
+// function from another goroutine. OpenGL ES 3.0 can't read the pixels without waiting for the
+// GPU, so they're read right away.
+func (w *Window) ScreenshotAsync(done func(img *image.RGBA)) {
+	img := w.Screenshot()
+	go done(img)
+}
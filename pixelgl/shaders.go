@@ -0,0 +1,110 @@
+package pixelgl
+
+import "strings"
+
+// The shaders of the Canvases are written in GLSL 3.30 both for the desktop and the browser, where
+// they're translated to GLSL ES 3.00 by glsl300es.
+
+var baseCanvasVertexShader = `
+#version 330 core
+
+in vec2  aPosition;
+in vec4  aColor;
+in vec2  aTexCoords;
+in float aIntensity;
+
+out vec4  vColor;
+out vec2  vTexCoords;
+out float vIntensity;
+out vec2  vPosition;
+
+uniform mat3 uTransform;
+uniform vec4 uBounds;
+uniform float uDepth;
+
+void main() {
+	vec2 transPos = (uTransform * vec3(aPosition, 1.0)).xy;
+	vec2 normPos = (transPos - uBounds.xy) / uBounds.zw * 2.0 - vec2(1.0, 1.0);
+	gl_Position = vec4(normPos, uDepth, 1.0);
+	vColor = aColor;
+	vPosition = aPosition;
+	vTexCoords = aTexCoords;
+	vIntensity = aIntensity;
+}
+`
+
+var baseCanvasFragmentShader = `
+#version 330 core
+
+in vec4  vColor;
+in vec2  vTexCoords;
+in float vIntensity;
+
+out vec4 fragColor;
+
+uniform vec4 uColorMask;
+uniform vec4 uTexBounds;
+uniform sampler2D uTexture;
+uniform int uLinear;
+uniform int uTexLinear;
+
+// converts between the premultiplied sRGB and linear RGB
+vec4 toLinear(vec4 c) {
+	if (c.a == 0.0) {
+		return c;
+	}
+	vec3 s = c.rgb / c.a;
+	vec3 l = mix(s / 12.92, pow((s + 0.055) / 1.055, vec3(2.4)), step(0.04045, s));
+	return vec4(l * c.a, c.a);
+}
+
+vec4 toSRGB(vec4 c) {
+	if (c.a == 0.0) {
+		return c;
+	}
+	vec3 l = c.rgb / c.a;
+	vec3 s = mix(l * 12.92, 1.055 * pow(l, vec3(1.0 / 2.4)) - 0.055, step(0.0031308, l));
+	return vec4(s * c.a, c.a);
+}
+
+void main() {
+	// the colors are blended in linear RGB on a linear Canvas
+	vec4 color = vColor;
+	vec4 mask = uColorMask;
+	if (uLinear != 0) {
+		color = toLinear(color);
+		mask = toLinear(mask);
+	}
+
+	if (vIntensity == 0.0) {
+		fragColor = mask * color;
+	} else {
+		fragColor = vec4(0.0, 0.0, 0.0, 0.0);
+		fragColor += (1.0 - vIntensity) * color;
+		vec2 t = (vTexCoords - uTexBounds.xy) / uTexBounds.zw;
+		vec4 tex = texture(uTexture, t);
+		if (uLinear != uTexLinear) {
+			tex = uLinear != 0 ? toLinear(tex) : toSRGB(tex);
+		}
+		fragColor += vIntensity * color * tex;
+		fragColor *= mask;
+	}
+}
+`
+
+// glsl300es translates a GLSL 3.30 shader into GLSL ES 3.00, the version of OpenGL ES 3.0 and
+// WebGL2: the #version directive is replaced and a default float precision is declared, which
+// GLSL ES requires in fragment shaders.
+func glsl300es(src string) string {
+	const header = "#version 300 es\nprecision highp float;\n"
+
+	start := strings.Index(src, "#version")
+	if start < 0 {
+		return header + src
+	}
+	end := strings.IndexByte(src[start:], '\n')
+	if end < 0 {
+		return src[:start] + header
+	}
+	return src[:start] + header + src[start+end+1:]
+}
@@ -0,0 +1,27 @@
+//go:build spout && !purego
+// +build spout,!purego
+
+#include "SpoutLibrary.h"
+
+extern "C" {
+
+void *pixelSpoutNew(const char *name) {
+	SPOUTHANDLE spout = GetSpout();
+	if (spout == NULL) {
+		return NULL;
+	}
+	spout->SetSenderName(name);
+	return spout;
+}
+
+// the textures of Pixel have the bottom row first, so they're inverted for the DirectX senders
+int pixelSpoutSend(void *spout, unsigned int texture, unsigned int target, unsigned int width, unsigned int height) {
+	return ((SPOUTHANDLE)spout)->SendTexture(texture, target, width, height, true, 0);
+}
+
+void pixelSpoutRelease(void *spout) {
+	((SPOUTHANDLE)spout)->ReleaseSender();
+	((SPOUTHANDLE)spout)->Release();
+}
+
+}
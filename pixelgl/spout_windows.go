@@ -0,0 +1,46 @@
+//go:build spout && !purego
+// +build spout,!purego
+
+package pixelgl
+
+/*
+#cgo LDFLAGS: -lSpoutLibrary
+
+#include <stdlib.h>
+
+void *pixelSpoutNew(const char *name);
+int pixelSpoutSend(void *spout, unsigned int texture, unsigned int target, unsigned int width, unsigned int height);
+void pixelSpoutRelease(void *spout);
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/pkg/errors"
+)
+
+// spoutSender shares the frames by a Spout sender, see spout_windows.cpp. SpoutLibrary.h must be
+// on the include path of the C++ compiler, e.g. by CGO_CXXFLAGS.
+type spoutSender struct {
+	spout unsafe.Pointer
+}
+
+func newFrameSender(name string, width, height int) (frameSender, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	spout := C.pixelSpoutNew(cname)
+	if spout == nil {
+		return nil, errors.New("failed to load SpoutLibrary")
+	}
+	return &spoutSender{spout: spout}, nil
+}
+
+func (s *spoutSender) send(texture uint32, width, height int) {
+	C.pixelSpoutSend(s.spout, C.uint(texture), gl.TEXTURE_2D, C.uint(width), C.uint(height))
+}
+
+func (s *spoutSender) close() {
+	C.pixelSpoutRelease(s.spout)
+}
@@ -0,0 +1,70 @@
+//go:build syphon && !ios && !purego
+// +build syphon,!ios,!purego
+
+package pixelgl
+
+/*
+#cgo CFLAGS: -x objective-c -fobjc-arc -F/Library/Frameworks -DGL_SILENCE_DEPRECATION
+#cgo LDFLAGS: -F/Library/Frameworks -framework Foundation -framework OpenGL -framework Syphon
+
+#include <stdlib.h>
+#import <Foundation/Foundation.h>
+#import <OpenGL/OpenGL.h>
+#import <Syphon/Syphon.h>
+
+static void *pixelSyphonNew(const char *name) {
+	SyphonOpenGLServer *server = [[SyphonOpenGLServer alloc]
+		initWithName:[NSString stringWithUTF8String:name]
+		context:CGLGetCurrentContext()
+		options:nil];
+	return (__bridge_retained void *)server;
+}
+
+// the textures of Pixel have the bottom row first, which isn't flipped for OpenGL
+static void pixelSyphonSend(void *server, GLuint texture, GLenum target, int width, int height) {
+	@autoreleasepool {
+		[(__bridge SyphonOpenGLServer *)server publishFrameTexture:texture
+			textureTarget:target
+			imageRegion:NSMakeRect(0, 0, width, height)
+			textureDimensions:NSMakeSize(width, height)
+			flipped:NO];
+	}
+}
+
+static void pixelSyphonRelease(void *server) {
+	SyphonOpenGLServer *s = (__bridge_transfer SyphonOpenGLServer *)server;
+	[s stop];
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/pkg/errors"
+)
+
+// syphonSender shares the frames by a Syphon server in the OpenGL context of the Window. It needs
+// Syphon 5, installed in /Library/Frameworks.
+type syphonSender struct {
+	server unsafe.Pointer
+}
+
+func newFrameSender(name string, width, height int) (frameSender, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	server := C.pixelSyphonNew(cname)
+	if server == nil {
+		return nil, errors.New("failed to create Syphon server")
+	}
+	return &syphonSender{server: server}, nil
+}
+
+func (s *syphonSender) send(texture uint32, width, height int) {
+	C.pixelSyphonSend(s.server, C.GLuint(texture), gl.TEXTURE_2D, C.int(width), C.int(height))
+}
+
+func (s *syphonSender) close() {
+	C.pixelSyphonRelease(s.server)
+}
@@ -0,0 +1,112 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"fmt"
+
+	"github.com/faiface/glhf"
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// Texture is an OpenGL texture with alpha-premultiplied RGBA pixels, the bottom row first. The
+// Pictures and the Targets of the package hand out their textures as this interface, so that the
+// code drawing them doesn't depend on how they're created. *glhf.Texture implements it.
+//
+// The methods must be called inside mainthread, and the Pixels and the SetPixels methods are only
+// valid between Begin and End.
+type Texture interface {
+	ID() uint32
+	Width() int
+	Height() int
+	Smooth() bool
+	SetSmooth(smooth bool)
+	Pixels(x, y, w, h int) []uint8
+	SetPixels(x, y, w, h int, pixels []uint8)
+	Begin()
+	End()
+}
+
+// Framebuffer is an OpenGL framebuffer with a color Texture attached. It's what Canvas and GLFrame
+// draw onto.
+//
+// The methods must be called inside mainthread.
+type Framebuffer interface {
+	ID() uint32
+	Texture() Texture
+	Begin()
+	End()
+}
+
+var _ Texture = (*glhf.Texture)(nil)
+
+// glFramebuffer is the Framebuffer of a *glhf.Frame
+type glFramebuffer struct {
+	*glhf.Frame
+}
+
+func (f glFramebuffer) Texture() Texture {
+	return f.Frame.Texture()
+}
+
+// externalTexture is an OpenGL texture created outside of glhf, by its ID. Its pixels are never
+// copied, the texture's owner keeps it alive.
+type externalTexture struct {
+	id            uint32
+	width, height int
+	smooth        bool
+}
+
+func (et *externalTexture) ID() uint32   { return et.id }
+func (et *externalTexture) Width() int   { return et.width }
+func (et *externalTexture) Height() int  { return et.height }
+func (et *externalTexture) Smooth() bool { return et.smooth }
+
+func (et *externalTexture) SetSmooth(smooth bool) {
+	et.smooth = smooth
+	filter := int32(gl.NEAREST)
+	if smooth {
+		filter = gl.LINEAR
+	}
+	et.Begin()
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, filter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, filter)
+	et.End()
+}
+
+// Pixels reads the pixels through a temporary framebuffer, which works on OpenGL ES too.
+func (et *externalTexture) Pixels(x, y, w, h int) []uint8 {
+	var prev int32
+	gl.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &prev)
+	var fbo uint32
+	gl.GenFramebuffers(1, &fbo)
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, fbo)
+	gl.FramebufferTexture2D(gl.READ_FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, et.id, 0)
+
+	pixels := make([]uint8, w*h*4)
+	gl.ReadPixels(int32(x), int32(y), int32(w), int32(h), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(prev))
+	gl.DeleteFramebuffers(1, &fbo)
+	return pixels
+}
+
+func (et *externalTexture) SetPixels(x, y, w, h int, pixels []uint8) {
+	if len(pixels) != w*h*4 {
+		panic(fmt.Errorf("(%T).SetPixels: invalid pixels len", et))
+	}
+	gl.TexSubImage2D(
+		gl.TEXTURE_2D, 0,
+		int32(x), int32(y), int32(w), int32(h),
+		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels),
+	)
+}
+
+func (et *externalTexture) Begin() {
+	gl.BindTexture(gl.TEXTURE_2D, et.id)
+}
+
+func (et *externalTexture) End() {
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
@@ -0,0 +1,228 @@
+//go:build js || android || ios
+// +build js android ios
+
+package pixelgl
+
+import (
+	"fmt"
+
+	"github.com/faiface/pixel"
+)
+
+// vertexBuffer is an OpenGL ES buffer of vertices with its vertex array, shared by triangles and
+// their Slices
+type vertexBuffer struct {
+	vao glVAO
+	vbo glBuffer
+
+	// capacity is the number of vertices the buffer has room for
+	capacity int
+}
+
+// triangles are Triangles with their vertices in a vertexBuffer. They support TrianglesPosition,
+// TrianglesColor and TrianglesPicture.
+type triangles struct {
+	ctx  *context
+	buf  *vertexBuffer
+	data []float32
+
+	// offset is the index of the first vertex in the buffer, non-zero in a Slice
+	offset int
+	slice  bool
+}
+
+var (
+	_ pixel.TrianglesPosition = (*triangles)(nil)
+	_ pixel.TrianglesColor    = (*triangles)(nil)
+	_ pixel.TrianglesPicture  = (*triangles)(nil)
+)
+
+// newTriangles returns triangles initialized with the data from the supplied Triangles
+func newTriangles(ctx *context, t pixel.Triangles) *triangles {
+	buf := &vertexBuffer{
+		vao: ctx.createVertexArray(),
+		vbo: ctx.createBuffer(),
+	}
+	ctx.bindVertexArray(buf.vao)
+	ctx.bindBuffer(buf.vbo)
+	for _, attr := range []struct {
+		loc, size, offset int
+	}{
+		{canvasPosition, 2, 0},
+		{canvasColor, 4, 2},
+		{canvasTexCoords, 2, 6},
+		{canvasIntensity, 1, 8},
+	} {
+		ctx.vertexAttribPointer(attr.loc, attr.size, attr.offset)
+	}
+	ctx.unbindVertexArray()
+
+	gt := &triangles{ctx: ctx, buf: buf}
+	gt.SetLen(t.Len())
+	gt.Update(t)
+	return gt
+}
+
+// Len returns the number of vertices.
+func (gt *triangles) Len() int {
+	return len(gt.data) / vertexStride
+}
+
+// SetLen resizes the triangles to len, the new vertices are zero, white and without a Picture.
+func (gt *triangles) SetLen(length int) {
+	switch {
+	case length > gt.Len():
+		needAppend := length - gt.Len()
+		for i := 0; i < needAppend; i++ {
+			gt.data = append(gt.data,
+				0, 0,
+				1, 1, 1, 1,
+				0, 0,
+				0,
+			)
+		}
+	case length < gt.Len():
+		gt.data = gt.data[:length*vertexStride]
+		return
+	default:
+		return
+	}
+
+	if gt.slice {
+		gt.upload()
+		return
+	}
+	if length > gt.buf.capacity {
+		// the buffer only grows, so that the Slices stay within it
+		capacity := 2 * gt.buf.capacity
+		if capacity < length {
+			capacity = length
+		}
+		gt.buf.capacity = capacity
+		gt.ctx.bindBuffer(gt.buf.vbo)
+		gt.ctx.bufferData(4 * vertexStride * capacity)
+	}
+	gt.upload()
+}
+
+// Slice returns a sub-Triangles of the triangles in range [i, j), sharing their buffer.
+func (gt *triangles) Slice(i, j int) pixel.Triangles {
+	return &triangles{
+		ctx:    gt.ctx,
+		buf:    gt.buf,
+		data:   gt.data[i*vertexStride : j*vertexStride],
+		offset: gt.offset + i,
+		slice:  true,
+	}
+}
+
+func (gt *triangles) updateData(t pixel.Triangles) {
+	// triangles short path
+	if t, ok := t.(*triangles); ok {
+		copy(gt.data, t.data)
+		return
+	}
+
+	// TrianglesData short path
+	length := gt.Len()
+	if t, ok := t.(*pixel.TrianglesData); ok {
+		for i := 0; i < length; i++ {
+			var (
+				px, py = (*t)[i].Position.XY()
+				col    = (*t)[i].Color
+				tx, ty = (*t)[i].Picture.XY()
+				in     = (*t)[i].Intensity
+			)
+			d := gt.data[i*vertexStride : i*vertexStride+9]
+			d[0] = float32(px)
+			d[1] = float32(py)
+			d[2] = float32(col.R)
+			d[3] = float32(col.G)
+			d[4] = float32(col.B)
+			d[5] = float32(col.A)
+			d[6] = float32(tx)
+			d[7] = float32(ty)
+			d[8] = float32(in)
+		}
+		return
+	}
+
+	if t, ok := t.(pixel.TrianglesPosition); ok {
+		for i := 0; i < length; i++ {
+			px, py := t.Position(i).XY()
+			gt.data[i*vertexStride+0] = float32(px)
+			gt.data[i*vertexStride+1] = float32(py)
+		}
+	}
+	if t, ok := t.(pixel.TrianglesColor); ok {
+		for i := 0; i < length; i++ {
+			col := t.Color(i)
+			gt.data[i*vertexStride+2] = float32(col.R)
+			gt.data[i*vertexStride+3] = float32(col.G)
+			gt.data[i*vertexStride+4] = float32(col.B)
+			gt.data[i*vertexStride+5] = float32(col.A)
+		}
+	}
+	if t, ok := t.(pixel.TrianglesPicture); ok {
+		for i := 0; i < length; i++ {
+			pic, intensity := t.Picture(i)
+			gt.data[i*vertexStride+6] = float32(pic.X)
+			gt.data[i*vertexStride+7] = float32(pic.Y)
+			gt.data[i*vertexStride+8] = float32(intensity)
+		}
+	}
+}
+
+// Update copies vertex properties from the supplied Triangles into the triangles.
+//
+// The two Triangles (gt and t) must be of the same len.
+func (gt *triangles) Update(t pixel.Triangles) {
+	if gt.Len() != t.Len() {
+		panic(fmt.Errorf("(%T).Update: invalid triangles len", gt))
+	}
+	gt.updateData(t)
+	gt.upload()
+}
+
+// upload copies the vertices into the buffer
+func (gt *triangles) upload() {
+	if len(gt.data) == 0 {
+		return
+	}
+	gt.ctx.bindBuffer(gt.buf.vbo)
+	gt.ctx.bufferSubData(4*vertexStride*gt.offset, gt.data)
+}
+
+// Copy returns an independent copy of the triangles.
+func (gt *triangles) Copy() pixel.Triangles {
+	return newTriangles(gt.ctx, gt)
+}
+
+// Position returns the Position property of the i-th vertex.
+func (gt *triangles) Position(i int) pixel.Vec {
+	px := gt.data[i*vertexStride+0]
+	py := gt.data[i*vertexStride+1]
+	return pixel.V(float64(px), float64(py))
+}
+
+// Color returns the Color property of the i-th vertex.
+func (gt *triangles) Color(i int) pixel.RGBA {
+	r := gt.data[i*vertexStride+2]
+	g := gt.data[i*vertexStride+3]
+	b := gt.data[i*vertexStride+4]
+	a := gt.data[i*vertexStride+5]
+	return pixel.RGBA{
+		R: float64(r),
+		G: float64(g),
+		B: float64(b),
+		A: float64(a),
+	}
+}
+
+// Picture returns the Picture property of the i-th vertex.
+func (gt *triangles) Picture(i int) (pic pixel.Vec, intensity float64) {
+	tx := gt.data[i*vertexStride+6]
+	ty := gt.data[i*vertexStride+7]
+	intensity = float64(gt.data[i*vertexStride+8])
+	return pixel.V(float64(tx), float64(ty)), intensity
+}
@@ -0,0 +1,165 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
+package pixelgl
+
+import (
+	"github.com/faiface/glhf"
+	"github.com/faiface/mainthread"
+	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// uploadChunk is the number of bytes of an AsyncPicture uploaded by one call in mainthread, so
+// that the frames drawn in the meantime are delayed by a small copy at most.
+const uploadChunk = 256 * 1024
+
+// AsyncPicture is a GLPicture whose texture is uploaded in the background, so that creating large
+// Pictures in the middle of a game doesn't hitch the frame. Use NewGLPictureAsync to create one.
+//
+// The pixels of the Picture are converted on another goroutine and uploaded in small parts from a
+// pixel buffer, which is orphaned before each part, so that the driver never waits for the GPU to
+// finish with the previous one. The frames drawn in the meantime are interleaved with the parts.
+//
+// An AsyncPicture is fully transparent until it's ready, see Done.
+type AsyncPicture struct {
+	bounds pixel.Rect
+	frame  *glhf.Frame
+	pixels []uint8
+	done   chan struct{}
+	mem    *gpuAlloc
+}
+
+var _ GLPicture = (*AsyncPicture)(nil)
+
+// NewGLPictureAsync creates a new GLPicture like NewGLPicture, but returns immediately and uploads
+// the Picture's pixels in the background. The Picture must not be changed until the AsyncPicture
+// is ready.
+//
+//   pic := pixelgl.NewGLPictureAsync(level)
+//   ...
+//   select {
+//   case <-pic.Done():
+//       sprite.Draw(win, pixel.IM)
+//   default:
+//       loading.Draw(win, pixel.IM)
+//   }
+func NewGLPictureAsync(p pixel.Picture) *AsyncPicture {
+	ap := &AsyncPicture{
+		bounds: p.Bounds(),
+		done:   make(chan struct{}),
+	}
+	_, _, bw, bh := intBounds(ap.bounds)
+
+	// an empty framebuffer is only allocated, its texture is cleared on the GPU
+	mainthread.Call(debugWrap(func() {
+		ap.frame = glhf.NewFrame(bw, bh, false)
+		glhf.Bounds(0, 0, bw, bh)
+		ap.frame.Begin()
+		glhf.Clear(0, 0, 0, 0)
+		ap.frame.End()
+	}))
+	ap.mem = trackGPU(gpuPicture, textureBytes(bw, bh))
+
+	go ap.upload(p)
+	return ap
+}
+
+// upload converts and uploads the pixels of the Picture, then marks the AsyncPicture as done
+func (ap *AsyncPicture) upload(p pixel.Picture) {
+	pixels := picturePixels(p)
+	_, _, bw, bh := intBounds(ap.bounds)
+	stride := 4 * bw
+	rows := 1
+	if stride > 0 && uploadChunk/stride > 1 {
+		rows = uploadChunk / stride
+	}
+
+	var pbo uint32
+	if !gles2 {
+		mainthread.Call(func() {
+			gl.GenBuffers(1, &pbo)
+		})
+	}
+
+	for y := 0; y < bh; y += rows {
+		n := rows
+		if y+n > bh {
+			n = bh - y
+		}
+		part := pixels[y*stride : (y+n)*stride]
+
+		// a blocking call per part lets the drawing goroutine's calls run between the parts
+		mainthread.Call(debugWrap(func() {
+			defer beginPhase(pixel.PhaseUpload).End()
+
+			tex := ap.frame.Texture()
+			if gles2 {
+				// OpenGL ES 2.0 has no pixel buffers
+				tex.Begin()
+				tex.SetPixels(0, y, bw, n, part)
+				tex.End()
+				return
+			}
+
+			gl.BindBuffer(gl.PIXEL_UNPACK_BUFFER, pbo)
+			// passing the data orphans the previous storage of the buffer
+			gl.BufferData(gl.PIXEL_UNPACK_BUFFER, len(part), gl.Ptr(part), gl.STREAM_DRAW)
+			tex.Begin()
+			gl.TexSubImage2D(
+				gl.TEXTURE_2D, 0,
+				0, int32(y), int32(bw), int32(n),
+				gl.RGBA, gl.UNSIGNED_BYTE, gl.PtrOffset(0),
+			)
+			tex.End()
+			gl.BindBuffer(gl.PIXEL_UNPACK_BUFFER, 0)
+		}))
+	}
+
+	if !gles2 {
+		mainthread.CallNonBlock(func() {
+			gl.DeleteBuffers(1, &pbo)
+		})
+	}
+
+	ap.pixels = pixels
+	close(ap.done)
+}
+
+// Done returns a channel closed when the AsyncPicture is uploaded. From then on, it draws the
+// content of the Picture.
+func (ap *AsyncPicture) Done() <-chan struct{} {
+	return ap.done
+}
+
+// Ready returns whether the AsyncPicture is uploaded, see Done.
+func (ap *AsyncPicture) Ready() bool {
+	select {
+	case <-ap.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Bounds returns the bounds of the Picture.
+func (ap *AsyncPicture) Bounds() pixel.Rect {
+	return ap.bounds
+}
+
+// Texture returns the Texture the Picture is uploaded into.
+//
+// Implements GLPicture interface.
+func (ap *AsyncPicture) Texture() Texture {
+	return ap.frame.Texture()
+}
+
+// Color returns the color of the Picture's pixel under the specified position, or a transparent
+// color until the AsyncPicture is ready.
+func (ap *AsyncPicture) Color(at pixel.Vec) pixel.RGBA {
+	if !ap.Ready() {
+		return pixel.Alpha(0)
+	}
+	gp := glPicture{bounds: ap.bounds, pixels: ap.pixels}
+	return gp.Color(at)
+}
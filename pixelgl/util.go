@@ -1,15 +1,61 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
 package pixelgl
 
 import (
-	"math"
+	"context"
+	"unsafe"
 
 	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v4.3-core/gl"
 )
 
-func intBounds(bounds pixel.Rect) (x, y, w, h int) {
-	x0 := int(math.Floor(bounds.Min.X))
-	y0 := int(math.Floor(bounds.Min.Y))
-	x1 := int(math.Ceil(bounds.Max.X))
-	y1 := int(math.Ceil(bounds.Max.Y))
-	return x0, y0, x1 - x0, y1 - y0
+// beginPhase starts timing the Phase of Pixel's work in mainthread and labels it for pprof. The
+// main thread runs only the calls of mainthread, so there are no labels of the program to preserve.
+//
+// must be manually called inside mainthread
+func beginPhase(p pixel.Phase) pixel.Span {
+	return pixel.BeginPhaseContext(context.Background(), p)
+}
+
+// missingProc is the address procLoader gives go-gl for the functions the context doesn't have, so
+// that the loading goes on past them. Pixel never calls them, calling one would crash.
+var missingProc byte
+
+// procLoader returns a loader for InitWithProcAddrFunc of go-gl, which looks the functions up by
+// lookup. go-gl stops loading at the first function it can't find, so the missing ones are
+// resolved to missingProc and their names are added to missing instead.
+func procLoader(lookup func(name string) unsafe.Pointer, missing map[string]bool) func(name string) unsafe.Pointer {
+	return func(name string) unsafe.Pointer {
+		if p := lookup(name); p != nil {
+			return p
+		}
+		missing[name] = true
+		return unsafe.Pointer(&missingProc)
+	}
+}
+
+var gl43 struct {
+	checked   bool
+	supported bool
+}
+
+// initGL43 loads the OpenGL 4.3 functions and reports whether the current context supports them.
+//
+// must be manually called inside mainthread
+func initGL43() bool {
+	if gl43.checked {
+		return gl43.supported
+	}
+	gl43.checked = true
+
+	if err := gl.Init(); err != nil {
+		return false
+	}
+	var major, minor int32
+	gl.GetIntegerv(gl.MAJOR_VERSION, &major)
+	gl.GetIntegerv(gl.MINOR_VERSION, &minor)
+	gl43.supported = major > 4 || (major == 4 && minor >= 3)
+	return gl43.supported
 }
@@ -0,0 +1,131 @@
+package pixelgl
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// ViewportScaling is a way a Viewport scales its virtual resolution to the Window.
+type ViewportScaling int
+
+const (
+	// ScaleFit scales the virtual resolution as much as it fits into the Window, keeping the
+	// aspect ratio. The rest of the Window is filled with bars on the sides (pillarbox), or on the
+	// top and the bottom (letterbox).
+	ScaleFit ViewportScaling = iota
+
+	// ScaleInteger scales the virtual resolution by the largest whole number that fits into the
+	// Window, so that every virtual pixel is the same number of the Window's pixels. It's the
+	// crispest scaling for the pixel art, at the cost of wider bars. If the Window is smaller than
+	// the virtual resolution, it scales down the same way as ScaleFit.
+	ScaleInteger
+
+	// ScaleStretch stretches the virtual resolution over the whole Window, distorting it if the
+	// aspect ratios differ.
+	ScaleStretch
+)
+
+// Viewport renders a game in a fixed virtual resolution and presents it in a Window of any size.
+// Everything is drawn onto the Viewport's Canvas in the virtual resolution, which Present then
+// scales into the Window. The Window can be freely resized, the Viewport uses its current bounds.
+//
+//   vp := pixelgl.NewViewport(win, 320, 180)
+//   for !win.Closed() {
+//       vp.Canvas().Clear(colornames.Skyblue)
+//       world.Draw(vp.Canvas())
+//       if win.JustPressed(pixelgl.MouseButtonLeft) {
+//           world.Click(vp.MousePosition())
+//       }
+//       vp.Present()
+//       win.Update()
+//   }
+type Viewport struct {
+	// Scaling is the way the virtual resolution is scaled to the Window.
+	Scaling ViewportScaling
+
+	// Background is the color of the bars around the virtual resolution, black if nil.
+	Background color.Color
+
+	win    *Window
+	canvas *Canvas
+}
+
+// NewViewport creates a new Viewport with the virtual resolution of width by height pixels,
+// presented in the Window. Its Canvas is not smooth, so the upscaled pixel art stays sharp.
+func NewViewport(win *Window, width, height float64) *Viewport {
+	return &Viewport{
+		win:    win,
+		canvas: NewCanvas(pixel.R(0, 0, width, height)),
+	}
+}
+
+// Canvas returns the Canvas of the Viewport in the virtual resolution, which everything is drawn
+// onto. Its bounds can be changed with SetBounds to change the virtual resolution.
+func (v *Viewport) Canvas() *Canvas {
+	return v.canvas
+}
+
+// Rect returns the rectangle of the Window which the virtual resolution covers.
+func (v *Viewport) Rect() pixel.Rect {
+	m := v.Matrix()
+	vb := v.canvas.Bounds()
+	return pixel.Rect{Min: m.Project(vb.Min), Max: m.Project(vb.Max)}
+}
+
+// Matrix returns the Matrix which transforms the virtual coordinates into the coordinates of the
+// Window.
+func (v *Viewport) Matrix() pixel.Matrix {
+	vb, wb := v.canvas.Bounds(), v.win.Bounds()
+	switch v.Scaling {
+	case ScaleFit:
+		return pixel.FitMatrix(vb, wb)
+	case ScaleInteger:
+		s := math.Min(wb.W()/vb.W(), wb.H()/vb.H())
+		if s < 1 {
+			return pixel.FitMatrix(vb, wb)
+		}
+		s = math.Floor(s)
+		// keep the virtual pixels aligned with the pixels of the Window
+		half := vb.Size().Scaled(s / 2)
+		center := wb.Center().Sub(half).Floor().Add(half)
+		return pixel.IM.Moved(vb.Center().Scaled(-1)).Scaled(pixel.ZV, s).Moved(center)
+	}
+	return pixel.IM.
+		Moved(vb.Center().Scaled(-1)).
+		ScaledXY(pixel.ZV, pixel.V(wb.W()/vb.W(), wb.H()/vb.H())).
+		Moved(wb.Center())
+}
+
+// WindowToVirtual returns the virtual coordinates of the position u in the Window.
+func (v *Viewport) WindowToVirtual(u pixel.Vec) pixel.Vec {
+	return v.Matrix().Unproject(u)
+}
+
+// VirtualToWindow returns the position in the Window of the virtual coordinates u.
+func (v *Viewport) VirtualToWindow(u pixel.Vec) pixel.Vec {
+	return v.Matrix().Project(u)
+}
+
+// MousePosition returns the position of the mouse in the virtual coordinates. It's outside of the
+// bounds of the Canvas if the mouse is over the bars.
+func (v *Viewport) MousePosition() pixel.Vec {
+	return v.WindowToVirtual(v.win.MousePosition())
+}
+
+// Present clears the Window with the Background and draws the Canvas onto it. Call it before
+// updating the Window.
+//
+// Present sets the Matrix of the Window to pixel.IM, anything drawn onto the Window after it, such
+// as UI in the full resolution, is in the coordinates of the Window.
+func (v *Viewport) Present() {
+	bg := v.Background
+	if bg == nil {
+		bg = color.Black
+	}
+	v.win.SetMatrix(pixel.IM)
+	v.win.Clear(bg)
+	// the Canvas is drawn centered around the origin, like a Sprite
+	v.canvas.Draw(v.win, pixel.IM.Moved(v.canvas.Bounds().Center()).Chained(v.Matrix()))
+}
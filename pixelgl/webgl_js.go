@@ -0,0 +1,304 @@
+package pixelgl
+
+import (
+	"fmt"
+	"syscall/js"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pkg/errors"
+)
+
+// the objects of WebGL2 are JavaScript objects, null if there's none
+type (
+	glTex     = js.Value
+	glFBO     = js.Value
+	glProgram = js.Value
+	glUniform = js.Value
+	glBuffer  = js.Value
+	glVAO     = js.Value
+)
+
+// context is the WebGL2 context of the Window, which holds all the Canvases and Pictures
+type context struct {
+	gl js.Value
+
+	// the array passing the data to WebGL, reused by all the calls
+	bytes    js.Value
+	bytesLen int
+
+	// names is the last ID given to a texture
+	names uint32
+}
+
+// newContext creates the WebGL2 context of the HTML canvas element
+func newContext(element js.Value) (*context, error) {
+	gl := element.Call("getContext", "webgl2", map[string]interface{}{
+		"alpha":     false,
+		"antialias": false,
+		"depth":     false,
+		"stencil":   false,
+	})
+	if gl.IsNull() || gl.IsUndefined() {
+		return nil, errors.New("WebGL2 is not supported by the browser")
+	}
+	gl.Call("enable", glBlend)
+	gl.Call("pixelStorei", glUnpackAlignment, 1)
+	gl.Call("pixelStorei", glPackAlignment, 1)
+	return &context{gl: gl}, nil
+}
+
+// buffer returns a Uint8Array of n bytes, which is reused by the next calls
+func (c *context) buffer(n int) js.Value {
+	if c.bytesLen < n {
+		c.bytesLen = 2 * n
+		c.bytes = js.Global().Get("Uint8Array").New(c.bytesLen)
+	}
+	return c.bytes.Call("subarray", 0, n)
+}
+
+// upload returns a buffer with a copy of the bytes
+func (c *context) upload(b []byte) js.Value {
+	arr := c.buffer(len(b))
+	js.CopyBytesToJS(arr, b)
+	return arr
+}
+
+// floats returns a Float32Array with a copy of the floats, in the buffer
+func (c *context) floats(data []float32) js.Value {
+	arr := c.upload(floatBytes(data))
+	return js.Global().Get("Float32Array").New(arr.Get("buffer"), 0, len(data))
+}
+
+func (c *context) createTexture() glTex {
+	return c.gl.Call("createTexture")
+}
+
+func (c *context) deleteTexture(t glTex) {
+	c.gl.Call("deleteTexture", t)
+}
+
+func (c *context) activeTexture(unit int) {
+	c.gl.Call("activeTexture", unit)
+}
+
+func (c *context) bindTexture(t glTex) {
+	c.gl.Call("bindTexture", glTexture2D, t)
+}
+
+func (c *context) unbindTexture() {
+	c.gl.Call("bindTexture", glTexture2D, js.Null())
+}
+
+// texImage2D allocates the bound texture with the pixels, or transparent if they're nil
+func (c *context) texImage2D(width, height int, pixels []uint8) {
+	data := js.Null()
+	if pixels != nil {
+		data = c.upload(pixels)
+	}
+	c.gl.Call("texImage2D", glTexture2D, 0, glRGBA8, width, height, 0, glRGBA, glUnsignedByte, data)
+}
+
+func (c *context) texSubImage2D(x, y, w, h int, pixels []uint8) {
+	c.gl.Call("texSubImage2D", glTexture2D, 0, x, y, w, h, glRGBA, glUnsignedByte, c.upload(pixels))
+}
+
+func (c *context) texParameteri(pname, param int) {
+	c.gl.Call("texParameteri", glTexture2D, pname, param)
+}
+
+func (c *context) createFramebuffer() glFBO {
+	return c.gl.Call("createFramebuffer")
+}
+
+func (c *context) deleteFramebuffer(fb glFBO) {
+	c.gl.Call("deleteFramebuffer", fb)
+}
+
+func (c *context) bindFramebuffer(target int, fb glFBO) {
+	c.gl.Call("bindFramebuffer", target, fb)
+}
+
+// unbindFramebuffer binds the canvas element
+func (c *context) unbindFramebuffer() {
+	c.gl.Call("bindFramebuffer", glFramebuffer, js.Null())
+}
+
+// bindScreen binds the canvas element to the target
+func (c *context) bindScreen(target int) {
+	c.gl.Call("bindFramebuffer", target, js.Null())
+}
+
+// framebufferTexture2D attaches the texture to the bound framebuffer
+func (c *context) framebufferTexture2D(t glTex) {
+	c.gl.Call("framebufferTexture2D", glFramebuffer, glColorAttachment0, glTexture2D, t, 0)
+}
+
+func (c *context) blitFramebuffer(sx0, sy0, sx1, sy1, dx0, dy0, dx1, dy1, filter int) {
+	c.gl.Call("blitFramebuffer", sx0, sy0, sx1, sy1, dx0, dy0, dx1, dy1, glColorBufferBit, filter)
+}
+
+// readPixels returns the RGBA bytes of the rectangle of the bound framebuffer
+func (c *context) readPixels(x, y, w, h int) []uint8 {
+	pixels := make([]uint8, 4*w*h)
+	arr := c.buffer(len(pixels))
+	c.gl.Call("readPixels", x, y, w, h, glRGBA, glUnsignedByte, arr)
+	js.CopyBytesToGo(pixels, arr)
+	return pixels
+}
+
+func (c *context) enable(cap int) {
+	c.gl.Call("enable", cap)
+}
+
+func (c *context) disable(cap int) {
+	c.gl.Call("disable", cap)
+}
+
+func (c *context) scissor(x, y, w, h int) {
+	c.gl.Call("scissor", x, y, w, h)
+}
+
+func (c *context) viewport(x, y, w, h int) {
+	c.gl.Call("viewport", x, y, w, h)
+}
+
+func (c *context) clearColor(r, g, b, a float32) {
+	c.gl.Call("clearColor", r, g, b, a)
+}
+
+func (c *context) clear(mask int) {
+	c.gl.Call("clear", mask)
+}
+
+func (c *context) blendFunc(src, dst int) {
+	c.gl.Call("blendFunc", src, dst)
+}
+
+// linkProgram compiles and links the shaders with the attributes of the Canvas, returning the
+// error logs of WebGL if it fails
+func (c *context) linkProgram(vs, fs string) (glProgram, error) {
+	gl := c.gl
+	program := gl.Call("createProgram")
+	for _, s := range []struct {
+		typ  int
+		name string
+		src  string
+	}{
+		{glVertexShader, "vertex", vs},
+		{glFragmentShader, "fragment", fs},
+	} {
+		shader := gl.Call("createShader", s.typ)
+		gl.Call("shaderSource", shader, s.src)
+		gl.Call("compileShader", shader)
+		if !gl.Call("getShaderParameter", shader, glCompileStatus).Bool() {
+			log := gl.Call("getShaderInfoLog", shader).String()
+			gl.Call("deleteShader", shader)
+			gl.Call("deleteProgram", program)
+			return js.Null(), fmt.Errorf("error compiling %s shader: %s", s.name, log)
+		}
+		gl.Call("attachShader", program, shader)
+		gl.Call("deleteShader", shader)
+	}
+	for loc, name := range canvasAttributes {
+		gl.Call("bindAttribLocation", program, loc, name)
+	}
+	gl.Call("linkProgram", program)
+	if !gl.Call("getProgramParameter", program, glLinkStatus).Bool() {
+		log := gl.Call("getProgramInfoLog", program).String()
+		gl.Call("deleteProgram", program)
+		return js.Null(), fmt.Errorf("error linking shader program: %s", log)
+	}
+	return program, nil
+}
+
+func (c *context) deleteProgram(p glProgram) {
+	c.gl.Call("deleteProgram", p)
+}
+
+func (c *context) useProgram(p glProgram) {
+	c.gl.Call("useProgram", p)
+}
+
+// uniformLocation returns the location of the uniform, false if the program doesn't use it
+func (c *context) uniformLocation(p glProgram, name string) (glUniform, bool) {
+	loc := c.gl.Call("getUniformLocation", p, name)
+	return loc, !loc.IsNull()
+}
+
+// uniform sets the uniform at the location to the value, one of the types returned by
+// uniformValue
+func (c *context) uniform(loc glUniform, value interface{}) {
+	gl := c.gl
+	switch v := value.(type) {
+	case int32:
+		gl.Call("uniform1i", loc, v)
+	case float32:
+		gl.Call("uniform1f", loc, v)
+	case mgl32.Vec2:
+		gl.Call("uniform2f", loc, v[0], v[1])
+	case mgl32.Vec3:
+		gl.Call("uniform3f", loc, v[0], v[1], v[2])
+	case mgl32.Vec4:
+		gl.Call("uniform4f", loc, v[0], v[1], v[2], v[3])
+	case mgl32.Mat2:
+		gl.Call("uniformMatrix2fv", loc, false, c.floats(v[:]))
+	case mgl32.Mat2x3:
+		gl.Call("uniformMatrix2x3fv", loc, false, c.floats(v[:]))
+	case mgl32.Mat2x4:
+		gl.Call("uniformMatrix2x4fv", loc, false, c.floats(v[:]))
+	case mgl32.Mat3:
+		gl.Call("uniformMatrix3fv", loc, false, c.floats(v[:]))
+	case mgl32.Mat3x2:
+		gl.Call("uniformMatrix3x2fv", loc, false, c.floats(v[:]))
+	case mgl32.Mat3x4:
+		gl.Call("uniformMatrix3x4fv", loc, false, c.floats(v[:]))
+	case mgl32.Mat4:
+		gl.Call("uniformMatrix4fv", loc, false, c.floats(v[:]))
+	case mgl32.Mat4x2:
+		gl.Call("uniformMatrix4x2fv", loc, false, c.floats(v[:]))
+	case mgl32.Mat4x3:
+		gl.Call("uniformMatrix4x3fv", loc, false, c.floats(v[:]))
+	}
+}
+
+func (c *context) createVertexArray() glVAO {
+	return c.gl.Call("createVertexArray")
+}
+
+func (c *context) bindVertexArray(vao glVAO) {
+	c.gl.Call("bindVertexArray", vao)
+}
+
+func (c *context) unbindVertexArray() {
+	c.gl.Call("bindVertexArray", js.Null())
+}
+
+func (c *context) createBuffer() glBuffer {
+	return c.gl.Call("createBuffer")
+}
+
+// bindBuffer binds the buffer of the vertices
+func (c *context) bindBuffer(b glBuffer) {
+	c.gl.Call("bindBuffer", glArrayBuffer, b)
+}
+
+// vertexAttribPointer enables the attribute at the location, size floats at the offset of each
+// vertex
+func (c *context) vertexAttribPointer(loc, size, offset int) {
+	c.gl.Call("enableVertexAttribArray", loc)
+	c.gl.Call("vertexAttribPointer", loc, size, glFloat, false, 4*vertexStride, 4*offset)
+}
+
+// bufferData allocates size bytes of the bound buffer
+func (c *context) bufferData(size int) {
+	c.gl.Call("bufferData", glArrayBuffer, size, glDynamicDraw)
+}
+
+// bufferSubData copies the floats into the bound buffer from the offset in bytes
+func (c *context) bufferSubData(offset int, data []float32) {
+	c.gl.Call("bufferSubData", glArrayBuffer, offset, c.upload(floatBytes(data)))
+}
+
+func (c *context) drawArrays(first, count int) {
+	c.gl.Call("drawArrays", glTriangles, first, count)
+}
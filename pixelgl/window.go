@@ -1,3 +1,6 @@
+//go:build !js && !android && !ios && !purego
+// +build !js,!android,!ios,!purego
+
 package pixelgl
 
 import (
@@ -8,6 +11,7 @@ import (
 	"github.com/faiface/glhf"
 	"github.com/faiface/mainthread"
 	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/pkg/errors"
 )
@@ -49,6 +53,39 @@ type WindowConfig struct {
 	// VSync (vertical synchronization) synchronizes Window's framerate with the framerate of
 	// the monitor.
 	VSync bool
+
+	// Debug creates the Window with an OpenGL debug context. OpenGL errors and warnings are then
+	// reported to the function set by SetDebugCallback, together with the position in your code
+	// that caused them. Debugging slows down rendering, don't enable it in release builds.
+	Debug bool
+
+	// GLES creates the Window with an OpenGL ES 3.0 context instead of a desktop OpenGL 3.3
+	// one. This is useful on machines with poor desktop OpenGL drivers and on single board
+	// computers, such as the Raspberry Pi.
+	//
+	// The context is created through EGL, so on Windows the ANGLE libraries (libEGL.dll and
+	// libGLESv2.dll) placed next to the executable are used. All Windows must use the same
+	// setting, because they share one context.
+	GLES bool
+
+	// GLES2 creates the Window with an OpenGL ES 2.0 context, implying GLES. This is for GPUs
+	// without OpenGL ES 3.0, such as the one of the Raspberry Pi 0 to 3 and of many handhelds.
+	//
+	// The GPU must support vertex array objects (GL_OES_vertex_array_object). Canvases are
+	// copied by drawing quads without framebuffer blits (GL_NV_framebuffer_blit), and shaders
+	// are translated to GLSL ES 1.00, which works for shaders written in the subset of GLSL used
+	// by Pixel's default one. GPU timers, occlusion queries and linear blending are unavailable,
+	// same as with GLES.
+	//
+	// GLFW needs a window system, X11 or Wayland: pixelgl can't draw to the screen directly
+	// through KMS/DRM. On kiosks and handhelds without a desktop, run the app under a Wayland
+	// compositor showing one fullscreen app (e.g. cage), or start a bare X server (e.g. with
+	// startx or xinit) running only the app.
+	GLES2 bool
+
+	// Linear makes the Window blend the colors drawn onto it in linear RGB, which avoids the dark
+	// halos and muddy gradients of blending in sRGB. See Canvas.SetLinear.
+	Linear bool
 }
 
 // Window is a window handler. Use this type to manipulate a window (input, drawing, etc.).
@@ -60,6 +97,15 @@ type Window struct {
 	vsync              bool
 	cursorVisible      bool
 	cursorInsideWindow bool
+	frameHook          func(texture uint32, width, height int)
+
+	// set when the system asks for a redraw, e.g. when the Window is uncovered
+	refresh bool
+
+	// the screenshots taken by ScreenshotAsync, which the GPU is copying
+	//
+	// accessed only inside mainthread
+	screenshots []pendingScreenshot
 
 	// need to save these to correctly restore a fullscreen window
 	restore struct {
@@ -71,6 +117,8 @@ type Window struct {
 		buttons [KeyLast + 1]bool
 		repeat  [KeyLast + 1]bool
 		scroll  pixel.Vec
+		precise bool
+		zoom    float64
 		typed   string
 	}
 
@@ -93,13 +141,29 @@ func NewWindow(cfg WindowConfig) (*Window, error) {
 	err := mainthread.CallErr(func() error {
 		var err error
 
-		glfw.WindowHint(glfw.ContextVersionMajor, 3)
-		glfw.WindowHint(glfw.ContextVersionMinor, 3)
-		glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-		glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+		if cfg.GLES || cfg.GLES2 {
+			major := 3
+			if cfg.GLES2 {
+				major = 2
+			}
+			glfw.WindowHint(glfw.ClientAPI, glfw.OpenGLESAPI)
+			glfw.WindowHint(glfw.ContextCreationAPI, glfw.EGLContextAPI)
+			glfw.WindowHint(glfw.ContextVersionMajor, major)
+			glfw.WindowHint(glfw.ContextVersionMinor, 0)
+			glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLAnyProfile)
+			glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.False)
+		} else {
+			glfw.WindowHint(glfw.ClientAPI, glfw.OpenGLAPI)
+			glfw.WindowHint(glfw.ContextCreationAPI, glfw.NativeContextAPI)
+			glfw.WindowHint(glfw.ContextVersionMajor, 3)
+			glfw.WindowHint(glfw.ContextVersionMinor, 3)
+			glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+			glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+		}
 
 		glfw.WindowHint(glfw.Resizable, bool2int[cfg.Resizable])
 		glfw.WindowHint(glfw.Decorated, bool2int[!cfg.Undecorated])
+		glfw.WindowHint(glfw.OpenGLDebugContext, bool2int[cfg.Debug])
 
 		var share *glfw.Window
 		if currWin != nil {
@@ -119,7 +183,19 @@ func NewWindow(cfg WindowConfig) (*Window, error) {
 
 		// enter the OpenGL context
 		w.begin()
-		glhf.Init()
+		if cfg.GLES || cfg.GLES2 {
+			if err := initGLES(cfg.GLES2); err != nil {
+				w.end()
+				w.window.Destroy()
+				return err
+			}
+		} else {
+			glhf.Init()
+		}
+		gles, gles2 = cfg.GLES || cfg.GLES2, cfg.GLES2
+		if cfg.Debug {
+			enableDebug()
+		}
 		w.end()
 
 		return nil
@@ -145,22 +221,43 @@ func NewWindow(cfg WindowConfig) (*Window, error) {
 	w.SetMonitor(cfg.Monitor)
 
 	w.canvas = NewCanvas(cfg.Bounds)
+	w.canvas.SetLabel("Window")
+	w.canvas.SetLinear(cfg.Linear)
 	w.Update()
 
-	runtime.SetFinalizer(w, (*Window).Destroy)
+	runtime.SetFinalizer(w, (*Window).delete)
 
 	return w, nil
 }
 
 // Destroy destroys the Window. The Window can't be used any further.
 func (w *Window) Destroy() {
-	mainthread.Call(func() {
-		w.window.Destroy()
-	})
+	runtime.SetFinalizer(w, nil)
+	mainthread.Call(w.destroy)
+}
+
+// delete destroys the garbage collected Window. Finalizers must not wait for mainthread, so it
+// only posts the work.
+func (w *Window) delete() {
+	mainthread.CallNonBlock(w.destroy)
+}
+
+// must be manually called inside mainthread
+func (w *Window) destroy() {
+	if len(w.screenshots) > 0 {
+		w.begin()
+		w.finishScreenshots(true)
+		w.end()
+	}
+	w.window.Destroy()
 }
 
 // Update swaps buffers and polls events. Call this method at the end of each frame.
+//
+// In the dirty rendering mode, Update only swaps buffers if something was redrawn, otherwise it
+// waits for events. See SetDirtyRendering.
 func (w *Window) Update() {
+	var resized bool
 	mainthread.Call(func() {
 		_, _, oldW, oldH := intBounds(w.bounds)
 		newW, newH := w.window.GetSize()
@@ -168,24 +265,57 @@ func (w *Window) Update() {
 			float64(newW-oldW),
 			float64(newH-oldH),
 		)))
+		resized = newW != oldW || newH != oldH
 	})
 
 	w.canvas.SetBounds(w.bounds)
+	flushDraws()
+
+	present := true
+	if w.canvas.dirtyMode {
+		present = w.canvas.DirtyRegion().Area() > 0
+		w.canvas.ResetDirtyRegion()
+		if resized {
+			// the content of the next frame doesn't fit the new size, redraw everything
+			w.canvas.Invalidate(w.canvas.Bounds())
+		}
+	}
+
+	mainthread.Call(debugWrap(func() {
+		if len(w.screenshots) > 0 {
+			w.begin()
+			w.finishScreenshots(false)
+			w.end()
+		}
+		if w.refresh {
+			// the Canvas still has the whole content to show
+			present = true
+			w.refresh = false
+		}
+		if !present {
+			return
+		}
+		defer beginPhase(pixel.PhaseSwap).End()
 
-	mainthread.Call(func() {
 		w.begin()
+		pushDebugGroup("Window.Update")
 
 		framebufferWidth, framebufferHeight := w.window.GetFramebufferSize()
 		glhf.Bounds(0, 0, framebufferWidth, framebufferHeight)
 
 		glhf.Clear(0, 0, 0, 0)
-		w.canvas.gf.Frame().Begin()
-		w.canvas.gf.Frame().Blit(
-			nil,
-			0, 0, w.canvas.Texture().Width(), w.canvas.Texture().Height(),
-			0, 0, framebufferWidth, framebufferHeight,
+		frame := w.canvas.gf.frame
+		blitFramebuffer(
+			frame.ID(), frame.Texture(), 0,
+			0, 0, int32(frame.Texture().Width()), int32(frame.Texture().Height()),
+			0, 0, int32(framebufferWidth), int32(framebufferHeight),
+			gl.NEAREST,
 		)
-		w.canvas.gf.Frame().End()
+		popDebugGroup()
+
+		if w.frameHook != nil {
+			w.frameHook(w.canvas.Texture().ID(), w.canvas.Texture().Width(), w.canvas.Texture().Height())
+		}
 
 		if w.vsync {
 			glfw.SwapInterval(1)
@@ -194,8 +324,12 @@ func (w *Window) Update() {
 		}
 		w.window.SwapBuffers()
 		w.end()
-	})
+	}))
 
+	if !present && !resized {
+		w.UpdateInputWait(idleTimeout)
+		return
+	}
 	w.UpdateInput()
 }
 
@@ -418,11 +552,38 @@ func (w *Window) Smooth() bool {
 	return w.canvas.Smooth()
 }
 
+// SetDrawMerging sets whether consecutive draws onto this Window with the same state are merged
+// into one OpenGL draw call, which is on by default. See Canvas.SetDrawMerging.
+func (w *Window) SetDrawMerging(merge bool) {
+	w.canvas.SetDrawMerging(merge)
+}
+
+// DrawMerging returns whether consecutive draws onto this Window are merged.
+func (w *Window) DrawMerging() bool {
+	return w.canvas.DrawMerging()
+}
+
 // Clear clears the Window with a single color.
 func (w *Window) Clear(c color.Color) {
 	w.canvas.Clear(c)
 }
 
+// ClearRect fills the rectangle of the Window with a single color, see Canvas.ClearRect.
+func (w *Window) ClearRect(r pixel.Rect, c color.Color) {
+	w.canvas.ClearRect(r, c)
+}
+
+// SetLazyClear sets whether Clear skips filling the Window, because every frame draws over all of
+// it anyway. See Canvas.SetLazyClear.
+func (w *Window) SetLazyClear(lazy bool) {
+	w.canvas.SetLazyClear(lazy)
+}
+
+// LazyClear returns whether Clear skips filling the Window.
+func (w *Window) LazyClear() bool {
+	return w.canvas.LazyClear()
+}
+
 // Color returns the color of the pixel over the given position inside the Window.
 func (w *Window) Color(at pixel.Vec) pixel.RGBA {
 	return w.canvas.Color(at)
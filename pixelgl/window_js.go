@@ -0,0 +1,345 @@
+package pixelgl
+
+import (
+	"image/color"
+	"syscall/js"
+
+	"github.com/faiface/pixel"
+	"github.com/pkg/errors"
+)
+
+// WindowConfig is a structure for specifying all possible properties of a Window. Properties are
+// chosen in such a way, that you usually only need to set a few of them - defaults (zeros) should
+// usually be sensible.
+//
+// In the browser, the Window is an HTML canvas element, so the properties of the desktop windows
+// that a page can't change are ignored.
+//
+// Note that you always need to set the Bounds of a Window.
+type WindowConfig struct {
+	// Title of the page.
+	Title string
+
+	// Icon is ignored, the icon of a page is set by its HTML.
+	Icon []pixel.Picture
+
+	// Bounds specify the bounds of the Window in pixels, the size of the canvas element.
+	Bounds pixel.Rect
+
+	// If set to nil, the Window will be windowed. Otherwise the canvas element is shown fullscreen,
+	// which the browser only allows after the user interacted with the page.
+	Monitor *Monitor
+
+	// Resizable makes the Window follow the size the page lays the canvas element out with, e.g.
+	// by the CSS width and height in percents.
+	Resizable bool
+
+	// Undecorated, VSync, Debug, GLES, GLES2 and Linear are ignored. The browser always
+	// synchronizes the frames with the screen, and the context is WebGL2, which is OpenGL ES 3.0.
+	Undecorated bool
+	VSync       bool
+	Debug       bool
+	GLES        bool
+	GLES2       bool
+	Linear      bool
+
+	// Canvas is the id of the HTML canvas element the Window draws into. By default, a new canvas
+	// element is appended to the body of the page. It's only in the browser.
+	Canvas string
+}
+
+// Window is an HTML canvas element drawn by WebGL2. Use this type to manipulate the canvas (input,
+// drawing, etc.).
+type Window struct {
+	element js.Value
+	created bool
+
+	bounds             pixel.Rect
+	canvas             *Canvas
+	resizable          bool
+	closed             bool
+	focused            bool
+	vsync              bool
+	cursorVisible      bool
+	cursorInsideWindow bool
+
+	// frame receives from onFrame when the browser is ready for the next frame
+	frame     chan struct{}
+	onFrame   js.Func
+	listeners []listener
+
+	prevInp, currInp, tempInp struct {
+		mouse   pixel.Vec
+		buttons [KeyLast + 1]bool
+		repeat  [KeyLast + 1]bool
+		scroll  pixel.Vec
+		precise bool
+		zoom    float64
+		typed   string
+	}
+
+	prevJoy, currJoy, tempJoy joystickState
+}
+
+var _ pixel.ComposeTarget = (*Window)(nil)
+
+// Run calls the function. In the browser the Window doesn't need the main thread, so Run only keeps
+// the programs the same as on the desktop.
+func Run(run func()) {
+	run()
+}
+
+// NewWindow creates a new Window with it's properties specified in the provided config.
+//
+// If Window creation fails, an error is returned (e.g. due to the browser not supporting WebGL2).
+// A page has only one Window.
+func NewWindow(cfg WindowConfig) (*Window, error) {
+	if glctx != nil {
+		return nil, errors.New("creating window failed: a page can only have one Window")
+	}
+
+	document := js.Global().Get("document")
+	w := &Window{
+		bounds:        cfg.Bounds,
+		resizable:     cfg.Resizable,
+		focused:       document.Call("hasFocus").Bool(),
+		vsync:         cfg.VSync,
+		cursorVisible: true,
+		frame:         make(chan struct{}, 1),
+	}
+	if cfg.Canvas != "" {
+		w.element = document.Call("getElementById", cfg.Canvas)
+		if w.element.IsNull() {
+			return nil, errors.Errorf("creating window failed: no canvas element with id %q", cfg.Canvas)
+		}
+	} else {
+		w.element = document.Call("createElement", "canvas")
+		document.Get("body").Call("appendChild", w.element)
+		w.created = true
+	}
+	if cfg.Title != "" {
+		w.SetTitle(cfg.Title)
+	}
+	w.resizeElement()
+
+	ctx, err := newContext(w.element)
+	if err != nil {
+		w.removeElement()
+		return nil, errors.Wrap(err, "creating window failed")
+	}
+	glctx = ctx
+	w.canvas = newCanvas(ctx, cfg.Bounds)
+
+	w.onFrame = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		select {
+		case w.frame <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	w.listen()
+	w.SetMonitor(cfg.Monitor)
+	return w, nil
+}
+
+// Destroy destroys the Window, removing the canvas element it created. The Window, its Canvases
+// and its Pictures can't be used any further.
+func (w *Window) Destroy() {
+	for _, l := range w.listeners {
+		l.target.Call("removeEventListener", l.event, l.f)
+		l.f.Release()
+	}
+	w.listeners = nil
+	w.onFrame.Release()
+	w.removeElement()
+	glctx = nil
+}
+
+func (w *Window) removeElement() {
+	if w.created {
+		w.element.Call("remove")
+	}
+}
+
+// resizeElement sets the size of the canvas element to the bounds
+func (w *Window) resizeElement() {
+	_, _, bw, bh := intBounds(w.bounds)
+	w.element.Set("width", bw)
+	w.element.Set("height", bh)
+}
+
+// Update shows the content of the Window and polls events. Call this method at the end of each
+// frame. It waits until the browser is ready for the next frame, which lets the page handle its
+// events meanwhile, so the game runs at the frame rate of the screen.
+func (w *Window) Update() {
+	ctx := w.canvas.ctx
+	tex := w.canvas.tex
+	ctx.bindFramebuffer(glReadFramebuffer, w.canvas.fbo)
+	ctx.bindScreen(glDrawFramebuffer)
+	ctx.blitFramebuffer(
+		0, 0, tex.width, tex.height,
+		0, 0, tex.width, tex.height,
+		glNearest,
+	)
+	ctx.unbindFramebuffer()
+
+	js.Global().Call("requestAnimationFrame", w.onFrame)
+	<-w.frame
+
+	if w.resizable {
+		width := w.element.Get("clientWidth").Float()
+		height := w.element.Get("clientHeight").Float()
+		if width > 0 && height > 0 && (width != w.bounds.W() || height != w.bounds.H()) {
+			w.SetBounds(w.bounds.ResizedMin(pixel.V(width, height)))
+		}
+	}
+	w.UpdateInput()
+}
+
+// SetClosed sets the closed flag of the Window.
+//
+// A page can't close itself, so this is only useful to end the loop of the game.
+func (w *Window) SetClosed(closed bool) {
+	w.closed = closed
+}
+
+// Closed returns the closed flag of the Window, which is only set by SetClosed. Closing the page
+// stops the game altogether.
+func (w *Window) Closed() bool {
+	return w.closed
+}
+
+// SetTitle changes the title of the page.
+func (w *Window) SetTitle(title string) {
+	js.Global().Get("document").Set("title", title)
+}
+
+// SetBounds sets the bounds of the Window in pixels, resizing the canvas element.
+func (w *Window) SetBounds(bounds pixel.Rect) {
+	w.bounds = bounds
+	w.resizeElement()
+	w.canvas.SetBounds(bounds)
+}
+
+// Bounds returns the current bounds of the Window.
+func (w *Window) Bounds() pixel.Rect {
+	return w.bounds
+}
+
+// SetMonitor shows the canvas element fullscreen if the Monitor isn't nil, otherwise it leaves
+// the fullscreen. The browser only allows entering the fullscreen in the events of the user's
+// input, it's ignored otherwise.
+func (w *Window) SetMonitor(monitor *Monitor) {
+	document := js.Global().Get("document")
+	if monitor != nil {
+		if w.Monitor() == nil && w.element.Get("requestFullscreen").Truthy() {
+			w.element.Call("requestFullscreen")
+		}
+		return
+	}
+	if w.Monitor() != nil {
+		document.Call("exitFullscreen")
+	}
+}
+
+// Monitor returns the screen if the canvas element is fullscreen, otherwise nil.
+func (w *Window) Monitor() *Monitor {
+	if !js.Global().Get("document").Get("fullscreenElement").Equal(w.element) {
+		return nil
+	}
+	return PrimaryMonitor()
+}
+
+// Focused returns true if the page has input focus.
+func (w *Window) Focused() bool {
+	return w.focused
+}
+
+// SetVSync does nothing but remembering vsync, the browser always synchronizes the frames with the
+// screen.
+func (w *Window) SetVSync(vsync bool) {
+	w.vsync = vsync
+}
+
+// VSync returns whether the Window is set to synchronize with the monitor refresh rate.
+func (w *Window) VSync() bool {
+	return w.vsync
+}
+
+// SetCursorVisible sets the visibility of the mouse cursor over the canvas element.
+func (w *Window) SetCursorVisible(visible bool) {
+	w.cursorVisible = visible
+	cursor := ""
+	if !visible {
+		cursor = "none"
+	}
+	w.element.Get("style").Set("cursor", cursor)
+}
+
+// CursorVisible returns the visibility status of the mouse cursor.
+func (w *Window) CursorVisible() bool {
+	return w.cursorVisible
+}
+
+// MakeTriangles generates a specialized copy of the supplied Triangles that will draw onto this
+// Window.
+//
+// Window supports TrianglesPosition, TrianglesColor and TrianglesPicture.
+func (w *Window) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	return w.canvas.MakeTriangles(t)
+}
+
+// MakePicture generates a specialized copy of the supplied Picture that will draw onto this Window.
+//
+// Window supports PictureColor.
+func (w *Window) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	return w.canvas.MakePicture(p)
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (w *Window) SetMatrix(m pixel.Matrix) {
+	w.canvas.SetMatrix(m)
+}
+
+// SetColorMask sets a global color mask for the Window.
+func (w *Window) SetColorMask(c color.Color) {
+	w.canvas.SetColorMask(c)
+}
+
+// SetComposeMethod sets a Porter-Duff composition method to be used in the following draws onto
+// this Window.
+func (w *Window) SetComposeMethod(cmp pixel.ComposeMethod) {
+	w.canvas.SetComposeMethod(cmp)
+}
+
+// SetSmooth sets whether the stretched Pictures drawn onto this Window should be drawn smooth or
+// pixely.
+func (w *Window) SetSmooth(smooth bool) {
+	w.canvas.SetSmooth(smooth)
+}
+
+// Smooth returns whether the stretched Pictures drawn onto this Window are set to be drawn smooth
+// or pixely.
+func (w *Window) Smooth() bool {
+	return w.canvas.Smooth()
+}
+
+// Clear clears the Window with a single color.
+func (w *Window) Clear(c color.Color) {
+	w.canvas.Clear(c)
+}
+
+// ClearRect fills the rectangle of the Window with a single color, see Canvas.ClearRect.
+func (w *Window) ClearRect(r pixel.Rect, c color.Color) {
+	w.canvas.ClearRect(r, c)
+}
+
+// Color returns the color of the pixel over the given position inside the Window.
+func (w *Window) Color(at pixel.Vec) pixel.RGBA {
+	return w.canvas.Color(at)
+}
+
+// Canvas returns the window's underlying Canvas
+func (w *Window) Canvas() *Canvas {
+	return w.canvas
+}
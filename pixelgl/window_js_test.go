@@ -0,0 +1,218 @@
+package pixelgl
+
+import (
+	"image/color"
+	"strings"
+	"sync"
+	"syscall/js"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// browserMocks are a page with a canvas element and a WebGL2 context recording its calls, enough
+// for the Window to run under node by go_js_wasm_exec. readPixels reads the last clear color.
+const browserMocks = `
+(() => {
+	const listeners = (target) => {
+		target.listeners = {};
+		target.addEventListener = (event, f) => {
+			(target.listeners[event] = target.listeners[event] || []).push(f);
+		};
+		target.removeEventListener = (event, f) => {
+			target.listeners[event] = (target.listeners[event] || []).filter((g) => g !== f);
+		};
+		return target;
+	};
+
+	const mock = globalThis.pixelMock = {
+		calls: [],
+		sources: [],
+		compiles: true,
+		clearColor: [0, 0, 0, 0],
+		count: (name) => mock.calls.filter((c) => c === name).length,
+	};
+
+	const gl = new Proxy({}, {
+		get: (target, name) => (...args) => {
+			mock.calls.push(name);
+			switch (name) {
+			case "getShaderParameter":
+				return mock.compiles;
+			case "getProgramParameter":
+				return true;
+			case "getShaderInfoLog":
+			case "getProgramInfoLog":
+				return "mock error";
+			case "getUniformLocation":
+				return {name: args[1]};
+			case "shaderSource":
+				mock.sources.push(args[1]);
+				return;
+			case "clearColor":
+				mock.clearColor = args.map((c) => Math.round(c * 255));
+				return;
+			case "readPixels":
+				const data = args[6];
+				for (let i = 0; i < data.length; i++) {
+					data[i] = mock.clearColor[i % 4];
+				}
+				return;
+			}
+			if (name.startsWith("create")) {
+				return {};
+			}
+		},
+	});
+
+	const canvas = listeners({
+		width: 0,
+		height: 0,
+		clientWidth: 0,
+		clientHeight: 0,
+		style: {},
+		getContext: () => gl,
+		getBoundingClientRect: () => ({left: 0, top: 0, width: canvas.width, height: canvas.height}),
+		remove: () => {},
+	});
+	mock.canvas = canvas;
+
+	globalThis.document = {
+		title: "",
+		body: {appendChild: () => {}},
+		fullscreenElement: null,
+		hasFocus: () => true,
+		createElement: () => canvas,
+		getElementById: () => null,
+	};
+	listeners(globalThis);
+	globalThis.requestAnimationFrame = (f) => setTimeout(f, 0);
+	Object.defineProperty(globalThis, "navigator", {
+		value: {getGamepads: () => [{id: "mock pad", buttons: [{pressed: true}], axes: [0.5]}]},
+		configurable: true,
+		writable: true,
+	});
+	globalThis.screen = {width: 1920, height: 1080, colorDepth: 24};
+	globalThis.devicePixelRatio = 1;
+
+	mock.dispatch = (target, event, props) => {
+		const e = Object.assign({
+			target: document.body,
+			repeat: false,
+			ctrlKey: false,
+			metaKey: false,
+			preventDefault: () => {},
+		}, props);
+		for (const f of target.listeners[event] || []) {
+			f(e);
+		}
+	};
+})()
+`
+
+var installMocks sync.Once
+
+// newMockWindow creates a Window in the mocked page, destroyed at the end of the test
+func newMockWindow(t *testing.T) (*Window, js.Value) {
+	installMocks.Do(func() {
+		js.Global().Call("eval", browserMocks)
+	})
+	mock := js.Global().Get("pixelMock")
+
+	win, err := NewWindow(WindowConfig{
+		Title:  "Pixel",
+		Bounds: pixel.R(0, 0, 64, 48),
+	})
+	require.NoError(t, err)
+	t.Cleanup(win.Destroy)
+	return win, mock
+}
+
+func TestNewWindow(t *testing.T) {
+	_, mock := newMockWindow(t)
+
+	assert.Equal(t, "Pixel", js.Global().Get("document").Get("title").String())
+	assert.Equal(t, 64, mock.Get("canvas").Get("width").Int())
+	assert.Equal(t, 48, mock.Get("canvas").Get("height").Int())
+
+	_, err := NewWindow(WindowConfig{Bounds: pixel.R(0, 0, 64, 48)})
+	assert.Error(t, err, "a page can only have one Window")
+}
+
+func TestWindow_Update(t *testing.T) {
+	win, mock := newMockWindow(t)
+
+	pic := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	draws := mock.Call("count", "drawArrays").Int()
+	pixel.NewSprite(pic, pic.Bounds()).Draw(win, pixel.IM.Moved(win.Bounds().Center()))
+	assert.Equal(t, draws+1, mock.Call("count", "drawArrays").Int())
+
+	blits := mock.Call("count", "blitFramebuffer").Int()
+	win.Update()
+	assert.Equal(t, blits+1, mock.Call("count", "blitFramebuffer").Int())
+}
+
+func TestWindow_Input(t *testing.T) {
+	win, mock := newMockWindow(t)
+
+	mock.Call("dispatch", js.Global(), "keydown", map[string]interface{}{"code": "KeyA", "key": "a"})
+	win.Update()
+	assert.True(t, win.Pressed(KeyA))
+	assert.True(t, win.JustPressed(KeyA))
+	assert.Equal(t, "a", win.Typed())
+
+	mock.Call("dispatch", js.Global(), "keyup", map[string]interface{}{"code": "KeyA", "key": "a"})
+	win.Update()
+	assert.False(t, win.Pressed(KeyA))
+	assert.True(t, win.JustReleased(KeyA))
+	assert.Equal(t, "", win.Typed())
+
+	mock.Call("dispatch", mock.Get("canvas"), "wheel", map[string]interface{}{
+		"deltaMode": 1,
+		"deltaX":    0,
+		"deltaY":    -3,
+		"ctrlKey":   true,
+	})
+	win.Update()
+	assert.Equal(t, pixel.V(0, 1), win.MouseScroll())
+	assert.InDelta(t, zoomStep, win.MouseZoom(), 1e-9)
+
+	assert.True(t, win.JoystickPresent(Joystick1))
+	assert.Equal(t, "mock pad", win.JoystickName(Joystick1))
+	assert.True(t, win.JoystickPressed(Joystick1, 0))
+	assert.InDelta(t, 0.5, win.JoystickAxis(Joystick1, 0), 1e-6)
+	assert.False(t, win.JoystickPresent(Joystick2))
+}
+
+func TestCanvas_SetFragmentShader(t *testing.T) {
+	_, mock := newMockWindow(t)
+	c := NewCanvas(pixel.R(0, 0, 8, 8))
+
+	const src = "#version 330 core\nout vec4 fragColor;\nvoid main() { fragColor = vec4(1.0); }\n"
+	c.SetFragmentShader(src)
+	sources := mock.Get("sources")
+	fs := sources.Index(sources.Length() - 1).String()
+	assert.True(t, strings.HasPrefix(fs, "#version 300 es\n"), fs)
+	assert.Contains(t, fs, "fragColor = vec4(1.0);")
+
+	mock.Set("compiles", false)
+	defer mock.Set("compiles", true)
+	err := c.TrySetFragmentShader("not glsl")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "mock error")
+	}
+}
+
+func TestCanvas_Pixels(t *testing.T) {
+	newMockWindow(t)
+	c := NewCanvas(pixel.R(0, 0, 2, 2))
+
+	c.Clear(color.RGBA{R: 255, A: 255})
+	assert.Equal(t, []uint8{255, 0, 0, 255}, c.Pixels()[:4])
+	assert.Equal(t, pixel.RGB(1, 0, 0), c.Color(pixel.V(1, 1)))
+
+	img := c.Texture().Pixels(0, 0, 1, 1)
+	assert.Equal(t, []uint8{255, 0, 0, 255}, img)
+}
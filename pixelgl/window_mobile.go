@@ -0,0 +1,393 @@
+//go:build android || ios
+// +build android ios
+
+package pixelgl
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/faiface/pixel"
+	"github.com/pkg/errors"
+	"golang.org/x/mobile/app"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/paint"
+	"golang.org/x/mobile/event/size"
+	"golang.org/x/mobile/event/touch"
+	"golang.org/x/mobile/gl"
+)
+
+// WindowConfig is a structure for specifying all possible properties of a Window. Properties are
+// chosen in such a way, that you usually only need to set a few of them - defaults (zeros) should
+// usually be sensible.
+//
+// On the phones, the Window is the screen of the app, so the properties of the desktop windows are
+// ignored.
+type WindowConfig struct {
+	// Title is ignored, the name of an app is set when it's built.
+	Title string
+
+	// Icon is ignored, the icon of an app is set when it's built.
+	Icon []pixel.Picture
+
+	// Bounds are ignored, the Window always has the size of the screen in pixels, which changes
+	// when the phone rotates. Use a Viewport to draw in a fixed resolution.
+	Bounds pixel.Rect
+
+	// Monitor, Resizable, Undecorated, VSync, Debug, GLES, GLES2 and Linear are ignored. The app is
+	// always fullscreen and synchronized with the screen, and the context is OpenGL ES 3.0.
+	Monitor     *Monitor
+	Resizable   bool
+	Undecorated bool
+	VSync       bool
+	Debug       bool
+	GLES        bool
+	GLES2       bool
+	Linear      bool
+}
+
+// Window is the screen of the app, drawn by OpenGL ES 3.0. Use this type to manipulate the screen
+// (input, drawing, etc.).
+type Window struct {
+	bounds        pixel.Rect
+	canvas        *Canvas
+	vsync         bool
+	cursorVisible bool
+
+	prevInp, currInp input
+	prevJoy, currJoy joystickState
+}
+
+var _ pixel.ComposeTarget = (*Window)(nil)
+
+// mobileApp is the app of gomobile. Its events are handled on the goroutine of app.Main, while the
+// game runs on another one, which makes all the OpenGL calls.
+type mobileApp struct {
+	app app.App
+
+	mu      sync.Mutex
+	glctx   gl.Context
+	visible bool
+	size    size.Event
+	closed  bool
+	focused bool
+	window  *Window
+	temp    input
+
+	// present asks for publishing the frame at the next paint, shown answers once it's published
+	present chan struct{}
+	shown   chan struct{}
+}
+
+// theApp is the app started by Run
+var theApp *mobileApp
+
+// Run runs the app of gomobile, calling run on a separate goroutine once the screen is ready. Call
+// this function from the main function of your application, it doesn't return.
+//
+// The OpenGL calls are made by the goroutine of the game, and gomobile runs them on the thread of
+// the app, so unlike on the desktop, there's no need for the main thread.
+func Run(run func()) {
+	app.Main(func(a app.App) {
+		theApp = &mobileApp{
+			app:     a,
+			focused: true,
+			present: make(chan struct{}, 1),
+			shown:   make(chan struct{}, 1),
+		}
+		theApp.loop(run)
+	})
+}
+
+// loop handles the events of the app
+func (a *mobileApp) loop(run func()) {
+	var start sync.Once
+	for e := range a.app.Events() {
+		switch e := a.app.Filter(e).(type) {
+		case lifecycle.Event:
+			a.mu.Lock()
+			switch e.Crosses(lifecycle.StageVisible) {
+			case lifecycle.CrossOn:
+				a.glctx = e.DrawContext.(gl.Context)
+				a.visible = true
+				// publish the frame which waited while the app was hidden
+				a.app.Send(paint.Event{})
+			case lifecycle.CrossOff:
+				a.visible = false
+			}
+			switch e.Crosses(lifecycle.StageFocused) {
+			case lifecycle.CrossOn:
+				a.focused = true
+			case lifecycle.CrossOff:
+				a.focused = false
+				a.temp.release()
+			}
+			if e.To == lifecycle.StageDead {
+				a.closed = true
+			}
+			ready := a.glctx != nil && a.size.WidthPx > 0
+			a.mu.Unlock()
+			if e.To == lifecycle.StageDead {
+				a.signal()
+				return
+			}
+			if ready {
+				start.Do(func() { go run() })
+			}
+
+		case size.Event:
+			a.mu.Lock()
+			a.size = e
+			ready := a.glctx != nil
+			a.mu.Unlock()
+			if ready {
+				start.Do(func() { go run() })
+			}
+
+		case paint.Event:
+			a.mu.Lock()
+			visible := a.visible
+			a.mu.Unlock()
+			if !visible {
+				continue
+			}
+			select {
+			case <-a.present:
+				a.app.Publish()
+				a.signal()
+			default:
+			}
+
+		case touch.Event:
+			a.mu.Lock()
+			// the touches go down from the top-left corner of the screen
+			at := pixel.V(float64(e.X), float64(a.size.HeightPx)-float64(e.Y))
+			a.temp.touch(int64(e.Sequence), at, e.Type)
+			a.mu.Unlock()
+
+		case key.Event:
+			a.mu.Lock()
+			a.temp.key(e)
+			a.mu.Unlock()
+		}
+	}
+}
+
+// signal lets the waiting Update return
+func (a *mobileApp) signal() {
+	select {
+	case a.shown <- struct{}{}:
+	default:
+	}
+}
+
+// screenBounds returns the size of the screen in pixels
+func (a *mobileApp) screenBounds() pixel.Rect {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return pixel.R(0, 0, float64(a.size.WidthPx), float64(a.size.HeightPx))
+}
+
+// NewWindow creates the Window of the screen, the properties in the provided config are ignored.
+//
+// It must be called inside the function passed to Run. If Window creation fails, an error is
+// returned (e.g. due to the phone not supporting OpenGL ES 3.0). An app has only one Window.
+func NewWindow(cfg WindowConfig) (*Window, error) {
+	if theApp == nil {
+		return nil, errors.New("creating window failed: NewWindow must be called inside Run")
+	}
+	if glctx != nil {
+		return nil, errors.New("creating window failed: an app can only have one Window")
+	}
+
+	theApp.mu.Lock()
+	drawContext := theApp.glctx
+	theApp.mu.Unlock()
+	ctx, err := newContext(drawContext)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating window failed")
+	}
+	glctx = ctx
+
+	w := &Window{
+		bounds:        theApp.screenBounds(),
+		vsync:         cfg.VSync,
+		cursorVisible: true,
+	}
+	w.canvas = newCanvas(ctx, w.bounds)
+	theApp.mu.Lock()
+	theApp.window = w
+	theApp.mu.Unlock()
+	return w, nil
+}
+
+// Destroy destroys the Window. The Window, its Canvases and its Pictures can't be used any
+// further.
+func (w *Window) Destroy() {
+	theApp.mu.Lock()
+	theApp.window = nil
+	theApp.mu.Unlock()
+	glctx = nil
+}
+
+// Update shows the content of the Window and polls events. Call this method at the end of each
+// frame. It waits until the frame is shown, so the game runs at the frame rate of the screen, and
+// it pauses while the app isn't visible.
+func (w *Window) Update() {
+	ctx := w.canvas.ctx
+	tex := w.canvas.tex
+	screen := theApp.screenBounds()
+	ctx.bindFramebuffer(glReadFramebuffer, w.canvas.fbo)
+	ctx.bindScreen(glDrawFramebuffer)
+	ctx.blitFramebuffer(
+		0, 0, tex.width, tex.height,
+		0, 0, int(screen.W()), int(screen.H()),
+		glNearest,
+	)
+	ctx.unbindFramebuffer()
+
+	select {
+	case theApp.present <- struct{}{}:
+	default:
+	}
+	theApp.app.Send(paint.Event{})
+	if !w.Closed() {
+		<-theApp.shown
+	}
+
+	if b := theApp.screenBounds(); b != w.bounds {
+		w.SetBounds(b)
+	}
+	w.UpdateInput()
+}
+
+// SetClosed sets the closed flag of the Window.
+//
+// An app doesn't close itself, so this is only useful to end the loop of the game.
+func (w *Window) SetClosed(closed bool) {
+	theApp.mu.Lock()
+	defer theApp.mu.Unlock()
+	theApp.closed = closed
+}
+
+// Closed returns the closed flag of the Window, which is set when the app is stopped.
+func (w *Window) Closed() bool {
+	theApp.mu.Lock()
+	defer theApp.mu.Unlock()
+	return theApp.closed
+}
+
+// SetTitle does nothing, the name of an app is set when it's built.
+func (w *Window) SetTitle(title string) {}
+
+// SetBounds sets the bounds of the Window in pixels. The Window is stretched to the screen, and
+// the bounds are reset to the size of the screen when it changes.
+func (w *Window) SetBounds(bounds pixel.Rect) {
+	w.bounds = bounds
+	w.canvas.SetBounds(bounds)
+}
+
+// Bounds returns the current bounds of the Window.
+func (w *Window) Bounds() pixel.Rect {
+	return w.bounds
+}
+
+// SetMonitor does nothing, an app is always fullscreen.
+func (w *Window) SetMonitor(monitor *Monitor) {}
+
+// Monitor returns the screen, an app is always fullscreen.
+func (w *Window) Monitor() *Monitor {
+	return PrimaryMonitor()
+}
+
+// Focused returns true if the app is in the foreground.
+func (w *Window) Focused() bool {
+	theApp.mu.Lock()
+	defer theApp.mu.Unlock()
+	return theApp.focused
+}
+
+// SetVSync does nothing but remembering vsync, the app always synchronizes the frames with the
+// screen.
+func (w *Window) SetVSync(vsync bool) {
+	w.vsync = vsync
+}
+
+// VSync returns whether the Window is set to synchronize with the monitor refresh rate.
+func (w *Window) VSync() bool {
+	return w.vsync
+}
+
+// SetCursorVisible does nothing but remembering visible, the phones have no mouse cursor.
+func (w *Window) SetCursorVisible(visible bool) {
+	w.cursorVisible = visible
+}
+
+// CursorVisible returns the visibility status of the mouse cursor.
+func (w *Window) CursorVisible() bool {
+	return w.cursorVisible
+}
+
+// MakeTriangles generates a specialized copy of the supplied Triangles that will draw onto this
+// Window.
+//
+// Window supports TrianglesPosition, TrianglesColor and TrianglesPicture.
+func (w *Window) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	return w.canvas.MakeTriangles(t)
+}
+
+// MakePicture generates a specialized copy of the supplied Picture that will draw onto this Window.
+//
+// Window supports PictureColor.
+func (w *Window) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	return w.canvas.MakePicture(p)
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (w *Window) SetMatrix(m pixel.Matrix) {
+	w.canvas.SetMatrix(m)
+}
+
+// SetColorMask sets a global color mask for the Window.
+func (w *Window) SetColorMask(c color.Color) {
+	w.canvas.SetColorMask(c)
+}
+
+// SetComposeMethod sets a Porter-Duff composition method to be used in the following draws onto
+// this Window.
+func (w *Window) SetComposeMethod(cmp pixel.ComposeMethod) {
+	w.canvas.SetComposeMethod(cmp)
+}
+
+// SetSmooth sets whether the stretched Pictures drawn onto this Window should be drawn smooth or
+// pixely.
+func (w *Window) SetSmooth(smooth bool) {
+	w.canvas.SetSmooth(smooth)
+}
+
+// Smooth returns whether the stretched Pictures drawn onto this Window are set to be drawn smooth
+// or pixely.
+func (w *Window) Smooth() bool {
+	return w.canvas.Smooth()
+}
+
+// Clear clears the Window with a single color.
+func (w *Window) Clear(c color.Color) {
+	w.canvas.Clear(c)
+}
+
+// ClearRect fills the rectangle of the Window with a single color, see Canvas.ClearRect.
+func (w *Window) ClearRect(r pixel.Rect, c color.Color) {
+	w.canvas.ClearRect(r, c)
+}
+
+// Color returns the color of the pixel over the given position inside the Window.
+func (w *Window) Color(at pixel.Vec) pixel.RGBA {
+	return w.canvas.Color(at)
+}
+
+// Canvas returns the window's underlying Canvas
+func (w *Window) Canvas() *Canvas {
+	return w.canvas
+}
@@ -0,0 +1,244 @@
+//go:build purego && !js && !android && !ios
+// +build purego,!js,!android,!ios
+
+package pixelgl
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelsw"
+)
+
+// WindowConfig is a structure for specifying all possible properties of a Window. Properties are
+// chosen in such a way, that you usually only need to set a few of them - defaults (zeros) should
+// usually be sensible.
+//
+// With the purego build tag, the Window is a pixelsw.Window, so only the Title, the Bounds,
+// Resizable and Undecorated apply, the other properties are ignored.
+//
+// Note that you always need to set the Bounds of a Window.
+type WindowConfig struct {
+	// Title at the top of the Window.
+	Title string
+
+	// Icon is ignored.
+	Icon []pixel.Picture
+
+	// Bounds specify the bounds of the Window in pixels.
+	Bounds pixel.Rect
+
+	// Monitor is ignored, the Windows are always windowed.
+	Monitor *Monitor
+
+	// Whether the Window is resizable.
+	Resizable bool
+
+	// Undecorated Window ommits the borders and decorations (close button, etc.).
+	Undecorated bool
+
+	// VSync, Debug, GLES, GLES2 and Linear are ignored, there's no OpenGL.
+	VSync  bool
+	Debug  bool
+	GLES   bool
+	GLES2  bool
+	Linear bool
+}
+
+// Window is a window drawn in software by pixelsw. Use this type to manipulate a window (input,
+// drawing, etc.).
+type Window struct {
+	win    *pixelsw.Window
+	canvas *Canvas
+	vsync  bool
+
+	// the joysticks stay disconnected, pixelsw has none
+	prevJoy, currJoy joystickState
+}
+
+var _ pixel.ComposeTarget = (*Window)(nil)
+
+// Run calls the function through pixelsw.Run. The Windows don't need the main thread, so Run only
+// keeps the programs the same as with OpenGL.
+func Run(run func()) {
+	pixelsw.Run(run)
+}
+
+// NewWindow creates a new Window with it's properties specified in the provided config, by
+// pixelsw.NewWindow.
+//
+// If Window creation fails, an error is returned (e.g. due to no windowing system to connect to).
+func NewWindow(cfg WindowConfig) (*Window, error) {
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{
+		Title:       cfg.Title,
+		Bounds:      cfg.Bounds,
+		Resizable:   cfg.Resizable,
+		Undecorated: cfg.Undecorated,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Window{
+		win:    win,
+		canvas: &Canvas{canvas: win.Canvas(), col: pixel.Alpha(1)},
+		vsync:  cfg.VSync,
+	}, nil
+}
+
+// Destroy destroys the Window. The Window can't be used any further.
+func (w *Window) Destroy() {
+	w.win.Destroy()
+}
+
+// Update shows the content of the Window and polls events. Call this method at the end of each
+// frame.
+func (w *Window) Update() {
+	w.win.Update()
+}
+
+// UpdateInput polls window events. Call this function to poll window events without showing the
+// content of the Window. Note that the Update method invokes UpdateInput.
+func (w *Window) UpdateInput() {
+	w.win.UpdateInput()
+}
+
+// SetClosed sets the closed flag of the Window.
+//
+// This is useful when overriding the user's attempt to close the Window, or just to close the
+// Window from within the program.
+func (w *Window) SetClosed(closed bool) {
+	w.win.SetClosed(closed)
+}
+
+// Closed returns the closed flag of the Window, which reports whether the Window should be closed.
+//
+// The closed flag is automatically set when a user attempts to close the Window.
+func (w *Window) Closed() bool {
+	return w.win.Closed()
+}
+
+// SetTitle changes the title of the Window.
+func (w *Window) SetTitle(title string) {
+	w.win.SetTitle(title)
+}
+
+// Bounds returns the current bounds of the Window.
+func (w *Window) Bounds() pixel.Rect {
+	return w.win.Bounds()
+}
+
+// SetMonitor does nothing, the Windows are always windowed.
+func (w *Window) SetMonitor(monitor *Monitor) {}
+
+// Monitor returns nil, the Windows are always windowed.
+func (w *Window) Monitor() *Monitor {
+	return nil
+}
+
+// Focused returns true if the Window has input focus.
+func (w *Window) Focused() bool {
+	return w.win.Focused()
+}
+
+// SetVSync does nothing but remembering vsync, the frames are sent to the windowing system as
+// images.
+func (w *Window) SetVSync(vsync bool) {
+	w.vsync = vsync
+}
+
+// VSync returns whether the Window is set to synchronize with the monitor refresh rate.
+func (w *Window) VSync() bool {
+	return w.vsync
+}
+
+// MakeTriangles generates a specialized copy of the supplied Triangles that will draw onto this
+// Window.
+//
+// Window supports TrianglesPosition, TrianglesColor and TrianglesPicture.
+func (w *Window) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	return w.win.MakeTriangles(t)
+}
+
+// MakePicture generates a specialized copy of the supplied Picture that will draw onto this Window.
+//
+// Window supports PictureColor.
+func (w *Window) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	return w.canvas.MakePicture(p)
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (w *Window) SetMatrix(m pixel.Matrix) {
+	w.win.SetMatrix(m)
+}
+
+// SetColorMask sets a global color mask for the Window.
+func (w *Window) SetColorMask(c color.Color) {
+	w.canvas.SetColorMask(c)
+}
+
+// SetComposeMethod sets a Porter-Duff composition method to be used in the following draws onto
+// this Window.
+func (w *Window) SetComposeMethod(cmp pixel.ComposeMethod) {
+	w.win.SetComposeMethod(cmp)
+}
+
+// SetSmooth sets whether the stretched Pictures drawn onto this Window should be drawn smooth or
+// pixely.
+func (w *Window) SetSmooth(smooth bool) {
+	w.win.SetSmooth(smooth)
+}
+
+// Smooth returns whether the stretched Pictures drawn onto this Window are set to be drawn smooth
+// or pixely.
+func (w *Window) Smooth() bool {
+	return w.win.Smooth()
+}
+
+// Clear clears the Window with a single color.
+func (w *Window) Clear(c color.Color) {
+	w.canvas.Clear(c)
+}
+
+// ClearRect fills the rectangle of the Window with a single color, see Canvas.ClearRect.
+func (w *Window) ClearRect(r pixel.Rect, c color.Color) {
+	w.canvas.ClearRect(r, c)
+}
+
+// Color returns the color of the pixel over the given position inside the Window.
+func (w *Window) Color(at pixel.Vec) pixel.RGBA {
+	return w.win.Color(at)
+}
+
+// Canvas returns the window's underlying Canvas
+func (w *Window) Canvas() *Canvas {
+	return w.canvas
+}
+
+// Screenshot returns the content of the Window as it was last drawn, the top row first. The pixels
+// are opaque, composed over black as they appear on the screen.
+func (w *Window) Screenshot() *image.RGBA {
+	_, _, width, height := intBounds(w.canvas.Bounds())
+	return screenshotImage(w.canvas.Pixels(), width, height)
+}
+
+// ScreenshotAsync takes a screenshot of the Window like Screenshot and passes it to the done
+// function from another goroutine. The pixels are in memory, so they're read right away.
+func (w *Window) ScreenshotAsync(done func(img *image.RGBA)) {
+	img := w.Screenshot()
+	go done(img)
+}
+
+// Monitor represents a physical display attached to your computer. With the purego build tag,
+// there are no Monitors, pixelsw doesn't show the Windows fullscreen.
+type Monitor struct{}
+
+// PrimaryMonitor returns nil, there are no Monitors with the purego build tag.
+func PrimaryMonitor() *Monitor {
+	return nil
+}
+
+// Monitors returns no Monitors with the purego build tag.
+func Monitors() []*Monitor {
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build linux || freebsd || netbsd || openbsd || dragonfly || solaris
+// +build linux freebsd netbsd openbsd dragonfly solaris
+
+package pixelsw
+
+import (
+	"fmt"
+	"os"
+)
+
+// openBackend connects to the windowing system selected by the config
+func openBackend(cfg WindowConfig) (backend, error) {
+	switch cfg.Backend {
+	case BackendX11:
+		return openX11(cfg)
+	case BackendWayland:
+		return openWayland(cfg)
+	case BackendAuto:
+	default:
+		return nil, fmt.Errorf("pixelsw: invalid backend %d", cfg.Backend)
+	}
+
+	if os.Getenv("WAYLAND_DISPLAY") == "" {
+		return openX11(cfg)
+	}
+	wl, err := openWayland(cfg)
+	if err == nil {
+		return wl, nil
+	}
+	if os.Getenv("DISPLAY") == "" {
+		return nil, err
+	}
+	x, xerr := openX11(cfg)
+	if xerr != nil {
+		return nil, fmt.Errorf("%v, and %v", err, xerr)
+	}
+	return x, nil
+}
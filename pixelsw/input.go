@@ -0,0 +1,336 @@
+package pixelsw
+
+import "github.com/faiface/pixel"
+
+// Pressed returns whether the Button is currently pressed down.
+func (w *Window) Pressed(button Button) bool {
+	return w.currInp.buttons[button]
+}
+
+// JustPressed returns whether the Button has just been pressed down.
+func (w *Window) JustPressed(button Button) bool {
+	return w.currInp.buttons[button] && !w.prevInp.buttons[button]
+}
+
+// JustReleased returns whether the Button has just been released up.
+func (w *Window) JustReleased(button Button) bool {
+	return !w.currInp.buttons[button] && w.prevInp.buttons[button]
+}
+
+// Repeated returns whether a repeat event has been triggered on button.
+//
+// Repeat event occurs repeatedly when a button is held down for some time.
+func (w *Window) Repeated(button Button) bool {
+	return w.currInp.repeat[button]
+}
+
+// MousePosition returns the current mouse position in the Window's Bounds.
+func (w *Window) MousePosition() pixel.Vec {
+	return w.currInp.mouse
+}
+
+// MousePreviousPosition returns the previous mouse position in the Window's Bounds.
+func (w *Window) MousePreviousPosition() pixel.Vec {
+	return w.prevInp.mouse
+}
+
+// MouseInsideWindow returns true if the mouse position is within the Window's Bounds.
+func (w *Window) MouseInsideWindow() bool {
+	return w.cursorInsideWindow
+}
+
+// MouseScroll returns the mouse scroll amount (in both axes) since the last call to Window.Update.
+func (w *Window) MouseScroll() pixel.Vec {
+	return w.currInp.scroll
+}
+
+// Typed returns the text typed on the keyboard since the last call to Window.Update.
+func (w *Window) Typed() string {
+	return w.currInp.typed
+}
+
+// Button is a keyboard or mouse button, with the same values as pixelgl.Button.
+type Button int
+
+// List of all mouse buttons.
+const (
+	MouseButton1      = Button(0)
+	MouseButton2      = Button(1)
+	MouseButton3      = Button(2)
+	MouseButton4      = Button(3)
+	MouseButton5      = Button(4)
+	MouseButton6      = Button(5)
+	MouseButton7      = Button(6)
+	MouseButton8      = Button(7)
+	MouseButtonLast   = Button(7)
+	MouseButtonLeft   = Button(0)
+	MouseButtonRight  = Button(1)
+	MouseButtonMiddle = Button(2)
+)
+
+// List of all keyboard buttons.
+const (
+	KeyUnknown      = Button(-1)
+	KeySpace        = Button(32)
+	KeyApostrophe   = Button(39)
+	KeyComma        = Button(44)
+	KeyMinus        = Button(45)
+	KeyPeriod       = Button(46)
+	KeySlash        = Button(47)
+	Key0            = Button(48)
+	Key1            = Button(49)
+	Key2            = Button(50)
+	Key3            = Button(51)
+	Key4            = Button(52)
+	Key5            = Button(53)
+	Key6            = Button(54)
+	Key7            = Button(55)
+	Key8            = Button(56)
+	Key9            = Button(57)
+	KeySemicolon    = Button(59)
+	KeyEqual        = Button(61)
+	KeyA            = Button(65)
+	KeyB            = Button(66)
+	KeyC            = Button(67)
+	KeyD            = Button(68)
+	KeyE            = Button(69)
+	KeyF            = Button(70)
+	KeyG            = Button(71)
+	KeyH            = Button(72)
+	KeyI            = Button(73)
+	KeyJ            = Button(74)
+	KeyK            = Button(75)
+	KeyL            = Button(76)
+	KeyM            = Button(77)
+	KeyN            = Button(78)
+	KeyO            = Button(79)
+	KeyP            = Button(80)
+	KeyQ            = Button(81)
+	KeyR            = Button(82)
+	KeyS            = Button(83)
+	KeyT            = Button(84)
+	KeyU            = Button(85)
+	KeyV            = Button(86)
+	KeyW            = Button(87)
+	KeyX            = Button(88)
+	KeyY            = Button(89)
+	KeyZ            = Button(90)
+	KeyLeftBracket  = Button(91)
+	KeyBackslash    = Button(92)
+	KeyRightBracket = Button(93)
+	KeyGraveAccent  = Button(96)
+	KeyWorld1       = Button(161)
+	KeyWorld2       = Button(162)
+	KeyEscape       = Button(256)
+	KeyEnter        = Button(257)
+	KeyTab          = Button(258)
+	KeyBackspace    = Button(259)
+	KeyInsert       = Button(260)
+	KeyDelete       = Button(261)
+	KeyRight        = Button(262)
+	KeyLeft         = Button(263)
+	KeyDown         = Button(264)
+	KeyUp           = Button(265)
+	KeyPageUp       = Button(266)
+	KeyPageDown     = Button(267)
+	KeyHome         = Button(268)
+	KeyEnd          = Button(269)
+	KeyCapsLock     = Button(280)
+	KeyScrollLock   = Button(281)
+	KeyNumLock      = Button(282)
+	KeyPrintScreen  = Button(283)
+	KeyPause        = Button(284)
+	KeyF1           = Button(290)
+	KeyF2           = Button(291)
+	KeyF3           = Button(292)
+	KeyF4           = Button(293)
+	KeyF5           = Button(294)
+	KeyF6           = Button(295)
+	KeyF7           = Button(296)
+	KeyF8           = Button(297)
+	KeyF9           = Button(298)
+	KeyF10          = Button(299)
+	KeyF11          = Button(300)
+	KeyF12          = Button(301)
+	KeyF13          = Button(302)
+	KeyF14          = Button(303)
+	KeyF15          = Button(304)
+	KeyF16          = Button(305)
+	KeyF17          = Button(306)
+	KeyF18          = Button(307)
+	KeyF19          = Button(308)
+	KeyF20          = Button(309)
+	KeyF21          = Button(310)
+	KeyF22          = Button(311)
+	KeyF23          = Button(312)
+	KeyF24          = Button(313)
+	KeyF25          = Button(314)
+	KeyKP0          = Button(320)
+	KeyKP1          = Button(321)
+	KeyKP2          = Button(322)
+	KeyKP3          = Button(323)
+	KeyKP4          = Button(324)
+	KeyKP5          = Button(325)
+	KeyKP6          = Button(326)
+	KeyKP7          = Button(327)
+	KeyKP8          = Button(328)
+	KeyKP9          = Button(329)
+	KeyKPDecimal    = Button(330)
+	KeyKPDivide     = Button(331)
+	KeyKPMultiply   = Button(332)
+	KeyKPSubtract   = Button(333)
+	KeyKPAdd        = Button(334)
+	KeyKPEnter      = Button(335)
+	KeyKPEqual      = Button(336)
+	KeyLeftShift    = Button(340)
+	KeyLeftControl  = Button(341)
+	KeyLeftAlt      = Button(342)
+	KeyLeftSuper    = Button(343)
+	KeyRightShift   = Button(344)
+	KeyRightControl = Button(345)
+	KeyRightAlt     = Button(346)
+	KeyRightSuper   = Button(347)
+	KeyMenu         = Button(348)
+	KeyLast         = Button(348)
+)
+
+// String returns a human-readable string describing the Button.
+func (b Button) String() string {
+	name, ok := buttonNames[b]
+	if !ok {
+		return "Invalid"
+	}
+	return name
+}
+
+var buttonNames = map[Button]string{
+	MouseButton4:      "MouseButton4",
+	MouseButton5:      "MouseButton5",
+	MouseButton6:      "MouseButton6",
+	MouseButton7:      "MouseButton7",
+	MouseButton8:      "MouseButton8",
+	MouseButtonLeft:   "MouseButtonLeft",
+	MouseButtonRight:  "MouseButtonRight",
+	MouseButtonMiddle: "MouseButtonMiddle",
+	KeyUnknown:        "Unknown",
+	KeySpace:          "Space",
+	KeyApostrophe:     "Apostrophe",
+	KeyComma:          "Comma",
+	KeyMinus:          "Minus",
+	KeyPeriod:         "Period",
+	KeySlash:          "Slash",
+	Key0:              "0",
+	Key1:              "1",
+	Key2:              "2",
+	Key3:              "3",
+	Key4:              "4",
+	Key5:              "5",
+	Key6:              "6",
+	Key7:              "7",
+	Key8:              "8",
+	Key9:              "9",
+	KeySemicolon:      "Semicolon",
+	KeyEqual:          "Equal",
+	KeyA:              "A",
+	KeyB:              "B",
+	KeyC:              "C",
+	KeyD:              "D",
+	KeyE:              "E",
+	KeyF:              "F",
+	KeyG:              "G",
+	KeyH:              "H",
+	KeyI:              "I",
+	KeyJ:              "J",
+	KeyK:              "K",
+	KeyL:              "L",
+	KeyM:              "M",
+	KeyN:              "N",
+	KeyO:              "O",
+	KeyP:              "P",
+	KeyQ:              "Q",
+	KeyR:              "R",
+	KeyS:              "S",
+	KeyT:              "T",
+	KeyU:              "U",
+	KeyV:              "V",
+	KeyW:              "W",
+	KeyX:              "X",
+	KeyY:              "Y",
+	KeyZ:              "Z",
+	KeyLeftBracket:    "LeftBracket",
+	KeyBackslash:      "Backslash",
+	KeyRightBracket:   "RightBracket",
+	KeyGraveAccent:    "GraveAccent",
+	KeyWorld1:         "World1",
+	KeyWorld2:         "World2",
+	KeyEscape:         "Escape",
+	KeyEnter:          "Enter",
+	KeyTab:            "Tab",
+	KeyBackspace:      "Backspace",
+	KeyInsert:         "Insert",
+	KeyDelete:         "Delete",
+	KeyRight:          "Right",
+	KeyLeft:           "Left",
+	KeyDown:           "Down",
+	KeyUp:             "Up",
+	KeyPageUp:         "PageUp",
+	KeyPageDown:       "PageDown",
+	KeyHome:           "Home",
+	KeyEnd:            "End",
+	KeyCapsLock:       "CapsLock",
+	KeyScrollLock:     "ScrollLock",
+	KeyNumLock:        "NumLock",
+	KeyPrintScreen:    "PrintScreen",
+	KeyPause:          "Pause",
+	KeyF1:             "F1",
+	KeyF2:             "F2",
+	KeyF3:             "F3",
+	KeyF4:             "F4",
+	KeyF5:             "F5",
+	KeyF6:             "F6",
+	KeyF7:             "F7",
+	KeyF8:             "F8",
+	KeyF9:             "F9",
+	KeyF10:            "F10",
+	KeyF11:            "F11",
+	KeyF12:            "F12",
+	KeyF13:            "F13",
+	KeyF14:            "F14",
+	KeyF15:            "F15",
+	KeyF16:            "F16",
+	KeyF17:            "F17",
+	KeyF18:            "F18",
+	KeyF19:            "F19",
+	KeyF20:            "F20",
+	KeyF21:            "F21",
+	KeyF22:            "F22",
+	KeyF23:            "F23",
+	KeyF24:            "F24",
+	KeyF25:            "F25",
+	KeyKP0:            "KP0",
+	KeyKP1:            "KP1",
+	KeyKP2:            "KP2",
+	KeyKP3:            "KP3",
+	KeyKP4:            "KP4",
+	KeyKP5:            "KP5",
+	KeyKP6:            "KP6",
+	KeyKP7:            "KP7",
+	KeyKP8:            "KP8",
+	KeyKP9:            "KP9",
+	KeyKPDecimal:      "KPDecimal",
+	KeyKPDivide:       "KPDivide",
+	KeyKPMultiply:     "KPMultiply",
+	KeyKPSubtract:     "KPSubtract",
+	KeyKPAdd:          "KPAdd",
+	KeyKPEnter:        "KPEnter",
+	KeyKPEqual:        "KPEqual",
+	KeyLeftShift:      "LeftShift",
+	KeyLeftControl:    "LeftControl",
+	KeyLeftAlt:        "LeftAlt",
+	KeyLeftSuper:      "LeftSuper",
+	KeyRightShift:     "RightShift",
+	KeyRightControl:   "RightControl",
+	KeyRightAlt:       "RightAlt",
+	KeyRightSuper:     "RightSuper",
+	KeyMenu:           "Menu",
+}
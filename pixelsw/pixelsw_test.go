@@ -0,0 +1,324 @@
+//go:build linux
+// +build linux
+
+package pixelsw_test
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelsw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var le = binary.LittleEndian
+
+// server is a fake X server accepting a single client, which records the requests and sends the
+// events
+type server struct {
+	conn net.Conn
+
+	mu     sync.Mutex
+	window uint32
+	titles []string
+	images [][]byte
+}
+
+const (
+	keycodeA      = 38
+	keycodeEscape = 9
+)
+
+func serve(t *testing.T) *server {
+	dir, err := ioutil.TempDir("", "pixelsw")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "X0")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	t.Setenv("DISPLAY", socket+":0")
+	t.Setenv("XAUTHORITY", filepath.Join(dir, "Xauthority"))
+	t.Setenv("WAYLAND_DISPLAY", "")
+
+	s := &server{}
+	accepted := make(chan struct{})
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		s.conn = conn
+		close(accepted)
+		s.run()
+	}()
+	t.Cleanup(func() {
+		select {
+		case <-accepted:
+			s.conn.Close()
+		default:
+		}
+	})
+	return s
+}
+
+func (s *server) run() {
+	// the setup request, without authorization
+	setup := make([]byte, 12)
+	if _, err := io.ReadFull(s.conn, setup); err != nil {
+		return
+	}
+
+	var reply []byte
+	reply = append(reply, 1, 0)
+	reply = put16(reply, 11, 0, 0) // the length is set below
+	reply = put32(reply, 0, 0x200000, 0x1fffff, 0)
+	reply = put16(reply, 0, 0xffff)   // vendor, maximum request length
+	reply = append(reply, 1, 1, 0, 0) // screens, formats, byte and bit order
+	reply = append(reply, 32, 32, 8, 255, 0, 0, 0, 0)
+	reply = append(reply, 24, 32, 32, 0, 0, 0, 0, 0) // the format of depth 24
+	reply = put32(reply, 0x100, 0, 0xffffff, 0, 0)   // root, colormap, white, black, masks
+	reply = put16(reply, 1920, 1080, 500, 300, 1, 1)
+	reply = put32(reply, 0x21)                      // root visual
+	reply = append(reply, 0, 0, 24, 1)              // backing stores, save unders, depth, depths
+	reply = append(reply, 24, 0, 1, 0, 0, 0, 0, 0)  // depth 24 with 1 visual
+	reply = put32(reply, 0x21)                      // visual
+	reply = append(reply, 4, 8, 0, 1)               // TrueColor
+	reply = put32(reply, 0xff0000, 0xff00, 0xff, 0) // masks
+	le.PutUint16(reply[6:], uint16((len(reply)-8)/4))
+	s.conn.Write(reply)
+
+	var seq uint16
+	for {
+		head := make([]byte, 4)
+		if _, err := io.ReadFull(s.conn, head); err != nil {
+			return
+		}
+		body := make([]byte, 4*int(le.Uint16(head[2:]))-4)
+		if _, err := io.ReadFull(s.conn, body); err != nil {
+			return
+		}
+		seq++
+
+		switch head[0] {
+		case 1: // CreateWindow
+			s.mu.Lock()
+			s.window = le.Uint32(body)
+			s.mu.Unlock()
+		case 16: // InternAtom, the atoms are numbered by the requests
+			r := []byte{1, 0}
+			r = put16(r, seq)
+			r = put32(r, 0, 100+uint32(seq))
+			s.conn.Write(append(r, make([]byte, 20)...))
+		case 18: // ChangeProperty
+			if le.Uint32(body[4:]) == 39 { // WM_NAME
+				s.mu.Lock()
+				s.titles = append(s.titles, string(body[20:20+le.Uint32(body[16:])]))
+				s.mu.Unlock()
+			}
+		case 72: // PutImage
+			s.mu.Lock()
+			s.images = append(s.images, body[20:])
+			s.mu.Unlock()
+		case 101: // GetKeyboardMapping, with 2 keysyms per keycode
+			first, count := body[0], int(body[1])
+			r := []byte{1, 2}
+			r = put16(r, seq)
+			r = put32(r, uint32(2*count))
+			r = append(r, make([]byte, 24)...)
+			for k := int(first); k < int(first)+count; k++ {
+				switch k {
+				case keycodeA:
+					r = put32(r, 'a', 'A')
+				case keycodeEscape:
+					r = put32(r, 0xff1b, 0)
+				default:
+					r = put32(r, 0, 0)
+				}
+			}
+			s.conn.Write(r)
+		}
+	}
+}
+
+// send sends the events at once
+func (s *server) send(events ...[]byte) {
+	var data []byte
+	for _, e := range events {
+		data = append(data, e...)
+	}
+	s.conn.Write(data)
+}
+
+// event returns the event of the code, with the fields at their offsets
+func event(code byte, detail byte, fields map[int][]byte) []byte {
+	e := make([]byte, 32)
+	e[0], e[1] = code, detail
+	for off, data := range fields {
+		copy(e[off:], data)
+	}
+	return e
+}
+
+// state returns the recorded window, titles and the last image
+func (s *server) state() (window uint32, titles []string, image []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.images) > 0 {
+		image = s.images[len(s.images)-1]
+	}
+	return s.window, append([]string(nil), s.titles...), image
+}
+
+func put16(b []byte, vs ...uint16) []byte {
+	for _, v := range vs {
+		b = append(b, byte(v), byte(v>>8))
+	}
+	return b
+}
+
+func put32(b []byte, vs ...uint32) []byte {
+	for _, v := range vs {
+		b = append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+	}
+	return b
+}
+
+// eventually waits until the condition holds, which is checked after the messages in flight arrive
+func eventually(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the X messages")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// update updates the input of the Window until the condition holds
+func update(t *testing.T, win *pixelsw.Window, cond func() bool) {
+	eventually(t, func() bool {
+		win.UpdateInput()
+		return cond()
+	})
+}
+
+func TestWindow(t *testing.T) {
+	s := serve(t)
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{
+		Title:  "Game",
+		Bounds: pixel.R(0, 0, 4, 3),
+	})
+	require.NoError(t, err)
+	defer win.Destroy()
+	assert.Equal(t, pixelsw.BackendX11, win.Backend())
+
+	eventually(t, func() bool {
+		window, titles, _ := s.state()
+		return window != 0 && len(titles) == 1
+	})
+	_, titles, _ := s.state()
+	assert.Equal(t, []string{"Game"}, titles)
+
+	win.SetTitle("Paused")
+	eventually(t, func() bool {
+		_, titles, _ := s.state()
+		return len(titles) == 2
+	})
+	_, titles, _ = s.state()
+	assert.Equal(t, "Paused", titles[1])
+}
+
+func TestWindow_Update(t *testing.T) {
+	s := serve(t)
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{Bounds: pixel.R(0, 0, 2, 2)})
+	require.NoError(t, err)
+	defer win.Destroy()
+
+	// the bottom row is red, the top row is blue
+	win.Canvas().SetPixels([]uint8{
+		255, 0, 0, 255, 255, 0, 0, 255,
+		0, 0, 255, 255, 0, 0, 255, 255,
+	})
+	win.Update()
+	eventually(t, func() bool {
+		_, _, img := s.state()
+		return img != nil
+	})
+	// the rows go down as BGRX
+	_, _, img := s.state()
+	assert.Equal(t, []byte{
+		255, 0, 0, 0, 255, 0, 0, 0,
+		0, 0, 255, 0, 0, 0, 255, 0,
+	}, img)
+}
+
+func TestWindow_input(t *testing.T) {
+	s := serve(t)
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{Bounds: pixel.R(0, 0, 100, 50)})
+	require.NoError(t, err)
+	defer win.Destroy()
+
+	// a shifted press of A types an upper case letter
+	s.send(event(2, keycodeA, map[int][]byte{4: put32(nil, 1), 28: put16(nil, 1)}))
+	update(t, win, func() bool { return win.Pressed(pixelsw.KeyA) })
+	assert.True(t, win.JustPressed(pixelsw.KeyA))
+	assert.Equal(t, "A", win.Typed())
+
+	// a release and a press at the same time is a repeat
+	s.send(
+		event(3, keycodeA, map[int][]byte{4: put32(nil, 2)}),
+		event(2, keycodeA, map[int][]byte{4: put32(nil, 2)}),
+	)
+	update(t, win, func() bool { return win.Repeated(pixelsw.KeyA) })
+	assert.True(t, win.Pressed(pixelsw.KeyA))
+	assert.False(t, win.JustPressed(pixelsw.KeyA))
+	assert.Equal(t, "a", win.Typed())
+
+	s.send(event(3, keycodeA, map[int][]byte{4: put32(nil, 3)}))
+	update(t, win, func() bool { return win.JustReleased(pixelsw.KeyA) })
+
+	s.send(event(2, keycodeEscape, nil))
+	update(t, win, func() bool { return win.Pressed(pixelsw.KeyEscape) })
+	assert.Equal(t, "", win.Typed())
+
+	// the mouse positions go up from the bottom-left corner
+	s.send(
+		event(6, 0, map[int][]byte{24: put16(nil, 10, 20)}),
+		event(4, 1, nil),
+	)
+	update(t, win, func() bool { return win.Pressed(pixelsw.MouseButtonLeft) })
+	assert.Equal(t, pixel.V(10, 30), win.MousePosition())
+
+	s.send(event(4, 4, nil))
+	update(t, win, func() bool { return win.MouseScroll() != pixel.ZV })
+	assert.Equal(t, pixel.V(0, 1), win.MouseScroll())
+
+	// the window manager asks to close the window by the atoms of WM_PROTOCOLS and
+	// WM_DELETE_WINDOW, numbered by the fake server
+	window, _, _ := s.state()
+	s.send(event(33|0x80, 32, map[int][]byte{4: put32(nil, window, 101, 102)}))
+	update(t, win, win.Closed)
+	win.SetClosed(false)
+	assert.False(t, win.Closed())
+}
+
+func TestNewWindow_noServer(t *testing.T) {
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("DISPLAY", filepath.Join(os.TempDir(), "pixelsw-none")+":0")
+	_, err := pixelsw.NewWindow(pixelsw.WindowConfig{Bounds: pixel.R(0, 0, 10, 10)})
+	assert.Error(t, err)
+
+	t.Setenv("DISPLAY", "")
+	_, err = pixelsw.NewWindow(pixelsw.WindowConfig{Bounds: pixel.R(0, 0, 10, 10)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !freebsd && !netbsd && !openbsd && !dragonfly && !solaris && !windows
+// +build !linux,!freebsd,!netbsd,!openbsd,!dragonfly,!solaris,!windows
+
+package pixelsw
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// openBackend fails, the windowing systems of the other platforms, e.g. Cocoa on macOS, can't be
+// reached without cgo
+func openBackend(cfg WindowConfig) (backend, error) {
+	return nil, fmt.Errorf("pixelsw: no backend for %s, use pixelgl there", runtime.GOOS)
+}
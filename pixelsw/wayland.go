@@ -0,0 +1,923 @@
+//go:build linux || freebsd || netbsd || openbsd || dragonfly || solaris
+// +build linux freebsd netbsd openbsd dragonfly solaris
+
+package pixelsw
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// wayland is a window of a Wayland compositor, talking to it by the Wayland protocol directly.
+// The frames are shared with the compositor in a file.
+//
+// The requests are written and the messages are handled by the goroutine of the Window, the
+// messages are read by a separate goroutine.
+type wayland struct {
+	conn   *net.UnixConn
+	out    []byte
+	outFDs []int
+	in     []byte
+	nextID uint32
+	cfg    WindowConfig
+
+	globals map[string]wlGlobal
+
+	registry, compositor, shm, wmBase, seat, pointer, keyboard      uint32
+	decorationManager, fractionalManager, viewporter                uint32
+	surface, xdgSurface, toplevel, decoration, fractional, viewport uint32
+
+	// width and height are the size of the content in logical pixels, without the title bar
+	width, height      int
+	pendingW, pendingH int
+	configured         bool
+	scale              float64
+	csd, activated     bool
+	title              string
+	early              []event
+	pix                []byte
+	file               *os.File
+	pool               uint32
+	buffers            []wlBuffer
+
+	pointerX, pointerY float64
+	serial             uint32
+	inBar              bool
+
+	mods, locked            uint32
+	repeatRate, repeatDelay int
+	held                    *wlHeldKey
+
+	mu        sync.Mutex
+	msgs      []wlMessage
+	err       error
+	destroyed bool
+}
+
+type wlGlobal struct {
+	name, version uint32
+}
+
+// wlBuffer is a frame in the file shared with the compositor, busy until the compositor
+// releases it
+type wlBuffer struct {
+	id                    uint32
+	offset, width, height int
+	busy                  bool
+}
+
+// wlHeldKey is the key repeated while it's held, Wayland leaves the repeating to the clients
+type wlHeldKey struct {
+	key    uint32
+	button Button
+	next   time.Time
+}
+
+type wlMessage struct {
+	object uint32
+	opcode uint16
+	args   []byte
+}
+
+// barHeight is the height of the title bar drawn by pixelsw in logical pixels
+const barHeight = 24
+
+func openWayland(cfg WindowConfig) (backend, error) {
+	display := os.Getenv("WAYLAND_DISPLAY")
+	if display == "" {
+		display = "wayland-0"
+	}
+	path := display
+	if !filepath.IsAbs(path) {
+		dir := os.Getenv("XDG_RUNTIME_DIR")
+		if dir == "" {
+			return nil, fmt.Errorf("pixelsw: failed to connect to the Wayland compositor: XDG_RUNTIME_DIR isn't set")
+		}
+		path = filepath.Join(dir, display)
+	}
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("pixelsw: failed to connect to the Wayland compositor: %v", err)
+	}
+	wl := &wayland{
+		conn:        conn,
+		nextID:      1, // the wl_display
+		cfg:         cfg,
+		globals:     make(map[string]wlGlobal),
+		width:       size(cfg.Bounds.W()),
+		height:      size(cfg.Bounds.H()),
+		scale:       1,
+		title:       cfg.Title,
+		repeatRate:  25,
+		repeatDelay: 600,
+	}
+	if err := wl.init(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go wl.read()
+	return wl, nil
+}
+
+// init binds the globals and creates the window, reading the messages until it's configured
+func (wl *wayland) init() error {
+	wl.registry = wl.newID()
+	wl.request(1, 1, wlArgs{}.uint(wl.registry)) // wl_display.get_registry
+	if err := wl.roundtrip(); err != nil {
+		return err
+	}
+
+	bind := func(iface string, version uint32, required bool) (uint32, error) {
+		g, ok := wl.globals[iface]
+		if !ok {
+			if required {
+				return 0, fmt.Errorf("pixelsw: Wayland compositor doesn't support %s", iface)
+			}
+			return 0, nil
+		}
+		if g.version < version {
+			version = g.version
+		}
+		id := wl.newID()
+		wl.request(wl.registry, 0, wlArgs{}.uint(g.name).string(iface).uint(version).uint(id)) // wl_registry.bind
+		return id, nil
+	}
+	var err error
+	for _, b := range []struct {
+		id       *uint32
+		iface    string
+		version  uint32
+		required bool
+	}{
+		{&wl.compositor, "wl_compositor", 4, true},
+		{&wl.shm, "wl_shm", 1, true},
+		{&wl.wmBase, "xdg_wm_base", 1, true},
+		{&wl.seat, "wl_seat", 5, false},
+		{&wl.decorationManager, "zxdg_decoration_manager_v1", 1, false},
+		{&wl.fractionalManager, "wp_fractional_scale_manager_v1", 1, false},
+		{&wl.viewporter, "wp_viewporter", 1, false},
+	} {
+		if *b.id, err = bind(b.iface, b.version, b.required); err != nil {
+			return err
+		}
+	}
+
+	wl.surface = wl.newID()
+	wl.request(wl.compositor, 0, wlArgs{}.uint(wl.surface)) // wl_compositor.create_surface
+	wl.xdgSurface = wl.newID()
+	wl.request(wl.wmBase, 2, wlArgs{}.uint(wl.xdgSurface).uint(wl.surface)) // xdg_wm_base.get_xdg_surface
+	wl.toplevel = wl.newID()
+	wl.request(wl.xdgSurface, 1, wlArgs{}.uint(wl.toplevel)) // xdg_surface.get_toplevel
+	wl.setTitle(wl.title)
+
+	if wl.decorationManager != 0 {
+		wl.decoration = wl.newID()
+		wl.request(wl.decorationManager, 1, wlArgs{}.uint(wl.decoration).uint(wl.toplevel)) // get_toplevel_decoration
+		// the client side decorations of an undecorated Window are none
+		const clientSide, serverSide = 1, 2
+		mode := uint32(serverSide)
+		if wl.cfg.Undecorated {
+			mode = clientSide
+		}
+		wl.request(wl.decoration, 1, wlArgs{}.uint(mode)) // zxdg_toplevel_decoration_v1.set_mode
+	} else {
+		wl.csd = wl.cfg.ClientDecorations && !wl.cfg.Undecorated
+	}
+	if wl.cfg.HiDPI && wl.fractionalManager != 0 && wl.viewporter != 0 {
+		wl.fractional = wl.newID()
+		wl.request(wl.fractionalManager, 1, wlArgs{}.uint(wl.fractional).uint(wl.surface)) // get_fractional_scale
+		wl.viewport = wl.newID()
+		wl.request(wl.viewporter, 1, wlArgs{}.uint(wl.viewport).uint(wl.surface)) // wp_viewporter.get_viewport
+	}
+	if !wl.cfg.Resizable {
+		w, h := wl.width, wl.height+wl.bar()
+		wl.request(wl.toplevel, 7, wlArgs{}.int(w).int(h)) // xdg_toplevel.set_max_size
+		wl.request(wl.toplevel, 8, wlArgs{}.int(w).int(h)) // xdg_toplevel.set_min_size
+	}
+
+	// the surface is configured after the first commit, before any buffer is attached
+	wl.request(wl.surface, 6, nil) // wl_surface.commit
+	wl.flush()
+	for !wl.configured {
+		msgs, err := wl.readMessages()
+		if err != nil {
+			return fmt.Errorf("pixelsw: failed to connect to the Wayland compositor: %v", err)
+		}
+		for _, m := range msgs {
+			wl.dispatch(m, wl.queue)
+		}
+		if err := wl.failed(); err != nil {
+			return err
+		}
+	}
+	wl.flush()
+	return wl.failed()
+}
+
+// roundtrip handles the messages until the compositor handles all the requests sent before
+func (wl *wayland) roundtrip() error {
+	callback := wl.newID()
+	wl.request(1, 0, wlArgs{}.uint(callback)) // wl_display.sync
+	wl.flush()
+	for {
+		msgs, err := wl.readMessages()
+		if err != nil {
+			return fmt.Errorf("pixelsw: failed to connect to the Wayland compositor: %v", err)
+		}
+		done := false
+		for _, m := range msgs {
+			if m.object == callback && m.opcode == 0 {
+				done = true
+			}
+			wl.dispatch(m, wl.queue)
+		}
+		if err := wl.failed(); err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// queue keeps the events handled before the first poll
+func (wl *wayland) queue(e event) {
+	wl.early = append(wl.early, e)
+}
+
+func (wl *wayland) newID() uint32 {
+	wl.nextID++
+	return wl.nextID
+}
+
+// request writes the request, which is sent by flush
+func (wl *wayland) request(object uint32, opcode uint16, args wlArgs) {
+	msg := wlArgs{}.uint(object).uint(uint32(8+len(args))<<16 | uint32(opcode))
+	wl.out = append(append(wl.out, msg...), args...)
+}
+
+func (wl *wayland) flush() {
+	if len(wl.out) == 0 {
+		return
+	}
+	var oob []byte
+	if len(wl.outFDs) > 0 {
+		oob = syscall.UnixRights(wl.outFDs...)
+	}
+	if _, _, err := wl.conn.WriteMsgUnix(wl.out, oob, nil); err != nil {
+		wl.fail(err)
+	}
+	wl.out, wl.outFDs = wl.out[:0], nil
+}
+
+func (wl *wayland) fail(err error) {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+	if wl.err == nil {
+		wl.err = err
+	}
+}
+
+func (wl *wayland) failed() error {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+	if wl.err != nil {
+		return fmt.Errorf("pixelsw: lost the connection to the Wayland compositor: %v", wl.err)
+	}
+	return nil
+}
+
+// readMessages reads the messages which arrive at once
+func (wl *wayland) readMessages() ([]wlMessage, error) {
+	buf := make([]byte, 4096)
+	oob := make([]byte, syscall.CmsgSpace(4*28))
+	n, oobn, _, _, err := wl.conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return nil, err
+	}
+	// the only file descriptor sent to the clients handled here is the keymap, which is unused
+	if cmsgs, err := syscall.ParseSocketControlMessage(oob[:oobn]); err == nil {
+		for i := range cmsgs {
+			fds, _ := syscall.ParseUnixRights(&cmsgs[i])
+			for _, fd := range fds {
+				syscall.Close(fd)
+			}
+		}
+	}
+
+	wl.in = append(wl.in, buf[:n]...)
+	var msgs []wlMessage
+	for len(wl.in) >= 8 {
+		header := hostEndian.Uint32(wl.in[4:])
+		size := int(header >> 16)
+		if size < 8 {
+			return nil, fmt.Errorf("invalid message")
+		}
+		if len(wl.in) < size {
+			break
+		}
+		msgs = append(msgs, wlMessage{
+			object: hostEndian.Uint32(wl.in),
+			opcode: uint16(header),
+			args:   append([]byte(nil), wl.in[8:size]...),
+		})
+		wl.in = wl.in[size:]
+	}
+	return msgs, nil
+}
+
+// read reads the messages until the connection is closed
+func (wl *wayland) read() {
+	for {
+		msgs, err := wl.readMessages()
+		if err != nil {
+			wl.fail(err)
+			return
+		}
+		wl.mu.Lock()
+		wl.msgs = append(wl.msgs, msgs...)
+		wl.mu.Unlock()
+	}
+}
+
+// bar returns the height of the title bar in logical pixels, zero if it isn't drawn
+func (wl *wayland) bar() int {
+	if wl.csd {
+		return barHeight
+	}
+	return 0
+}
+
+// dispatch handles the message, the events of the Window are handled by handle
+func (wl *wayland) dispatch(m wlMessage, handle func(event)) {
+	args := &wlReader{m.args}
+	switch m.object {
+	case 1: // wl_display
+		if m.opcode == 0 { // error
+			object, code, message := args.uint(), args.uint(), args.string()
+			wl.fail(fmt.Errorf("%s (error %d of object %d)", message, code, object))
+		}
+	case wl.registry:
+		if m.opcode == 0 { // global
+			name, iface, version := args.uint(), args.string(), args.uint()
+			wl.globals[iface] = wlGlobal{name: name, version: version}
+		}
+	case wl.wmBase:
+		if m.opcode == 0 { // ping
+			wl.request(wl.wmBase, 3, wlArgs{}.uint(args.uint())) // xdg_wm_base.pong
+		}
+	case wl.toplevel:
+		switch m.opcode {
+		case 0: // configure
+			wl.pendingW, wl.pendingH = int(args.int()), int(args.int())
+			states := args.array()
+			wl.activated = false
+			for i := 0; i+4 <= len(states); i += 4 {
+				const activated = 4
+				if hostEndian.Uint32(states[i:]) == activated {
+					wl.activated = true
+				}
+			}
+		case 1: // close
+			handle(event{kind: closeEvent})
+		}
+	case wl.xdgSurface:
+		if m.opcode == 0 { // configure
+			wl.request(wl.xdgSurface, 4, wlArgs{}.uint(args.uint())) // xdg_surface.ack_configure
+			wl.configured = true
+			if wl.cfg.Resizable && wl.pendingW > 0 && wl.pendingH > 0 {
+				w, h := wl.pendingW, wl.pendingH-wl.bar()
+				if h < 1 {
+					h = 1
+				}
+				if w != wl.width || h != wl.height {
+					wl.width, wl.height = w, h
+					handle(event{kind: resizeEvent, x: float64(w), y: float64(h)})
+				}
+			}
+		}
+	case wl.decoration:
+		if m.opcode == 0 { // configure
+			const clientSide = 1
+			wl.csd = args.uint() == clientSide && wl.cfg.ClientDecorations && !wl.cfg.Undecorated
+		}
+	case wl.fractional:
+		if m.opcode == 0 { // preferred_scale
+			if scale := float64(args.uint()) / 120; scale > 0 && scale != wl.scale {
+				wl.scale = scale
+				handle(event{kind: scaleEvent, x: scale})
+			}
+		}
+	case wl.seat:
+		if m.opcode == 0 { // capabilities
+			const pointer, keyboard = 1, 2
+			caps := args.uint()
+			if caps&pointer != 0 && wl.pointer == 0 {
+				wl.pointer = wl.newID()
+				wl.request(wl.seat, 0, wlArgs{}.uint(wl.pointer)) // wl_seat.get_pointer
+			}
+			if caps&keyboard != 0 && wl.keyboard == 0 {
+				wl.keyboard = wl.newID()
+				wl.request(wl.seat, 1, wlArgs{}.uint(wl.keyboard)) // wl_seat.get_keyboard
+			}
+		}
+	case wl.pointer:
+		wl.dispatchPointer(m.opcode, args, handle)
+	case wl.keyboard:
+		wl.dispatchKeyboard(m.opcode, args, handle)
+	default:
+		for i := range wl.buffers {
+			if wl.buffers[i].id == m.object && m.opcode == 0 { // release
+				wl.buffers[i].busy = false
+			}
+		}
+	}
+}
+
+func (wl *wayland) dispatchPointer(opcode uint16, args *wlReader, handle func(event)) {
+	switch opcode {
+	case 0: // enter
+		wl.serial = args.uint()
+		args.uint()
+		wl.pointerX, wl.pointerY = args.fixed(), args.fixed()
+		handle(event{kind: enterEvent})
+		wl.motion(handle)
+	case 1: // leave
+		handle(event{kind: leaveEvent})
+	case 2: // motion
+		args.uint()
+		wl.pointerX, wl.pointerY = args.fixed(), args.fixed()
+		wl.motion(handle)
+	case 3: // button
+		wl.serial = args.uint()
+		args.uint()
+		code, pressed := args.uint(), args.uint() == 1
+		const btnLeft = 0x110
+		if wl.inBar {
+			if code == btnLeft && pressed {
+				if wl.pointerX >= float64(wl.width-barHeight) {
+					handle(event{kind: closeEvent})
+				} else {
+					wl.request(wl.toplevel, 5, wlArgs{}.uint(wl.seat).uint(wl.serial)) // xdg_toplevel.move
+				}
+			}
+			return
+		}
+		if code >= btnLeft && code < btnLeft+uint32(len(wlMouseButtons)) {
+			handle(event{kind: buttonEvent, button: wlMouseButtons[code-btnLeft], pressed: pressed})
+		}
+	case 4: // axis
+		args.uint()
+		axis, value := args.uint(), args.fixed()/10
+		if axis == 0 {
+			handle(event{kind: scrollEvent, y: -value})
+		} else {
+			handle(event{kind: scrollEvent, x: value})
+		}
+	}
+}
+
+var wlMouseButtons = []Button{MouseButtonLeft, MouseButtonRight, MouseButtonMiddle, MouseButton4, MouseButton5}
+
+// motion handles the position of the pointer, which goes to the title bar or to the content
+func (wl *wayland) motion(handle func(event)) {
+	bar := float64(wl.bar())
+	wl.inBar = wl.pointerY < bar
+	if !wl.inBar {
+		handle(event{kind: motionEvent, x: wl.pointerX, y: wl.pointerY - bar})
+	}
+}
+
+func (wl *wayland) dispatchKeyboard(opcode uint16, args *wlReader, handle func(event)) {
+	switch opcode {
+	case 1: // enter
+		handle(event{kind: focusEvent})
+	case 2: // leave
+		wl.held = nil
+		handle(event{kind: unfocusEvent})
+	case 3: // key
+		args.uint()
+		args.uint()
+		key, pressed := args.uint(), args.uint() == 1
+		button := evdevButton(key)
+		if button != KeyUnknown {
+			handle(event{kind: buttonEvent, button: button, pressed: pressed})
+		}
+		if !pressed {
+			if wl.held != nil && wl.held.key == key {
+				wl.held = nil
+			}
+			return
+		}
+		wl.typed(handle, key)
+		if wl.repeatRate > 0 && !modifier(button) {
+			delay := time.Duration(wl.repeatDelay) * time.Millisecond
+			wl.held = &wlHeldKey{key: key, button: button, next: time.Now().Add(delay)}
+		}
+	case 4: // modifiers
+		args.uint()
+		depressed, latched, locked := args.uint(), args.uint(), args.uint()
+		wl.mods, wl.locked = depressed|latched, locked
+	case 5: // repeat_info
+		wl.repeatRate, wl.repeatDelay = int(args.int()), int(args.int())
+		if wl.repeatRate <= 0 {
+			wl.held = nil
+		}
+	}
+}
+
+// typed handles the character typed by the key, by the US layout
+func (wl *wayland) typed(handle func(event), key uint32) {
+	const shift, lock, control, alt = 1 << 0, 1 << 1, 1 << 2, 1 << 3
+	if wl.mods&(control|alt) != 0 {
+		return
+	}
+	runes, ok := evdevRunes[key]
+	if !ok {
+		return
+	}
+	shifted := wl.mods&shift != 0
+	if wl.locked&lock != 0 && runes[0] >= 'a' && runes[0] <= 'z' {
+		shifted = !shifted
+	}
+	if shifted {
+		handle(event{kind: charEvent, char: runes[1]})
+	} else {
+		handle(event{kind: charEvent, char: runes[0]})
+	}
+}
+
+func modifier(button Button) bool {
+	switch button {
+	case KeyLeftShift, KeyRightShift, KeyLeftControl, KeyRightControl, KeyLeftAlt, KeyRightAlt,
+		KeyLeftSuper, KeyRightSuper, KeyCapsLock, KeyNumLock, KeyScrollLock:
+		return true
+	}
+	return false
+}
+
+func (wl *wayland) setTitle(title string) {
+	wl.title = title
+	wl.request(wl.toplevel, 2, wlArgs{}.string(title)) // xdg_toplevel.set_title
+	wl.flush()
+}
+
+// buffer returns a free buffer of the size in pixels, creating the buffers of a new size, nil if
+// all of them are busy
+func (wl *wayland) buffer(width, height int) *wlBuffer {
+	if len(wl.buffers) == 0 || wl.buffers[0].width != width || wl.buffers[0].height != height {
+		wl.releaseBuffers()
+
+		stride := 4 * width
+		size := 2 * stride * height
+		dir := os.Getenv("XDG_RUNTIME_DIR")
+		if dir == "" {
+			dir = os.TempDir()
+		}
+		file, err := ioutil.TempFile(dir, "pixelsw-")
+		if err != nil {
+			wl.fail(err)
+			return nil
+		}
+		os.Remove(file.Name())
+		if err := file.Truncate(int64(size)); err != nil {
+			file.Close()
+			wl.fail(err)
+			return nil
+		}
+		wl.file = file
+
+		wl.pool = wl.newID()
+		wl.request(wl.shm, 0, wlArgs{}.uint(wl.pool).int(size)) // wl_shm.create_pool
+		wl.outFDs = append(wl.outFDs, int(file.Fd()))
+		for i := 0; i < 2; i++ {
+			b := wlBuffer{id: wl.newID(), offset: i * stride * height, width: width, height: height}
+			const xrgb8888 = 1
+			args := wlArgs{}.uint(b.id).int(b.offset).int(width).int(height).int(stride).uint(xrgb8888)
+			wl.request(wl.pool, 0, args) // wl_shm_pool.create_buffer
+			wl.buffers = append(wl.buffers, b)
+		}
+	}
+	for i := range wl.buffers {
+		if !wl.buffers[i].busy {
+			return &wl.buffers[i]
+		}
+	}
+	return nil
+}
+
+// releaseBuffers destroys the buffers, the pool and the file
+func (wl *wayland) releaseBuffers() {
+	for _, b := range wl.buffers {
+		wl.request(b.id, 0, nil) // wl_buffer.destroy
+	}
+	wl.buffers = nil
+	if wl.pool != 0 {
+		wl.request(wl.pool, 1, nil) // wl_shm_pool.destroy
+		wl.pool = 0
+	}
+	if wl.file != nil {
+		wl.flush() // the file descriptor may be waiting to be sent
+		wl.file.Close()
+		wl.file = nil
+	}
+}
+
+func (wl *wayland) present(img *image.RGBA) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	if width == 0 || height == 0 {
+		return
+	}
+	bar := int(math.Round(float64(wl.bar()) * wl.scale))
+	b := wl.buffer(width, height+bar)
+	if b == nil {
+		// the compositor didn't show the previous frames yet
+		wl.flush()
+		return
+	}
+
+	frame := img
+	if bar > 0 {
+		frame = image.NewRGBA(image.Rect(0, 0, width, height+bar))
+		wl.drawBar(frame.SubImage(image.Rect(0, 0, width, bar)).(*image.RGBA))
+		draw.Draw(frame, image.Rect(0, bar, width, height+bar), img, img.Rect.Min, draw.Src)
+	}
+
+	// the premultiplied colors are drawn over black
+	wl.pix = wl.pix[:0]
+	for y := 0; y < frame.Rect.Dy(); y++ {
+		row := frame.Pix[y*frame.Stride : y*frame.Stride+4*width]
+		for i := 0; i < len(row); i += 4 {
+			p := uint32(row[i])<<16 | uint32(row[i+1])<<8 | uint32(row[i+2])
+			var px [4]byte
+			hostEndian.PutUint32(px[:], p)
+			wl.pix = append(wl.pix, px[:]...)
+		}
+	}
+	if _, err := wl.file.WriteAt(wl.pix, int64(b.offset)); err != nil {
+		wl.fail(err)
+		return
+	}
+
+	b.busy = true
+	wl.request(wl.surface, 1, wlArgs{}.uint(b.id).int(0).int(0)) // wl_surface.attach
+	if wl.globals["wl_compositor"].version >= 4 {
+		wl.request(wl.surface, 9, wlArgs{}.int(0).int(0).int(width).int(height+bar)) // wl_surface.damage_buffer
+	} else {
+		wl.request(wl.surface, 2, wlArgs{}.int(0).int(0).int(math.MaxInt32).int(math.MaxInt32)) // wl_surface.damage
+	}
+	if wl.viewport != 0 {
+		wl.request(wl.viewport, 2, wlArgs{}.int(wl.width).int(wl.height+wl.bar())) // wp_viewport.set_destination
+	}
+	wl.request(wl.surface, 6, nil) // wl_surface.commit
+	wl.flush()
+}
+
+// drawBar draws the title bar with the title and a close button at the right
+func (wl *wayland) drawBar(img *image.RGBA) {
+	background := color.RGBA{0x50, 0x50, 0x50, 0xff}
+	if wl.activated {
+		background = color.RGBA{0x30, 0x30, 0x30, 0xff}
+	}
+	draw.Draw(img, img.Rect, image.NewUniform(background), image.Point{}, draw.Src)
+
+	h := img.Rect.Dy()
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.White,
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(h/3, (h+basicfont.Face7x13.Ascent-basicfont.Face7x13.Descent)/2),
+	}
+	d.DrawString(wl.title)
+
+	// the cross of the close button, in the square at the right
+	x0, inset := img.Rect.Dx()-h, h/3
+	thickness := int(math.Max(1, math.Round(wl.scale)))
+	for i := inset; i < h-inset; i++ {
+		for t := 0; t < thickness; t++ {
+			img.Set(x0+i+t, i, color.White)
+			img.Set(x0+i+t, h-1-i, color.White)
+		}
+	}
+}
+
+func (wl *wayland) poll(handle func(event)) {
+	wl.mu.Lock()
+	msgs, err := wl.msgs, wl.err
+	wl.msgs = nil
+	wl.mu.Unlock()
+
+	for _, e := range wl.early {
+		handle(e)
+	}
+	wl.early = nil
+	if err != nil {
+		handle(event{kind: closeEvent})
+	}
+	for _, m := range msgs {
+		wl.dispatch(m, handle)
+	}
+
+	if wl.held != nil {
+		if now := time.Now(); !now.Before(wl.held.next) {
+			handle(event{kind: repeatEvent, button: wl.held.button})
+			wl.typed(handle, wl.held.key)
+			wl.held.next = now.Add(time.Second / time.Duration(wl.repeatRate))
+		}
+	}
+	wl.flush()
+}
+
+func (wl *wayland) kind() Backend {
+	return BackendWayland
+}
+
+func (wl *wayland) destroy() {
+	wl.mu.Lock()
+	destroyed := wl.destroyed
+	wl.destroyed = true
+	wl.mu.Unlock()
+	if destroyed {
+		return
+	}
+	for _, id := range []uint32{wl.viewport, wl.fractional, wl.decoration, wl.toplevel, wl.xdgSurface} {
+		if id != 0 {
+			wl.request(id, 0, nil) // destroy
+		}
+	}
+	wl.releaseBuffers()
+	wl.request(wl.surface, 0, nil) // wl_surface.destroy
+	wl.flush()
+	wl.conn.Close()
+}
+
+// hostEndian is the byte order of the Wayland protocol, the one of the machine
+var hostEndian binary.ByteOrder = func() binary.ByteOrder {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 0 {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}()
+
+// wlArgs builds the arguments of a request
+type wlArgs []byte
+
+func (a wlArgs) uint(v uint32) wlArgs {
+	var b [4]byte
+	hostEndian.PutUint32(b[:], v)
+	return append(a, b[:]...)
+}
+
+func (a wlArgs) int(v int) wlArgs {
+	return a.uint(uint32(int32(v)))
+}
+
+func (a wlArgs) string(s string) wlArgs {
+	a = a.uint(uint32(len(s) + 1))
+	return pad(append(append(a, s...), 0))
+}
+
+// wlReader reads the arguments of an event, which are zero past the end
+type wlReader struct {
+	b []byte
+}
+
+func (r *wlReader) uint() uint32 {
+	if len(r.b) < 4 {
+		r.b = nil
+		return 0
+	}
+	v := hostEndian.Uint32(r.b)
+	r.b = r.b[4:]
+	return v
+}
+
+func (r *wlReader) int() int32 {
+	return int32(r.uint())
+}
+
+func (r *wlReader) fixed() float64 {
+	return float64(r.int()) / 256
+}
+
+func (r *wlReader) array() []byte {
+	n := int(r.uint())
+	padded := (n + 3) / 4 * 4
+	if padded > len(r.b) {
+		r.b = nil
+		return nil
+	}
+	a := r.b[:n]
+	r.b = r.b[padded:]
+	return a
+}
+
+func (r *wlReader) string() string {
+	a := r.array()
+	if len(a) == 0 {
+		return ""
+	}
+	return string(a[:len(a)-1])
+}
+
+// evdevButton returns the Button of the Linux key code, KeyUnknown if none
+func evdevButton(key uint32) Button {
+	if runes, ok := evdevRunes[key]; ok {
+		return keysymButton(uint32(runes[0]))
+	}
+	switch {
+	case key >= 59 && key <= 68:
+		return KeyF1 + Button(key-59)
+	case key >= 183 && key <= 194:
+		return KeyF13 + Button(key-183)
+	}
+	if button, ok := evdevButtons[key]; ok {
+		return button
+	}
+	return KeyUnknown
+}
+
+// evdevRunes are the characters typed by the Linux key codes by the US layout, without and with
+// shift
+var evdevRunes = map[uint32][2]rune{
+	2: {'1', '!'}, 3: {'2', '@'}, 4: {'3', '#'}, 5: {'4', '$'}, 6: {'5', '%'},
+	7: {'6', '^'}, 8: {'7', '&'}, 9: {'8', '*'}, 10: {'9', '('}, 11: {'0', ')'},
+	12: {'-', '_'}, 13: {'=', '+'},
+	16: {'q', 'Q'}, 17: {'w', 'W'}, 18: {'e', 'E'}, 19: {'r', 'R'}, 20: {'t', 'T'},
+	21: {'y', 'Y'}, 22: {'u', 'U'}, 23: {'i', 'I'}, 24: {'o', 'O'}, 25: {'p', 'P'},
+	26: {'[', '{'}, 27: {']', '}'},
+	30: {'a', 'A'}, 31: {'s', 'S'}, 32: {'d', 'D'}, 33: {'f', 'F'}, 34: {'g', 'G'},
+	35: {'h', 'H'}, 36: {'j', 'J'}, 37: {'k', 'K'}, 38: {'l', 'L'},
+	39: {';', ':'}, 40: {'\'', '"'}, 41: {'`', '~'}, 43: {'\\', '|'},
+	44: {'z', 'Z'}, 45: {'x', 'X'}, 46: {'c', 'C'}, 47: {'v', 'V'}, 48: {'b', 'B'},
+	49: {'n', 'N'}, 50: {'m', 'M'},
+	51: {',', '<'}, 52: {'.', '>'}, 53: {'/', '?'}, 57: {' ', ' '},
+}
+
+var evdevButtons = map[uint32]Button{
+	1:   KeyEscape,
+	14:  KeyBackspace,
+	15:  KeyTab,
+	28:  KeyEnter,
+	29:  KeyLeftControl,
+	42:  KeyLeftShift,
+	54:  KeyRightShift,
+	55:  KeyKPMultiply,
+	56:  KeyLeftAlt,
+	58:  KeyCapsLock,
+	69:  KeyNumLock,
+	70:  KeyScrollLock,
+	71:  KeyKP7,
+	72:  KeyKP8,
+	73:  KeyKP9,
+	74:  KeyKPSubtract,
+	75:  KeyKP4,
+	76:  KeyKP5,
+	77:  KeyKP6,
+	78:  KeyKPAdd,
+	79:  KeyKP1,
+	80:  KeyKP2,
+	81:  KeyKP3,
+	82:  KeyKP0,
+	83:  KeyKPDecimal,
+	86:  KeyWorld2,
+	87:  KeyF11,
+	88:  KeyF12,
+	96:  KeyKPEnter,
+	97:  KeyRightControl,
+	98:  KeyKPDivide,
+	99:  KeyPrintScreen,
+	100: KeyRightAlt,
+	102: KeyHome,
+	103: KeyUp,
+	104: KeyPageUp,
+	105: KeyLeft,
+	106: KeyRight,
+	107: KeyEnd,
+	108: KeyDown,
+	109: KeyPageDown,
+	110: KeyInsert,
+	111: KeyDelete,
+	117: KeyKPEqual,
+	119: KeyPause,
+	125: KeyLeftSuper,
+	126: KeyRightSuper,
+	127: KeyMenu,
+}
@@ -0,0 +1,456 @@
+//go:build linux
+// +build linux
+
+package pixelsw_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelsw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compositor is a fake Wayland compositor accepting a single client, which records the requests
+// and sends the events
+type compositor struct {
+	conn *net.UnixConn
+
+	// decorationMode is the mode of the decorations, zero if they aren't supported
+	decorationMode uint32
+	// scale is the fractional scale in 120ths, zero if it isn't supported
+	scale uint32
+
+	mu          sync.Mutex
+	objects     map[uint32]string
+	ids         map[string]uint32
+	titles      []string
+	pools       map[uint32]*os.File
+	buffers     map[uint32]testBuffer
+	attached    uint32
+	configured  bool
+	frames      []testFrame
+	destination [2]uint32
+	moves       int
+}
+
+type testBuffer struct {
+	pool                          uint32
+	offset, width, height, stride int
+}
+
+type testFrame struct {
+	width, height int
+	pix           []byte
+}
+
+const (
+	evdevA      = 30
+	evdevEscape = 1
+)
+
+func compose(t *testing.T, c *compositor) *compositor {
+	dir, err := ioutil.TempDir("", "pixelsw")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "wayland-0")
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	t.Setenv("WAYLAND_DISPLAY", socket)
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	t.Setenv("DISPLAY", "")
+
+	c.objects = map[uint32]string{1: "wl_display"}
+	c.ids = make(map[string]uint32)
+	c.pools = make(map[uint32]*os.File)
+	c.buffers = make(map[uint32]testBuffer)
+	accepted := make(chan struct{})
+	go func() {
+		conn, err := l.AcceptUnix()
+		if err != nil {
+			return
+		}
+		c.conn = conn
+		close(accepted)
+		c.run()
+	}()
+	t.Cleanup(func() {
+		select {
+		case <-accepted:
+			c.conn.Close()
+		default:
+		}
+	})
+	return c
+}
+
+func (c *compositor) run() {
+	var data []byte
+	var fds []int
+	for {
+		buf := make([]byte, 1<<16)
+		oob := make([]byte, syscall.CmsgSpace(4*28))
+		n, oobn, _, _, err := c.conn.ReadMsgUnix(buf, oob)
+		if err != nil {
+			return
+		}
+		cmsgs, _ := syscall.ParseSocketControlMessage(oob[:oobn])
+		for i := range cmsgs {
+			rights, _ := syscall.ParseUnixRights(&cmsgs[i])
+			fds = append(fds, rights...)
+		}
+		data = append(data, buf[:n]...)
+		for len(data) >= 8 {
+			size := int(le.Uint32(data[4:]) >> 16)
+			if len(data) < size {
+				break
+			}
+			object, opcode, args := le.Uint32(data), le.Uint16(data[4:]), data[8:size]
+			data = data[size:]
+
+			c.mu.Lock()
+			iface := c.objects[object]
+			c.mu.Unlock()
+			var fd int
+			if iface == "wl_shm" && opcode == 0 {
+				fd, fds = fds[0], fds[1:]
+			}
+			c.handle(iface, object, opcode, args, fd)
+		}
+	}
+}
+
+// create records the new object of the interface
+func (c *compositor) create(id uint32, iface string) {
+	c.objects[id] = iface
+	c.ids[iface] = id
+}
+
+func (c *compositor) handle(iface string, object uint32, opcode uint16, args []byte, fd int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	arg := func(i int) uint32 { return le.Uint32(args[4*i:]) }
+
+	switch iface + "." + string(rune('0'+opcode)) {
+	case "wl_display.0": // sync
+		c.send(arg(0), 0, put32(nil, 0))
+	case "wl_display.1": // get_registry
+		c.create(arg(0), "wl_registry")
+		globals := []string{"wl_compositor", "wl_shm", "xdg_wm_base", "wl_seat"}
+		if c.decorationMode != 0 {
+			globals = append(globals, "zxdg_decoration_manager_v1")
+		}
+		if c.scale != 0 {
+			globals = append(globals, "wp_fractional_scale_manager_v1", "wp_viewporter")
+		}
+		for i, g := range globals {
+			e := put32(nil, uint32(i+1))
+			e = wlString(e, g)
+			c.send(arg(0), 0, put32(e, 4))
+		}
+	case "wl_registry.0": // bind
+		n := int(arg(1))
+		name := string(args[8 : 8+n-1])
+		rest := args[8+(n+3)/4*4:]
+		id := le.Uint32(rest[4:])
+		c.create(id, name)
+		if name == "wl_seat" {
+			c.send(id, 0, put32(nil, 3)) // capabilities, a pointer and a keyboard
+		}
+	case "wl_compositor.0":
+		c.create(arg(0), "wl_surface")
+	case "xdg_wm_base.2":
+		c.create(arg(0), "xdg_surface")
+	case "xdg_surface.1":
+		c.create(arg(0), "xdg_toplevel")
+	case "xdg_toplevel.2": // set_title
+		c.titles = append(c.titles, string(args[4:4+arg(0)-1]))
+	case "xdg_toplevel.5": // move
+		c.moves++
+	case "wl_seat.0":
+		c.create(arg(0), "wl_pointer")
+	case "wl_seat.1":
+		c.create(arg(0), "wl_keyboard")
+	case "wl_shm.0": // create_pool
+		c.create(arg(0), "wl_shm_pool")
+		c.pools[arg(0)] = os.NewFile(uintptr(fd), "pool")
+	case "wl_shm_pool.0": // create_buffer
+		c.create(arg(0), "wl_buffer")
+		c.buffers[arg(0)] = testBuffer{
+			pool:   object,
+			offset: int(arg(1)),
+			width:  int(arg(2)),
+			height: int(arg(3)),
+			stride: int(arg(4)),
+		}
+	case "zxdg_decoration_manager_v1.1":
+		c.create(arg(0), "zxdg_toplevel_decoration_v1")
+	case "zxdg_toplevel_decoration_v1.1": // set_mode
+		c.send(object, 0, put32(nil, c.decorationMode))
+	case "wp_fractional_scale_manager_v1.1":
+		c.create(arg(0), "wp_fractional_scale_v1")
+		c.send(arg(0), 0, put32(nil, c.scale))
+	case "wp_viewporter.1":
+		c.create(arg(0), "wp_viewport")
+	case "wp_viewport.2": // set_destination
+		c.destination = [2]uint32{arg(0), arg(1)}
+	case "wl_surface.1": // attach
+		c.attached = arg(0)
+	case "wl_surface.6": // commit
+		if !c.configured {
+			c.configured = true
+			c.send(c.ids["xdg_toplevel"], 0, put32(nil, 0, 0, 0))
+			c.send(c.ids["xdg_surface"], 0, put32(nil, 1))
+			return
+		}
+		if b, ok := c.buffers[c.attached]; ok {
+			pix := make([]byte, b.stride*b.height)
+			c.pools[b.pool].ReadAt(pix, int64(b.offset))
+			c.frames = append(c.frames, testFrame{b.width, b.height, pix})
+			c.send(c.attached, 0, nil) // release
+		}
+	}
+}
+
+// send sends the event, c.mu must be locked
+func (c *compositor) send(object uint32, opcode uint16, args []byte) {
+	e := put32(nil, object, uint32(8+len(args))<<16|uint32(opcode))
+	c.conn.Write(append(e, args...))
+}
+
+// event sends the event to the object of the interface
+func (c *compositor) event(iface string, opcode uint16, args []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.send(c.ids[iface], opcode, args)
+}
+
+// state returns the recorded titles, the last frame and the destination of the viewport
+func (c *compositor) state() (titles []string, frame testFrame, destination [2]uint32, moves int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.frames) > 0 {
+		frame = c.frames[len(c.frames)-1]
+	}
+	return append([]string(nil), c.titles...), frame, c.destination, c.moves
+}
+
+// has returns whether the client created an object of the interface
+func (c *compositor) has(iface string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ids[iface] != 0
+}
+
+func wlString(b []byte, s string) []byte {
+	b = put32(b, uint32(len(s)+1))
+	b = append(b, s...)
+	b = append(b, 0)
+	for len(b)%4 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+func TestWayland(t *testing.T) {
+	c := compose(t, &compositor{})
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{
+		Title:  "Game",
+		Bounds: pixel.R(0, 0, 2, 2),
+	})
+	require.NoError(t, err)
+	defer win.Destroy()
+	assert.Equal(t, pixelsw.BackendWayland, win.Backend())
+	assert.Equal(t, 1.0, win.Scale())
+
+	win.SetTitle("Paused")
+	eventually(t, func() bool {
+		titles, _, _, _ := c.state()
+		return len(titles) == 2
+	})
+	titles, _, _, _ := c.state()
+	assert.Equal(t, []string{"Game", "Paused"}, titles)
+
+	// the bottom row is red, the top row is blue
+	win.Canvas().SetPixels([]uint8{
+		255, 0, 0, 255, 255, 0, 0, 255,
+		0, 0, 255, 255, 0, 0, 255, 255,
+	})
+	for i := 0; i < 3; i++ {
+		win.Update()
+	}
+	eventually(t, func() bool {
+		_, frame, _, _ := c.state()
+		return frame.pix != nil
+	})
+	// the rows go down as XRGB
+	_, frame, _, _ := c.state()
+	assert.Equal(t, 2, frame.width)
+	assert.Equal(t, []byte{
+		255, 0, 0, 0, 255, 0, 0, 0,
+		0, 0, 255, 0, 0, 0, 255, 0,
+	}, frame.pix)
+}
+
+func TestWayland_input(t *testing.T) {
+	c := compose(t, &compositor{})
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{Bounds: pixel.R(0, 0, 100, 50)})
+	require.NoError(t, err)
+	defer win.Destroy()
+	eventually(t, func() bool { return c.has("wl_keyboard") && c.has("wl_pointer") })
+
+	c.event("wl_keyboard", 1, put32(nil, 1, 0, 0)) // enter
+	update(t, win, win.Focused)
+
+	// a shifted press of A types an upper case letter
+	c.event("wl_keyboard", 4, put32(nil, 2, 1, 0, 0, 0)) // modifiers
+	c.event("wl_keyboard", 3, put32(nil, 3, 0, evdevA, 1))
+	update(t, win, func() bool { return win.Pressed(pixelsw.KeyA) })
+	assert.True(t, win.JustPressed(pixelsw.KeyA))
+	assert.Equal(t, "A", win.Typed())
+
+	c.event("wl_keyboard", 4, put32(nil, 4, 0, 0, 0, 0))
+	c.event("wl_keyboard", 3, put32(nil, 5, 0, evdevA, 0))
+	update(t, win, func() bool { return win.JustReleased(pixelsw.KeyA) })
+
+	c.event("wl_keyboard", 3, put32(nil, 6, 0, evdevEscape, 1))
+	update(t, win, func() bool { return win.Pressed(pixelsw.KeyEscape) })
+	assert.Equal(t, "", win.Typed())
+
+	// the pointer positions go up from the bottom-left corner
+	c.event("wl_pointer", 0, put32(nil, 7, 0, 10*256, 20*256)) // enter
+	c.event("wl_pointer", 3, put32(nil, 8, 0, 0x110, 1))       // button
+	update(t, win, func() bool { return win.Pressed(pixelsw.MouseButtonLeft) })
+	assert.Equal(t, pixel.V(10, 30), win.MousePosition())
+	assert.True(t, win.MouseInsideWindow())
+
+	c.event("wl_pointer", 4, put32(nil, 0, 0, 10*256)) // axis
+	update(t, win, func() bool { return win.MouseScroll() != pixel.ZV })
+	assert.Equal(t, pixel.V(0, -1), win.MouseScroll())
+
+	c.event("xdg_toplevel", 1, nil) // close
+	update(t, win, win.Closed)
+}
+
+func TestWayland_repeat(t *testing.T) {
+	c := compose(t, &compositor{})
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{Bounds: pixel.R(0, 0, 10, 10)})
+	require.NoError(t, err)
+	defer win.Destroy()
+	eventually(t, func() bool { return c.has("wl_keyboard") })
+
+	// the repeating is up to the client
+	c.event("wl_keyboard", 5, put32(nil, 1000, 1)) // repeat_info
+	c.event("wl_keyboard", 3, put32(nil, 1, 0, evdevA, 1))
+	update(t, win, func() bool { return win.Repeated(pixelsw.KeyA) })
+	assert.True(t, win.Pressed(pixelsw.KeyA))
+	assert.Equal(t, "a", win.Typed())
+}
+
+func TestWayland_scale(t *testing.T) {
+	c := compose(t, &compositor{scale: 180})
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{
+		Bounds: pixel.R(0, 0, 4, 2),
+		HiDPI:  true,
+	})
+	require.NoError(t, err)
+	defer win.Destroy()
+
+	win.UpdateInput()
+	assert.Equal(t, 1.5, win.Scale())
+	assert.Equal(t, pixel.R(0, 0, 6, 3), win.Canvas().Bounds())
+
+	// the frames have the pixels of the screen, shown in the logical size
+	win.Update()
+	eventually(t, func() bool {
+		_, frame, _, _ := c.state()
+		return frame.pix != nil
+	})
+	_, frame, destination, _ := c.state()
+	assert.Equal(t, [2]int{6, 3}, [2]int{frame.width, frame.height})
+	assert.Equal(t, [2]uint32{4, 2}, destination)
+}
+
+func TestWayland_decorations(t *testing.T) {
+	c := compose(t, &compositor{})
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{
+		Bounds:            pixel.R(0, 0, 100, 50),
+		ClientDecorations: true,
+	})
+	require.NoError(t, err)
+	defer win.Destroy()
+	eventually(t, func() bool { return c.has("wl_pointer") })
+
+	// the compositor doesn't decorate the windows, so the title bar is drawn above the content
+	win.Update()
+	eventually(t, func() bool {
+		_, frame, _, _ := c.state()
+		return frame.pix != nil
+	})
+	_, frame, _, _ := c.state()
+	assert.Equal(t, [2]int{100, 74}, [2]int{frame.width, frame.height})
+
+	// the content starts below the title bar
+	c.event("wl_pointer", 0, put32(nil, 1, 0, 10*256, 34*256))
+	update(t, win, func() bool { return win.MousePosition() != pixel.ZV })
+	assert.Equal(t, pixel.V(10, 40), win.MousePosition())
+
+	// the title bar moves the window and the cross closes it
+	c.event("wl_pointer", 2, put32(nil, 0, 10*256, 10*256)) // motion
+	c.event("wl_pointer", 3, put32(nil, 2, 0, 0x110, 1))
+	eventually(t, func() bool {
+		win.UpdateInput()
+		_, _, _, moves := c.state()
+		return moves == 1
+	})
+	assert.False(t, win.Pressed(pixelsw.MouseButtonLeft))
+
+	c.event("wl_pointer", 2, put32(nil, 0, 90*256, 10*256))
+	c.event("wl_pointer", 3, put32(nil, 3, 0, 0x110, 1))
+	update(t, win, win.Closed)
+}
+
+func TestWayland_serverDecorations(t *testing.T) {
+	c := compose(t, &compositor{decorationMode: 2})
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{
+		Bounds:            pixel.R(0, 0, 100, 50),
+		ClientDecorations: true,
+	})
+	require.NoError(t, err)
+	defer win.Destroy()
+
+	win.Update()
+	eventually(t, func() bool {
+		_, frame, _, _ := c.state()
+		return frame.pix != nil
+	})
+	_, frame, _, _ := c.state()
+	assert.Equal(t, [2]int{100, 50}, [2]int{frame.width, frame.height})
+}
+
+func TestNewWindow_fallback(t *testing.T) {
+	s := serve(t)
+	t.Setenv("WAYLAND_DISPLAY", filepath.Join(os.TempDir(), "pixelsw-none"))
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{Bounds: pixel.R(0, 0, 10, 10)})
+	require.NoError(t, err)
+	defer win.Destroy()
+	assert.Equal(t, pixelsw.BackendX11, win.Backend())
+	eventually(t, func() bool {
+		window, _, _ := s.state()
+		return window != 0
+	})
+
+	_, err = pixelsw.NewWindow(pixelsw.WindowConfig{
+		Bounds:  pixel.R(0, 0, 10, 10),
+		Backend: pixelsw.BackendWayland,
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,572 @@
+//go:build windows
+// +build windows
+
+package pixelsw
+
+import (
+	"fmt"
+	"image"
+	"runtime"
+	"sync"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+// win32 is a window of the Win32 API, drawn by GDI. The functions are called from the system DLLs
+// by the syscall package, without cgo.
+//
+// The messages of a window go to the thread that created it, so each window has a goroutine
+// locked to its thread, which creates it and dispatches its messages until it's destroyed.
+type win32 struct {
+	hwnd uintptr
+	done chan struct{}
+
+	mu       sync.Mutex
+	events   []event
+	tracking bool
+
+	// surrogate is the high surrogate of the character being typed
+	surrogate uint16
+
+	// pix is the last presented image in the BGRA format of GDI, repainted by WM_PAINT
+	pix           []byte
+	width, height int
+
+	destroyOnce sync.Once
+}
+
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	gdi32    = syscall.NewLazyDLL("gdi32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procRegisterClassExW   = user32.NewProc("RegisterClassExW")
+	procCreateWindowExW    = user32.NewProc("CreateWindowExW")
+	procDefWindowProcW     = user32.NewProc("DefWindowProcW")
+	procDestroyWindow      = user32.NewProc("DestroyWindow")
+	procShowWindow         = user32.NewProc("ShowWindow")
+	procGetMessageW        = user32.NewProc("GetMessageW")
+	procTranslateMessage   = user32.NewProc("TranslateMessage")
+	procDispatchMessageW   = user32.NewProc("DispatchMessageW")
+	procPostMessageW       = user32.NewProc("PostMessageW")
+	procPostQuitMessage    = user32.NewProc("PostQuitMessage")
+	procSetWindowTextW     = user32.NewProc("SetWindowTextW")
+	procAdjustWindowRectEx = user32.NewProc("AdjustWindowRectEx")
+	procLoadCursorW        = user32.NewProc("LoadCursorW")
+	procTrackMouseEvent    = user32.NewProc("TrackMouseEvent")
+	procGetDC              = user32.NewProc("GetDC")
+	procReleaseDC          = user32.NewProc("ReleaseDC")
+	procBeginPaint         = user32.NewProc("BeginPaint")
+	procEndPaint           = user32.NewProc("EndPaint")
+	procSetDIBitsToDevice  = gdi32.NewProc("SetDIBitsToDevice")
+	procGetModuleHandleW   = kernel32.NewProc("GetModuleHandleW")
+)
+
+// the Win32 constants used by the backend
+const (
+	wsOverlappedWindow = 0x00CF0000
+	wsThickFrame       = 0x00040000
+	wsMaximizeBox      = 0x00010000
+	wsPopup            = 0x80000000
+	cwUseDefault       = 0x80000000
+	csVRedraw          = 0x0001
+	csHRedraw          = 0x0002
+	swShow             = 5
+	idcArrow           = 32512
+	sizeMinimized      = 1
+	tmeLeave           = 0x00000002
+	wheelDelta         = 120
+
+	wmDestroy       = 0x0002
+	wmSize          = 0x0005
+	wmSetFocus      = 0x0007
+	wmKillFocus     = 0x0008
+	wmPaint         = 0x000F
+	wmClose         = 0x0010
+	wmEraseBkgnd    = 0x0014
+	wmKeyDown       = 0x0100
+	wmKeyUp         = 0x0101
+	wmChar          = 0x0102
+	wmSysKeyDown    = 0x0104
+	wmSysKeyUp      = 0x0105
+	wmMouseMove     = 0x0200
+	wmLButtonDown   = 0x0201
+	wmLButtonUp     = 0x0202
+	wmRButtonDown   = 0x0204
+	wmRButtonUp     = 0x0205
+	wmMButtonDown   = 0x0207
+	wmMButtonUp     = 0x0208
+	wmMouseWheel    = 0x020A
+	wmXButtonDown   = 0x020B
+	wmXButtonUp     = 0x020C
+	wmMouseHWheel   = 0x020E
+	wmMouseLeave    = 0x02A3
+	wmDestroyWindow = 0x8000 // WM_APP, sent by destroy
+)
+
+type wndClassEx struct {
+	size       uint32
+	style      uint32
+	wndProc    uintptr
+	clsExtra   int32
+	wndExtra   int32
+	instance   uintptr
+	icon       uintptr
+	cursor     uintptr
+	background uintptr
+	menuName   *uint16
+	className  *uint16
+	iconSm     uintptr
+}
+
+type rect struct {
+	left, top, right, bottom int32
+}
+
+type msg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	x, y    int32
+	private uint32
+}
+
+type trackMouseEvent struct {
+	size      uint32
+	flags     uint32
+	hwnd      uintptr
+	hoverTime uint32
+}
+
+type paintStruct struct {
+	hdc       uintptr
+	erase     int32
+	paint     rect
+	restore   int32
+	incUpdate int32
+	reserved  [32]byte
+}
+
+type bitmapInfoHeader struct {
+	size          uint32
+	width         int32
+	height        int32
+	planes        uint16
+	bitCount      uint16
+	compression   uint32
+	sizeImage     uint32
+	xPelsPerMeter int32
+	yPelsPerMeter int32
+	clrUsed       uint32
+	clrImportant  uint32
+}
+
+var (
+	// win32Windows are the windows by their handles, for the window procedure
+	win32Mu      sync.Mutex
+	win32Windows = make(map[uintptr]*win32)
+
+	registerOnce  sync.Once
+	registerErr   error
+	win32Class, _ = syscall.UTF16PtrFromString("pixelsw")
+)
+
+// registerClass registers the window class of the windows, once
+func registerClass() error {
+	registerOnce.Do(func() {
+		instance, _, _ := procGetModuleHandleW.Call(0)
+		cursor, _, _ := procLoadCursorW.Call(0, idcArrow)
+		wc := wndClassEx{
+			style:     csHRedraw | csVRedraw,
+			wndProc:   syscall.NewCallback(wndProc),
+			instance:  instance,
+			cursor:    cursor,
+			className: win32Class,
+		}
+		wc.size = uint32(unsafe.Sizeof(wc))
+		if r, _, err := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); r == 0 {
+			registerErr = fmt.Errorf("pixelsw: failed to register the window class: %v", err)
+		}
+	})
+	return registerErr
+}
+
+func openBackend(cfg WindowConfig) (backend, error) {
+	switch cfg.Backend {
+	case BackendAuto, BackendWin32:
+		return openWin32(cfg)
+	case BackendX11, BackendWayland:
+		return nil, fmt.Errorf("pixelsw: no %v backend on windows", cfg.Backend)
+	default:
+		return nil, fmt.Errorf("pixelsw: invalid backend %d", cfg.Backend)
+	}
+}
+
+func openWin32(cfg WindowConfig) (backend, error) {
+	if err := registerClass(); err != nil {
+		return nil, err
+	}
+	w := &win32{done: make(chan struct{})}
+	created := make(chan error, 1)
+	go w.run(cfg, created)
+	if err := <-created; err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// run creates the window and dispatches its messages on a locked thread, until it's destroyed
+func (w *win32) run(cfg WindowConfig, created chan<- error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(w.done)
+
+	style := uintptr(wsOverlappedWindow)
+	switch {
+	case cfg.Undecorated:
+		style = wsPopup
+	case !cfg.Resizable:
+		style &^= wsThickFrame | wsMaximizeBox
+	}
+	// the Bounds are the size of the client area, without the borders
+	r := rect{right: int32(cfg.Bounds.W()), bottom: int32(cfg.Bounds.H())}
+	procAdjustWindowRectEx.Call(uintptr(unsafe.Pointer(&r)), style, 0, 0)
+
+	title, err := syscall.UTF16PtrFromString(cfg.Title)
+	if err != nil {
+		created <- fmt.Errorf("pixelsw: invalid title: %v", err)
+		return
+	}
+	instance, _, _ := procGetModuleHandleW.Call(0)
+	hwnd, _, err := procCreateWindowExW.Call(
+		0,
+		uintptr(unsafe.Pointer(win32Class)),
+		uintptr(unsafe.Pointer(title)),
+		style,
+		cwUseDefault, cwUseDefault,
+		uintptr(r.right-r.left), uintptr(r.bottom-r.top),
+		0, 0, instance, 0,
+	)
+	if hwnd == 0 {
+		created <- fmt.Errorf("pixelsw: failed to create the window: %v", err)
+		return
+	}
+	w.hwnd = hwnd
+	win32Mu.Lock()
+	win32Windows[hwnd] = w
+	win32Mu.Unlock()
+	procShowWindow.Call(hwnd, swShow)
+	created <- nil
+
+	var m msg
+	for {
+		r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+		if int32(r) <= 0 {
+			return
+		}
+		procTranslateMessage.Call(uintptr(unsafe.Pointer(&m)))
+		procDispatchMessageW.Call(uintptr(unsafe.Pointer(&m)))
+	}
+}
+
+// wndProc is the window procedure of the windows, called on their threads
+func wndProc(hwnd, message, wParam, lParam uintptr) uintptr {
+	win32Mu.Lock()
+	w := win32Windows[hwnd]
+	win32Mu.Unlock()
+	if w == nil {
+		r, _, _ := procDefWindowProcW.Call(hwnd, message, wParam, lParam)
+		return r
+	}
+
+	x, y := float64(int16(lParam)), float64(int16(lParam>>16))
+	switch message {
+	case wmClose:
+		w.queue(event{kind: closeEvent})
+		return 0
+	case wmDestroyWindow:
+		procDestroyWindow.Call(hwnd)
+		return 0
+	case wmDestroy:
+		win32Mu.Lock()
+		delete(win32Windows, hwnd)
+		win32Mu.Unlock()
+		procPostQuitMessage.Call(0)
+		return 0
+	case wmSize:
+		width, height := uint16(lParam), uint16(lParam>>16)
+		if wParam != sizeMinimized && width > 0 && height > 0 {
+			w.queue(event{kind: resizeEvent, x: float64(width), y: float64(height)})
+		}
+	case wmSetFocus:
+		w.queue(event{kind: focusEvent})
+	case wmKillFocus:
+		w.queue(event{kind: unfocusEvent})
+	case wmPaint:
+		var ps paintStruct
+		hdc, _, _ := procBeginPaint.Call(hwnd, uintptr(unsafe.Pointer(&ps)))
+		w.mu.Lock()
+		w.blit(hdc)
+		w.mu.Unlock()
+		procEndPaint.Call(hwnd, uintptr(unsafe.Pointer(&ps)))
+		return 0
+	case wmEraseBkgnd:
+		// the whole client area is painted, erasing it would flicker
+		return 1
+	case wmKeyDown, wmSysKeyDown, wmKeyUp, wmSysKeyUp:
+		pressed := message == wmKeyDown || message == wmSysKeyDown
+		button := vkButton(uint32(wParam), uint32(lParam>>16)&0xff, lParam>>24&1 == 1)
+		switch {
+		case button == KeyUnknown:
+		case pressed && lParam>>30&1 == 1:
+			// the key was already down
+			w.queue(event{kind: repeatEvent, button: button})
+		default:
+			w.queue(event{kind: buttonEvent, button: button, pressed: pressed})
+		}
+		if message == wmKeyDown || message == wmKeyUp {
+			return 0
+		}
+		// the system keys, e.g. Alt+F4, go on to the default procedure
+	case wmChar:
+		if w.char(uint16(wParam)) {
+			return 0
+		}
+	case wmMouseMove:
+		w.mu.Lock()
+		tracking := w.tracking
+		w.tracking = true
+		w.mu.Unlock()
+		if !tracking {
+			tme := trackMouseEvent{flags: tmeLeave, hwnd: hwnd}
+			tme.size = uint32(unsafe.Sizeof(tme))
+			procTrackMouseEvent.Call(uintptr(unsafe.Pointer(&tme)))
+			w.queue(event{kind: enterEvent})
+		}
+		w.queue(event{kind: motionEvent, x: x, y: y})
+		return 0
+	case wmMouseLeave:
+		w.mu.Lock()
+		w.tracking = false
+		w.mu.Unlock()
+		w.queue(event{kind: leaveEvent})
+		return 0
+	case wmLButtonDown, wmLButtonUp, wmRButtonDown, wmRButtonUp, wmMButtonDown, wmMButtonUp:
+		button := MouseButtonLeft
+		switch message {
+		case wmRButtonDown, wmRButtonUp:
+			button = MouseButtonRight
+		case wmMButtonDown, wmMButtonUp:
+			button = MouseButtonMiddle
+		}
+		pressed := message == wmLButtonDown || message == wmRButtonDown || message == wmMButtonDown
+		w.queue(event{kind: buttonEvent, button: button, pressed: pressed})
+		return 0
+	case wmXButtonDown, wmXButtonUp:
+		button := MouseButton4
+		if uint16(wParam>>16) == 2 {
+			button = MouseButton5
+		}
+		w.queue(event{kind: buttonEvent, button: button, pressed: message == wmXButtonDown})
+		return 1
+	case wmMouseWheel:
+		w.queue(event{kind: scrollEvent, y: float64(int16(wParam>>16)) / wheelDelta})
+		return 0
+	case wmMouseHWheel:
+		w.queue(event{kind: scrollEvent, x: float64(int16(wParam>>16)) / wheelDelta})
+		return 0
+	}
+	r, _, _ := procDefWindowProcW.Call(hwnd, message, wParam, lParam)
+	return r
+}
+
+func (w *win32) queue(e event) {
+	w.mu.Lock()
+	w.events = append(w.events, e)
+	w.mu.Unlock()
+}
+
+// char queues the typed character of a WM_CHAR, which comes in UTF-16 units, returning false for
+// the control characters
+//
+// must be called on the thread of the window
+func (w *win32) char(unit uint16) bool {
+	var r rune
+	switch {
+	case utf16.IsSurrogate(rune(unit)) && unit < 0xdc00:
+		// the high surrogate comes first
+		w.surrogate = unit
+		return true
+	case utf16.IsSurrogate(rune(unit)):
+		r = utf16.DecodeRune(rune(w.surrogate), rune(unit))
+		w.surrogate = 0
+	case unit < ' ' || unit == 0x7f:
+		return false
+	default:
+		r = rune(unit)
+	}
+	w.queue(event{kind: charEvent, char: r})
+	return true
+}
+
+// blit draws the last presented image into the device context
+//
+// must be called with the mutex locked
+func (w *win32) blit(hdc uintptr) {
+	if w.width == 0 || w.height == 0 {
+		return
+	}
+	bmi := bitmapInfoHeader{
+		width:    int32(w.width),
+		height:   -int32(w.height), // top-down
+		planes:   1,
+		bitCount: 32,
+	}
+	bmi.size = uint32(unsafe.Sizeof(bmi))
+	procSetDIBitsToDevice.Call(
+		hdc,
+		0, 0, uintptr(w.width), uintptr(w.height),
+		0, 0, 0, uintptr(w.height),
+		uintptr(unsafe.Pointer(&w.pix[0])),
+		uintptr(unsafe.Pointer(&bmi)),
+		0, // DIB_RGB_COLORS
+	)
+}
+
+func (w *win32) present(img *image.RGBA) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.width, w.height = width, height
+	w.pix = w.pix[:0]
+	for y := 0; y < height; y++ {
+		w.pix = append(w.pix, img.Pix[y*img.Stride:y*img.Stride+4*width]...)
+	}
+	// the premultiplied colors are drawn over black
+	for i := 0; i < len(w.pix); i += 4 {
+		r, g, b := w.pix[i], w.pix[i+1], w.pix[i+2]
+		w.pix[i], w.pix[i+1], w.pix[i+2], w.pix[i+3] = b, g, r, 0
+	}
+	hdc, _, _ := procGetDC.Call(w.hwnd)
+	if hdc == 0 {
+		return
+	}
+	w.blit(hdc)
+	procReleaseDC.Call(w.hwnd, hdc)
+}
+
+func (w *win32) poll(handle func(event)) {
+	w.mu.Lock()
+	events := w.events
+	w.events = nil
+	w.mu.Unlock()
+	for _, e := range events {
+		handle(e)
+	}
+}
+
+func (w *win32) setTitle(title string) {
+	p, err := syscall.UTF16PtrFromString(title)
+	if err != nil {
+		return
+	}
+	procSetWindowTextW.Call(w.hwnd, uintptr(unsafe.Pointer(p)))
+}
+
+func (w *win32) kind() Backend {
+	return BackendWin32
+}
+
+func (w *win32) destroy() {
+	w.destroyOnce.Do(func() {
+		procPostMessageW.Call(w.hwnd, wmDestroyWindow, 0, 0)
+		<-w.done
+	})
+}
+
+// vkButton returns the Button of the virtual-key code of a key message, distinguishing the left
+// and the right modifiers by the scan code and the extended flag, KeyUnknown if none
+func vkButton(vk, scancode uint32, extended bool) Button {
+	switch {
+	case vk >= '0' && vk <= '9':
+		return Key0 + Button(vk-'0')
+	case vk >= 'A' && vk <= 'Z':
+		return KeyA + Button(vk-'A')
+	case vk >= 0x60 && vk <= 0x69: // VK_NUMPAD0 to VK_NUMPAD9
+		return KeyKP0 + Button(vk-0x60)
+	case vk >= 0x70 && vk <= 0x87: // VK_F1 to VK_F24
+		return KeyF1 + Button(vk-0x70)
+	}
+	switch vk {
+	case 0x10: // VK_SHIFT
+		if scancode == 0x36 {
+			return KeyRightShift
+		}
+		return KeyLeftShift
+	case 0x11: // VK_CONTROL
+		if extended {
+			return KeyRightControl
+		}
+		return KeyLeftControl
+	case 0x12: // VK_MENU
+		if extended {
+			return KeyRightAlt
+		}
+		return KeyLeftAlt
+	case 0x0D: // VK_RETURN
+		if extended {
+			return KeyKPEnter
+		}
+		return KeyEnter
+	}
+	if button, ok := vkButtons[vk]; ok {
+		return button
+	}
+	return KeyUnknown
+}
+
+// vkButtons are the Buttons of the other virtual-key codes
+var vkButtons = map[uint32]Button{
+	0x08: KeyBackspace,
+	0x09: KeyTab,
+	0x13: KeyPause,
+	0x14: KeyCapsLock,
+	0x1B: KeyEscape,
+	0x20: KeySpace,
+	0x21: KeyPageUp,
+	0x22: KeyPageDown,
+	0x23: KeyEnd,
+	0x24: KeyHome,
+	0x25: KeyLeft,
+	0x26: KeyUp,
+	0x27: KeyRight,
+	0x28: KeyDown,
+	0x2C: KeyPrintScreen,
+	0x2D: KeyInsert,
+	0x2E: KeyDelete,
+	0x5B: KeyLeftSuper,
+	0x5C: KeyRightSuper,
+	0x5D: KeyMenu,
+	0x6A: KeyKPMultiply,
+	0x6B: KeyKPAdd,
+	0x6D: KeyKPSubtract,
+	0x6E: KeyKPDecimal,
+	0x6F: KeyKPDivide,
+	0x90: KeyNumLock,
+	0x91: KeyScrollLock,
+	0xBA: KeySemicolon,
+	0xBB: KeyEqual,
+	0xBC: KeyComma,
+	0xBD: KeyMinus,
+	0xBE: KeyPeriod,
+	0xBF: KeySlash,
+	0xC0: KeyGraveAccent,
+	0xDB: KeyLeftBracket,
+	0xDC: KeyBackslash,
+	0xDD: KeyRightBracket,
+	0xDE: KeyApostrophe,
+	0xE2: KeyWorld2,
+}
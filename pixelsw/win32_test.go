@@ -0,0 +1,40 @@
+//go:build windows
+// +build windows
+
+package pixelsw_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelsw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWin32(t *testing.T) {
+	win, err := pixelsw.NewWindow(pixelsw.WindowConfig{
+		Title:  "Game",
+		Bounds: pixel.R(0, 0, 4, 3),
+	})
+	require.NoError(t, err)
+	defer win.Destroy()
+	assert.Equal(t, pixelsw.BackendWin32, win.Backend())
+
+	win.SetTitle("Paused")
+	win.Canvas().SetPixels(make([]uint8, 4*4*3))
+	win.Update()
+	assert.False(t, win.Closed())
+	assert.Equal(t, pixel.R(0, 0, 4, 3), win.Bounds())
+
+	// destroying twice, e.g. by the finalizer, does nothing
+	win.Destroy()
+}
+
+func TestWin32_backend(t *testing.T) {
+	_, err := pixelsw.NewWindow(pixelsw.WindowConfig{
+		Bounds:  pixel.R(0, 0, 4, 3),
+		Backend: pixelsw.BackendX11,
+	})
+	assert.Error(t, err)
+}
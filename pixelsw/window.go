@@ -0,0 +1,353 @@
+// Package pixelsw implements Windows drawn in software, which talk to the windowing system
+// without cgo. It builds by the plain Go toolchain, so a game cross-compiles and links statically
+// by setting GOOS and GOARCH, e.g. for tools and servers that show a window occasionally.
+//
+// The drawing is done by a raster.Canvas on the CPU and the frames are sent to the windowing
+// system as images, so the feature set is reduced: there are no shaders, no OpenGL and no
+// joysticks. The backends speak the Wayland and the X11 protocols, on Linux and the BSDs, and call
+// Win32 and GDI from the system DLLs on Windows. Wayland is used in Wayland sessions, falling back
+// to X11 (e.g. XWayland) if the connection fails, see WindowConfig.Backend.
+//
+// There is no backend for macOS: Cocoa is an Objective-C API, which Go only reaches by cgo. There,
+// and on the other systems, NewWindow returns an error.
+//
+// The names of the package follow pixelgl, and pixelgl forwards to pixelsw with the build tag
+// purego, so a game written for pixelgl builds without cgo, unchanged:
+//
+//   CGO_ENABLED=0 go build -tags purego
+package pixelsw
+
+import (
+	"image"
+	"image/color"
+	"runtime"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+)
+
+// WindowConfig is a structure for specifying the properties of a Window, a subset of the ones of
+// pixelgl.WindowConfig.
+//
+// Note that you always need to set the Bounds of a Window.
+type WindowConfig struct {
+	// Title at the top of the Window.
+	Title string
+
+	// Bounds specify the bounds of the Window in pixels.
+	Bounds pixel.Rect
+
+	// Whether the Window is resizable.
+	Resizable bool
+
+	// Undecorated Window ommits the borders and decorations (close button, etc.).
+	Undecorated bool
+
+	// ClientDecorations makes the Window draw its own title bar with a close button on Wayland
+	// compositors which don't decorate the windows themselves (e.g. GNOME). Without it, such
+	// Windows have no decorations.
+	ClientDecorations bool
+
+	// HiDPI makes the Window draw in the pixels of the screen, whose scale may be fractional,
+	// e.g. 1.5. The Bounds stay in the logical pixels of the windowing system, see Window.Scale.
+	// Only Wayland compositors supporting fractional scaling (wp_fractional_scale_v1) have a
+	// scale, other Windows have a scale of 1.
+	HiDPI bool
+
+	// Backend selects the windowing system. By default, Wayland is used if WAYLAND_DISPLAY is
+	// set and X11 otherwise, or if the connection to the Wayland compositor fails. On Windows,
+	// the only windowing system is Win32.
+	Backend Backend
+}
+
+// Backend is a windowing system of a Window.
+type Backend int
+
+// List of all the Backends.
+const (
+	// BackendAuto selects the windowing system of the session.
+	BackendAuto Backend = iota
+	BackendX11
+	BackendWayland
+	BackendWin32
+)
+
+// String returns the name of the Backend.
+func (b Backend) String() string {
+	switch b {
+	case BackendAuto:
+		return "auto"
+	case BackendX11:
+		return "X11"
+	case BackendWayland:
+		return "Wayland"
+	case BackendWin32:
+		return "Win32"
+	default:
+		return "Invalid"
+	}
+}
+
+// Window is a window drawn in software. Use this type to manipulate a window (input, drawing,
+// etc.).
+type Window struct {
+	backend backend
+
+	bounds             pixel.Rect
+	scale              float64
+	matrix             pixel.Matrix
+	canvas             *raster.Canvas
+	closed             bool
+	focused            bool
+	cursorInsideWindow bool
+
+	prevInp, currInp, tempInp struct {
+		mouse   pixel.Vec
+		buttons [KeyLast + 1]bool
+		repeat  [KeyLast + 1]bool
+		scroll  pixel.Vec
+		typed   string
+	}
+}
+
+var _ pixel.ComposeTarget = (*Window)(nil)
+
+// backend is the connection of a Window to the windowing system
+type backend interface {
+	// present shows the image in the window
+	present(img *image.RGBA)
+
+	// poll calls the function with every event received since the last poll, without waiting
+	poll(handle func(event))
+
+	setTitle(title string)
+	destroy()
+	kind() Backend
+}
+
+type eventKind int
+
+const (
+	buttonEvent eventKind = iota
+	repeatEvent
+	motionEvent
+	scrollEvent
+	charEvent
+	enterEvent
+	leaveEvent
+	focusEvent
+	unfocusEvent
+	resizeEvent
+	scaleEvent
+	closeEvent
+)
+
+// event is an event of a window, the positions and the sizes are in logical pixels from the
+// top-left corner of the window, x is the scale of a scaleEvent
+type event struct {
+	kind    eventKind
+	button  Button
+	pressed bool
+	x, y    float64
+	char    rune
+}
+
+// Run calls the function. Unlike pixelgl, the Windows don't need the main thread, so Run only
+// exists for the games selecting the backend by a build tag.
+func Run(run func()) {
+	run()
+}
+
+// NewWindow creates a new Window with it's properties specified in the provided config.
+//
+// If Window creation fails, an error is returned (e.g. due to no windowing system to connect to).
+func NewWindow(cfg WindowConfig) (*Window, error) {
+	b, err := openBackend(cfg)
+	if err != nil {
+		return nil, err
+	}
+	w := &Window{
+		backend: b,
+		bounds:  cfg.Bounds,
+		scale:   1,
+		matrix:  pixel.IM,
+		canvas:  raster.NewCanvas(cfg.Bounds),
+		focused: true,
+	}
+	runtime.SetFinalizer(w, (*Window).Destroy)
+	return w, nil
+}
+
+// Destroy destroys the Window. The Window can't be used any further.
+func (w *Window) Destroy() {
+	w.backend.destroy()
+}
+
+// Update shows the content of the Window and polls events. Call this method at the end of each
+// frame.
+func (w *Window) Update() {
+	w.backend.present(w.canvas.Image())
+	w.UpdateInput()
+}
+
+// UpdateInput polls window events. Call this function to poll window events without showing the
+// content of the Window. Note that the Update method invokes UpdateInput.
+func (w *Window) UpdateInput() {
+	w.backend.poll(w.handle)
+
+	w.prevInp = w.currInp
+	w.currInp = w.tempInp
+
+	w.tempInp.repeat = [KeyLast + 1]bool{}
+	w.tempInp.scroll = pixel.ZV
+	w.tempInp.typed = ""
+}
+
+func (w *Window) handle(e event) {
+	switch e.kind {
+	case buttonEvent:
+		if e.button >= 0 && e.button <= KeyLast {
+			w.tempInp.buttons[e.button] = e.pressed
+		}
+	case repeatEvent:
+		if e.button >= 0 && e.button <= KeyLast {
+			w.tempInp.repeat[e.button] = true
+		}
+	case motionEvent:
+		w.tempInp.mouse = pixel.V(
+			e.x+w.bounds.Min.X,
+			(w.bounds.H()-e.y)+w.bounds.Min.Y,
+		)
+	case scrollEvent:
+		w.tempInp.scroll = w.tempInp.scroll.Add(pixel.V(e.x, e.y))
+	case charEvent:
+		w.tempInp.typed += string(e.char)
+	case enterEvent:
+		w.cursorInsideWindow = true
+	case leaveEvent:
+		w.cursorInsideWindow = false
+	case focusEvent:
+		w.focused = true
+	case unfocusEvent:
+		w.focused = false
+		// the releases of the buttons go to the focused window
+		w.tempInp.buttons = [KeyLast + 1]bool{}
+	case resizeEvent:
+		w.bounds = w.bounds.ResizedMin(pixel.V(e.x, e.y))
+		w.resizeCanvas()
+	case scaleEvent:
+		w.scale = e.x
+		w.resizeCanvas()
+	case closeEvent:
+		w.closed = true
+	}
+}
+
+// resizeCanvas sets the bounds and the matrix of the canvas, which has the pixels of the screen
+func (w *Window) resizeCanvas() {
+	s := w.scale
+	w.canvas.SetBounds(pixel.R(w.bounds.Min.X*s, w.bounds.Min.Y*s, w.bounds.Max.X*s, w.bounds.Max.Y*s))
+	w.canvas.SetMatrix(w.matrix.Scaled(pixel.ZV, s))
+}
+
+// SetClosed sets the closed flag of the Window.
+//
+// This is useful when overriding the user's attempt to close the Window, or just to close the
+// Window from within the program.
+func (w *Window) SetClosed(closed bool) {
+	w.closed = closed
+}
+
+// Closed returns the closed flag of the Window, which reports whether the Window should be closed.
+//
+// The closed flag is automatically set when a user attempts to close the Window, or when the
+// connection to the windowing system is lost.
+func (w *Window) Closed() bool {
+	return w.closed
+}
+
+// SetTitle changes the title of the Window.
+func (w *Window) SetTitle(title string) {
+	w.backend.setTitle(title)
+}
+
+// Bounds returns the current bounds of the Window.
+func (w *Window) Bounds() pixel.Rect {
+	return w.bounds
+}
+
+// Scale returns the number of the pixels of the screen per a pixel of the Bounds, see
+// WindowConfig.HiDPI.
+func (w *Window) Scale() float64 {
+	return w.scale
+}
+
+// Backend returns the windowing system of the Window, BackendX11, BackendWayland or BackendWin32.
+func (w *Window) Backend() Backend {
+	return w.backend.kind()
+}
+
+// Focused returns true if the Window has input focus.
+func (w *Window) Focused() bool {
+	return w.focused
+}
+
+// MakeTriangles generates a specialized copy of the supplied Triangles that will draw onto this
+// Window.
+//
+// Window supports TrianglesPosition, TrianglesColor and TrianglesPicture.
+func (w *Window) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	return w.canvas.MakeTriangles(t)
+}
+
+// MakePicture generates a specialized copy of the supplied Picture that will draw onto this Window.
+//
+// Window supports PictureColor.
+func (w *Window) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	return w.canvas.MakePicture(p)
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (w *Window) SetMatrix(m pixel.Matrix) {
+	w.matrix = m
+	w.canvas.SetMatrix(m.Scaled(pixel.ZV, w.scale))
+}
+
+// SetColorMask sets a global color mask for the Window.
+func (w *Window) SetColorMask(c color.Color) {
+	w.canvas.SetColorMask(c)
+}
+
+// SetComposeMethod sets a Porter-Duff composition method to be used in the following draws onto
+// this Window.
+func (w *Window) SetComposeMethod(cmp pixel.ComposeMethod) {
+	w.canvas.SetComposeMethod(cmp)
+}
+
+// SetSmooth sets whether the stretched Pictures drawn onto this Window should be drawn smooth or
+// pixely.
+func (w *Window) SetSmooth(smooth bool) {
+	w.canvas.SetSmooth(smooth)
+}
+
+// Smooth returns whether the stretched Pictures drawn onto this Window are set to be drawn smooth
+// or pixely.
+func (w *Window) Smooth() bool {
+	return w.canvas.Smooth()
+}
+
+// Clear clears the Window with a single color.
+func (w *Window) Clear(c color.Color) {
+	w.canvas.Clear(c)
+}
+
+// Color returns the color of the pixel over the given position inside the Window.
+func (w *Window) Color(at pixel.Vec) pixel.RGBA {
+	return w.canvas.Color(at.Scaled(w.scale))
+}
+
+// Canvas returns the window's underlying Canvas. It has the pixels of the screen, so its Bounds and
+// Matrix are the ones of the Window multiplied by the Scale.
+func (w *Window) Canvas() *raster.Canvas {
+	return w.canvas
+}
@@ -0,0 +1,692 @@
+//go:build linux || freebsd || netbsd || openbsd || dragonfly || solaris
+// +build linux freebsd netbsd openbsd dragonfly solaris
+
+package pixelsw
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// x11 is a window of an X server, talking to it by the X11 protocol directly.
+//
+// The requests are written by the goroutine of the Window, the replies and the events are read
+// by a separate goroutine.
+type x11 struct {
+	conn net.Conn
+	bw   *bufio.Writer
+	seq  uint16
+
+	idBase, idMask, nextID uint32
+	root, window, gc       uint32
+	maxRequest             int
+	msbFirst               bool
+	minKeycode, maxKeycode byte
+	keysymsPerKeycode      int
+	keysyms                []uint32
+
+	wmProtocols, wmDeleteWindow, netWMName, utf8String, motifWMHints uint32
+
+	// pix is the buffer of the image in the format of the server
+	pix []byte
+
+	replies chan []byte
+
+	mu        sync.Mutex
+	events    [][]byte
+	err       error
+	destroyed bool
+}
+
+// predefined atoms
+const (
+	atomAtom          = 4
+	atomString        = 31
+	atomWMName        = 39
+	atomWMNormalHints = 40
+	atomWMSizeHints   = 41
+)
+
+func openX11(cfg WindowConfig) (backend, error) {
+	network, addr, number, err := parseDisplay(os.Getenv("DISPLAY"))
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("pixelsw: failed to connect to the X server: %v", err)
+	}
+	x := &x11{
+		conn:    conn,
+		bw:      bufio.NewWriter(conn),
+		replies: make(chan []byte, 16),
+	}
+	if err := x.setup(number); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go x.read()
+	if err := x.init(cfg); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return x, nil
+}
+
+// parseDisplay returns the address of the X server of the display, e.g. ":0" or "host:1.0"
+func parseDisplay(display string) (network, addr, number string, err error) {
+	colon := strings.LastIndexByte(display, ':')
+	if colon < 0 {
+		return "", "", "", fmt.Errorf("pixelsw: invalid X display %q, is DISPLAY set?", display)
+	}
+	host, number := display[:colon], display[colon+1:]
+	if dot := strings.IndexByte(number, '.'); dot >= 0 {
+		number = number[:dot]
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return "", "", "", fmt.Errorf("pixelsw: invalid X display %q", display)
+	}
+	switch {
+	case strings.HasPrefix(host, "/"):
+		// a path to the socket, as set by launchd
+		return "unix", host, number, nil
+	case host == "" || host == "unix":
+		return "unix", "/tmp/.X11-unix/X" + number, number, nil
+	default:
+		return "tcp", net.JoinHostPort(host, strconv.Itoa(6000+n)), number, nil
+	}
+}
+
+// readAuth returns the MIT-MAGIC-COOKIE-1 of the local display from the Xauthority file, if any
+func readAuth(number string) (name string, data []byte) {
+	file := os.Getenv("XAUTHORITY")
+	if file == "" {
+		file = filepath.Join(os.Getenv("HOME"), ".Xauthority")
+	}
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", nil
+	}
+	hostname, _ := os.Hostname()
+	r := bytes.NewReader(content)
+	for {
+		var family uint16
+		if binary.Read(r, binary.BigEndian, &family) != nil {
+			return "", nil
+		}
+		var fields [4][]byte
+		for i := range fields {
+			var n uint16
+			if binary.Read(r, binary.BigEndian, &n) != nil {
+				return "", nil
+			}
+			fields[i] = make([]byte, n)
+			if _, err := io.ReadFull(r, fields[i]); err != nil {
+				return "", nil
+			}
+		}
+		const familyLocal, familyWild = 256, 0xffff
+		local := family == familyWild || (family == familyLocal && string(fields[0]) == hostname)
+		if local && (len(fields[1]) == 0 || string(fields[1]) == number) && string(fields[2]) == "MIT-MAGIC-COOKIE-1" {
+			return string(fields[2]), fields[3]
+		}
+	}
+}
+
+// setup exchanges the connection setup with the server
+func (x *x11) setup(number string) error {
+	name, data := readAuth(number)
+	req := []byte{'l', 0}
+	req = put16(req, 11)
+	req = put16(req, 0)
+	req = put16(req, uint16(len(name)))
+	req = put16(req, uint16(len(data)))
+	req = put16(req, 0)
+	req = pad(append(req, name...))
+	req = pad(append(req, data...))
+	if _, err := x.conn.Write(req); err != nil {
+		return fmt.Errorf("pixelsw: failed to connect to the X server: %v", err)
+	}
+
+	head := make([]byte, 8)
+	if _, err := io.ReadFull(x.conn, head); err != nil {
+		return fmt.Errorf("pixelsw: failed to connect to the X server: %v", err)
+	}
+	reply := make([]byte, 4*int(le.Uint16(head[6:])))
+	if _, err := io.ReadFull(x.conn, reply); err != nil {
+		return fmt.Errorf("pixelsw: failed to connect to the X server: %v", err)
+	}
+	if head[0] != 1 {
+		reason := string(reply)
+		if head[0] == 0 && int(head[1]) <= len(reply) {
+			reason = string(reply[:head[1]])
+		}
+		return fmt.Errorf("pixelsw: X server refused the connection: %s", strings.TrimSpace(reason))
+	}
+	if len(reply) < 32 {
+		return fmt.Errorf("pixelsw: invalid X server setup")
+	}
+
+	x.idBase, x.idMask = le.Uint32(reply[4:]), le.Uint32(reply[8:])
+	vendorLen, numFormats := int(le.Uint16(reply[16:])), int(reply[21])
+	x.maxRequest = 4 * int(le.Uint16(reply[18:]))
+	x.msbFirst = reply[22] != 0
+	x.minKeycode, x.maxKeycode = reply[26], reply[27]
+
+	off := 32 + (vendorLen+3)/4*4
+	bpp := make(map[byte]byte)
+	for i := 0; i < numFormats && off+8 <= len(reply); i++ {
+		bpp[reply[off]] = reply[off+1]
+		off += 8
+	}
+	if reply[20] == 0 || off+40 > len(reply) {
+		return fmt.Errorf("pixelsw: X server has no screens")
+	}
+
+	// the first screen, the pixels of its root visual must be 32-bit RGB
+	screen := reply[off:]
+	x.root = le.Uint32(screen)
+	rootVisual, rootDepth, numDepths := le.Uint32(screen[32:]), screen[38], int(screen[39])
+	supported := false
+	depths := screen[40:]
+	for i := 0; i < numDepths && len(depths) >= 8; i++ {
+		depth, numVisuals := depths[0], int(le.Uint16(depths[2:]))
+		for j := 0; j < numVisuals && len(depths) >= 8+24*(j+1); j++ {
+			v := depths[8+24*j:]
+			if le.Uint32(v) == rootVisual && depth == rootDepth {
+				const trueColor = 4
+				supported = v[4] == trueColor && bpp[depth] == 32 &&
+					le.Uint32(v[8:]) == 0xff0000 && le.Uint32(v[12:]) == 0xff00 && le.Uint32(v[16:]) == 0xff
+			}
+		}
+		depths = depths[minInt(len(depths), 8+24*numVisuals):]
+	}
+	if !supported {
+		return fmt.Errorf("pixelsw: unsupported X visual, only 24-bit TrueColor is supported")
+	}
+	return nil
+}
+
+// init creates the window
+func (x *x11) init(cfg WindowConfig) error {
+	atoms := []string{"WM_PROTOCOLS", "WM_DELETE_WINDOW", "_NET_WM_NAME", "UTF8_STRING"}
+	ids := []*uint32{&x.wmProtocols, &x.wmDeleteWindow, &x.netWMName, &x.utf8String}
+	if cfg.Undecorated {
+		atoms, ids = append(atoms, "_MOTIF_WM_HINTS"), append(ids, &x.motifWMHints)
+	}
+	seqs := make([]uint16, len(atoms))
+	for i, name := range atoms {
+		req := put16(nil, uint16(len(name)))
+		req = put16(req, 0)
+		seqs[i] = x.request(16, 0, append(req, name...)) // InternAtom
+	}
+	count := x.maxKeycode - x.minKeycode + 1
+	kbSeq := x.request(101, 0, []byte{x.minKeycode, count, 0, 0}) // GetKeyboardMapping
+	x.flush()
+
+	for i, seq := range seqs {
+		reply, err := x.reply(seq)
+		if err != nil {
+			return err
+		}
+		*ids[i] = le.Uint32(reply[8:])
+	}
+	reply, err := x.reply(kbSeq)
+	if err != nil {
+		return err
+	}
+	x.keysymsPerKeycode = int(reply[1])
+	for i := 32; i+4 <= len(reply); i += 4 {
+		x.keysyms = append(x.keysyms, le.Uint32(reply[i:]))
+	}
+
+	const (
+		backPixel = 1 << 1
+		eventMask = 1 << 11
+		events    = 1<<0 | 1<<1 | 1<<2 | 1<<3 | 1<<4 | 1<<5 | 1<<6 | 1<<15 | 1<<17 | 1<<21
+	)
+	width, height := size(cfg.Bounds.W()), size(cfg.Bounds.H())
+	x.window = x.newID()
+	req := put32(nil, x.window)
+	req = put32(req, x.root)
+	req = put16(req, 0)
+	req = put16(req, 0)
+	req = put16(req, uint16(width))
+	req = put16(req, uint16(height))
+	req = put16(req, 0)
+	req = put16(req, 1) // InputOutput
+	req = put32(req, 0) // the visual of the parent
+	req = put32(req, backPixel|eventMask)
+	req = put32(req, 0)
+	req = put32(req, events)
+	x.request(1, 0, req) // CreateWindow
+
+	x.changeProperty(x.wmProtocols, atomAtom, 32, put32(nil, x.wmDeleteWindow))
+	if !cfg.Resizable {
+		const minSize, maxSize = 1 << 4, 1 << 5
+		hints := make([]uint32, 18)
+		hints[0] = minSize | maxSize
+		hints[5], hints[6], hints[7], hints[8] = uint32(width), uint32(height), uint32(width), uint32(height)
+		var data []byte
+		for _, v := range hints {
+			data = put32(data, v)
+		}
+		x.changeProperty(atomWMNormalHints, atomWMSizeHints, 32, data)
+	}
+	if cfg.Undecorated {
+		// the flags say that the decorations are set, to none
+		const decorationsFlag = 1 << 1
+		hints := put32(nil, decorationsFlag)
+		hints = append(hints, make([]byte, 16)...)
+		x.changeProperty(x.motifWMHints, x.motifWMHints, 32, hints)
+	}
+	x.setTitle(cfg.Title)
+	x.request(8, 0, put32(nil, x.window)) // MapWindow
+
+	x.gc = x.newID()
+	req = put32(nil, x.gc)
+	req = put32(req, x.window)
+	req = put32(req, 0)
+	x.request(55, 0, req) // CreateGC
+	x.flush()
+	return x.failed()
+}
+
+func (x *x11) newID() uint32 {
+	id := x.idBase | (x.nextID & x.idMask)
+	x.nextID++
+	return id
+}
+
+// request writes the request and returns its sequence number
+func (x *x11) request(opcode, detail byte, body []byte) uint16 {
+	body = pad(body)
+	req := []byte{opcode, detail}
+	req = put16(req, uint16(1+len(body)/4))
+	x.write(req)
+	x.write(body)
+	x.seq++
+	return x.seq
+}
+
+func (x *x11) write(data []byte) {
+	if _, err := x.bw.Write(data); err != nil {
+		x.fail(err)
+	}
+}
+
+func (x *x11) flush() {
+	if err := x.bw.Flush(); err != nil {
+		x.fail(err)
+	}
+}
+
+func (x *x11) fail(err error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.err == nil {
+		x.err = err
+	}
+}
+
+func (x *x11) failed() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.err != nil {
+		return fmt.Errorf("pixelsw: lost the connection to the X server: %v", x.err)
+	}
+	return nil
+}
+
+// reply waits for the reply of the request
+func (x *x11) reply(seq uint16) ([]byte, error) {
+	for reply := range x.replies {
+		if le.Uint16(reply[2:]) != seq {
+			continue
+		}
+		if reply[0] == 0 {
+			return nil, fmt.Errorf("pixelsw: X request failed with error %d", reply[1])
+		}
+		return reply, nil
+	}
+	if err := x.failed(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("pixelsw: lost the connection to the X server")
+}
+
+// read reads the replies, the errors and the events, until the connection is closed
+func (x *x11) read() {
+	defer close(x.replies)
+	r := bufio.NewReader(x.conn)
+	for {
+		buf := make([]byte, 32)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			x.fail(err)
+			return
+		}
+		switch buf[0] {
+		case 0:
+			// the errors of the requests without replies are dropped
+			select {
+			case x.replies <- buf:
+			default:
+			}
+		case 1:
+			reply := make([]byte, 32+4*int(le.Uint32(buf[4:])))
+			copy(reply, buf)
+			if _, err := io.ReadFull(r, reply[32:]); err != nil {
+				x.fail(err)
+				return
+			}
+			x.replies <- reply
+		default:
+			events := [][]byte{buf}
+			if buf[0]&0x7f == 3 && r.Buffered() >= 32 {
+				// a repeated key is released and pressed again at once, which poll must see together
+				if next, _ := r.Peek(32); isRepeat(buf, next) {
+					next = make([]byte, 32)
+					io.ReadFull(r, next)
+					events = append(events, next)
+				}
+			}
+			x.mu.Lock()
+			x.events = append(x.events, events...)
+			x.mu.Unlock()
+		}
+	}
+}
+
+func (x *x11) changeProperty(property, typ uint32, format byte, data []byte) {
+	req := put32(nil, x.window)
+	req = put32(req, property)
+	req = put32(req, typ)
+	req = append(req, format, 0, 0, 0)
+	req = put32(req, uint32(len(data)/int(format/8)))
+	x.request(18, 0, append(req, data...)) // ChangeProperty, replacing
+}
+
+func (x *x11) setTitle(title string) {
+	// WM_NAME is Latin-1, _NET_WM_NAME is UTF-8
+	latin := make([]byte, 0, len(title))
+	for _, r := range title {
+		if r > 0xff {
+			r = '?'
+		}
+		latin = append(latin, byte(r))
+	}
+	x.changeProperty(atomWMName, atomString, 8, latin)
+	x.changeProperty(x.netWMName, x.utf8String, 8, []byte(title))
+	x.flush()
+}
+
+func (x *x11) present(img *image.RGBA) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	if width == 0 || height == 0 {
+		return
+	}
+	rows := (x.maxRequest - 24) / (4 * width)
+	if rows < 1 {
+		return
+	}
+	for y0 := 0; y0 < height; y0 += rows {
+		n := minInt(rows, height-y0)
+		x.pix = x.pix[:0]
+		for y := y0; y < y0+n; y++ {
+			x.pix = append(x.pix, img.Pix[y*img.Stride:y*img.Stride+4*width]...)
+		}
+		// the premultiplied colors are drawn over black
+		for i := 0; i < len(x.pix); i += 4 {
+			r, g, b := x.pix[i], x.pix[i+1], x.pix[i+2]
+			if x.msbFirst {
+				x.pix[i], x.pix[i+1], x.pix[i+2], x.pix[i+3] = 0, r, g, b
+			} else {
+				x.pix[i], x.pix[i+1], x.pix[i+2], x.pix[i+3] = b, g, r, 0
+			}
+		}
+		req := put32(nil, x.window)
+		req = put32(req, x.gc)
+		req = put16(req, uint16(width))
+		req = put16(req, uint16(n))
+		req = put16(req, 0)
+		req = put16(req, uint16(y0))
+		req = append(req, 0, 24, 0, 0)
+		x.request(72, 2, append(req, x.pix...)) // PutImage, ZPixmap
+	}
+	x.flush()
+}
+
+func (x *x11) poll(handle func(event)) {
+	x.mu.Lock()
+	events, err := x.events, x.err
+	x.events = nil
+	x.mu.Unlock()
+	if err != nil {
+		handle(event{kind: closeEvent})
+	}
+
+	for i := 0; i < len(events); i++ {
+		e := events[i]
+		switch e[0] & 0x7f {
+		case 2, 3: // KeyPress, KeyRelease
+			pressed := e[0]&0x7f == 2
+			keycode, state := e[1], le.Uint16(e[28:])
+			button := keysymButton(x.keysym(keycode, 0))
+			if !pressed && i+1 < len(events) && isRepeat(e, events[i+1]) {
+				handle(event{kind: repeatEvent, button: button})
+				x.typed(handle, keycode, state)
+				i++
+				continue
+			}
+			if button != KeyUnknown {
+				handle(event{kind: buttonEvent, button: button, pressed: pressed})
+			}
+			if pressed {
+				x.typed(handle, keycode, state)
+			}
+		case 4, 5: // ButtonPress, ButtonRelease
+			pressed := e[0]&0x7f == 4
+			switch e[1] {
+			case 1:
+				handle(event{kind: buttonEvent, button: MouseButtonLeft, pressed: pressed})
+			case 2:
+				handle(event{kind: buttonEvent, button: MouseButtonMiddle, pressed: pressed})
+			case 3:
+				handle(event{kind: buttonEvent, button: MouseButtonRight, pressed: pressed})
+			case 4, 5, 6, 7:
+				if pressed {
+					scroll := [...]event{{y: 1}, {y: -1}, {x: -1}, {x: 1}}[e[1]-4]
+					scroll.kind = scrollEvent
+					handle(scroll)
+				}
+			case 8, 9:
+				handle(event{kind: buttonEvent, button: MouseButton4 + Button(e[1]-8), pressed: pressed})
+			}
+		case 6: // MotionNotify
+			handle(event{kind: motionEvent, x: float64(int16(le.Uint16(e[24:]))), y: float64(int16(le.Uint16(e[26:])))})
+		case 7: // EnterNotify
+			handle(event{kind: enterEvent})
+		case 8: // LeaveNotify
+			handle(event{kind: leaveEvent})
+		case 9: // FocusIn
+			handle(event{kind: focusEvent})
+		case 10: // FocusOut
+			handle(event{kind: unfocusEvent})
+		case 22: // ConfigureNotify
+			if le.Uint32(e[4:]) == x.window {
+				handle(event{kind: resizeEvent, x: float64(le.Uint16(e[20:])), y: float64(le.Uint16(e[22:]))})
+			}
+		case 33: // ClientMessage
+			if e[1] == 32 && le.Uint32(e[8:]) == x.wmProtocols && le.Uint32(e[12:]) == x.wmDeleteWindow {
+				handle(event{kind: closeEvent})
+			}
+		}
+	}
+}
+
+// isRepeat reports whether the KeyRelease event and the next event repeat the key, which is
+// released and pressed again at the same time
+func isRepeat(release, next []byte) bool {
+	return next[0]&0x7f == 2 && next[1] == release[1] && le.Uint32(next[4:]) == le.Uint32(release[4:])
+}
+
+// keysym returns the keysym of the key in the column of the keyboard mapping, zero if none
+func (x *x11) keysym(keycode byte, column int) uint32 {
+	i := (int(keycode)-int(x.minKeycode))*x.keysymsPerKeycode + column
+	if keycode < x.minKeycode || column >= x.keysymsPerKeycode || i >= len(x.keysyms) {
+		return 0
+	}
+	return x.keysyms[i]
+}
+
+// typed handles the character typed by the key, if any
+func (x *x11) typed(handle func(event), keycode byte, state uint16) {
+	const shift, lock, control, alt = 1 << 0, 1 << 1, 1 << 2, 1 << 3
+	if state&(control|alt) != 0 {
+		return
+	}
+	sym, upper := x.keysym(keycode, 0), x.keysym(keycode, 1)
+	shifted := state&shift != 0
+	if state&lock != 0 && sym >= 'a' && sym <= 'z' {
+		shifted = !shifted
+	}
+	if shifted && upper != 0 {
+		sym = upper
+	}
+	if r, ok := keysymRune(sym); ok {
+		handle(event{kind: charEvent, char: r})
+	}
+}
+
+func (x *x11) kind() Backend {
+	return BackendX11
+}
+
+func (x *x11) destroy() {
+	x.mu.Lock()
+	destroyed := x.destroyed
+	x.destroyed = true
+	x.mu.Unlock()
+	if destroyed {
+		return
+	}
+	x.request(60, 0, put32(nil, x.gc))    // FreeGC
+	x.request(4, 0, put32(nil, x.window)) // DestroyWindow
+	x.flush()
+	x.conn.Close()
+}
+
+// keysymButton returns the Button of the keysym, KeyUnknown if none
+func keysymButton(sym uint32) Button {
+	switch {
+	case sym >= 'a' && sym <= 'z':
+		return Button(sym - 'a' + 'A')
+	case sym >= ' ' && sym <= '`':
+		// the Buttons of the ASCII symbols are their codes
+		if _, ok := buttonNames[Button(sym)]; ok {
+			return Button(sym)
+		}
+	case sym >= 0xffbe && sym <= 0xffd6:
+		return KeyF1 + Button(sym-0xffbe)
+	case sym >= 0xffb0 && sym <= 0xffb9:
+		return KeyKP0 + Button(sym-0xffb0)
+	}
+	if button, ok := keysymButtons[sym]; ok {
+		return button
+	}
+	return KeyUnknown
+}
+
+var keysymButtons = map[uint32]Button{
+	0xff1b: KeyEscape,
+	0xff0d: KeyEnter,
+	0xff09: KeyTab,
+	0xff08: KeyBackspace,
+	0xff63: KeyInsert,
+	0xffff: KeyDelete,
+	0xff51: KeyLeft,
+	0xff52: KeyUp,
+	0xff53: KeyRight,
+	0xff54: KeyDown,
+	0xff55: KeyPageUp,
+	0xff56: KeyPageDown,
+	0xff50: KeyHome,
+	0xff57: KeyEnd,
+	0xffe5: KeyCapsLock,
+	0xff14: KeyScrollLock,
+	0xff7f: KeyNumLock,
+	0xff61: KeyPrintScreen,
+	0xff13: KeyPause,
+	0xffae: KeyKPDecimal,
+	0xffaf: KeyKPDivide,
+	0xffaa: KeyKPMultiply,
+	0xffad: KeyKPSubtract,
+	0xffab: KeyKPAdd,
+	0xff8d: KeyKPEnter,
+	0xffbd: KeyKPEqual,
+	0xffe1: KeyLeftShift,
+	0xffe3: KeyLeftControl,
+	0xffe9: KeyLeftAlt,
+	0xffeb: KeyLeftSuper,
+	0xffe2: KeyRightShift,
+	0xffe4: KeyRightControl,
+	0xffea: KeyRightAlt,
+	0xffec: KeyRightSuper,
+	0xff67: KeyMenu,
+}
+
+// keysymRune returns the character of the keysym, if any
+func keysymRune(sym uint32) (rune, bool) {
+	switch {
+	case sym >= 0x20 && sym <= 0x7e, sym >= 0xa0 && sym <= 0xff:
+		return rune(sym), true
+	case sym >= 0x01000100 && sym <= 0x0110ffff:
+		return rune(sym - 0x01000000), true
+	}
+	return 0, false
+}
+
+var le = binary.LittleEndian
+
+func put16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func put32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// pad pads the data to a multiple of 4 bytes
+func pad(b []byte) []byte {
+	for len(b)%4 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// size returns the size in pixels, at least 1
+func size(v float64) int {
+	if v < 1 {
+		return 1
+	}
+	return int(v + 0.5)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
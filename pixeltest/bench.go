@@ -0,0 +1,38 @@
+package pixeltest
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+// Benchmark runs the frame function b.N times with the profiling of Pixel on (see
+// pixel.SetProfiling) and reports the time spent in each Phase per frame next to the total time,
+// as the metrics named after the phases, e.g. draw-ns/op. This attributes the frame time to the
+// subsystems of Pixel:
+//
+//   func BenchmarkLevel(b *testing.B) {
+//       canvas := raster.NewCanvas(pixel.R(0, 0, 640, 480))
+//       pixeltest.Benchmark(b, func() {
+//           canvas.Clear(color.Black)
+//           level.Draw(canvas)
+//       })
+//   }
+//
+// The profile is reset before the benchmark.
+func Benchmark(b *testing.B, frame func()) {
+	b.Helper()
+	defer pixel.SetProfiling(pixel.Profiling())
+	pixel.SetProfiling(true)
+	pixel.ResetProfile()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		frame()
+	}
+	b.StopTimer()
+
+	for _, pt := range pixel.PhaseTimes() {
+		b.ReportMetric(float64(pt.Total.Nanoseconds())/float64(b.N), pt.Phase.String()+"-ns/op")
+	}
+}
@@ -0,0 +1,95 @@
+package pixeltest
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Diff is the difference of two images.
+type Diff struct {
+	// Pixels is the number of the differing pixels and Total is the number of all the pixels.
+	Pixels, Total int
+
+	// Max is the largest perceptual difference of a pixel, from 0 to 1.
+	Max float64
+
+	// Image shows the differing pixels in red over the faded golden image.
+	Image *image.RGBA
+}
+
+// Compare compares the images pixel by pixel, aligned by the top-left corners of their bounds.
+//
+// The perceptual difference of two colors is their distance in the YIQ color space, composed over
+// white, which matches the human perception better than the distance of the RGB components. The
+// pixels differing by more than the threshold are counted in the Diff. If the sizes of the images
+// differ, the pixels outside of one of them differ too.
+func Compare(want, got image.Image, threshold float64) Diff {
+	wb, gb := want.Bounds(), got.Bounds()
+	w, h := maxInt(wb.Dx(), gb.Dx()), maxInt(wb.Dy(), gb.Dy())
+
+	diff := Diff{
+		Total: w * h,
+		Image: image.NewRGBA(image.Rect(0, 0, w, h)),
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := image.Pt(x, y)
+			wp, gp := wb.Min.Add(p), gb.Min.Add(p)
+			if !wp.In(wb) || !gp.In(gb) {
+				diff.Pixels++
+				diff.Max = 1
+				diff.Image.SetRGBA(x, y, color.RGBA{R: 0xff, A: 0xff})
+				continue
+			}
+
+			wc, gc := want.At(wp.X, wp.Y), got.At(gp.X, gp.Y)
+			d := distance(wc, gc)
+			if d > diff.Max {
+				diff.Max = d
+			}
+			if d > threshold {
+				diff.Pixels++
+				diff.Image.SetRGBA(x, y, color.RGBA{R: 0xff, A: 0xff})
+				continue
+			}
+			// the equal pixels are faded towards white
+			luma, _, _ := yiq(wc)
+			v := uint8(math.Round(255 - (255-luma)*0.25))
+			diff.Image.SetRGBA(x, y, color.RGBA{R: v, G: v, B: v, A: 0xff})
+		}
+	}
+	return diff
+}
+
+// maxYIQ is the largest YIQ distance of two colors, squared
+const maxYIQ = 35215
+
+// distance returns the perceptual difference of the colors, from 0 to 1
+func distance(a, b color.Color) float64 {
+	y1, i1, q1 := yiq(a)
+	y2, i2, q2 := yiq(b)
+	dy, di, dq := y1-y2, i1-i2, q1-q2
+	return math.Sqrt((0.5053*dy*dy + 0.299*di*di + 0.1957*dq*dq) / maxYIQ)
+}
+
+// yiq returns the components of the color composed over white in the YIQ color space, with the
+// luma from 0 to 255
+func yiq(c color.Color) (y, i, q float64) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	a := float64(n.A) / 255
+	r := 255 + (float64(n.R)-255)*a
+	g := 255 + (float64(n.G)-255)*a
+	b := 255 + (float64(n.B)-255)*a
+	y = r*0.29889531 + g*0.58662247 + b*0.11448223
+	i = r*0.59597799 - g*0.27417610 - b*0.32180189
+	q = r*0.21147017 - g*0.52261711 + b*0.31114694
+	return y, i, q
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,151 @@
+// Package pixeltest compares the rendering of Pixel to the reference images stored with the tests,
+// the golden images, so the rendering regressions are caught by the Go tests.
+//
+// A scene is rendered headlessly by the software rasterizer of the raster package and compared to
+// testdata/<name>.png with a perceptual tolerance:
+//
+//   func TestHUD(t *testing.T) {
+//       img := pixeltest.Render(pixel.R(0, 0, 320, 240), func(t pixel.ComposeTarget) {
+//           hud.Draw(t)
+//       })
+//       pixeltest.Assert(t, "hud", img)
+//   }
+//
+// Any image can be asserted, e.g. pixelgl.Window.Screenshot of a hidden Window to test the OpenGL
+// rendering. The golden images are created and updated by running the tests with the
+// -pixeltest.update flag:
+//
+//   go test ./... -args -pixeltest.update
+//
+// If an image differs from its golden image, the rendered image and an image highlighting the
+// differences are saved next to it, as <name>.got.png and <name>.diff.png.
+package pixeltest
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+)
+
+var update = flag.Bool("pixeltest.update", false, "update the golden images of pixeltest")
+
+// Options are the options of comparing the images to the golden images.
+type Options struct {
+	// Dir is the directory of the golden images, "testdata" if empty.
+	Dir string
+
+	// Threshold is the largest perceptual difference of the colors of a pixel considered equal,
+	// from 0 (exactly equal colors) to 1 (the most different colors, such as red and cyan).
+	Threshold float64
+
+	// MaxDiff is the fraction of the pixels allowed to differ, e.g. 0.001 ignores a few pixels of
+	// the antialiased edges.
+	MaxDiff float64
+
+	// Update saves the images as the golden images instead of comparing them, like the
+	// -pixeltest.update flag.
+	Update bool
+}
+
+// DefaultOptions are the Options of Assert. The Threshold of 0.1 tolerates the small differences
+// of rounding, which aren't visible.
+var DefaultOptions = Options{Dir: "testdata", Threshold: 0.1}
+
+// Render draws a scene of the bounds by the draw function onto a new raster.Canvas cleared to
+// transparent and returns its content.
+func Render(bounds pixel.Rect, draw func(t pixel.ComposeTarget)) *image.RGBA {
+	c := raster.NewCanvas(bounds)
+	draw(c)
+	return c.Image()
+}
+
+// Assert compares the image to the golden image of the name with the DefaultOptions, and fails
+// the test if they differ. It reports whether they're equal.
+func Assert(t testing.TB, name string, img image.Image) bool {
+	t.Helper()
+	return DefaultOptions.Assert(t, name, img)
+}
+
+// Assert compares the image to the golden image <Dir>/<name>.png, and fails the test if they
+// differ. It reports whether they're equal.
+func (o Options) Assert(t testing.TB, name string, img image.Image) bool {
+	t.Helper()
+	dir := o.Dir
+	if dir == "" {
+		dir = "testdata"
+	}
+	file := filepath.Join(dir, filepath.FromSlash(name))
+
+	if o.Update || *update {
+		if err := savePNG(file+".png", img); err != nil {
+			t.Fatalf("pixeltest: failed to update %s: %v", name, err)
+		}
+		return true
+	}
+
+	want, err := loadPNG(file + ".png")
+	if os.IsNotExist(err) {
+		t.Errorf("pixeltest: no golden image %s.png, run the test with -pixeltest.update to create it", file)
+		return false
+	}
+	if err != nil {
+		t.Fatalf("pixeltest: failed to load %s: %v", name, err)
+	}
+
+	diff := Compare(want, img, o.Threshold)
+	var msg string
+	switch {
+	case want.Bounds().Size() != img.Bounds().Size():
+		msg = fmt.Sprintf("size %v, golden image %v", img.Bounds().Size(), want.Bounds().Size())
+	case float64(diff.Pixels) > o.MaxDiff*float64(diff.Total):
+		msg = fmt.Sprintf("%d of %d pixels differ, by up to %.3f", diff.Pixels, diff.Total, diff.Max)
+	default:
+		// the differences of a previous failure are outdated
+		os.Remove(file + ".got.png")
+		os.Remove(file + ".diff.png")
+		return true
+	}
+
+	saveErr := savePNG(file+".got.png", img)
+	if saveErr == nil {
+		saveErr = savePNG(file+".diff.png", diff.Image)
+	}
+	if saveErr != nil {
+		t.Errorf("pixeltest: %s differs: %s (failed to save the differences: %v)", name, msg, saveErr)
+	} else {
+		t.Errorf("pixeltest: %s differs: %s, see %s.got.png and %s.diff.png", name, msg, file, file)
+	}
+	return false
+}
+
+func loadPNG(file string) (image.Image, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func savePNG(file string, img image.Image) (err error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	return png.Encode(f, img)
+}
@@ -0,0 +1,140 @@
+package pixeltest_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixeltest"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeT records the failures of the assertions
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (ft *fakeT) Helper() {}
+
+func (ft *fakeT) Errorf(format string, args ...interface{}) {
+	ft.errors = append(ft.errors, fmt.Sprintf(format, args...))
+}
+
+func (ft *fakeT) Fatalf(format string, args ...interface{}) {
+	ft.Errorf(format, args...)
+}
+
+func scene(c color.Color, size float64) func(t pixel.ComposeTarget) {
+	return func(t pixel.ComposeTarget) {
+		imd := imdraw.New(nil)
+		imd.Color = c
+		imd.Push(pixel.V(2, 2), pixel.V(2+size, 2+size))
+		imd.Rectangle(0)
+		imd.Draw(t)
+	}
+}
+
+func TestAssert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pixeltest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	opts := pixeltest.Options{Dir: dir, Threshold: 0.1}
+	img := pixeltest.Render(pixel.R(0, 0, 20, 10), scene(pixel.RGB(1, 0, 0), 5))
+	assert.Equal(t, image.Rect(0, 0, 20, 10), img.Bounds())
+
+	ft := &fakeT{TB: t}
+	assert.False(t, opts.Assert(ft, "scenes/square", img))
+	if assert.Len(t, ft.errors, 1) {
+		assert.Contains(t, ft.errors[0], "-pixeltest.update")
+	}
+
+	update := opts
+	update.Update = true
+	ft = &fakeT{TB: t}
+	assert.True(t, update.Assert(ft, "scenes/square", img))
+	assert.Empty(t, ft.errors)
+	assert.FileExists(t, filepath.Join(dir, "scenes", "square.png"))
+
+	ft = &fakeT{TB: t}
+	assert.True(t, opts.Assert(ft, "scenes/square", img))
+	similar := pixeltest.Render(pixel.R(0, 0, 20, 10), scene(pixel.RGB(0.98, 0.02, 0), 5))
+	assert.True(t, opts.Assert(ft, "scenes/square", similar), "within the threshold")
+	assert.Empty(t, ft.errors)
+
+	bigger := pixeltest.Render(pixel.R(0, 0, 20, 10), scene(pixel.RGB(1, 0, 0), 6))
+	ft = &fakeT{TB: t}
+	assert.False(t, opts.Assert(ft, "scenes/square", bigger))
+	if assert.Len(t, ft.errors, 1) {
+		assert.Contains(t, ft.errors[0], "11 of 200 pixels differ")
+	}
+	assert.FileExists(t, filepath.Join(dir, "scenes", "square.got.png"))
+	assert.FileExists(t, filepath.Join(dir, "scenes", "square.diff.png"))
+
+	tolerant := opts
+	tolerant.MaxDiff = 0.1
+	ft = &fakeT{TB: t}
+	assert.True(t, tolerant.Assert(ft, "scenes/square", bigger))
+	assert.Empty(t, ft.errors)
+	_, err = os.Stat(filepath.Join(dir, "scenes", "square.got.png"))
+	assert.True(t, os.IsNotExist(err), "the differences are removed")
+
+	ft = &fakeT{TB: t}
+	assert.False(t, tolerant.Assert(ft, "scenes/square", img.SubImage(image.Rect(0, 0, 10, 10))))
+	if assert.Len(t, ft.errors, 1) {
+		assert.Contains(t, ft.errors[0], "size")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	b := image.NewRGBA(image.Rect(10, 10, 14, 12))
+	for i := range a.Pix {
+		a.Pix[i], b.Pix[i] = 0xff, 0xff
+	}
+	diff := pixeltest.Compare(a, b, 0)
+	assert.Equal(t, 0, diff.Pixels)
+	assert.Equal(t, 8, diff.Total)
+	assert.Equal(t, 0.0, diff.Max)
+
+	b.SetRGBA(11, 10, color.RGBA{A: 0xff})
+	b.SetRGBA(12, 11, color.RGBA{R: 0xf8, G: 0xfa, B: 0xf6, A: 0xff})
+	diff = pixeltest.Compare(a, b, 0.1)
+	assert.Equal(t, 1, diff.Pixels)
+	assert.InDelta(t, 0.966, diff.Max, 0.001, "black and white")
+	assert.Equal(t, color.RGBA{R: 0xff, A: 0xff}, diff.Image.RGBAAt(1, 0))
+	assert.Equal(t, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, diff.Image.RGBAAt(2, 1))
+
+	// transparent pixels are composed over white
+	c := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	diff = pixeltest.Compare(a, c, 0)
+	assert.Equal(t, 0, diff.Pixels)
+
+	// the pixels outside of the smaller image differ
+	diff = pixeltest.Compare(a, image.NewRGBA(image.Rect(0, 0, 3, 3)), 0.1)
+	assert.Equal(t, 12, diff.Total)
+	assert.Equal(t, 6, diff.Pixels)
+}
+
+func TestBenchmark(t *testing.T) {
+	batch := pixel.NewBatch(&pixel.TrianglesData{}, nil)
+	tri := pixel.MakeTrianglesData(3)
+	res := testing.Benchmark(func(b *testing.B) {
+		pixeltest.Benchmark(b, func() {
+			batch.Clear()
+			batch.MakeTriangles(tri).Draw()
+		})
+	})
+	assert.Contains(t, res.Extra, "transform-ns/op")
+	assert.Contains(t, res.Extra, "swap-ns/op")
+	assert.True(t, res.Extra["transform-ns/op"] > 0)
+	assert.False(t, pixel.Profiling())
+}
@@ -0,0 +1,152 @@
+package platformer
+
+import "github.com/faiface/pixel"
+
+// Body is an axis-aligned box moved through a World.
+type Body struct {
+	// Pos is the center of the bottom side of the Body.
+	Pos  pixel.Vec
+	Size pixel.Vec
+	Vel  pixel.Vec
+
+	// StepHeight is the height of the steps the Body walks up onto and down from, while it's on
+	// the ground.
+	StepHeight float64
+
+	// Drop makes the Body fall through the OneWay platforms.
+	Drop bool
+
+	// The contacts of the Body during the last Move: Grounded stands on the ground, Ceiling hit
+	// the ceiling and WallLeft and WallRight hit the walls on the sides.
+	Grounded, Ceiling, WallLeft, WallRight bool
+
+	// the gradient of the ground under the Body
+	slope float64
+}
+
+// Rect returns the rectangle of the Body in the world.
+func (b *Body) Rect() pixel.Rect {
+	return pixel.R(b.Pos.X-b.Size.X/2, b.Pos.Y, b.Pos.X+b.Size.X/2, b.Pos.Y+b.Size.Y)
+}
+
+// Controller is a character of a platformer game: its Body runs, jumps and falls. The jumps are
+// forgiving: possible a moment after walking off a ledge, remembered when pressed a moment before
+// landing and lower when released early.
+//
+//   player := platformer.NewController(pixel.V(100, 32), pixel.V(12, 24))
+//   for !win.Closed() {
+//       var in platformer.Input
+//       if win.Pressed(pixelgl.KeyLeft) {
+//           in.Move--
+//       }
+//       if win.Pressed(pixelgl.KeyRight) {
+//           in.Move++
+//       }
+//       in.Jump = win.Pressed(pixelgl.KeySpace)
+//       in.Down = win.Pressed(pixelgl.KeyDown)
+//       player.Update(world, in, dt)
+//       ...
+//   }
+type Controller struct {
+	Body
+
+	// Gravity is the downwards acceleration and MaxFall is the maximal falling speed, zero means
+	// unlimited.
+	Gravity, MaxFall float64
+
+	// RunSpeed is the maximal running speed, reached by the Accel on the ground and by the AirAccel
+	// in the air.
+	RunSpeed, Accel, AirAccel float64
+
+	// JumpSpeed is the initial upwards speed of a jump. JumpCut multiplies it, when the jump is
+	// released while going up.
+	JumpSpeed, JumpCut float64
+
+	// CoyoteTime is the time in seconds after walking off a ledge, when the jump is still possible.
+	// JumpBuffer is the time in seconds a jump pressed in the air is remembered for, before
+	// landing.
+	CoyoteTime, JumpBuffer float64
+
+	coyote  float64
+	buffer  float64
+	held    bool
+	jumping bool
+}
+
+// Input is the input of a Controller during a frame.
+type Input struct {
+	// Move is the direction of running, within range [-1, 1].
+	Move float64
+
+	// Jump is whether the jump is held.
+	Jump bool
+
+	// Down is whether the Controller drops through the OneWay platforms.
+	Down bool
+}
+
+// NewController creates a new Controller of the Body of the size at the position, with the
+// parameters for the sizes of the tiles about 16 pixels.
+func NewController(pos, size pixel.Vec) *Controller {
+	return &Controller{
+		Body:       Body{Pos: pos, Size: size},
+		Gravity:    1800,
+		MaxFall:    900,
+		RunSpeed:   200,
+		Accel:      2000,
+		AirAccel:   1200,
+		JumpSpeed:  600,
+		JumpCut:    0.5,
+		CoyoteTime: 0.1,
+		JumpBuffer: 0.1,
+	}
+}
+
+// Update updates the Controller with the Input and moves it through the World over the time dt in
+// seconds.
+func (c *Controller) Update(w *World, in Input, dt float64) {
+	accel := c.Accel
+	if !c.Grounded {
+		accel = c.AirAccel
+	}
+	c.Vel.X = approach(c.Vel.X, pixel.Clamp(in.Move, -1, 1)*c.RunSpeed, accel*dt)
+
+	pressed := in.Jump && !c.held
+	c.held = in.Jump
+	if pressed {
+		c.buffer = c.JumpBuffer
+	}
+	if (pressed || c.buffer > 0) && (c.Grounded || c.coyote > 0) {
+		c.Vel.Y = c.JumpSpeed
+		c.buffer, c.coyote = 0, 0
+		c.jumping = true
+	}
+	if c.jumping && !in.Jump && c.Vel.Y > 0 {
+		c.Vel.Y *= c.JumpCut
+		c.jumping = false
+	}
+
+	c.Vel.Y -= c.Gravity * dt
+	if c.MaxFall > 0 && c.Vel.Y < -c.MaxFall {
+		c.Vel.Y = -c.MaxFall
+	}
+
+	c.Drop = in.Down
+	w.Move(&c.Body, dt)
+
+	c.buffer -= dt
+	if c.Grounded {
+		c.coyote = c.CoyoteTime
+		c.jumping = false
+	} else {
+		c.coyote -= dt
+	}
+}
+
+// approach moves x towards the target by at most the step.
+func approach(x, target, step float64) float64 {
+	if x < target {
+		return pixel.Clamp(x+step, x, target)
+	}
+	return pixel.Clamp(x-step, target, x)
+}
@@ -0,0 +1,241 @@
+package platformer_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/platformer"
+	"github.com/faiface/pixel/tilemap"
+	"github.com/stretchr/testify/assert"
+)
+
+// world returns a World of the tiles 10 by 10 drawn by the rows, the top one first and the bottom
+// one at y = 0: '#' is Solid, '-' is OneWay, '/' and '\' are Slopes. Above is Empty, the rest outside is Solid.
+func world(rows ...string) *platformer.World {
+	return &platformer.World{
+		TileSize: pixel.V(10, 10),
+		Tile: func(x, y int) platformer.Tile {
+			if y >= len(rows) {
+				return platformer.Tile{}
+			}
+			if y < 0 || x < 0 || x >= len(rows[0]) {
+				return platformer.Tile{Kind: platformer.Solid}
+			}
+			switch rows[len(rows)-1-y][x] {
+			case '#':
+				return platformer.Tile{Kind: platformer.Solid}
+			case '-':
+				return platformer.Tile{Kind: platformer.OneWay}
+			case '/':
+				return platformer.SlopeUp
+			case '\\':
+				return platformer.SlopeDown
+			}
+			return platformer.Tile{}
+		},
+	}
+}
+
+func TestMove_Tunneling(t *testing.T) {
+	w := world(
+		"#####",
+		"     ",
+		"     ",
+		"     ",
+		"     ",
+	)
+	b := &platformer.Body{Pos: pixel.V(25, 30), Size: pixel.V(8, 8), Vel: pixel.V(0, -1e6)}
+	w.Move(b, 1)
+	assert.Equal(t, pixel.V(25, 0), b.Pos)
+	assert.Equal(t, pixel.ZV, b.Vel)
+	assert.True(t, b.Grounded)
+
+	b.Vel = pixel.V(1e6, 0)
+	w.Move(b, 1)
+	assert.Equal(t, pixel.V(46, 0), b.Pos)
+	assert.True(t, b.WallRight)
+	assert.False(t, b.WallLeft)
+	assert.Equal(t, 0.0, b.Vel.X)
+
+	b.Vel = pixel.V(-1e6, 1e6)
+	w.Move(b, 1)
+	assert.Equal(t, pixel.V(4, 32), b.Pos)
+	assert.True(t, b.WallLeft)
+	assert.True(t, b.Ceiling)
+	assert.False(t, b.Grounded)
+}
+
+func TestMove_Touching(t *testing.T) {
+	w := world(
+		"#####",
+		"     ",
+		"  #  ",
+		"     ",
+	)
+	// sliding along the sides of a tile doesn't hit it
+	b := &platformer.Body{Pos: pixel.V(5, 20), Size: pixel.V(10, 10), Vel: pixel.V(40, 0)}
+	w.Move(b, 1)
+	assert.Equal(t, pixel.V(45, 20), b.Pos)
+	assert.False(t, b.WallRight)
+
+	b = &platformer.Body{Pos: pixel.V(15, 0), Size: pixel.V(10, 10), Vel: pixel.V(0, 15)}
+	w.Move(b, 1)
+	assert.Equal(t, pixel.V(15, 15), b.Pos)
+	assert.False(t, b.Ceiling)
+}
+
+func TestMove_OneWay(t *testing.T) {
+	w := world(
+		"     ",
+		"     ",
+		"-----",
+		"     ",
+	)
+	// jumps through from below
+	b := &platformer.Body{Pos: pixel.V(25, 0), Size: pixel.V(8, 8), Vel: pixel.V(0, 25)}
+	w.Move(b, 1)
+	assert.Equal(t, 25.0, b.Pos.Y)
+	assert.False(t, b.Ceiling)
+
+	// falls onto the top
+	b.Vel = pixel.V(0, -100)
+	w.Move(b, 1)
+	assert.Equal(t, 20.0, b.Pos.Y)
+	assert.True(t, b.Grounded)
+
+	// drops through
+	b.Drop = true
+	b.Vel = pixel.V(0, -100)
+	w.Move(b, 1)
+	assert.Equal(t, 0.0, b.Pos.Y)
+}
+
+func TestMove_Slopes(t *testing.T) {
+	w := world(
+		"      ",
+		"  /#\\ ",
+		"######",
+	)
+	b := &platformer.Body{Pos: pixel.V(5, 10), Size: pixel.V(4, 8), Grounded: true}
+	step := func(vx float64) {
+		b.Vel.X = vx
+		b.Vel.Y -= 1
+		w.Move(b, 1)
+	}
+
+	// up the slope
+	for b.Pos.X < 27 {
+		step(1)
+		assert.True(t, b.Grounded, "x %v", b.Pos.X)
+	}
+	assert.InDelta(t, 17, b.Pos.Y, 1e-9)
+
+	// onto the top and down the other side
+	for b.Pos.X < 48 {
+		step(1)
+		assert.True(t, b.Grounded, "x %v", b.Pos.X)
+		if b.Pos.X > 42 && b.Pos.X < 48 {
+			assert.InDelta(t, 10+(50-b.Pos.X), b.Pos.Y, 1e-9, "x %v", b.Pos.X)
+		}
+	}
+
+	// and back up
+	for b.Pos.X > 42 {
+		step(-1)
+		assert.True(t, b.Grounded, "x %v", b.Pos.X)
+	}
+	assert.InDelta(t, 18, b.Pos.Y, 1e-9)
+}
+
+func TestMove_Steps(t *testing.T) {
+	w := world(
+		"      ",
+		"   ###",
+		"  ####",
+		"######",
+	)
+	b := &platformer.Body{Pos: pixel.V(5, 10), Size: pixel.V(8, 8), Grounded: true}
+	b.Vel = pixel.V(20, -1)
+	w.Move(b, 1)
+	assert.Equal(t, pixel.V(16, 10), b.Pos, "a wall without a StepHeight")
+	assert.True(t, b.WallRight)
+
+	b.StepHeight = 10
+	b.Vel = pixel.V(20, -1)
+	w.Move(b, 1)
+	assert.Equal(t, pixel.V(36, 30), b.Pos, "up the stairs")
+	assert.True(t, b.Grounded)
+
+	b.Vel = pixel.V(-20, -1)
+	w.Move(b, 1)
+	assert.Equal(t, pixel.V(16, 10), b.Pos, "down the stairs")
+	assert.True(t, b.Grounded)
+}
+
+func TestController(t *testing.T) {
+	w := world(
+		"          ",
+		"          ",
+		"          ",
+		"          ",
+		"          ",
+		"#####     ",
+	)
+	const dt = 1.0 / 60
+	c := platformer.NewController(pixel.V(10, 10), pixel.V(8, 8))
+	update := func(in platformer.Input, frames int) {
+		for i := 0; i < frames; i++ {
+			c.Update(w, in, dt)
+		}
+	}
+
+	update(platformer.Input{}, 10)
+	assert.True(t, c.Grounded)
+	assert.Equal(t, 10.0, c.Pos.Y)
+
+	// a full jump is higher than a short one
+	update(platformer.Input{Jump: true}, 10)
+	assert.False(t, c.Grounded)
+	full := c.Pos.Y
+	update(platformer.Input{}, 60)
+	assert.True(t, c.Grounded)
+	update(platformer.Input{Jump: true}, 2)
+	update(platformer.Input{}, 8)
+	assert.True(t, c.Pos.Y < full)
+
+	// the jump pressed just before landing is buffered
+	update(platformer.Input{}, 60)
+	c.Pos.Y += 3
+	c.Grounded = false
+	update(platformer.Input{Jump: true}, 1)
+	update(platformer.Input{Jump: true}, 5)
+	assert.False(t, c.Grounded)
+	assert.True(t, c.Vel.Y > 0)
+
+	// runs off the ledge and jumps a moment later
+	update(platformer.Input{}, 60)
+	for c.Grounded {
+		update(platformer.Input{Move: 1}, 1)
+	}
+	update(platformer.Input{Move: 1, Jump: true}, 1)
+	assert.True(t, c.Vel.Y > 0, "coyote jump")
+}
+
+func TestMapWorld(t *testing.T) {
+	m := tilemap.NewMap(tilemap.NewTileset(pixel.MakePictureData(pixel.R(0, 0, 16, 8)), pixel.V(8, 8)), 4)
+	m.Generate = func(x, y int) int {
+		if y < 0 {
+			return 0
+		}
+		return -1
+	}
+	m.SetTile(2, 0, 1)
+	w := platformer.MapWorld(m, map[int]platformer.Tile{0: {Kind: platformer.Solid}, 1: platformer.SlopeUp})
+	assert.Equal(t, platformer.Solid, w.Tile(5, -1).Kind)
+	assert.Equal(t, platformer.SlopeUp, w.Tile(2, 0))
+	assert.Equal(t, platformer.Empty, w.Tile(5, 5).Kind)
+
+	b := &platformer.Body{Pos: pixel.V(4, 100), Size: pixel.V(4, 4), Vel: pixel.V(0, -1000)}
+	w.Move(b, 1)
+	assert.Equal(t, pixel.V(4, 0), b.Pos)
+}
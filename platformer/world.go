@@ -0,0 +1,265 @@
+// Package platformer implements the physics of the platformer games: boxes moving through a grid
+// of tiles with the one-way platforms, slopes and steps, and a character controller on top of it.
+//
+// The movement is swept along the tiles, so the bodies never pass through them, no matter how fast
+// they move.
+package platformer
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/tilemap"
+)
+
+// Kind is the kind of the collision of a tile.
+type Kind int
+
+const (
+	// Empty tiles don't collide.
+	Empty Kind = iota
+
+	// Solid tiles collide from all sides.
+	Solid
+
+	// OneWay tiles are the platforms, which collide only with the bodies falling onto their tops.
+	OneWay
+
+	// Slope tiles have a sloped floor, which the bodies walk on by the centers of their bottoms.
+	// From below, they collide like Solid tiles. The higher side of a Slope should be next to a
+	// Solid tile or another Slope.
+	Slope
+)
+
+// Tile is the collision of a tile.
+type Tile struct {
+	Kind Kind
+
+	// Left and Right are the heights of the floor of a Slope at the left and the right side of the
+	// tile, within range [0, 1] of the tile's height.
+	Left, Right float64
+}
+
+// The common Slopes.
+var (
+	SlopeUp   = Tile{Kind: Slope, Left: 0, Right: 1}
+	SlopeDown = Tile{Kind: Slope, Left: 1, Right: 0}
+)
+
+// World is the static collision geometry of the Bodies: a grid of the tiles.
+type World struct {
+	// TileSize is the size of the tiles. The tile (x, y) covers the rectangle from (x, y) to
+	// (x+1, y+1) scaled by the TileSize, like the cells of a tilemap.Map.
+	TileSize pixel.Vec
+
+	// Tile returns the collision of the tile (x, y).
+	Tile func(x, y int) Tile
+}
+
+// MapWorld returns a World of the cells of the Map, with the collisions of their tiles. The other
+// tiles are Empty.
+//
+//   w := platformer.MapWorld(m, map[int]platformer.Tile{
+//       ground: {Kind: platformer.Solid},
+//       bridge: {Kind: platformer.OneWay},
+//       ramp:   platformer.SlopeUp,
+//   })
+func MapWorld(m *tilemap.Map, tiles map[int]Tile) *World {
+	return &World{
+		TileSize: m.Tileset.TileSize,
+		Tile: func(x, y int) Tile {
+			return tiles[m.Tile(x, y)]
+		},
+	}
+}
+
+// eps is the tolerance of the positions in the tiles, so that the bodies touching the tiles don't
+// overlap them
+const eps = 1e-9
+
+// Move moves the Body by its velocity over the time dt in seconds. It stops the Body at the tiles
+// in its way, zeroes its velocity into them and sets its contact flags.
+func (w *World) Move(b *Body, dt float64) {
+	delta := b.Vel.Scaled(dt)
+	grounded, slope := b.Grounded, b.slope
+	b.Grounded, b.Ceiling, b.WallLeft, b.WallRight = false, false, false, false
+	b.slope = 0
+
+	w.moveX(b, delta.X, grounded, slope)
+	w.moveY(b, delta.Y, math.Abs(delta.X))
+
+	// stay on the ground walking down the slopes and the steps
+	if grounded && !b.Grounded && delta.Y <= 0 {
+		pos := b.Pos
+		w.moveY(b, -(b.StepHeight + math.Abs(delta.X)*w.TileSize.Y/w.TileSize.X), 0)
+		if !b.Grounded {
+			b.Pos = pos
+		}
+	}
+}
+
+// Overlaps reports whether the Rect overlaps any Solid tile.
+func (w *World) Overlaps(r pixel.Rect) bool {
+	x0, x1 := spanned(r.Min.X, r.Max.X, w.TileSize.X)
+	y0, y1 := spanned(r.Min.Y, r.Max.Y, w.TileSize.Y)
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			if w.Tile(x, y).Kind == Solid {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (w *World) moveX(b *Body, dx float64, grounded bool, slope float64) {
+	if dx == 0 {
+		return
+	}
+	tw, th := w.TileSize.X, w.TileSize.Y
+
+	// the Body walks up onto the tiles this much above its bottom
+	var climb float64
+	if grounded {
+		climb = b.StepHeight + math.Abs(slope)*b.Size.X/2
+	}
+
+	r := b.Rect()
+	lead := r.Max.X
+	if dx < 0 {
+		lead = r.Min.X
+	}
+	first, last, step := crossed(lead, lead+dx, tw)
+	for x := first; x != last+step; x += step {
+		y0, y1 := spanned(r.Min.Y, r.Max.Y, th)
+		top, blocked := r.Min.Y, false
+		for y := y0; y <= y1; y++ {
+			if w.Tile(x, y).Kind != Solid {
+				continue
+			}
+			tileTop := float64(y+1) * th
+			if tileTop > r.Min.Y+climb+eps {
+				blocked = true
+				break
+			}
+			top = math.Max(top, tileTop)
+		}
+
+		if !blocked && top > r.Min.Y {
+			// a step, the Body needs the room above it
+			room := pixel.R(math.Min(r.Min.X, float64(x)*tw), top, math.Max(r.Max.X, float64(x+1)*tw), top+r.H())
+			if w.Overlaps(room) {
+				blocked = true
+			} else {
+				b.Pos.Y = top
+				r = b.Rect()
+			}
+		}
+
+		if blocked {
+			if dx > 0 {
+				b.Pos.X = float64(x)*tw - b.Size.X/2
+				b.WallRight = true
+			} else {
+				b.Pos.X = float64(x+1)*tw + b.Size.X/2
+				b.WallLeft = true
+			}
+			b.Vel.X = 0
+			return
+		}
+	}
+	b.Pos.X += dx
+}
+
+func (w *World) moveY(b *Body, dy float64, dx float64) {
+	tw, th := w.TileSize.X, w.TileSize.Y
+	r := b.Rect()
+
+	switch {
+	case dy > 0:
+		b.Pos.Y += dy
+		first, last, _ := crossed(r.Max.Y, r.Max.Y+dy, th)
+		x0, x1 := spanned(r.Min.X, r.Max.X, tw)
+	up:
+		for y := first; y <= last; y++ {
+			for x := x0; x <= x1; x++ {
+				if k := w.Tile(x, y).Kind; k == Solid || k == Slope {
+					b.Pos.Y = float64(y)*th - b.Size.Y
+					b.Vel.Y = 0
+					b.Ceiling = true
+					break up
+				}
+			}
+		}
+
+	case dy < 0:
+		b.Pos.Y += dy
+		first, last, _ := crossed(r.Min.Y, r.Min.Y+dy, th)
+		x0, x1 := spanned(r.Min.X, r.Max.X, tw)
+	down:
+		for y := first; y >= last; y-- {
+			for x := x0; x <= x1; x++ {
+				if k := w.Tile(x, y).Kind; k == Solid || (k == OneWay && !b.Drop) {
+					b.Pos.Y = float64(y+1) * th
+					b.Vel.Y = 0
+					b.Grounded = true
+					break down
+				}
+			}
+		}
+	}
+
+	// the slopes under the center of the bottom, crossed by the movement or climbed by walking
+	top := math.Max(r.Min.Y, b.Pos.Y)
+	if h, gradient, ok := w.slope(b.Pos.X, b.Pos.Y, top, dx); ok && h >= b.Pos.Y {
+		b.Pos.Y = h
+		if b.Vel.Y <= 0 {
+			b.Vel.Y = 0
+			b.Grounded = true
+			b.slope = gradient
+		}
+	}
+}
+
+// slope returns the highest floor of a Slope at x between the heights bottom and top, or a bit
+// above top, if the Body walked into the Slope by dx. It returns its gradient, too.
+func (w *World) slope(x, bottom, top, dx float64) (h, gradient float64, ok bool) {
+	tw, th := w.TileSize.X, w.TileSize.Y
+	tx := int(math.Floor(x / tw))
+	fx := x/tw - float64(tx)
+	y0 := int(math.Floor(bottom/th - eps))
+	y1 := int(math.Floor((top+dx*th/tw)/th + eps))
+	for y := y1; y >= y0; y-- {
+		t := w.Tile(tx, y)
+		if t.Kind != Slope {
+			continue
+		}
+		g := (t.Right - t.Left) * th / tw
+		floor := (float64(y) + t.Left + (t.Right-t.Left)*fx) * th
+		if floor < bottom-eps || floor > top+dx*math.Abs(g)+eps {
+			continue
+		}
+		if !ok || floor > h {
+			h, gradient, ok = floor, g, true
+		}
+	}
+	return h, gradient, ok
+}
+
+// crossed returns the range of the cells of the size crossed by moving the edge from a to b,
+// without the ones the edge is already inside of.
+func crossed(a, b, size float64) (first, last, step int) {
+	if b >= a {
+		first = int(math.Ceil(a/size - eps))
+		last = int(math.Ceil(b/size-eps)) - 1
+		return first, last, 1
+	}
+	first = int(math.Floor(a/size+eps)) - 1
+	last = int(math.Floor(b/size + eps))
+	return first, last, -1
+}
+
+// spanned returns the range of the cells of the size overlapped by the interval from a to b.
+func spanned(a, b, size float64) (first, last int) {
+	return int(math.Floor(a/size + eps)), int(math.Ceil(b/size-eps)) - 1
+}
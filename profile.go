@@ -0,0 +1,205 @@
+package pixel
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Phase is a part of the work of drawing a frame, timed when profiling is enabled (see
+// SetProfiling).
+type Phase int
+
+// Here's the list of all the phases timed by Pixel.
+const (
+	// PhaseTransform is transforming and converting vertices, e.g. drawing onto a Batch or
+	// updating the vertices of OpenGL triangles.
+	PhaseTransform Phase = iota
+
+	// PhaseUpload is uploading vertices and pixels to the GPU.
+	PhaseUpload
+
+	// PhaseDraw is issuing draws and clears to the GPU.
+	PhaseDraw
+
+	// PhaseSwap is presenting a frame, e.g. swapping the buffers of a Window.
+	PhaseSwap
+
+	numPhases
+)
+
+var phaseNames = [numPhases]string{
+	PhaseTransform: "transform",
+	PhaseUpload:    "upload",
+	PhaseDraw:      "draw",
+	PhaseSwap:      "swap",
+}
+
+// String returns the lowercase name of the Phase, e.g. "transform".
+func (p Phase) String() string {
+	if p < 0 || p >= numPhases {
+		return fmt.Sprintf("Phase(%d)", int(p))
+	}
+	return phaseNames[p]
+}
+
+// PhaseTime is the time spent in a Phase since the last ResetProfile.
+type PhaseTime struct {
+	Phase Phase
+	Calls int
+	Total time.Duration
+}
+
+// String returns a short description of the PhaseTime, e.g. "draw: 120 calls, 1.5ms".
+func (pt PhaseTime) String() string {
+	return fmt.Sprintf("%v: %d calls, %v", pt.Phase, pt.Calls, pt.Total)
+}
+
+var profile struct {
+	enabled int32
+
+	mu    sync.Mutex
+	times [numPhases]PhaseTime
+
+	// the pprof labels of the phases, made once
+	labels [numPhases]pprof.LabelSet
+}
+
+func init() {
+	for p := Phase(0); p < numPhases; p++ {
+		profile.times[p].Phase = p
+		profile.labels[p] = pprof.Labels("pixel", p.String())
+	}
+}
+
+// SetProfiling sets whether Pixel times its phases, see PhaseTimes. It's off by default, because
+// timing costs a little time itself.
+//
+// When profiling is on, the CPU samples taken in a phase started by BeginPhaseContext carry the
+// pprof label "pixel" with the name of the phase, e.g. go tool pprof -tagfocus=pixel=draw shows only
+// the drawing. Pixel labels this way its work on the main thread (see mainthread), the drawing,
+// the uploads and the swaps, but not its work on the goroutines of the program, whose labels it
+// can't preserve. With an execution trace running (see runtime/trace), all the phases are also
+// traced as regions.
+func SetProfiling(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&profile.enabled, v)
+}
+
+// Profiling returns whether Pixel times its phases, see SetProfiling.
+func Profiling() bool {
+	return atomic.LoadInt32(&profile.enabled) != 0
+}
+
+// PhaseTimes returns the time spent in each Phase since the last ResetProfile, in the order of the
+// phases.
+//
+//   pixel.SetProfiling(true)
+//   for !win.Closed() {
+//       pixel.ResetProfile()
+//       ... draw the frame
+//       win.Update()
+//       for _, pt := range pixel.PhaseTimes() {
+//           stats.Record(pt.Phase.String(), pt.Total)
+//       }
+//   }
+func PhaseTimes() []PhaseTime {
+	profile.mu.Lock()
+	defer profile.mu.Unlock()
+	times := make([]PhaseTime, numPhases)
+	copy(times, profile.times[:])
+	return times
+}
+
+// ResetProfile sets the times of all the phases to zero.
+func ResetProfile() {
+	profile.mu.Lock()
+	defer profile.mu.Unlock()
+	for p := range profile.times {
+		profile.times[p].Calls = 0
+		profile.times[p].Total = 0
+	}
+}
+
+// Span is a timed run of a Phase started by BeginPhase.
+type Span struct {
+	phase  Phase
+	start  time.Time
+	region *trace.Region
+
+	// the context with the labels restored by End, nil if the goroutine wasn't labeled
+	ctx context.Context
+}
+
+// BeginPhase starts timing the Phase on the calling goroutine, End stops it. If profiling is off,
+// BeginPhase and End do nothing.
+//
+//   span := pixel.BeginPhase(pixel.PhaseTransform)
+//   ... transform the vertices
+//   span.End()
+//
+// Targets implemented outside of Pixel use it to attribute their work to the phases. BeginPhase
+// leaves the pprof labels of the goroutine as they are, Go has no way to read them so that they
+// could be restored, see BeginPhaseContext.
+func BeginPhase(p Phase) Span {
+	return beginPhase(nil, p)
+}
+
+// BeginPhaseContext starts timing the Phase like BeginPhase and also sets the pprof labels of the
+// calling goroutine to the labels of the context with the label "pixel" of the Phase on top. End
+// sets the labels of the context back, so the context must carry the current labels of the
+// goroutine, like the one passed by pprof.Do:
+//
+//   pprof.Do(ctx, pprof.Labels("system", "minimap"), func(ctx context.Context) {
+//       span := pixel.BeginPhaseContext(ctx, pixel.PhaseDraw)
+//       ... draw the minimap
+//       span.End()
+//   })
+//
+// The phases started by BeginPhaseContext must not overlap on one goroutine.
+func BeginPhaseContext(ctx context.Context, p Phase) Span {
+	return beginPhase(ctx, p)
+}
+
+func beginPhase(ctx context.Context, p Phase) Span {
+	if atomic.LoadInt32(&profile.enabled) == 0 {
+		return Span{phase: -1}
+	}
+	s := Span{phase: p, start: time.Now(), ctx: ctx}
+	if ctx != nil {
+		pprof.SetGoroutineLabels(pprof.WithLabels(ctx, profile.labels[p]))
+	}
+	if trace.IsEnabled() {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		s.region = trace.StartRegion(ctx, "pixel."+p.String())
+	}
+	return s
+}
+
+// End stops timing the Phase started by BeginPhase and adds the time to its PhaseTime.
+func (s Span) End() {
+	if s.phase < 0 {
+		return
+	}
+	elapsed := time.Since(s.start)
+	if s.region != nil {
+		s.region.End()
+	}
+	if s.ctx != nil {
+		pprof.SetGoroutineLabels(s.ctx)
+	}
+
+	profile.mu.Lock()
+	profile.times[s.phase].Calls++
+	profile.times[s.phase].Total += elapsed
+	profile.mu.Unlock()
+}
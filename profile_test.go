@@ -0,0 +1,53 @@
+package pixel_test
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiling(t *testing.T) {
+	defer pixel.SetProfiling(false)
+	pixel.ResetProfile()
+
+	// nothing is timed when profiling is off
+	pixel.BeginPhase(pixel.PhaseDraw).End()
+	assert.Equal(t, 0, pixel.PhaseTimes()[pixel.PhaseDraw].Calls)
+
+	pixel.SetProfiling(true)
+	assert.True(t, pixel.Profiling())
+	span := pixel.BeginPhase(pixel.PhaseDraw)
+	time.Sleep(time.Millisecond)
+	span.End()
+
+	batch := pixel.NewBatch(&pixel.TrianglesData{}, nil)
+	batch.MakeTriangles(pixel.MakeTrianglesData(3)).Draw()
+
+	times := pixel.PhaseTimes()
+	assert.Len(t, times, 4)
+	assert.Equal(t, pixel.PhaseDraw, times[pixel.PhaseDraw].Phase)
+	assert.Equal(t, 1, times[pixel.PhaseDraw].Calls)
+	assert.True(t, times[pixel.PhaseDraw].Total >= time.Millisecond)
+	assert.Equal(t, 1, times[pixel.PhaseTransform].Calls)
+	assert.Equal(t, 0, times[pixel.PhaseSwap].Calls)
+
+	// the labels of the caller are set back after the phase
+	pprof.Do(context.Background(), pprof.Labels("system", "test"), func(ctx context.Context) {
+		pixel.BeginPhaseContext(ctx, pixel.PhaseUpload).End()
+	})
+	assert.Equal(t, 1, pixel.PhaseTimes()[pixel.PhaseUpload].Calls)
+
+	pixel.ResetProfile()
+	assert.Equal(t, pixel.PhaseTime{Phase: pixel.PhaseDraw}, pixel.PhaseTimes()[pixel.PhaseDraw])
+}
+
+func TestPhase_String(t *testing.T) {
+	assert.Equal(t, "transform", pixel.PhaseTransform.String())
+	assert.Equal(t, "swap", pixel.PhaseSwap.String())
+	assert.Equal(t, "Phase(7)", pixel.Phase(7).String())
+	assert.Equal(t, "upload: 2 calls, 3ms", pixel.PhaseTime{Phase: pixel.PhaseUpload, Calls: 2, Total: 3 * time.Millisecond}.String())
+}
@@ -0,0 +1,170 @@
+// Package quality adapts the quality of the rendering to a frame budget, so that games degrade
+// gracefully on weak hardware: a Controller watches the recent frame times and lowers its quality
+// level when the frames take too long, and raises it back when there's time to spare. The knobs
+// derived from the level, such as the resolution scale of a Canvas, the number of particles or the
+// toggles of the effects, call back the game when their values change.
+package quality
+
+import (
+	"math"
+	"time"
+)
+
+// Controller keeps a quality level within range [0, 1], where 1 is the full quality, adjusted by
+// the frame times it's updated with.
+//
+//   q := quality.NewController(time.Second / 60)
+//   q.Scale(0.5, 1, func(scale float64) {
+//       canvas.SetBounds(pixel.R(0, 0, 640*scale, 360*scale))
+//   })
+//   q.Cap(100, 2000, func(n int) { emitter.Max = n })
+//   q.Toggle(0.75, func(on bool) { bloom = on })
+//
+//   last := time.Now()
+//   for !win.Closed() {
+//       ... draw the frame
+//       q.Update(time.Since(last))
+//       win.Update()
+//       last = time.Now()
+//   }
+//
+// With VSync on, the time of a whole frame never drops below the refresh interval, so measure the
+// work of the frame before Window.Update, as above.
+type Controller struct {
+	// Budget is the time a frame should take at most.
+	Budget time.Duration
+
+	// Frames is the number of frames averaged before the level changes. After a change, the
+	// Controller waits for as many frames with the new level.
+	Frames int
+
+	// Step is how much the level changes at once.
+	Step float64
+
+	// Headroom is the fraction of the Budget the average frame time must stay below for the level
+	// to be raised, which keeps the level from oscillating. It should be lower than 1 - Step.
+	Headroom float64
+
+	level float64
+	sum   time.Duration
+	count int
+	knobs []*Knob
+}
+
+// NewController creates a Controller with the full quality and the frame budget. It averages 30
+// frames, changes the level by 0.1 and raises it when the frames take less than 80% of the budget.
+func NewController(budget time.Duration) *Controller {
+	return &Controller{
+		Budget:   budget,
+		Frames:   30,
+		Step:     0.1,
+		Headroom: 0.8,
+		level:    1,
+	}
+}
+
+// Update records the time of a frame and adjusts the level once Frames frames are recorded.
+func (c *Controller) Update(frame time.Duration) {
+	c.sum += frame
+	c.count++
+	if c.count < c.Frames {
+		return
+	}
+	avg := c.sum / time.Duration(c.count)
+	c.sum, c.count = 0, 0
+
+	switch {
+	case avg > c.Budget:
+		c.SetLevel(c.level - c.Step)
+	case float64(avg) < c.Headroom*float64(c.Budget):
+		c.SetLevel(c.level + c.Step)
+	}
+}
+
+// SetLevel sets the quality level, clamped to range [0, 1], and calls back the knobs whose values
+// change. The frames recorded so far are dropped.
+func (c *Controller) SetLevel(level float64) {
+	// rounded, so that the levels reached by steps compare equal to the thresholds
+	c.level = math.Round(math.Max(0, math.Min(1, level))*1e9) / 1e9
+	c.sum, c.count = 0, 0
+	for _, k := range c.knobs {
+		k.update(c.level)
+	}
+}
+
+// Level returns the current quality level.
+func (c *Controller) Level() float64 {
+	return c.level
+}
+
+// Scale adds a Knob interpolating between low at the level 0 and high at the level 1, e.g. the
+// resolution scale of a Canvas.
+func (c *Controller) Scale(low, high float64, f func(v float64)) *Knob {
+	return c.add(func(level float64) float64 {
+		return low + (high-low)*level
+	}, f)
+}
+
+// Cap adds a Knob interpolating between the integers low at the level 0 and high at the level 1,
+// e.g. the number of particles, rounded to the nearest integer.
+func (c *Controller) Cap(low, high int, f func(n int)) *Knob {
+	return c.add(func(level float64) float64 {
+		return math.Round(float64(low) + float64(high-low)*level)
+	}, func(v float64) {
+		f(int(v))
+	})
+}
+
+// Toggle adds a Knob which is on (1) with the level at least the threshold and off (0) below it,
+// e.g. an expensive effect.
+func (c *Controller) Toggle(threshold float64, f func(on bool)) *Knob {
+	return c.add(func(level float64) float64 {
+		if level >= threshold {
+			return 1
+		}
+		return 0
+	}, func(v float64) {
+		f(v != 0)
+	})
+}
+
+// add adds the knob and calls it back with its value at the current level
+func (c *Controller) add(value func(level float64) float64, f func(v float64)) *Knob {
+	k := &Knob{value: value, f: f, v: value(c.level)}
+	c.knobs = append(c.knobs, k)
+	f(k.v)
+	return k
+}
+
+// Remove removes the Knob from the Controller, its function isn't called anymore.
+func (c *Controller) Remove(k *Knob) {
+	for i := range c.knobs {
+		if c.knobs[i] == k {
+			c.knobs = append(c.knobs[:i], c.knobs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Knob is a value derived from the level of a Controller. Its function is called with the value
+// when the Knob is added and whenever the value changes.
+type Knob struct {
+	value func(level float64) float64
+	f     func(v float64)
+	v     float64
+}
+
+// Value returns the current value of the Knob. The value of a Toggle is 1 when it's on and 0 when
+// it's off.
+func (k *Knob) Value() float64 {
+	return k.v
+}
+
+func (k *Knob) update(level float64) {
+	v := k.value(level)
+	if v == k.v {
+		return
+	}
+	k.v = v
+	k.f(v)
+}
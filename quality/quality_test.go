@@ -0,0 +1,68 @@
+package quality_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/faiface/pixel/quality"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestController(t *testing.T) {
+	q := quality.NewController(10 * time.Millisecond)
+	q.Frames = 4
+
+	var (
+		scales []float64
+		caps   []int
+		toggle []bool
+	)
+	scale := q.Scale(0.5, 1, func(v float64) { scales = append(scales, v) })
+	q.Cap(0, 10, func(n int) { caps = append(caps, n) })
+	q.Toggle(0.9, func(on bool) { toggle = append(toggle, on) })
+
+	// the knobs are called back with their initial values
+	assert.Equal(t, []float64{1}, scales)
+	assert.Equal(t, []int{10}, caps)
+	assert.Equal(t, []bool{true}, toggle)
+
+	// slow frames lower the level once the window is full
+	for i := 0; i < 3; i++ {
+		q.Update(20 * time.Millisecond)
+	}
+	assert.Equal(t, 1.0, q.Level())
+	q.Update(20 * time.Millisecond)
+	assert.Equal(t, 0.9, q.Level())
+	assert.Equal(t, []int{10, 9}, caps)
+	assert.Equal(t, []bool{true}, toggle)
+
+	for i := 0; i < 4; i++ {
+		q.Update(20 * time.Millisecond)
+	}
+	assert.Equal(t, 0.8, q.Level())
+	assert.InDelta(t, 0.9, scale.Value(), 1e-9)
+	assert.Equal(t, []bool{true, false}, toggle)
+
+	// frames within the budget, but without the headroom, keep the level
+	for i := 0; i < 8; i++ {
+		q.Update(9 * time.Millisecond)
+	}
+	assert.Equal(t, 0.8, q.Level())
+
+	// fast frames raise it back
+	for i := 0; i < 4; i++ {
+		q.Update(time.Millisecond)
+	}
+	assert.Equal(t, 0.9, q.Level())
+	assert.Equal(t, []bool{true, false, true}, toggle)
+
+	q.SetLevel(-1)
+	assert.Equal(t, 0.0, q.Level())
+	assert.Equal(t, 0, caps[len(caps)-1])
+	assert.Equal(t, 0.5, scale.Value())
+
+	q.Remove(scale)
+	n := len(scales)
+	q.SetLevel(1)
+	assert.Len(t, scales, n)
+}
@@ -0,0 +1,299 @@
+// Package raster implements a software rendering backend for Pixel, which draws into memory on the
+// CPU without any OpenGL context or window.
+//
+// It's useful for testing drawing code headlessly and for small tools that want to avoid cgo.
+package raster
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Canvas is an in-memory rectangular ComposeTarget and Picture at the same time, that you can draw
+// onto. All drawing is done on the CPU, so a Canvas requires no OpenGL context or window.
+//
+// Canvas mirrors the API of pixelgl.Canvas, so code drawing onto one can draw onto the other
+// without changes. It supports TrianglesPosition, TrianglesColor, TrianglesPicture and
+// PictureColor.
+type Canvas struct {
+	pd *pixel.PictureData
+
+	cmp    pixel.ComposeMethod
+	mat    pixel.Matrix
+	col    pixel.RGBA
+	smooth bool
+
+	sprite *pixel.Sprite
+}
+
+var _ pixel.ComposeTarget = (*Canvas)(nil)
+
+// NewCanvas creates a new empty, fully transparent Canvas with given bounds.
+func NewCanvas(bounds pixel.Rect) *Canvas {
+	c := &Canvas{
+		mat: pixel.IM,
+		col: pixel.Alpha(1),
+	}
+	c.SetBounds(bounds)
+	return c
+}
+
+// MakeTriangles creates a specialized copy of the supplied Triangles that draws onto this Canvas.
+//
+// TrianglesPosition, TrianglesColor and TrianglesPicture are supported.
+func (c *Canvas) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	td := pixel.MakeTrianglesData(t.Len())
+	td.Update(t)
+	return &canvasTriangles{
+		data: td,
+		dst:  c,
+	}
+}
+
+// MakePicture create a specialized copy of the supplied Picture that draws onto this Canvas.
+//
+// PictureColor is supported. Drawing another Canvas always uses its current content.
+func (c *Canvas) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	switch p := p.(type) {
+	case *canvasPicture:
+		return &canvasPicture{
+			pd:  p.pd,
+			src: p.src,
+			dst: c,
+		}
+	case *Canvas:
+		return &canvasPicture{
+			src: p,
+			dst: c,
+		}
+	}
+	return &canvasPicture{
+		pd:  pixel.PictureDataFromPicture(p),
+		dst: c,
+	}
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (c *Canvas) SetMatrix(m pixel.Matrix) {
+	c.mat = m
+}
+
+// SetColorMask sets a color that every color in triangles or a picture will be multiplied by.
+func (c *Canvas) SetColorMask(col color.Color) {
+	if col == nil {
+		c.col = pixel.Alpha(1)
+		return
+	}
+	c.col = pixel.ToRGBA(col)
+}
+
+// SetComposeMethod sets a Porter-Duff composition method to be used in the following draws onto
+// this Canvas.
+func (c *Canvas) SetComposeMethod(cmp pixel.ComposeMethod) {
+	c.cmp = cmp
+}
+
+// SetBounds resizes the Canvas to the new bounds. Old content will be preserved.
+func (c *Canvas) SetBounds(bounds pixel.Rect) {
+	old := c.pd
+	c.pd = pixel.MakePictureData(bounds)
+	if old != nil {
+		// copy the pixels in the intersection of the old and the new bounds
+		ox, oy, ow, oh := intBounds(old)
+		nx, ny, nw, nh := intBounds(c.pd)
+		for y := maxInt(oy, ny); y < minInt(oy+oh, ny+nh); y++ {
+			for x := maxInt(ox, nx); x < minInt(ox+ow, nx+nw); x++ {
+				c.pd.Pix[(y-ny)*nw+(x-nx)] = old.Pix[(y-oy)*ow+(x-ox)]
+			}
+		}
+	}
+
+	if c.sprite == nil {
+		c.sprite = pixel.NewSprite(nil, pixel.Rect{})
+	}
+	c.sprite.Set(c, c.Bounds())
+}
+
+// Bounds returns the rectangular bounds of the Canvas.
+func (c *Canvas) Bounds() pixel.Rect {
+	return c.pd.Rect
+}
+
+// SetSmooth sets whether stretched Pictures drawn onto this Canvas should be drawn smooth or
+// pixely.
+func (c *Canvas) SetSmooth(smooth bool) {
+	c.smooth = smooth
+}
+
+// Smooth returns whether stretched Pictures drawn onto this Canvas are set to be drawn smooth or
+// pixely.
+func (c *Canvas) Smooth() bool {
+	return c.smooth
+}
+
+// Clear fills the whole Canvas with a single color.
+func (c *Canvas) Clear(color color.Color) {
+	rgba := quantize(pixel.ToRGBA(color).Mul(c.col))
+	for i := range c.pd.Pix {
+		c.pd.Pix[i] = rgba
+	}
+}
+
+// Color returns the color of the pixel over the given position inside the Canvas.
+func (c *Canvas) Color(at pixel.Vec) pixel.RGBA {
+	return c.pd.Color(at)
+}
+
+// SetPixels replaces the content of the Canvas with the provided pixels. The provided slice must be
+// an alpha-premultiplied RGBA sequence of correct length (4 * width * height), starting with the
+// bottom row, just like with pixelgl.Canvas.
+func (c *Canvas) SetPixels(pixels []uint8) {
+	if len(pixels) != 4*len(c.pd.Pix) {
+		panic(fmt.Errorf("(%T).SetPixels: invalid pixels length", c))
+	}
+	for i := range c.pd.Pix {
+		c.pd.Pix[i] = color.RGBA{
+			R: pixels[i*4+0],
+			G: pixels[i*4+1],
+			B: pixels[i*4+2],
+			A: pixels[i*4+3],
+		}
+	}
+}
+
+// Pixels returns an alpha-premultiplied RGBA sequence of the content of the Canvas, starting with
+// the bottom row, just like with pixelgl.Canvas.
+func (c *Canvas) Pixels() []uint8 {
+	pixels := make([]uint8, 4*len(c.pd.Pix))
+	for i, p := range c.pd.Pix {
+		pixels[i*4+0] = p.R
+		pixels[i*4+1] = p.G
+		pixels[i*4+2] = p.B
+		pixels[i*4+3] = p.A
+	}
+	return pixels
+}
+
+// Image returns the content of the Canvas as an image.RGBA.
+func (c *Canvas) Image() *image.RGBA {
+	return c.pd.Image()
+}
+
+// Draw draws the content of the Canvas onto another Target, transformed by the given Matrix, just
+// like if it was a Sprite containing the whole Canvas.
+func (c *Canvas) Draw(t pixel.Target, matrix pixel.Matrix) {
+	c.sprite.Draw(t, matrix)
+}
+
+// DrawColorMask draws the content of the Canvas onto another Target, transformed by the given
+// Matrix and multiplied by the given mask, just like if it was a Sprite containing the whole Canvas.
+//
+// If the color mask is nil, a fully opaque white mask will be used causing no effect.
+func (c *Canvas) DrawColorMask(t pixel.Target, matrix pixel.Matrix, mask color.Color) {
+	c.sprite.DrawColorMask(t, matrix, mask)
+}
+
+type canvasTriangles struct {
+	data *pixel.TrianglesData
+	dst  *Canvas
+}
+
+func (ct *canvasTriangles) Len() int {
+	return ct.data.Len()
+}
+
+func (ct *canvasTriangles) SetLen(len int) {
+	ct.data.SetLen(len)
+}
+
+func (ct *canvasTriangles) Slice(i, j int) pixel.Triangles {
+	return &canvasTriangles{
+		data: ct.data.Slice(i, j).(*pixel.TrianglesData),
+		dst:  ct.dst,
+	}
+}
+
+func (ct *canvasTriangles) Update(t pixel.Triangles) {
+	ct.data.Update(t)
+}
+
+func (ct *canvasTriangles) Copy() pixel.Triangles {
+	return &canvasTriangles{
+		data: ct.data.Copy().(*pixel.TrianglesData),
+		dst:  ct.dst,
+	}
+}
+
+func (ct *canvasTriangles) Position(i int) pixel.Vec {
+	return ct.data.Position(i)
+}
+
+func (ct *canvasTriangles) Color(i int) pixel.RGBA {
+	return ct.data.Color(i)
+}
+
+func (ct *canvasTriangles) Picture(i int) (pic pixel.Vec, intensity float64) {
+	return ct.data.Picture(i)
+}
+
+func (ct *canvasTriangles) Draw() {
+	ct.dst.rasterize(*ct.data, nil)
+}
+
+type canvasPicture struct {
+	pd  *pixel.PictureData
+	src *Canvas
+	dst *Canvas
+}
+
+func (cp *canvasPicture) data() *pixel.PictureData {
+	if cp.src != nil {
+		return cp.src.pd
+	}
+	return cp.pd
+}
+
+func (cp *canvasPicture) Bounds() pixel.Rect {
+	return cp.data().Bounds()
+}
+
+func (cp *canvasPicture) Color(at pixel.Vec) pixel.RGBA {
+	return cp.data().Color(at)
+}
+
+func (cp *canvasPicture) Draw(t pixel.TargetTriangles) {
+	ct := t.(*canvasTriangles)
+	if cp.dst != ct.dst {
+		panic(fmt.Errorf("(%T).Draw: TargetTriangles generated by different Canvas", cp))
+	}
+	ct.dst.rasterize(*ct.data, cp.data())
+}
+
+// intBounds returns the integer position of the bottom-left pixel of the PictureData and its size
+// in pixels.
+func intBounds(pd *pixel.PictureData) (x, y, w, h int) {
+	x, y = int(math.Floor(pd.Rect.Min.X)), int(math.Floor(pd.Rect.Min.Y))
+	w = pd.Stride
+	if w > 0 {
+		h = len(pd.Pix) / w
+	}
+	return x, y, w, h
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,108 @@
+package raster_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+)
+
+func square(rect pixel.Rect, col pixel.RGBA) *pixel.TrianglesData {
+	td := pixel.MakeTrianglesData(6)
+	for i, v := range []pixel.Vec{
+		rect.Min, pixel.V(rect.Max.X, rect.Min.Y), rect.Max,
+		rect.Min, rect.Max, pixel.V(rect.Min.X, rect.Max.Y),
+	} {
+		(*td)[i].Position = v
+		(*td)[i].Color = col
+	}
+	return td
+}
+
+func TestCanvasFill(t *testing.T) {
+	c := raster.NewCanvas(pixel.R(0, 0, 8, 8))
+	d := pixel.Drawer{Triangles: square(pixel.R(2, 2, 6, 6), pixel.RGB(1, 0, 0))}
+	d.Draw(c)
+
+	for y := 0.0; y < 8; y++ {
+		for x := 0.0; x < 8; x++ {
+			want := pixel.Alpha(0)
+			if x >= 2 && x < 6 && y >= 2 && y < 6 {
+				want = pixel.RGB(1, 0, 0)
+			}
+			if got := c.Color(pixel.V(x, y)); got != want {
+				t.Fatalf("Color(%v, %v) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestCanvasSharedEdge(t *testing.T) {
+	// two halves of a square sharing the diagonal must not blend twice on it
+	c := raster.NewCanvas(pixel.R(0, 0, 4, 4))
+	d := pixel.Drawer{Triangles: square(pixel.R(0, 0, 4, 4), pixel.RGBA{R: 0, G: 0, B: 0.5, A: 0.5})}
+	d.Draw(c)
+
+	want := color.RGBA{B: 128, A: 128}
+	pixels := c.Pixels()
+	for i := 0; i < len(pixels); i += 4 {
+		got := color.RGBA{R: pixels[i], G: pixels[i+1], B: pixels[i+2], A: pixels[i+3]}
+		if got != want {
+			t.Fatalf("pixel %d = %v, want %v", i/4, got, want)
+		}
+	}
+}
+
+func TestCanvasMatrixAndMask(t *testing.T) {
+	c := raster.NewCanvas(pixel.R(0, 0, 8, 8))
+	c.SetMatrix(pixel.IM.Moved(pixel.V(4, 4)))
+	c.SetColorMask(pixel.RGB(0, 1, 0))
+	d := pixel.Drawer{Triangles: square(pixel.R(0, 0, 2, 2), pixel.RGB(1, 1, 1))}
+	d.Draw(c)
+
+	if got := c.Color(pixel.V(1, 1)); got != pixel.Alpha(0) {
+		t.Errorf("Color outside moved square = %v, want transparent", got)
+	}
+	if got := c.Color(pixel.V(5, 5)); got != pixel.RGB(0, 1, 0) {
+		t.Errorf("Color inside moved square = %v, want green", got)
+	}
+}
+
+func TestCanvasPicture(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 1))
+	pic.Pix[0] = color.RGBA{R: 255, A: 255}
+	pic.Pix[1] = color.RGBA{B: 255, A: 255}
+
+	c := raster.NewCanvas(pixel.R(0, 0, 4, 2))
+	sprite := pixel.NewSprite(pic, pic.Bounds())
+	sprite.Draw(c, pixel.IM.Scaled(pixel.ZV, 2).Moved(c.Bounds().Center()))
+
+	if got := c.Color(pixel.V(0, 0)); got != pixel.RGB(1, 0, 0) {
+		t.Errorf("left half = %v, want red", got)
+	}
+	if got := c.Color(pixel.V(3, 1)); got != pixel.RGB(0, 0, 1) {
+		t.Errorf("right half = %v, want blue", got)
+	}
+}
+
+func TestCanvasSetBounds(t *testing.T) {
+	c := raster.NewCanvas(pixel.R(0, 0, 4, 4))
+	c.Clear(pixel.RGB(1, 1, 1))
+	c.SetBounds(pixel.R(2, 2, 6, 6))
+
+	if got := c.Color(pixel.V(3, 3)); got != pixel.RGB(1, 1, 1) {
+		t.Errorf("preserved pixel = %v, want white", got)
+	}
+	if got := c.Color(pixel.V(5, 5)); got != pixel.Alpha(0) {
+		t.Errorf("new pixel = %v, want transparent", got)
+	}
+}
+
+func BenchmarkCanvasFill(b *testing.B) {
+	c := raster.NewCanvas(pixel.R(0, 0, 256, 256))
+	d := pixel.Drawer{Triangles: square(c.Bounds(), pixel.RGB(1, 0, 0))}
+	for i := 0; i < b.N; i++ {
+		d.Draw(c)
+	}
+}
@@ -0,0 +1,128 @@
+package raster
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// rasterize draws the triangles onto the Canvas with the current matrix, color mask and compose
+// method. The picture may be nil.
+//
+// A pixel is covered by a triangle if its center lies inside the triangle. Pixels whose center lies
+// exactly on a shared edge are drawn only once (the usual top-left rule), so adjacent triangles
+// don't overlap.
+func (c *Canvas) rasterize(td pixel.TrianglesData, pic *pixel.PictureData) {
+	bx, by, bw, bh := intBounds(c.pd)
+
+	for i := 0; i+2 < len(td); i += 3 {
+		v := [3]int{i, i + 1, i + 2}
+		p := [3]pixel.Vec{
+			c.mat.Project(td[i].Position),
+			c.mat.Project(td[i+1].Position),
+			c.mat.Project(td[i+2].Position),
+		}
+
+		area := p[1].Sub(p[0]).Cross(p[2].Sub(p[0]))
+		if area == 0 {
+			continue
+		}
+		if area < 0 {
+			// make the triangle counter-clockwise
+			v[1], v[2] = v[2], v[1]
+			p[1], p[2] = p[2], p[1]
+			area = -area
+		}
+
+		minX := maxInt(bx, int(math.Floor(math.Min(p[0].X, math.Min(p[1].X, p[2].X)))))
+		minY := maxInt(by, int(math.Floor(math.Min(p[0].Y, math.Min(p[1].Y, p[2].Y)))))
+		maxX := minInt(bx+bw, int(math.Ceil(math.Max(p[0].X, math.Max(p[1].X, p[2].X)))))
+		maxY := minInt(by+bh, int(math.Ceil(math.Max(p[0].Y, math.Max(p[1].Y, p[2].Y)))))
+
+		for y := minY; y < maxY; y++ {
+			for x := minX; x < maxX; x++ {
+				at := pixel.V(float64(x)+0.5, float64(y)+0.5)
+
+				w0 := edge(p[1], p[2], at)
+				w1 := edge(p[2], p[0], at)
+				w2 := edge(p[0], p[1], at)
+				if !covers(w0, p[1], p[2]) || !covers(w1, p[2], p[0]) || !covers(w2, p[0], p[1]) {
+					continue
+				}
+				w0, w1, w2 = w0/area, w1/area, w2/area
+
+				a, b, d := &td[v[0]], &td[v[1]], &td[v[2]]
+				col := a.Color.Scaled(w0).Add(b.Color.Scaled(w1)).Add(d.Color.Scaled(w2))
+				if pic != nil {
+					intensity := a.Intensity*w0 + b.Intensity*w1 + d.Intensity*w2
+					if intensity != 0 {
+						texAt := a.Picture.Scaled(w0).Add(b.Picture.Scaled(w1)).Add(d.Picture.Scaled(w2))
+						tex := sample(pic, texAt, c.smooth)
+						col = col.Scaled(1 - intensity).Add(col.Mul(tex).Scaled(intensity))
+					}
+				}
+				col = col.Mul(c.col)
+
+				off := (y-by)*bw + (x - bx)
+				c.pd.Pix[off] = quantize(c.cmp.Compose(col, pixel.ToRGBA(c.pd.Pix[off])))
+			}
+		}
+	}
+}
+
+// edge returns the doubled signed area of the triangle (a, b, p), which is positive if p is on the
+// left side of the edge going from a to b.
+func edge(a, b, p pixel.Vec) float64 {
+	return b.Sub(a).Cross(p.Sub(a))
+}
+
+// covers reports whether a point with the edge value w relative to the edge (a, b) of a
+// counter-clockwise triangle is inside the triangle.
+func covers(w float64, a, b pixel.Vec) bool {
+	if w != 0 {
+		return w > 0
+	}
+	// top or left edge
+	d := b.Sub(a)
+	return d.Y < 0 || (d.Y == 0 && d.X < 0)
+}
+
+// sample returns the color of the PictureData at the position, clamped to its edge pixels. If smooth
+// is true, the four nearest pixels are interpolated.
+func sample(pd *pixel.PictureData, at pixel.Vec, smooth bool) pixel.RGBA {
+	x, y, w, h := intBounds(pd)
+	if w == 0 || h == 0 {
+		return pixel.Alpha(0)
+	}
+	pix := func(px, py int) pixel.RGBA {
+		px = minInt(maxInt(px-x, 0), w-1)
+		py = minInt(maxInt(py-y, 0), h-1)
+		return pixel.ToRGBA(pd.Pix[py*w+px])
+	}
+
+	if !smooth {
+		return pix(int(math.Floor(at.X)), int(math.Floor(at.Y)))
+	}
+
+	fx, fy := at.X-0.5, at.Y-0.5
+	x0, y0 := math.Floor(fx), math.Floor(fy)
+	tx, ty := fx-x0, fy-y0
+	px, py := int(x0), int(y0)
+	bottom := pix(px, py).Scaled(1 - tx).Add(pix(px+1, py).Scaled(tx))
+	top := pix(px, py+1).Scaled(1 - tx).Add(pix(px+1, py+1).Scaled(tx))
+	return bottom.Scaled(1 - ty).Add(top.Scaled(ty))
+}
+
+// quantize converts an RGBA into color.RGBA, clamping the components into the valid range.
+func quantize(c pixel.RGBA) color.RGBA {
+	q := func(v float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+	return color.RGBA{
+		R: q(c.R),
+		G: q(c.G),
+		B: q(c.B),
+		A: q(c.A),
+	}
+}
@@ -0,0 +1,315 @@
+package pixel
+
+import (
+	"image/color"
+	"math"
+	"sort"
+)
+
+// Renderable is an object kept and drawn by a Renderer.
+//
+// Bounds returns the rectangle the object covers in the world, the objects outside of the view are
+// not drawn. Picture returns the Picture the object is drawn with, or nil if it's drawn without
+// one (such as IMDraw shapes). The following objects with the same Picture are drawn at once
+// through a Batch, so Draw must only draw the Picture returned by Picture.
+type Renderable interface {
+	Bounds() Rect
+	Picture() Picture
+	Draw(t Target)
+}
+
+// SpriteRenderable is a Renderable Sprite drawn with the Matrix and the color Mask. If the Sprite's
+// frame has no transparent pixels, set Opaque to let it hide what's behind it, see Occluder.
+type SpriteRenderable struct {
+	Sprite *Sprite
+	Matrix Matrix
+	Mask   color.Color
+	Opaque bool
+}
+
+// Bounds returns the rectangle covering the Sprite's frame transformed by the Matrix.
+func (sr *SpriteRenderable) Bounds() Rect {
+	frame := sr.Sprite.Frame()
+	return transformedBounds(sr.Matrix, frame.Moved(frame.Center().Scaled(-1)))
+}
+
+// Picture returns the Picture of the Sprite.
+func (sr *SpriteRenderable) Picture() Picture {
+	return sr.Sprite.Picture()
+}
+
+// Draw draws the Sprite onto the Target.
+func (sr *SpriteRenderable) Draw(t Target) {
+	sr.Sprite.DrawColorMask(t, sr.Matrix, sr.Mask)
+}
+
+// Renderer keeps the objects of a world in layers and draws the ones visible by a Camera. The
+// layers are drawn from the lowest to the highest, the objects inside a layer in the order of
+// adding, or sorted by the Sort function.
+//
+//   r := pixel.NewRenderer()
+//   r.Sort = pixel.SortByY
+//   r.Add(0, ground)
+//   r.Add(1, &pixel.SpriteRenderable{Sprite: tree, Matrix: pixel.IM.Moved(pos)})
+//   r.Add(1, player)
+//
+//   win.SetMatrix(cam.Matrix())
+//   r.Draw(win, cam)
+//
+// The objects following each other with the same Picture are collected into a Batch and drawn at
+// once, so it pays off to put the objects from a single atlas into one layer. With Occlusion on,
+// the objects hidden behind opaque ones aren't drawn, e.g. the tiles covered by higher layers, and
+// on a DepthTarget the hidden parts of the objects aren't shaded (see OpaqueDrawer).
+type Renderer struct {
+	// Sort reports whether the object a is drawn before the object b in the same layer. If it's
+	// nil, the objects are drawn in the order they were added.
+	Sort func(a, b Renderable) bool
+
+	// Occlusion enables culling of the objects hidden behind the Occluders drawn after them and,
+	// on a DepthTarget, the depth-tested drawing of the OpaqueDrawers. The objects are expected to
+	// be drawn with ComposeOver.
+	Occlusion bool
+
+	layers    []renderLayer
+	visible   []Renderable
+	runs      []renderRun
+	batches   []*Batch
+	occluders occlusionGrid
+}
+
+type renderLayer struct {
+	index   int
+	objects []Renderable
+}
+
+// renderRun is a run of the visible objects drawn at once, the objects i to j
+type renderRun struct {
+	i, j   int
+	opaque bool
+}
+
+// SortByY sorts the objects from the top to the bottom of the world, so the lower ones are drawn
+// in front of the higher ones, as in the top-down games.
+func SortByY(a, b Renderable) bool {
+	return a.Bounds().Min.Y > b.Bounds().Min.Y
+}
+
+// NewRenderer creates a new empty Renderer.
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+// Add adds the object on top of the layer.
+func (r *Renderer) Add(layer int, obj Renderable) {
+	i := sort.Search(len(r.layers), func(i int) bool {
+		return r.layers[i].index >= layer
+	})
+	if i == len(r.layers) || r.layers[i].index != layer {
+		r.layers = append(r.layers, renderLayer{})
+		copy(r.layers[i+1:], r.layers[i:])
+		r.layers[i] = renderLayer{index: layer}
+	}
+	r.layers[i].objects = append(r.layers[i].objects, obj)
+}
+
+// Remove removes the object from all layers of the Renderer.
+func (r *Renderer) Remove(obj Renderable) {
+	for i := range r.layers {
+		objects := r.layers[i].objects[:0]
+		for _, o := range r.layers[i].objects {
+			if o != obj {
+				objects = append(objects, o)
+			}
+		}
+		for j := len(objects); j < len(r.layers[i].objects); j++ {
+			r.layers[i].objects[j] = nil
+		}
+		r.layers[i].objects = objects
+	}
+}
+
+// Clear removes all objects from the Renderer.
+func (r *Renderer) Clear() {
+	r.layers = nil
+}
+
+// Len returns the number of objects in the Renderer.
+func (r *Renderer) Len() int {
+	n := 0
+	for _, l := range r.layers {
+		n += len(l.objects)
+	}
+	return n
+}
+
+// Draw draws the objects visible by the Camera onto the Target and returns how many were drawn.
+// The Target's Matrix must be the Camera's Matrix.
+func (r *Renderer) Draw(t Target, cam *Camera) int {
+	culler := cam.Culler()
+	r.visible = r.visible[:0]
+	for _, l := range r.layers {
+		start := len(r.visible)
+		for _, obj := range l.objects {
+			if culler.Visible(obj.Bounds()) {
+				r.visible = append(r.visible, obj)
+			}
+		}
+		if r.Sort != nil {
+			layer := r.visible[start:]
+			sort.SliceStable(layer, func(i, j int) bool {
+				return r.Sort(layer[i], layer[j])
+			})
+		}
+	}
+	dt, depth := t.(DepthTarget)
+	depth = depth && r.Occlusion
+	if r.Occlusion {
+		r.cullOccluded(culler.View())
+	}
+
+	r.splitRuns(depth)
+	if depth {
+		r.drawDepth(dt)
+	} else {
+		batch := 0
+		for _, run := range r.runs {
+			batch = r.drawRun(t, run, batch, false)
+		}
+	}
+
+	drawn := len(r.visible)
+	for i := range r.visible {
+		r.visible[i] = nil
+	}
+	return drawn
+}
+
+// splitRuns splits the visible objects into the runs of the objects with the same Picture, and the
+// same opacity if opaque is true
+func (r *Renderer) splitRuns(opaque bool) {
+	r.runs = r.runs[:0]
+	for i := 0; i < len(r.visible); {
+		pic := r.visible[i].Picture()
+		op := opaque && drawsOpaque(r.visible[i])
+		j := i + 1
+		for j < len(r.visible) && pic != nil && r.visible[j].Picture() == pic &&
+			(!opaque || drawsOpaque(r.visible[j]) == op) {
+			j++
+		}
+		r.runs = append(r.runs, renderRun{i: i, j: j, opaque: op})
+		i = j
+	}
+}
+
+// drawsOpaque returns whether the object is an OpaqueDrawer drawing only opaque pixels
+func drawsOpaque(obj Renderable) bool {
+	o, ok := obj.(OpaqueDrawer)
+	return ok && o.DrawsOpaque()
+}
+
+// drawDepth draws the runs at their depths, the later runs nearer. The opaque runs are drawn first,
+// from the front to the back with depth writes, so the pixels hidden behind them are never shaded,
+// then the translucent runs from the back to the front over them.
+func (r *Renderer) drawDepth(t DepthTarget) {
+	n := len(r.runs)
+	depth := func(k int) float64 {
+		return float64(n-k) / float64(n+1)
+	}
+
+	t.ClearDepth()
+	batch := 0
+	for k := n - 1; k >= 0; k-- {
+		if r.runs[k].opaque {
+			t.SetDepth(depth(k), true)
+			// the objects of a run share the depth, so the first drawn covers the later ones
+			batch = r.drawRun(t, r.runs[k], batch, true)
+		}
+	}
+	for k := 0; k < n; k++ {
+		if !r.runs[k].opaque {
+			t.SetDepth(depth(k), false)
+			batch = r.drawRun(t, r.runs[k], batch, false)
+		}
+	}
+	t.SetDepth(-1, false)
+}
+
+// drawRun draws the objects of the run, through the batch-th Batch if there are more of them, in
+// the reverse order if reverse is true. It returns the index of the next Batch.
+func (r *Renderer) drawRun(t Target, run renderRun, batch int, reverse bool) int {
+	objects := r.visible[run.i:run.j]
+	if len(objects) == 1 {
+		objects[0].Draw(t)
+		return batch
+	}
+	b := r.batch(batch, objects[0].Picture())
+	for k := range objects {
+		if reverse {
+			k = len(objects) - 1 - k
+		}
+		objects[k].Draw(b)
+	}
+	b.Draw(t)
+	return batch + 1
+}
+
+// cullOccluded removes the visible objects covered by the Occluders in front of them. The objects
+// are visited from the front to the back, so every Occluder is known before the objects behind it.
+func (r *Renderer) cullOccluded(view Rect) {
+	r.occluders.reset(view)
+	visible := len(r.visible)
+	for i := len(r.visible) - 1; i >= 0; i-- {
+		obj := r.visible[i]
+		if r.occluders.hidden(obj.Bounds()) {
+			r.visible[i] = nil
+			visible--
+			continue
+		}
+		if o, ok := obj.(Occluder); ok {
+			r.occluders.add(o.OpaqueBounds())
+		}
+	}
+	if visible == len(r.visible) {
+		return
+	}
+	kept := r.visible[:0]
+	for _, obj := range r.visible {
+		if obj != nil {
+			kept = append(kept, obj)
+		}
+	}
+	for i := len(kept); i < len(r.visible); i++ {
+		r.visible[i] = nil
+	}
+	r.visible = kept
+}
+
+// batch returns the i-th Batch of the frame emptied and set to the Picture. Each run of the objects
+// gets its own Batch, so a Batch is never refilled before the Target is done with it.
+func (r *Renderer) batch(i int, pic Picture) *Batch {
+	if i == len(r.batches) {
+		r.batches = append(r.batches, nil)
+	}
+	b := r.batches[i]
+	if b == nil || b.cont.Picture != pic {
+		b = NewBatch(&TrianglesData{}, pic)
+		r.batches[i] = b
+	}
+	b.Clear()
+	return b
+}
+
+// transformedBounds returns the smallest rectangle containing the rectangle r transformed by the
+// Matrix.
+func transformedBounds(m Matrix, r Rect) Rect {
+	b := Rect{
+		Min: V(math.Inf(1), math.Inf(1)),
+		Max: V(math.Inf(-1), math.Inf(-1)),
+	}
+	for _, corner := range [...]Vec{r.Min, V(r.Max.X, r.Min.Y), r.Max, V(r.Min.X, r.Max.Y)} {
+		u := m.Project(corner)
+		b.Min = V(math.Min(b.Min.X, u.X), math.Min(b.Min.Y, u.Y))
+		b.Max = V(math.Max(b.Max.X, u.X), math.Max(b.Max.Y, u.Y))
+	}
+	return b
+}
@@ -0,0 +1,227 @@
+package pixel_test
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+	"github.com/stretchr/testify/assert"
+)
+
+// logged is a Renderable which records when it's drawn.
+type logged struct {
+	name   string
+	bounds pixel.Rect
+	log    *[]string
+}
+
+func (l *logged) Bounds() pixel.Rect     { return l.bounds }
+func (l *logged) Picture() pixel.Picture { return nil }
+func (l *logged) Draw(t pixel.Target)    { *l.log = append(*l.log, l.name) }
+
+func TestRenderer_Order(t *testing.T) {
+	var log []string
+	obj := func(name string, y float64) *logged {
+		return &logged{name: name, bounds: pixel.R(0, y, 1, y+1), log: &log}
+	}
+
+	r := pixel.NewRenderer()
+	a, b, c, d := obj("a", 0), obj("b", 2), obj("c", 1), obj("d", 0)
+	r.Add(1, a)
+	r.Add(-1, b)
+	r.Add(1, c)
+	r.Add(0, d)
+	r.Add(5, obj("far", 1000))
+	assert.Equal(t, 5, r.Len())
+
+	cam := pixel.NewCamera(pixel.R(0, 0, 10, 10))
+	cam.Pos = pixel.V(5, 5)
+
+	// the layers in their order, the objects in the order of adding, the far one culled
+	assert.Equal(t, 4, r.Draw(raster.NewCanvas(cam.Screen), cam))
+	assert.Equal(t, []string{"b", "d", "a", "c"}, log)
+
+	log = nil
+	r.Sort = pixel.SortByY
+	r.Draw(raster.NewCanvas(cam.Screen), cam)
+	assert.Equal(t, []string{"b", "d", "c", "a"}, log)
+
+	log = nil
+	r.Remove(c)
+	assert.Equal(t, 4, r.Len())
+	r.Draw(raster.NewCanvas(cam.Screen), cam)
+	assert.Equal(t, []string{"b", "d", "a"}, log)
+}
+
+func TestRenderer_Sprites(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 1))
+	pic.Pix[0] = color.RGBA{R: 255, A: 255}
+	pic.Pix[1] = color.RGBA{B: 255, A: 255}
+	red := pixel.NewSprite(pic, pixel.R(0, 0, 1, 1))
+	blue := pixel.NewSprite(pic, pixel.R(1, 0, 2, 1))
+
+	r := pixel.NewRenderer()
+	r.Add(0, &pixel.SpriteRenderable{Sprite: red, Matrix: pixel.IM.Moved(pixel.V(0.5, 0.5))})
+	r.Add(0, &pixel.SpriteRenderable{Sprite: blue, Matrix: pixel.IM.Moved(pixel.V(2.5, 0.5))})
+	r.Add(0, &pixel.SpriteRenderable{Sprite: red, Matrix: pixel.IM.Moved(pixel.V(3.5, 0.5))})
+	r.Add(0, &pixel.SpriteRenderable{Sprite: red, Matrix: pixel.IM.Moved(pixel.V(100, 0.5))})
+
+	cam := pixel.NewCamera(pixel.R(0, 0, 4, 1))
+	cam.Pos = pixel.V(2, 0.5)
+	c := raster.NewCanvas(cam.Screen)
+	c.SetMatrix(cam.Matrix())
+	assert.Equal(t, 3, r.Draw(c, cam))
+
+	want := []pixel.RGBA{pixel.RGB(1, 0, 0), pixel.Alpha(0), pixel.RGB(0, 0, 1), pixel.RGB(1, 0, 0)}
+	for x, w := range want {
+		assert.Equal(t, w, c.Color(pixel.V(float64(x), 0)), "pixel %d", x)
+	}
+}
+
+// opaque is a logged Occluder.
+type opaque struct {
+	logged
+}
+
+func (o *opaque) OpaqueBounds() pixel.Rect { return o.bounds }
+
+func TestRenderer_Occlusion(t *testing.T) {
+	var log []string
+	r := pixel.NewRenderer()
+	r.Occlusion = true
+	r.Add(0, &logged{name: "hidden", bounds: pixel.R(1, 1, 2, 2), log: &log})
+	r.Add(0, &logged{name: "partly", bounds: pixel.R(3, 3, 6, 4), log: &log})
+	r.Add(0, &logged{name: "outside", bounds: pixel.R(-2, 1, 2, 2), log: &log})
+	r.Add(1, &opaque{logged{name: "floor", bounds: pixel.R(0, 0, 5, 5), log: &log}})
+	r.Add(2, &logged{name: "front", bounds: pixel.R(1, 1, 2, 2), log: &log})
+
+	cam := pixel.NewCamera(pixel.R(0, 0, 10, 10))
+	cam.Pos = pixel.V(5, 5)
+
+	// the part of "outside" within the view is covered too
+	assert.Equal(t, 3, r.Draw(raster.NewCanvas(cam.Screen), cam))
+	assert.Equal(t, []string{"partly", "floor", "front"}, log)
+
+	log = nil
+	r.Occlusion = false
+	assert.Equal(t, 5, r.Draw(raster.NewCanvas(cam.Screen), cam))
+}
+
+// drawsOpaque is a logged OpaqueDrawer.
+type drawsOpaque struct {
+	logged
+}
+
+func (o *drawsOpaque) DrawsOpaque() bool { return true }
+
+// depthTarget is a DepthTarget which records the depth of the draws into the log.
+type depthTarget struct {
+	pixel.Target
+	log *[]string
+}
+
+func (dt *depthTarget) SetDepth(depth float64, write bool) {
+	switch {
+	case depth < 0:
+		*dt.log = append(*dt.log, "off")
+	case write:
+		*dt.log = append(*dt.log, fmt.Sprintf("write %.2g", depth))
+	default:
+		*dt.log = append(*dt.log, fmt.Sprintf("test %.2g", depth))
+	}
+}
+
+func (dt *depthTarget) ClearDepth() { *dt.log = append(*dt.log, "clear") }
+
+func TestRenderer_Depth(t *testing.T) {
+	var log []string
+	r := pixel.NewRenderer()
+	r.Occlusion = true
+	r.Add(0, &logged{name: "back", bounds: pixel.R(1, 1, 2, 2), log: &log})
+	r.Add(1, &drawsOpaque{logged{name: "wall", bounds: pixel.R(0, 0, 5, 5), log: &log}})
+	r.Add(2, &logged{name: "glass", bounds: pixel.R(1, 1, 3, 3), log: &log})
+	r.Add(3, &drawsOpaque{logged{name: "front", bounds: pixel.R(2, 2, 4, 4), log: &log}})
+
+	cam := pixel.NewCamera(pixel.R(0, 0, 10, 10))
+	cam.Pos = pixel.V(5, 5)
+	dt := &depthTarget{Target: raster.NewCanvas(cam.Screen), log: &log}
+
+	// the opaque objects from the front, then the rest from the back, the later ones nearer
+	assert.Equal(t, 4, r.Draw(dt, cam))
+	assert.Equal(t, []string{
+		"clear",
+		"write 0.2", "front",
+		"write 0.6", "wall",
+		"test 0.8", "back",
+		"test 0.4", "glass",
+		"off",
+	}, log)
+
+	log = nil
+	r.Occlusion = false
+	r.Draw(dt, cam)
+	assert.Equal(t, []string{"back", "wall", "glass", "front"}, log)
+}
+
+// atlasOpaque is a drawsOpaque drawn with a Picture.
+type atlasOpaque struct {
+	drawsOpaque
+	pic pixel.Picture
+}
+
+func (a *atlasOpaque) Picture() pixel.Picture { return a.pic }
+
+func TestRenderer_DepthBatch(t *testing.T) {
+	var log []string
+	pic := pixel.MakePictureData(pixel.R(0, 0, 1, 1))
+	obj := func(name string) *atlasOpaque {
+		return &atlasOpaque{drawsOpaque{logged{name: name, bounds: pixel.R(1, 1, 2, 2), log: &log}}, pic}
+	}
+
+	r := pixel.NewRenderer()
+	r.Occlusion = true
+	r.Add(0, obj("a"))
+	r.Add(0, obj("b"))
+	r.Add(0, &logged{name: "c", bounds: pixel.R(1, 1, 2, 2), log: &log})
+
+	cam := pixel.NewCamera(pixel.R(0, 0, 10, 10))
+	cam.Pos = pixel.V(5, 5)
+	dt := &depthTarget{Target: raster.NewCanvas(cam.Screen), log: &log}
+
+	// the run of the opaque objects shares a depth, so it's batched from the front
+	assert.Equal(t, 3, r.Draw(dt, cam))
+	assert.Equal(t, []string{"clear", "write 0.67", "b", "a", "test 0.33", "c", "off"}, log)
+}
+
+func TestSpriteRenderable_OpaqueBounds(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 2))
+	sr := &pixel.SpriteRenderable{Sprite: pixel.NewSprite(pic, pic.Bounds()), Matrix: pixel.IM}
+	assert.Equal(t, pixel.Rect{}, sr.OpaqueBounds())
+
+	sr.Opaque = true
+	assert.Equal(t, pixel.R(-1, -1, 1, 1), sr.OpaqueBounds())
+	sr.Matrix = pixel.IM.Rotated(pixel.ZV, math.Pi/2)
+	assert.InDelta(t, 2, sr.OpaqueBounds().W(), 1e-9)
+	sr.Matrix = pixel.IM.Rotated(pixel.ZV, math.Pi/4)
+	assert.Equal(t, pixel.Rect{}, sr.OpaqueBounds())
+
+	sr.Matrix = pixel.IM
+	sr.Mask = pixel.Alpha(0.5)
+	assert.Equal(t, pixel.Rect{}, sr.OpaqueBounds())
+}
+
+func TestSpriteRenderable_DrawsOpaque(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 2))
+	sr := &pixel.SpriteRenderable{Sprite: pixel.NewSprite(pic, pic.Bounds()), Matrix: pixel.IM}
+	assert.False(t, sr.DrawsOpaque())
+
+	sr.Opaque = true
+	sr.Matrix = pixel.IM.Rotated(pixel.ZV, math.Pi/4)
+	assert.True(t, sr.DrawsOpaque(), "rotated Sprites draw opaque pixels too")
+
+	sr.Mask = pixel.Alpha(0.5)
+	assert.False(t, sr.DrawsOpaque())
+}
@@ -0,0 +1,211 @@
+// Package scene implements a stack of game scenes (such as the menu, the level and the pause
+// screen) with transitions between them.
+//
+// The package is independent of the rendering backend, the transitions draw the scenes onto any
+// Canvas, such as pixelgl.Canvas or raster.Canvas.
+package scene
+
+import (
+	"image/color"
+
+	"github.com/faiface/pixel"
+)
+
+// Scene is one screen of a game with its own logic and drawing.
+//
+// Enter is called when the Scene becomes the top of the Manager's stack, Exit when it stops being
+// the top, either because another Scene was pushed over it, or because it was removed. A Scene
+// covered by another one stays in the stack and is entered again when the Scene over it is popped.
+type Scene interface {
+	Enter()
+	Exit()
+	Update(dt float64)
+	Draw(t pixel.Target)
+}
+
+// Canvas is an off-screen Target, that the scenes are drawn onto during a Transition.
+// pixelgl.Canvas and raster.Canvas are Canvases.
+type Canvas interface {
+	pixel.Target
+	pixel.Picture
+	Clear(color color.Color)
+	DrawColorMask(t pixel.Target, matrix pixel.Matrix, mask color.Color)
+}
+
+// Manager is a stack of Scenes. Only the top Scene is updated and drawn, except during a
+// Transition, when the previous top Scene is drawn as well.
+//
+//   scenes := scene.NewManager(win.Bounds(), func(r pixel.Rect) scene.Canvas {
+//       return pixelgl.NewCanvas(r)
+//   })
+//   scenes.Push(menu, nil)
+//   for !win.Closed() {
+//       scenes.Update(dt)
+//       scenes.Draw(win)
+//       win.Update()
+//   }
+//
+//   // in the menu
+//   scenes.Replace(level, scene.CrossFade(0.5))
+type Manager struct {
+	bounds    pixel.Rect
+	newCanvas func(bounds pixel.Rect) Canvas
+	from, to  Canvas
+
+	stack []Scene
+
+	// the Scene left by the running Transition, which is still drawn
+	prev     Scene
+	trans    Transition
+	progress float64
+}
+
+// NewManager creates a new empty Manager of Scenes drawn into the bounds. The newCanvas function
+// creates the Canvases the Scenes are drawn onto during the Transitions.
+func NewManager(bounds pixel.Rect, newCanvas func(bounds pixel.Rect) Canvas) *Manager {
+	return &Manager{
+		bounds:    bounds,
+		newCanvas: newCanvas,
+	}
+}
+
+// SetBounds sets the bounds the Scenes are drawn into, e.g. after the Window was resized.
+func (m *Manager) SetBounds(bounds pixel.Rect) {
+	m.bounds = bounds
+	m.from, m.to = nil, nil
+}
+
+// Bounds returns the bounds the Scenes are drawn into.
+func (m *Manager) Bounds() pixel.Rect {
+	return m.bounds
+}
+
+// Current returns the top Scene of the stack, or nil if it's empty.
+func (m *Manager) Current() Scene {
+	if len(m.stack) == 0 {
+		return nil
+	}
+	return m.stack[len(m.stack)-1]
+}
+
+// Len returns the number of Scenes in the stack.
+func (m *Manager) Len() int {
+	return len(m.stack)
+}
+
+// Transitioning reports whether a Transition is running.
+func (m *Manager) Transitioning() bool {
+	return m.trans != nil
+}
+
+// Push puts the Scene on the top of the stack with the Transition, or immediately if it's nil.
+func (m *Manager) Push(s Scene, tr Transition) {
+	m.change(tr, func() {
+		m.stack = append(m.stack, s)
+	})
+}
+
+// Pop removes the top Scene from the stack with the Transition, or immediately if it's nil. The
+// Scene under it becomes the top again. Pop does nothing if the stack is empty.
+func (m *Manager) Pop(tr Transition) {
+	if len(m.stack) == 0 {
+		return
+	}
+	m.change(tr, func() {
+		m.stack[len(m.stack)-1] = nil
+		m.stack = m.stack[:len(m.stack)-1]
+	})
+}
+
+// Replace replaces the top Scene of the stack (or pushes it, if the stack is empty) with the
+// Transition, or immediately if it's nil.
+func (m *Manager) Replace(s Scene, tr Transition) {
+	m.change(tr, func() {
+		if len(m.stack) > 0 {
+			m.stack = m.stack[:len(m.stack)-1]
+		}
+		m.stack = append(m.stack, s)
+	})
+}
+
+// change changes the stack and starts the Transition from the old top Scene to the new one.
+func (m *Manager) change(tr Transition, modify func()) {
+	// a running transition is finished first
+	m.finish()
+
+	old := m.Current()
+	modify()
+	cur := m.Current()
+	if old == cur {
+		return
+	}
+
+	if tr == nil || tr.Duration() <= 0 {
+		if old != nil {
+			old.Exit()
+		}
+		if cur != nil {
+			cur.Enter()
+		}
+		return
+	}
+
+	m.prev, m.trans, m.progress = old, tr, 0
+	if cur != nil {
+		cur.Enter()
+	}
+}
+
+// finish ends the running Transition.
+func (m *Manager) finish() {
+	if m.trans == nil {
+		return
+	}
+	prev := m.prev
+	m.prev, m.trans, m.progress = nil, nil, 0
+	if prev != nil {
+		prev.Exit()
+	}
+}
+
+// Update updates the top Scene by the time dt in seconds and advances the running Transition.
+// The Scene being left by the Transition is not updated anymore.
+func (m *Manager) Update(dt float64) {
+	if m.trans != nil {
+		m.progress += dt / m.trans.Duration()
+		if m.progress >= 1 {
+			m.finish()
+		}
+	}
+	if cur := m.Current(); cur != nil {
+		cur.Update(dt)
+	}
+}
+
+// Draw draws the top Scene onto the Target. During a Transition, both Scenes are drawn onto
+// Canvases first and the Transition draws them.
+func (m *Manager) Draw(t pixel.Target) {
+	if m.trans == nil {
+		if cur := m.Current(); cur != nil {
+			cur.Draw(t)
+		}
+		return
+	}
+
+	if m.from == nil {
+		m.from, m.to = m.newCanvas(m.bounds), m.newCanvas(m.bounds)
+	}
+	for _, sc := range []struct {
+		canvas Canvas
+		scene  Scene
+	}{
+		{m.from, m.prev},
+		{m.to, m.Current()},
+	} {
+		sc.canvas.Clear(color.Transparent)
+		if sc.scene != nil {
+			sc.scene.Draw(sc.canvas)
+		}
+	}
+	m.trans.Draw(t, m.from, m.to, m.progress)
+}
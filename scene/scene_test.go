@@ -0,0 +1,149 @@
+package scene_test
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+	"github.com/faiface/pixel/scene"
+)
+
+// testScene fills the Target with its color and records the calls.
+type testScene struct {
+	name  string
+	color pixel.RGBA
+	log   *[]string
+	quad  pixel.Drawer
+}
+
+func newTestScene(name string, c pixel.RGBA, log *[]string) *testScene {
+	td := pixel.MakeTrianglesData(6)
+	for i, v := range []pixel.Vec{
+		pixel.V(0, 0), pixel.V(4, 0), pixel.V(4, 4),
+		pixel.V(0, 0), pixel.V(4, 4), pixel.V(0, 4),
+	} {
+		(*td)[i].Position = v
+		(*td)[i].Color = c
+	}
+	return &testScene{name: name, color: c, log: log, quad: pixel.Drawer{Triangles: td}}
+}
+
+func (s *testScene) Enter()            { *s.log = append(*s.log, "enter "+s.name) }
+func (s *testScene) Exit()             { *s.log = append(*s.log, "exit "+s.name) }
+func (s *testScene) Update(dt float64) { *s.log = append(*s.log, "update "+s.name) }
+func (s *testScene) Draw(t pixel.Target) {
+	s.quad.Draw(t)
+}
+
+func newManager() *scene.Manager {
+	return scene.NewManager(pixel.R(0, 0, 4, 4), func(r pixel.Rect) scene.Canvas {
+		return raster.NewCanvas(r)
+	})
+}
+
+func TestManagerStack(t *testing.T) {
+	var log []string
+	a := newTestScene("a", pixel.RGB(1, 0, 0), &log)
+	b := newTestScene("b", pixel.RGB(0, 1, 0), &log)
+	c := newTestScene("c", pixel.RGB(0, 0, 1), &log)
+
+	m := newManager()
+	m.Push(a, nil)
+	m.Push(b, nil)
+	m.Update(1)
+	m.Replace(c, nil)
+	m.Pop(nil)
+	m.Update(1)
+	m.Pop(nil)
+	m.Pop(nil)
+
+	want := []string{
+		"enter a",
+		"exit a", "enter b",
+		"update b",
+		"exit b", "enter c",
+		"exit c", "enter a",
+		"update a",
+		"exit a",
+	}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("calls %v, want %v", log, want)
+	}
+	if m.Len() != 0 || m.Current() != nil {
+		t.Errorf("stack of %d scenes left", m.Len())
+	}
+}
+
+func TestManagerCrossFade(t *testing.T) {
+	var log []string
+	a := newTestScene("a", pixel.RGB(1, 0, 0), &log)
+	b := newTestScene("b", pixel.RGB(0, 0, 1), &log)
+
+	m := newManager()
+	m.Push(a, nil)
+	m.Replace(b, scene.CrossFade(1))
+
+	if !m.Transitioning() || m.Current() != b {
+		t.Fatalf("transition not started")
+	}
+	m.Update(0.5)
+
+	dst := raster.NewCanvas(pixel.R(0, 0, 4, 4))
+	m.Draw(dst)
+	got := dst.Color(pixel.V(1, 1))
+	want := color.RGBA{R: 128, B: 128, A: 255}
+	if q := color.RGBAModel.Convert(got).(color.RGBA); absDiff(q.R, want.R) > 1 || q.G != 0 || absDiff(q.B, want.B) > 1 || q.A != 255 {
+		t.Errorf("cross-faded color %v, want %v", q, want)
+	}
+
+	m.Update(0.5)
+	if m.Transitioning() {
+		t.Errorf("transition not finished")
+	}
+	m.Draw(dst)
+	if got := dst.Color(pixel.V(1, 1)); got != pixel.RGB(0, 0, 1) {
+		t.Errorf("color after transition %v, want blue", got)
+	}
+
+	want2 := []string{"enter a", "enter b", "update b", "exit a", "update b"}
+	if !reflect.DeepEqual(log, want2) {
+		t.Errorf("calls %v, want %v", log, want2)
+	}
+}
+
+func TestFadeThrough(t *testing.T) {
+	var log []string
+	a := newTestScene("a", pixel.RGB(1, 0, 0), &log)
+	b := newTestScene("b", pixel.RGB(0, 0, 1), &log)
+
+	m := newManager()
+	m.Push(a, nil)
+	m.Push(b, scene.FadeThrough(color.Black, 2))
+
+	dst := raster.NewCanvas(pixel.R(0, 0, 4, 4))
+	for _, tt := range []struct {
+		dt   float64
+		want pixel.RGBA
+	}{
+		{0, pixel.RGB(1, 0, 0)},
+		{1, pixel.RGB(0, 0, 0)},
+		{0.5, pixel.RGB(0, 0, 0.5)},
+	} {
+		m.Update(tt.dt)
+		m.Draw(dst)
+		got := color.RGBAModel.Convert(dst.Color(pixel.V(1, 1))).(color.RGBA)
+		want := color.RGBAModel.Convert(tt.want).(color.RGBA)
+		if absDiff(got.R, want.R) > 1 || absDiff(got.G, want.G) > 1 || absDiff(got.B, want.B) > 1 {
+			t.Errorf("color %v, want %v", got, want)
+		}
+	}
+}
+
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
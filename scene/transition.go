@@ -0,0 +1,80 @@
+package scene
+
+import (
+	"image/color"
+
+	"github.com/faiface/pixel"
+)
+
+// Transition draws the change from one Scene to another, both already drawn onto the Canvases.
+//
+// Draw is called every frame with the progress of the Transition, which goes from 0 to 1 over its
+// Duration in seconds.
+type Transition interface {
+	Duration() float64
+	Draw(t pixel.Target, from, to Canvas, progress float64)
+}
+
+// CrossFade returns a Transition which fades the new Scene in over the old one.
+func CrossFade(duration float64) Transition {
+	return crossFade(duration)
+}
+
+type crossFade float64
+
+func (cf crossFade) Duration() float64 {
+	return float64(cf)
+}
+
+func (cf crossFade) Draw(t pixel.Target, from, to Canvas, progress float64) {
+	drawCanvas(t, from, pixel.Alpha(1))
+	drawCanvas(t, to, pixel.Alpha(progress))
+}
+
+// FadeThrough returns a Transition which fades the old Scene out to the color in the first half of
+// the duration and the new Scene in from it in the second half, e.g. through black.
+func FadeThrough(c color.Color, duration float64) Transition {
+	return &fadeThrough{
+		color:    pixel.ToRGBA(c),
+		duration: duration,
+	}
+}
+
+type fadeThrough struct {
+	color    pixel.RGBA
+	duration float64
+	quad     pixel.Drawer
+}
+
+func (ft *fadeThrough) Duration() float64 {
+	return ft.duration
+}
+
+func (ft *fadeThrough) Draw(t pixel.Target, from, to Canvas, progress float64) {
+	c, cover := from, progress*2
+	if progress >= 0.5 {
+		c, cover = to, 2-progress*2
+	}
+	drawCanvas(t, c, pixel.Alpha(1))
+
+	// the color covers the Scene
+	r := c.Bounds()
+	if ft.quad.Triangles == nil {
+		ft.quad.Triangles = pixel.MakeTrianglesData(6)
+	}
+	td := ft.quad.Triangles.(*pixel.TrianglesData)
+	for i, v := range []pixel.Vec{
+		r.Min, pixel.V(r.Max.X, r.Min.Y), r.Max,
+		r.Min, r.Max, pixel.V(r.Min.X, r.Max.Y),
+	} {
+		(*td)[i].Position = v
+		(*td)[i].Color = ft.color.Scaled(cover)
+	}
+	ft.quad.Dirty()
+	ft.quad.Draw(t)
+}
+
+// drawCanvas draws the Canvas onto the Target in its own bounds.
+func drawCanvas(t pixel.Target, c Canvas, mask pixel.RGBA) {
+	c.DrawColorMask(t, pixel.IM.Moved(c.Bounds().Center()), mask)
+}
@@ -1,9 +1,11 @@
 package text
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"image/draw"
+	"math"
 	"sort"
 	"unicode"
 
@@ -30,6 +32,40 @@ type Atlas struct {
 	ascent     float64
 	descent    float64
 	lineHeight float64
+	spread     float64
+	colored    map[rune]bool
+	dyn        *dynamicAtlas
+
+	// variants are the glyphs pre-drawn at each of the subpixel positions
+	variants map[rune][]Glyph
+	subpixel int
+	hinting  font.Hinting
+}
+
+// AtlasOptions are the options of placing the glyphs of an Atlas, which tune small text between
+// crisp glyphs on the pixel grid and smooth proportional spacing.
+//
+// The shapes of the glyphs are hinted by the font face, such as by the Hinting of the
+// truetype.Options or the opentype.FaceOptions, set it together with these options.
+type AtlasOptions struct {
+	// Hinting rounds the metrics of the Atlas to whole pixels. HintingVertical rounds the ascent,
+	// the descent and the line height, so that the baselines of all lines sit on the pixel grid.
+	// HintingFull rounds the advances of the glyphs and the kerning too, which keeps the glyphs
+	// on the pixel grid, but spaces them less evenly. Defaults to font.HintingNone, which keeps
+	// the metrics of the face.
+	Hinting font.Hinting
+
+	// Subpixel is the number of horizontal positions within a pixel, at which the glyphs are
+	// drawn. Each glyph is pre-drawn at each of the positions and the glyphs are snapped to the
+	// position closest to the dot horizontally and to a whole pixel vertically, so they're
+	// crisp, but the spacing stays proportional. One snaps the glyphs to whole pixels. Zero (the
+	// default) doesn't snap the glyphs at all, they're drawn right at the dot.
+	//
+	// The positions are in the units of the Atlas, so they match the pixels only if the Text is
+	// drawn unscaled at whole pixel coordinates. Only the faces which draw the glyphs at
+	// fractional dots benefit from more than one position, such as the faces of the truetype and
+	// opentype packages.
+	Subpixel int
 }
 
 // NewAtlas creates a new Atlas containing glyphs of the union of the given sets of runes (plus
@@ -39,9 +75,35 @@ type Atlas struct {
 //
 // Do not destroy or close the font.Face after creating the Atlas. Atlas still uses it.
 func NewAtlas(face font.Face, runeSets ...[]rune) *Atlas {
+	return newAtlas(face, AtlasOptions{}, 0, nil, runeSets)
+}
+
+// NewAtlasWithOptions creates a new Atlas like NewAtlas, with the glyphs placed according to the
+// options.
+//
+// Here we create an Atlas for small UI text, which is hinted by the face and drawn at 4 positions
+// within a pixel:
+//   face := truetype.NewFace(ttf, &truetype.Options{
+//       Size:    9,
+//       Hinting: font.HintingFull,
+//   })
+//   atlas := text.NewAtlasWithOptions(face, text.AtlasOptions{
+//       Hinting:  font.HintingVertical,
+//       Subpixel: 4,
+//   }, text.ASCII)
+func NewAtlasWithOptions(face font.Face, opts AtlasOptions, runeSets ...[]rune) *Atlas {
+	if opts.Subpixel < 0 {
+		panic(errors.New("NewAtlasWithOptions: negative Subpixel"))
+	}
+	return newAtlas(face, opts, 0, nil, runeSets)
+}
+
+// newAtlas creates a new Atlas, which is a signed distance field reaching the spread out of the
+// glyphs if the spread isn't zero. The images are the colored glyphs of their runes.
+func newAtlas(face font.Face, opts AtlasOptions, spread int, images map[rune]*image.RGBA, runeSets [][]rune) *Atlas {
 	seen := make(map[rune]bool)
 	runes := []rune{unicode.ReplacementChar}
-	for _, set := range runeSets {
+	for _, set := range append(runeSets, imageRunes(images)) {
 		for _, r := range set {
 			if !seen[r] {
 				runes = append(runes, r)
@@ -50,7 +112,9 @@ func NewAtlas(face font.Face, runeSets ...[]rune) *Atlas {
 		}
 	}
 
-	fixedMapping, fixedBounds := makeSquareMapping(face, runes, fixed.I(2))
+	// the distance fields of two glyphs must not reach into each other
+	fixedMapping, fixedBounds := makeSquareMapping(face, images, runes, opts.Subpixel, fixed.I(2+2*spread))
+	fixedBounds = expand(fixedBounds, spread)
 
 	atlasImg := image.NewRGBA(image.Rect(
 		fixedBounds.Min.X.Floor(),
@@ -59,9 +123,19 @@ func NewAtlas(face font.Face, runeSets ...[]rune) *Atlas {
 		fixedBounds.Max.Y.Ceil(),
 	))
 
-	for r, fg := range fixedMapping {
-		dr, mask, maskp, _, _ := face.Glyph(fg.dot, r)
-		draw.Draw(atlasImg, dr, mask, maskp, draw.Src)
+	for r, fgs := range fixedMapping {
+		for _, fg := range fgs {
+			if img, ok := images[r]; ok {
+				min := image.Pt(fg.frame.Min.X.Floor(), fg.frame.Min.Y.Floor())
+				draw.Draw(atlasImg, img.Bounds().Sub(img.Bounds().Min).Add(min), img, img.Bounds().Min, draw.Src)
+				continue
+			}
+			dr, mask, maskp, _, _ := face.Glyph(fg.dot, r)
+			draw.Draw(atlasImg, dr, mask, maskp, draw.Src)
+		}
+	}
+	if spread > 0 {
+		distanceField(atlasImg, spread)
 	}
 
 	bounds := pixel.R(
@@ -72,53 +146,104 @@ func NewAtlas(face font.Face, runeSets ...[]rune) *Atlas {
 	)
 
 	mapping := make(map[rune]Glyph)
-	for r, fg := range fixedMapping {
-		mapping[r] = Glyph{
-			Dot: pixel.V(
-				i2f(fg.dot.X),
-				bounds.Max.Y-(i2f(fg.dot.Y)-bounds.Min.Y),
-			),
-			Frame: pixel.R(
-				i2f(fg.frame.Min.X),
-				bounds.Max.Y-(i2f(fg.frame.Min.Y)-bounds.Min.Y),
-				i2f(fg.frame.Max.X),
-				bounds.Max.Y-(i2f(fg.frame.Max.Y)-bounds.Min.Y),
-			).Norm(),
-			Advance: i2f(fg.advance),
+	variants := make(map[rune][]Glyph)
+	for r, fgs := range fixedMapping {
+		glyphs := make([]Glyph, len(fgs))
+		for i, fg := range fgs {
+			if !fg.frame.Empty() {
+				fg.frame = expand(fg.frame, spread)
+			}
+			if opts.Hinting == font.HintingFull {
+				fg.advance = fixed.I(fg.advance.Round())
+			}
+			glyphs[i] = Glyph{
+				Dot: pixel.V(
+					i2f(fg.dot.X),
+					bounds.Max.Y-(i2f(fg.dot.Y)-bounds.Min.Y),
+				),
+				Frame: pixel.R(
+					i2f(fg.frame.Min.X),
+					bounds.Max.Y-(i2f(fg.frame.Min.Y)-bounds.Min.Y),
+					i2f(fg.frame.Max.X),
+					bounds.Max.Y-(i2f(fg.frame.Max.Y)-bounds.Min.Y),
+				).Norm(),
+				Advance: i2f(fg.advance),
+			}
+		}
+		mapping[r] = glyphs[0]
+		if len(glyphs) > 1 {
+			variants[r] = glyphs
 		}
 	}
 
+	colored := make(map[rune]bool, len(images))
+	for r := range images {
+		colored[r] = true
+	}
+	if len(images) > 0 {
+		for _, r := range emojiModifiers {
+			if _, ok := mapping[r]; !ok {
+				mapping[r] = Glyph{}
+			}
+		}
+	}
+
+	metrics := face.Metrics()
+	if opts.Hinting != font.HintingNone {
+		metrics.Ascent = fixed.I(metrics.Ascent.Ceil())
+		metrics.Descent = fixed.I(metrics.Descent.Ceil())
+		metrics.Height = fixed.I(metrics.Height.Round())
+	}
+
 	return &Atlas{
 		face:       face,
 		pic:        pixel.PictureDataFromImage(atlasImg),
 		mapping:    mapping,
-		ascent:     i2f(face.Metrics().Ascent),
-		descent:    i2f(face.Metrics().Descent),
-		lineHeight: i2f(face.Metrics().Height),
+		ascent:     i2f(metrics.Ascent),
+		descent:    i2f(metrics.Descent),
+		lineHeight: i2f(metrics.Height),
+		spread:     float64(spread),
+		colored:    colored,
+		variants:   variants,
+		subpixel:   opts.Subpixel,
+		hinting:    opts.Hinting,
 	}
 }
 
 // Picture returns the underlying Picture containing an arrangement of all the glyphs contained
 // within the Atlas.
+//
+// The Picture of a dynamic Atlas changes when new glyphs are added to it.
 func (a *Atlas) Picture() pixel.Picture {
+	if a.dyn != nil {
+		a.pic = a.dyn.picture(a.pic)
+	}
 	return a.pic
 }
 
-// Contains reports wheter r in contained within the Atlas.
+// Contains reports wheter r in contained within the Atlas. A dynamic Atlas contains all runes of its
+// font face.
 func (a *Atlas) Contains(r rune) bool {
-	_, ok := a.mapping[r]
-	return ok
+	return a.add(r)
 }
 
 // Glyph returns the description of r within the Atlas.
 func (a *Atlas) Glyph(r rune) Glyph {
+	a.add(r)
 	return a.mapping[r]
 }
 
 // Kern returns the kerning distance between runes r0 and r1. Positive distance means that the
-// glyphs should be further apart.
+// glyphs should be further apart. The distance comes from the kerning of the font face, such as the
+// kern or GPOS tables of an OpenType font, if the face supports them.
+//
+// The kerning is rounded to whole pixels if the Atlas was created with font.HintingFull.
 func (a *Atlas) Kern(r0, r1 rune) float64 {
-	return i2f(a.face.Kern(r0, r1))
+	kern := a.face.Kern(r0, r1)
+	if a.hinting == font.HintingFull {
+		kern = fixed.I(kern.Round())
+	}
+	return i2f(kern)
 }
 
 // Ascent returns the distance from the top of the line to the baseline.
@@ -140,6 +265,10 @@ func (a *Atlas) LineHeight() float64 {
 //
 // Rect is a rectangle where the glyph should be positioned. Frame is the glyph frame inside the
 // Atlas's Picture. NewDot is the new position of the dot.
+//
+// The glyph is kerned against prevR, which is the rune drawn right before it. Negative prevR, such
+// as -1, means that there's no previous rune, so there's no kerning. The glyph is snapped to the
+// subpixel positions, if the Atlas has them (see AtlasOptions).
 func (a *Atlas) DrawRune(prevR, r rune, dot pixel.Vec) (rect, frame, bounds pixel.Rect, newDot pixel.Vec) {
 	if !a.Contains(r) {
 		r = unicode.ReplacementChar
@@ -147,7 +276,7 @@ func (a *Atlas) DrawRune(prevR, r rune, dot pixel.Vec) (rect, frame, bounds pixe
 	if !a.Contains(unicode.ReplacementChar) {
 		return pixel.Rect{}, pixel.Rect{}, pixel.Rect{}, dot
 	}
-	if !a.Contains(prevR) {
+	if prevR >= 0 && !a.Contains(prevR) {
 		prevR = unicode.ReplacementChar
 	}
 
@@ -156,15 +285,23 @@ func (a *Atlas) DrawRune(prevR, r rune, dot pixel.Vec) (rect, frame, bounds pixe
 	}
 
 	glyph := a.Glyph(r)
+	pos := dot
+	if a.subpixel > 0 {
+		var sub int
+		pos, sub = a.snap(dot)
+		if variants, ok := a.variants[r]; ok {
+			glyph = variants[sub]
+		}
+	}
 
-	rect = glyph.Frame.Moved(dot.Sub(glyph.Dot))
+	rect = glyph.Frame.Moved(pos.Sub(glyph.Dot))
 	bounds = rect
 
 	if bounds.W()*bounds.H() != 0 {
 		bounds = pixel.R(
-			bounds.Min.X,
+			bounds.Min.X+a.spread,
 			dot.Y-a.Descent(),
-			bounds.Max.X,
+			bounds.Max.X-a.spread,
 			dot.Y+a.Ascent(),
 		)
 	}
@@ -174,6 +311,22 @@ func (a *Atlas) DrawRune(prevR, r rune, dot pixel.Vec) (rect, frame, bounds pixe
 	return rect, glyph.Frame, bounds, dot
 }
 
+// snap snaps the dot to the closest subpixel position horizontally and to a whole pixel
+// vertically. It returns the snapped dot and the index of the subpixel position.
+func (a *Atlas) snap(dot pixel.Vec) (snapped pixel.Vec, sub int) {
+	x := math.Floor(dot.X)
+	sub = int(math.Round((dot.X - x) * float64(a.subpixel)))
+	if sub == a.subpixel {
+		x, sub = x+1, 0
+	}
+	return pixel.V(x+i2f(subpixelOffset(sub, a.subpixel)), math.Round(dot.Y)), sub
+}
+
+// subpixelOffset returns the offset of the subpixel position i out of n.
+func subpixelOffset(i, n int) fixed.Int26_6 {
+	return fixed.Int26_6(i * 64 / n)
+}
+
 type fixedGlyph struct {
 	dot     fixed.Point26_6
 	frame   fixed.Rectangle26_6
@@ -182,67 +335,87 @@ type fixedGlyph struct {
 
 // makeSquareMapping finds an optimal glyph arrangement of the given runes, so that their common
 // bounding box is as square as possible.
-func makeSquareMapping(face font.Face, runes []rune, padding fixed.Int26_6) (map[rune]fixedGlyph, fixed.Rectangle26_6) {
+func makeSquareMapping(face font.Face, images map[rune]*image.RGBA, runes []rune, subpixel int, padding fixed.Int26_6) (map[rune][]fixedGlyph, fixed.Rectangle26_6) {
 	width := sort.Search(int(fixed.I(1024*1024)), func(i int) bool {
 		width := fixed.Int26_6(i)
-		_, bounds := makeMapping(face, runes, padding, width)
+		_, bounds := makeMapping(face, images, runes, subpixel, padding, width)
 		return bounds.Max.X-bounds.Min.X >= bounds.Max.Y-bounds.Min.Y
 	})
-	return makeMapping(face, runes, padding, fixed.Int26_6(width))
+	return makeMapping(face, images, runes, subpixel, padding, fixed.Int26_6(width))
 }
 
 // makeMapping arranges glyphs of the given runes into rows in such a way, that no glyph is located
 // fully to the right of the specified width. Specifically, it places glyphs in a row one by one and
 // once it reaches the specified width, it starts a new row.
-func makeMapping(face font.Face, runes []rune, padding, width fixed.Int26_6) (map[rune]fixedGlyph, fixed.Rectangle26_6) {
-	mapping := make(map[rune]fixedGlyph)
+//
+// If subpixel is more than one, each glyph (except for the images) is placed that many times, with
+// the dot at each of the subpixel positions.
+func makeMapping(face font.Face, images map[rune]*image.RGBA, runes []rune, subpixel int, padding, width fixed.Int26_6) (map[rune][]fixedGlyph, fixed.Rectangle26_6) {
+	mapping := make(map[rune][]fixedGlyph)
 	bounds := fixed.Rectangle26_6{}
 
 	dot := fixed.P(0, 0)
 
 	for _, r := range runes {
-		b, advance, ok := face.GlyphBounds(r)
+		b, advance, ok := glyphBounds(face, images, r)
 		if !ok {
 			fmt.Println(r)
 			continue
 		}
 
-		// this is important for drawing, artifacts arise otherwise
-		frame := fixed.Rectangle26_6{
-			Min: fixed.P(b.Min.X.Floor(), b.Min.Y.Floor()),
-			Max: fixed.P(b.Max.X.Ceil(), b.Max.Y.Ceil()),
+		positions := 1
+		if _, ok := images[r]; !ok && subpixel > 1 {
+			positions = subpixel
 		}
 
-		dot.X -= frame.Min.X
-		frame = frame.Add(dot)
+		for i := 0; i < positions; i++ {
+			offset := fixed.Point26_6{X: subpixelOffset(i, positions)}
 
-		mapping[r] = fixedGlyph{
-			dot:     dot,
-			frame:   frame,
-			advance: advance,
-		}
-		bounds = bounds.Union(frame)
+			// this is important for drawing, artifacts arise otherwise
+			frame := fixed.Rectangle26_6{
+				Min: fixed.P((b.Min.X + offset.X).Floor(), b.Min.Y.Floor()),
+				Max: fixed.P((b.Max.X + offset.X).Ceil(), b.Max.Y.Ceil()),
+			}
 
-		dot.X = frame.Max.X
+			dot.X -= frame.Min.X
+			frame = frame.Add(dot)
 
-		// padding + align to integer
-		dot.X += padding
-		dot.X = fixed.I(dot.X.Ceil())
+			mapping[r] = append(mapping[r], fixedGlyph{
+				dot:     dot.Add(offset),
+				frame:   frame,
+				advance: advance,
+			})
+			bounds = bounds.Union(frame)
 
-		// width exceeded, new row
-		if frame.Max.X >= width {
-			dot.X = 0
-			dot.Y += face.Metrics().Ascent + face.Metrics().Descent
+			dot.X = frame.Max.X
 
 			// padding + align to integer
-			dot.Y += padding
-			dot.Y = fixed.I(dot.Y.Ceil())
+			dot.X += padding
+			dot.X = fixed.I(dot.X.Ceil())
+
+			// width exceeded, new row
+			if frame.Max.X >= width {
+				dot.X = 0
+				dot.Y += face.Metrics().Ascent + face.Metrics().Descent
+
+				// padding + align to integer
+				dot.Y += padding
+				dot.Y = fixed.I(dot.Y.Ceil())
+			}
 		}
 	}
 
 	return mapping, bounds
 }
 
+// expand expands the rectangle by n in each direction.
+func expand(r fixed.Rectangle26_6, n int) fixed.Rectangle26_6 {
+	return fixed.Rectangle26_6{
+		Min: r.Min.Sub(fixed.P(n, n)),
+		Max: r.Max.Add(fixed.P(n, n)),
+	}
+}
+
 func i2f(i fixed.Int26_6) float64 {
 	return float64(i) / (1 << 6)
 }
@@ -1,9 +1,16 @@
 package text_test
 
 import (
+	"math"
 	"testing"
 
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/text"
+	"github.com/golang/freetype/truetype"
 )
 
 func TestAtlas7x13(t *testing.T) {
@@ -22,3 +29,97 @@ func TestAtlas7x13(t *testing.T) {
 		}
 	}
 }
+
+func goRegularFace(t *testing.T, size float64, hinting font.Hinting) font.Face {
+	ttf, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return truetype.NewFace(ttf, &truetype.Options{Size: size, Hinting: hinting})
+}
+
+func TestAtlasOptionsDefault(t *testing.T) {
+	face := goRegularFace(t, 11.5, font.HintingNone)
+	plain := text.NewAtlas(face, text.ASCII)
+	atlas := text.NewAtlasWithOptions(face, text.AtlasOptions{}, text.ASCII)
+
+	if atlas.Ascent() != plain.Ascent() || atlas.LineHeight() != plain.LineHeight() {
+		t.Errorf("metrics changed by zero options")
+	}
+	dot := pixel.V(10.3, 5.7)
+	for _, r := range "Hello" {
+		got, _, _, _ := atlas.DrawRune(-1, r, dot)
+		want, _, _, _ := plain.DrawRune(-1, r, dot)
+		if got.Size() != want.Size() || got.Min.Sub(dot) != want.Min.Sub(dot) {
+			t.Errorf("%q drawn into %v, want %v", r, got, want)
+		}
+	}
+}
+
+func TestAtlasSubpixel(t *testing.T) {
+	face := goRegularFace(t, 11.5, font.HintingNone)
+	for _, subpixel := range []int{1, 4} {
+		atlas := text.NewAtlasWithOptions(face, text.AtlasOptions{Subpixel: subpixel}, text.ASCII)
+
+		for _, x := range []float64{0, 0.2, 0.3, 0.5, 0.9, 7.13} {
+			dot := pixel.V(x, 0.4)
+			rect, frame, _, _ := atlas.DrawRune(-1, 'a', dot)
+
+			// the glyph is snapped to the pixels, since the frames are whole pixels
+			if rect.Min != pixel.V(math.Round(rect.Min.X), math.Round(rect.Min.Y)) {
+				t.Errorf("subpixel %d, dot %v: 'a' drawn into %v, not snapped to pixels", subpixel, dot, rect)
+			}
+			// and it's off by less than a subpixel position from the dot
+			want, _, _, _ := text.NewAtlas(face, []rune{'a'}).DrawRune(-1, 'a', dot)
+			if math.Abs(rect.Max.X-want.Max.X) > 1 || math.Abs(rect.Max.Y-want.Max.Y) > 0.5 {
+				t.Errorf("subpixel %d, dot %v: 'a' drawn into %v, too far from %v", subpixel, dot, rect, want)
+			}
+			if frame.Size() != rect.Size() {
+				t.Errorf("subpixel %d, dot %v: frame %v doesn't match %v", subpixel, dot, frame, rect)
+			}
+		}
+
+		// each subpixel position has its own glyph
+		frames := make(map[pixel.Rect]bool)
+		for i := 0; i < subpixel; i++ {
+			_, frame, _, _ := atlas.DrawRune(-1, 'a', pixel.V(float64(i)/float64(subpixel), 0))
+			frames[frame] = true
+		}
+		if len(frames) != subpixel {
+			t.Errorf("subpixel %d: %d different glyphs", subpixel, len(frames))
+		}
+	}
+}
+
+func TestAtlasHinting(t *testing.T) {
+	face := goRegularFace(t, 11.5, font.HintingNone)
+	whole := func(x float64) bool { return x == math.Round(x) }
+
+	vertical := text.NewAtlasWithOptions(face, text.AtlasOptions{Hinting: font.HintingVertical}, text.ASCII)
+	if !whole(vertical.Ascent()) || !whole(vertical.Descent()) || !whole(vertical.LineHeight()) {
+		t.Errorf("vertical hinting: ascent %v, descent %v and line height %v, want whole pixels",
+			vertical.Ascent(), vertical.Descent(), vertical.LineHeight())
+	}
+	if plain := text.NewAtlas(face, text.ASCII); vertical.Glyph('a').Advance != plain.Glyph('a').Advance {
+		t.Errorf("vertical hinting: advance %v, want %v", vertical.Glyph('a').Advance, plain.Glyph('a').Advance)
+	}
+
+	full := text.NewAtlasWithOptions(face, text.AtlasOptions{Hinting: font.HintingFull}, text.ASCII)
+	for _, r := range text.ASCII {
+		if adv := full.Glyph(r).Advance; !whole(adv) {
+			t.Errorf("full hinting: advance of %q %v, want whole pixels", r, adv)
+		}
+	}
+	if !whole(full.Kern('A', 'V')) {
+		t.Errorf("full hinting: kerning %v, want whole pixels", full.Kern('A', 'V'))
+	}
+}
+
+func TestAtlasOptionsInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic with negative Subpixel")
+		}
+	}()
+	text.NewAtlasWithOptions(basicfont.Face7x13, text.AtlasOptions{Subpixel: -1}, text.ASCII)
+}
@@ -0,0 +1,286 @@
+package text
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// BMFont is a bitmap font in the AngelCode BMFont format, made of pre-drawn glyphs in page
+// images. It's a font.Face, so an Atlas is created from it just like from any other face. The
+// colors of the page images are preserved, so the hand-drawn pixel fonts keep their baked-in
+// outlines, shadows and gradients. Such glyphs are meant to be drawn with a white Color, which
+// leaves their colors intact.
+//
+//   fnt, err := text.LoadBMFont(file, func(page string) (image.Image, error) {
+//       return loadPNG(filepath.Join("fonts", page))
+//   })
+//   if err != nil {
+//       panic(err)
+//   }
+//   atlas := text.NewAtlas(fnt, fnt.Runes())
+type BMFont struct {
+	pages      []image.Image
+	chars      map[rune]bmChar
+	kernings   map[[2]rune]int
+	lineHeight int
+	base       int
+}
+
+// bmChar is one glyph of a BMFont. The position and the size are in the page image, the offset is
+// from the dot on the top of the line to the top-left corner of the glyph.
+type bmChar struct {
+	x, y, width, height int
+	xOffset, yOffset    int
+	xAdvance            int
+	page                int
+}
+
+// LoadBMFont loads a BMFont from its font descriptor (the .fnt file) in the text or the XML format.
+// The page images are loaded by the page function from the file names given in the descriptor,
+// usually relative to the descriptor.
+//
+// BMFonts rarely contain unicode.ReplacementChar, which an Atlas draws for unknown runes. If it's
+// missing, the BMFont draws '?' in its place, or nothing if there's no '?' either.
+//
+// The fonts with the glyphs packed into separate color channels aren't supported.
+func LoadBMFont(r io.Reader, page func(file string) (image.Image, error)) (*BMFont, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &BMFont{
+		chars:    make(map[rune]bmChar),
+		kernings: make(map[[2]rune]int),
+	}
+	files := make(map[int]string)
+
+	tags := readBMFontText
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		tags = readBMFontXML
+	}
+	err = tags(data, func(tag string, attrs *bmAttrs) error {
+		switch tag {
+		case "common":
+			if attrs.int("packed") != 0 {
+				return fmt.Errorf("text: packed BMFont not supported")
+			}
+			f.lineHeight = attrs.int("lineHeight")
+			f.base = attrs.int("base")
+		case "page":
+			files[attrs.int("id")] = attrs.str("file")
+		case "char":
+			f.chars[rune(attrs.int("id"))] = bmChar{
+				x:        attrs.int("x"),
+				y:        attrs.int("y"),
+				width:    attrs.int("width"),
+				height:   attrs.int("height"),
+				xOffset:  attrs.int("xoffset"),
+				yOffset:  attrs.int("yoffset"),
+				xAdvance: attrs.int("xadvance"),
+				page:     attrs.int("page"),
+			}
+		case "kerning":
+			pair := [2]rune{rune(attrs.int("first")), rune(attrs.int("second"))}
+			f.kernings[pair] = attrs.int("amount")
+		}
+		return attrs.err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the Atlas draws the unknown runes as the replacement rune
+	if _, ok := f.chars[unicode.ReplacementChar]; !ok {
+		f.chars[unicode.ReplacementChar] = f.chars['?']
+	}
+
+	f.pages = make([]image.Image, len(files))
+	for id, file := range files {
+		if id < 0 || id >= len(files) {
+			return nil, fmt.Errorf("text: invalid BMFont page id %d", id)
+		}
+		img, err := page(file)
+		if err != nil {
+			return nil, fmt.Errorf("text: loading BMFont page %q: %v", file, err)
+		}
+		f.pages[id] = img
+	}
+	for r, c := range f.chars {
+		if c.page < 0 || c.page >= len(f.pages) {
+			return nil, fmt.Errorf("text: BMFont char %d on missing page %d", r, c.page)
+		}
+	}
+
+	return f, nil
+}
+
+// Runes returns all the runes of the BMFont, sorted.
+func (f *BMFont) Runes() []rune {
+	runes := make([]rune, 0, len(f.chars))
+	for r := range f.chars {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes
+}
+
+// Close does nothing, the BMFont doesn't hold any resources.
+func (f *BMFont) Close() error {
+	return nil
+}
+
+// Glyph returns the glyph of r drawn at the dot, which is rounded to the whole pixels. The mask is
+// the page image of the glyph.
+func (f *BMFont) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	c, ok := f.chars[r]
+	if !ok {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+	min := image.Pt(dot.X.Round()+c.xOffset, dot.Y.Round()-f.base+c.yOffset)
+	dr = image.Rectangle{Min: min, Max: min.Add(image.Pt(c.width, c.height))}
+	maskp = f.pages[c.page].Bounds().Min.Add(image.Pt(c.x, c.y))
+	return dr, f.pages[c.page], maskp, fixed.I(c.xAdvance), true
+}
+
+// GlyphBounds returns the bounds of the glyph of r relative to the dot and its advance.
+func (f *BMFont) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	c, ok := f.chars[r]
+	if !ok {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	bounds = fixed.R(c.xOffset, c.yOffset-f.base, c.xOffset+c.width, c.yOffset-f.base+c.height)
+	return bounds, fixed.I(c.xAdvance), true
+}
+
+// GlyphAdvance returns the advance of the glyph of r.
+func (f *BMFont) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	c, ok := f.chars[r]
+	return fixed.I(c.xAdvance), ok
+}
+
+// Kern returns the kerning amount between the runes r0 and r1 from the kerning pairs of the
+// BMFont.
+func (f *BMFont) Kern(r0, r1 rune) fixed.Int26_6 {
+	return fixed.I(f.kernings[[2]rune{r0, r1}])
+}
+
+// Metrics returns the metrics of the BMFont. The ascent is the base of the font and the descent is
+// the rest of its line height.
+func (f *BMFont) Metrics() font.Metrics {
+	return font.Metrics{
+		Height:  fixed.I(f.lineHeight),
+		Ascent:  fixed.I(f.base),
+		Descent: fixed.I(f.lineHeight - f.base),
+	}
+}
+
+// bmAttrs are the attributes of a tag of a BMFont descriptor. The first error of converting them
+// is kept in err.
+type bmAttrs struct {
+	values map[string]string
+	err    error
+}
+
+func (attrs *bmAttrs) str(key string) string {
+	return attrs.values[key]
+}
+
+func (attrs *bmAttrs) int(key string) int {
+	s, ok := attrs.values[key]
+	if !ok {
+		return 0
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil && attrs.err == nil {
+		attrs.err = fmt.Errorf("text: invalid BMFont attribute %s=%q", key, s)
+	}
+	return i
+}
+
+// readBMFontText reads the tags of a BMFont descriptor in the text format, one tag per line:
+//
+//   char id=65 x=10 y=0 width=7 height=9 xoffset=0 yoffset=3 xadvance=8 page=0 chnl=15
+func readBMFontText(data []byte, tag func(name string, attrs *bmAttrs) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		name := line
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			name, line = line[:i], line[i:]
+		} else {
+			line = ""
+		}
+
+		attrs := &bmAttrs{values: make(map[string]string)}
+		for {
+			line = strings.TrimLeft(line, " \t")
+			if line == "" {
+				break
+			}
+			eq := strings.IndexByte(line, '=')
+			if eq < 0 {
+				return fmt.Errorf("text: invalid BMFont line %q", scanner.Text())
+			}
+			key, value := line[:eq], line[eq+1:]
+			if strings.HasPrefix(value, `"`) {
+				end := strings.IndexByte(value[1:], '"')
+				if end < 0 {
+					return fmt.Errorf("text: invalid BMFont line %q", scanner.Text())
+				}
+				value, line = value[1:end+1], value[end+2:]
+			} else if i := strings.IndexAny(value, " \t"); i >= 0 {
+				value, line = value[:i], value[i:]
+			} else {
+				line = ""
+			}
+			attrs.values[key] = value
+		}
+
+		if err := tag(name, attrs); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// readBMFontXML reads the tags of a BMFont descriptor in the XML format, which has the same tags
+// and attributes as the text format.
+func readBMFontXML(data []byte, tag func(name string, attrs *bmAttrs) error) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("text: invalid BMFont XML: %v", err)
+		}
+		elem, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		attrs := &bmAttrs{values: make(map[string]string)}
+		for _, attr := range elem.Attr {
+			attrs.values[attr.Name.Local] = attr.Value
+		}
+		if err := tag(elem.Name.Local, attrs); err != nil {
+			return err
+		}
+	}
+}
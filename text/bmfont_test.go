@@ -0,0 +1,125 @@
+package text_test
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+)
+
+// a font of two 3×4 glyphs on one page, 'A' is red and 'B' is green
+const bmFontText = `info face="Test Font" size=8 bold=0 italic=0 padding=0,0,0,0 spacing=1,1
+common lineHeight=6 base=5 scaleW=16 scaleH=8 pages=1 packed=0
+page id=0 file="test_0.png"
+chars count=3
+char id=32 x=0 y=0 width=0 height=0 xoffset=0 yoffset=5 xadvance=2 page=0 chnl=15
+char id=65 x=0 y=0 width=3 height=4 xoffset=0 yoffset=1 xadvance=4 page=0 chnl=15
+char id=66 x=4 y=0 width=3 height=4 xoffset=1 yoffset=1 xadvance=5 page=0 chnl=15
+kernings count=1
+kerning first=65 second=66 amount=-1
+`
+
+const bmFontXML = `<?xml version="1.0"?>
+<font>
+  <info face="Test Font" size="8"/>
+  <common lineHeight="6" base="5" scaleW="16" scaleH="8" pages="1" packed="0"/>
+  <pages>
+    <page id="0" file="test_0.png"/>
+  </pages>
+  <chars count="3">
+    <char id="32" x="0" y="0" width="0" height="0" xoffset="0" yoffset="5" xadvance="2" page="0"/>
+    <char id="65" x="0" y="0" width="3" height="4" xoffset="0" yoffset="1" xadvance="4" page="0"/>
+    <char id="66" x="4" y="0" width="3" height="4" xoffset="1" yoffset="1" xadvance="5" page="0"/>
+  </chars>
+  <kernings count="1">
+    <kerning first="65" second="66" amount="-1"/>
+  </kernings>
+</font>
+`
+
+func bmFontPage(file string) (image.Image, error) {
+	if file != "test_0.png" {
+		return nil, errors.New("no such file")
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 3; x++ {
+			img.Set(x, y, color.RGBA{0xff, 0, 0, 0xff})
+			img.Set(4+x, y, color.RGBA{0, 0xff, 0, 0xff})
+		}
+	}
+	return img, nil
+}
+
+func TestLoadBMFont(t *testing.T) {
+	for name, desc := range map[string]string{"text": bmFontText, "xml": bmFontXML} {
+		fnt, err := text.LoadBMFont(strings.NewReader(desc), bmFontPage)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if got, want := string(fnt.Runes()), " AB\ufffd"; got != want {
+			t.Errorf("%s: runes %q, want %q", name, got, want)
+		}
+		m := fnt.Metrics()
+		if m.Height.Round() != 6 || m.Ascent.Round() != 5 || m.Descent.Round() != 1 {
+			t.Errorf("%s: metrics %+v, want height 6, ascent 5 and descent 1", name, m)
+		}
+		if got := fnt.Kern('A', 'B').Round(); got != -1 {
+			t.Errorf("%s: kerning of AB %d, want -1", name, got)
+		}
+		if got := fnt.Kern('B', 'A').Round(); got != 0 {
+			t.Errorf("%s: kerning of BA %d, want 0", name, got)
+		}
+
+		atlas := text.NewAtlas(fnt, fnt.Runes())
+		if got, want := atlas.Glyph('B').Advance, 5.0; got != want {
+			t.Errorf("%s: advance of B %v, want %v", name, got, want)
+		}
+		_, _, _, dot := atlas.DrawRune(-1, 'A', pixel.ZV)
+		rect, _, _, _ := atlas.DrawRune('A', 'B', dot)
+		if want := pixel.R(4, 0, 7, 4); rect != want {
+			t.Errorf("%s: B drawn into %v, want %v", name, rect, want)
+		}
+	}
+}
+
+func TestBMFontColors(t *testing.T) {
+	fnt, err := text.LoadBMFont(strings.NewReader(bmFontText), bmFontPage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txt := text.New(pixel.ZV, text.NewAtlas(fnt, fnt.Runes()))
+	txt.WriteString("AB")
+	drawn := drawnText(txt)
+
+	red, green := pixel.RGB(1, 0, 0), pixel.RGB(0, 1, 0)
+	if got := drawn[pixel.V(1, 1)]; got != red {
+		t.Errorf("A drawn with %v, want %v", got, red)
+	}
+	if got := drawn[pixel.V(5, 1)]; got != green {
+		t.Errorf("B drawn with %v, want %v", got, green)
+	}
+	if got, want := len(drawn), 2*3*4; got != want {
+		t.Errorf("%d pixels drawn, want %d", got, want)
+	}
+}
+
+func TestLoadBMFontErrors(t *testing.T) {
+	tests := map[string]string{
+		"packed":       strings.Replace(bmFontText, "packed=0", "packed=1", 1),
+		"invalid":      strings.Replace(bmFontText, "xadvance=4", "xadvance=four", 1),
+		"missing page": strings.Replace(bmFontText, "test_0.png", "test_1.png", 1),
+		"bad page":     strings.Replace(bmFontText, "xadvance=4 page=0", "xadvance=4 page=1", 1),
+		"bad xml":      strings.Replace(bmFontXML, "</font>", "", 1),
+	}
+	for name, desc := range tests {
+		if _, err := text.LoadBMFont(strings.NewReader(desc), bmFontPage); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package text
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"unicode"
+
+	"github.com/faiface/pixel"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// dynamicPadding is the space between the glyphs of a dynamic Atlas.
+const dynamicPadding = 2
+
+// dynamicAtlas adds the glyphs to an Atlas when they're first needed. The glyphs are packed into
+// rows going up in the Picture, which grows when it's full. The glyphs are never moved, so that
+// their frames stay valid.
+type dynamicAtlas struct {
+	pix     *pixel.PictureData
+	changed bool
+
+	// the position of the next glyph and the height of the current row
+	x, y, row int
+
+	// missing are the runes, which the face doesn't have a glyph for
+	missing map[rune]bool
+}
+
+// NewDynamicAtlas creates a new Atlas like NewAtlas, but the Atlas isn't limited to the given sets
+// of runes. Other runes are added to it from the face when they're first drawn, so the Atlas can
+// draw any text the face can. The given sets of runes are added right away, which saves growing
+// the Atlas later.
+//
+// When new glyphs are added, the Picture of the Atlas changes (grows if needed) and a Text drawing
+// the Atlas picks the new Picture up automatically. The glyphs already added never move, so the
+// text already written stays valid. Unlike other Atlases, a dynamic Atlas isn't safe for concurrent
+// use.
+//
+//   // any user-generated text
+//   atlas := text.NewDynamicAtlas(face, text.ASCII)
+//   txt := text.New(orig, atlas)
+//   fmt.Fprint(txt, playerName)
+func NewDynamicAtlas(face font.Face, runeSets ...[]rune) *Atlas {
+	runes := []rune{unicode.ReplacementChar}
+	area := 0
+	for _, set := range runeSets {
+		for _, r := range set {
+			runes = append(runes, r)
+			if b, _, ok := face.GlyphBounds(r); ok {
+				area += (b.Max.X.Ceil() - b.Min.X.Floor() + dynamicPadding) *
+					(b.Max.Y.Ceil() - b.Min.Y.Floor() + dynamicPadding)
+			}
+		}
+	}
+	size := int(math.Max(256, math.Ceil(math.Sqrt(float64(area)))))
+
+	a := &Atlas{
+		face:       face,
+		mapping:    make(map[rune]Glyph),
+		ascent:     i2f(face.Metrics().Ascent),
+		descent:    i2f(face.Metrics().Descent),
+		lineHeight: i2f(face.Metrics().Height),
+		dyn: &dynamicAtlas{
+			pix:     pixel.MakePictureData(pixel.R(0, 0, float64(size), float64(size))),
+			missing: make(map[rune]bool),
+		},
+	}
+	for _, r := range runes {
+		a.add(r)
+	}
+	return a
+}
+
+// add adds the glyph of r to a dynamic Atlas, if it's not in the Atlas already and the face has
+// it. It reports whether the Atlas contains the glyph.
+func (a *Atlas) add(r rune) bool {
+	if _, ok := a.mapping[r]; ok {
+		return true
+	}
+	if a.dyn == nil || a.dyn.missing[r] {
+		return false
+	}
+
+	b, advance, ok := a.face.GlyphBounds(r)
+	if !ok {
+		a.dyn.missing[r] = true
+		return false
+	}
+	frame := image.Rect(b.Min.X.Floor(), b.Min.Y.Floor(), b.Max.X.Ceil(), b.Max.Y.Ceil())
+	w, h := frame.Dx(), frame.Dy()
+
+	d := a.dyn
+	if d.x > 0 && float64(d.x+w) > d.pix.Rect.W() {
+		// new row
+		d.x, d.y, d.row = 0, d.y+d.row+dynamicPadding, 0
+	}
+	d.grow(d.x+w, d.y+h)
+
+	// draw the glyph with its frame at the origin and copy it upside down into the Picture
+	glyph := image.NewRGBA(image.Rect(0, 0, w, h))
+	dr, mask, maskp, _, ok := a.face.Glyph(fixed.P(-frame.Min.X, -frame.Min.Y), r)
+	if ok {
+		draw.Draw(glyph, dr, mask, maskp, draw.Src)
+	}
+	for iy := 0; iy < h; iy++ {
+		for ix := 0; ix < w; ix++ {
+			d.pix.Pix[(d.y+h-1-iy)*d.pix.Stride+d.x+ix] = glyph.RGBAAt(ix, iy)
+		}
+	}
+
+	a.mapping[r] = Glyph{
+		Dot:     pixel.V(float64(d.x-frame.Min.X), float64(d.y+h+frame.Min.Y)),
+		Frame:   pixel.R(float64(d.x), float64(d.y), float64(d.x+w), float64(d.y+h)),
+		Advance: i2f(advance),
+	}
+
+	d.x += w + dynamicPadding
+	if h > d.row {
+		d.row = h
+	}
+	d.changed = true
+	return true
+}
+
+// grow grows the Picture to at least the width and the height, by doubling them. The Picture
+// coordinates of the pixels stay the same.
+func (d *dynamicAtlas) grow(width, height int) {
+	w, h := int(d.pix.Rect.W()), int(d.pix.Rect.H())
+	if width <= w && height <= h {
+		return
+	}
+	newW, newH := w, h
+	for newW < width {
+		newW *= 2
+	}
+	for newH < height {
+		newH *= 2
+	}
+
+	pix := pixel.MakePictureData(pixel.R(0, 0, float64(newW), float64(newH)))
+	for y := 0; y < h; y++ {
+		copy(pix.Pix[y*pix.Stride:y*pix.Stride+w], d.pix.Pix[y*d.pix.Stride:y*d.pix.Stride+w])
+	}
+	d.pix = pix
+}
+
+// picture returns the current Picture of a dynamic Atlas. A new Picture is returned after adding
+// glyphs, so that the Targets, which cache the Pictures, draw the new glyphs.
+func (d *dynamicAtlas) picture(prev pixel.Picture) pixel.Picture {
+	if !d.changed && prev != nil {
+		return prev
+	}
+	d.changed = false
+	// the pixels are shared, but the old Pictures only show the glyphs, which were already there
+	return &pixel.PictureData{
+		Pix:    d.pix.Pix,
+		Stride: d.pix.Stride,
+		Rect:   d.pix.Rect,
+	}
+}
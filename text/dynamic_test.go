@@ -0,0 +1,90 @@
+package text_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/font/basicfont"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+	"github.com/faiface/pixel/text"
+)
+
+func TestDynamicAtlas(t *testing.T) {
+	atlas := text.NewDynamicAtlas(basicfont.Face7x13)
+
+	if !atlas.Contains('a') || !atlas.Contains('~') {
+		t.Error("the dynamic atlas doesn't contain the runes of its face")
+	}
+	if atlas.Contains('世') {
+		t.Error("the dynamic atlas contains a rune its face doesn't have")
+	}
+
+	// the glyphs are the same as in a static atlas
+	pic := atlas.Picture().(pixel.PictureColor)
+	static := text.Atlas7x13.Picture().(pixel.PictureColor)
+	for _, r := range "aHg@ " {
+		rect, frame, bounds, dot := text.Atlas7x13.DrawRune('a', r, pixel.V(10, 20))
+		dynRect, dynFrame, dynBounds, dynDot := atlas.DrawRune('a', r, pixel.V(10, 20))
+		if dynRect != rect || dynBounds != bounds || dynDot != dot {
+			t.Errorf("%q: got %v, %v, %v, want %v, %v, %v", r, dynRect, dynBounds, dynDot, rect, bounds, dot)
+		}
+		for x := 0.5; x < frame.W(); x++ {
+			for y := 0.5; y < frame.H(); y++ {
+				at := pixel.V(x, y)
+				if got, want := pic.Color(dynFrame.Min.Add(at)), static.Color(frame.Min.Add(at)); got != want {
+					t.Errorf("%q: pixel %v = %v, want %v", r, at, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDynamicAtlasGrow(t *testing.T) {
+	// big opaque squares for the lowercase letters (and the replacement rune)
+	mask := image.NewAlpha(image.Rect(0, 0, 100, 26*100))
+	for i := range mask.Pix {
+		mask.Pix[i] = 0xff
+	}
+	face := &basicfont.Face{
+		Advance: 100, Width: 100, Height: 100, Ascent: 100,
+		Mask:   mask,
+		Ranges: []basicfont.Range{{Low: 'a', High: 'z' + 1}, {Low: '\ufffd', High: '\ufffe'}},
+	}
+
+	atlas := text.NewDynamicAtlas(face)
+	txt := text.New(pixel.ZV, atlas)
+	canvas := raster.NewCanvas(pixel.R(0, 0, 300, 100))
+
+	fmt.Fprint(txt, "a")
+	txt.Draw(canvas, pixel.IM)
+	frame := atlas.Glyph('a').Frame
+	first := atlas.Picture()
+
+	// the letters don't fit into the initial Picture
+	txt.Clear()
+	fmt.Fprint(txt, "abcdefghijklmnopqrstuvwxyz")
+	txt.Clear()
+	fmt.Fprint(txt, "azq")
+
+	if atlas.Picture() == first {
+		t.Fatal("the Picture didn't change after adding glyphs")
+	}
+	if got := atlas.Picture().Bounds(); got.H() <= first.Bounds().H() {
+		t.Errorf("the Picture didn't grow, its bounds are %v", got)
+	}
+	if got := atlas.Glyph('a').Frame; got != frame {
+		t.Errorf("the frame of 'a' moved from %v to %v", frame, got)
+	}
+
+	canvas.Clear(color.Transparent)
+	txt.Draw(canvas, pixel.IM)
+	for _, x := range []float64{50, 150, 250} {
+		if got, want := canvas.Color(pixel.V(x, 50)), pixel.Alpha(1); got != want {
+			t.Errorf("pixel at %v drawn with %v, want %v", x, got, want)
+		}
+	}
+}
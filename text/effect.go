@@ -0,0 +1,65 @@
+package text
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// effects are the parameters of the outline and the shadow the text was last drawn with.
+type effects struct {
+	outline    float64
+	outlineCol pixel.RGBA
+	shadow     pixel.Vec
+	shadowCol  pixel.RGBA
+}
+
+// effectPass is one copy of the glyphs drawn for the outline or the shadow, or the glyphs
+// themselves if the color is nil.
+type effectPass struct {
+	offset pixel.Vec
+	color  *pixel.RGBA
+}
+
+func (txt *Text) currentEffects() effects {
+	return effects{
+		outline:    txt.Outline,
+		outlineCol: effectColor(txt.OutlineColor),
+		shadow:     txt.Shadow,
+		shadowCol:  effectColor(txt.ShadowColor),
+	}
+}
+
+// effectColor returns the color of an outline or a shadow, nil is black.
+func effectColor(c color.Color) pixel.RGBA {
+	if c == nil {
+		return pixel.RGB(0, 0, 0)
+	}
+	return pixel.ToRGBA(c)
+}
+
+// passes returns the copies of the glyphs to draw, in the order of drawing. The outline is made of
+// copies of the glyphs offset in all directions, the shadow is a copy of the glyphs and their
+// outline underneath.
+func (e effects) passes() []effectPass {
+	offsets := []pixel.Vec{pixel.ZV}
+	if e.outline > 0 {
+		// enough directions for the copies to be at most a unit apart
+		num := int(math.Max(8, math.Ceil(2*math.Pi*e.outline)))
+		for i := 0; i < num; i++ {
+			offsets = append(offsets, pixel.Unit(2*math.Pi*float64(i)/float64(num)).Scaled(e.outline))
+		}
+	}
+
+	var passes []effectPass
+	if e.shadow != pixel.ZV {
+		for _, offset := range offsets {
+			passes = append(passes, effectPass{offset.Add(e.shadow), &e.shadowCol})
+		}
+	}
+	for _, offset := range offsets[1:] {
+		passes = append(passes, effectPass{offset, &e.outlineCol})
+	}
+	return append(passes, effectPass{pixel.ZV, nil})
+}
@@ -0,0 +1,87 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+	"github.com/faiface/pixel/text"
+)
+
+// drawnText draws the text onto a transparent canvas and returns the colors of the drawn pixels.
+func drawnText(txt *text.Text) map[pixel.Vec]pixel.RGBA {
+	canvas := raster.NewCanvas(pixel.R(-10, -15, 30, 20))
+	txt.Draw(canvas, pixel.IM)
+
+	drawn := make(map[pixel.Vec]pixel.RGBA)
+	for x := -10.0; x < 30; x++ {
+		for y := -15.0; y < 20; y++ {
+			if c := canvas.Color(pixel.V(x, y)); c.A > 0 {
+				drawn[pixel.V(x, y)] = c
+			}
+		}
+	}
+	return drawn
+}
+
+func TestOutline(t *testing.T) {
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	txt.WriteString("Hi")
+	glyphs := drawnText(txt)
+	if len(glyphs) == 0 {
+		t.Fatal("nothing drawn")
+	}
+
+	red := pixel.RGB(1, 0, 0)
+	txt.Outline = 1
+	txt.OutlineColor = red
+	drawn := drawnText(txt)
+
+	outline := 0
+	for pos, c := range drawn {
+		if _, ok := glyphs[pos]; ok {
+			if c != pixel.RGB(1, 1, 1) {
+				t.Errorf("glyph pixel %v drawn with %v, want white", pos, c)
+			}
+			continue
+		}
+		if c != red {
+			t.Errorf("outline pixel %v drawn with %v, want %v", pos, c, red)
+		}
+		outline++
+	}
+	if outline == 0 {
+		t.Error("no outline drawn")
+	}
+
+	// the outline is removed again
+	txt.Outline = 0
+	if got, want := len(drawnText(txt)), len(glyphs); got != want {
+		t.Errorf("%d pixels drawn without the outline, want %d", got, want)
+	}
+}
+
+func TestShadow(t *testing.T) {
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	txt.WriteString("Hi")
+	glyphs := drawnText(txt)
+
+	blue := pixel.RGB(0, 0, 1)
+	shadow := pixel.V(3, -3)
+	txt.Shadow = shadow
+	txt.ShadowColor = blue
+	drawn := drawnText(txt)
+
+	if got, want := len(drawn), len(glyphs); got <= want {
+		t.Fatalf("%d pixels drawn with the shadow, want more than %d", got, want)
+	}
+	for pos := range glyphs {
+		want := blue
+		if _, ok := glyphs[pos.Add(shadow)]; ok {
+			want = pixel.RGB(1, 1, 1)
+		}
+		if got := drawn[pos.Add(shadow)]; got != want {
+			t.Errorf("pixel %v drawn with %v, want %v", pos.Add(shadow), got, want)
+		}
+	}
+}
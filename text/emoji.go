@@ -0,0 +1,85 @@
+package text
+
+import (
+	"image"
+	"sort"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// emojiModifiers are the invisible runes, which modify the emoji in the sequences of runes. They
+// are drawn as nothing, so that they don't show up as unknown runes.
+var emojiModifiers = []rune{
+	'\u200d', // zero width joiner
+	'\ufe0e', // text presentation selector
+	'\ufe0f', // emoji presentation selector
+}
+
+// NewImageAtlas creates a new Atlas like NewAtlas, which additionally contains colored glyphs
+// drawn from the images. This is useful for emoji, which fonts usually don't contain, or which are
+// stored in color font tables, that font.Face doesn't support.
+//
+// Each image is scaled to the height from the ascent to the descent of the face, keeping its
+// aspect ratio, and it sits on the descent. An image replaces the glyph of its rune from the face,
+// if there's one. The colored glyphs aren't tinted by the Color of the Text, they only take its
+// opacity. See the Colored method.
+//
+// The emoji modifier runes (zero width joiner and the presentation selectors) are drawn as nothing,
+// but the sequences of runes aren't combined. That is, a sequence, such as a family emoji, draws
+// each of its emoji.
+//
+//   emoji := map[rune]image.Image{
+//       '😀': loadPNG("emoji/1f600.png"),
+//       '❤': loadPNG("emoji/2764.png"),
+//   }
+//   atlas := text.NewImageAtlas(face, emoji, text.ASCII)
+func NewImageAtlas(face font.Face, images map[rune]image.Image, runeSets ...[]rune) *Atlas {
+	height := (face.Metrics().Ascent + face.Metrics().Descent).Ceil()
+
+	scaled := make(map[rune]*image.RGBA, len(images))
+	for r, img := range images {
+		size := img.Bounds().Size()
+		if size.X == 0 || size.Y == 0 {
+			continue
+		}
+		width := (size.X*height + size.Y/2) / size.Y
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+		scaled[r] = dst
+	}
+
+	return newAtlas(face, AtlasOptions{}, 0, scaled, runeSets)
+}
+
+// Colored reports whether the glyph of r is a colored image.
+func (a *Atlas) Colored(r rune) bool {
+	return a.colored[r]
+}
+
+// imageRunes returns the runes of the images, sorted.
+func imageRunes(images map[rune]*image.RGBA) []rune {
+	runes := make([]rune, 0, len(images))
+	for r := range images {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes
+}
+
+// glyphBounds returns the bounds and the advance of the glyph of r like font.Face.GlyphBounds,
+// but from the image of r if there's one. The images are already scaled to the line.
+func glyphBounds(face font.Face, images map[rune]*image.RGBA, r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	img, ok := images[r]
+	if !ok {
+		return face.GlyphBounds(r)
+	}
+	size := img.Bounds().Size()
+	descent := face.Metrics().Descent
+	bounds = fixed.Rectangle26_6{
+		Min: fixed.Point26_6{X: 0, Y: descent - fixed.I(size.Y)},
+		Max: fixed.Point26_6{X: fixed.I(size.X), Y: descent},
+	}
+	return bounds, fixed.I(size.X), true
+}
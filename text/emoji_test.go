@@ -0,0 +1,64 @@
+package text_test
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"golang.org/x/image/font/basicfont"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+)
+
+func TestImageAtlas(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 26, 26))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+		if i%4 == 1 || i%4 == 2 {
+			img.Pix[i] = 0
+		}
+	}
+
+	atlas := text.NewImageAtlas(basicfont.Face7x13, map[rune]image.Image{'😀': img}, text.ASCII)
+
+	if !atlas.Contains('😀') || !atlas.Colored('😀') {
+		t.Fatal("the emoji is not a colored glyph of the atlas")
+	}
+	if atlas.Colored('a') {
+		t.Error("atlas.Colored('a') = true, want false")
+	}
+
+	// scaled to the line, with the same aspect ratio
+	glyph := atlas.Glyph('😀')
+	if got, want := glyph.Frame.Size(), pixel.V(13, 13); got != want {
+		t.Errorf("emoji frame size = %v, want %v", got, want)
+	}
+	if got, want := glyph.Advance, 13.0; got != want {
+		t.Errorf("emoji advance = %v, want %v", got, want)
+	}
+
+	txt := text.New(pixel.ZV, atlas)
+	txt.Color = pixel.RGB(0, 0, 1)
+	fmt.Fprint(txt, "a😀\ufe0fa")
+
+	if got, want := txt.Dot, pixel.V(27, 0); !eqVectors(got, want) {
+		t.Errorf("txt.Dot = %v, want %v", got, want)
+	}
+
+	// the emoji isn't tinted by the color of the text
+	drawn := drawnText(txt)
+	for pos, c := range drawn {
+		want := pixel.RGB(0, 0, 1)
+		if pos.X >= 7 && pos.X < 20 {
+			want = pixel.RGB(1, 0, 0)
+		}
+		if c != want {
+			t.Errorf("pixel %v drawn with %v, want %v", pos, c, want)
+			break
+		}
+	}
+	if got, want := drawn[pixel.V(13, 3)], pixel.RGB(1, 0, 0); got != want {
+		t.Errorf("emoji pixel drawn with %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package text_test
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+)
+
+// kernFace is basicfont.Face7x13 kerning the glyphs before 'V' closer together
+type kernFace struct {
+	*basicfont.Face
+}
+
+func (kernFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	if r1 == 'V' && (r0 == 'A' || r0 == unicode.ReplacementChar) {
+		return fixed.I(-2)
+	}
+	return 0
+}
+
+func TestKerning(t *testing.T) {
+	atlas := text.NewAtlas(kernFace{basicfont.Face7x13}, text.ASCII)
+
+	tests := []struct {
+		s       string
+		kerning bool
+		dot     pixel.Vec
+	}{
+		{"AV", true, pixel.V(12, 0)},
+		{"AV", false, pixel.V(14, 0)},
+		{"AAVA", true, pixel.V(26, 0)},
+		// nothing to kern with
+		{"V", true, pixel.V(7, 0)},
+		{"A\nV", true, pixel.V(7, -13)},
+	}
+	for _, tt := range tests {
+		txt := text.New(pixel.ZV, atlas)
+		txt.Kerning = tt.kerning
+		fmt.Fprint(txt, tt.s)
+		if got := txt.Dot; !eqVectors(got, tt.dot) {
+			t.Errorf("%q, kerning %v: txt.Dot = %v, want %v", tt.s, tt.kerning, got, tt.dot)
+		}
+	}
+
+	// the Dot moved manually ends kerning
+	txt := text.New(pixel.ZV, atlas)
+	fmt.Fprint(txt, "A")
+	txt.Dot = pixel.V(100, 0)
+	fmt.Fprint(txt, "V")
+	if got, want := txt.Dot, pixel.V(107, 0); !eqVectors(got, want) {
+		t.Errorf("txt.Dot = %v after moving it, want %v", got, want)
+	}
+}
@@ -0,0 +1,305 @@
+package text
+
+import (
+	"math"
+	"unicode"
+
+	"github.com/faiface/pixel"
+)
+
+// Align specifies the horizontal alignment of the lines of a Text.
+type Align int
+
+const (
+	// AlignLeft aligns the lines to the left edge at Orig.
+	AlignLeft Align = iota
+
+	// AlignCenter centers the lines between the left edge and MaxWidth, or around Orig if
+	// MaxWidth is zero.
+	AlignCenter
+
+	// AlignRight aligns the lines to the right edge at MaxWidth, or to Orig if MaxWidth is zero.
+	AlignRight
+
+	// AlignJustify stretches the spaces between words, so that the lines fill MaxWidth
+	// exactly. The lines ending with a newline (such as the last line of a paragraph) stay
+	// aligned to the left. With zero MaxWidth, it's the same as AlignLeft.
+	AlignJustify
+)
+
+// layout is the state of laying out the glyphs written to a Text.
+type layout struct {
+	dot   pixel.Vec
+	prevR rune
+
+	// bounds is the union of prevBounds and, if the current word has any glyphs, wordBounds
+	bounds     pixel.Rect
+	prevBounds pixel.Rect
+	wordBounds pixel.Rect
+
+	// the glyphs of the current word are moved to the next line together when wrapping
+	inWord     bool
+	wordDot    pixel.Vec
+	wordGlyphs int
+
+	inLine bool
+	lineY  float64
+
+	// fixedBreaks turns off the wrapping by MaxWidth, the lines are broken by breakLine instead,
+	// see shapeWrapped. broken is true after breakLine, until the next glyph.
+	fixedBreaks bool
+	broken      bool
+}
+
+// laidGlyph is a glyph placed by layoutRune.
+type laidGlyph struct {
+	rect, frame pixel.Rect
+
+	// wordMoved is the distance by which the previous glyphs of the current word were moved by
+	// wrapping
+	wordMoved pixel.Vec
+
+	// newWord is true if the glyph starts a new word
+	newWord bool
+
+	// dot is the position of the dot the glyph was drawn at
+	dot pixel.Vec
+
+	info glyphInfo
+}
+
+// glyphInfo describes a glyph written to a Text, for aligning the lines of the text.
+type glyphInfo struct {
+	bounds pixel.Rect
+	end    float64 // the X of the dot after the glyph
+	space  bool
+
+	// lineStart is true for the first glyph of a line, wrapped is true if the line was started by
+	// wrapping
+	lineStart bool
+	wrapped   bool
+
+	// fixed glyphs are not aligned, such as the glyphs written along a Path
+	fixed bool
+}
+
+func unionBounds(a, b pixel.Rect) pixel.Rect {
+	if a.W()*a.H() == 0 {
+		return b
+	}
+	return a.Union(b)
+}
+
+// add adds the bounds of a glyph to the current word, or the previous text if there's no word.
+func (l *layout) add(b pixel.Rect) {
+	if l.inWord {
+		l.wordBounds = unionBounds(l.wordBounds, b)
+		l.wordGlyphs++
+	} else {
+		l.prevBounds = unionBounds(l.prevBounds, b)
+	}
+	l.updateBounds()
+}
+
+func (l *layout) updateBounds() {
+	l.bounds = l.prevBounds
+	if l.wordGlyphs > 0 {
+		l.bounds = unionBounds(l.bounds, l.wordBounds)
+	}
+}
+
+// endWord ends the current word, it won't be moved by wrapping anymore.
+func (l *layout) endWord() {
+	l.inWord = false
+	l.prevBounds = l.bounds
+	l.wordBounds = pixel.Rect{}
+	l.wordGlyphs = 0
+}
+
+// startWord starts a new word at the dot.
+func (l *layout) startWord() {
+	l.endWord()
+	l.inWord = true
+	l.wordDot = l.dot
+}
+
+// breakLine moves the dot to the start of the next line, as if the next glyph was wrapped.
+func (txt *Text) breakLine(l *layout) {
+	l.endWord()
+	l.dot = pixel.V(txt.Orig.X, l.dot.Y-txt.lineHeight())
+	l.prevR = -1
+	l.broken = true
+}
+
+// layoutRune places the rune after the previously placed runes. Control runes only move the dot
+// and return false.
+//
+// If the glyph doesn't fit into MaxWidth, the whole current word is moved to the next line, or
+// only the glyph if the word alone is wider than MaxWidth.
+func (txt *Text) layoutRune(l *layout, r rune) (g laidGlyph, ok bool) {
+	var control bool
+	l.dot, control = txt.controlRune(r, l.dot)
+	if control {
+		// the glyphs around a newline or a tab aren't kerned
+		l.endWord()
+		l.prevR = -1
+		return laidGlyph{}, false
+	}
+
+	wrapped := l.broken
+	l.broken = false
+	if unicode.IsSpace(r) {
+		l.endWord()
+	} else if !l.inWord {
+		l.startWord()
+		g.newWord = true
+	}
+
+	var bounds pixel.Rect
+	var dot pixel.Vec
+	g.dot = l.dot
+	g.rect, g.frame, bounds, dot = txt.drawRune(l.prevR, r, l.dot)
+
+	if txt.MaxWidth > 0 && !l.fixedBreaks && l.inWord && g.rect.Max.X-txt.Orig.X > txt.MaxWidth {
+		if l.wordDot.X > txt.Orig.X {
+			// move the whole word to the next line
+			g.wordMoved = pixel.V(txt.Orig.X-l.wordDot.X, -txt.lineHeight())
+			l.wordDot = l.wordDot.Add(g.wordMoved)
+			l.wordBounds = l.wordBounds.Moved(g.wordMoved)
+			l.updateBounds()
+			g.rect = g.rect.Moved(g.wordMoved)
+			g.dot = g.dot.Add(g.wordMoved)
+			bounds = bounds.Moved(g.wordMoved)
+			dot = dot.Add(g.wordMoved)
+			wrapped = true
+		} else if l.dot.X > txt.Orig.X {
+			// the word is too long, break it here
+			l.dot = pixel.V(txt.Orig.X, l.dot.Y-txt.lineHeight())
+			l.startWord()
+			g.newWord = true
+			g.dot = l.dot
+			g.rect, g.frame, bounds, dot = txt.drawRune(-1, r, l.dot)
+			wrapped = true
+		}
+	}
+
+	g.info = glyphInfo{
+		bounds: bounds,
+		end:    dot.X,
+		space:  unicode.IsSpace(r),
+	}
+	if g.wordMoved != pixel.ZV {
+		// the first glyph of the moved word starts the line
+		g.info.lineStart = l.wordGlyphs == 0
+	} else {
+		g.info.lineStart = !l.inLine || dot.Y != l.lineY
+	}
+	g.info.wrapped = wrapped && g.info.lineStart
+	l.inLine = true
+	l.lineY = dot.Y
+
+	l.dot = dot
+	l.prevR = r
+	l.add(bounds)
+
+	return g, true
+}
+
+// moveWord moves the glyph infos of a word moved to the next line by the distance.
+func moveWord(word []glyphInfo, moved pixel.Vec) {
+	for i := range word {
+		word[i].bounds = word[i].bounds.Moved(moved)
+		word[i].end += moved.X
+	}
+	if len(word) > 0 {
+		word[0].lineStart = true
+		word[0].wrapped = true
+	}
+}
+
+// alignShifts returns the horizontal distances by which the glyphs need to be moved to align their
+// lines according to Align.
+func (txt *Text) alignShifts(infos []glyphInfo) []float64 {
+	shifts := make([]float64, len(infos))
+	for start := 0; start < len(infos); {
+		end := start + 1
+		for end < len(infos) && !infos[end].lineStart {
+			end++
+		}
+		wrapped := end < len(infos) && infos[end].wrapped
+		txt.alignLine(infos[start:end], shifts[start:end], wrapped)
+		start = end
+	}
+	return shifts
+}
+
+// alignLine sets the shifts of the glyphs of one line. Only lines ended by wrapping are justified.
+func (txt *Text) alignLine(line []glyphInfo, shifts []float64, wrapped bool) {
+	// the spaces at the ends of the line don't count
+	first, last := -1, -1
+	end := math.Inf(-1)
+	for i, g := range line {
+		if g.fixed || g.space {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+		end = math.Max(end, g.end)
+	}
+	if last < 0 {
+		return
+	}
+	width := end - txt.Orig.X
+
+	var shift float64
+	switch txt.Align {
+	case AlignCenter:
+		shift = (txt.MaxWidth - width) / 2
+	case AlignRight:
+		shift = txt.MaxWidth - width
+	case AlignJustify:
+		spaces := 0
+		for _, g := range line[first:last] {
+			if g.space && !g.fixed {
+				spaces++
+			}
+		}
+		if !wrapped || spaces == 0 || txt.MaxWidth == 0 {
+			return
+		}
+		extra := (txt.MaxWidth - width) / float64(spaces)
+		spaces = 0
+		for i, g := range line {
+			if g.fixed {
+				continue
+			}
+			shifts[i] = extra * float64(spaces)
+			if g.space && i > first && i < last {
+				spaces++
+			}
+		}
+		return
+	}
+
+	for i, g := range line {
+		if !g.fixed {
+			shifts[i] = shift
+		}
+	}
+}
+
+// alignedBounds returns the bounds of the glyphs after aligning them. The spaces are left out, so
+// that the trailing spaces of the lines don't stick out of MaxWidth.
+func (txt *Text) alignedBounds(infos []glyphInfo) pixel.Rect {
+	shifts := txt.alignShifts(infos)
+	bounds := pixel.Rect{}
+	for i, g := range infos {
+		if g.space && !g.fixed {
+			continue
+		}
+		bounds = unionBounds(bounds, g.bounds.Moved(pixel.V(shifts[i], 0)))
+	}
+	return bounds
+}
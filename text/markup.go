@@ -0,0 +1,134 @@
+package text
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/faiface/pixel"
+	"golang.org/x/image/colornames"
+)
+
+// WriteMarkup writes the string to the Text just like WriteString, but the string can contain
+// tags, which change the Color, Scale and Style of the text between an opening and a closing tag.
+// This is useful for highlighting parts of the text, such as keywords in a dialogue:
+//
+//   txt.WriteMarkup("Find the [color=gold][b]golden key[/b][/color] in the [i]cellar[/i].")
+//
+// The supported tags are:
+//
+//   [color=name]...[/color]  sets the Color by a name from golang.org/x/image/colornames,
+//                            or by a hexadecimal #rgb, #rrggbb or #rrggbbaa
+//   [scale=1.5]...[/scale]   multiplies the Scale
+//   [b]...[/b]               adds Bold to the Style
+//   [i]...[/i]               adds Italic to the Style
+//
+// Tags can be nested, but they must be closed in the reverse order. A literal '[' is written as
+// "[[". The Color, Scale and Style are restored at the end of the string, so the tags left open
+// are closed automatically.
+//
+// If the markup is invalid, the text before the invalid tag is written and an error is returned.
+func (txt *Text) WriteMarkup(s string) error {
+	type span struct {
+		tag   string
+		color color.Color
+		scale float64
+		style Style
+	}
+	var spans []span
+	defer func() {
+		if len(spans) > 0 {
+			txt.Color, txt.Scale, txt.Style = spans[0].color, spans[0].scale, spans[0].style
+		}
+	}()
+
+	for s != "" {
+		i := strings.IndexByte(s, '[')
+		if i < 0 {
+			txt.WriteString(s)
+			return nil
+		}
+		txt.WriteString(s[:i])
+		s = s[i+1:]
+
+		if strings.HasPrefix(s, "[") {
+			txt.WriteString("[")
+			s = s[1:]
+			continue
+		}
+
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return fmt.Errorf("text: unterminated markup tag %q", "["+s)
+		}
+		tag := s[:end]
+		s = s[end+1:]
+
+		if strings.HasPrefix(tag, "/") {
+			if len(spans) == 0 || spans[len(spans)-1].tag != tag[1:] {
+				return fmt.Errorf("text: unexpected markup tag [%s]", tag)
+			}
+			last := spans[len(spans)-1]
+			spans = spans[:len(spans)-1]
+			txt.Color, txt.Scale, txt.Style = last.color, last.scale, last.style
+			continue
+		}
+
+		name, value := tag, ""
+		if eq := strings.IndexByte(tag, '='); eq >= 0 {
+			name, value = tag[:eq], tag[eq+1:]
+		}
+		spans = append(spans, span{name, txt.Color, txt.Scale, txt.Style})
+
+		switch {
+		case name == "color":
+			col, err := parseColor(value)
+			if err != nil {
+				return err
+			}
+			txt.Color = col
+		case name == "scale":
+			scale, err := strconv.ParseFloat(value, 64)
+			if err != nil || scale <= 0 {
+				return fmt.Errorf("text: invalid markup scale %q", value)
+			}
+			txt.Scale *= scale
+		case name == "b" && name == tag:
+			txt.Style |= Bold
+		case name == "i" && name == tag:
+			txt.Style |= Italic
+		default:
+			return fmt.Errorf("text: unknown markup tag [%s]", tag)
+		}
+	}
+
+	return nil
+}
+
+// parseColor parses a color name from golang.org/x/image/colornames or a hexadecimal color.
+func parseColor(s string) (color.Color, error) {
+	if col, ok := colornames.Map[strings.ToLower(s)]; ok {
+		return col, nil
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if hex == s || (len(hex) != 3 && len(hex) != 6 && len(hex) != 8) {
+		return nil, fmt.Errorf("text: invalid markup color %q", s)
+	}
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	rgba, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("text: invalid markup color %q", s)
+	}
+
+	channel := func(shift uint) float64 {
+		return float64(rgba>>shift&0xff) / 0xff
+	}
+	return pixel.RGB(channel(24), channel(16), channel(8)).Mul(pixel.Alpha(channel(0))), nil
+}
@@ -0,0 +1,123 @@
+package text_test
+
+import (
+	"testing"
+
+	"golang.org/x/image/colornames"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+	"github.com/faiface/pixel/text"
+)
+
+func TestWriteMarkup(t *testing.T) {
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	if err := txt.WriteMarkup("a[color=red]b[color=#00f]c[/color][/color]d"); err != nil {
+		t.Fatalf("txt.WriteMarkup: %v", err)
+	}
+
+	canvas := raster.NewCanvas(pixel.R(0, -5, 28, 15))
+	txt.Draw(canvas, pixel.IM)
+
+	want := []pixel.RGBA{pixel.RGB(1, 1, 1), pixel.ToRGBA(colornames.Red), pixel.RGB(0, 0, 1), pixel.RGB(1, 1, 1)}
+	for i, col := range want {
+		// the color of the opaque pixels of each glyph
+		var got []pixel.RGBA
+		for x := 7 * float64(i); x < 7*float64(i+1); x++ {
+			for y := -5.0; y < 15; y++ {
+				if c := canvas.Color(pixel.V(x, y)); c.A == 1 {
+					got = append(got, c)
+				}
+			}
+		}
+		if len(got) == 0 {
+			t.Errorf("glyph %d: nothing drawn", i)
+			continue
+		}
+		for _, c := range got {
+			if c != col {
+				t.Errorf("glyph %d: drawn with %v, want %v", i, c, col)
+				break
+			}
+		}
+	}
+
+	if got, want := pixel.ToRGBA(txt.Color), pixel.RGB(1, 1, 1); got != want {
+		t.Errorf("txt.Color = %v after the markup, want %v", got, want)
+	}
+}
+
+func TestWriteMarkupScale(t *testing.T) {
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	txt.WriteMarkup("[scale=2]ab[/scale]c")
+
+	if got, want := txt.Dot, pixel.V(35, 0); !eqVectors(got, want) {
+		t.Errorf("txt.Dot = %v, want %v", got, want)
+	}
+
+	plain := text.New(pixel.ZV, text.Atlas7x13)
+	bounds := plain.BoundsOf("ab")
+	if got, want := txt.Bounds().Max.Y, 2*bounds.Max.Y; got != want {
+		t.Errorf("txt.Bounds().Max.Y = %v, want %v", got, want)
+	}
+	if got := txt.Scale; got != 1 {
+		t.Errorf("txt.Scale = %v after the markup, want 1", got)
+	}
+}
+
+func TestWriteMarkupStyle(t *testing.T) {
+	plain := text.New(pixel.ZV, text.Atlas7x13)
+	plain.WriteString("l")
+
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	txt.WriteMarkup("[b][i]l[/i][/b]")
+
+	// bold glyphs are one unit wider and italic ones lean to the right
+	if got, want := txt.Bounds().Max.X, plain.Bounds().Max.X+1; got <= want {
+		t.Errorf("txt.Bounds().Max.X = %v, want more than %v", got, want)
+	}
+	if got, want := txt.Bounds().Min.X, plain.Bounds().Min.X; got >= want {
+		t.Errorf("txt.Bounds().Min.X = %v, want less than %v", got, want)
+	}
+	if got, want := txt.Dot, plain.Dot; !eqVectors(got, want) {
+		t.Errorf("txt.Dot = %v, want %v", got, want)
+	}
+	if got := txt.Style; got != 0 {
+		t.Errorf("txt.Style = %v after the markup, want 0", got)
+	}
+}
+
+func TestWriteMarkupErrors(t *testing.T) {
+	tests := []struct {
+		markup  string
+		written string
+		valid   bool
+	}{
+		{"ab[[c", "ab[c", true},
+		{"ab[b]c", "abc", true},
+		{"ab[u]c", "ab", false},
+		{"ab[b]c[/i]", "abc", false},
+		{"ab[/b]c", "ab", false},
+		{"ab[color=nope]c", "ab", false},
+		{"ab[color=#12345]c", "ab", false},
+		{"ab[scale=-1]c", "ab", false},
+		{"ab[b=1]c", "ab", false},
+		{"ab[bc", "ab", false},
+	}
+	for _, tt := range tests {
+		txt := text.New(pixel.ZV, text.Atlas7x13)
+		err := txt.WriteMarkup(tt.markup)
+		if (err == nil) != tt.valid {
+			t.Errorf("txt.WriteMarkup(%q) = %v", tt.markup, err)
+		}
+
+		want := text.New(pixel.ZV, text.Atlas7x13)
+		want.WriteString(tt.written)
+		if got, want := txt.Dot, want.Dot; !eqVectors(got, want) {
+			t.Errorf("txt.WriteMarkup(%q): txt.Dot = %v, want %v", tt.markup, got, want)
+		}
+		if txt.Style != 0 || txt.Scale != 1 {
+			t.Errorf("txt.WriteMarkup(%q): the style isn't restored", tt.markup)
+		}
+	}
+}
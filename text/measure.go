@@ -0,0 +1,87 @@
+package text
+
+import (
+	"math"
+	"strings"
+
+	"github.com/faiface/pixel"
+)
+
+// LineMetrics describes one line of text measured by an Atlas. The line starts with the dot at
+// the origin.
+type LineMetrics struct {
+	// Bounds is the bounding box of the line excluding whitespace, the same as Text.Bounds would
+	// be. It's a zero rectangle if the line has no visible glyphs.
+	Bounds pixel.Rect
+
+	// Ascent and Descent are the distances by which the drawn glyphs reach above and below the
+	// baseline. Unlike Atlas.Ascent and Atlas.Descent, they depend on the glyphs in the line.
+	Ascent, Descent float64
+
+	// Width is the distance the dot moves by writing the line.
+	Width float64
+
+	// Advances are the distances the dot moves by each rune of the line, including the kerning
+	// against the previous rune. They add up to Width.
+	Advances []float64
+}
+
+// Measure returns the bounding box of s as if it was written to a new Text at the origin, without
+// creating one. This allows computing a layout of the text before drawing it.
+//
+// The lines are LineHeight apart and the tab stops are four spaces apart, as they are by default
+// in a Text. If s has no visible glyphs, a zero rectangle is returned.
+func (a *Atlas) Measure(s string) pixel.Rect {
+	var bounds pixel.Rect
+	for i, line := range a.MeasureLines(s) {
+		if line.Bounds.W()*line.Bounds.H() == 0 {
+			continue
+		}
+		bounds = unionBounds(bounds, line.Bounds.Moved(pixel.V(0, -float64(i)*a.LineHeight())))
+	}
+	return bounds
+}
+
+// MeasureLines returns the metrics of each line of s, as if it was written to a new Text at the
+// origin like in Measure. The lines are split by newlines, so there's always at least one line. A
+// carriage return moves the dot back to the start of the line.
+//
+//   for _, line := range atlas.MeasureLines("Score\n1250") {
+//       fmt.Println(line.Width)
+//   }
+func (a *Atlas) MeasureLines(s string) []LineMetrics {
+	tabWidth := a.Glyph(' ').Advance * 4
+
+	var lines []LineMetrics
+	for _, text := range strings.Split(s, "\n") {
+		var line LineMetrics
+		dot := pixel.ZV
+		prevR := rune(-1)
+		for _, r := range text {
+			start := dot.X
+			switch r {
+			case '\r':
+				dot.X = 0
+				prevR = -1
+			case '\t':
+				if tabWidth > 0 {
+					dot.X = (math.Floor(dot.X/tabWidth) + 1) * tabWidth
+				}
+				prevR = -1
+			default:
+				var rect, bounds pixel.Rect
+				rect, _, bounds, dot = a.DrawRune(prevR, r, dot)
+				if bounds.W()*bounds.H() != 0 {
+					line.Bounds = unionBounds(line.Bounds, bounds)
+					line.Ascent = math.Max(line.Ascent, rect.Max.Y-a.spread)
+					line.Descent = math.Max(line.Descent, a.spread-rect.Min.Y)
+				}
+				prevR = r
+			}
+			line.Advances = append(line.Advances, dot.X-start)
+		}
+		line.Width = dot.X
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,86 @@
+package text_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+)
+
+func TestMeasure(t *testing.T) {
+	for _, s := range []string{"", " ", "Hello", "Hello, world!", "two\nlines", "\n\nlow", "over\rwrite", "trailing  \n", "tab\tstops\t\tx"} {
+		want := text.New(pixel.ZV, text.Atlas7x13).BoundsOf(s)
+		if got := text.Atlas7x13.Measure(s); got != want {
+			t.Errorf("Measure(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestMeasureLines(t *testing.T) {
+	lines := text.Atlas7x13.MeasureLines("ab\n\nX,y")
+	if len(lines) != 3 {
+		t.Fatalf("%d lines, want 3", len(lines))
+	}
+
+	if got, want := lines[0].Width, 14.0; got != want {
+		t.Errorf("width %v, want %v", got, want)
+	}
+	if got, want := lines[0].Advances, []float64{7, 7}; !equalFloats(got, want) {
+		t.Errorf("advances %v, want %v", got, want)
+	}
+
+	if lines[1].Width != 0 || len(lines[1].Advances) != 0 || lines[1].Bounds != (pixel.Rect{}) {
+		t.Errorf("empty line measured as %+v", lines[1])
+	}
+
+	// the glyphs of basicfont fill the whole line
+	if lines[2].Ascent != text.Atlas7x13.Ascent() || lines[2].Descent != text.Atlas7x13.Descent() {
+		t.Errorf("ascent %v and descent %v, want the atlas's", lines[2].Ascent, lines[2].Descent)
+	}
+}
+
+func TestMeasureLinesGlyphs(t *testing.T) {
+	// 'B' reaches two below the baseline
+	desc := strings.Replace(bmFontText, "xoffset=1 yoffset=1", "xoffset=1 yoffset=3", 1)
+	fnt, err := text.LoadBMFont(strings.NewReader(desc), bmFontPage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	atlas := text.NewAtlas(fnt, fnt.Runes())
+
+	lines := atlas.MeasureLines("A\nAB\n ")
+	if lines[0].Ascent != 4 || lines[0].Descent != 0 {
+		t.Errorf("A: ascent %v and descent %v, want 4 and 0", lines[0].Ascent, lines[0].Descent)
+	}
+	if lines[1].Ascent != 4 || lines[1].Descent != 2 {
+		t.Errorf("AB: ascent %v and descent %v, want 4 and 2", lines[1].Ascent, lines[1].Descent)
+	}
+	if lines[2].Ascent != 0 || lines[2].Descent != 0 || lines[2].Width != 2 {
+		t.Errorf("space measured as %+v, want the width 2 only", lines[2])
+	}
+	// kerned by -1
+	if got, want := lines[1].Advances, []float64{4, 4}; !equalFloats(got, want) {
+		t.Errorf("AB: advances %v, want %v", got, want)
+	}
+}
+
+func TestMeasureTab(t *testing.T) {
+	tab := text.Atlas7x13.Glyph(' ').Advance * 4
+	lines := text.Atlas7x13.MeasureLines("a\tb\t\t")
+	if got, want := lines[0].Advances, []float64{7, tab - 7, 7, tab - 7, tab}; !equalFloats(got, want) {
+		t.Errorf("advances %v, want %v", got, want)
+	}
+}
+
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package text
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Path is a polyline along which text can be written with WriteAlong. Curves can be approximated
+// by a Path with enough points.
+type Path []pixel.Vec
+
+// ArcPath returns a Path along a circular arc of the radius around the center, going from the
+// start angle to the end angle (counterclockwise if start<end). Text written along it is on the
+// outside of the circle if it goes clockwise and on the inside otherwise.
+//
+//   // a label around the top half of a circle
+//   path := text.ArcPath(center, 100, math.Pi, 0)
+func ArcPath(center pixel.Vec, radius, start, end float64) Path {
+	// one point every 2 degrees is smooth enough for glyphs
+	num := int(math.Max(1, math.Ceil(math.Abs(end-start)/(math.Pi/90))))
+	path := make(Path, num+1)
+	for i := range path {
+		sin, cos := math.Sincos(start + (end-start)*float64(i)/float64(num))
+		path[i] = center.Add(pixel.V(radius*cos, radius*sin))
+	}
+	return path
+}
+
+// Len returns the length of the Path.
+func (p Path) Len() float64 {
+	length := 0.0
+	for i := 0; i+1 < len(p); i++ {
+		length += p[i].To(p[i+1]).Len()
+	}
+	return length
+}
+
+// At returns the position at the distance along the Path and the angle of the Path's direction
+// there. Beyond the ends of the Path, its first and last segment are extended.
+func (p Path) At(dist float64) (pos pixel.Vec, angle float64) {
+	switch len(p) {
+	case 0:
+		return pixel.ZV, 0
+	case 1:
+		return p[0].Add(pixel.V(dist, 0)), 0
+	}
+	for i := 0; i+1 < len(p); i++ {
+		seg := p[i].To(p[i+1])
+		if seg.Len() == 0 {
+			continue
+		}
+		if dist <= seg.Len() || i+2 == len(p) {
+			return p[i].Add(seg.Unit().Scaled(dist)), seg.Angle()
+		}
+		dist -= seg.Len()
+	}
+	// all segments are zero length
+	return p[0], 0
+}
+
+// PathStyle specifies how WriteAlong lays the glyphs along a Path.
+type PathStyle struct {
+	// Offset is the distance along the Path where the text starts.
+	Offset float64
+
+	// Spacing is an extra distance between two subsequent glyphs. Negative values move the glyphs
+	// closer together.
+	Spacing float64
+
+	// Baseline moves the baseline of the text perpendicularly off the Path. Positive values move
+	// it to the left of the Path's direction, that is up for a Path going right.
+	Baseline float64
+
+	// Upright keeps the glyphs unrotated, only their positions follow the Path. Otherwise, each
+	// glyph is rotated along the Path's direction at its center.
+	Upright bool
+}
+
+// WriteAlong writes the string along the Path with the specified style. The Dot is not moved and
+// control runes (such as newlines) are ignored. The distance along the Path where the text ended
+// is returned, so that more text can be written after it:
+//
+//   end := txt.WriteAlong(path, text.PathStyle{}, "Hello, ")
+//   txt.Color = colornames.Red
+//   txt.WriteAlong(path, text.PathStyle{Offset: end}, "world!")
+//
+// Text written along a Path is drawn just like any other text written to the Text.
+func (txt *Text) WriteAlong(path Path, style PathStyle, s string) (end float64) {
+	rgba := pixel.ToRGBA(txt.Color)
+
+	// the glyphs along the Path are never wrapped
+	txt.lay.endWord()
+
+	dist := style.Offset
+	prevR := rune(-1)
+	for _, r := range txt.shape([]rune(s)) {
+		if _, control := txt.controlRune(r, pixel.ZV); control {
+			continue
+		}
+
+		// lay the glyph out on a horizontal line with the dot at the origin first and then move
+		// its center to the Path
+		rect, frame, bounds, newDot := txt.drawRune(prevR, r, pixel.ZV)
+		prevR = r
+
+		center, angle := path.At(dist + newDot.X/2)
+		if style.Upright {
+			angle = 0
+		}
+		center = center.Add(pixel.V(0, style.Baseline).Rotated(angle))
+		matrix := pixel.IM.Moved(pixel.V(-newDot.X/2, 0)).Rotated(pixel.ZV, angle).Moved(center)
+
+		dist += newDot.X + style.Spacing
+
+		info := glyphInfo{fixed: true}
+		if bounds.W()*bounds.H() != 0 {
+			info.bounds = transformedBounds(bounds, matrix)
+			txt.lay.add(info.bounds)
+		}
+		txt.setGlyphColor(r, rgba)
+		txt.appendStyled(rect, frame, pixel.ZV, matrix, info)
+	}
+
+	return dist
+}
+
+// transformedBounds returns the bounds of the rectangle transformed by the matrix.
+func transformedBounds(r pixel.Rect, matrix pixel.Matrix) pixel.Rect {
+	min := pixel.V(math.Inf(+1), math.Inf(+1))
+	max := pixel.V(math.Inf(-1), math.Inf(-1))
+	for _, v := range [...]pixel.Vec{r.Min, pixel.V(r.Max.X, r.Min.Y), r.Max, pixel.V(r.Min.X, r.Max.Y)} {
+		v = matrix.Project(v)
+		min = pixel.V(math.Min(min.X, v.X), math.Min(min.Y, v.Y))
+		max = pixel.V(math.Max(max.X, v.X), math.Max(max.Y, v.Y))
+	}
+	return pixel.Rect{Min: min, Max: max}
+}
@@ -0,0 +1,71 @@
+package text_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+)
+
+func TestPathAt(t *testing.T) {
+	path := text.Path{pixel.V(0, 0), pixel.V(10, 0), pixel.V(10, 10)}
+
+	if got := path.Len(); got != 20 {
+		t.Errorf("Len() = %v, want 20", got)
+	}
+
+	tests := []struct {
+		dist  float64
+		pos   pixel.Vec
+		angle float64
+	}{
+		{-5, pixel.V(-5, 0), 0},
+		{5, pixel.V(5, 0), 0},
+		{15, pixel.V(10, 5), math.Pi / 2},
+		{25, pixel.V(10, 15), math.Pi / 2},
+	}
+	for _, tt := range tests {
+		pos, angle := path.At(tt.dist)
+		if pos.To(tt.pos).Len() > 1e-9 || math.Abs(angle-tt.angle) > 1e-9 {
+			t.Errorf("At(%v) = %v, %v, want %v, %v", tt.dist, pos, angle, tt.pos, tt.angle)
+		}
+	}
+}
+
+func TestArcPath(t *testing.T) {
+	path := text.ArcPath(pixel.V(10, 10), 50, 0, math.Pi)
+	if got, want := path.Len(), 50*math.Pi; math.Abs(got-want) > 0.1 {
+		t.Errorf("Len() = %v, want %v", got, want)
+	}
+	if got, want := path[len(path)-1], pixel.V(-40, 10); got.To(want).Len() > 1e-9 {
+		t.Errorf("last point = %v, want %v", got, want)
+	}
+}
+
+func TestWriteAlong(t *testing.T) {
+	// along a horizontal Path, the text is laid out just like when written normally
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	want := txt.BoundsOf("Hello")
+	end := txt.WriteAlong(text.Path{pixel.V(0, 0), pixel.V(100, 0)}, text.PathStyle{}, "Hello")
+	if got := txt.Bounds(); got.Min.To(want.Min).Len() > 1e-9 || got.Max.To(want.Max).Len() > 1e-9 {
+		t.Errorf("horizontal Bounds() = %v, want %v", got, want)
+	}
+	if end != 35 {
+		t.Errorf("horizontal end = %v, want 35", end)
+	}
+	if txt.Dot != pixel.ZV {
+		t.Errorf("Dot = %v, want it unchanged", txt.Dot)
+	}
+
+	// along a vertical Path, the text is rotated
+	txt.Clear()
+	end = txt.WriteAlong(text.Path{pixel.V(0, 0), pixel.V(0, 100)}, text.PathStyle{Offset: 10, Spacing: 1}, "Hello")
+	got := txt.Bounds()
+	if got.Min.Y < 9.99 || got.Max.Y > 10+39+0.01 || got.Max.X > txt.Atlas().Descent()+0.01 {
+		t.Errorf("vertical Bounds() = %v, want the text going up from 10 with its top to the left", got)
+	}
+	if end != 10+35+5 {
+		t.Errorf("vertical end = %v, want %v", end, 10+35+5)
+	}
+}
@@ -0,0 +1,184 @@
+package text
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/font"
+)
+
+// NewSDFAtlas creates a new Atlas like NewAtlas, but instead of the glyphs themselves, its Picture
+// contains their signed distance fields. Text drawn with an SDF Atlas stays sharp at any scale and
+// rotation, but it must be drawn with SDFFragmentShader.
+//
+// The alpha of the Picture is 0.5 at the edges of the glyphs and goes linearly to 1 inside and 0
+// outside of them, reaching them at the spread (in pixels of the face) from the edges. The spread
+// must be positive, a few pixels are usually enough. The fields are computed from the glyphs drawn
+// in the size of the face, so use a large face (such as 48 points) and scale the Text down.
+//
+// Here we create a Text, which draws crisp text with a one pixel outline at any camera zoom:
+//   txt := text.New(orig, text.NewSDFAtlas(face, 6, text.ASCII))
+//   txt.Scale = 0.25
+//   txt.Outline = 1
+//
+//   canvas.SetSmooth(true)
+//   canvas.SetFragmentShader(text.SDFFragmentShader)
+//   txt.Draw(canvas, cam)
+func NewSDFAtlas(face font.Face, spread int, runeSets ...[]rune) *Atlas {
+	if spread <= 0 {
+		panic(errors.New("NewSDFAtlas: spread must be positive"))
+	}
+	return newAtlas(face, AtlasOptions{}, spread, nil, runeSets)
+}
+
+// Spread returns the distance by which the signed distance fields reach out of the glyphs of an
+// SDF Atlas, or zero if the Atlas is not an SDF Atlas.
+func (a *Atlas) Spread() float64 {
+	return a.spread
+}
+
+// SDFFragmentShader is a fragment shader for a pixelgl.Canvas, which draws the text using an SDF
+// Atlas. The Canvas must be smooth (see SetSmooth), the distance fields are sharpened to an edge
+// antialiased over one pixel of the Canvas.
+//
+// Triangles with zero Intensity are drawn the same way as with the default shader, so other simple
+// shapes can be drawn with it as well.
+const SDFFragmentShader = `
+#version 330 core
+
+in vec4  vColor;
+in vec2  vTexCoords;
+in float vIntensity;
+
+out vec4 fragColor;
+
+uniform vec4 uColorMask;
+uniform vec4 uTexBounds;
+uniform sampler2D uTexture;
+
+void main() {
+	if (vIntensity == 0.0) {
+		fragColor = uColorMask * vColor;
+	} else {
+		vec2 t = (vTexCoords - uTexBounds.xy) / uTexBounds.zw;
+		float dist = texture(uTexture, t).a;
+		float width = max(fwidth(dist), 1e-4);
+		float alpha = smoothstep(0.5 - width/2.0, 0.5 + width/2.0, dist);
+		fragColor = mix(vColor, vColor * alpha, vIntensity) * uColorMask;
+	}
+}
+`
+
+// distanceField replaces the glyphs drawn in the image with their signed distance fields reaching
+// the spread from the edges. Pixels with at least half alpha are inside the glyphs.
+func distanceField(img *image.RGBA, spread int) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	inside := make([]bool, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			inside[y*w+x] = img.RGBAAt(b.Min.X+x, b.Min.Y+y).A >= 0x80
+		}
+	}
+
+	toInside := squaredDistances(w, h, func(i int) bool { return inside[i] })
+	toOutside := squaredDistances(w, h, func(i int) bool { return !inside[i] })
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			// the edges are half-way between the centers of the inside and outside pixels
+			var dist float64
+			if inside[i] {
+				dist = math.Sqrt(toOutside[i]) - 0.5
+			} else {
+				dist = 0.5 - math.Sqrt(toInside[i])
+			}
+			alpha := math.Max(0, math.Min(1, 0.5+dist/(2*float64(spread))))
+			a := uint8(math.Round(alpha * 0xff))
+			img.SetRGBA(b.Min.X+x, b.Min.Y+y, color.RGBA{a, a, a, a})
+		}
+	}
+}
+
+// squaredDistances returns the squared Euclidean distances from each pixel of a w×h image to the
+// nearest pixel, for which the feature function is true.
+//
+// It's the linear-time algorithm by Felzenszwalb and Huttenlocher, Distance Transforms of Sampled
+// Functions, which transforms the columns and then the rows.
+func squaredDistances(w, h int, feature func(i int) bool) []float64 {
+	// large enough to be farther than any pixel, but still finite to avoid Inf-Inf
+	const far = 1e20
+
+	dist := make([]float64, w*h)
+	for i := range dist {
+		if !feature(i) {
+			dist[i] = far
+		}
+	}
+
+	n := w
+	if h > n {
+		n = h
+	}
+	f := make([]float64, n)
+	d := make([]float64, n)
+	v := make([]int, n)
+	z := make([]float64, n+1)
+
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			f[y] = dist[y*w+x]
+		}
+		distances1D(f[:h], d[:h], v, z)
+		for y := 0; y < h; y++ {
+			dist[y*w+x] = d[y]
+		}
+	}
+	for y := 0; y < h; y++ {
+		copy(f, dist[y*w:(y+1)*w])
+		distances1D(f[:w], d[:w], v, z)
+		copy(dist[y*w:(y+1)*w], d[:w])
+	}
+
+	return dist
+}
+
+// distances1D computes the one-dimensional distance transform of f into d, as the lower envelope
+// of the parabolas rooted at each f. The v and z are buffers at least as long as f plus one.
+func distances1D(f, d []float64, v []int, z []float64) {
+	if len(f) == 0 {
+		return
+	}
+
+	// intersection of the parabolas rooted at p and q
+	intersection := func(p, q int) float64 {
+		fp, fq := f[p]+float64(p*p), f[q]+float64(q*q)
+		return (fq - fp) / float64(2*q-2*p)
+	}
+
+	k := 0
+	v[0] = 0
+	z[0], z[1] = math.Inf(-1), math.Inf(+1)
+	for q := 1; q < len(f); q++ {
+		s := intersection(v[k], q)
+		for s <= z[k] {
+			k--
+			s = intersection(v[k], q)
+		}
+		k++
+		v[k] = q
+		z[k], z[k+1] = s, math.Inf(+1)
+	}
+
+	k = 0
+	for q := range f {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		d[q] = float64((q-v[k])*(q-v[k])) + f[v[k]]
+	}
+}
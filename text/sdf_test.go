@@ -0,0 +1,58 @@
+package text_test
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/basicfont"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+)
+
+func TestSDFAtlas(t *testing.T) {
+	const spread = 3
+	atlas := text.NewSDFAtlas(basicfont.Face7x13, spread, text.ASCII)
+
+	if got := atlas.Spread(); got != spread {
+		t.Errorf("atlas.Spread() = %v, want %v", got, spread)
+	}
+	if got := text.Atlas7x13.Spread(); got != 0 {
+		t.Errorf("text.Atlas7x13.Spread() = %v, want 0", got)
+	}
+
+	sdfPic := atlas.Picture().(pixel.PictureColor)
+	pic := text.Atlas7x13.Picture().(pixel.PictureColor)
+
+	for _, r := range "aHg@ " {
+		rect, frame, bounds, dot := text.Atlas7x13.DrawRune(-1, r, pixel.V(10, 20))
+		sdfRect, sdfFrame, sdfBounds, sdfDot := atlas.DrawRune(-1, r, pixel.V(10, 20))
+
+		// the glyphs are the same, except for the distance field around them
+		if rect.Area() > 0 {
+			rect = rect.Resized(rect.Center(), rect.Size().Add(pixel.V(2*spread, 2*spread)))
+		}
+		if sdfRect != rect {
+			t.Errorf("%q: SDF rect = %v, want %v", r, sdfRect, rect)
+		}
+		if sdfBounds != bounds || sdfDot != dot {
+			t.Errorf("%q: SDF bounds and dot = %v, %v, want %v, %v", r, sdfBounds, sdfDot, bounds, dot)
+		}
+
+		// the pixels inside and outside of the glyph are the same as in the bitmap
+		for x := frame.Min.X; x < frame.Max.X; x++ {
+			for y := frame.Min.Y; y < frame.Max.Y; y++ {
+				at := pixel.V(x+0.5, y+0.5)
+				sdfAt := at.Sub(frame.Min).Add(sdfFrame.Min).Add(pixel.V(spread, spread))
+				in := pic.Color(at).A >= 0.5
+				if sdfIn := sdfPic.Color(sdfAt).A >= 0.5; sdfIn != in {
+					t.Errorf("%q: SDF at %v inside is %v, want %v", r, at.Sub(frame.Min), sdfIn, in)
+				}
+			}
+		}
+		if sdfFrame.Area() > 0 {
+			if got := sdfPic.Color(sdfFrame.Min.Add(pixel.V(0.5, 0.5))).A; got != 0 {
+				t.Errorf("%q: SDF at the corner = %v, want 0", r, got)
+			}
+		}
+	}
+}
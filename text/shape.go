@@ -0,0 +1,452 @@
+package text
+
+import (
+	"unicode"
+
+	"github.com/faiface/pixel"
+)
+
+// Shaper transforms the text written to a Text into the runes to draw, before the text is laid
+// out. Shaping replaces the runes by the glyphs of their context (such as the joining forms of the
+// Arabic letters, or ligatures) and reorders the right-to-left runs of the text for display.
+//
+// The shaped glyphs are runes drawn from the Atlas one after another, so a Shaper can't position
+// glyphs or use glyphs that have no rune, which the Indic and other complex scripts need. Pixel
+// doesn't include a shaping engine for them. BasicShaper is a simple Shaper without any
+// dependencies for the Arabic and Hebrew scripts.
+type Shaper interface {
+	// Shape returns the runes to draw for one line of text, in the order from left to right. The
+	// line contains no newlines.
+	Shape(line []rune) []rune
+}
+
+// BidiShaper is a Shaper which shapes the text and resolves its directions separately, so that
+// the lines wrapped by MaxWidth are reordered for display one by one, as the Unicode Bidirectional
+// Algorithm requires (rule L2). A line of text is a paragraph, which is shaped and wrapped in the
+// logical order (the order it's written in) first, then each wrapped line is reordered by the
+// levels of its runes.
+//
+// Without MaxWidth, a Text shapes each line by Shape.
+type BidiShaper interface {
+	Shaper
+
+	// ShapeLogical returns the shaped runes of one line of text, in the logical order. The line
+	// contains no newlines.
+	ShapeLogical(line []rune) []rune
+
+	// Levels returns the embedding level of each rune of a line returned by ShapeLogical. The
+	// runes of odd levels are right-to-left, the runs of the higher levels are nested inside of
+	// the runs of the lower ones.
+	Levels(line []rune) []int
+}
+
+// shapeLines shapes each line of the runes by the function.
+func shapeLines(runes []rune, shape func([]rune) []rune) []rune {
+	var shaped []rune
+	for len(runes) > 0 {
+		end := 0
+		for end < len(runes) && runes[end] != '\n' && runes[end] != '\r' {
+			end++
+		}
+		shaped = append(shaped, shape(runes[:end])...)
+		if end < len(runes) {
+			shaped = append(shaped, runes[end])
+			end++
+		}
+		runes = runes[end:]
+	}
+	return shaped
+}
+
+// shape shapes each line of the runes by the Shaper, if there's one.
+func (txt *Text) shape(runes []rune) []rune {
+	if txt.Shaper == nil {
+		return runes
+	}
+	return shapeLines(runes, txt.Shaper.Shape)
+}
+
+// shapeWrapped shapes the runes like shape, but if the Shaper is a BidiShaper and the lines are
+// wrapped by MaxWidth, it wraps them in the logical order starting from the layout, and reorders
+// each wrapped line. Then breaks is true for the runes starting the wrapped lines, which must be
+// laid out with the wrapping by MaxWidth turned off, see layout.fixedBreaks.
+func (txt *Text) shapeWrapped(l layout, runes []rune) (shaped []rune, breaks []bool) {
+	bs, ok := txt.Shaper.(BidiShaper)
+	if !ok || txt.MaxWidth <= 0 {
+		return txt.shape(runes), nil
+	}
+	logical := shapeLines(runes, bs.ShapeLogical)
+	wrapped := txt.wrapBreaks(l, logical)
+
+	shaped = make([]rune, 0, len(logical))
+	breaks = make([]bool, 0, len(logical))
+	for start := 0; start < len(logical); {
+		end := start
+		for end < len(logical) && logical[end] != '\n' && logical[end] != '\r' {
+			end++
+		}
+		line := logical[start:end]
+		levels := bs.Levels(line)
+		for i := 0; i < len(line); {
+			j := i + 1
+			for j < len(line) && !wrapped[start+j] {
+				j++
+			}
+			shaped = append(shaped, reorderLevels(line[i:j], levels[i:j])...)
+			breaks = append(breaks, wrapped[start+i])
+			for k := i + 1; k < j; k++ {
+				breaks = append(breaks, false)
+			}
+			i = j
+		}
+		if end < len(logical) {
+			shaped = append(shaped, logical[end])
+			breaks = append(breaks, false)
+			end++
+		}
+		start = end
+	}
+	return shaped, breaks
+}
+
+// wrapBreaks lays out the runes from a copy of the layout and returns which of them start a line
+// by wrapping.
+func (txt *Text) wrapBreaks(l layout, runes []rune) []bool {
+	breaks := make([]bool, len(runes))
+	wordStart := 0
+	for i, r := range runes {
+		g, ok := txt.layoutRune(&l, r)
+		if !ok {
+			continue
+		}
+		if g.newWord {
+			wordStart = i
+		}
+		switch {
+		case g.wordMoved != pixel.ZV:
+			breaks[wordStart] = true
+		case g.info.wrapped:
+			breaks[i] = true
+		}
+	}
+	return breaks
+}
+
+// BasicShaper is a BidiShaper for the Arabic and Hebrew scripts. It replaces the Arabic letters by
+// their joining forms and the lam-alef ligatures from the Arabic Presentation Forms-B block, so an
+// Atlas must contain these, for example by including RangeTable(unicode.Arabic). Then it reorders
+// the right-to-left runs of each line for display, by a simplified Unicode Bidirectional
+// Algorithm: the direction of the paragraph is given by its first strong rune and the numbers are
+// kept left to right.
+//
+// BasicShaper doesn't support the scripts which need glyph reordering or positioning, such as the
+// Indic scripts.
+type BasicShaper struct{}
+
+// Shape shapes one line of the text.
+func (BasicShaper) Shape(line []rune) []rune {
+	joined := joinArabic(line)
+	return reorderLevels(joined, bidiLevels(joined))
+}
+
+// ShapeLogical replaces the Arabic letters of one line of the text by their joining forms.
+func (BasicShaper) ShapeLogical(line []rune) []rune {
+	return joinArabic(line)
+}
+
+// Levels returns the embedding levels of the runes of one line of the text.
+func (BasicShaper) Levels(line []rune) []int {
+	return bidiLevels(line)
+}
+
+// arabicForm describes an Arabic letter by its isolated form in the Arabic Presentation Forms-B.
+// The final form follows the isolated one and dual-joining letters continue with the initial and
+// the medial forms.
+type arabicForm struct {
+	isolated rune
+	dual     bool
+}
+
+const (
+	arabicTatweel = 'ـ'
+	arabicLam     = 'ل'
+)
+
+var arabicForms = map[rune]arabicForm{
+	'ء': {'ﺀ', false}, // hamza, doesn't join at all
+	'آ': {'ﺁ', false},
+	'أ': {'ﺃ', false},
+	'ؤ': {'ﺅ', false},
+	'إ': {'ﺇ', false},
+	'ئ': {'ﺉ', true},
+	'ا': {'ﺍ', false},
+	'ب': {'ﺏ', true},
+	'ة': {'ﺓ', false},
+	'ت': {'ﺕ', true},
+	'ث': {'ﺙ', true},
+	'ج': {'ﺝ', true},
+	'ح': {'ﺡ', true},
+	'خ': {'ﺥ', true},
+	'د': {'ﺩ', false},
+	'ذ': {'ﺫ', false},
+	'ر': {'ﺭ', false},
+	'ز': {'ﺯ', false},
+	'س': {'ﺱ', true},
+	'ش': {'ﺵ', true},
+	'ص': {'ﺹ', true},
+	'ض': {'ﺽ', true},
+	'ط': {'ﻁ', true},
+	'ظ': {'ﻅ', true},
+	'ع': {'ﻉ', true},
+	'غ': {'ﻍ', true},
+	'ف': {'ﻑ', true},
+	'ق': {'ﻕ', true},
+	'ك': {'ﻙ', true},
+	'ل': {'ﻝ', true},
+	'م': {'ﻡ', true},
+	'ن': {'ﻥ', true},
+	'ه': {'ﻩ', true},
+	'و': {'ﻭ', false},
+	'ى': {'ﻯ', false},
+	'ي': {'ﻱ', true},
+}
+
+// lamAlef maps the alefs to the isolated forms of their ligatures with lam.
+var lamAlef = map[rune]rune{
+	'آ': 'ﻵ',
+	'أ': 'ﻷ',
+	'إ': 'ﻹ',
+	'ا': 'ﻻ',
+}
+
+// joinsLeft reports whether the rune joins the next letter (to its left).
+func joinsLeft(r rune) bool {
+	return r == arabicTatweel || arabicForms[r].dual
+}
+
+// joinsRight reports whether the rune joins the previous letter (to its right).
+func joinsRight(r rune) bool {
+	_, ok := arabicForms[r]
+	return (ok && r != 'ء') || r == arabicTatweel
+}
+
+// transparent reports whether the rune is a mark, which doesn't break the joining of letters.
+func transparent(r rune) bool {
+	return unicode.Is(unicode.Mn, r)
+}
+
+// joinArabic replaces the Arabic letters by their joining forms.
+func joinArabic(line []rune) []rune {
+	// the neighbouring letters skipping the marks
+	neighbour := func(i, dir int) rune {
+		for i += dir; i >= 0 && i < len(line); i += dir {
+			if !transparent(line[i]) {
+				return line[i]
+			}
+		}
+		return 0
+	}
+
+	joined := make([]rune, 0, len(line))
+	for i := 0; i < len(line); i++ {
+		r := line[i]
+		form, ok := arabicForms[r]
+		if !ok {
+			joined = append(joined, r)
+			continue
+		}
+
+		prev := joinsRight(r) && joinsLeft(neighbour(i, -1))
+		if r == arabicLam && i+1 < len(line) {
+			if lig, ok := lamAlef[line[i+1]]; ok {
+				if prev {
+					lig++
+				}
+				joined = append(joined, lig)
+				i++
+				continue
+			}
+		}
+		next := form.dual && joinsRight(neighbour(i, +1))
+
+		switch {
+		case prev && next:
+			joined = append(joined, form.isolated+3)
+		case next:
+			joined = append(joined, form.isolated+2)
+		case prev:
+			joined = append(joined, form.isolated+1)
+		default:
+			joined = append(joined, form.isolated)
+		}
+	}
+	return joined
+}
+
+// bidiClass is a simplified bidirectional class of a rune.
+type bidiClass int
+
+const (
+	bidiNeutral bidiClass = iota
+	bidiLTR
+	bidiRTL
+	bidiNumber
+)
+
+func classify(r rune) bidiClass {
+	switch {
+	case unicode.IsDigit(r):
+		return bidiNumber
+	case unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana):
+		if transparent(r) {
+			return bidiNeutral
+		}
+		return bidiRTL
+	case unicode.IsLetter(r):
+		return bidiLTR
+	}
+	return bidiNeutral
+}
+
+// mirrored maps the paired punctuation to its mirror image in right-to-left text.
+var mirrored = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+}
+
+// bidiLevels returns the embedding levels of the runes of the line, 0 for all of them if there's
+// no right-to-left rune.
+func bidiLevels(line []rune) []int {
+	levels := make([]int, len(line))
+	classes := make([]bidiClass, len(line))
+	rtl := false
+	hasRTL := false
+	for i, r := range line {
+		classes[i] = classify(r)
+		if classes[i] == bidiRTL {
+			hasRTL = true
+		}
+	}
+	if !hasRTL {
+		return levels
+	}
+	for _, c := range classes {
+		if c == bidiLTR || c == bidiRTL {
+			rtl = c == bidiRTL
+			break
+		}
+	}
+
+	base := bidiLTR
+	if rtl {
+		base = bidiRTL
+	}
+
+	// the numbers after left-to-right text are a part of it
+	last := base
+	for i, c := range classes {
+		switch c {
+		case bidiLTR, bidiRTL:
+			last = c
+		case bidiNumber:
+			if last == bidiLTR {
+				classes[i] = bidiLTR
+			}
+		}
+	}
+
+	// the other numbers act as right-to-left for the neutrals around them
+	strong := func(c bidiClass) bidiClass {
+		if c == bidiNumber {
+			return bidiRTL
+		}
+		return c
+	}
+
+	for i := 0; i < len(line); {
+		if classes[i] != bidiNeutral {
+			i++
+			continue
+		}
+		// the neutrals take the direction of their surroundings if it's the same on both sides
+		end := i
+		for end < len(line) && classes[end] == bidiNeutral {
+			end++
+		}
+		before, after := base, base
+		if i > 0 {
+			before = strong(classes[i-1])
+		}
+		if end < len(line) {
+			after = strong(classes[end])
+		}
+		dir := base
+		if before == after {
+			dir = before
+		}
+		for j := i; j < end; j++ {
+			classes[j] = dir
+		}
+		i = end
+	}
+
+	for i, c := range classes {
+		switch {
+		case c == bidiRTL:
+			levels[i] = 1
+		case c == bidiNumber, c == bidiLTR && rtl:
+			levels[i] = 2
+		}
+	}
+	return levels
+}
+
+// reorderLevels reorders one displayed line from the logical to the visual order by the levels of
+// its runes: from the highest level to the lowest odd one, the runs at that level or higher are
+// reversed. The paired punctuation of the right-to-left runes is mirrored.
+func reorderLevels(line []rune, levels []int) []rune {
+	highest, lowestOdd := 0, -1
+	for _, level := range levels {
+		if level > highest {
+			highest = level
+		}
+		if level%2 == 1 && (lowestOdd < 0 || level < lowestOdd) {
+			lowestOdd = level
+		}
+	}
+	if lowestOdd < 0 {
+		return line
+	}
+
+	visual := append([]rune(nil), line...)
+	levels = append([]int(nil), levels...)
+	for level := highest; level >= lowestOdd; level-- {
+		for i := 0; i < len(visual); {
+			if levels[i] < level {
+				i++
+				continue
+			}
+			end := i
+			for end < len(visual) && levels[end] >= level {
+				end++
+			}
+			for a, b := i, end-1; a < b; a, b = a+1, b-1 {
+				visual[a], visual[b] = visual[b], visual[a]
+				levels[a], levels[b] = levels[b], levels[a]
+			}
+			i = end
+		}
+	}
+
+	for i, r := range visual {
+		if levels[i]%2 == 1 {
+			if m, ok := mirrored[r]; ok {
+				visual[i] = m
+			}
+		}
+	}
+	return visual
+}
@@ -0,0 +1,146 @@
+package text_test
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+	"github.com/faiface/pixel/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicShaper(t *testing.T) {
+	tests := []struct {
+		line, want string
+	}{
+		{"hello", "hello"},
+		// right-to-left runs are reversed, the numbers and the left-to-right text are kept
+		{"abc אבג def", "abc גבא def"},
+		{"שלום 123!", "!123 םולש"},
+		{"שלום abc 12 עולם", "םלוע abc 12 םולש"},
+		{"(אב)", "(בא)"},
+		// the letters are joined, so beh beh is the initial and the final form
+		{"بب", "ﺐﺑ"},
+		{"ببب", "ﺐﺒﺑ"},
+		// alef doesn't join the next letter
+		{"اب", "ﺏﺍ"},
+		{"با", "ﺎﺑ"},
+		// lam-alef ligature
+		{"لا", "ﻻ"},
+		{"بلا", "ﻼﺑ"},
+	}
+	for _, tt := range tests {
+		if got := string(text.BasicShaper{}.Shape([]rune(tt.line))); got != tt.want {
+			t.Errorf("Shape(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+// dropShaper removes every other rune of the line
+type dropShaper struct{}
+
+func (dropShaper) Shape(line []rune) []rune {
+	var shaped []rune
+	for i := 0; i < len(line); i += 2 {
+		shaped = append(shaped, line[i])
+	}
+	return shaped
+}
+
+func TestShaper(t *testing.T) {
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	txt.Shaper = dropShaper{}
+
+	bounds := txt.BoundsOf("abcd\nabc")
+	fmt.Fprint(txt, "abcd\nabc")
+
+	if got, want := txt.Dot, pixel.V(14, -13); !eqVectors(got, want) {
+		t.Errorf("txt.Dot = %v, want %v", got, want)
+	}
+	if got := txt.Bounds(); got != bounds {
+		t.Errorf("txt.Bounds() = %v, want BoundsOf = %v", got, bounds)
+	}
+}
+
+// upperShaper is a BidiShaper for which the lines starting with an uppercase letter are
+// right-to-left as a whole
+type upperShaper struct{}
+
+func (s upperShaper) Shape(line []rune) []rune {
+	visual := make([]rune, len(line))
+	for i, r := range line {
+		visual[len(line)-1-i] = r
+	}
+	if len(line) > 0 && unicode.IsUpper(line[0]) {
+		return visual
+	}
+	return line
+}
+
+func (upperShaper) ShapeLogical(line []rune) []rune {
+	return line
+}
+
+func (upperShaper) Levels(line []rune) []int {
+	levels := make([]int, len(line))
+	if len(line) > 0 && unicode.IsUpper(line[0]) {
+		for i := range levels {
+			levels[i] = 1
+		}
+	}
+	return levels
+}
+
+// drawnLines draws the text onto a transparent canvas and returns the drawn pixels.
+func drawnLines(txt *text.Text) map[pixel.Vec]bool {
+	canvas := raster.NewCanvas(pixel.R(-10, -40, 60, 20))
+	txt.Draw(canvas, pixel.IM)
+
+	drawn := make(map[pixel.Vec]bool)
+	for x := -10.0; x < 60; x++ {
+		for y := -40.0; y < 20; y++ {
+			if canvas.Color(pixel.V(x, y)).A > 0 {
+				drawn[pixel.V(x, y)] = true
+			}
+		}
+	}
+	return drawn
+}
+
+func TestBidiShaperWrap(t *testing.T) {
+	// the paragraph is wrapped in the logical order, then each line is reordered, so the first
+	// word stays on the first line
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	txt.Shaper = upperShaper{}
+	txt.MaxWidth = 30
+	bounds := txt.BoundsOf("ABC DEF")
+	fmt.Fprint(txt, "ABC DEF")
+
+	want := text.New(pixel.ZV, text.Atlas7x13)
+	fmt.Fprint(want, " CBA\nFED")
+
+	assert.Equal(t, drawnLines(want), drawnLines(txt))
+	assert.Equal(t, want.Dot, txt.Dot)
+	assert.Equal(t, bounds, txt.Bounds())
+
+	// without MaxWidth, the whole line is shaped at once
+	txt = text.New(pixel.ZV, text.Atlas7x13)
+	txt.Shaper = upperShaper{}
+	fmt.Fprint(txt, "ABC DEF")
+
+	want = text.New(pixel.ZV, text.Atlas7x13)
+	fmt.Fprint(want, "FED CBA")
+
+	assert.Equal(t, drawnLines(want), drawnLines(txt))
+}
+
+func TestBasicShaperLevels(t *testing.T) {
+	var s text.BasicShaper
+	assert.Equal(t, []int{0, 0, 0}, s.Levels([]rune("abc")))
+	// a right-to-left paragraph, the left-to-right words and the numbers are nested inside
+	assert.Equal(t, []int{1, 1, 1, 2, 2, 1, 1, 2, 2}, s.Levels([]rune("אב ab ג12")))
+	assert.Equal(t, []rune("ﺐﺑ"), []rune(string(s.Shape([]rune("بب")))))
+	assert.Equal(t, []rune("ﺑﺐ"), s.ShapeLogical([]rune("بب")))
+}
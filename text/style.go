@@ -0,0 +1,73 @@
+package text
+
+import (
+	"github.com/faiface/pixel"
+)
+
+// Style is a set of styles of the text that is to be written, such as Bold|Italic. The styles are
+// synthesized from the glyphs of the Atlas, so they work with any font.
+type Style int
+
+const (
+	// Bold thickens the glyphs by drawing them twice, one unit (scaled by Scale) apart.
+	Bold Style = 1 << iota
+
+	// Italic slants the glyphs to the right around the baseline.
+	Italic
+)
+
+// italicSlant is the horizontal shift of an italic glyph per unit of height above the baseline.
+const italicSlant = 0.2
+
+// drawRune is like Atlas.DrawRune, but the glyph is kerned only if Kerning is enabled, it's scaled
+// by Scale around the dot, followed by the Tracking and the bounds include the Style.
+func (txt *Text) drawRune(prevR, r rune, dot pixel.Vec) (rect, frame, bounds pixel.Rect, newDot pixel.Vec) {
+	if !txt.Kerning {
+		prevR = -1
+	}
+	rect, frame, bounds, newDot = txt.atlas.DrawRune(prevR, r, dot)
+
+	if txt.Scale != 1 {
+		scale := func(v pixel.Vec) pixel.Vec {
+			return dot.Add(dot.To(v).Scaled(txt.Scale))
+		}
+		rect = pixel.Rect{Min: scale(rect.Min), Max: scale(rect.Max)}
+		bounds = pixel.Rect{Min: scale(bounds.Min), Max: scale(bounds.Max)}
+		newDot = scale(newDot)
+	}
+
+	newDot.X += txt.Tracking
+
+	if bounds.W()*bounds.H() != 0 {
+		if txt.Style&Bold != 0 {
+			bounds.Max.X += txt.Scale
+		}
+		if txt.Style&Italic != 0 {
+			bounds = transformedBounds(bounds, txt.styleMatrix(dot))
+		}
+	}
+
+	return rect, frame, bounds, newDot
+}
+
+// styleMatrix returns the Matrix which slants a glyph drawn at the dot if the Style is Italic.
+func (txt *Text) styleMatrix(dot pixel.Vec) pixel.Matrix {
+	if txt.Style&Italic == 0 {
+		return pixel.IM
+	}
+	return pixel.Matrix{1, 0, italicSlant, 1, -italicSlant * dot.Y, 0}
+}
+
+// appendStyled appends the glyph drawn at the dot in the Style and then transformed by the matrix,
+// together with its info. Bold glyphs are appended twice, with an info for each copy.
+func (txt *Text) appendStyled(rect, frame pixel.Rect, dot pixel.Vec, matrix pixel.Matrix, info glyphInfo) {
+	style := txt.styleMatrix(dot).Chained(matrix)
+	txt.appendGlyph(rect, frame, style)
+	txt.infos = append(txt.infos, info)
+
+	if txt.Style&Bold != 0 {
+		txt.appendGlyph(rect.Moved(pixel.V(txt.Scale, 0)), frame, style)
+		info.lineStart, info.wrapped = false, false
+		txt.infos = append(txt.infos, info)
+	}
+}
@@ -56,6 +56,10 @@ func RangeTable(table *unicode.RangeTable) []rune {
 //
 // Newlines, tabs and carriage returns are supported.
 //
+// The Color, Scale and Style fields apply to the text written after they're changed, so a single
+// Text can contain differently styled parts. WriteMarkup changes them within one string:
+//   txt.WriteMarkup("Press [color=yellow]Space[/color] to [b]jump[/b].")
+//
 // Finally, if we want the written text to show up on some other Target, we can draw it:
 //   txt.Draw(target)
 //
@@ -75,6 +79,17 @@ type Text struct {
 	// Color is the color of the text that is to be written. Defaults to white.
 	Color color.Color
 
+	// Scale is the size of the text that is to be written, relative to the size of the Atlas's
+	// font. The glyphs are scaled around the dot, so they stay on the same baseline. LineHeight is
+	// not changed by Scale. Defaults to 1.
+	Scale float64
+
+	// Style is the style of the text that is to be written. Defaults to no style.
+	//
+	// Example:
+	//   txt.Style = text.Bold | text.Italic
+	Style Style
+
 	// LineHeight is the vertical distance between two lines of text.
 	//
 	// Example:
@@ -88,19 +103,100 @@ type Text struct {
 	//   txt.TabWidth = 8 * txt.Atlas().Glyph(' ').Advance
 	TabWidth float64
 
+	// LineSpacing multiplies the LineHeight, which is useful for spacing the lines relative to the
+	// font. Defaults to 1.
+	//
+	// Example:
+	//   txt.LineSpacing = 1.5
+	LineSpacing float64
+
+	// Tracking is the extra horizontal space after each glyph of the text that is to be written.
+	// Positive Tracking spreads the glyphs apart, negative Tracking condenses them. It's not
+	// scaled by Scale. Defaults to zero.
+	//
+	// Example:
+	//   txt.Tracking = 2
+	Tracking float64
+
+	// Kerning enables adjusting the distances between pairs of glyphs by the kerning of the font
+	// face (see Atlas.Kern). Defaults to true.
+	//
+	// Note, that the faces from github.com/golang/freetype/truetype only support the kern table,
+	// the faces from golang.org/x/image/font/opentype support the GPOS kerning as well.
+	Kerning bool
+
+	// MaxWidth is the width at which lines are wrapped, measured from Orig. Lines are wrapped
+	// between words, or inside of a word if it's wider than MaxWidth alone. Zero means that lines
+	// are never wrapped.
+	//
+	// Example:
+	//   txt.MaxWidth = 200
+	//   fmt.Fprint(txt, "This sentence will wrap onto multiple lines.")
+	MaxWidth float64
+
+	// Align is the horizontal alignment of the lines. The lines are aligned when the text is
+	// drawn, so Align can be changed after writing the text too. Defaults to AlignLeft.
+	//
+	// Example:
+	//   txt.MaxWidth = 200
+	//   txt.Align = text.AlignCenter
+	Align Align
+
+	// Outline is the width of an outline drawn around the glyphs in OutlineColor. Zero means no
+	// outline. The outline is made of copies of the glyphs offset in all directions, so it's best
+	// kept to a few units. Like Align, it's applied when the text is drawn.
+	//
+	// Example:
+	//   txt.Outline = 1
+	//   txt.OutlineColor = colornames.Black
+	Outline float64
+
+	// OutlineColor is the color of the outline, multiplied by the opacity of each glyph. Defaults
+	// to black.
+	OutlineColor color.Color
+
+	// Shadow is the offset of a shadow of the glyphs (and their outline) drawn underneath them in
+	// ShadowColor. Zero means no shadow. Like Align, it's applied when the text is drawn.
+	//
+	// Example:
+	//   txt.Shadow = pixel.V(2, -2)
+	Shadow pixel.Vec
+
+	// ShadowColor is the color of the shadow, multiplied by the opacity of each glyph. Defaults to
+	// black.
+	ShadowColor color.Color
+
+	// Shaper shapes the text before it's laid out, if it's not nil. The text is shaped by lines
+	// within each write, so each write should contain whole lines (or at least whole words). With
+	// MaxWidth, a BidiShaper wraps the lines before reordering them, see BidiShaper.
+	//
+	// Example:
+	//   txt := text.New(orig, text.NewAtlas(face, text.ASCII, text.RangeTable(unicode.Arabic)))
+	//   txt.Shaper = text.BasicShaper{}
+	Shaper Shaper
+
 	atlas *Atlas
 
-	buf    []byte
-	prevR  rune
-	bounds pixel.Rect
-	glyph  pixel.TrianglesData
-	tris   pixel.TrianglesData
-
-	mat    pixel.Matrix
-	col    pixel.RGBA
-	trans  pixel.TrianglesData
-	transD pixel.Drawer
-	dirty  bool
+	buf       []byte
+	lay       layout
+	wordStart int
+	glyph     pixel.TrianglesData
+	tris      pixel.TrianglesData
+	infos     []glyphInfo
+
+	mat        pixel.Matrix
+	col        pixel.RGBA
+	align      Align
+	alignWidth float64
+	effects    effects
+	trans      pixel.TrianglesData
+	transD     pixel.Drawer
+	dirty      bool
+
+	// the Arena of trans, release is registered to be called by its Reset
+	arena     *pixel.Arena
+	release   func()
+	releasing bool
 }
 
 // New creates a new Text capable of drawing runes contained in the provided Atlas. Orig and Dot
@@ -117,14 +213,19 @@ type Text struct {
 //   txt := text.New(orig, text.NewAtlas(face, text.ASCII))
 func New(orig pixel.Vec, atlas *Atlas) *Text {
 	txt := &Text{
-		Orig:       orig,
-		Dot:        orig,
-		Color:      pixel.Alpha(1),
-		LineHeight: atlas.LineHeight(),
-		TabWidth:   atlas.Glyph(' ').Advance * 4,
-		atlas:      atlas,
-		mat:        pixel.IM,
-		col:        pixel.Alpha(1),
+		Orig:         orig,
+		Dot:          orig,
+		Color:        pixel.Alpha(1),
+		Scale:        1,
+		OutlineColor: pixel.RGB(0, 0, 0),
+		ShadowColor:  pixel.RGB(0, 0, 0),
+		LineHeight:   atlas.LineHeight(),
+		TabWidth:     atlas.Glyph(' ').Advance * 4,
+		LineSpacing:  1,
+		Kerning:      true,
+		atlas:        atlas,
+		mat:          pixel.IM,
+		col:          pixel.Alpha(1),
 	}
 
 	txt.glyph.SetLen(6)
@@ -133,7 +234,7 @@ func New(orig pixel.Vec, atlas *Atlas) *Text {
 		txt.glyph[i].Intensity = 1
 	}
 
-	txt.transD.Picture = txt.atlas.pic
+	txt.transD.Picture = txt.atlas.Picture()
 	txt.transD.Triangles = &txt.trans
 
 	txt.Clear()
@@ -151,46 +252,87 @@ func (txt *Text) Atlas() *Atlas {
 //
 // If the Text is empty, a zero rectangle is returned.
 func (txt *Text) Bounds() pixel.Rect {
-	return txt.bounds
+	if txt.Align == AlignLeft {
+		return txt.lay.bounds
+	}
+	return txt.alignedBounds(txt.infos)
 }
 
 // BoundsOf returns the bounding box of s if it was to be written to the Text right now.
 func (txt *Text) BoundsOf(s string) pixel.Rect {
-	dot := txt.Dot
-	prevR := txt.prevR
-	bounds := pixel.Rect{}
-
-	for _, r := range s {
-		var control bool
-		dot, control = txt.controlRune(r, dot)
-		if control {
+	l := txt.lay
+	txt.syncDot(&l)
+	l.bounds, l.prevBounds, l.wordBounds = pixel.Rect{}, pixel.Rect{}, pixel.Rect{}
+
+	var infos []glyphInfo
+	wordStart := 0
+	shaped, breaks := txt.shapeWrapped(l, []rune(s))
+	l.fixedBreaks = breaks != nil
+	for i, r := range shaped {
+		if breaks != nil && breaks[i] {
+			txt.breakLine(&l)
+		}
+		g, ok := txt.layoutRune(&l, r)
+		if !ok {
 			continue
 		}
-
-		var b pixel.Rect
-		_, _, b, dot = txt.Atlas().DrawRune(prevR, r, dot)
-
-		if bounds.W()*bounds.H() == 0 {
-			bounds = b
-		} else {
-			bounds = bounds.Union(b)
+		if g.newWord {
+			wordStart = len(infos)
 		}
-
-		prevR = r
+		if g.wordMoved != pixel.ZV {
+			moveWord(infos[wordStart:], g.wordMoved)
+		}
+		infos = append(infos, g.info)
 	}
 
-	return bounds
+	if txt.Align == AlignLeft {
+		return l.bounds
+	}
+	return txt.alignedBounds(infos)
 }
 
 // Clear removes all written text from the Text. The Dot field is reset to Orig.
 func (txt *Text) Clear() {
-	txt.prevR = -1
-	txt.bounds = pixel.Rect{}
+	txt.lay = layout{prevR: -1, dot: txt.Orig}
 	txt.tris.SetLen(0)
+	txt.infos = txt.infos[:0]
+	txt.wordStart = 0
 	txt.dirty = true
 	txt.Dot = txt.Orig
 }
 
+// SetArena sets an Arena which the transformed vertices drawn by DrawColorMask are allocated from.
+// Resetting the Arena frees them and the next draw transforms the written text again, so it's only
+// worth it for text rewritten every frame, such as a score. Passing nil stops allocating from the
+// Arena.
+func (txt *Text) SetArena(arena *pixel.Arena) {
+	if arena != txt.arena {
+		txt.releasing = false
+	}
+	txt.arena = arena
+	if txt.release == nil {
+		txt.release = func() {
+			txt.releasing = false
+			txt.trans = nil
+			txt.dirty = true
+		}
+	}
+}
+
+// Arena returns the Arena set by SetArena.
+func (txt *Text) Arena() *pixel.Arena {
+	return txt.arena
+}
+
+// syncDot continues the layout at the Dot, if it was changed manually.
+func (txt *Text) syncDot(l *layout) {
+	if l.dot != txt.Dot {
+		l.endWord()
+		l.dot = txt.Dot
+		l.prevR = -1
+	}
+}
+
 // Write writes a slice of bytes to the Text. This method never fails, always returns len(p), nil.
 func (txt *Text) Write(p []byte) (n int, err error) {
 	txt.buf = append(txt.buf, p...)
@@ -253,13 +395,52 @@ func (txt *Text) DrawColorMask(t pixel.Target, matrix pixel.Matrix, mask color.C
 		txt.dirty = true
 	}
 
+	if txt.Align != txt.align || txt.MaxWidth != txt.alignWidth {
+		txt.align = txt.Align
+		txt.alignWidth = txt.MaxWidth
+		txt.dirty = true
+	}
+
+	if pic := txt.atlas.Picture(); pic != txt.transD.Picture {
+		// the glyphs of a dynamic Atlas were added, the old Picture won't be drawn anymore
+		txt.transD = pixel.Drawer{Triangles: &txt.trans, Picture: pic}
+	}
+
+	if e := txt.currentEffects(); e != txt.effects {
+		txt.effects = e
+		txt.dirty = true
+	}
+
 	if txt.dirty {
-		txt.trans.SetLen(txt.tris.Len())
-		txt.trans.Update(&txt.tris)
+		var shifts []float64
+		if txt.align != AlignLeft {
+			shifts = txt.alignShifts(txt.infos)
+		}
 
-		for i := range txt.trans {
-			txt.trans[i].Position = txt.mat.Project(txt.trans[i].Position)
-			txt.trans[i].Color = txt.trans[i].Color.Mul(txt.col)
+		passes := txt.effects.passes()
+		n := txt.tris.Len()
+		if txt.arena != nil {
+			if !txt.releasing {
+				txt.arena.OnReset(txt.release)
+				txt.releasing = true
+			}
+			txt.arena.SetLen(&txt.trans, n*len(passes))
+		} else {
+			txt.trans.SetLen(n * len(passes))
+		}
+
+		for p, pass := range passes {
+			for i, v := range txt.tris {
+				if shifts != nil {
+					v.Position.X += shifts[i/6]
+				}
+				v.Position = txt.mat.Project(v.Position.Add(pass.offset))
+				if pass.color != nil {
+					v.Color = pass.color.Scaled(v.Color.A)
+				}
+				v.Color = v.Color.Mul(txt.col)
+				txt.trans[p*n+i] = v
+			}
 		}
 
 		txt.transD.Dirty()
@@ -275,73 +456,111 @@ func (txt *Text) controlRune(r rune, dot pixel.Vec) (newDot pixel.Vec, control b
 	switch r {
 	case '\n':
 		dot.X = txt.Orig.X
-		dot.Y -= txt.LineHeight
+		dot.Y -= txt.lineHeight()
 	case '\r':
 		dot.X = txt.Orig.X
 	case '\t':
+		if txt.TabWidth <= 0 {
+			break
+		}
+		// move to the next tab stop, a whole TabWidth if the dot is right at one
 		rem := math.Mod(dot.X-txt.Orig.X, txt.TabWidth)
-		rem = math.Mod(rem, rem+txt.TabWidth)
-		if rem == 0 {
-			rem = txt.TabWidth
+		if rem < 0 {
+			rem += txt.TabWidth
 		}
-		dot.X += rem
+		dot.X += txt.TabWidth - rem
 	default:
 		return dot, false
 	}
 	return dot, true
 }
 
+// lineHeight returns the distance between the lines, the LineHeight multiplied by LineSpacing.
+func (txt *Text) lineHeight() float64 {
+	return txt.LineHeight * txt.LineSpacing
+}
+
 func (txt *Text) drawBuf() {
 	if !utf8.FullRune(txt.buf) {
 		return
 	}
 
 	rgba := pixel.ToRGBA(txt.Color)
-	for i := range txt.glyph {
-		txt.glyph[i].Color = rgba
-	}
 
+	txt.syncDot(&txt.lay)
+
+	var runes []rune
 	for utf8.FullRune(txt.buf) {
 		r, size := utf8.DecodeRune(txt.buf)
 		txt.buf = txt.buf[size:]
+		runes = append(runes, r)
+	}
 
-		var control bool
-		txt.Dot, control = txt.controlRune(r, txt.Dot)
-		if control {
+	shaped, breaks := txt.shapeWrapped(txt.lay, runes)
+	txt.lay.fixedBreaks = breaks != nil
+	for n, r := range shaped {
+		if breaks != nil && breaks[n] {
+			txt.breakLine(&txt.lay)
+		}
+		g, ok := txt.layoutRune(&txt.lay, r)
+		if !ok {
 			continue
 		}
 
-		var rect, frame, bounds pixel.Rect
-		rect, frame, bounds, txt.Dot = txt.Atlas().DrawRune(txt.prevR, r, txt.Dot)
+		if g.newWord {
+			txt.wordStart = len(txt.infos)
+		}
+		if g.wordMoved != pixel.ZV {
+			for i := 6 * txt.wordStart; i < len(txt.tris); i++ {
+				txt.tris[i].Position = txt.tris[i].Position.Add(g.wordMoved)
+			}
+			moveWord(txt.infos[txt.wordStart:], g.wordMoved)
+		}
 
-		txt.prevR = r
+		txt.setGlyphColor(r, rgba)
+		txt.appendStyled(g.rect, g.frame, g.dot, pixel.IM, g.info)
+	}
+	txt.lay.fixedBreaks = false
 
-		rv := [...]pixel.Vec{
-			{X: rect.Min.X, Y: rect.Min.Y},
-			{X: rect.Max.X, Y: rect.Min.Y},
-			{X: rect.Max.X, Y: rect.Max.Y},
-			{X: rect.Min.X, Y: rect.Max.Y},
-		}
+	txt.Dot = txt.lay.dot
+}
 
-		fv := [...]pixel.Vec{
-			{X: frame.Min.X, Y: frame.Min.Y},
-			{X: frame.Max.X, Y: frame.Min.Y},
-			{X: frame.Max.X, Y: frame.Max.Y},
-			{X: frame.Min.X, Y: frame.Max.Y},
-		}
+// setGlyphColor sets the color of the next glyph of r. The colored glyphs only take the opacity of
+// the color.
+func (txt *Text) setGlyphColor(r rune, rgba pixel.RGBA) {
+	if txt.atlas.Colored(r) {
+		rgba = pixel.Alpha(rgba.A)
+	}
+	if txt.glyph[0].Color == rgba {
+		return
+	}
+	for i := range txt.glyph {
+		txt.glyph[i].Color = rgba
+	}
+}
 
-		for i, j := range [...]int{0, 1, 2, 0, 2, 3} {
-			txt.glyph[i].Position = rv[j]
-			txt.glyph[i].Picture = fv[j]
-		}
+// appendGlyph appends the glyph with the rect and the frame in the Atlas, transformed by the
+// matrix.
+func (txt *Text) appendGlyph(rect, frame pixel.Rect, matrix pixel.Matrix) {
+	rv := [...]pixel.Vec{
+		{X: rect.Min.X, Y: rect.Min.Y},
+		{X: rect.Max.X, Y: rect.Min.Y},
+		{X: rect.Max.X, Y: rect.Max.Y},
+		{X: rect.Min.X, Y: rect.Max.Y},
+	}
 
-		txt.tris = append(txt.tris, txt.glyph...)
-		txt.dirty = true
+	fv := [...]pixel.Vec{
+		{X: frame.Min.X, Y: frame.Min.Y},
+		{X: frame.Max.X, Y: frame.Min.Y},
+		{X: frame.Max.X, Y: frame.Max.Y},
+		{X: frame.Min.X, Y: frame.Max.Y},
+	}
 
-		if txt.bounds.W()*txt.bounds.H() == 0 {
-			txt.bounds = bounds
-		} else {
-			txt.bounds = txt.bounds.Union(bounds)
-		}
+	for i, j := range [...]int{0, 1, 2, 0, 2, 3} {
+		txt.glyph[i].Position = matrix.Project(rv[j])
+		txt.glyph[i].Picture = fv[j]
 	}
+
+	txt.tris = append(txt.tris, txt.glyph...)
+	txt.dirty = true
 }
@@ -2,6 +2,7 @@ package text_test
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"testing"
 	"unicode"
@@ -10,6 +11,7 @@ import (
 	"golang.org/x/image/font/gofont/goregular"
 
 	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
 	"github.com/faiface/pixel/text"
 	"github.com/golang/freetype/truetype"
 )
@@ -85,3 +87,195 @@ func BenchmarkTextWrite(b *testing.B) {
 func eqVectors(a, b pixel.Vec) bool {
 	return (a.X == b.X && a.Y == b.Y)
 }
+
+func TestMaxWidth(t *testing.T) {
+	tests := []struct {
+		s        string
+		maxWidth float64
+		dot      pixel.Vec
+	}{
+		{"hello world foo", 0, pixel.V(105, 0)},
+		{"hello world foo", 50, pixel.V(21, -26)},
+		{"hello world", 200, pixel.V(77, 0)},
+		// too long words are broken
+		{"abcdefghij", 35, pixel.V(35, -13)},
+		{"a abcdefghij", 35, pixel.V(35, -26)},
+		// newlines start the line again
+		{"hello\nworld foo", 50, pixel.V(21, -26)},
+	}
+	for _, tt := range tests {
+		txt := text.New(pixel.ZV, text.Atlas7x13)
+		txt.MaxWidth = tt.maxWidth
+		want := txt.BoundsOf(tt.s)
+
+		// write in pieces to check that the words are moved after being written
+		for _, r := range tt.s {
+			fmt.Fprint(txt, string(r))
+		}
+
+		if got := txt.Dot; !eqVectors(got, tt.dot) {
+			t.Errorf("%q, max width %v: txt.Dot = %v, want %v", tt.s, tt.maxWidth, got, tt.dot)
+		}
+		if got := txt.Bounds(); got != want {
+			t.Errorf("%q, max width %v: txt.Bounds() = %v, want BoundsOf = %v", tt.s, tt.maxWidth, got, want)
+		}
+		if tt.maxWidth > 0 && txt.Bounds().Max.X > tt.maxWidth {
+			t.Errorf("%q, max width %v: txt.Bounds() = %v, wider than the max width", tt.s, tt.maxWidth, txt.Bounds())
+		}
+	}
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		s        string
+		align    text.Align
+		min, max float64
+	}{
+		{"abc", text.AlignLeft, 0, 20},
+		{"abc", text.AlignCenter, 19.5, 39.5},
+		{"abc", text.AlignRight, 39, 59},
+		{"aa bb cc dd", text.AlignCenter, 2, 57},
+		{"aa bb cc dd", text.AlignRight, 4, 59},
+		// the wrapped line fills the width, the last line stays on the left
+		{"aa bb cc dd", text.AlignJustify, 0, 59},
+		{"aa bb\ncc dd ee", text.AlignJustify, 0, 55},
+	}
+	for _, tt := range tests {
+		txt := text.New(pixel.ZV, text.Atlas7x13)
+		txt.MaxWidth = 60
+		txt.Align = tt.align
+		fmt.Fprint(txt, tt.s)
+
+		bounds := txt.Bounds()
+		if bounds.Min.X != tt.min || bounds.Max.X != tt.max {
+			t.Errorf("%q, align %v: txt.Bounds() = %v, want X from %v to %v", tt.s, tt.align, bounds, tt.min, tt.max)
+		}
+		empty := text.New(pixel.ZV, text.Atlas7x13)
+		empty.MaxWidth = 60
+		empty.Align = tt.align
+		if got := empty.BoundsOf(tt.s); got != bounds {
+			t.Errorf("%q, align %v: BoundsOf = %v, want the same as txt.Bounds() = %v", tt.s, tt.align, got, bounds)
+		}
+
+		// the glyphs are aligned when drawn
+		canvas := raster.NewCanvas(pixel.R(-10, -30, 70, 15))
+		txt.Draw(canvas, pixel.IM)
+		drawn := pixel.Rect{Min: pixel.V(math.Inf(+1), 0), Max: pixel.V(math.Inf(-1), 0)}
+		for x := -10.0; x < 70; x++ {
+			for y := -30.0; y < 15; y++ {
+				if canvas.Color(pixel.V(x, y)).A > 0 {
+					drawn.Min.X = math.Min(drawn.Min.X, x)
+					drawn.Max.X = math.Max(drawn.Max.X, x+1)
+				}
+			}
+		}
+		if drawn.Min.X > drawn.Max.X {
+			t.Errorf("%q, align %v: nothing drawn", tt.s, tt.align)
+		} else if drawn.Min.X < math.Floor(tt.min) || drawn.Max.X > math.Ceil(tt.max) {
+			t.Errorf("%q, align %v: drawn from X %v to %v, outside of %v to %v", tt.s, tt.align, drawn.Min.X, drawn.Max.X, tt.min, tt.max)
+		}
+	}
+}
+
+func TestAlignChanged(t *testing.T) {
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	txt.MaxWidth = 60
+	fmt.Fprint(txt, "abc")
+
+	txt.Align = text.AlignRight
+	if got, want := txt.Bounds().Min.X, 39.0; got != want {
+		t.Errorf("txt.Bounds().Min.X = %v, want %v", got, want)
+	}
+	txt.MaxWidth = 0
+	if got, want := txt.Bounds().Max.X, -1.0; got != want {
+		t.Errorf("txt.Bounds().Max.X = %v, want %v", got, want)
+	}
+}
+
+func TestSpacing(t *testing.T) {
+	tests := []struct {
+		s           string
+		tracking    float64
+		lineSpacing float64
+		tabWidth    float64
+		dot         pixel.Vec
+	}{
+		{"abc", 0, 1, 28, pixel.V(21, 0)},
+		{"abc", 2, 1, 28, pixel.V(27, 0)},
+		{"abc", -1, 1, 28, pixel.V(18, 0)},
+		{"a\nb\nc", 0, 1.5, 28, pixel.V(7, -39)},
+		{"a\nb", 3, 2, 28, pixel.V(10, -26)},
+		// tabs move to the next tab stop
+		{"a\t", 0, 1, 28, pixel.V(28, 0)},
+		{"\t", 0, 1, 28, pixel.V(28, 0)},
+		{"abcd\t", 0, 1, 28, pixel.V(56, 0)},
+		{"a\tb\t", 0, 1, 10, pixel.V(20, 0)},
+		{"a\t", 0, 1, 0, pixel.V(7, 0)},
+	}
+	for _, tt := range tests {
+		txt := text.New(pixel.ZV, text.Atlas7x13)
+		txt.Tracking = tt.tracking
+		txt.LineSpacing = tt.lineSpacing
+		txt.TabWidth = tt.tabWidth
+		want := txt.BoundsOf(tt.s)
+		fmt.Fprint(txt, tt.s)
+
+		if got := txt.Dot; !eqVectors(got, tt.dot) {
+			t.Errorf("%q, tracking %v, line spacing %v, tab width %v: txt.Dot = %v, want %v",
+				tt.s, tt.tracking, tt.lineSpacing, tt.tabWidth, got, tt.dot)
+		}
+		if got := txt.Bounds(); got != want {
+			t.Errorf("%q: txt.Bounds() = %v, want BoundsOf = %v", tt.s, got, want)
+		}
+	}
+}
+
+func TestSpacingWrap(t *testing.T) {
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	txt.MaxWidth = 50
+	txt.LineSpacing = 2
+	txt.Tracking = 1
+	fmt.Fprint(txt, "hello world")
+
+	// the wrapped lines are spaced by LineSpacing too
+	if got, want := txt.Dot, pixel.V(40, -26); !eqVectors(got, want) {
+		t.Errorf("txt.Dot = %v, want %v", got, want)
+	}
+}
+
+func TestArena(t *testing.T) {
+	arena := pixel.NewArena()
+	txt := text.New(pixel.ZV, text.Atlas7x13)
+	txt.SetArena(arena)
+	fmt.Fprint(txt, "ab")
+
+	var want []pixel.RGBA
+	for frame := 0; frame < 3; frame++ {
+		canvas := raster.NewCanvas(pixel.R(0, 0, 20, 20))
+		txt.Draw(canvas, pixel.IM.Moved(pixel.V(2, 5)))
+		if arena.Len() == 0 {
+			t.Fatalf("frame %d: no vertices were allocated from the Arena", frame)
+		}
+
+		// the text is transformed again after every Reset
+		var got []pixel.RGBA
+		drawn := 0
+		for y := 0.0; y < 20; y++ {
+			for x := 0.0; x < 20; x++ {
+				got = append(got, canvas.Color(pixel.V(x, y)))
+				if got[len(got)-1].A > 0 {
+					drawn++
+				}
+			}
+		}
+		if drawn == 0 {
+			t.Errorf("frame %d: no text was drawn", frame)
+		}
+		if want == nil {
+			want = got
+		} else if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("frame %d: the text is drawn differently than in the first frame", frame)
+		}
+		arena.Reset()
+	}
+}
@@ -0,0 +1,241 @@
+package tilemap
+
+// Mode is a way an Autotiler picks the tiles by the neighbours of the cells.
+type Mode int
+
+const (
+	// Mask4 looks at the four edge neighbours of a cell. The mask has the bits N = 1, E = 2, S = 4
+	// and W = 8, which gives 16 tiles.
+	Mask4 Mode = iota
+
+	// Mask8 looks at all eight neighbours of a cell (the blob tiles). The mask has the bits N = 1,
+	// NE = 2, E = 4, SE = 8, S = 16, SW = 32, W = 64 and NW = 128. A corner only counts if both of
+	// its edges do, which leaves 47 different masks. Without a Tiles table, they are numbered from
+	// 0 to 46 in the increasing order.
+	Mask8
+
+	// Corners picks the tiles of a grid shifted by a half of a cell, each tile covering the corners
+	// of four cells (the corner Wang tiles, or the dual grid). The mask has the bits NW = 1, NE = 2,
+	// SE = 4 and SW = 8 set for the non-empty cells, which gives 16 tiles. The grid of the tiles is
+	// one tile wider and higher than the grid of the cells, the tile (x, y) is at the bottom-left
+	// corner of the cell (x, y).
+	Corners
+)
+
+// Autotiler picks the tiles of a grid of terrain by the neighbours of each cell, so the edges and
+// the corners of the terrain get the matching tiles. The cells are counted from the bottom-left
+// corner, x to the right and y up. The terrain 0 is empty and gets no tile, the neighbours of the
+// same terrain are connected.
+//
+//   at := tilemap.NewAutotiler(tilemap.Mask4, 64, 64)
+//   at.OnChange = func(x, y, tile int) {
+//       layer.SetTile(x, y, tile)
+//   }
+//   at.Set(x, y, dirt) // in the level editor, or when the terrain is destroyed
+//
+// Changing a cell updates only the tiles around it.
+type Autotiler struct {
+	// Tiles maps the masks to the tiles. A mask missing in it gets the tile -1. If it's nil, the
+	// tile is the mask itself (or its index in the Mask8 mode).
+	Tiles map[uint8]int
+
+	// Outside is the terrain around the grid. With the terrain of the walls, the walls connect to
+	// the borders instead of ending with an edge.
+	Outside int
+
+	// OnChange is called with every tile that changes, after Set or Refresh.
+	OnChange func(x, y, tile int)
+
+	mode          Mode
+	width, height int
+	terrain       []int
+	tiles         []int
+}
+
+// NewAutotiler creates a new Autotiler of an empty grid of width by height cells.
+func NewAutotiler(mode Mode, width, height int) *Autotiler {
+	a := &Autotiler{
+		mode:    mode,
+		width:   width,
+		height:  height,
+		terrain: make([]int, width*height),
+	}
+	tw, th := a.TilesSize()
+	a.tiles = make([]int, tw*th)
+	for i := range a.tiles {
+		a.tiles[i] = -1
+	}
+	return a
+}
+
+// Mode returns the Mode of the Autotiler.
+func (a *Autotiler) Mode() Mode {
+	return a.mode
+}
+
+// Size returns the size of the grid of the cells.
+func (a *Autotiler) Size() (width, height int) {
+	return a.width, a.height
+}
+
+// TilesSize returns the size of the grid of the tiles. It's one tile larger than the grid of the
+// cells in both directions in the Corners mode.
+func (a *Autotiler) TilesSize() (width, height int) {
+	if a.mode == Corners {
+		return a.width + 1, a.height + 1
+	}
+	return a.width, a.height
+}
+
+// Terrain returns the terrain of the cell, or the Outside terrain outside of the grid.
+func (a *Autotiler) Terrain(x, y int) int {
+	if x < 0 || y < 0 || x >= a.width || y >= a.height {
+		return a.Outside
+	}
+	return a.terrain[y*a.width+x]
+}
+
+// Set sets the terrain of the cell and updates the tiles around it. It does nothing outside of the
+// grid.
+func (a *Autotiler) Set(x, y, terrain int) {
+	if x < 0 || y < 0 || x >= a.width || y >= a.height || a.terrain[y*a.width+x] == terrain {
+		return
+	}
+	a.terrain[y*a.width+x] = terrain
+	if a.mode == Corners {
+		// the tiles at the four corners of the cell
+		a.update(x, y, x+1, y+1)
+		return
+	}
+	a.update(x-1, y-1, x+1, y+1)
+}
+
+// Refresh updates all tiles, e.g. after changing the Tiles or the Outside.
+func (a *Autotiler) Refresh() {
+	tw, th := a.TilesSize()
+	a.update(0, 0, tw-1, th-1)
+}
+
+// Tile returns the tile of the tiles grid, or -1 if there's none.
+func (a *Autotiler) Tile(x, y int) int {
+	tw, th := a.TilesSize()
+	if x < 0 || y < 0 || x >= tw || y >= th {
+		return -1
+	}
+	return a.tiles[y*tw+x]
+}
+
+// Mask returns the mask of the tile of the tiles grid.
+func (a *Autotiler) Mask(x, y int) uint8 {
+	if a.mode == Corners {
+		var mask uint8
+		for i, c := range [...][2]int{{-1, 0}, {0, 0}, {0, -1}, {-1, -1}} {
+			if a.Terrain(x+c[0], y+c[1]) != 0 {
+				mask |= 1 << uint(i)
+			}
+		}
+		return mask
+	}
+
+	t := a.Terrain(x, y)
+	same := func(dx, dy int) bool {
+		return a.Terrain(x+dx, y+dy) == t
+	}
+	if a.mode == Mask4 {
+		var mask uint8
+		for i, d := range [...][2]int{{0, 1}, {1, 0}, {0, -1}, {-1, 0}} {
+			if same(d[0], d[1]) {
+				mask |= 1 << uint(i)
+			}
+		}
+		return mask
+	}
+
+	var mask uint8
+	for i, d := range neighbours8 {
+		if same(d[0], d[1]) {
+			mask |= 1 << uint(i)
+		}
+	}
+	return blobMask(mask)
+}
+
+// update updates the tiles in the rectangle of the tiles grid between the corners, including them.
+func (a *Autotiler) update(x0, y0, x1, y1 int) {
+	tw, th := a.TilesSize()
+	for y := max(y0, 0); y <= min(y1, th-1); y++ {
+		for x := max(x0, 0); x <= min(x1, tw-1); x++ {
+			tile := a.pick(x, y)
+			if a.tiles[y*tw+x] == tile {
+				continue
+			}
+			a.tiles[y*tw+x] = tile
+			if a.OnChange != nil {
+				a.OnChange(x, y, tile)
+			}
+		}
+	}
+}
+
+// pick returns the tile of the tiles grid for its current neighbours.
+func (a *Autotiler) pick(x, y int) int {
+	if a.mode != Corners && a.Terrain(x, y) == 0 {
+		return -1
+	}
+	mask := a.Mask(x, y)
+	if a.mode == Corners && mask == 0 {
+		return -1
+	}
+	if a.Tiles != nil {
+		tile, ok := a.Tiles[mask]
+		if !ok {
+			return -1
+		}
+		return tile
+	}
+	if a.mode == Mask8 {
+		return blobIndex[mask]
+	}
+	return int(mask)
+}
+
+// neighbours8 are the directions of the bits of the Mask8 mode.
+var neighbours8 = [...][2]int{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
+
+// blobMask clears the corners of the Mask8 mask without both of their edges.
+func blobMask(mask uint8) uint8 {
+	const n, ne, e, se, s, sw, w, nw = 1, 2, 4, 8, 16, 32, 64, 128
+	for _, c := range [...][3]uint8{{ne, n, e}, {se, s, e}, {sw, s, w}, {nw, n, w}} {
+		if mask&c[1] == 0 || mask&c[2] == 0 {
+			mask &^= c[0]
+		}
+	}
+	return mask
+}
+
+// blobIndex numbers the 47 masks of the Mask8 mode in the increasing order.
+var blobIndex = func() (index [256]int) {
+	i := 0
+	for m := 0; m < 256; m++ {
+		index[m] = -1
+		if blobMask(uint8(m)) == uint8(m) {
+			index[m] = i
+			i++
+		}
+	}
+	return index
+}()
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,86 @@
+package tilemap_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel/tilemap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutotiler_Mask4(t *testing.T) {
+	at := tilemap.NewAutotiler(tilemap.Mask4, 3, 3)
+	at.Set(1, 1, 1)
+	assert.Equal(t, 0, at.Tile(1, 1))
+	assert.Equal(t, -1, at.Tile(0, 0))
+
+	// the neighbours connect to the center
+	at.Set(1, 2, 1)
+	at.Set(2, 1, 1)
+	assert.Equal(t, 1|2, at.Tile(1, 1))
+	assert.Equal(t, 4, at.Tile(1, 2))
+	assert.Equal(t, 8, at.Tile(2, 1))
+
+	// a different terrain doesn't connect
+	at.Set(1, 0, 2)
+	assert.Equal(t, 1|2, at.Tile(1, 1))
+	assert.Equal(t, 0, at.Tile(1, 0))
+
+	// the outside connects to the cells of its terrain
+	at.Outside = 2
+	at.Refresh()
+	assert.Equal(t, 4, at.Tile(1, 0))
+}
+
+func TestAutotiler_Mask8(t *testing.T) {
+	at := tilemap.NewAutotiler(tilemap.Mask8, 3, 3)
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			at.Set(x, y, 1)
+		}
+	}
+	assert.Equal(t, uint8(255), at.Mask(1, 1))
+	assert.Equal(t, 46, at.Tile(1, 1))
+	// the bottom-left corner has its neighbours to the N, NE and E
+	assert.Equal(t, uint8(1|2|4), at.Mask(0, 0))
+
+	// a corner without both edges doesn't count
+	at.Set(1, 2, 0)
+	assert.Equal(t, uint8(4|8|16|32|64), at.Mask(1, 1))
+	assert.Equal(t, uint8(0), at.Mask(1, 1)&(1|2|128))
+}
+
+func TestAutotiler_Corners(t *testing.T) {
+	at := tilemap.NewAutotiler(tilemap.Corners, 2, 2)
+	w, h := at.TilesSize()
+	assert.Equal(t, 3, w)
+	assert.Equal(t, 3, h)
+
+	at.Set(0, 0, 1)
+	// the tiles at the corners of the cell have it on the opposite side
+	assert.Equal(t, 2, at.Tile(0, 0))
+	assert.Equal(t, 1, at.Tile(1, 0))
+	assert.Equal(t, 8, at.Tile(1, 1))
+	assert.Equal(t, 4, at.Tile(0, 1))
+	assert.Equal(t, -1, at.Tile(2, 2))
+}
+
+func TestAutotiler_OnChange(t *testing.T) {
+	at := tilemap.NewAutotiler(tilemap.Mask4, 10, 10)
+	at.Tiles = map[uint8]int{0: 100, 2: 102, 8: 108}
+	changed := map[[2]int]int{}
+	at.OnChange = func(x, y, tile int) {
+		changed[[2]int{x, y}] = tile
+	}
+
+	at.Set(5, 5, 1)
+	assert.Equal(t, map[[2]int]int{{5, 5}: 100}, changed)
+
+	changed = map[[2]int]int{}
+	at.Set(6, 5, 1)
+	assert.Equal(t, map[[2]int]int{{5, 5}: 102, {6, 5}: 108}, changed)
+
+	// a mask missing in the Tiles gets no tile
+	changed = map[[2]int]int{}
+	at.Set(5, 6, 1)
+	assert.Equal(t, -1, changed[[2]int{5, 5}])
+}
@@ -0,0 +1,3 @@
+// Package tilemap implements the tools for the grids of tiles: autotiling of a terrain and drawing
+// of unbounded maps in chunks.
+package tilemap
@@ -0,0 +1,237 @@
+package tilemap
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Tileset is a Picture cut into a grid of tiles of the same size. The tiles are numbered from 0,
+// row by row from the top-left corner of the Picture.
+type Tileset struct {
+	Picture  pixel.Picture
+	TileSize pixel.Vec
+}
+
+// NewTileset creates a new Tileset of the tiles of the size in the Picture.
+func NewTileset(pic pixel.Picture, tileSize pixel.Vec) *Tileset {
+	return &Tileset{
+		Picture:  pic,
+		TileSize: tileSize,
+	}
+}
+
+// Frame returns the rectangle of the tile in the Picture.
+func (ts *Tileset) Frame(tile int) pixel.Rect {
+	b := ts.Picture.Bounds()
+	columns := int(b.W() / ts.TileSize.X)
+	col, row := tile%columns, tile/columns
+	min := pixel.V(b.Min.X+float64(col)*ts.TileSize.X, b.Max.Y-float64(row+1)*ts.TileSize.Y)
+	return pixel.Rect{Min: min, Max: min.Add(ts.TileSize)}
+}
+
+// Map is an unbounded grid of tiles, split into square chunks. Only the chunks around the Camera
+// are kept in the memory: they are generated by the Generate function when they come into the
+// view, baked into triangles once, and dropped when they get far from the view again. A chunk is
+// only baked again after its tiles change, so a huge world is drawn at a stable cost.
+//
+// The tiles are counted from the origin of the world, x to the right and y up. The tile -1 is
+// empty.
+//
+//   m := tilemap.NewMap(tilemap.NewTileset(pic, pixel.V(16, 16)), 32)
+//   m.Generate = func(x, y int) int {
+//       if noise(x, y) > 0.5 {
+//           return rock
+//       }
+//       return -1
+//   }
+//
+//   win.SetMatrix(cam.Matrix())
+//   m.Draw(win, cam)
+type Map struct {
+	Tileset *Tileset
+
+	// Generate returns the tile of a cell of a newly loaded chunk. If it's nil, the new chunks are
+	// empty.
+	Generate func(x, y int) int
+
+	// Keep is the number of chunks around the view which stay loaded. The chunks with tiles
+	// changed by SetTile are never dropped, only their triangles are.
+	Keep int
+
+	chunkSize int
+	chunks    map[[2]int]*chunk
+}
+
+type chunk struct {
+	tiles  []int
+	edited bool
+	dirty  bool
+	tris   *pixel.TrianglesData
+	d      pixel.Drawer
+}
+
+// NewMap creates a new Map of the tiles from the Tileset, split into chunks of chunkSize by
+// chunkSize tiles. It keeps one chunk around the view.
+func NewMap(ts *Tileset, chunkSize int) *Map {
+	return &Map{
+		Tileset:   ts,
+		Keep:      1,
+		chunkSize: chunkSize,
+		chunks:    make(map[[2]int]*chunk),
+	}
+}
+
+// ChunkSize returns the number of tiles along a side of a chunk.
+func (m *Map) ChunkSize() int {
+	return m.chunkSize
+}
+
+// Loaded returns the number of chunks in the memory.
+func (m *Map) Loaded() int {
+	return len(m.chunks)
+}
+
+// Tile returns the tile of the cell, loading its chunk if it's not loaded.
+func (m *Map) Tile(x, y int) int {
+	c, i := m.cell(x, y)
+	return c.tiles[i]
+}
+
+// SetTile sets the tile of the cell. Its chunk is baked again before the next Draw and it's never
+// dropped.
+func (m *Map) SetTile(x, y, tile int) {
+	c, i := m.cell(x, y)
+	if c.tiles[i] == tile {
+		return
+	}
+	c.tiles[i] = tile
+	c.edited = true
+	c.dirty = true
+}
+
+// TileRect returns the rectangle of the cell in the world.
+func (m *Map) TileRect(x, y int) pixel.Rect {
+	size := m.Tileset.TileSize
+	min := pixel.V(float64(x)*size.X, float64(y)*size.Y)
+	return pixel.Rect{Min: min, Max: min.Add(size)}
+}
+
+// TileAt returns the cell at the world position.
+func (m *Map) TileAt(pos pixel.Vec) (x, y int) {
+	size := m.Tileset.TileSize
+	return int(math.Floor(pos.X / size.X)), int(math.Floor(pos.Y / size.Y))
+}
+
+// Draw draws the chunks visible by the Camera onto the Target, loading and baking them as needed,
+// and drops the chunks far from the view. The Target's Matrix must be the Camera's Matrix.
+func (m *Map) Draw(t pixel.Target, cam *pixel.Camera) {
+	view := cam.VisibleBounds()
+	x0, y0 := m.chunkAt(view.Min)
+	x1, y1 := m.chunkAt(view.Max)
+
+	for cy := y0; cy <= y1; cy++ {
+		for cx := x0; cx <= x1; cx++ {
+			c := m.chunk(cx, cy)
+			if c.dirty {
+				m.bake(cx, cy, c)
+			}
+			c.d.Draw(t)
+		}
+	}
+
+	for key, c := range m.chunks {
+		cx, cy := key[0], key[1]
+		if cx >= x0-m.Keep && cx <= x1+m.Keep && cy >= y0-m.Keep && cy <= y1+m.Keep {
+			continue
+		}
+		if !c.edited {
+			delete(m.chunks, key)
+			continue
+		}
+		c.tris, c.d, c.dirty = nil, pixel.Drawer{}, true
+	}
+}
+
+// chunkAt returns the chunk containing the world position.
+func (m *Map) chunkAt(pos pixel.Vec) (cx, cy int) {
+	x, y := m.TileAt(pos)
+	return floorDiv(x, m.chunkSize), floorDiv(y, m.chunkSize)
+}
+
+// cell returns the chunk of the cell, loading it if needed, and the index of the cell in it.
+func (m *Map) cell(x, y int) (*chunk, int) {
+	cx, cy := floorDiv(x, m.chunkSize), floorDiv(y, m.chunkSize)
+	c := m.chunk(cx, cy)
+	return c, (y-cy*m.chunkSize)*m.chunkSize + (x - cx*m.chunkSize)
+}
+
+// chunk returns the chunk, loading it if it isn't loaded.
+func (m *Map) chunk(cx, cy int) *chunk {
+	if c, ok := m.chunks[[2]int{cx, cy}]; ok {
+		return c
+	}
+	c := &chunk{
+		tiles: make([]int, m.chunkSize*m.chunkSize),
+		dirty: true,
+	}
+	for i := range c.tiles {
+		c.tiles[i] = -1
+		if m.Generate != nil {
+			c.tiles[i] = m.Generate(cx*m.chunkSize+i%m.chunkSize, cy*m.chunkSize+i/m.chunkSize)
+		}
+	}
+	m.chunks[[2]int{cx, cy}] = c
+	return c
+}
+
+// bake builds the triangles of the tiles of the chunk.
+func (m *Map) bake(cx, cy int, c *chunk) {
+	if c.tris == nil {
+		c.tris = pixel.MakeTrianglesData(0)
+	}
+	c.tris.SetLen(0)
+	for i, tile := range c.tiles {
+		if tile < 0 {
+			continue
+		}
+		r := m.TileRect(cx*m.chunkSize+i%m.chunkSize, cy*m.chunkSize+i/m.chunkSize)
+		frame := m.Tileset.Frame(tile)
+		n := len(*c.tris)
+		c.tris.SetLen(n + 6)
+		for j, k := range [...]int{0, 1, 2, 0, 2, 3} {
+			(*c.tris)[n+j].Position = corner(r, k)
+			(*c.tris)[n+j].Picture = corner(frame, k)
+			(*c.tris)[n+j].Color = pixel.Alpha(1)
+			(*c.tris)[n+j].Intensity = 1
+		}
+	}
+	c.d.Triangles = c.tris
+	c.d.Picture = m.Tileset.Picture
+	c.d.Dirty()
+	// the chunk only changes when rebuilt
+	c.d.Freeze()
+	c.dirty = false
+}
+
+// corner returns the k-th corner of the rectangle, counter-clockwise from the bottom-left.
+func corner(r pixel.Rect, k int) pixel.Vec {
+	switch k {
+	case 1:
+		return pixel.V(r.Max.X, r.Min.Y)
+	case 2:
+		return r.Max
+	case 3:
+		return pixel.V(r.Min.X, r.Max.Y)
+	}
+	return r.Min
+}
+
+// floorDiv divides a by b, rounding down.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
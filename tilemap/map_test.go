@@ -0,0 +1,69 @@
+package tilemap_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+	"github.com/faiface/pixel/tilemap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTileset_Frame(t *testing.T) {
+	ts := tilemap.NewTileset(pixel.MakePictureData(pixel.R(0, 0, 32, 16)), pixel.V(8, 8))
+	assert.Equal(t, pixel.R(0, 8, 8, 16), ts.Frame(0))
+	assert.Equal(t, pixel.R(24, 8, 32, 16), ts.Frame(3))
+	assert.Equal(t, pixel.R(8, 0, 16, 8), ts.Frame(5))
+}
+
+func TestMap(t *testing.T) {
+	// the tile 0 is red, the tile 1 is blue
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 1))
+	pic.Pix[0] = color.RGBA{R: 255, A: 255}
+	pic.Pix[1] = color.RGBA{B: 255, A: 255}
+
+	generated := 0
+	m := tilemap.NewMap(tilemap.NewTileset(pic, pixel.V(1, 1)), 4)
+	m.Generate = func(x, y int) int {
+		generated++
+		if x < 0 {
+			return -1
+		}
+		return 0
+	}
+
+	cam := pixel.NewCamera(pixel.R(0, 0, 4, 4))
+	draw := func() *raster.Canvas {
+		c := raster.NewCanvas(cam.Screen)
+		c.SetMatrix(cam.Matrix())
+		m.Draw(c, cam)
+		return c
+	}
+
+	// the view covers the tiles from -2 to 2 in both directions, 4 chunks
+	c := draw()
+	assert.Equal(t, 4, m.Loaded())
+	assert.Equal(t, 4*16, generated)
+	assert.Equal(t, pixel.Alpha(0), c.Color(pixel.V(0, 0)))
+	assert.Equal(t, pixel.RGB(1, 0, 0), c.Color(pixel.V(3, 3)))
+
+	m.SetTile(1, 1, 1)
+	assert.Equal(t, 1, m.Tile(1, 1))
+	assert.Equal(t, pixel.RGB(0, 0, 1), draw().Color(pixel.V(3, 3)))
+
+	// far away, the old chunks are dropped except the edited one
+	cam.Pos = pixel.V(100, 100)
+	draw()
+	assert.Equal(t, 4+1, m.Loaded())
+
+	// the edited chunk keeps its tiles
+	cam.Pos = pixel.ZV
+	assert.Equal(t, pixel.RGB(0, 0, 1), draw().Color(pixel.V(3, 3)))
+	assert.Equal(t, -1, m.Tile(-1, -1))
+
+	x, y := m.TileAt(pixel.V(-0.5, 3.5))
+	assert.Equal(t, -1, x)
+	assert.Equal(t, 3, y)
+	assert.Equal(t, pixel.R(-1, 3, 0, 4), m.TileRect(x, y))
+}
@@ -0,0 +1,219 @@
+// Package timer implements the timing of the game logic driven by the main loop: delayed and
+// repeated calls, cooldowns and stopwatches. All of them advance only by their Update, so they stop
+// with a paused game and run in slow motion with a scaled dt.
+package timer
+
+import "fmt"
+
+// Scheduler calls the functions after a delay or repeatedly, by the time it's updated with.
+//
+//   var s timer.Scheduler
+//   s.After(2, func() { door.Close() })
+//   spawner := s.Every(0.5, spawnEnemy)
+//
+//   for !win.Closed() {
+//       s.Update(dt)
+//       if boss.Dead() {
+//           spawner.Stop()
+//       }
+//       ...
+//   }
+type Scheduler struct {
+	timers []*Timer
+
+	// the Timers advanced by the running Update, so that its functions can Clear them
+	running []*Timer
+}
+
+// Timer is a call scheduled by a Scheduler.
+type Timer struct {
+	fn       func()
+	interval float64
+	left     float64
+	repeat   bool
+	stopped  bool
+}
+
+// After calls the function once after the delay in seconds.
+func (s *Scheduler) After(delay float64, fn func()) *Timer {
+	t := &Timer{fn: fn, interval: delay, left: delay}
+	s.timers = append(s.timers, t)
+	return t
+}
+
+// Every calls the function repeatedly with the interval in seconds, first after one interval. If an
+// Update covers more intervals, the function is called for each of them. The interval must be
+// positive.
+func (s *Scheduler) Every(interval float64, fn func()) *Timer {
+	if interval <= 0 {
+		panic(fmt.Errorf("(%T).Every: non-positive interval %v", s, interval))
+	}
+	t := &Timer{fn: fn, interval: interval, left: interval, repeat: true}
+	s.timers = append(s.timers, t)
+	return t
+}
+
+// Len returns the number of the scheduled Timers.
+func (s *Scheduler) Len() int {
+	return len(s.timers)
+}
+
+// Clear stops all the Timers, also when called by the function of a Timer.
+func (s *Scheduler) Clear() {
+	for _, t := range s.running {
+		t.stopped = true
+	}
+	for _, t := range s.timers {
+		t.stopped = true
+	}
+	s.timers = nil
+}
+
+// Update advances the Timers by the time dt in seconds and calls the functions of those which are
+// due, in the order they are due.
+func (s *Scheduler) Update(dt float64) {
+	// the functions may schedule new Timers, which start after this Update
+	timers := s.timers
+	s.timers = nil
+	s.running = timers
+	defer func() { s.running = nil }()
+	for _, t := range timers {
+		t.left -= dt
+	}
+
+	for {
+		// the earliest due Timer goes first
+		var next *Timer
+		for _, t := range timers {
+			if !t.stopped && t.left <= 0 && (next == nil || t.left < next.left) {
+				next = t
+			}
+		}
+		if next == nil {
+			break
+		}
+		if next.repeat {
+			next.left += next.interval
+		} else {
+			next.stopped = true
+		}
+		next.fn()
+	}
+
+	running := timers[:0]
+	for _, t := range timers {
+		if !t.stopped {
+			running = append(running, t)
+		}
+	}
+	s.timers = append(running, s.timers...)
+}
+
+// Stop cancels the Timer, its function is not called anymore.
+func (t *Timer) Stop() {
+	t.stopped = true
+}
+
+// Stopped reports whether the Timer was stopped or has already fired, if it's not repeated.
+func (t *Timer) Stopped() bool {
+	return t.stopped
+}
+
+// Left returns the time in seconds until the function is called next.
+func (t *Timer) Left() float64 {
+	return t.left
+}
+
+// Cooldown limits how often an action can happen, such as shooting.
+//
+//   shot := timer.Cooldown{Duration: 0.25}
+//   shot.Update(dt)
+//   if win.Pressed(pixelgl.KeySpace) && shot.Trigger() {
+//       shoot()
+//   }
+type Cooldown struct {
+	// Duration is the time in seconds after triggering, before the Cooldown is ready again.
+	Duration float64
+
+	left float64
+}
+
+// Update advances the Cooldown by the time dt in seconds.
+func (c *Cooldown) Update(dt float64) {
+	c.left -= dt
+	if c.left < 0 {
+		c.left = 0
+	}
+}
+
+// Ready reports whether the Cooldown can be triggered.
+func (c *Cooldown) Ready() bool {
+	return c.left <= 0
+}
+
+// Trigger starts the Cooldown if it's ready and reports whether it was.
+func (c *Cooldown) Trigger() bool {
+	if !c.Ready() {
+		return false
+	}
+	c.left = c.Duration
+	return true
+}
+
+// Reset makes the Cooldown ready immediately.
+func (c *Cooldown) Reset() {
+	c.left = 0
+}
+
+// Left returns the time in seconds until the Cooldown is ready.
+func (c *Cooldown) Left() float64 {
+	return c.left
+}
+
+// Progress returns how much of the Cooldown has passed, within range [0, 1], e.g. for an indicator
+// of the ability. It's 1 when it's ready.
+func (c *Cooldown) Progress() float64 {
+	if c.Duration <= 0 || c.left <= 0 {
+		return 1
+	}
+	return 1 - c.left/c.Duration
+}
+
+// Stopwatch measures the time while it's running, e.g. the time of a lap. A zero Stopwatch is
+// stopped at zero.
+type Stopwatch struct {
+	elapsed float64
+	running bool
+}
+
+// Start starts or resumes the Stopwatch.
+func (sw *Stopwatch) Start() {
+	sw.running = true
+}
+
+// Stop pauses the Stopwatch, keeping the measured time.
+func (sw *Stopwatch) Stop() {
+	sw.running = false
+}
+
+// Reset sets the measured time to zero, it doesn't stop the Stopwatch.
+func (sw *Stopwatch) Reset() {
+	sw.elapsed = 0
+}
+
+// Running reports whether the Stopwatch is running.
+func (sw *Stopwatch) Running() bool {
+	return sw.running
+}
+
+// Update adds the time dt in seconds to the Stopwatch, if it's running.
+func (sw *Stopwatch) Update(dt float64) {
+	if sw.running {
+		sw.elapsed += dt
+	}
+}
+
+// Elapsed returns the measured time in seconds.
+func (sw *Stopwatch) Elapsed() float64 {
+	return sw.elapsed
+}
@@ -0,0 +1,88 @@
+package timer_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel/timer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	var s timer.Scheduler
+	var log []string
+	s.After(1.5, func() { log = append(log, "after") })
+	every := s.Every(1, func() { log = append(log, "every") })
+	assert.Equal(t, 2, s.Len())
+
+	s.Update(0.5)
+	assert.Empty(t, log)
+
+	// the due calls go in their order, the repeated one catches up
+	s.Update(2)
+	assert.Equal(t, []string{"every", "after", "every"}, log)
+	assert.Equal(t, 1, s.Len())
+	assert.InDelta(t, 0.5, every.Left(), 1e-9)
+
+	every.Stop()
+	s.Update(10)
+	assert.Len(t, log, 3)
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestScheduler_FromCallback(t *testing.T) {
+	var s timer.Scheduler
+	calls := 0
+	s.After(1, func() {
+		s.After(1, func() { calls++ })
+	})
+	// the new Timer starts after the Update
+	s.Update(5)
+	assert.Equal(t, 0, calls)
+	s.Update(1)
+	assert.Equal(t, 1, calls)
+
+	tm := s.After(1, func() { calls++ })
+	s.Clear()
+	assert.True(t, tm.Stopped())
+	s.Update(2)
+	assert.Equal(t, 1, calls)
+
+	// clearing from a function stops the Timers of the running Update
+	every := s.Every(1, func() { calls++ })
+	s.After(0.5, s.Clear)
+	s.Update(3)
+	assert.Equal(t, 1, calls)
+	assert.True(t, every.Stopped())
+	assert.Equal(t, 0, s.Len())
+
+	assert.Panics(t, func() { s.Every(0, func() {}) })
+}
+
+func TestCooldown(t *testing.T) {
+	c := timer.Cooldown{Duration: 1}
+	assert.True(t, c.Trigger())
+	assert.False(t, c.Trigger())
+	c.Update(0.25)
+	assert.Equal(t, 0.25, c.Progress())
+	assert.Equal(t, 0.75, c.Left())
+	c.Update(1)
+	assert.True(t, c.Ready())
+	assert.Equal(t, 1.0, c.Progress())
+	assert.True(t, c.Trigger())
+	c.Reset()
+	assert.True(t, c.Ready())
+}
+
+func TestStopwatch(t *testing.T) {
+	var sw timer.Stopwatch
+	sw.Update(1)
+	assert.Equal(t, 0.0, sw.Elapsed())
+	sw.Start()
+	sw.Update(1)
+	sw.Stop()
+	sw.Update(1)
+	assert.Equal(t, 1.0, sw.Elapsed())
+	assert.False(t, sw.Running())
+	sw.Reset()
+	assert.Equal(t, 0.0, sw.Elapsed())
+}
@@ -0,0 +1,86 @@
+package tmx
+
+import "github.com/faiface/pixel"
+
+// Draw draws the Layer onto the Target. The triangles of the tiles are built once, one set for each
+// Tileset, and rebuilt only after the Layer changes, so drawing a big static Layer is cheap.
+func (l *Layer) Draw(t pixel.Target) {
+	if l.dirty {
+		l.build()
+		l.dirty = false
+	}
+	for i := range l.parts {
+		l.parts[i].Draw(t)
+	}
+}
+
+// build builds the triangles of the tiles of the Layer for each Tileset.
+func (l *Layer) build() {
+	m := l.m
+	tris := make(map[*Tileset]*pixel.TrianglesData)
+	mask := pixel.Alpha(l.Opacity)
+
+	for y := 0; y < l.Height; y++ {
+		for x := 0; x < l.Width; x++ {
+			tile := l.Tiles[y*l.Width+x]
+			ts := m.Tileset(tile.GID())
+			if ts == nil || ts.Picture == nil {
+				continue
+			}
+			td := tris[ts]
+			if td == nil {
+				td = pixel.MakeTrianglesData(0)
+				tris[ts] = td
+			}
+
+			// the tiles larger than the cells stick out to the top-right
+			min := m.CellRect(x, y).Min.Add(l.Offset).Add(ts.Offset)
+			size := pixel.V(float64(ts.TileWidth), float64(ts.TileHeight))
+			frame := ts.Frame(tile.GID() - ts.FirstGID)
+
+			n := len(*td)
+			td.SetLen(n + 6)
+			for i, k := range [...]int{0, 1, 2, 0, 2, 3} {
+				// the corner of the tile and its flipped corner of the frame
+				u := quad[k]
+				f := flip(tile, u)
+				(*td)[n+i].Position = min.Add(pixel.V(u.X*size.X, u.Y*size.Y))
+				(*td)[n+i].Picture = frame.Min.Add(pixel.V(f.X*frame.W(), f.Y*frame.H()))
+				(*td)[n+i].Color = mask
+				(*td)[n+i].Intensity = 1
+			}
+		}
+	}
+
+	l.parts = l.parts[:0]
+	for _, ts := range m.Tilesets {
+		if td := tris[ts]; td != nil {
+			l.parts = append(l.parts, pixel.Drawer{Triangles: td, Picture: ts.Picture})
+			l.parts[len(l.parts)-1].Freeze()
+		}
+	}
+}
+
+// quad are the corners of a tile relative to its size, counter-clockwise from the bottom-left.
+var quad = [...]pixel.Vec{
+	pixel.V(0, 0),
+	pixel.V(1, 0),
+	pixel.V(1, 1),
+	pixel.V(0, 1),
+}
+
+// flip returns the relative position in the frame of the Tile that appears at the relative
+// position u of the flipped Tile.
+func flip(t Tile, u pixel.Vec) pixel.Vec {
+	if t&FlippedVertically != 0 {
+		u.Y = 1 - u.Y
+	}
+	if t&FlippedHorizontally != 0 {
+		u.X = 1 - u.X
+	}
+	if t&FlippedDiagonally != 0 {
+		// Tiled swaps the axes going down from the top-left corner
+		u = pixel.V(1-u.Y, 1-u.X)
+	}
+	return u
+}
@@ -0,0 +1,409 @@
+package tmx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/png" // the tileset images are mostly PNG
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/faiface/pixel"
+)
+
+// LoadFile loads a Map from the .tmx file at the path. The external tilesets and the images are
+// loaded from the paths relative to it.
+func LoadFile(file string) (*Map, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dir := filepath.Dir(file)
+	return Load(f, func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+	})
+}
+
+// Load loads a Map in the TMX format. The open function opens the external tilesets (the .tsx
+// files) and the images of the tilesets by their slash-separated paths relative to the map. The
+// images are decoded by image.Decode, the PNG format is registered by the package.
+//
+// The tile layers may be encoded as CSV, XML or base64, uncompressed or compressed by zlib or gzip.
+func Load(r io.Reader, open func(file string) (io.ReadCloser, error)) (*Map, error) {
+	var xm xmlMap
+	if err := xml.NewDecoder(r).Decode(&xm); err != nil {
+		return nil, fmt.Errorf("tmx: invalid map XML: %v", err)
+	}
+	if xm.Orientation != "orthogonal" && xm.Orientation != "" {
+		return nil, fmt.Errorf("tmx: %s maps not supported", xm.Orientation)
+	}
+	if xm.Infinite {
+		return nil, fmt.Errorf("tmx: infinite maps not supported")
+	}
+
+	m := &Map{
+		Width:      xm.Width,
+		Height:     xm.Height,
+		TileWidth:  xm.TileWidth,
+		TileHeight: xm.TileHeight,
+		Properties: properties(xm.Properties),
+	}
+
+	for _, xt := range xm.Tilesets {
+		ts, err := loadTileset(xt, open)
+		if err != nil {
+			return nil, err
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+	sort.SliceStable(m.Tilesets, func(i, j int) bool {
+		return m.Tilesets[i].FirstGID < m.Tilesets[j].FirstGID
+	})
+
+	for _, xl := range xm.Layers {
+		tiles, err := xl.Data.tiles()
+		if err != nil {
+			return nil, fmt.Errorf("tmx: layer %q: %v", xl.Name, err)
+		}
+		if len(tiles) != xl.Width*xl.Height {
+			return nil, fmt.Errorf("tmx: layer %q has %d tiles, want %d", xl.Name, len(tiles), xl.Width*xl.Height)
+		}
+		m.Layers = append(m.Layers, &Layer{
+			Name:       xl.Name,
+			Width:      xl.Width,
+			Height:     xl.Height,
+			Tiles:      tiles,
+			Visible:    xl.Visible == nil || *xl.Visible,
+			Opacity:    opacity(xl.Opacity),
+			Offset:     pixel.V(xl.OffsetX, -xl.OffsetY),
+			Properties: properties(xl.Properties),
+			m:          m,
+			dirty:      true,
+		})
+	}
+
+	height := float64(m.Height * m.TileHeight)
+	for _, xg := range xm.ObjectGroups {
+		g := &ObjectGroup{
+			Name:       xg.Name,
+			Visible:    xg.Visible == nil || *xg.Visible,
+			Opacity:    opacity(xg.Opacity),
+			Offset:     pixel.V(xg.OffsetX, -xg.OffsetY),
+			Properties: properties(xg.Properties),
+		}
+		for _, xo := range xg.Objects {
+			o := &Object{
+				ID:         xo.ID,
+				Name:       xo.Name,
+				Type:       xo.Type,
+				Pos:        pixel.V(xo.X, height-xo.Y).Add(g.Offset),
+				Size:       pixel.V(xo.Width, xo.Height),
+				Rotation:   -xo.Rotation * math.Pi / 180,
+				Tile:       Tile(xo.GID),
+				Visible:    xo.Visible == nil || *xo.Visible,
+				Ellipse:    xo.Ellipse != nil,
+				Point:      xo.Point != nil,
+				Properties: properties(xo.Properties),
+			}
+			if o.Type == "" {
+				o.Type = xo.Class
+			}
+			var err error
+			if xo.Polygon != nil {
+				o.Polygon, err = o.points(xo.Polygon.Points)
+			}
+			if xo.Polyline != nil && err == nil {
+				o.Polyline, err = o.points(xo.Polyline.Points)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("tmx: object %d: %v", xo.ID, err)
+			}
+			g.Objects = append(g.Objects, o)
+		}
+		m.ObjectGroups = append(m.ObjectGroups, g)
+	}
+
+	return m, nil
+}
+
+// loadTileset loads the Tileset, from the external file if it has a source, and its image.
+func loadTileset(xt xmlTileset, open func(file string) (io.ReadCloser, error)) (*Tileset, error) {
+	dir := ""
+	if xt.Source != "" {
+		f, err := open(xt.Source)
+		if err != nil {
+			return nil, fmt.Errorf("tmx: loading tileset %q: %v", xt.Source, err)
+		}
+		firstGID := xt.FirstGID
+		err = xml.NewDecoder(f).Decode(&xt)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("tmx: invalid tileset XML %q: %v", xt.Source, err)
+		}
+		xt.FirstGID = firstGID
+		dir = path.Dir(xt.Source)
+	}
+
+	ts := &Tileset{
+		FirstGID:   xt.FirstGID,
+		Name:       xt.Name,
+		TileWidth:  xt.TileWidth,
+		TileHeight: xt.TileHeight,
+		Spacing:    xt.Spacing,
+		Margin:     xt.Margin,
+		TileCount:  xt.TileCount,
+		Columns:    xt.Columns,
+		Offset:     pixel.V(xt.TileOffset.X, -xt.TileOffset.Y),
+		Properties: properties(xt.Properties),
+		Tiles:      make(map[int]*TileInfo),
+	}
+	for _, tile := range xt.Tiles {
+		typ := tile.Type
+		if typ == "" {
+			typ = tile.Class
+		}
+		ts.Tiles[tile.ID] = &TileInfo{
+			ID:         tile.ID,
+			Type:       typ,
+			Properties: properties(tile.Properties),
+		}
+	}
+
+	if xt.Image.Source == "" {
+		return ts, nil
+	}
+	file := path.Join(dir, xt.Image.Source)
+	f, err := open(file)
+	if err != nil {
+		return nil, fmt.Errorf("tmx: loading tileset image %q: %v", file, err)
+	}
+	img, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("tmx: loading tileset image %q: %v", file, err)
+	}
+	ts.Picture = pixel.PictureDataFromImage(img)
+
+	if ts.Columns == 0 && ts.TileWidth > 0 {
+		w := img.Bounds().Dx()
+		ts.Columns = (w - 2*ts.Margin + ts.Spacing) / (ts.TileWidth + ts.Spacing)
+	}
+	if ts.Columns <= 0 {
+		return nil, fmt.Errorf("tmx: tileset %q has no columns", ts.Name)
+	}
+	return ts, nil
+}
+
+// points parses the points of a polygon or a polyline relative to the Object into the world.
+func (o *Object) points(s string) ([]pixel.Vec, error) {
+	m := pixel.IM.Rotated(pixel.ZV, o.Rotation).Moved(o.Pos)
+	var points []pixel.Vec
+	for _, pair := range strings.Fields(s) {
+		xy := strings.Split(pair, ",")
+		if len(xy) != 2 {
+			return nil, fmt.Errorf("invalid point %q", pair)
+		}
+		x, errX := strconv.ParseFloat(xy[0], 64)
+		y, errY := strconv.ParseFloat(xy[1], 64)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("invalid point %q", pair)
+		}
+		points = append(points, m.Project(pixel.V(x, -y)))
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no points")
+	}
+	return points, nil
+}
+
+// tiles decodes the Tiles of a layer.
+func (d *xmlData) tiles() ([]Tile, error) {
+	switch d.Encoding {
+	case "":
+		tiles := make([]Tile, len(d.Tiles))
+		for i, t := range d.Tiles {
+			tiles[i] = Tile(t.GID)
+		}
+		return tiles, nil
+
+	case "csv":
+		var tiles []Tile
+		for _, s := range strings.Split(d.Text, ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			gid, err := strconv.ParseUint(s, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CSV tile %q", s)
+			}
+			tiles = append(tiles, Tile(gid))
+		}
+		return tiles, nil
+
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(d.Text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data: %v", err)
+		}
+		var r io.Reader = bytes.NewReader(data)
+		switch d.Compression {
+		case "":
+		case "zlib":
+			r, err = zlib.NewReader(r)
+		case "gzip":
+			r, err = gzip.NewReader(r)
+		default:
+			return nil, fmt.Errorf("%s compression not supported", d.Compression)
+		}
+		if err == nil {
+			data, err = ioutil.ReadAll(r)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s data: %v", d.Compression, err)
+		}
+		if len(data)%4 != 0 {
+			return nil, fmt.Errorf("invalid length of base64 data")
+		}
+		tiles := make([]Tile, len(data)/4)
+		for i := range tiles {
+			tiles[i] = Tile(binary.LittleEndian.Uint32(data[i*4:]))
+		}
+		return tiles, nil
+	}
+	return nil, fmt.Errorf("%s encoding not supported", d.Encoding)
+}
+
+// properties converts the XML properties into Properties. The multi-line strings are stored in the
+// text of the property.
+func properties(xps []xmlProperty) Properties {
+	p := make(Properties, len(xps))
+	for _, xp := range xps {
+		value := xp.Value
+		if value == "" {
+			value = xp.Text
+		}
+		p[xp.Name] = value
+	}
+	return p
+}
+
+// opacity returns the opacity of a layer, which is 1 if missing.
+func opacity(o *float64) float64 {
+	if o == nil {
+		return 1
+	}
+	return *o
+}
+
+type xmlMap struct {
+	Orientation  string           `xml:"orientation,attr"`
+	Width        int              `xml:"width,attr"`
+	Height       int              `xml:"height,attr"`
+	TileWidth    int              `xml:"tilewidth,attr"`
+	TileHeight   int              `xml:"tileheight,attr"`
+	Infinite     bool             `xml:"infinite,attr"`
+	Properties   []xmlProperty    `xml:"properties>property"`
+	Tilesets     []xmlTileset     `xml:"tileset"`
+	Layers       []xmlLayer       `xml:"layer"`
+	ObjectGroups []xmlObjectGroup `xml:"objectgroup"`
+}
+
+type xmlProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"`
+}
+
+type xmlTileset struct {
+	FirstGID   int    `xml:"firstgid,attr"`
+	Source     string `xml:"source,attr"`
+	Name       string `xml:"name,attr"`
+	TileWidth  int    `xml:"tilewidth,attr"`
+	TileHeight int    `xml:"tileheight,attr"`
+	Spacing    int    `xml:"spacing,attr"`
+	Margin     int    `xml:"margin,attr"`
+	TileCount  int    `xml:"tilecount,attr"`
+	Columns    int    `xml:"columns,attr"`
+	TileOffset struct {
+		X float64 `xml:"x,attr"`
+		Y float64 `xml:"y,attr"`
+	} `xml:"tileoffset"`
+	Image struct {
+		Source string `xml:"source,attr"`
+	} `xml:"image"`
+	Properties []xmlProperty `xml:"properties>property"`
+	Tiles      []struct {
+		ID         int           `xml:"id,attr"`
+		Type       string        `xml:"type,attr"`
+		Class      string        `xml:"class,attr"`
+		Properties []xmlProperty `xml:"properties>property"`
+	} `xml:"tile"`
+}
+
+type xmlLayer struct {
+	Name       string        `xml:"name,attr"`
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	Visible    *bool         `xml:"visible,attr"`
+	Opacity    *float64      `xml:"opacity,attr"`
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	Properties []xmlProperty `xml:"properties>property"`
+	Data       xmlData       `xml:"data"`
+}
+
+type xmlData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Text        string `xml:",chardata"`
+	Tiles       []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+}
+
+type xmlObjectGroup struct {
+	Name       string        `xml:"name,attr"`
+	Visible    *bool         `xml:"visible,attr"`
+	Opacity    *float64      `xml:"opacity,attr"`
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	Properties []xmlProperty `xml:"properties>property"`
+	Objects    []xmlObject   `xml:"object"`
+}
+
+type xmlObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	Rotation   float64       `xml:"rotation,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Visible    *bool         `xml:"visible,attr"`
+	Ellipse    *struct{}     `xml:"ellipse"`
+	Point      *struct{}     `xml:"point"`
+	Polygon    *xmlPoints    `xml:"polygon"`
+	Polyline   *xmlPoints    `xml:"polyline"`
+	Properties []xmlProperty `xml:"properties>property"`
+}
+
+type xmlPoints struct {
+	Points string `xml:"points,attr"`
+}
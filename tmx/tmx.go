@@ -0,0 +1,327 @@
+// Package tmx loads the maps made in the Tiled map editor (the .tmx files) and draws their tile
+// layers.
+//
+// The positions in the package are in the pixel coordinates of the world, which starts at the
+// bottom-left corner of the map and goes up, unlike Tiled, which goes down from the top-left
+// corner. Only the tile coordinates (the columns and the rows of a Layer) are counted from the top,
+// the same as in Tiled.
+//
+//   m, err := tmx.LoadFile("levels/1.tmx")
+//   if err != nil {
+//       panic(err)
+//   }
+//   for _, o := range m.ObjectGroup("spawns").Objects {
+//       spawn(o.Type, o.Pos)
+//   }
+//
+//   win.SetMatrix(cam.Matrix())
+//   m.Draw(win)
+package tmx
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/faiface/pixel"
+)
+
+// Map is a map made in Tiled. Only the orthogonal maps of a finite size are supported.
+type Map struct {
+	// Width and Height are the size of the map in tiles.
+	Width, Height int
+
+	// TileWidth and TileHeight are the size of a cell of the map's grid in pixels.
+	TileWidth, TileHeight int
+
+	Properties Properties
+
+	// Tilesets are sorted by their FirstGID.
+	Tilesets []*Tileset
+
+	// Layers are the tile layers, in the order they are drawn.
+	Layers []*Layer
+
+	ObjectGroups []*ObjectGroup
+}
+
+// Bounds returns the rectangle of the whole map in the world.
+func (m *Map) Bounds() pixel.Rect {
+	return pixel.R(0, 0, float64(m.Width*m.TileWidth), float64(m.Height*m.TileHeight))
+}
+
+// Layer returns the first tile layer with the name, or nil if there's none.
+func (m *Map) Layer(name string) *Layer {
+	for _, l := range m.Layers {
+		if l.Name == name {
+			return l
+		}
+	}
+	return nil
+}
+
+// ObjectGroup returns the first object group with the name, or nil if there's none.
+func (m *Map) ObjectGroup(name string) *ObjectGroup {
+	for _, g := range m.ObjectGroups {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// Tileset returns the Tileset containing the tile with the global ID (GID). It returns nil for
+// the empty tile (the GID 0) and the GIDs outside of all Tilesets.
+func (m *Map) Tileset(gid int) *Tileset {
+	if gid <= 0 {
+		return nil
+	}
+	for i := len(m.Tilesets) - 1; i >= 0; i-- {
+		ts := m.Tilesets[i]
+		if gid >= ts.FirstGID {
+			if ts.TileCount > 0 && gid-ts.FirstGID >= ts.TileCount {
+				return nil
+			}
+			return ts
+		}
+	}
+	return nil
+}
+
+// CellRect returns the rectangle of the cell in the column x and the row y (counted from the top)
+// in the world.
+func (m *Map) CellRect(x, y int) pixel.Rect {
+	min := pixel.V(float64(x*m.TileWidth), float64((m.Height-1-y)*m.TileHeight))
+	return pixel.Rect{Min: min, Max: min.Add(pixel.V(float64(m.TileWidth), float64(m.TileHeight)))}
+}
+
+// Cell returns the column and the row (counted from the top) of the cell at the world position
+// pos. They are outside of the map if pos is.
+func (m *Map) Cell(pos pixel.Vec) (x, y int) {
+	x = int(math.Floor(pos.X / float64(m.TileWidth)))
+	y = m.Height - 1 - int(math.Floor(pos.Y/float64(m.TileHeight)))
+	return x, y
+}
+
+// Draw draws the visible tile layers of the Map onto the Target.
+func (m *Map) Draw(t pixel.Target) {
+	for _, l := range m.Layers {
+		if l.Visible {
+			l.Draw(t)
+		}
+	}
+}
+
+// Tileset is a set of tiles cut from a single image.
+type Tileset struct {
+	// FirstGID is the global ID of the first tile of the Tileset in the Map.
+	FirstGID int
+
+	Name string
+
+	// TileWidth and TileHeight are the size of the tiles in pixels. Spacing is the space between
+	// them and the Margin is the space around them in the image.
+	TileWidth, TileHeight int
+	Spacing, Margin       int
+
+	TileCount, Columns int
+
+	// Offset moves the tiles when they are drawn.
+	Offset pixel.Vec
+
+	// Picture is the image of the Tileset, nil for a collection of images, which isn't drawn.
+	Picture pixel.Picture
+
+	Properties Properties
+
+	// Tiles are the tiles with a type or properties by their local IDs.
+	Tiles map[int]*TileInfo
+}
+
+// Frame returns the rectangle of the tile with the local ID in the Picture.
+func (ts *Tileset) Frame(id int) pixel.Rect {
+	b := ts.Picture.Bounds()
+	col, row := id%ts.Columns, id/ts.Columns
+	x := b.Min.X + float64(ts.Margin+col*(ts.TileWidth+ts.Spacing))
+	top := b.Max.Y - float64(ts.Margin+row*(ts.TileHeight+ts.Spacing))
+	return pixel.R(x, top-float64(ts.TileHeight), x+float64(ts.TileWidth), top)
+}
+
+// TileInfo is the additional data of a tile of a Tileset.
+type TileInfo struct {
+	ID         int
+	Type       string
+	Properties Properties
+}
+
+// Tile is a tile in a cell of a Layer: its global ID with the flip flags in the highest bits. The
+// Tile 0 is an empty cell.
+type Tile uint32
+
+// The flip flags of a Tile. A diagonal flip swaps the x and the y axis, it's done before the other
+// flips. A rotated tile is a diagonal flip combined with a horizontal or a vertical one.
+const (
+	FlippedHorizontally Tile = 0x80000000
+	FlippedVertically   Tile = 0x40000000
+	FlippedDiagonally   Tile = 0x20000000
+
+	// the rotation of the hexagonal maps, ignored
+	rotatedHexagonal Tile = 0x10000000
+
+	flags = FlippedHorizontally | FlippedVertically | FlippedDiagonally | rotatedHexagonal
+)
+
+// GID returns the global ID of the Tile without the flip flags.
+func (t Tile) GID() int {
+	return int(t &^ flags)
+}
+
+// Layer is a grid of Tiles.
+type Layer struct {
+	Name string
+
+	// Width and Height are the size of the Layer in tiles.
+	Width, Height int
+
+	// Tiles are the Tiles of the Layer row by row, starting from the top.
+	Tiles []Tile
+
+	Visible bool
+	Opacity float64
+
+	// Offset moves the Layer when it's drawn.
+	Offset pixel.Vec
+
+	Properties Properties
+
+	m     *Map
+	dirty bool
+	parts []pixel.Drawer
+}
+
+// Tile returns the Tile in the column x and the row y (counted from the top). It returns 0 outside
+// of the Layer.
+func (l *Layer) Tile(x, y int) Tile {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return 0
+	}
+	return l.Tiles[y*l.Width+x]
+}
+
+// SetTile sets the Tile in the column x and the row y (counted from the top). It does nothing
+// outside of the Layer.
+func (l *Layer) SetTile(x, y int, t Tile) {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return
+	}
+	l.Tiles[y*l.Width+x] = t
+	l.dirty = true
+}
+
+// Dirty notifies the Layer about a change of its Tiles or properties made directly, not through
+// SetTile.
+func (l *Layer) Dirty() {
+	l.dirty = true
+}
+
+// ObjectGroup is a layer of Objects.
+type ObjectGroup struct {
+	Name    string
+	Visible bool
+	Opacity float64
+
+	// Offset moves the Objects, it's already added to their positions.
+	Offset pixel.Vec
+
+	Properties Properties
+	Objects    []*Object
+}
+
+// Object returns the first Object with the name, or nil if there's none.
+func (g *ObjectGroup) Object(name string) *Object {
+	for _, o := range g.Objects {
+		if o.Name == name {
+			return o
+		}
+	}
+	return nil
+}
+
+// Object is a shape placed in a Map, such as a spawn point, a trigger area or a collision polygon.
+type Object struct {
+	ID         int
+	Name, Type string
+
+	// Pos is the position of the Object. It's the top-left corner of a rectangle or an ellipse, the
+	// bottom-left corner of a tile Object, and the origin of a polygon or a polyline.
+	Pos pixel.Vec
+
+	// Size is the size of a rectangle, an ellipse or a tile Object.
+	Size pixel.Vec
+
+	// Rotation is the rotation of the Object around its Pos in radians, counter-clockwise.
+	Rotation float64
+
+	// Tile is the Tile of a tile Object, 0 for the other Objects.
+	Tile Tile
+
+	Visible bool
+
+	// Ellipse and Point tell the shape of the Object, it's a rectangle if neither is set.
+	Ellipse, Point bool
+
+	// Polygon and Polyline are the points of a polygon or a polyline Object in the world, with the
+	// Rotation already applied.
+	Polygon, Polyline []pixel.Vec
+
+	Properties Properties
+}
+
+// Bounds returns the smallest rectangle containing the Object.
+func (o *Object) Bounds() pixel.Rect {
+	var points []pixel.Vec
+	switch {
+	case o.Polygon != nil:
+		points = o.Polygon
+	case o.Polyline != nil:
+		points = o.Polyline
+	default:
+		local := pixel.R(0, -o.Size.Y, o.Size.X, 0)
+		if o.Tile != 0 {
+			local = pixel.R(0, 0, o.Size.X, o.Size.Y)
+		}
+		m := pixel.IM.Rotated(pixel.ZV, o.Rotation).Moved(o.Pos)
+		points = []pixel.Vec{
+			m.Project(local.Min),
+			m.Project(pixel.V(local.Max.X, local.Min.Y)),
+			m.Project(local.Max),
+			m.Project(pixel.V(local.Min.X, local.Max.Y)),
+		}
+	}
+
+	b := pixel.Rect{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		b = b.Union(pixel.Rect{Min: p, Max: p})
+	}
+	return b
+}
+
+// Properties are the custom properties of a Map, a tile, a layer or an Object by their names.
+type Properties map[string]string
+
+// Bool returns the property as a bool, false if it's missing or not a bool.
+func (p Properties) Bool(name string) bool {
+	b, _ := strconv.ParseBool(p[name])
+	return b
+}
+
+// Int returns the property as an int, 0 if it's missing or not an int.
+func (p Properties) Int(name string) int {
+	i, _ := strconv.Atoi(p[name])
+	return i
+}
+
+// Float returns the property as a float64, 0 if it's missing or not a number.
+func (p Properties) Float(name string) float64 {
+	f, _ := strconv.ParseFloat(p[name], 64)
+	return f
+}
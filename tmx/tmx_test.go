@@ -0,0 +1,227 @@
+package tmx_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/raster"
+	"github.com/faiface/pixel/tmx"
+	"github.com/stretchr/testify/assert"
+)
+
+// a tileset of two 2×2 tiles, the first one is red and green on the top, blue and white on the
+// bottom, the second one is all black
+const testTileset = `<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="test" tilewidth="2" tileheight="2" tilecount="2" columns="2">
+ <image source="../images/tiles.png" width="4" height="2"/>
+ <tile id="1" type="wall">
+  <properties>
+   <property name="solid" type="bool" value="true"/>
+  </properties>
+ </tile>
+</tileset>
+`
+
+const testMap = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" renderorder="right-down" width="3" height="2" tilewidth="2" tileheight="2" infinite="0">
+ <properties>
+  <property name="title" value="Test"/>
+  <property name="gravity" type="float" value="9.8"/>
+  <property name="intro" type="string">first line
+second line</property>
+ </properties>
+ <tileset firstgid="1" source="tilesets/test.tsx"/>
+ <layer id="1" name="ground" width="3" height="2">
+  <data encoding="csv">
+1,2147483649,536870913,
+0,2,0
+</data>
+ </layer>
+ <layer id="2" name="hidden" width="3" height="2" visible="0" opacity="0.5">
+  <data encoding="base64" compression="zlib">%s</data>
+ </layer>
+ <objectgroup id="3" name="things" offsetx="1" offsety="1">
+  <object id="1" name="spawn" type="player" x="1" y="2">
+   <point/>
+  </object>
+  <object id="2" name="box" x="0" y="0" width="4" height="2"/>
+  <object id="3" name="slope" x="0" y="4">
+   <polygon points="0,0 2,0 2,-2"/>
+  </object>
+  <object id="4" name="crate" gid="2" x="2" y="4" width="2" height="2" rotation="90"/>
+ </objectgroup>
+</map>
+`
+
+func testFiles(t *testing.T) func(file string) (io.ReadCloser, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	img.Set(1, 0, color.RGBA{G: 255, A: 255})
+	img.Set(0, 1, color.RGBA{B: 255, A: 255})
+	img.Set(1, 1, color.White)
+	for x := 2; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			img.Set(x, y, color.Black)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{
+		"tilesets/test.tsx": []byte(testTileset),
+		"images/tiles.png":  buf.Bytes(),
+	}
+	return func(file string) (io.ReadCloser, error) {
+		data, ok := files[file]
+		if !ok {
+			return nil, fmt.Errorf("no file %q", file)
+		}
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+}
+
+func zlibTiles(tiles ...uint32) string {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	binary.Write(w, binary.LittleEndian, tiles)
+	w.Close()
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func loadTestMap(t *testing.T) *tmx.Map {
+	src := fmt.Sprintf(testMap, zlibTiles(2, 2, 2, 0, 0, 1))
+	m, err := tmx.Load(strings.NewReader(src), testFiles(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestLoad(t *testing.T) {
+	m := loadTestMap(t)
+
+	assert.Equal(t, 3, m.Width)
+	assert.Equal(t, 2, m.Height)
+	assert.Equal(t, pixel.R(0, 0, 6, 4), m.Bounds())
+	assert.Equal(t, "Test", m.Properties["title"])
+	assert.Equal(t, 9.8, m.Properties.Float("gravity"))
+	assert.Equal(t, "first line\nsecond line", m.Properties["intro"])
+
+	ts := m.Tilesets[0]
+	assert.Equal(t, "test", ts.Name)
+	assert.Equal(t, 2, ts.Columns)
+	assert.Equal(t, pixel.R(2, 0, 4, 2), ts.Frame(1))
+	assert.Equal(t, "wall", ts.Tiles[1].Type)
+	assert.True(t, ts.Tiles[1].Properties.Bool("solid"))
+	assert.Equal(t, ts, m.Tileset(2))
+	assert.Nil(t, m.Tileset(0))
+	assert.Nil(t, m.Tileset(3))
+
+	ground := m.Layer("ground")
+	assert.True(t, ground.Visible)
+	assert.Equal(t, 1.0, ground.Opacity)
+	assert.Equal(t, tmx.Tile(1)|tmx.FlippedHorizontally, ground.Tile(1, 0))
+	assert.Equal(t, 1, ground.Tile(1, 0).GID())
+	assert.Equal(t, 2, ground.Tile(1, 1).GID())
+	assert.Equal(t, tmx.Tile(0), ground.Tile(5, 5))
+
+	hidden := m.Layer("hidden")
+	assert.False(t, hidden.Visible)
+	assert.Equal(t, 0.5, hidden.Opacity)
+	assert.Equal(t, []tmx.Tile{2, 2, 2, 0, 0, 1}, hidden.Tiles)
+
+	x, y := m.Cell(pixel.V(3, 1))
+	assert.Equal(t, 1, x)
+	assert.Equal(t, 1, y)
+	assert.Equal(t, pixel.R(2, 0, 4, 2), m.CellRect(1, 1))
+}
+
+func TestLoad_Objects(t *testing.T) {
+	g := loadTestMap(t).ObjectGroup("things")
+	assert.Len(t, g.Objects, 4)
+
+	spawn := g.Object("spawn")
+	assert.Equal(t, "player", spawn.Type)
+	assert.True(t, spawn.Point)
+	assert.Equal(t, pixel.V(2, 1), spawn.Pos)
+
+	box := g.Object("box")
+	assert.Equal(t, pixel.V(1, 3), box.Pos)
+	assert.Equal(t, pixel.R(1, 1, 5, 3), box.Bounds())
+
+	slope := g.Object("slope")
+	assert.Equal(t, []pixel.Vec{pixel.V(1, -1), pixel.V(3, -1), pixel.V(3, 1)}, slope.Polygon)
+	assert.Equal(t, pixel.R(1, -1, 3, 1), slope.Bounds())
+
+	// rotated clockwise around the bottom-left corner
+	crate := g.Object("crate")
+	assert.Equal(t, 2, crate.Tile.GID())
+	assert.InDelta(t, -math.Pi/2, crate.Rotation, 1e-9)
+	b := crate.Bounds()
+	assert.InDelta(t, 3, b.Min.X, 1e-9)
+	assert.InDelta(t, -3, b.Min.Y, 1e-9)
+	assert.InDelta(t, 5, b.Max.X, 1e-9)
+	assert.InDelta(t, -1, b.Max.Y, 1e-9)
+}
+
+func TestMap_Draw(t *testing.T) {
+	m := loadTestMap(t)
+	c := raster.NewCanvas(m.Bounds())
+	m.Draw(c)
+
+	var (
+		red   = pixel.RGB(1, 0, 0)
+		green = pixel.RGB(0, 1, 0)
+		blue  = pixel.RGB(0, 0, 1)
+		white = pixel.RGB(1, 1, 1)
+		black = pixel.RGB(0, 0, 0)
+		empty = pixel.Alpha(0)
+	)
+	want := [][]pixel.RGBA{
+		// the top row of cells: unflipped, flipped horizontally, flipped diagonally
+		{red, green, green, red, red, blue},
+		{blue, white, white, blue, green, white},
+		// the bottom row: empty, the black tile, empty
+		{empty, empty, black, black, empty, empty},
+		{empty, empty, black, black, empty, empty},
+	}
+	for row, colors := range want {
+		for x, w := range colors {
+			y := 3 - row
+			assert.Equal(t, w, c.Color(pixel.V(float64(x), float64(y))), "pixel %d, %d", x, y)
+		}
+	}
+
+	// a changed tile is drawn after rebuilding the layer
+	m.Layer("ground").SetTile(0, 1, 2)
+	c.Clear(color.Transparent)
+	m.Draw(c)
+	assert.Equal(t, black, c.Color(pixel.V(0, 0)))
+}
+
+func TestLoad_Errors(t *testing.T) {
+	for name, src := range map[string]string{
+		"isometric":    `<map orientation="isometric" width="1" height="1" tilewidth="2" tileheight="2"/>`,
+		"infinite":     `<map orientation="orthogonal" width="1" height="1" tilewidth="2" tileheight="2" infinite="1"/>`,
+		"tile count":   `<map width="2" height="1"><layer name="l" width="2" height="1"><data encoding="csv">1</data></layer></map>`,
+		"compression":  `<map width="1" height="1"><layer name="l" width="1" height="1"><data encoding="base64" compression="zstd">AAAA</data></layer></map>`,
+		"missing file": `<map width="1" height="1"><tileset firstgid="1" source="missing.tsx"/></map>`,
+	} {
+		_, err := tmx.Load(strings.NewReader(src), testFiles(t))
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,119 @@
+package pixel
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelMinLen is the number of vertices below which ParallelVertices doesn't split the work,
+// because starting the workers would take longer than the work itself.
+const ParallelMinLen = 4096
+
+// vertexChunk is a part of the vertices processed by a worker of the transform pool.
+type vertexChunk struct {
+	f    func(i, j int)
+	i, j int
+	wg   *sync.WaitGroup
+}
+
+// transformPool are the goroutines processing the chunks of vertices of ParallelVertices. They're
+// started on the first large call.
+var transformPool struct {
+	mu      sync.RWMutex
+	workers int
+	chunks  chan vertexChunk
+}
+
+// SetTransformWorkers sets the number of goroutines transforming the vertices of large draws, such
+// as the draws of many thousands of vertices onto a Batch. The default is runtime.GOMAXPROCS(0).
+// Setting 1 transforms all the vertices on the drawing goroutine, 0 restores the default.
+func SetTransformWorkers(workers int) {
+	if workers < 0 {
+		workers = 0
+	}
+	transformPool.mu.Lock()
+	defer transformPool.mu.Unlock()
+	if transformPool.chunks != nil {
+		close(transformPool.chunks)
+		transformPool.chunks = nil
+	}
+	transformPool.workers = workers
+}
+
+// TransformWorkers returns the number of goroutines transforming the vertices of large draws. See
+// SetTransformWorkers.
+func TransformWorkers() int {
+	transformPool.mu.RLock()
+	defer transformPool.mu.RUnlock()
+	return transformWorkers()
+}
+
+// must be called with transformPool.mu locked
+func transformWorkers() int {
+	if transformPool.workers > 0 {
+		return transformPool.workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// ParallelVertices calls f with consecutive ranges [i, j) covering the n vertices [0, n). If n is
+// at least ParallelMinLen and there's more than one transform worker (see SetTransformWorkers),
+// the ranges are processed concurrently, otherwise f is called once with the range [0, n).
+//
+// ParallelVertices returns after all the calls of f have returned. It's meant for Targets that
+// transform or convert the vertices of large Triangles, f must only touch the vertices in its
+// range and must not call ParallelVertices.
+func ParallelVertices(n int, f func(i, j int)) {
+	if n < ParallelMinLen {
+		f(0, n)
+		return
+	}
+
+	transformPool.mu.RLock()
+	for transformPool.chunks == nil && transformWorkers() > 1 {
+		// the workers are started while holding the write lock
+		transformPool.mu.RUnlock()
+		startTransformPool()
+		transformPool.mu.RLock()
+	}
+	chunks := n / (ParallelMinLen / 2)
+	if workers := transformWorkers(); chunks > workers {
+		chunks = workers
+	}
+	if chunks <= 1 {
+		transformPool.mu.RUnlock()
+		f(0, n)
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(chunks - 1)
+	size := (n + chunks - 1) / chunks
+	for i := 0; i < chunks-1; i++ {
+		transformPool.chunks <- vertexChunk{f: f, i: i * size, j: (i + 1) * size, wg: &wg}
+	}
+	transformPool.mu.RUnlock()
+
+	// the last chunk is processed by the calling goroutine, which would wait anyway
+	f((chunks-1)*size, n)
+	wg.Wait()
+}
+
+func startTransformPool() {
+	transformPool.mu.Lock()
+	defer transformPool.mu.Unlock()
+	if transformPool.chunks != nil {
+		return
+	}
+	workers := transformWorkers()
+	chunks := make(chan vertexChunk, workers)
+	for i := 0; i < workers-1; i++ {
+		go func() {
+			for c := range chunks {
+				c.f(c.i, c.j)
+				c.wg.Done()
+			}
+		}()
+	}
+	transformPool.chunks = chunks
+}
@@ -0,0 +1,81 @@
+package pixel_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelVertices(t *testing.T) {
+	defer pixel.SetTransformWorkers(0)
+
+	for _, workers := range []int{1, 3, 8} {
+		pixel.SetTransformWorkers(workers)
+		assert.Equal(t, workers, pixel.TransformWorkers())
+
+		for _, n := range []int{0, 10, pixel.ParallelMinLen, 5*pixel.ParallelMinLen + 7} {
+			seen := make([]int32, n)
+			var calls int32
+			pixel.ParallelVertices(n, func(i, j int) {
+				atomic.AddInt32(&calls, 1)
+				for ; i < j; i++ {
+					atomic.AddInt32(&seen[i], 1)
+				}
+			})
+			for i := range seen {
+				if !assert.Equal(t, int32(1), seen[i], "workers %d, n %d, vertex %d", workers, n, i) {
+					break
+				}
+			}
+			if n < pixel.ParallelMinLen || workers == 1 {
+				assert.Equal(t, int32(1), calls)
+			} else {
+				assert.True(t, calls > 1 && int(calls) <= workers)
+			}
+		}
+	}
+}
+
+func TestBatch_parallel(t *testing.T) {
+	defer pixel.SetTransformWorkers(0)
+
+	tris := pixel.MakeTrianglesData(3 * pixel.ParallelMinLen)
+	for i := range *tris {
+		(*tris)[i].Position = pixel.V(float64(i), float64(-i))
+		(*tris)[i].Color = pixel.RGB(0.5, 1, 0.25)
+		(*tris)[i].Picture = pixel.V(1, float64(i))
+	}
+	mat := pixel.IM.Rotated(pixel.ZV, 1).Moved(pixel.V(3, 4))
+	mask := pixel.RGB(1, 0.5, 1)
+
+	draw := func(workers int) *pixel.TrianglesData {
+		pixel.SetTransformWorkers(workers)
+		cont := &pixel.TrianglesData{}
+		batch := pixel.NewBatch(cont, nil)
+		batch.SetMatrix(mat)
+		batch.SetColorMask(mask)
+		bt := batch.MakeTriangles(tris)
+		bt.Draw()
+		bt.Draw()
+		return cont
+	}
+
+	want := draw(1)
+	assert.Equal(t, want, draw(4))
+	assert.Equal(t, 2*tris.Len(), want.Len())
+	assert.Equal(t, mat.Project(tris.Position(5)), want.Position(tris.Len()+5))
+	assert.Equal(t, mask.Mul(tris.Color(5)), want.Color(5))
+}
+
+func BenchmarkBatchDraw_parallel(b *testing.B) {
+	tris := pixel.MakeTrianglesData(64 * 1024)
+	batch := pixel.NewBatch(&pixel.TrianglesData{}, nil)
+	batch.SetMatrix(pixel.IM.Rotated(pixel.ZV, 1))
+	bt := batch.MakeTriangles(tris)
+	for i := 0; i < b.N; i++ {
+		batch.Clear()
+		bt.Draw()
+	}
+}
@@ -0,0 +1,3 @@
+// Package tween animates values over time: numbers, vectors, rectangles, matrices and colors, with
+// easing functions, sequences, parallel groups, delays and callbacks.
+package tween
@@ -0,0 +1,124 @@
+package tween
+
+import "math"
+
+// Ease is an easing function, which maps the linear progress of a Tween within range [0, 1] to the
+// progress of the animated value. It must map 0 to 0 and 1 to 1, anything between may overshoot.
+type Ease func(t float64) float64
+
+// Linear doesn't ease at all.
+func Linear(t float64) float64 {
+	return t
+}
+
+// InQuad starts slowly and accelerates.
+func InQuad(t float64) float64 {
+	return t * t
+}
+
+// OutQuad starts quickly and decelerates.
+func OutQuad(t float64) float64 {
+	return t * (2 - t)
+}
+
+// InOutQuad accelerates in the first half and decelerates in the second.
+func InOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// InCubic starts slowly and accelerates, more than InQuad.
+func InCubic(t float64) float64 {
+	return t * t * t
+}
+
+// OutCubic starts quickly and decelerates, more than OutQuad.
+func OutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// InOutCubic accelerates in the first half and decelerates in the second, more than InOutQuad.
+func InOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
+
+// InSine starts slowly along a sine wave.
+func InSine(t float64) float64 {
+	return 1 - math.Cos(t*math.Pi/2)
+}
+
+// OutSine ends slowly along a sine wave.
+func OutSine(t float64) float64 {
+	return math.Sin(t * math.Pi / 2)
+}
+
+// InOutSine starts and ends slowly along a sine wave.
+func InOutSine(t float64) float64 {
+	return (1 - math.Cos(t*math.Pi)) / 2
+}
+
+// InExpo starts very slowly and accelerates exponentially.
+func InExpo(t float64) float64 {
+	if t == 0 {
+		return 0
+	}
+	return math.Pow(2, 10*(t-1))
+}
+
+// OutExpo starts very quickly and decelerates exponentially.
+func OutExpo(t float64) float64 {
+	if t == 1 {
+		return 1
+	}
+	return 1 - math.Pow(2, -10*t)
+}
+
+// InBack pulls back a little before going forward.
+func InBack(t float64) float64 {
+	const s = 1.70158
+	return t * t * ((s+1)*t - s)
+}
+
+// OutBack overshoots the target a little and comes back.
+func OutBack(t float64) float64 {
+	const s = 1.70158
+	t--
+	return t*t*((s+1)*t+s) + 1
+}
+
+// OutElastic overshoots the target and oscillates around it like a spring.
+func OutElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	return math.Pow(2, -10*t)*math.Sin((t-0.075)*2*math.Pi/0.3) + 1
+}
+
+// OutBounce bounces off the target like a falling ball.
+func OutBounce(t float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	}
+	t -= 2.625 / d
+	return n*t*t + 0.984375
+}
+
+// InBounce bounces a few times before leaving, the reverse of OutBounce.
+func InBounce(t float64) float64 {
+	return 1 - OutBounce(1-t)
+}
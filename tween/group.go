@@ -0,0 +1,212 @@
+package tween
+
+// Sequence runs the Animations one after another.
+type Sequence struct {
+	anims []Animation
+	i     int
+}
+
+// NewSequence creates a new Sequence of the Animations.
+func NewSequence(anims ...Animation) *Sequence {
+	return &Sequence{anims: anims}
+}
+
+// Update advances the current Animation of the Sequence by dt, and the next ones by the time left.
+func (s *Sequence) Update(dt float64) float64 {
+	for s.i < len(s.anims) {
+		dt = s.anims[s.i].Update(dt)
+		if !s.anims[s.i].Done() {
+			return 0
+		}
+		s.i++
+	}
+	return dt
+}
+
+// Done reports whether all the Animations of the Sequence have finished.
+func (s *Sequence) Done() bool {
+	return s.i >= len(s.anims)
+}
+
+// Reset rewinds all the Animations of the Sequence.
+func (s *Sequence) Reset() {
+	for _, a := range s.anims {
+		a.Reset()
+	}
+	s.i = 0
+}
+
+// Group runs the Animations at the same time, until all of them finish.
+type Group struct {
+	anims []Animation
+}
+
+// NewGroup creates a new Group of the Animations.
+func NewGroup(anims ...Animation) *Group {
+	return &Group{anims: anims}
+}
+
+// Update advances all the Animations of the Group by dt.
+func (g *Group) Update(dt float64) float64 {
+	left := dt
+	for _, a := range g.anims {
+		if l := a.Update(dt); l < left {
+			left = l
+		}
+	}
+	if !g.Done() {
+		return 0
+	}
+	return left
+}
+
+// Done reports whether all the Animations of the Group have finished.
+func (g *Group) Done() bool {
+	for _, a := range g.anims {
+		if !a.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset rewinds all the Animations of the Group.
+func (g *Group) Reset() {
+	for _, a := range g.anims {
+		a.Reset()
+	}
+}
+
+// Delay returns an Animation which does nothing for the duration in seconds, e.g. to wait in a
+// Sequence.
+func Delay(duration float64) Animation {
+	return &delay{duration: duration}
+}
+
+type delay struct {
+	duration, elapsed float64
+}
+
+func (d *delay) Update(dt float64) float64 {
+	if d.Done() {
+		return dt
+	}
+	d.elapsed += dt
+	if d.elapsed < d.duration {
+		return 0
+	}
+	return d.elapsed - d.duration
+}
+
+func (d *delay) Done() bool {
+	return d.elapsed >= d.duration
+}
+
+func (d *delay) Reset() {
+	d.elapsed = 0
+}
+
+// Call returns an Animation which calls the function once and finishes immediately, e.g. to play a
+// sound in a Sequence.
+func Call(fn func()) Animation {
+	return &call{fn: fn}
+}
+
+type call struct {
+	fn   func()
+	done bool
+}
+
+func (c *call) Update(dt float64) float64 {
+	if !c.done {
+		c.done = true
+		c.fn()
+	}
+	return dt
+}
+
+func (c *call) Done() bool {
+	return c.done
+}
+
+func (c *call) Reset() {
+	c.done = false
+}
+
+// Player runs the Animations played on it, updated from the main loop.
+//
+//   var p tween.Player
+//   p.Play(tween.Float(&door.Angle, math.Pi/2, 0.5, tween.OutBounce))
+//   for !win.Closed() {
+//       p.Update(dt)
+//       ...
+//   }
+type Player struct {
+	anims []Animation
+
+	// the Animations advanced by the running Update, those stopped from their callbacks are nil
+	updating []Animation
+}
+
+// Play starts the Animation. It runs until it finishes or it's stopped.
+func (p *Player) Play(a Animation) {
+	p.anims = append(p.anims, a)
+}
+
+// Stop stops the Animation, leaving the animated values as they are. It can be called from the
+// callbacks of the Animations too.
+func (p *Player) Stop(a Animation) {
+	for i := range p.updating {
+		if p.updating[i] == a {
+			p.updating[i] = nil
+		}
+	}
+	for i := range p.anims {
+		if p.anims[i] == a {
+			p.anims = append(p.anims[:i], p.anims[i+1:]...)
+			return
+		}
+	}
+}
+
+// StopAll stops all the Animations.
+func (p *Player) StopAll() {
+	for i := range p.updating {
+		p.updating[i] = nil
+	}
+	p.anims = nil
+}
+
+// Len returns the number of the running Animations.
+func (p *Player) Len() int {
+	n := len(p.anims)
+	for _, a := range p.updating {
+		if a != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Update advances all the running Animations by the time dt in seconds and removes the finished
+// ones.
+func (p *Player) Update(dt float64) {
+	// the Animations may play new ones from their callbacks
+	anims := p.anims
+	p.anims = nil
+	p.updating = anims
+	for i := range anims {
+		if a := anims[i]; a != nil {
+			a.Update(dt)
+		}
+	}
+	p.updating = nil
+
+	running := anims[:0]
+	for _, a := range anims {
+		if a != nil && !a.Done() {
+			running = append(running, a)
+		}
+	}
+	p.anims = append(running, p.anims...)
+}
@@ -0,0 +1,137 @@
+package tween
+
+import "github.com/faiface/pixel"
+
+// Animation is anything which runs over a time: a Tween, a Sequence, a Group, a Delay or a Call.
+//
+// Update advances the Animation by the time dt in seconds and returns the part of dt left after
+// the Animation finished, 0 if it's still running. A finished Animation returns the whole dt.
+type Animation interface {
+	Update(dt float64) (left float64)
+	Done() bool
+	Reset()
+}
+
+// Tween animates a value from the value it has when the Tween starts to the target, over the
+// duration with the easing.
+//
+//   // slide the menu in and fade in its title
+//   p.Play(tween.NewSequence(
+//       tween.Vec(&menu.Pos, pixel.V(100, 0), 0.3, tween.OutBack),
+//       tween.RGBA(&title.Color, pixel.Alpha(1), 0.2, tween.Linear),
+//   ))
+type Tween struct {
+	// OnComplete is called when the Tween finishes.
+	OnComplete func()
+
+	duration float64
+	ease     Ease
+	start    func() func(t float64)
+	set      func(t float64)
+	elapsed  float64
+	done     bool
+}
+
+// New creates a new Tween of the duration in seconds with the easing, nil is Linear. When the
+// Tween starts, it calls start, which returns the function setting the value at the eased
+// progress t.
+//
+// New is for animating values of other types, the functions such as Float and Vec create the Tweens
+// of the common ones.
+func New(duration float64, ease Ease, start func() func(t float64)) *Tween {
+	if ease == nil {
+		ease = Linear
+	}
+	return &Tween{
+		duration: duration,
+		ease:     ease,
+		start:    start,
+	}
+}
+
+// Float returns a Tween animating the float64 to the value.
+func Float(p *float64, to, duration float64, ease Ease) *Tween {
+	return New(duration, ease, func() func(t float64) {
+		from := *p
+		return func(t float64) {
+			*p = from + (to-from)*t
+		}
+	})
+}
+
+// Vec returns a Tween animating the Vec to the value.
+func Vec(p *pixel.Vec, to pixel.Vec, duration float64, ease Ease) *Tween {
+	return New(duration, ease, func() func(t float64) {
+		from := *p
+		return func(t float64) {
+			*p = pixel.Lerp(from, to, t)
+		}
+	})
+}
+
+// Rect returns a Tween animating the corners of the Rect to the value.
+func Rect(p *pixel.Rect, to pixel.Rect, duration float64, ease Ease) *Tween {
+	return New(duration, ease, func() func(t float64) {
+		from := *p
+		return func(t float64) {
+			*p = pixel.Rect{Min: pixel.Lerp(from.Min, to.Min, t), Max: pixel.Lerp(from.Max, to.Max, t)}
+		}
+	})
+}
+
+// Matrix returns a Tween animating the components of the Matrix to the value. The Matrices in the
+// middle may skew, animate the position, the scale and the angle separately for a clean rotation.
+func Matrix(p *pixel.Matrix, to pixel.Matrix, duration float64, ease Ease) *Tween {
+	return New(duration, ease, func() func(t float64) {
+		from := *p
+		return func(t float64) {
+			for i := range from {
+				p[i] = from[i] + (to[i]-from[i])*t
+			}
+		}
+	})
+}
+
+// RGBA returns a Tween animating the color to the value.
+func RGBA(p *pixel.RGBA, to pixel.RGBA, duration float64, ease Ease) *Tween {
+	return New(duration, ease, func() func(t float64) {
+		from := *p
+		return func(t float64) {
+			*p = from.Lerp(to, t)
+		}
+	})
+}
+
+// Update advances the Tween by the time dt in seconds.
+func (tw *Tween) Update(dt float64) float64 {
+	if tw.done {
+		return dt
+	}
+	if tw.set == nil {
+		tw.set = tw.start()
+	}
+	tw.elapsed += dt
+	if tw.elapsed < tw.duration {
+		tw.set(tw.ease(tw.elapsed / tw.duration))
+		return 0
+	}
+	tw.set(1)
+	tw.done = true
+	if tw.OnComplete != nil {
+		tw.OnComplete()
+	}
+	return tw.elapsed - tw.duration
+}
+
+// Done reports whether the Tween has finished.
+func (tw *Tween) Done() bool {
+	return tw.done
+}
+
+// Reset rewinds the Tween to the beginning. It starts from the current value of the animated value
+// again.
+func (tw *Tween) Reset() {
+	tw.set = nil
+	tw.elapsed = 0
+	tw.done = false
+}
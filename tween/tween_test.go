@@ -0,0 +1,158 @@
+package tween_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/tween"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEases(t *testing.T) {
+	for name, ease := range map[string]tween.Ease{
+		"Linear":     tween.Linear,
+		"InQuad":     tween.InQuad,
+		"OutQuad":    tween.OutQuad,
+		"InOutQuad":  tween.InOutQuad,
+		"InCubic":    tween.InCubic,
+		"OutCubic":   tween.OutCubic,
+		"InOutCubic": tween.InOutCubic,
+		"InSine":     tween.InSine,
+		"OutSine":    tween.OutSine,
+		"InOutSine":  tween.InOutSine,
+		"InExpo":     tween.InExpo,
+		"OutExpo":    tween.OutExpo,
+		"InBack":     tween.InBack,
+		"OutBack":    tween.OutBack,
+		"OutElastic": tween.OutElastic,
+		"InBounce":   tween.InBounce,
+		"OutBounce":  tween.OutBounce,
+	} {
+		assert.InDelta(t, 0, ease(0), 1e-9, name)
+		assert.InDelta(t, 1, ease(1), 1e-9, name)
+	}
+	assert.InDelta(t, 0.5, tween.InOutQuad(0.5), 1e-9)
+	assert.True(t, tween.OutBack(0.8) > 1)
+}
+
+func TestTween(t *testing.T) {
+	x := 10.0
+	completed := 0
+	tw := tween.Float(&x, 20, 2, nil)
+	tw.OnComplete = func() { completed++ }
+
+	// the starting value is taken when the Tween starts
+	x = 0
+	assert.Equal(t, 0.0, tw.Update(1))
+	assert.Equal(t, 10.0, x)
+	assert.False(t, tw.Done())
+
+	assert.Equal(t, 0.5, tw.Update(1.5))
+	assert.Equal(t, 20.0, x)
+	assert.True(t, tw.Done())
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, 1.0, tw.Update(1))
+	assert.Equal(t, 1, completed)
+
+	tw.Reset()
+	x = 30
+	tw.Update(1)
+	assert.Equal(t, 25.0, x)
+}
+
+func TestTween_Types(t *testing.T) {
+	v := pixel.ZV
+	tween.Vec(&v, pixel.V(4, 2), 1, tween.Linear).Update(0.5)
+	assert.Equal(t, pixel.V(2, 1), v)
+
+	r := pixel.R(0, 0, 2, 2)
+	tween.Rect(&r, pixel.R(2, 2, 6, 6), 1, tween.Linear).Update(0.5)
+	assert.Equal(t, pixel.R(1, 1, 4, 4), r)
+
+	m := pixel.IM
+	tween.Matrix(&m, pixel.IM.Moved(pixel.V(10, 0)), 1, tween.Linear).Update(0.5)
+	assert.Equal(t, pixel.IM.Moved(pixel.V(5, 0)), m)
+
+	c := pixel.RGB(1, 0, 0)
+	tween.RGBA(&c, pixel.RGB(0, 0, 1), 1, tween.Linear).Update(0.5)
+	assert.Equal(t, pixel.RGB(0.5, 0, 0.5), c)
+}
+
+func TestSequenceAndGroup(t *testing.T) {
+	var x, y float64
+	var log []string
+	seq := tween.NewSequence(
+		tween.Float(&x, 1, 1, nil),
+		tween.Delay(1),
+		tween.Call(func() { log = append(log, "called") }),
+		tween.NewGroup(
+			tween.Float(&x, 0, 1, nil),
+			tween.Float(&y, 1, 2, nil),
+		),
+	)
+
+	// the time left from the first Tween goes to the Delay
+	seq.Update(1.5)
+	assert.Equal(t, 1.0, x)
+	assert.Empty(t, log)
+
+	// the Call runs immediately after the Delay
+	seq.Update(1)
+	assert.Equal(t, []string{"called"}, log)
+	assert.InDelta(t, 0.5, x, 1e-9)
+	assert.InDelta(t, 0.25, y, 1e-9)
+
+	assert.Equal(t, 0.0, seq.Update(1))
+	assert.Equal(t, 0.0, x)
+	assert.False(t, seq.Done())
+	assert.Equal(t, 0.5, seq.Update(1))
+	assert.Equal(t, 1.0, y)
+	assert.True(t, seq.Done())
+}
+
+func TestPlayer(t *testing.T) {
+	var p tween.Player
+	var x float64
+	a := tween.Float(&x, 1, 1, nil)
+	b := tween.Delay(5)
+	p.Play(a)
+	p.Play(b)
+	assert.Equal(t, 2, p.Len())
+
+	p.Update(1)
+	assert.Equal(t, 1.0, x)
+	assert.Equal(t, 1, p.Len())
+
+	p.Stop(b)
+	assert.Equal(t, 0, p.Len())
+
+	// an Animation started from a callback
+	p.Play(tween.Call(func() { p.Play(tween.Delay(1)) }))
+	p.Update(0)
+	assert.Equal(t, 1, p.Len())
+	p.StopAll()
+	assert.Equal(t, 0, p.Len())
+}
+
+func TestPlayer_StopFromCallback(t *testing.T) {
+	var p tween.Player
+	var x, y float64
+	a := tween.Float(&x, 1, 1, nil)
+	b := tween.Float(&y, 1, 2, nil)
+	a.OnComplete = func() { p.Stop(b) }
+	p.Play(a)
+	p.Play(b)
+	p.Update(1)
+	assert.Equal(t, 0, p.Len())
+	p.Update(1)
+	assert.Equal(t, 0.0, y, "b was stopped by the callback of a before its update")
+
+	y = 0
+	p.Play(b)
+	p.Play(tween.Call(p.StopAll))
+	p.Play(tween.Float(&x, 2, 1, nil))
+	p.Update(0.5)
+	assert.Equal(t, 0, p.Len())
+	p.Update(1)
+	assert.Equal(t, 1.0, x, "stopped before its update")
+}
@@ -0,0 +1,356 @@
+// Package ui implements an immediate mode GUI for the tools and the debug panels of a game. The
+// widgets are functions called every frame, which draw themselves and return the input they got,
+// there's no state to keep in sync with the game.
+//
+//   ctx := ui.NewContext()
+//   for !win.Closed() {
+//       ctx.Begin(win)
+//       ctx.BeginWindow("Debug", pixel.V(10, 590), 200)
+//       ctx.Label(fmt.Sprintf("FPS: %d", fps))
+//       ctx.Checkbox("Wireframe", &wireframe)
+//       ctx.Slider("Zoom", &zoom, 0.5, 4)
+//       if ctx.Button("Reset") {
+//           world.Reset()
+//       }
+//       ctx.EndWindow()
+//       ctx.End()
+//
+//       if !ctx.WantsMouse() {
+//           // the game input
+//       }
+//       ...
+//       ctx.Draw(win)
+//       win.Update()
+//   }
+//
+// The widgets are identified by their labels within a window. A label can end with "##" and a
+// suffix, which isn't shown, to tell apart the widgets of the same label.
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+)
+
+// Input is the input of a Context, such as a *pixelgl.Window.
+type Input interface {
+	MousePosition() pixel.Vec
+	Pressed(button pixelgl.Button) bool
+	JustPressed(button pixelgl.Button) bool
+	JustReleased(button pixelgl.Button) bool
+	Repeated(button pixelgl.Button) bool
+	Typed() string
+}
+
+// Style is the look of the widgets of a Context.
+type Style struct {
+	// Padding is the space between the border and the content of the windows and the widgets.
+	Padding float64
+
+	// Spacing is the space between the widgets.
+	Spacing float64
+
+	Text    color.Color
+	Window  color.Color
+	Title   color.Color
+	Widget  color.Color
+	Hovered color.Color
+	Active  color.Color
+	Accent  color.Color
+}
+
+// DefaultStyle returns the default Style of a Context, light text on dark translucent windows.
+func DefaultStyle() Style {
+	return Style{
+		Padding: 4,
+		Spacing: 4,
+		Text:    pixel.RGB(0.9, 0.9, 0.9),
+		Window:  pixel.RGBA{R: 0.1, G: 0.1, B: 0.12, A: 0.9},
+		Title:   pixel.RGB(0.2, 0.25, 0.4),
+		Widget:  pixel.RGB(0.25, 0.25, 0.3),
+		Hovered: pixel.RGB(0.35, 0.35, 0.45),
+		Active:  pixel.RGB(0.45, 0.45, 0.6),
+		Accent:  pixel.RGB(0.4, 0.6, 1),
+	}
+}
+
+// Context is the state of an immediate mode GUI. Every frame, the widgets are called between Begin
+// and End, and then drawn by Draw.
+type Context struct {
+	Style Style
+	Atlas *text.Atlas
+
+	in        Input
+	mouse     pixel.Vec
+	prevMouse pixel.Vec
+
+	// hot is the widget under the mouse, active is the widget held by the mouse and focus is the
+	// widget taking the keyboard
+	hot, active, focus string
+
+	windows map[string]*window
+	order   []*window
+	cur     *window
+	hovered *window
+}
+
+type window struct {
+	title string
+	pos   pixel.Vec
+	width float64
+	rect  pixel.Rect
+	used  bool
+
+	cursor  pixel.Vec
+	row     int
+	rowX    float64
+	rowCols int
+	rowH    float64
+
+	bg  *imdraw.IMDraw
+	imd *imdraw.IMDraw
+	txt *text.Text
+}
+
+// NewContext creates a new Context of the DefaultStyle, drawing the text by text.Atlas7x13.
+func NewContext() *Context {
+	return &Context{
+		Style:   DefaultStyle(),
+		Atlas:   text.Atlas7x13,
+		windows: make(map[string]*window),
+	}
+}
+
+// Begin starts a frame of the GUI with the Input.
+func (ctx *Context) Begin(in Input) {
+	ctx.in = in
+	ctx.prevMouse, ctx.mouse = ctx.mouse, in.MousePosition()
+	ctx.hot = ""
+
+	// the windows are hit by the mouse in the order they were drawn in the previous frame
+	ctx.hovered = nil
+	for i := len(ctx.order) - 1; i >= 0; i-- {
+		if w := ctx.order[i]; w.used && w.rect.Contains(ctx.mouse) {
+			ctx.hovered = w
+			break
+		}
+	}
+	for _, w := range ctx.order {
+		w.used = false
+	}
+}
+
+// End ends the frame of the GUI.
+func (ctx *Context) End() {
+	if ctx.cur != nil {
+		panic(fmt.Errorf("(%T).End: window %q not ended", ctx, ctx.cur.title))
+	}
+	if !ctx.in.Pressed(pixelgl.MouseButtonLeft) {
+		ctx.active = ""
+	}
+}
+
+// WantsMouse reports whether the mouse is over a window or holds a widget, so the game should
+// ignore it.
+func (ctx *Context) WantsMouse() bool {
+	return ctx.hovered != nil || ctx.active != ""
+}
+
+// WantsKeyboard reports whether a widget takes the keyboard, so the game should ignore it.
+func (ctx *Context) WantsKeyboard() bool {
+	return ctx.focus != ""
+}
+
+// Draw draws the windows used in the last frame onto the Target, the last focused one on top.
+func (ctx *Context) Draw(t pixel.Target) {
+	for _, w := range ctx.order {
+		if !w.used {
+			continue
+		}
+		w.bg.Draw(t)
+		w.imd.Draw(t)
+		w.txt.Draw(t, pixel.IM)
+	}
+}
+
+// BeginWindow starts a window of the title and the width, with the top-left corner at the position
+// the first time, which then can be dragged by its title bar. The window is as high as the widgets
+// called before EndWindow.
+func (ctx *Context) BeginWindow(title string, pos pixel.Vec, width float64) {
+	if ctx.cur != nil {
+		panic(fmt.Errorf("(%T).BeginWindow: window %q not ended", ctx, ctx.cur.title))
+	}
+	w, ok := ctx.windows[title]
+	if !ok {
+		w = &window{title: title, pos: pos, rect: pixel.R(pos.X, pos.Y, pos.X+width, pos.Y)}
+		w.bg, w.imd = imdraw.New(nil), imdraw.New(nil)
+		ctx.windows[title] = w
+		ctx.order = append(ctx.order, w)
+	}
+	if w.txt == nil || w.txt.Atlas() != ctx.Atlas {
+		w.txt = text.New(pixel.ZV, ctx.Atlas)
+	}
+	w.width, w.used = width, true
+	w.bg.Clear()
+	w.imd.Clear()
+	w.txt.Clear()
+	ctx.cur = w
+
+	if ctx.hovered == w && ctx.in.JustPressed(pixelgl.MouseButtonLeft) {
+		ctx.raise(w)
+	}
+
+	// the title bar
+	pad := ctx.Style.Padding
+	titleH := ctx.lineHeight() + 2*pad
+	bar := pixel.R(w.pos.X, w.pos.Y-titleH, w.pos.X+width, w.pos.Y)
+	id := "\x00title/" + title
+	if _, held, _ := ctx.behavior(id, bar); held && !ctx.in.JustPressed(pixelgl.MouseButtonLeft) {
+		w.pos = w.pos.Add(ctx.mouse.Sub(ctx.prevMouse))
+		bar = bar.Moved(ctx.mouse.Sub(ctx.prevMouse))
+	}
+	w.imd.Color = ctx.Style.Title
+	w.imd.Push(bar.Min, bar.Max)
+	w.imd.Rectangle(0)
+	ctx.text(bar.Min.X+pad, bar, title, ctx.Style.Text)
+
+	w.cursor = pixel.V(w.pos.X+pad, bar.Min.Y-pad)
+	w.rowCols = 0
+}
+
+// EndWindow ends the current window.
+func (ctx *Context) EndWindow() {
+	w := ctx.window("EndWindow")
+	ctx.endRow(w)
+	bottom := w.cursor.Y + ctx.Style.Spacing - ctx.Style.Padding
+	w.rect = pixel.R(w.pos.X, bottom, w.pos.X+w.width, w.pos.Y)
+	w.bg.Color = ctx.Style.Window
+	w.bg.Push(w.rect.Min, w.rect.Max)
+	w.bg.Rectangle(0)
+	ctx.cur = nil
+}
+
+// Row places the next cols widgets in the current window next to each other, in the columns of the
+// same width.
+func (ctx *Context) Row(cols int) {
+	w := ctx.window("Row")
+	ctx.endRow(w)
+	if cols > 1 {
+		w.rowCols, w.row, w.rowX, w.rowH = cols, 0, w.cursor.X, 0
+	}
+}
+
+// next returns the rectangle of the next widget of the height in the current window
+func (ctx *Context) next(w *window, height float64) pixel.Rect {
+	pad, spacing := ctx.Style.Padding, ctx.Style.Spacing
+	width := w.width - 2*pad
+	if w.rowCols > 0 {
+		colW := (width - float64(w.rowCols-1)*spacing) / float64(w.rowCols)
+		r := pixel.R(w.rowX, w.cursor.Y-height, w.rowX+colW, w.cursor.Y)
+		w.rowX += colW + spacing
+		if height > w.rowH {
+			w.rowH = height
+		}
+		if w.row++; w.row == w.rowCols {
+			ctx.endRow(w)
+		}
+		return r
+	}
+	r := pixel.R(w.cursor.X, w.cursor.Y-height, w.cursor.X+width, w.cursor.Y)
+	w.cursor.Y -= height + spacing
+	return r
+}
+
+func (ctx *Context) endRow(w *window) {
+	if w.rowCols > 0 && w.row > 0 {
+		w.cursor.Y -= w.rowH + ctx.Style.Spacing
+	}
+	w.rowCols = 0
+}
+
+// raise moves the window on top of the others
+func (ctx *Context) raise(w *window) {
+	for i := range ctx.order {
+		if ctx.order[i] == w {
+			copy(ctx.order[i:], ctx.order[i+1:])
+			ctx.order[len(ctx.order)-1] = w
+			return
+		}
+	}
+}
+
+// window returns the current window, or panics in a method called outside of a window
+func (ctx *Context) window(method string) *window {
+	if ctx.cur == nil {
+		panic(fmt.Errorf("(%T).%s: outside of a window", ctx, method))
+	}
+	return ctx.cur
+}
+
+// behavior handles the mouse on the widget of the id in the rectangle of the current window. It
+// returns whether the widget is hovered, held by the mouse and clicked by releasing the mouse
+// over it.
+func (ctx *Context) behavior(id string, r pixel.Rect) (hovered, held, clicked bool) {
+	hovered = ctx.hovered == ctx.cur && r.Contains(ctx.mouse) && (ctx.active == "" || ctx.active == id)
+	if hovered {
+		ctx.hot = id
+		if ctx.in.JustPressed(pixelgl.MouseButtonLeft) {
+			ctx.active = id
+		}
+	}
+	held = ctx.active == id
+	clicked = held && hovered && ctx.in.JustReleased(pixelgl.MouseButtonLeft)
+	return hovered, held, clicked
+}
+
+// widgetColor returns the color of a widget by its state
+func (ctx *Context) widgetColor(hovered, held bool) color.Color {
+	switch {
+	case held:
+		return ctx.Style.Active
+	case hovered:
+		return ctx.Style.Hovered
+	}
+	return ctx.Style.Widget
+}
+
+func (ctx *Context) lineHeight() float64 {
+	return ctx.Atlas.LineHeight()
+}
+
+// widgetHeight returns the height of the widgets with a line of text
+func (ctx *Context) widgetHeight() float64 {
+	return ctx.lineHeight() + 2*ctx.Style.Padding
+}
+
+// text writes the text from x, centered vertically in the rectangle
+func (ctx *Context) text(x float64, r pixel.Rect, s string, col color.Color) {
+	txt := ctx.cur.txt
+	txt.Color = col
+	txt.Dot = pixel.V(x, r.Min.Y+(r.H()-ctx.lineHeight())/2+ctx.Atlas.Descent())
+	txt.WriteString(s)
+}
+
+// textWidth returns the width of the text drawn by the Atlas
+func (ctx *Context) textWidth(s string) float64 {
+	return ctx.cur.txt.BoundsOf(s).W()
+}
+
+// id returns the id of the widget of the label in the current window
+func (ctx *Context) id(label string) string {
+	return ctx.cur.title + "/" + label
+}
+
+// display returns the label without the "##" suffix
+func display(label string) string {
+	if i := strings.Index(label, "##"); i >= 0 {
+		return label[:i]
+	}
+	return label
+}
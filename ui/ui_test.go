@@ -0,0 +1,215 @@
+package ui_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/raster"
+	"github.com/faiface/pixel/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+// input is the Input of a single frame, the left mouse button is down or up and changed if it's
+// just pressed or released
+type input struct {
+	mouse   pixel.Vec
+	down    bool
+	changed bool
+	keys    []pixelgl.Button
+	typed   string
+}
+
+func (in input) MousePosition() pixel.Vec { return in.mouse }
+
+func (in input) Pressed(button pixelgl.Button) bool {
+	return button == pixelgl.MouseButtonLeft && in.down
+}
+
+func (in input) JustPressed(button pixelgl.Button) bool {
+	if button == pixelgl.MouseButtonLeft {
+		return in.down && in.changed
+	}
+	for _, k := range in.keys {
+		if k == button {
+			return true
+		}
+	}
+	return false
+}
+
+func (in input) JustReleased(button pixelgl.Button) bool {
+	return button == pixelgl.MouseButtonLeft && !in.down && in.changed
+}
+
+func (in input) Repeated(button pixelgl.Button) bool { return in.JustPressed(button) }
+func (in input) Typed() string                       { return in.typed }
+
+// panel is a window with a button, a checkbox, a slider and a text input
+type panel struct {
+	clicked int
+	check   bool
+	value   float64
+	text    string
+}
+
+func (p *panel) frame(ctx *ui.Context, in input) {
+	ctx.Begin(in)
+	ctx.BeginWindow("Panel", pixel.V(0, 200), 100)
+	ctx.Label("Hello")
+	if ctx.Button("Click") {
+		p.clicked++
+	}
+	ctx.Checkbox("Check", &p.check)
+	ctx.Slider("Value", &p.value, 0, 10)
+	ctx.TextInput("Name", &p.text)
+	ctx.EndWindow()
+	ctx.End()
+}
+
+// click clicks at the position by pressing the mouse in one frame and releasing it in the next one
+func (p *panel) click(ctx *ui.Context, pos pixel.Vec) {
+	p.frame(ctx, input{mouse: pos, down: true, changed: true})
+	p.frame(ctx, input{mouse: pos, changed: true})
+}
+
+// The layout of the Panel with the Atlas7x13 of the line height 13, the padding and the spacing 4:
+// the title bar from 200 to 179, Hello from 175 to 162, Click from 158 to 137, Check from 133 to
+// 112, Value from 108 to 87 and Name from 83 to 62.
+var (
+	clickPos = pixel.V(50, 147)
+	checkPos = pixel.V(10, 122)
+	valueY   = 97.0
+	namePos  = pixel.V(50, 72)
+	titlePos = pixel.V(50, 190)
+)
+
+func TestButtonAndCheckbox(t *testing.T) {
+	ctx := ui.NewContext()
+	var p panel
+	p.frame(ctx, input{})
+
+	p.click(ctx, clickPos)
+	assert.Equal(t, 1, p.clicked)
+	p.click(ctx, checkPos)
+	assert.Equal(t, 1, p.clicked)
+	assert.True(t, p.check)
+	p.click(ctx, checkPos)
+	assert.False(t, p.check)
+
+	// pressed on the button, released elsewhere
+	p.frame(ctx, input{mouse: clickPos, down: true, changed: true})
+	p.frame(ctx, input{mouse: checkPos, down: true})
+	p.frame(ctx, input{mouse: checkPos, changed: true})
+	assert.Equal(t, 1, p.clicked)
+	assert.False(t, p.check)
+
+	p.click(ctx, pixel.V(150, 147))
+	assert.Equal(t, 1, p.clicked, "outside of the window")
+}
+
+func TestSlider(t *testing.T) {
+	ctx := ui.NewContext()
+	var p panel
+	p.frame(ctx, input{})
+
+	p.frame(ctx, input{mouse: pixel.V(4+46, valueY), down: true, changed: true})
+	assert.InDelta(t, 5, p.value, 1e-9)
+	p.frame(ctx, input{mouse: pixel.V(300, 0), down: true})
+	assert.Equal(t, 10.0, p.value, "dragged outside")
+	assert.True(t, ctx.WantsMouse())
+	p.frame(ctx, input{mouse: pixel.V(300, 0), changed: true})
+	p.frame(ctx, input{mouse: pixel.V(4, valueY)})
+	assert.Equal(t, 10.0, p.value, "released")
+}
+
+func TestTextInput(t *testing.T) {
+	ctx := ui.NewContext()
+	var p panel
+	p.frame(ctx, input{})
+
+	p.frame(ctx, input{typed: "x"})
+	assert.Equal(t, "", p.text, "not focused")
+
+	p.click(ctx, namePos)
+	assert.True(t, ctx.WantsKeyboard())
+	p.frame(ctx, input{mouse: namePos, typed: "abc"})
+	p.frame(ctx, input{mouse: namePos, keys: []pixelgl.Button{pixelgl.KeyBackspace}})
+	assert.Equal(t, "ab", p.text)
+	p.frame(ctx, input{mouse: namePos, keys: []pixelgl.Button{pixelgl.KeyEnter}})
+	assert.False(t, ctx.WantsKeyboard())
+
+	p.click(ctx, namePos)
+	p.click(ctx, pixel.V(150, 0))
+	assert.False(t, ctx.WantsKeyboard(), "clicked elsewhere")
+}
+
+func TestWindowDrag(t *testing.T) {
+	ctx := ui.NewContext()
+	var p panel
+	p.frame(ctx, input{})
+
+	p.frame(ctx, input{mouse: titlePos, down: true, changed: true})
+	p.frame(ctx, input{mouse: titlePos.Add(pixel.V(100, -50)), down: true})
+	p.frame(ctx, input{mouse: titlePos.Add(pixel.V(100, -50)), changed: true})
+
+	p.click(ctx, clickPos)
+	assert.Equal(t, 0, p.clicked, "the window moved")
+	p.click(ctx, clickPos.Add(pixel.V(100, -50)))
+	assert.Equal(t, 1, p.clicked)
+}
+
+func TestRowAndOverlap(t *testing.T) {
+	ctx := ui.NewContext()
+	var a, b int
+	frame := func(in input) {
+		ctx.Begin(in)
+		ctx.BeginWindow("A", pixel.V(0, 100), 100)
+		ctx.Row(2)
+		if ctx.Button("L") {
+			a--
+		}
+		if ctx.Button("R") {
+			a++
+		}
+		ctx.EndWindow()
+		ctx.BeginWindow("B", pixel.V(50, 100), 100)
+		if ctx.Button("B##button") {
+			b++
+		}
+		ctx.EndWindow()
+		ctx.End()
+	}
+	click := func(pos pixel.Vec) {
+		frame(input{mouse: pos, down: true, changed: true})
+		frame(input{mouse: pos, changed: true})
+	}
+	frame(input{})
+
+	click(pixel.V(75, 70))
+	assert.Equal(t, 0, a)
+	assert.Equal(t, 1, b, "B is on top")
+
+	// A comes to the front after a click on it
+	click(pixel.V(10, 70))
+	assert.Equal(t, -1, a)
+	click(pixel.V(75, 70))
+	assert.Equal(t, 0, a)
+	assert.Equal(t, 1, b)
+
+	assert.Panics(t, func() { ctx.Button("outside") })
+}
+
+func TestDraw(t *testing.T) {
+	canvas := raster.NewCanvas(pixel.R(0, 0, 200, 200))
+	ctx := ui.NewContext()
+	ctx.Style.Window = pixel.RGB(0, 0, 1)
+	ctx.Style.Title = pixel.RGB(1, 0, 0)
+	var p panel
+	p.frame(ctx, input{})
+	ctx.Draw(canvas)
+	assert.Equal(t, pixel.RGB(1, 0, 0), canvas.Color(pixel.V(95, 198)))
+	assert.Equal(t, pixel.RGB(0, 0, 1), canvas.Color(pixel.V(1, 100)))
+	assert.Equal(t, pixel.Alpha(0), canvas.Color(pixel.V(150, 100)))
+	assert.Equal(t, pixel.Alpha(0), canvas.Color(pixel.V(50, 50)))
+}
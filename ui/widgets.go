@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// Label draws the text in the current window.
+func (ctx *Context) Label(s string) {
+	w := ctx.window("Label")
+	r := ctx.next(w, ctx.lineHeight())
+	ctx.text(r.Min.X, r, s, ctx.Style.Text)
+}
+
+// Button draws a button of the label in the current window and reports whether it was clicked.
+func (ctx *Context) Button(label string) bool {
+	w := ctx.window("Button")
+	r := ctx.next(w, ctx.widgetHeight())
+	hovered, held, clicked := ctx.behavior(ctx.id(label), r)
+
+	w.imd.Color = ctx.widgetColor(hovered, held)
+	w.imd.Push(r.Min, r.Max)
+	w.imd.Rectangle(0)
+	s := display(label)
+	ctx.text(r.Center().X-math.Round(ctx.textWidth(s)/2), r, s, ctx.Style.Text)
+	return clicked
+}
+
+// Checkbox draws a checkbox of the label in the current window, toggles the value when it's
+// clicked and reports whether it was.
+func (ctx *Context) Checkbox(label string, value *bool) bool {
+	w := ctx.window("Checkbox")
+	r := ctx.next(w, ctx.widgetHeight())
+	hovered, held, clicked := ctx.behavior(ctx.id(label), r)
+	if clicked {
+		*value = !*value
+	}
+
+	box := pixel.R(r.Min.X, r.Min.Y, r.Min.X+r.H(), r.Max.Y)
+	w.imd.Color = ctx.widgetColor(hovered, held)
+	w.imd.Push(box.Min, box.Max)
+	w.imd.Rectangle(0)
+	if *value {
+		inset := ctx.Style.Padding
+		w.imd.Color = ctx.Style.Accent
+		w.imd.Push(box.Min.Add(pixel.V(inset, inset)), box.Max.Sub(pixel.V(inset, inset)))
+		w.imd.Rectangle(0)
+	}
+	ctx.text(box.Max.X+ctx.Style.Padding, r, display(label), ctx.Style.Text)
+	return clicked
+}
+
+// Slider draws a slider of the label in the current window, sets the value within range [min, max]
+// when it's dragged and reports whether it changed.
+func (ctx *Context) Slider(label string, value *float64, min, max float64) bool {
+	w := ctx.window("Slider")
+	r := ctx.next(w, ctx.widgetHeight())
+	hovered, held, _ := ctx.behavior(ctx.id(label), r)
+
+	old := *value
+	if held && r.W() > 0 {
+		t := pixel.Clamp((ctx.mouse.X-r.Min.X)/r.W(), 0, 1)
+		*value = min + t*(max-min)
+	}
+	*value = pixel.Clamp(*value, math.Min(min, max), math.Max(min, max))
+
+	w.imd.Color = ctx.widgetColor(hovered, held)
+	w.imd.Push(r.Min, r.Max)
+	w.imd.Rectangle(0)
+	if max != min {
+		t := (*value - min) / (max - min)
+		w.imd.Color = ctx.Style.Accent
+		w.imd.Push(r.Min, pixel.V(r.Min.X+t*r.W(), r.Max.Y))
+		w.imd.Rectangle(0)
+	}
+	s := fmt.Sprintf("%s: %.3g", display(label), *value)
+	ctx.text(r.Center().X-math.Round(ctx.textWidth(s)/2), r, s, ctx.Style.Text)
+	return *value != old
+}
+
+// TextInput draws a text field of the label in the current window, which takes the keyboard after
+// it's clicked until Enter or a click elsewhere, edits the value and reports whether it changed.
+func (ctx *Context) TextInput(label string, value *string) bool {
+	w := ctx.window("TextInput")
+	r := ctx.next(w, ctx.widgetHeight())
+	id := ctx.id(label)
+	hovered, held, _ := ctx.behavior(id, r)
+
+	if ctx.in.JustPressed(pixelgl.MouseButtonLeft) {
+		if held {
+			ctx.focus = id
+		} else if ctx.focus == id {
+			ctx.focus = ""
+		}
+	}
+	focused := ctx.focus == id
+
+	old := *value
+	if focused {
+		*value += ctx.in.Typed()
+		if ctx.in.Repeated(pixelgl.KeyBackspace) && len(*value) > 0 {
+			runes := []rune(*value)
+			*value = string(runes[:len(runes)-1])
+		}
+		if ctx.in.JustPressed(pixelgl.KeyEnter) || ctx.in.JustPressed(pixelgl.KeyEscape) {
+			ctx.focus = ""
+		}
+	}
+
+	w.imd.Color = ctx.widgetColor(hovered, held)
+	w.imd.Push(r.Min, r.Max)
+	w.imd.Rectangle(0)
+	x := r.Min.X + ctx.Style.Padding
+	s := *value
+	if s == "" && !focused {
+		ctx.text(x, r, display(label), ctx.Style.Hovered)
+	} else {
+		ctx.text(x, r, s, ctx.Style.Text)
+	}
+	if focused {
+		cursorX := math.Round(x+ctx.textWidth(s)) + 0.5
+		w.imd.Color = ctx.Style.Accent
+		w.imd.Push(pixel.V(cursorX, r.Min.Y+ctx.Style.Padding), pixel.V(cursorX, r.Max.Y-ctx.Style.Padding))
+		w.imd.Line(1)
+	}
+	return *value != old
+}
+
+// Separator draws a horizontal line in the current window.
+func (ctx *Context) Separator() {
+	w := ctx.window("Separator")
+	r := ctx.next(w, 1)
+	w.imd.Color = ctx.Style.Widget
+	w.imd.Push(r.Min, r.Max)
+	w.imd.Rectangle(0)
+}
@@ -0,0 +1,315 @@
+// Package vector implements a Target recording the drawn triangles as vector shapes, which are
+// written out as SVG or PDF, so the diagrams and the level overviews drawn with Pixel export
+// losslessly at any resolution.
+//
+// Anything drawing onto a Target draws onto a Canvas, e.g. IMDraw and Sprites:
+//
+//   c := vector.NewCanvas(pixel.R(0, 0, 800, 600))
+//   imd.Draw(c)
+//   sprite.Draw(c, pixel.IM.Moved(c.Bounds().Center()))
+//   err := c.WriteSVG(file)
+//
+// The triangles are filled by their average color, because SVG and PDF can't interpolate the
+// colors of the vertices. The Pictures are embedded as images, mapped onto the triangles exactly,
+// their color mask only changes their opacity.
+package vector
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/faiface/pixel"
+)
+
+// Canvas is a BasicTarget recording the triangles drawn onto it as vector shapes.
+//
+// It supports TrianglesPosition, TrianglesColor, TrianglesPicture and PictureColor, like the other
+// Targets.
+type Canvas struct {
+	bounds pixel.Rect
+	mat    pixel.Matrix
+	col    pixel.RGBA
+
+	shapes []shape
+	pics   []*pixel.PictureData
+	picIDs map[*pixel.PictureData]int
+}
+
+var _ pixel.BasicTarget = (*Canvas)(nil)
+
+// shape is a recorded triangle, in the coordinates of the Canvas
+type shape struct {
+	pts   [3]pixel.Vec
+	color pixel.RGBA
+
+	// pic is the index of the Picture plus one, zero for no Picture, and tex are the positions of
+	// the vertices in the Picture
+	pic int
+	tex [3]pixel.Vec
+}
+
+// NewCanvas creates a new empty Canvas with the bounds, which are the bounds of the exported
+// document.
+func NewCanvas(bounds pixel.Rect) *Canvas {
+	return &Canvas{
+		bounds: bounds,
+		mat:    pixel.IM,
+		col:    pixel.Alpha(1),
+		picIDs: make(map[*pixel.PictureData]int),
+	}
+}
+
+// Bounds returns the bounds of the Canvas.
+func (c *Canvas) Bounds() pixel.Rect {
+	return c.bounds
+}
+
+// SetBounds sets the bounds of the Canvas. The recorded shapes are kept.
+func (c *Canvas) SetBounds(bounds pixel.Rect) {
+	c.bounds = bounds
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (c *Canvas) SetMatrix(m pixel.Matrix) {
+	c.mat = m
+}
+
+// SetColorMask sets a color that every color in triangles or a picture will be multiplied by.
+func (c *Canvas) SetColorMask(col color.Color) {
+	if col == nil {
+		c.col = pixel.Alpha(1)
+		return
+	}
+	c.col = pixel.ToRGBA(col)
+}
+
+// Clear removes all the recorded shapes.
+func (c *Canvas) Clear() {
+	c.shapes = nil
+	c.pics = nil
+	c.picIDs = make(map[*pixel.PictureData]int)
+}
+
+// Len returns the number of the recorded triangles.
+func (c *Canvas) Len() int {
+	return len(c.shapes)
+}
+
+// MakeTriangles creates a specialized copy of the supplied Triangles that draws onto this Canvas.
+func (c *Canvas) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	td := pixel.MakeTrianglesData(t.Len())
+	td.Update(t)
+	return &canvasTriangles{
+		data: td,
+		dst:  c,
+	}
+}
+
+// MakePicture create a specialized copy of the supplied Picture that draws onto this Canvas.
+func (c *Canvas) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	if cp, ok := p.(*canvasPicture); ok {
+		return &canvasPicture{pd: cp.pd, dst: c}
+	}
+	return &canvasPicture{
+		pd:  pixel.PictureDataFromPicture(p),
+		dst: c,
+	}
+}
+
+// record records the triangles with the current matrix and color mask. The picture may be nil.
+func (c *Canvas) record(td pixel.TrianglesData, pd *pixel.PictureData) {
+	for i := 0; i+2 < len(td); i += 3 {
+		s := shape{}
+		var intensity float64
+		for j := 0; j < 3; j++ {
+			v := &td[i+j]
+			s.pts[j] = c.mat.Project(v.Position)
+			s.color = s.color.Add(v.Color.Scaled(1.0 / 3))
+			s.tex[j] = v.Picture
+			intensity += v.Intensity / 3
+		}
+		area := s.pts[1].Sub(s.pts[0]).Cross(s.pts[2].Sub(s.pts[0]))
+		if area == 0 || math.IsNaN(area) {
+			continue
+		}
+		s.color = s.color.Mul(c.col)
+		if pd != nil && intensity > 0.5 {
+			s.pic = c.picture(pd)
+		}
+		c.shapes = append(c.shapes, s)
+	}
+}
+
+// picture returns the index of the PictureData plus one, adding it if it's new
+func (c *Canvas) picture(pd *pixel.PictureData) int {
+	if id, ok := c.picIDs[pd]; ok {
+		return id
+	}
+	c.pics = append(c.pics, pd)
+	c.picIDs[pd] = len(c.pics)
+	return len(c.pics)
+}
+
+type canvasTriangles struct {
+	data *pixel.TrianglesData
+	dst  *Canvas
+}
+
+func (ct *canvasTriangles) Len() int {
+	return ct.data.Len()
+}
+
+func (ct *canvasTriangles) SetLen(len int) {
+	ct.data.SetLen(len)
+}
+
+func (ct *canvasTriangles) Slice(i, j int) pixel.Triangles {
+	return &canvasTriangles{
+		data: ct.data.Slice(i, j).(*pixel.TrianglesData),
+		dst:  ct.dst,
+	}
+}
+
+func (ct *canvasTriangles) Update(t pixel.Triangles) {
+	ct.data.Update(t)
+}
+
+func (ct *canvasTriangles) Copy() pixel.Triangles {
+	return &canvasTriangles{
+		data: ct.data.Copy().(*pixel.TrianglesData),
+		dst:  ct.dst,
+	}
+}
+
+func (ct *canvasTriangles) Position(i int) pixel.Vec {
+	return ct.data.Position(i)
+}
+
+func (ct *canvasTriangles) Color(i int) pixel.RGBA {
+	return ct.data.Color(i)
+}
+
+func (ct *canvasTriangles) Picture(i int) (pic pixel.Vec, intensity float64) {
+	return ct.data.Picture(i)
+}
+
+func (ct *canvasTriangles) Draw() {
+	ct.dst.record(*ct.data, nil)
+}
+
+type canvasPicture struct {
+	pd  *pixel.PictureData
+	dst *Canvas
+}
+
+func (cp *canvasPicture) Bounds() pixel.Rect {
+	return cp.pd.Bounds()
+}
+
+func (cp *canvasPicture) Color(at pixel.Vec) pixel.RGBA {
+	return cp.pd.Color(at)
+}
+
+func (cp *canvasPicture) Draw(t pixel.TargetTriangles) {
+	ct := t.(*canvasTriangles)
+	if cp.dst != ct.dst {
+		panic(fmt.Errorf("(%T).Draw: TargetTriangles generated by different Canvas", cp))
+	}
+	ct.dst.record(*ct.data, cp.pd)
+}
+
+// group is a run of the shapes drawn together, filled by the same color, or showing the same
+// picture by the same mapping
+type group struct {
+	shapes []shape
+	color  pixel.RGBA
+	pic    int
+	// mapping maps the positions in the Picture to the positions in the Canvas
+	mapping pixel.Matrix
+}
+
+// groups returns the recorded shapes grouped into runs
+func (c *Canvas) groups() []group {
+	var groups []group
+	for _, s := range c.shapes {
+		g := group{shapes: []shape{s}, color: s.color, pic: s.pic}
+		if s.pic != 0 {
+			m, ok := affine(s.tex, s.pts)
+			if !ok {
+				// a degenerate mapping shows nothing of the Picture
+				continue
+			}
+			g.mapping = m
+		}
+		if n := len(groups); n > 0 && groups[n-1].joins(g) {
+			groups[n-1].shapes = append(groups[n-1].shapes, s)
+			continue
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// joins reports whether the group of a single shape can join the group
+func (g *group) joins(next group) bool {
+	if g.pic != next.pic || !nearRGBA(g.color, next.color) {
+		return false
+	}
+	if g.pic == 0 {
+		return true
+	}
+	for i := range g.mapping {
+		if math.Abs(g.mapping[i]-next.mapping[i]) > 1e-6*math.Max(1, math.Abs(g.mapping[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func nearRGBA(a, b pixel.RGBA) bool {
+	const eps = 1.0 / 512
+	return math.Abs(a.R-b.R) < eps && math.Abs(a.G-b.G) < eps && math.Abs(a.B-b.B) < eps && math.Abs(a.A-b.A) < eps
+}
+
+// affine returns the affine Matrix mapping the points src to the points dst
+func affine(src, dst [3]pixel.Vec) (pixel.Matrix, bool) {
+	s1, s2 := src[1].Sub(src[0]), src[2].Sub(src[0])
+	d1, d2 := dst[1].Sub(dst[0]), dst[2].Sub(dst[0])
+	det := s1.X*s2.Y - s2.X*s1.Y
+	if det == 0 || math.IsNaN(det) {
+		return pixel.Matrix{}, false
+	}
+	// the inverse of the matrix of the columns s1 and s2
+	inv := [4]float64{s2.Y / det, -s1.Y / det, -s2.X / det, s1.X / det}
+	m := pixel.Matrix{
+		d1.X*inv[0] + d2.X*inv[1],
+		d1.Y*inv[0] + d2.Y*inv[1],
+		d1.X*inv[2] + d2.X*inv[3],
+		d1.Y*inv[2] + d2.Y*inv[3],
+		0, 0,
+	}
+	origin := m.Project(src[0])
+	m[4], m[5] = dst[0].X-origin.X, dst[0].Y-origin.Y
+	return m, true
+}
+
+// unpremultiply returns the straight color components of the alpha-premultiplied color, within
+// [0, 1]
+func unpremultiply(c pixel.RGBA) (r, g, b, a float64) {
+	a = pixel.Clamp(c.A, 0, 1)
+	if a == 0 {
+		return 0, 0, 0, 0
+	}
+	return pixel.Clamp(c.R/a, 0, 1), pixel.Clamp(c.G/a, 0, 1), pixel.Clamp(c.B/a, 0, 1), a
+}
+
+// num formats the number with at most 3 decimals, as short as possible
+func num(v float64) string {
+	s := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.3f", v), "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
@@ -0,0 +1,185 @@
+package vector
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/faiface/pixel"
+)
+
+// WritePDF writes the recorded shapes to the writer as a single page PDF document of the size of
+// the bounds of the Canvas, one pixel per point. The Pictures are embedded as images.
+func (c *Canvas) WritePDF(w io.Writer) error {
+	b := c.bounds
+	out := pixel.Matrix{1, 0, 0, 1, -b.Min.X, -b.Min.Y}
+	imgs := make([]*image.RGBA, len(c.pics))
+	for i, pd := range c.pics {
+		imgs[i] = pd.Image()
+	}
+
+	// the content stream, with the opacities as graphics states
+	var content strings.Builder
+	states := make(map[string]string)
+	for _, g := range c.groups() {
+		r, gr, bl, a := unpremultiply(g.color)
+		if a == 0 {
+			continue
+		}
+		content.WriteString("q\n")
+		if a < 1 {
+			alpha := num(a)
+			if states[alpha] == "" {
+				states[alpha] = fmt.Sprintf("G%d", len(states))
+			}
+			fmt.Fprintf(&content, "/%s gs\n", states[alpha])
+		}
+		pdfPath(&content, g.shapes, out)
+		if g.pic == 0 {
+			fmt.Fprintf(&content, "%s %s %s rg\nf\nQ\n", num(r), num(gr), num(bl))
+			continue
+		}
+		// the image fills the unit square, mapped onto the Picture
+		ib := imgs[g.pic-1].Rect
+		unit := pixel.Matrix{float64(ib.Dx()), 0, 0, float64(ib.Dy()), float64(ib.Min.X), float64(ib.Min.Y)}
+		m := unit.Chained(g.mapping).Chained(out)
+		fmt.Fprintf(&content, "W n\n%s cm\n/Im%d Do\nQ\n", matrix(m), g.pic)
+	}
+
+	pw := &pdfWriter{w: bufio.NewWriter(w)}
+	pw.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	pw.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	pw.object(2, "<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+
+	var resources strings.Builder
+	if len(states) > 0 {
+		alphas := make([]string, 0, len(states))
+		for alpha := range states {
+			alphas = append(alphas, alpha)
+		}
+		sort.Slice(alphas, func(i, j int) bool { return states[alphas[i]] < states[alphas[j]] })
+		resources.WriteString(" /ExtGState <<")
+		for _, alpha := range alphas {
+			fmt.Fprintf(&resources, " /%s << /ca %s >>", states[alpha], alpha)
+		}
+		resources.WriteString(" >>")
+	}
+	if len(c.pics) > 0 {
+		resources.WriteString(" /XObject <<")
+		for i := range c.pics {
+			// each image has its object and the object of its alpha
+			fmt.Fprintf(&resources, " /Im%d %d 0 R", i+1, 5+2*i)
+		}
+		resources.WriteString(" >>")
+	}
+	pw.object(3, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources <<%s >> /Contents 4 0 R >>",
+		num(b.W()), num(b.H()), resources.String()))
+	pw.stream(4, "", []byte(content.String()))
+
+	for i, img := range imgs {
+		width, height := img.Rect.Dx(), img.Rect.Dy()
+		rgb := make([]byte, 0, 3*width*height)
+		alpha := make([]byte, 0, width*height)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				r, g, b, a := unpremultiply(pixel.ToRGBA(img.RGBAAt(img.Rect.Min.X+x, img.Rect.Min.Y+y)))
+				rgb = append(rgb, to8(r), to8(g), to8(b))
+				alpha = append(alpha, to8(a))
+			}
+		}
+		id := 5 + 2*i
+		pw.stream(id, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB "+
+			"/BitsPerComponent 8 /SMask %d 0 R", width, height, id+1), rgb)
+		pw.stream(id+1, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray "+
+			"/BitsPerComponent 8", width, height), alpha)
+	}
+
+	pw.finish()
+	if pw.err != nil {
+		return fmt.Errorf("vector: failed to write PDF: %v", pw.err)
+	}
+	return nil
+}
+
+// pdfPath writes the PDF path of the triangles, transformed by the Matrix
+func pdfPath(sb *strings.Builder, shapes []shape, m pixel.Matrix) {
+	for _, s := range shapes {
+		for j, p := range s.pts {
+			p = m.Project(p)
+			op := "l"
+			if j == 0 {
+				op = "m"
+			}
+			fmt.Fprintf(sb, "%s %s %s ", num(p.X), num(p.Y), op)
+		}
+		sb.WriteString("h\n")
+	}
+}
+
+// pdfWriter writes the objects of a PDF document, recording their offsets for the cross-reference
+// table
+type pdfWriter struct {
+	w       *bufio.Writer
+	offset  int
+	offsets []int
+	err     error
+}
+
+func (pw *pdfWriter) printf(format string, args ...interface{}) {
+	if pw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(pw.w, format, args...)
+	pw.offset += n
+	pw.err = err
+}
+
+func (pw *pdfWriter) write(data []byte) {
+	if pw.err != nil {
+		return
+	}
+	n, err := pw.w.Write(data)
+	pw.offset += n
+	pw.err = err
+}
+
+// object writes the object of the id, which must be the next one
+func (pw *pdfWriter) object(id int, dict string) {
+	pw.offsets = append(pw.offsets, pw.offset)
+	pw.printf("%d 0 obj\n%s\nendobj\n", id, dict)
+}
+
+// stream writes the object of the id with the data compressed as a stream, the dict are the extra
+// entries of its dictionary
+func (pw *pdfWriter) stream(id int, dict string, data []byte) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+
+	pw.offsets = append(pw.offsets, pw.offset)
+	if dict != "" {
+		dict += " "
+	}
+	pw.printf("%d 0 obj\n<< %s/Filter /FlateDecode /Length %d >>\nstream\n", id, dict, buf.Len())
+	pw.write(buf.Bytes())
+	pw.printf("\nendstream\nendobj\n")
+}
+
+// finish writes the cross-reference table and the trailer
+func (pw *pdfWriter) finish() {
+	xref := pw.offset
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for _, off := range pw.offsets {
+		pw.printf("%010d 00000 n \n", off)
+	}
+	pw.printf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(pw.offsets)+1, xref)
+	if pw.err == nil {
+		pw.err = pw.w.Flush()
+	}
+}
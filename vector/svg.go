@@ -0,0 +1,127 @@
+package vector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/faiface/pixel"
+)
+
+// WriteSVG writes the recorded shapes to the writer as an SVG document of the size of the bounds of
+// the Canvas, in pixels. The Pictures are embedded as PNG images.
+func (c *Canvas) WriteSVG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	b := c.bounds
+	fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" "+
+		"width=\"%s\" height=\"%s\" viewBox=\"0 0 %s %s\">\n", num(b.W()), num(b.H()), num(b.W()), num(b.H()))
+
+	imgBounds := make([]image.Rectangle, len(c.pics))
+	if len(c.pics) > 0 {
+		fmt.Fprintf(bw, "<defs>\n")
+		for i, pd := range c.pics {
+			img := pd.Image()
+			imgBounds[i] = img.Bounds()
+			var data bytes.Buffer
+			if err := png.Encode(&data, img); err != nil {
+				return fmt.Errorf("vector: failed to encode picture: %v", err)
+			}
+			r := img.Bounds()
+			fmt.Fprintf(bw, "<image id=\"p%d\" width=\"%d\" height=\"%d\" image-rendering=\"pixelated\" "+
+				"preserveAspectRatio=\"none\" xlink:href=\"data:image/png;base64,%s\"/>\n",
+				i+1, r.Dx(), r.Dy(), base64.StdEncoding.EncodeToString(data.Bytes()))
+		}
+		fmt.Fprintf(bw, "</defs>\n")
+	}
+
+	// the SVG coordinates go down from the top-left corner of the bounds
+	out := pixel.Matrix{1, 0, 0, -1, -b.Min.X, b.Max.Y}
+	var clips int
+	for _, g := range c.groups() {
+		r, gr, bl, a := unpremultiply(g.color)
+		if a == 0 {
+			continue
+		}
+		d := svgPath(g.shapes, out)
+		opacity := ""
+		if a < 1 {
+			opacity = num(a)
+		}
+
+		if g.pic == 0 {
+			fmt.Fprintf(bw, "<path fill=\"%s\"", hexColor(r, gr, bl))
+			if opacity != "" {
+				fmt.Fprintf(bw, " fill-opacity=\"%s\"", opacity)
+			}
+			fmt.Fprintf(bw, " d=\"%s\"/>\n", d)
+			continue
+		}
+
+		// the image pixels go down from the top-left corner of the Picture
+		ib := imgBounds[g.pic-1]
+		img := pixel.Matrix{1, 0, 0, -1, float64(ib.Min.X), float64(ib.Max.Y)}
+		m := img.Chained(g.mapping).Chained(out)
+		clips++
+		fmt.Fprintf(bw, "<clipPath id=\"c%d\"><path d=\"%s\"/></clipPath>\n", clips, d)
+		// the clip path is in the coordinates of the group, not transformed like the image
+		fmt.Fprintf(bw, "<g clip-path=\"url(#c%d)\"", clips)
+		if opacity != "" {
+			fmt.Fprintf(bw, " opacity=\"%s\"", opacity)
+		}
+		fmt.Fprintf(bw, "><use xlink:href=\"#p%d\" transform=\"matrix(%s)\"/></g>\n", g.pic, matrix(m))
+	}
+
+	fmt.Fprintf(bw, "</svg>\n")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("vector: failed to write SVG: %v", err)
+	}
+	return nil
+}
+
+// svgPath returns the SVG path data of the triangles, transformed by the Matrix
+func svgPath(shapes []shape, m pixel.Matrix) string {
+	var sb strings.Builder
+	for i, s := range shapes {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		for j, p := range s.pts {
+			p = m.Project(p)
+			cmd := "L"
+			if j == 0 {
+				cmd = "M"
+			}
+			fmt.Fprintf(&sb, "%s%s %s ", cmd, num(p.X), num(p.Y))
+		}
+		sb.WriteByte('Z')
+	}
+	return sb.String()
+}
+
+func hexColor(r, g, b float64) string {
+	return fmt.Sprintf("#%02x%02x%02x", to8(r), to8(g), to8(b))
+}
+
+func to8(v float64) uint8 {
+	return uint8(math.Round(v * 255))
+}
+
+// matrix formats the components of the Matrix with 6 decimals, enough for the small scales of
+// large Pictures
+func matrix(m pixel.Matrix) string {
+	s := make([]string, len(m))
+	for i, v := range m {
+		s[i] = strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.6f", v), "0"), ".")
+		if s[i] == "-0" {
+			s[i] = "0"
+		}
+	}
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,128 @@
+package vector_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/colornames"
+)
+
+// drawScene draws a red rectangle and a half transparent sprite of a 4x2 picture centered on the
+// canvas
+func drawScene(c *vector.Canvas) {
+	imd := imdraw.New(nil)
+	imd.Color = colornames.Red
+	imd.Push(pixel.V(10, 10), pixel.V(30, 20))
+	imd.Rectangle(0)
+	imd.Draw(c)
+
+	pd := pixel.MakePictureData(pixel.R(0, 0, 4, 2))
+	for i := range pd.Pix {
+		pd.Pix[i] = colornames.Blue
+	}
+	sprite := pixel.NewSprite(pd, pd.Bounds())
+	sprite.DrawColorMask(c, pixel.IM.Moved(c.Bounds().Center()), pixel.Alpha(0.5))
+}
+
+func TestCanvas(t *testing.T) {
+	c := vector.NewCanvas(pixel.R(0, 0, 100, 50))
+	drawScene(c)
+	assert.Equal(t, 4, c.Len())
+
+	// degenerate triangles are skipped
+	imd := imdraw.New(nil)
+	imd.Push(pixel.V(0, 0), pixel.V(10, 0), pixel.V(20, 0))
+	imd.Polygon(0)
+	imd.Draw(c)
+	assert.Equal(t, 4, c.Len())
+
+	c.Clear()
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCanvas_WriteSVG(t *testing.T) {
+	c := vector.NewCanvas(pixel.R(0, 0, 100, 50))
+	drawScene(c)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.WriteSVG(&buf))
+	svg := buf.String()
+	assert.Contains(t, svg, `width="100" height="50" viewBox="0 0 100 50"`)
+
+	// both triangles of the rectangle are in a single path, flipped to go down from the top
+	paths := regexp.MustCompile(`<path fill="#ff0000" d="([^"]*)"/>`).FindAllStringSubmatch(svg, -1)
+	require.Len(t, paths, 1)
+	assert.Equal(t, 2, strings.Count(paths[0][1], "M"))
+	assert.Contains(t, paths[0][1], "M10 40 ")
+
+	// the picture is embedded once and both triangles of the sprite clip it
+	assert.Equal(t, 1, strings.Count(svg, `<image id="p1" width="4" height="2"`))
+	assert.Equal(t, 1, strings.Count(svg, "<clipPath"))
+	assert.Contains(t, svg, `<g clip-path="url(#c1)" opacity="0.5"><use xlink:href="#p1" transform="matrix(1 0 0 1 48 24)"/></g>`)
+}
+
+func TestCanvas_WritePDF(t *testing.T) {
+	c := vector.NewCanvas(pixel.R(0, 0, 100, 50))
+	drawScene(c)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.WritePDF(&buf))
+	pdf := buf.Bytes()
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.Contains(t, string(pdf), "/MediaBox [0 0 100 50]")
+	assert.Contains(t, string(pdf), "/ExtGState << /G0 << /ca 0.5 >> >>")
+	assert.Contains(t, string(pdf), "/XObject << /Im1 5 0 R >>")
+	assert.Contains(t, string(pdf), "/Width 4 /Height 2 /ColorSpace /DeviceRGB /BitsPerComponent 8 /SMask 6 0 R")
+
+	// the cross-reference table points to the objects
+	xref := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	require.NotNil(t, xref)
+	off, _ := strconv.Atoi(string(xref[1]))
+	require.True(t, bytes.HasPrefix(pdf[off:], []byte("xref\n0 7\n")))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[off:], -1)
+	require.Len(t, entries, 6)
+	for i, e := range entries {
+		at, _ := strconv.Atoi(string(e[1]))
+		assert.True(t, bytes.HasPrefix(pdf[at:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+
+	// the content stream fills the rectangle and draws the image clipped by the sprite
+	m := regexp.MustCompile(`(?s)4 0 obj\n<< /Filter /FlateDecode /Length (\d+) >>\nstream\n`).FindSubmatchIndex(pdf)
+	require.NotNil(t, m)
+	length, _ := strconv.Atoi(string(pdf[m[2]:m[3]]))
+	zr, err := zlib.NewReader(bytes.NewReader(pdf[m[1] : m[1]+length]))
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "10 10 m ")
+	assert.Contains(t, string(content), "1 0 0 rg\nf\n")
+	assert.Contains(t, string(content), "/G0 gs\n")
+	assert.Contains(t, string(content), "W n\n4 0 0 2 48 24 cm\n/Im1 Do\n")
+}
+
+func TestCanvas_bounds(t *testing.T) {
+	// the exported coordinates are relative to the bounds
+	c := vector.NewCanvas(pixel.R(10, 10, 60, 40))
+	imd := imdraw.New(nil)
+	imd.Color = colornames.Lime
+	imd.Push(pixel.V(10, 10), pixel.V(20.5, 15))
+	imd.Rectangle(0)
+	imd.Draw(c)
+
+	var svg bytes.Buffer
+	require.NoError(t, c.WriteSVG(&svg))
+	assert.Contains(t, svg.String(), `fill="#00ff00"`)
+	assert.Contains(t, svg.String(), "M0 30 ")
+	assert.Contains(t, svg.String(), "10.5 25")
+}